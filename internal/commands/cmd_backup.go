@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/internal/store/backup"
+	"github.com/urfave/cli/v3"
+)
+
+type BackupCmd struct {
+	flags *Flags
+
+	// flags
+	reason string
+}
+
+// NewBackupCmd creates a new backup command
+func NewBackupCmd(flags *Flags) *BackupCmd {
+	return &BackupCmd{flags: flags}
+}
+
+// Register adds the backup command to the application
+func (cmd *BackupCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "backup",
+		Usage: "Create, list, and restore backups of the session and message stores",
+		Description: `Backups hold copies of the session store (sessions.json or sessions.db) and
+the message topics. They are kept in the data directory under backups/.
+
+hive takes a backup automatically before prune, batch, and migrate-store.
+The newest backups.keep (default 10) are retained; backups.keep: 0 turns
+automatic backups off.`,
+		Commands: []*cli.Command{
+			cmd.createCmd(),
+			cmd.listCmd(),
+			cmd.restoreCmd(),
+		},
+	})
+
+	return app
+}
+
+func (cmd *BackupCmd) createCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "create",
+		Usage:     "Back up the stores now",
+		UsageText: "hive backup create [--reason text]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "reason",
+				Usage:       "label recorded with the backup",
+				Value:       backup.ReasonManual,
+				Destination: &cmd.reason,
+			},
+		},
+		Action: cmd.runCreate,
+	}
+}
+
+func (cmd *BackupCmd) listCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "list",
+		Aliases:   []string{"ls"},
+		Usage:     "List backups, newest first",
+		UsageText: "hive backup list",
+		Action:    cmd.runList,
+	}
+}
+
+func (cmd *BackupCmd) restoreCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "restore",
+		Usage:     "Replace the stores with a backup",
+		UsageText: "hive backup restore <id>",
+		Description: `Replaces the session store and message topics with the contents of a
+backup. The current state is backed up first (reason "pre-restore"), so a
+restore can be undone by restoring that backup.
+
+Session directories are not part of a backup. Run 'hive gc' afterwards to
+find records whose directory no longer exists.`,
+		Action: cmd.runRestore,
+	}
+}
+
+func (cmd *BackupCmd) runCreate(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	b, err := cmd.flags.Service.Backups().Create(ctx, cmd.reason)
+	if err != nil {
+		return fmt.Errorf("create backup: %w", err)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, b)
+	}
+
+	p.Successf("Created backup %s (%s)", b.ID, formatBytes(b.Bytes))
+	return nil
+}
+
+func (cmd *BackupCmd) runList(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	backups, err := cmd.flags.Service.Backups().List()
+	if err != nil {
+		return err
+	}
+
+	if p.IsJSON() {
+		if backups == nil {
+			backups = []backup.Backup{}
+		}
+		return printer.EncodeJSON(c.Root().Writer, backups)
+	}
+
+	if len(backups) == 0 {
+		p.Infof("No backups")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tREASON\tCREATED\tSIZE")
+	for _, b := range backups {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.ID, b.Reason, b.CreatedAt.Local().Format(time.DateTime), formatBytes(b.Bytes))
+	}
+	return w.Flush()
+}
+
+func (cmd *BackupCmd) runRestore(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one backup ID\n\nUsage: hive backup restore <id>")
+	}
+
+	// Release our own handle on the store before swapping its files
+	CloseStore(cmd.flags.Store)
+
+	b, err := cmd.flags.Service.Backups().Restore(ctx, c.Args().First())
+	if err != nil {
+		return fmt.Errorf("restore backup: %w", err)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, b)
+	}
+
+	p.Successf("Restored backup %s (%s)", b.ID, b.Reason)
+	p.Infof("The previous state was saved as a pre-restore backup")
+	return nil
+}
@@ -1,20 +1,32 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/hay-kot/criterio"
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/core/templates"
 	"github.com/hay-kot/hive/internal/core/validate"
+	"github.com/hay-kot/hive/internal/events"
 	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/pkg/randid"
 	"github.com/rs/zerolog"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -22,16 +34,28 @@ const (
 	StatusCreated = "created"
 	// StatusFailed indicates the session creation failed.
 	StatusFailed = "failed"
+	// StatusSpawnFailed indicates the session was created but its spawn
+	// commands failed. Resuming the batch spawns it again.
+	StatusSpawnFailed = "spawn_failed"
 	// StatusSkipped indicates the session was not attempted due to failure threshold.
 	StatusSkipped = "skipped"
+	// StatusRecycled indicates the session was recycled by hive batch rm.
+	StatusRecycled = "recycled"
+	// StatusDeleted indicates the session was deleted by hive batch rm.
+	StatusDeleted = "deleted"
+)
 
-	// maxFailures is the number of failures before stopping batch processing.
-	maxFailures = 3
+// Batch input formats accepted by --format.
+const (
+	formatAuto   = "auto"
+	formatJSON   = "json"
+	formatYAML   = "yaml"
+	formatNDJSON = "ndjson"
 )
 
 // BatchInput is the JSON input schema for batch session creation.
 type BatchInput struct {
-	Sessions []BatchSession `json:"sessions"`
+	Sessions []BatchSession `json:"sessions" yaml:"sessions"`
 }
 
 // Validate checks the batch input for errors using criterio.
@@ -41,47 +65,134 @@ func (b BatchInput) Validate() error {
 	}
 
 	var errs criterio.FieldErrorsBuilder
-	seenNames := make(map[string]bool)
-	seenIDs := make(map[string]bool)
+	v := newSessionValidator()
 
 	for i, sess := range b.Sessions {
-		field := fmt.Sprintf("sessions[%d]", i)
-
-		if err := validate.SessionName(sess.Name); err != nil {
-			errs = errs.Append(field+".name", err)
-			continue
+		if field, err := v.check(sess); err != nil {
+			errs = errs.Append(fmt.Sprintf("sessions[%d].%s", i, field), err)
 		}
+	}
 
-		if seenNames[sess.Name] {
-			errs = errs.Append(field+".name", fmt.Errorf("duplicate name %q", sess.Name))
-			continue
-		}
-		seenNames[sess.Name] = true
+	return errs.ToError()
+}
 
-		// Validate session_id if provided
-		if sess.SessionID != "" {
-			if err := validate.SessionID(sess.SessionID); err != nil {
-				errs = errs.Append(field+".session_id", err)
-				continue
-			}
-			if seenIDs[sess.SessionID] {
-				errs = errs.Append(field+".session_id", fmt.Errorf("duplicate session_id %q", sess.SessionID))
-				continue
-			}
-			seenIDs[sess.SessionID] = true
+// sessionValidator checks sessions one at a time, remembering names and IDs
+// it has seen so duplicates are caught even when input is streamed.
+type sessionValidator struct {
+	names map[string]bool
+	ids   map[string]bool
+}
+
+func newSessionValidator() *sessionValidator {
+	return &sessionValidator{
+		names: make(map[string]bool),
+		ids:   make(map[string]bool),
+	}
+}
+
+// check validates sess and returns the offending field name on error.
+func (v *sessionValidator) check(sess BatchSession) (string, error) {
+	if err := validate.SessionName(sess.Name); err != nil {
+		return "name", err
+	}
+
+	// Compare normalized names, as sessions are created under those
+	name := validate.NormalizeSessionName(sess.Name)
+	if v.names[name] {
+		return "name", fmt.Errorf("duplicate name %q", sess.Name)
+	}
+	v.names[name] = true
+
+	if sess.SessionID != "" {
+		if err := validate.SessionID(sess.SessionID); err != nil {
+			return "session_id", err
+		}
+		if v.ids[sess.SessionID] {
+			return "session_id", fmt.Errorf("duplicate session_id %q", sess.SessionID)
 		}
+		v.ids[sess.SessionID] = true
+	}
+
+	if sess.Template != "" && sess.Prompt != "" {
+		return "template", fmt.Errorf("cannot set both prompt and template")
+	}
+	if sess.Template == "" && len(sess.Values) > 0 {
+		return "values", fmt.Errorf("values require a template")
 	}
 
+	return "", nil
+}
+
+// validatePrompts checks that every templated session renders against the
+// configured templates, so bad references fail before any session is created.
+func (b BatchInput) validatePrompts(defs map[string]config.Template) error {
+	var errs criterio.FieldErrorsBuilder
+	for i, sess := range b.Sessions {
+		if _, err := renderPrompt(defs, sess); err != nil {
+			errs = errs.Append(fmt.Sprintf("sessions[%d].template", i), err)
+		}
+	}
 	return errs.ToError()
 }
 
 // BatchSession defines a single session to create.
 type BatchSession struct {
-	Name      string `json:"name"`
-	SessionID string `json:"session_id,omitempty"`
-	Prompt    string `json:"prompt,omitempty"`
-	Remote    string `json:"remote,omitempty"`
-	Source    string `json:"source,omitempty"`
+	Name      string         `json:"name"                 yaml:"name"`
+	SessionID string         `json:"session_id,omitempty" yaml:"session_id,omitempty"`
+	Prompt    string         `json:"prompt,omitempty"     yaml:"prompt,omitempty"`
+	Remote    string         `json:"remote,omitempty"     yaml:"remote,omitempty"`
+	Source    string         `json:"source,omitempty"     yaml:"source,omitempty"`
+	Template  string         `json:"template,omitempty"   yaml:"template,omitempty"` // configured template that renders the prompt
+	Values    map[string]any `json:"values,omitempty"     yaml:"values,omitempty"`   // field values for Template; numbers and booleans allowed
+	Spawn     string         `json:"spawn,omitempty"      yaml:"spawn,omitempty"`    // spawn profile overriding batch_spawn
+	Subdir    string         `json:"subdir,omitempty"     yaml:"subdir,omitempty"`   // working subdirectory in a monorepo
+
+	// promptPreambled is set once a preview has put the rules' prompt
+	// preambles into Prompt, so they are not added again. It is kept in
+	// BatchState.Preambled, never in input.
+	promptPreambled bool
+
+	// respawn is the earlier result of a session whose spawn failed; when
+	// set, the session is spawned again rather than created.
+	respawn *BatchResult
+}
+
+// errPromptPreambled rejects input that claims its prompt already carries
+// the rules' prompt preambles, which would skip them.
+var errPromptPreambled = errors.New("prompt_preambled is set by hive and cannot be given in input")
+
+// UnmarshalJSON decodes a session, rejecting prompt_preambled.
+func (s *BatchSession) UnmarshalJSON(data []byte) error {
+	type plain BatchSession
+	var in struct {
+		plain
+		PromptPreambled *bool `json:"prompt_preambled"`
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.PromptPreambled != nil {
+		return errPromptPreambled
+	}
+	*s = BatchSession(in.plain)
+	return nil
+}
+
+// UnmarshalYAML decodes a session, rejecting prompt_preambled.
+func (s *BatchSession) UnmarshalYAML(value *yaml.Node) error {
+	type plain BatchSession
+	var in struct {
+		plain           `yaml:",inline"`
+		PromptPreambled *bool `yaml:"prompt_preambled"`
+	}
+	if err := value.Decode(&in); err != nil {
+		return err
+	}
+	if in.PromptPreambled != nil {
+		return errPromptPreambled
+	}
+	*s = BatchSession(in.plain)
+	return nil
 }
 
 // BatchResult is the output for a single session creation attempt.
@@ -100,14 +211,83 @@ type BatchOutput struct {
 	Results []BatchResult `json:"results"`
 }
 
+// BatchRecyclePlanOutput is the JSON output schema for batch rm --dry-run.
+type BatchRecyclePlanOutput struct {
+	BatchID string             `json:"batch_id"`
+	DryRun  bool               `json:"dry_run"`
+	Plans   []hive.RecyclePlan `json:"plans"`
+}
+
+// BatchPlanOutput is the JSON output schema for --dry-run.
+type BatchPlanOutput struct {
+	DryRun bool               `json:"dry_run"`
+	Plans  []hive.SessionPlan `json:"plans"`
+}
+
+// BatchStreamResult is a single NDJSON output line in streaming mode.
+type BatchStreamResult struct {
+	BatchID string `json:"batch_id"`
+	Index   int    `json:"index"`
+	BatchResult
+}
+
+// BatchState is the persisted record of a batch, stored in the batches
+// directory keyed by batch ID so failed sessions can be resumed.
+type BatchState struct {
+	BatchID   string         `json:"batch_id"`
+	Dir       string         `json:"dir"` // Working directory the batch was started from
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	Sessions  []BatchSession `json:"sessions"`
+	Results   []BatchResult  `json:"results"`
+	Preambled []int          `json:"preambled,omitempty"` // Sessions whose prompt a preview already preambled
+}
+
+// pending returns the indices of sessions that were not created or whose
+// spawn failed.
+func (st BatchState) pending() []int {
+	var idx []int
+	for i := range st.Sessions {
+		if i >= len(st.Results) || st.Results[i].Status != StatusCreated {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// resumeSessions returns the sessions at the given indices, marking those
+// whose spawn failed to be spawned again instead of created, and those whose
+// prompt was preambled by a preview.
+func (st BatchState) resumeSessions(indices []int) []BatchSession {
+	sessions := make([]BatchSession, len(indices))
+	for j, i := range indices {
+		sessions[j] = st.Sessions[i]
+		sessions[j].promptPreambled = slices.Contains(st.Preambled, i)
+		if i < len(st.Results) && st.Results[i].Status == StatusSpawnFailed {
+			result := st.Results[i]
+			sessions[j].respawn = &result
+		}
+	}
+	return sessions
+}
+
 // BatchErrorOutput is the JSON output for fatal errors.
 type BatchErrorOutput struct {
 	Error string `json:"error"`
 }
 
 type BatchCmd struct {
-	flags *Flags
-	file  string
+	flags       *Flags
+	file        string
+	concurrency int
+	dryRun      bool
+	format      string
+	maxFailures int
+	failFast    bool
+	keepGoing   bool
+	preview     bool
+	delete      bool
+	wait        bool
 }
 
 func NewBatchCmd(flags *Flags) *BatchCmd {
@@ -119,19 +299,39 @@ func (cmd *BatchCmd) Register(app *cli.Command) *cli.Command {
 		Name:  "batch",
 		Usage: "Create multiple sessions from JSON input",
 		UsageText: `hive batch [options]
+hive batch resume <batch-id>
+hive batch rm [--delete] <batch-id>
 
 Read from stdin:
   echo '{"sessions":[{"name":"task1","prompt":"Do something"}]}' | hive batch
 
 Read from file:
-  hive batch -f sessions.json`,
+  hive batch -f sessions.json
+  hive batch -f sessions.yaml
+
+Stream sessions as NDJSON, one per line:
+  producer | hive batch --format ndjson`,
 		Description: `Creates multiple agent sessions from a JSON specification.
 
-Each session in the input array is created sequentially. A terminal is
+Sessions are created in parallel, up to --concurrency at a time (default
+from batch.concurrency in config, which defaults to 1). A terminal is
 spawned for each session using the batch_spawn commands if configured,
 otherwise falls back to spawn commands.
 
-Processing stops after 3 failures. Sessions not attempted are marked as skipped.
+Processing stops after batch.max_failures failures (default 3). Sessions not
+attempted are marked as skipped. Use --max-failures to override the threshold,
+--fail-fast to stop after the first failure, or --keep-going to never skip.
+Results are always reported in input order, and each session's log lines are
+tagged with its name and index in the batch log.
+
+Input may be JSON or YAML with the same schema. The format is taken from
+--format, then the file extension (.json, .yaml/.yml, .ndjson/.jsonl); stdin
+is read as JSON if it starts with "{" and as YAML otherwise.
+
+In NDJSON mode each line is a single session object, processed as soon as it
+arrives, so another tool can keep piping sessions in. Each result is written
+as an NDJSON line when it completes (tagged with batch_id and the input
+index), and invalid lines are reported as failed without stopping the stream.
 
 Input JSON schema:
   {
@@ -141,7 +341,10 @@ Input JSON schema:
         "session_id": "optional-id",
         "prompt": "optional task prompt",
         "remote": "optional-url",
-        "source": "optional-path"
+        "source": "optional-path",
+        "template": "optional-template-name",
+        "values": {"field": "value"},
+        "spawn": "optional-profile"
       }
     ]
   }
@@ -152,6 +355,12 @@ Fields:
   prompt     - Optional. Task prompt passed to batch_spawn via {{.Prompt}} template.
   remote     - Optional. Git remote URL (auto-detected from current dir if empty).
   source     - Optional. Directory to copy files from (per copy rules in config).
+  template   - Optional. Name of a template under "templates" in config; the
+               prompt is rendered from it. Cannot be combined with prompt.
+  values     - Optional. Field values for template; defaults from the
+               template's fields fill in anything omitted.
+  spawn      - Optional. Spawn profile from commands.spawn_profiles, used
+               instead of batch_spawn.
 
 Config example (in ~/.config/hive/config.yaml):
   commands:
@@ -160,14 +369,54 @@ Config example (in ~/.config/hive/config.yaml):
     batch_spawn:  # Used by hive batch (supports {{.Prompt}})
       - "wezterm cli spawn --cwd {{.Path}} -- claude --prompt '{{.Prompt}}'"
 
-Output is JSON with a batch ID, log file path, and results for each session.`,
-		Flags: []cli.Flag{
+Output is JSON with a batch ID, log file path, and results for each session.
+Batch state is saved to the batches directory under the data dir; use
+"hive batch resume <batch-id>" to retry only the failed and skipped sessions.
+
+With --dry-run, the input is validated and each session is planned without
+side effects: the remote is resolved, a recycled session is assigned if one
+is available (otherwise a clone is planned), matching rules are listed, and
+spawn commands are rendered. Output is JSON with a plan for each session.
+
+With --preview (or "preview: true" on a template), each templated prompt is
+shown on the terminal before the batch starts so it can be accepted, edited
+in $EDITOR, or declined. Declining cancels the whole batch. Previews read
+from /dev/tty when the input is piped on stdin, and are not available in
+NDJSON mode.`,
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:        "file",
 				Aliases:     []string{"f"},
 				Usage:       "path to JSON file (reads from stdin if not provided)",
 				Destination: &cmd.file,
 			},
+			&cli.IntFlag{
+				Name:        "concurrency",
+				Aliases:     []string{"j"},
+				Usage:       "number of sessions to create in parallel (default: batch.concurrency)",
+				Destination: &cmd.concurrency,
+			},
+			&cli.StringFlag{
+				Name:        "format",
+				Usage:       "input format: auto, json, yaml, or ndjson",
+				Value:       formatAuto,
+				Destination: &cmd.format,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "validate input and print the plan for each session without creating anything",
+				Destination: &cmd.dryRun,
+			},
+			&cli.BoolFlag{
+				Name:        "preview",
+				Usage:       "review and optionally edit templated prompts before creating sessions",
+				Destination: &cmd.preview,
+			},
+			cmd.waitFlag(),
+		}, cmd.failureFlags()...),
+		Commands: []*cli.Command{
+			cmd.resumeCmd(),
+			cmd.rmCmd(),
 		},
 		Action: cmd.run,
 	})
@@ -175,7 +424,220 @@ Output is JSON with a batch ID, log file path, and results for each session.`,
 	return app
 }
 
+func (cmd *BatchCmd) resumeCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "resume",
+		Usage:     "Retry failed and skipped sessions from a previous batch",
+		ArgsUsage: "<batch-id>",
+		Description: `Loads the saved state for a batch and retries only the sessions that were
+not created. Sessions that already succeeded are left alone, so resuming never
+creates duplicates. Results are merged into the saved state and the full
+result set is written as JSON, like hive batch.
+
+Missing sources and remotes are resolved from the directory the batch was
+originally started in.`,
+		Flags: append([]cli.Flag{
+			&cli.IntFlag{
+				Name:        "concurrency",
+				Aliases:     []string{"j"},
+				Usage:       "number of sessions to create in parallel (default: batch.concurrency)",
+				Destination: &cmd.concurrency,
+			},
+			cmd.waitFlag(),
+		}, cmd.failureFlags()...),
+		Action: cmd.runResume,
+	}
+}
+
+func (cmd *BatchCmd) rmCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "rm",
+		Usage:     "Recycle or delete every session created by a batch",
+		ArgsUsage: "<batch-id>",
+		Description: `Recycles every active session created by the given batch, or deletes
+them with --delete (which also removes corrupted sessions from the batch).
+Sessions are found by the batch ID recorded when they were created.
+
+Output is JSON in the same shape as hive batch, with a status of recycled,
+deleted, or failed for each session.
+
+With --dry-run, nothing is recycled. Output is JSON with a plan for each
+session: the directory it would be renamed to and the rendered pre_recycle
+hooks, recycle commands, and post_recycle hooks.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "delete",
+				Usage:       "delete sessions instead of recycling them",
+				Destination: &cmd.delete,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "print a recycle plan for each session without recycling",
+				Destination: &cmd.dryRun,
+			},
+		},
+		Action: cmd.runRm,
+	}
+}
+
+func (cmd *BatchCmd) runRm(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 1 {
+		return cmd.writeError(fmt.Errorf("expected exactly one batch ID"))
+	}
+	batchID := c.Args().First()
+
+	sessions, err := cmd.flags.Service.ListSessions(ctx)
+	if err != nil {
+		return cmd.writeError(fmt.Errorf("list sessions: %w", err))
+	}
+
+	targets := batchSessions(sessions, batchID, cmd.delete)
+	if len(targets) == 0 {
+		return cmd.writeError(fmt.Errorf("no sessions found for batch %q", batchID))
+	}
+
+	if cmd.dryRun {
+		return cmd.planRm(ctx, batchID, targets)
+	}
+
+	cmd.flags.Service.AutoBackup(ctx, hive.BackupBatch)
+
+	output := BatchOutput{
+		BatchID: batchID,
+		LogFile: filepath.Join(cmd.flags.Config.LogsDir(), fmt.Sprintf("batch-%s.log", batchID)),
+		Results: make([]BatchResult, 0, len(targets)),
+	}
+
+	for _, sess := range targets {
+		result := BatchResult{Name: sess.Name, SessionID: sess.ID, Path: sess.Path}
+
+		// Hook output goes to stderr to keep stdout valid JSON
+		if cmd.delete {
+			err = cmd.flags.Service.DeleteSession(ctx, sess.ID)
+			result.Status = StatusDeleted
+		} else {
+			err = cmd.flags.Service.RecycleSession(ctx, sess.ID, cmd.flags.progressWriter())
+			result.Status = StatusRecycled
+		}
+		if err != nil {
+			result.Status = StatusFailed
+			result.Error = err.Error()
+		}
+
+		output.Results = append(output.Results, result)
+	}
+
+	return cmd.writeOutput(output)
+}
+
+// planRm writes the recycle plan for each of a batch's sessions.
+func (cmd *BatchCmd) planRm(ctx context.Context, batchID string, targets []session.Session) error {
+	if cmd.delete {
+		return cmd.writeError(fmt.Errorf("--dry-run is not supported with --delete"))
+	}
+
+	output := BatchRecyclePlanOutput{BatchID: batchID, DryRun: true, Plans: make([]hive.RecyclePlan, 0, len(targets))}
+	for _, sess := range targets {
+		plan, err := cmd.flags.Service.PlanRecycle(ctx, sess.ID)
+		if err != nil {
+			return cmd.writeError(fmt.Errorf("plan recycle %s: %w", sess.ID, err))
+		}
+		output.Plans = append(output.Plans, plan)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(output)
+}
+
+// batchSessions returns the sessions created by batchID, sorted by name.
+// Only active sessions are returned unless includeCorrupted is set.
+func batchSessions(sessions []session.Session, batchID string, includeCorrupted bool) []session.Session {
+	var out []session.Session
+	for _, sess := range sessions {
+		if sess.BatchID() != batchID {
+			continue
+		}
+		if sess.State == session.StateActive || (includeCorrupted && sess.State == session.StateCorrupted) {
+			out = append(out, sess)
+		}
+	}
+	slices.SortFunc(out, func(a, b session.Session) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return out
+}
+
+// waitFlag returns the flag making sessions wait for a free slot under the
+// max_active limits, shared by batch and batch resume.
+func (cmd *BatchCmd) waitFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:        "wait",
+		Usage:       "queue sessions until a slot frees up instead of failing when max_active limits are reached",
+		Destination: &cmd.wait,
+	}
+}
+
+// failureFlags returns the flags controlling the failure threshold, shared
+// by batch and batch resume.
+func (cmd *BatchCmd) failureFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:        "max-failures",
+			Usage:       "skip remaining sessions after this many failures, 0 to never skip (default: batch.max_failures)",
+			Destination: &cmd.maxFailures,
+		},
+		&cli.BoolFlag{
+			Name:        "fail-fast",
+			Usage:       "skip remaining sessions after the first failure",
+			Destination: &cmd.failFast,
+		},
+		&cli.BoolFlag{
+			Name:        "keep-going",
+			Usage:       "attempt every session regardless of failures",
+			Destination: &cmd.keepGoing,
+		},
+	}
+}
+
+// failureLimit resolves the failure threshold from flags and config.
+// Returns 0 for no limit.
+func (cmd *BatchCmd) failureLimit(c *cli.Command) (int, error) {
+	set := 0
+	for _, name := range []string{"max-failures", "fail-fast", "keep-going"} {
+		if c.IsSet(name) {
+			set++
+		}
+	}
+	if set > 1 {
+		return 0, fmt.Errorf("--max-failures, --fail-fast, and --keep-going are mutually exclusive")
+	}
+
+	switch {
+	case cmd.failFast:
+		return 1, nil
+	case cmd.keepGoing:
+		return 0, nil
+	case c.IsSet("max-failures"):
+		if cmd.maxFailures < 0 {
+			return 0, fmt.Errorf("--max-failures must be >= 0, got %d", cmd.maxFailures)
+		}
+		return cmd.maxFailures, nil
+	default:
+		return cmd.flags.Config.Batch.FailureLimit(), nil
+	}
+}
+
 func (cmd *BatchCmd) run(ctx context.Context, c *cli.Command) error {
+	if cmd.dryRun {
+		return cmd.runDryRun(ctx)
+	}
+
+	maxFailures, err := cmd.failureLimit(c)
+	if err != nil {
+		return cmd.writeError(err)
+	}
+
 	batchID := randid.Generate(6)
 
 	logger, logFile, err := cmd.setupLogger(batchID)
@@ -190,8 +652,43 @@ func (cmd *BatchCmd) run(ctx context.Context, c *cli.Command) error {
 	}()
 
 	logger.Info().Str("batch_id", batchID).Msg("starting batch processing")
+	cmd.flags.Service.AutoBackup(ctx, hive.BackupBatch)
 
-	input, err := cmd.readInput()
+	dir, err := os.Getwd()
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to determine working directory")
+		return cmd.writeError(fmt.Errorf("determine working directory: %w", err))
+	}
+
+	in, err := cmd.openInput()
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to read input")
+		return cmd.writeError(fmt.Errorf("read input: %w", err))
+	}
+	defer func() { _ = in.Close() }()
+
+	br := bufio.NewReader(in)
+	format, err := cmd.inputFormat(br)
+	if err != nil {
+		return cmd.writeError(err)
+	}
+
+	now := time.Now()
+	state := BatchState{
+		BatchID:   batchID,
+		Dir:       dir,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if format == formatNDJSON {
+		if cmd.preview {
+			return cmd.writeError(fmt.Errorf("--preview is not supported with ndjson input"))
+		}
+		return cmd.stream(ctx, logger, &state, br, maxFailures)
+	}
+
+	input, err := decodeInput(br, format)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to read input")
 		return cmd.writeError(fmt.Errorf("read input: %w", err))
@@ -202,46 +699,279 @@ func (cmd *BatchCmd) run(ctx context.Context, c *cli.Command) error {
 		return cmd.writeError(fmt.Errorf("invalid input: %w", err))
 	}
 
-	output := BatchOutput{
-		BatchID: batchID,
-		LogFile: filepath.Join(cmd.flags.Config.LogsDir(), fmt.Sprintf("batch-%s.log", batchID)),
-		Results: make([]BatchResult, 0, len(input.Sessions)),
+	if err := input.validatePrompts(cmd.flags.Config.Templates); err != nil {
+		logger.Error().Err(err).Msg("template validation failed")
+		return cmd.writeError(fmt.Errorf("invalid input: %w", err))
+	}
+
+	if err := cmd.previewPrompts(ctx, input.Sessions); err != nil {
+		logger.Error().Err(err).Msg("prompt preview failed")
+		return cmd.writeError(err)
 	}
 
-	failures := 0
+	state.Sessions = input.Sessions
+	state.Results = make([]BatchResult, len(input.Sessions))
 	for i, sess := range input.Sessions {
-		if failures >= maxFailures {
-			logger.Warn().Str("name", sess.Name).Msg("skipping session due to failure threshold")
-			for j := i; j < len(input.Sessions); j++ {
-				output.Results = append(output.Results, BatchResult{
-					Name:   input.Sessions[j].Name,
-					Status: StatusSkipped,
-				})
+		if sess.promptPreambled {
+			state.Preambled = append(state.Preambled, i)
+		}
+	}
+
+	return cmd.execute(ctx, logger, &state, state.pending(), maxFailures)
+}
+
+// previewPrompts shows each templated prompt that needs a preview (all of
+// them with --preview, otherwise those whose template sets preview) and lets
+// the user accept, edit, or decline it. Accepted prompts replace the
+// template so the batch, and any later resume, uses exactly what was
+// reviewed. Template-level previews are skipped without a terminal.
+func (cmd *BatchCmd) previewPrompts(ctx context.Context, sessions []BatchSession) error {
+	defs := cmd.flags.Config.Templates
+
+	var pending []int
+	for i, sess := range sessions {
+		if sess.Template != "" && (cmd.preview || defs[sess.Template].Preview) {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	preview, closeTTY, err := openPreview()
+	if err != nil {
+		if cmd.preview {
+			return err
+		}
+		return nil
+	}
+	defer closeTTY()
+
+	for _, i := range pending {
+		sess := sessions[i]
+		prompt, err := renderPrompt(defs, sess)
+		if err != nil {
+			return err
+		}
+		remote, err := cmd.flags.Service.ResolveRemote(ctx, sess.Remote)
+		if err != nil {
+			return fmt.Errorf("session %q: %w", sess.Name, err)
+		}
+		prompt = cmd.flags.Service.PromptFor(remote, prompt)
+
+		prompt, ok, err := preview.confirm(ctx, sess.Name, prompt)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("batch cancelled at session %q", sess.Name)
+		}
+
+		sessions[i].Prompt = prompt
+		sessions[i].promptPreambled = true
+		sessions[i].Template = ""
+		sessions[i].Values = nil
+	}
+	return nil
+}
+
+func (cmd *BatchCmd) runResume(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 1 {
+		return cmd.writeError(fmt.Errorf("expected exactly one batch ID"))
+	}
+	batchID := c.Args().First()
+
+	maxFailures, err := cmd.failureLimit(c)
+	if err != nil {
+		return cmd.writeError(err)
+	}
+
+	state, err := loadBatchState(cmd.flags.Config.BatchesDir(), batchID)
+	if err != nil {
+		return cmd.writeError(err)
+	}
+
+	logger, logFile, err := cmd.setupLogger(batchID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "batch %s: failed to setup logger: %v\n", batchID, err)
+		return cmd.writeError(fmt.Errorf("setup logger: %w", err))
+	}
+	defer func() {
+		if err := logFile.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to close log file: %v\n", err)
+		}
+	}()
+
+	pending := state.pending()
+	logger.Info().Str("batch_id", batchID).Int("pending", len(pending)).Msg("resuming batch")
+
+	// Resolve defaults against the original directory, not wherever resume runs
+	for _, i := range pending {
+		sess := &state.Sessions[i]
+		if sess.Source == "" {
+			sess.Source = state.Dir
+		}
+		if sess.Remote == "" {
+			remote, err := cmd.flags.Service.DetectRemote(ctx, state.Dir)
+			if err != nil {
+				logger.Error().Err(err).Msg("failed to detect remote")
+				return cmd.writeError(fmt.Errorf("detect remote in %s: %w", state.Dir, err))
 			}
-			break
+			sess.Remote = remote
 		}
+	}
 
-		logger.Info().Str("name", sess.Name).Int("index", i).Msg("creating session")
+	return cmd.execute(ctx, logger, &state, pending, maxFailures)
+}
 
-		result := cmd.createSession(ctx, sess)
-		output.Results = append(output.Results, result)
+// execute creates the sessions at the given indices of state, merges their
+// results, persists the state, and writes the full result set.
+func (cmd *BatchCmd) execute(ctx context.Context, logger zerolog.Logger, state *BatchState, indices []int, maxFailures int) error {
+	output := BatchOutput{
+		BatchID: state.BatchID,
+		LogFile: filepath.Join(cmd.flags.Config.LogsDir(), fmt.Sprintf("batch-%s.log", state.BatchID)),
+	}
 
-		if result.Status == StatusFailed {
-			failures++
-			logger.Error().Str("name", sess.Name).Str("error", result.Error).Msg("session creation failed")
-		} else {
-			logger.Info().Str("name", sess.Name).Str("session_id", result.SessionID).Msg("session created")
+	concurrency := cmd.batchConcurrency()
+	logger.Info().
+		Int("concurrency", concurrency).
+		Int("max_failures", maxFailures).
+		Int("sessions", len(indices)).
+		Msg("processing sessions")
+
+	sessions := state.resumeSessions(indices)
+	results := runBatch(ctx, logger, sessions, concurrency, maxFailures, cmd.creator(state.BatchID))
+	for j, i := range indices {
+		state.Results[i] = results[j]
+	}
+	cmd.finish(logger, state)
+
+	output.Results = state.Results
+	if err := cmd.writeOutput(output); err != nil {
+		return err
+	}
+	return interrupted(ctx, state)
+}
+
+// stream creates sessions from NDJSON input as each line arrives, writing
+// every result as an NDJSON line once it is known.
+func (cmd *BatchCmd) stream(ctx context.Context, logger zerolog.Logger, state *BatchState, r io.Reader, maxFailures int) error {
+	concurrency := cmd.batchConcurrency()
+	logger.Info().Int("concurrency", concurrency).Int("max_failures", maxFailures).Msg("streaming sessions")
+
+	// The reader goroutine is the only writer of state.Sessions until the
+	// channel is closed, which happens before runBatchStream returns.
+	var scanErr error
+	items := make(chan batchItem)
+	go func() {
+		defer close(items)
+		scanErr = scanSessions(ctx, r, func(item batchItem) {
+			state.Sessions = append(state.Sessions, item.sess)
+			items <- item
+		})
+	}()
+
+	enc := json.NewEncoder(os.Stdout)
+	onResult := func(i int, result BatchResult) {
+		if err := enc.Encode(BatchStreamResult{BatchID: state.BatchID, Index: i, BatchResult: result}); err != nil {
+			logger.Error().Err(err).Msg("failed to write result")
 		}
 	}
 
+	state.Results = runBatchStream(ctx, logger, items, concurrency, maxFailures, cmd.creator(state.BatchID), onResult)
+	cmd.finish(logger, state)
+
+	if err := interrupted(ctx, state); err != nil {
+		return err
+	}
+	if scanErr != nil {
+		logger.Error().Err(scanErr).Msg("failed to read input")
+		fmt.Fprintf(os.Stderr, "batch %s: read input: %v\n", state.BatchID, scanErr)
+		return fmt.Errorf("read input: %w", scanErr)
+	}
+	return nil
+}
+
+// batchConcurrency returns --concurrency, falling back to batch.concurrency.
+func (cmd *BatchCmd) batchConcurrency() int {
+	if cmd.concurrency > 0 {
+		return cmd.concurrency
+	}
+	return cmd.flags.Config.Batch.Concurrency
+}
+
+// finish saves the batch state and logs a summary of the results.
+func (cmd *BatchCmd) finish(logger zerolog.Logger, state *BatchState) {
+	state.UpdatedAt = time.Now()
+
+	if err := saveBatchState(cmd.flags.Config.BatchesDir(), *state); err != nil {
+		logger.Error().Err(err).Msg("failed to save batch state")
+		fmt.Fprintf(os.Stderr, "warning: failed to save batch state: %v\n", err)
+	}
+
 	logger.Info().
-		Int("total", len(input.Sessions)).
-		Int("created", countByStatus(output.Results, StatusCreated)).
-		Int("failed", countByStatus(output.Results, StatusFailed)).
-		Int("skipped", countByStatus(output.Results, StatusSkipped)).
+		Int("total", len(state.Results)).
+		Int("created", countByStatus(state.Results, StatusCreated)).
+		Int("failed", countFailed(state.Results)).
+		Int("skipped", countByStatus(state.Results, StatusSkipped)).
 		Msg("batch processing complete")
 
-	return cmd.writeOutput(output)
+	cmd.flags.Service.Emit(events.BatchCompleted, "", map[string]any{
+		"batch_id": state.BatchID,
+		"total":    len(state.Results),
+		"created":  countByStatus(state.Results, StatusCreated),
+		"failed":   countFailed(state.Results),
+		"skipped":  countByStatus(state.Results, StatusSkipped),
+	})
+}
+
+// interrupted returns an error describing how to resume the batch if ctx
+// was cancelled while it ran. The state is already saved by finish.
+func interrupted(ctx context.Context, state *BatchState) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return fmt.Errorf("batch %s interrupted with %d created, %d failed, %d not started; run 'hive batch resume %s' to continue",
+		state.BatchID,
+		countByStatus(state.Results, StatusCreated),
+		countFailed(state.Results),
+		countByStatus(state.Results, StatusSkipped),
+		state.BatchID,
+	)
+}
+
+// runDryRun validates the input and writes the plan for each session.
+// It does not create a log file or touch any repositories.
+func (cmd *BatchCmd) runDryRun(ctx context.Context) error {
+	input, err := cmd.readInput()
+	if err != nil {
+		return cmd.writeError(fmt.Errorf("read input: %w", err))
+	}
+
+	if err := input.Validate(); err != nil {
+		return cmd.writeError(fmt.Errorf("invalid input: %w", err))
+	}
+
+	if err := input.validatePrompts(cmd.flags.Config.Templates); err != nil {
+		return cmd.writeError(fmt.Errorf("invalid input: %w", err))
+	}
+
+	opts := make([]hive.CreateOptions, len(input.Sessions))
+	for i, sess := range input.Sessions {
+		opts[i], err = createOptions(cmd.flags.Config.Templates, sess)
+		if err != nil {
+			return cmd.writeError(err)
+		}
+	}
+
+	plans, err := cmd.flags.Service.PlanSessions(ctx, opts)
+	if err != nil {
+		return cmd.writeError(fmt.Errorf("plan sessions: %w", err))
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(BatchPlanOutput{DryRun: true, Plans: plans})
 }
 
 func (cmd *BatchCmd) setupLogger(batchID string) (zerolog.Logger, *os.File, error) {
@@ -251,64 +981,405 @@ func (cmd *BatchCmd) setupLogger(batchID string) (zerolog.Logger, *os.File, erro
 	}
 
 	logPath := filepath.Join(logsDir, fmt.Sprintf("batch-%s.log", batchID))
-	file, err := os.Create(logPath)
+	// Append so resumed batches keep the history of earlier attempts
+	file, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 	if err != nil {
 		return zerolog.Logger{}, nil, fmt.Errorf("create log file: %w", err)
 	}
 
-	logger := zerolog.New(file).With().Timestamp().Logger()
+	// SyncWriter keeps lines from concurrent sessions from interleaving
+	logger := zerolog.New(zerolog.SyncWriter(file)).With().Timestamp().Logger()
 	return logger, file, nil
 }
 
+// batchItem is a session read from input. err is set when the entry could
+// not be decoded or failed validation; such entries are reported as failed
+// without being attempted.
+type batchItem struct {
+	sess BatchSession
+	err  error
+}
+
+// runBatch creates sessions with at most concurrency in flight. Once
+// maxFailures sessions have failed, or ctx is cancelled, sessions not yet
+// started are skipped; a maxFailures of 0 never skips on failures. Results are returned in input order
+// regardless of completion order.
+func runBatch(
+	ctx context.Context,
+	logger zerolog.Logger,
+	sessions []BatchSession,
+	concurrency int,
+	maxFailures int,
+	create func(context.Context, BatchSession) BatchResult,
+) []BatchResult {
+	items := make(chan batchItem)
+	go func() {
+		defer close(items)
+		for _, sess := range sessions {
+			items <- batchItem{sess: sess}
+		}
+	}()
+
+	return runBatchStream(ctx, logger, items, concurrency, maxFailures, create, nil)
+}
+
+// runBatchStream is runBatch for input that arrives over time. It returns
+// once items is closed and all started sessions have finished. If onResult
+// is set, it is called with each result and its input index as soon as the
+// result is known; calls never overlap.
+func runBatchStream(
+	ctx context.Context,
+	logger zerolog.Logger,
+	items <-chan batchItem,
+	concurrency int,
+	maxFailures int,
+	create func(context.Context, BatchSession) BatchResult,
+	onResult func(int, BatchResult),
+) []BatchResult {
+	concurrency = max(concurrency, 1)
+
+	var (
+		results  []BatchResult
+		sem      = make(chan struct{}, concurrency)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures int
+	)
+
+	// record stores a result; callers must hold mu.
+	record := func(i int, result BatchResult) {
+		results[i] = result
+		if onResult != nil {
+			onResult(i, result)
+		}
+	}
+
+	i := 0
+	for item := range items {
+		idx := i
+		i++
+
+		mu.Lock()
+		results = append(results, BatchResult{})
+		mu.Unlock()
+
+		if item.err != nil {
+			logger.Error().Err(item.err).Int("index", idx).Msg("invalid session")
+			mu.Lock()
+			record(idx, BatchResult{Name: item.sess.Name, Status: StatusFailed, Error: item.err.Error()})
+			mu.Unlock()
+			continue
+		}
+
+		sem <- struct{}{}
+
+		mu.Lock()
+		interrupted := ctx.Err() != nil
+		stop := interrupted || (maxFailures > 0 && failures >= maxFailures)
+		if stop {
+			record(idx, BatchResult{Name: item.sess.Name, Status: StatusSkipped})
+		}
+		mu.Unlock()
+
+		if stop {
+			<-sem
+			if interrupted {
+				logger.Warn().Str("name", item.sess.Name).Msg("skipping session after interrupt")
+			} else {
+				logger.Warn().Str("name", item.sess.Name).Msg("skipping session due to failure threshold")
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, sess BatchSession) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sessLog := logger.With().Str("name", sess.Name).Int("index", i).Logger()
+			sessLog.Info().Msg("creating session")
+
+			// Command output is logged line by line with the session's tags
+			out := &logLines{log: sessLog}
+			result := create(hive.WithOutput(ctx, out), sess)
+			out.Flush()
+
+			mu.Lock()
+			record(i, result)
+			failed := result.Status == StatusFailed || result.Status == StatusSpawnFailed
+			if failed {
+				failures++
+			}
+			mu.Unlock()
+
+			if failed {
+				sessLog.Error().Str("error", result.Error).Msg("session creation failed")
+			} else {
+				sessLog.Info().Str("session_id", result.SessionID).Msg("session created")
+			}
+		}(idx, item.sess)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// logLines is a writer that logs each line written to it as an "output"
+// entry, so the command output of concurrent sessions can be told apart in
+// the batch log. Carriage returns, as in git's progress, also end a line.
+type logLines struct {
+	mu  sync.Mutex
+	log zerolog.Logger
+	buf []byte
+}
+
+func (w *logLines) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexAny(w.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		w.emit(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush logs output left without a trailing newline.
+func (w *logLines) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit(w.buf)
+	w.buf = nil
+}
+
+func (w *logLines) emit(line []byte) {
+	if line = bytes.TrimSpace(line); len(line) > 0 {
+		w.log.Info().Str("output", string(line)).Send()
+	}
+}
+
+// readInput reads and decodes the whole batch input.
 func (cmd *BatchCmd) readInput() (BatchInput, error) {
-	var reader io.Reader
+	in, err := cmd.openInput()
+	if err != nil {
+		return BatchInput{}, err
+	}
+	defer func() { _ = in.Close() }()
+
+	br := bufio.NewReader(in)
+	format, err := cmd.inputFormat(br)
+	if err != nil {
+		return BatchInput{}, err
+	}
 
+	return decodeInput(br, format)
+}
+
+// openInput opens the input file, or stdin when no file is given.
+func (cmd *BatchCmd) openInput() (io.ReadCloser, error) {
 	if cmd.file != "" {
 		f, err := os.Open(cmd.file)
 		if err != nil {
-			return BatchInput{}, fmt.Errorf("open file: %w", err)
+			return nil, fmt.Errorf("open file: %w", err)
 		}
-		defer func() { _ = f.Close() }()
-		reader = f
-	} else {
-		if term.IsTerminal(int(os.Stdin.Fd())) {
-			return BatchInput{}, fmt.Errorf("no input provided (stdin is a terminal); use -f flag or pipe JSON input")
+		return f, nil
+	}
+
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("no input provided (stdin is a terminal); use -f flag or pipe JSON input")
+	}
+	return io.NopCloser(os.Stdin), nil
+}
+
+// inputFormat resolves the input format from --format, then the file
+// extension, and finally by peeking at the first non-whitespace byte.
+func (cmd *BatchCmd) inputFormat(br *bufio.Reader) (string, error) {
+	switch cmd.format {
+	case formatJSON, formatYAML, formatNDJSON:
+		return cmd.format, nil
+	case "", formatAuto:
+	default:
+		return "", fmt.Errorf("unknown format %q (expected auto, json, yaml, or ndjson)", cmd.format)
+	}
+
+	switch strings.ToLower(filepath.Ext(cmd.file)) {
+	case ".json":
+		return formatJSON, nil
+	case ".yaml", ".yml":
+		return formatYAML, nil
+	case ".ndjson", ".jsonl":
+		return formatNDJSON, nil
+	}
+
+	return sniffFormat(br), nil
+}
+
+// sniffFormat reports JSON if the input starts with "{" and YAML otherwise.
+// Empty input is treated as JSON so the decoder reports it.
+func sniffFormat(br *bufio.Reader) string {
+	for n := 1; ; n++ {
+		b, err := br.Peek(n)
+		if err != nil {
+			return formatJSON
+		}
+		switch b[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		case '{':
+			return formatJSON
+		default:
+			return formatYAML
 		}
-		reader = os.Stdin
 	}
+}
 
+// decodeInput decodes a complete batch input in the given format.
+func decodeInput(r io.Reader, format string) (BatchInput, error) {
 	var input BatchInput
-	if err := json.NewDecoder(reader).Decode(&input); err != nil {
-		return BatchInput{}, fmt.Errorf("decode JSON: %w", err)
+
+	switch format {
+	case formatYAML:
+		if err := yaml.NewDecoder(r).Decode(&input); err != nil {
+			if errors.Is(err, io.EOF) {
+				return BatchInput{}, fmt.Errorf("decode YAML: input is empty")
+			}
+			return BatchInput{}, fmt.Errorf("decode YAML: %w", err)
+		}
+	case formatNDJSON:
+		var decodeErr error
+		err := scanSessions(context.Background(), r, func(item batchItem) {
+			if item.err != nil && decodeErr == nil {
+				decodeErr = item.err
+			}
+			input.Sessions = append(input.Sessions, item.sess)
+		})
+		if err != nil {
+			return BatchInput{}, err
+		}
+		if decodeErr != nil {
+			return BatchInput{}, decodeErr
+		}
+	default:
+		if err := json.NewDecoder(r).Decode(&input); err != nil {
+			return BatchInput{}, fmt.Errorf("decode JSON: %w", err)
+		}
 	}
 
 	return input, nil
 }
 
-func (cmd *BatchCmd) createSession(ctx context.Context, sess BatchSession) BatchResult {
-	source := sess.Source
-	if source == "" {
-		var err error
-		source, err = os.Getwd()
-		if err != nil {
-			return BatchResult{
-				Name:   sess.Name,
-				Status: StatusFailed,
-				Error:  fmt.Errorf("determine source directory: %w", err).Error(),
+// scanSessions reads NDJSON sessions from r and calls emit for each non-blank
+// line. Lines that fail to decode or validate are emitted with an error.
+// It stops as soon as ctx is canceled, even while waiting for input.
+func scanSessions(ctx context.Context, r io.Reader, emit func(batchItem)) error {
+	// Reads block until a line or EOF arrives, as on a terminal or pipe, so
+	// they run apart from the loop below, which can stop waiting on cancel
+	type scanned struct {
+		text []byte
+		err  error
+	}
+	lines := make(chan scanned)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanned{text: bytes.Clone(scanner.Bytes())}:
+			case <-done:
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			select {
+			case lines <- scanned{err: err}:
+			case <-done:
 			}
 		}
+	}()
+
+	v := newSessionValidator()
+	line := 0
+	for {
+		var next scanned
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case next, ok = <-lines:
+		}
+		if !ok {
+			return nil
+		}
+		if next.err != nil {
+			return fmt.Errorf("scan input: %w", next.err)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		line++
+		text := bytes.TrimSpace(next.text)
+		if len(text) == 0 {
+			continue
+		}
+
+		var sess BatchSession
+		if err := json.Unmarshal(text, &sess); err != nil {
+			emit(batchItem{err: fmt.Errorf("line %d: decode JSON: %w", line, err)})
+			continue
+		}
+
+		if field, err := v.check(sess); err != nil {
+			emit(batchItem{sess: sess, err: fmt.Errorf("line %d: %s: %w", line, field, err)})
+			continue
+		}
+
+		emit(batchItem{sess: sess})
+	}
+}
+
+// creator returns a create function for runBatch that tags sessions with batchID.
+func (cmd *BatchCmd) creator(batchID string) func(context.Context, BatchSession) BatchResult {
+	return func(ctx context.Context, sess BatchSession) BatchResult {
+		return cmd.createSession(ctx, batchID, sess)
 	}
+}
 
-	opts := hive.CreateOptions{
-		Name:          sess.Name,
-		SessionID:     sess.SessionID,
-		Prompt:        sess.Prompt,
-		Remote:        sess.Remote,
-		Source:        source,
-		UseBatchSpawn: true,
+func (cmd *BatchCmd) createSession(ctx context.Context, batchID string, sess BatchSession) BatchResult {
+	if sess.respawn != nil {
+		return cmd.respawnSession(ctx, *sess.respawn)
 	}
 
+	opts, err := createOptions(cmd.flags.Config.Templates, sess)
+	if err != nil {
+		return BatchResult{
+			Name:   sess.Name,
+			Status: StatusFailed,
+			Error:  err.Error(),
+		}
+	}
+	opts.BatchID = batchID
+	opts.Wait = cmd.wait
+
 	created, err := cmd.flags.Service.CreateSession(ctx, opts)
+	if errors.Is(err, hive.ErrSpawnFailed) && created != nil {
+		return BatchResult{
+			Name:      sess.Name,
+			SessionID: created.ID,
+			Path:      created.Path,
+			Status:    StatusSpawnFailed,
+			Error:     err.Error(),
+		}
+	}
 	if err != nil {
 		return BatchResult{
 			Name:   sess.Name,
@@ -325,6 +1396,72 @@ func (cmd *BatchCmd) createSession(ctx context.Context, sess BatchSession) Batch
 	}
 }
 
+// respawnSession runs the spawn commands again for a session created by an
+// earlier run of the batch whose spawn failed.
+func (cmd *BatchCmd) respawnSession(ctx context.Context, prev BatchResult) BatchResult {
+	result := BatchResult{Name: prev.Name, SessionID: prev.SessionID, Path: prev.Path, Status: StatusCreated}
+	if err := cmd.flags.Service.SpawnSession(ctx, prev.SessionID, ""); err != nil {
+		result.Status = StatusSpawnFailed
+		result.Error = fmt.Errorf("%w: %w", hive.ErrSpawnFailed, err).Error()
+	}
+	return result
+}
+
+// createOptions converts a batch session into service options, rendering
+// its template prompt and defaulting the source directory to the current
+// working directory.
+func createOptions(defs map[string]config.Template, sess BatchSession) (hive.CreateOptions, error) {
+	prompt, err := renderPrompt(defs, sess)
+	if err != nil {
+		return hive.CreateOptions{}, err
+	}
+
+	source := sess.Source
+	if source == "" {
+		source, err = os.Getwd()
+		if err != nil {
+			return hive.CreateOptions{}, fmt.Errorf("determine source directory: %w", err)
+		}
+	}
+
+	return hive.CreateOptions{
+		Name:            sess.Name,
+		SessionID:       sess.SessionID,
+		Prompt:          prompt,
+		Remote:          sess.Remote,
+		Source:          source,
+		UseBatchSpawn:   true,
+		SpawnProfile:    sess.Spawn,
+		Subdir:          sess.Subdir,
+		PromptPreambled: sess.promptPreambled,
+	}, nil
+}
+
+// renderPrompt returns the session's prompt, rendering its template if set.
+func renderPrompt(defs map[string]config.Template, sess BatchSession) (string, error) {
+	if sess.Template == "" {
+		return sess.Prompt, nil
+	}
+
+	t, err := templates.Lookup(defs, sess.Template)
+	if err != nil {
+		return "", err
+	}
+
+	values := make(map[string]string, len(sess.Values))
+	for k, v := range sess.Values {
+		if v != nil {
+			values[k] = fmt.Sprint(v)
+		}
+	}
+
+	prompt, err := templates.Render(t, values)
+	if err != nil {
+		return "", fmt.Errorf("template %q: %w", sess.Template, err)
+	}
+	return prompt, nil
+}
+
 func (cmd *BatchCmd) writeOutput(output BatchOutput) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -334,7 +1471,7 @@ func (cmd *BatchCmd) writeOutput(output BatchOutput) error {
 		fmt.Fprintf(os.Stderr, "log_file: %s\n", output.LogFile)
 		fmt.Fprintf(os.Stderr, "results: %d created, %d failed, %d skipped\n",
 			countByStatus(output.Results, StatusCreated),
-			countByStatus(output.Results, StatusFailed),
+			countFailed(output.Results),
 			countByStatus(output.Results, StatusSkipped))
 		return err
 	}
@@ -351,6 +1488,58 @@ func (cmd *BatchCmd) writeError(err error) error {
 	return err
 }
 
+// saveBatchState writes state to <dir>/<batch-id>.json atomically.
+func saveBatchState(dir string, state BatchState) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create batches dir: %w", err)
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal batch state: %w", err)
+	}
+
+	path := filepath.Join(dir, state.BatchID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write batch state: %w", err)
+	}
+	return nil
+}
+
+// loadBatchState reads the saved state for batchID.
+func loadBatchState(dir, batchID string) (BatchState, error) {
+	if batchID == "" || strings.ContainsAny(batchID, `/\`) {
+		return BatchState{}, fmt.Errorf("invalid batch ID %q", batchID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, batchID+".json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return BatchState{}, fmt.Errorf("batch %q not found", batchID)
+		}
+		return BatchState{}, fmt.Errorf("read batch state: %w", err)
+	}
+
+	var state BatchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return BatchState{}, fmt.Errorf("decode batch state: %w", err)
+	}
+	if len(state.Results) < len(state.Sessions) {
+		state.Results = append(state.Results, make([]BatchResult, len(state.Sessions)-len(state.Results))...)
+	}
+	return state, nil
+}
+
+// countFailed counts results that failed to be created or spawned.
+func countFailed(results []BatchResult) int {
+	return countByStatus(results, StatusFailed) + countByStatus(results, StatusSpawnFailed)
+}
+
 func countByStatus(results []BatchResult, status string) int {
 	count := 0
 	for _, r := range results {
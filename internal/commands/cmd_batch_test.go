@@ -1,9 +1,23 @@
 package commands
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
 )
 
 func TestBatchInput_Validate(t *testing.T) {
@@ -53,6 +67,20 @@ func TestBatchInput_Validate(t *testing.T) {
 			}},
 			wantErr: "session_id",
 		},
+		{
+			name: "prompt and template",
+			input: BatchInput{Sessions: []BatchSession{
+				{Name: "test", Prompt: "p", Template: "pr-review"},
+			}},
+			wantErr: "cannot set both",
+		},
+		{
+			name: "values without template",
+			input: BatchInput{Sessions: []BatchSession{
+				{Name: "test", Values: map[string]any{"a": "b"}},
+			}},
+			wantErr: "values require a template",
+		},
 		{
 			name: "invalid session_id with space",
 			input: BatchInput{Sessions: []BatchSession{
@@ -220,3 +248,466 @@ func TestCountByStatus(t *testing.T) {
 		t.Errorf("countByStatus(skipped) = %d, want 3", got)
 	}
 }
+
+func TestRunBatch_OrderAndConcurrency(t *testing.T) {
+	sessions := make([]BatchSession, 8)
+	for i := range sessions {
+		sessions[i] = BatchSession{Name: fmt.Sprintf("s%d", i)}
+	}
+
+	var inFlight, peak atomic.Int32
+	create := func(_ context.Context, sess BatchSession) BatchResult {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+		return BatchResult{Name: sess.Name, Status: StatusCreated}
+	}
+
+	results := runBatch(context.Background(), zerolog.Nop(), sessions, 3, 0, create)
+
+	require.Len(t, results, len(sessions))
+	for i, r := range results {
+		assert.Equal(t, sessions[i].Name, r.Name, "results must keep input order")
+		assert.Equal(t, StatusCreated, r.Status)
+	}
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Greater(t, peak.Load(), int32(1))
+}
+
+func TestRunBatch_TagsSessionOutput(t *testing.T) {
+	sessions := []BatchSession{{Name: "a"}, {Name: "b"}}
+
+	// Clone progress reaches the context's progress writer, as with git
+	create := func(ctx context.Context, sess BatchSession) BatchResult {
+		w := git.ProgressWriter(ctx)
+		_, _ = io.WriteString(w, "Receiving objects:  50%\rReceiving objects: 100%\n")
+		_, _ = io.WriteString(w, "done in "+sess.Name)
+		return BatchResult{Name: sess.Name, Status: StatusCreated}
+	}
+
+	var buf strings.Builder
+	logger := zerolog.New(zerolog.SyncWriter(&buf))
+	runBatch(context.Background(), logger, sessions, 2, 0, create)
+
+	type entry struct {
+		Name   string `json:"name"`
+		Index  int    `json:"index"`
+		Output string `json:"output"`
+	}
+	var got []entry
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		var e entry
+		require.NoError(t, json.Unmarshal([]byte(line), &e))
+		if e.Output != "" {
+			got = append(got, e)
+		}
+	}
+
+	assert.ElementsMatch(t, []entry{
+		{Name: "a", Index: 0, Output: "Receiving objects:  50%"},
+		{Name: "a", Index: 0, Output: "Receiving objects: 100%"},
+		{Name: "a", Index: 0, Output: "done in a"},
+		{Name: "b", Index: 1, Output: "Receiving objects:  50%"},
+		{Name: "b", Index: 1, Output: "Receiving objects: 100%"},
+		{Name: "b", Index: 1, Output: "done in b"},
+	}, got)
+}
+
+func TestRunBatch_FailureThreshold(t *testing.T) {
+	sessions := make([]BatchSession, 6)
+	for i := range sessions {
+		sessions[i] = BatchSession{Name: fmt.Sprintf("s%d", i)}
+	}
+
+	create := func(_ context.Context, sess BatchSession) BatchResult {
+		return BatchResult{Name: sess.Name, Status: StatusFailed, Error: "boom"}
+	}
+
+	tests := []struct {
+		name        string
+		maxFailures int
+		wantFailed  int
+	}{
+		{name: "default threshold", maxFailures: 3, wantFailed: 3},
+		{name: "fail fast", maxFailures: 1, wantFailed: 1},
+		{name: "keep going", maxFailures: 0, wantFailed: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := runBatch(context.Background(), zerolog.Nop(), sessions, 1, tt.maxFailures, create)
+
+			require.Len(t, results, 6)
+			assert.Equal(t, tt.wantFailed, countByStatus(results, StatusFailed))
+			assert.Equal(t, 6-tt.wantFailed, countByStatus(results, StatusSkipped))
+		})
+	}
+}
+
+func TestRunBatch_Interrupted(t *testing.T) {
+	sessions := make([]BatchSession, 5)
+	for i := range sessions {
+		sessions[i] = BatchSession{Name: fmt.Sprintf("s%d", i)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	create := func(_ context.Context, sess BatchSession) BatchResult {
+		if sess.Name == "s1" {
+			cancel()
+		}
+		return BatchResult{Name: sess.Name, Status: StatusCreated}
+	}
+
+	results := runBatch(ctx, zerolog.Nop(), sessions, 1, 0, create)
+
+	require.Len(t, results, 5)
+	assert.Equal(t, 2, countByStatus(results, StatusCreated))
+	assert.Equal(t, 3, countByStatus(results, StatusSkipped))
+
+	state := &BatchState{BatchID: "abc123", Results: results}
+	err := interrupted(ctx, state)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hive batch resume abc123")
+	assert.NoError(t, interrupted(context.Background(), state))
+}
+
+func TestBatchState_Pending(t *testing.T) {
+	state := BatchState{
+		Sessions: []BatchSession{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+		Results: []BatchResult{
+			{Name: "a", Status: StatusCreated},
+			{Name: "b", Status: StatusFailed},
+			{Name: "c", Status: StatusSkipped},
+		},
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, state.pending())
+}
+
+func TestBatchState_ResumeSpawnFailed(t *testing.T) {
+	state := BatchState{
+		Sessions: []BatchSession{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		Results: []BatchResult{
+			{Name: "a", Status: StatusCreated},
+			{Name: "b", SessionID: "abc123", Path: "/tmp/b", Status: StatusSpawnFailed, Error: "spawn failed: exit status 1"},
+			{Name: "c", Status: StatusFailed},
+		},
+	}
+
+	pending := state.pending()
+	require.Equal(t, []int{1, 2}, pending)
+
+	sessions := state.resumeSessions(pending)
+	require.NotNil(t, sessions[0].respawn, "spawn failures are spawned again, not created")
+	assert.Equal(t, "abc123", sessions[0].respawn.SessionID)
+	assert.Equal(t, "/tmp/b", sessions[0].respawn.Path)
+	assert.Nil(t, sessions[1].respawn)
+	assert.Equal(t, 2, countFailed(state.Results))
+}
+
+func TestBatchState_ResumePreambled(t *testing.T) {
+	dir := t.TempDir()
+	state := BatchState{
+		BatchID:   "abc123",
+		Sessions:  []BatchSession{{Name: "a", Prompt: "preamble\n\ntask"}, {Name: "b", Prompt: "task"}},
+		Preambled: []int{0},
+	}
+	require.NoError(t, saveBatchState(dir, state))
+
+	got, err := loadBatchState(dir, "abc123")
+	require.NoError(t, err)
+
+	sessions := got.resumeSessions(got.pending())
+	require.Len(t, sessions, 2)
+	assert.True(t, sessions[0].promptPreambled, "previewed prompts are not preambled again")
+	assert.False(t, sessions[1].promptPreambled)
+
+	opts, err := createOptions(nil, sessions[0])
+	require.NoError(t, err)
+	assert.True(t, opts.PromptPreambled)
+}
+
+func TestBatchState_SaveLoad(t *testing.T) {
+	dir := t.TempDir()
+	state := BatchState{
+		BatchID:  "abc123",
+		Dir:      "/work",
+		Sessions: []BatchSession{{Name: "a"}, {Name: "b"}},
+		Results:  []BatchResult{{Name: "a", Status: StatusCreated}},
+	}
+
+	require.NoError(t, saveBatchState(dir, state))
+
+	got, err := loadBatchState(dir, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "/work", got.Dir)
+	assert.Len(t, got.Results, 2, "results are padded to match sessions")
+	assert.Equal(t, []int{1}, got.pending())
+
+	_, err = loadBatchState(dir, "missing")
+	require.ErrorContains(t, err, "not found")
+
+	_, err = loadBatchState(dir, "../etc")
+	require.ErrorContains(t, err, "invalid batch ID")
+}
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		input   string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "json",
+			format: formatJSON,
+			input:  `{"sessions":[{"name":"a"},{"name":"b","prompt":"p"}]}`,
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "yaml",
+			format: formatYAML,
+			input:  "sessions:\n  - name: a\n    session_id: abc123\n  - name: b\n",
+			want:   []string{"a", "b"},
+		},
+		{
+			name:    "empty yaml",
+			format:  formatYAML,
+			input:   "",
+			wantErr: "input is empty",
+		},
+		{
+			name:   "ndjson skips blank lines",
+			format: formatNDJSON,
+			input:  "{\"name\":\"a\"}\n\n{\"name\":\"b\"}\n",
+			want:   []string{"a", "b"},
+		},
+		{
+			name:    "ndjson bad line",
+			format:  formatNDJSON,
+			input:   "{\"name\":\"a\"}\nnot json\n",
+			wantErr: "line 2",
+		},
+		{
+			name:    "json prompt_preambled",
+			format:  formatJSON,
+			input:   `{"sessions":[{"name":"a","prompt":"p","prompt_preambled":true}]}`,
+			wantErr: "prompt_preambled is set by hive",
+		},
+		{
+			name:    "yaml prompt_preambled",
+			format:  formatYAML,
+			input:   "sessions:\n  - name: a\n    prompt_preambled: false\n",
+			wantErr: "prompt_preambled is set by hive",
+		},
+		{
+			name:    "ndjson prompt_preambled",
+			format:  formatNDJSON,
+			input:   "{\"name\":\"a\",\"prompt_preambled\":true}\n",
+			wantErr: "line 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeInput(strings.NewReader(tt.input), tt.format)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, len(got.Sessions))
+			for i, s := range got.Sessions {
+				names[i] = s.Name
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestSniffFormat(t *testing.T) {
+	assert.Equal(t, formatJSON, sniffFormat(bufio.NewReader(strings.NewReader("  \n{\"sessions\":[]}"))))
+	assert.Equal(t, formatYAML, sniffFormat(bufio.NewReader(strings.NewReader("sessions:\n  - name: a\n"))))
+	assert.Equal(t, formatJSON, sniffFormat(bufio.NewReader(strings.NewReader(""))))
+}
+
+func TestRunBatchStream_InvalidLinesAndCallback(t *testing.T) {
+	input := "{\"name\":\"a\"}\n{\"name\":\"a\"}\n{bad\n{\"name\":\"b\"}\n"
+
+	items := make(chan batchItem)
+	go func() {
+		defer close(items)
+		_ = scanSessions(context.Background(), strings.NewReader(input), func(item batchItem) {
+			items <- item
+		})
+	}()
+
+	create := func(_ context.Context, sess BatchSession) BatchResult {
+		return BatchResult{Name: sess.Name, Status: StatusCreated}
+	}
+
+	var seen []int
+	results := runBatchStream(context.Background(), zerolog.Nop(), items, 2, 3, create, func(i int, _ BatchResult) {
+		seen = append(seen, i)
+	})
+
+	require.Len(t, results, 4)
+	assert.Equal(t, StatusCreated, results[0].Status)
+	assert.Equal(t, StatusFailed, results[1].Status)
+	assert.Contains(t, results[1].Error, "duplicate name")
+	assert.Equal(t, StatusFailed, results[2].Status)
+	assert.Contains(t, results[2].Error, "line 3")
+	assert.Equal(t, StatusCreated, results[3].Status)
+	assert.ElementsMatch(t, []int{0, 1, 2, 3}, seen)
+}
+
+func TestScanSessions_StopsWaitingOnCancel(t *testing.T) {
+	// Input that stays open, as stdin does until the writer closes it
+	r, w := io.Pipe()
+	t.Cleanup(func() { _ = w.Close() })
+	go func() { _, _ = io.WriteString(w, "{\"name\":\"a\"}\n") }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	var names []string
+	go func() {
+		done <- scanSessions(ctx, r, func(item batchItem) {
+			names = append(names, item.sess.Name)
+			cancel()
+		})
+	}()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"a"}, names)
+	case <-time.After(5 * time.Second):
+		t.Fatal("scanSessions kept waiting for input after cancel")
+	}
+}
+
+func TestRenderPrompt(t *testing.T) {
+	defs := map[string]config.Template{
+		"pr-review": {
+			Prompt: "Review PR #{{ .pr_number }}",
+			Fields: []config.TemplateField{{Name: "pr_number", Required: true}},
+		},
+	}
+
+	got, err := renderPrompt(defs, BatchSession{Name: "a", Prompt: "plain"})
+	require.NoError(t, err)
+	assert.Equal(t, "plain", got)
+
+	got, err = renderPrompt(defs, BatchSession{Name: "a", Template: "pr-review", Values: map[string]any{"pr_number": 123}})
+	require.NoError(t, err)
+	assert.Equal(t, "Review PR #123", got)
+
+	input := BatchInput{Sessions: []BatchSession{
+		{Name: "a", Template: "pr-review"},
+		{Name: "b", Template: "missing"},
+	}}
+	err = input.validatePrompts(defs)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sessions[0].template")
+	assert.Contains(t, err.Error(), "sessions[1].template")
+}
+
+func TestBatchCmd_PreviewPromptsSkipsUnflagged(t *testing.T) {
+	cmd := NewBatchCmd(&Flags{Config: &config.Config{
+		Templates: map[string]config.Template{"plain": {Prompt: "Do it"}},
+	}})
+
+	sessions := []BatchSession{
+		{Name: "a", Prompt: "raw"},
+		{Name: "b", Template: "plain"},
+	}
+	require.NoError(t, cmd.previewPrompts(context.Background(), sessions))
+	assert.Equal(t, "plain", sessions[1].Template, "sessions without a preview keep their template")
+}
+
+func TestBatchCmd_FailureLimit(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+
+	tests := []struct {
+		name    string
+		config  *int
+		args    []string
+		want    int
+		wantErr string
+	}{
+		{name: "default", want: config.DefaultBatchMaxFailures},
+		{name: "from config", config: intPtr(5), want: 5},
+		{name: "max failures flag", config: intPtr(5), args: []string{"--max-failures", "2"}, want: 2},
+		{name: "fail fast", args: []string{"--fail-fast"}, want: 1},
+		{name: "keep going", args: []string{"--keep-going"}, want: 0},
+		{name: "negative", args: []string{"--max-failures", "-1"}, wantErr: ">= 0"},
+		{name: "exclusive", args: []string{"--fail-fast", "--keep-going"}, wantErr: "mutually exclusive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Batch: config.BatchConfig{MaxFailures: tt.config}}
+			cmd := NewBatchCmd(&Flags{Config: cfg})
+
+			var (
+				got    int
+				gotErr error
+			)
+			app := &cli.Command{
+				Name:  "batch",
+				Flags: cmd.failureFlags(),
+				Action: func(_ context.Context, c *cli.Command) error {
+					got, gotErr = cmd.failureLimit(c)
+					return nil
+				},
+			}
+			require.NoError(t, app.Run(context.Background(), append([]string{"batch"}, tt.args...)))
+
+			if tt.wantErr != "" {
+				require.ErrorContains(t, gotErr, tt.wantErr)
+				return
+			}
+			require.NoError(t, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBatchSessions(t *testing.T) {
+	mk := func(id, name, batch string, state session.State) session.Session {
+		s := session.Session{ID: id, Name: name, State: state}
+		if batch != "" {
+			s.SetMeta(session.MetaBatchID, batch)
+		}
+		return s
+	}
+
+	sessions := []session.Session{
+		mk("1", "b", "abc", session.StateActive),
+		mk("2", "a", "abc", session.StateActive),
+		mk("3", "c", "abc", session.StateRecycled),
+		mk("4", "d", "abc", session.StateCorrupted),
+		mk("5", "e", "other", session.StateActive),
+		mk("6", "f", "", session.StateActive),
+	}
+
+	ids := func(ss []session.Session) []string {
+		out := make([]string, len(ss))
+		for i, s := range ss {
+			out[i] = s.ID
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"2", "1"}, ids(batchSessions(sessions, "abc", false)))
+	assert.Equal(t, []string{"2", "1", "4"}, ids(batchSessions(sessions, "abc", true)))
+	assert.Empty(t, batchSessions(sessions, "missing", true))
+}
@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/urfave/cli/v3"
+)
+
+type CICmd struct {
+	flags *Flags
+
+	// flags
+	noWait     bool
+	timeout    time.Duration
+	jsonOutput bool
+}
+
+// NewCICmd creates a new ci command.
+func NewCICmd(flags *Flags) *CICmd {
+	return &CICmd{flags: flags}
+}
+
+// Register adds the ci command to the application.
+func (cmd *CICmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "ci",
+		Usage:     "Trigger CI for a session and wait for the result",
+		ArgsUsage: "[session-id|name]",
+		Description: `Runs the ci.trigger command of the session's last matching rule in its
+working directory, then waits for CI to report a status on the session's
+CI topic, agent.<session-id>.ci unless the rule sets ci.topic.
+
+The trigger gets the session's HIVE_* variables plus HIVE_CI_TOPIC, and its
+template the same fields as rule commands plus .Branch and .Topic. CI reports
+back by publishing to the topic, e.g. with 'hive msg pub' or POST
+/v1/topics/<topic>/messages on hive serve. A status is plain text starting with the state,
+or JSON with a status or conclusion field: success/passed and
+failure/failed/cancelled end the wait; anything else is shown as progress.
+
+hive exits non-zero when CI fails or no final status arrives within the
+rule's ci.timeout (default 30m). Without a session, the session of the
+working directory is used, or one is picked interactively.
+
+Example:
+  hive ci
+  hive ci fix-auth --timeout 1h
+  hive ci abc123 --no-wait`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "no-wait",
+				Usage:       "trigger CI and exit without waiting for a status",
+				Destination: &cmd.noWait,
+			},
+			&cli.DurationFlag{
+				Name:        "timeout",
+				Usage:       "how long to wait for a final status (default: the rule's ci.timeout)",
+				Destination: &cmd.timeout,
+			},
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+// ciOutput is the JSON output format for hive ci.
+type ciOutput struct {
+	ID      string `json:"id"`
+	Topic   string `json:"topic"`
+	State   string `json:"state,omitempty"` // empty with --no-wait
+	Payload string `json:"payload,omitempty"`
+}
+
+func (cmd *CICmd) run(ctx context.Context, c *cli.Command) error {
+	ref, err := cmd.sessionRef(ctx, c.Args().Slice())
+	if err != nil {
+		return err
+	}
+
+	asJSON := wantJSON(ctx, cmd.jsonOutput)
+	p := printer.Ctx(ctx)
+
+	// Keep stdout for the JSON result
+	stdout := c.Root().Writer
+	if asJSON {
+		stdout = c.Root().ErrWriter
+	}
+	run, err := cmd.flags.Service.TriggerCI(ctx, ref, stdout, c.Root().ErrWriter)
+	if err != nil {
+		return err
+	}
+
+	out := ciOutput{ID: run.SessionID, Topic: run.Topic}
+	if cmd.noWait {
+		if asJSON {
+			return printer.EncodeJSON(c.Root().Writer, out)
+		}
+		p.Success("CI triggered", "status on "+run.Topic)
+		return nil
+	}
+
+	if cmd.timeout > 0 {
+		run.Timeout = cmd.timeout
+	}
+	if !asJSON {
+		p.Infof("CI triggered; waiting up to %s for a status on %s", run.Timeout, run.Topic)
+	}
+
+	store := jsonfile.NewMsgStore(filepath.Join(cmd.flags.DataDir, "messages", "topics"))
+	status, err := cmd.flags.Service.WaitCIStatus(ctx, store, run, func(s hive.CIStatus) {
+		if !asJSON && !s.Done() {
+			p.Infof("CI %s", s.Message.Payload)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	out.State, out.Payload = status.State, status.Message.Payload
+	if asJSON {
+		if err := printer.EncodeJSON(c.Root().Writer, out); err != nil {
+			return err
+		}
+	}
+
+	if status.State == hive.CIStateFailure {
+		return fmt.Errorf("ci failed on %s: %s", run.Topic, status.Message.Payload)
+	}
+	if !asJSON {
+		p.Success("CI passed", status.Message.Payload)
+	}
+	return nil
+}
+
+// sessionRef returns the session given as the argument, else the session of
+// the working directory, else one picked interactively.
+func (cmd *CICmd) sessionRef(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 {
+		if id, err := cmd.flags.SessionDetector().DetectSession(ctx); err == nil && id != "" {
+			return id, nil
+		}
+	}
+	return sessionArg(ctx, cmd.flags, args, "session required\n\nUsage: hive ci [session-id|name]", isActive)
+}
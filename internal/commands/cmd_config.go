@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/pkg/textdiff"
+	"github.com/urfave/cli/v3"
+)
+
+type ConfigCmd struct {
+	flags *Flags
+
+	// migrate flags
+	dryRun   bool
+	noBackup bool
+}
+
+// NewConfigCmd creates a new config command.
+func NewConfigCmd(flags *Flags) *ConfigCmd {
+	return &ConfigCmd{flags: flags}
+}
+
+// Register adds the config command to the application.
+func (cmd *ConfigCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "config",
+		Usage: "Manage the hive configuration file",
+		Description: `Config commands operate on the configuration file directly.
+
+Use 'hive config migrate' to upgrade an older config to the latest version.`,
+		Commands: []*cli.Command{
+			cmd.migrateCmd(),
+		},
+	})
+
+	return app
+}
+
+func (cmd *ConfigCmd) migrateCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Apply mechanical migrations to the config file",
+		Description: `Rewrites the config file to the latest schema version.
+
+Applies the known mechanical migrations:
+  - moves spawn commands that use {{.Prompt}} to batch_spawn
+  - adds or updates the version field
+
+A diff of the changes is shown before writing. The original file is
+saved next to it as <config>.<timestamp>.bak unless --no-backup is set.
+Changes that require judgement are listed but not applied; see
+'hive doc migrate' for the full guide.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "show the diff without writing changes",
+				Destination: &cmd.dryRun,
+			},
+			&cli.BoolFlag{
+				Name:        "no-backup",
+				Usage:       "do not write a backup of the original file",
+				Destination: &cmd.noBackup,
+			},
+		},
+		Action: cmd.runMigrate,
+	}
+}
+
+func (cmd *ConfigCmd) runMigrate(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+	path := cmd.flags.ConfigPath
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("config file not found: %s", path)
+		}
+		return fmt.Errorf("stat config file: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	res, err := config.Migrate(data)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range res.Manual {
+		p.Warnf("manual action required: %s", msg)
+	}
+	if len(res.Manual) > 0 {
+		p.Warnf("version not updated; run 'hive config migrate' again after the manual actions")
+	}
+
+	if !res.Changed() {
+		if len(res.Manual) > 0 {
+			return nil
+		}
+		p.Successf("Config is up to date (version %s)", config.CurrentConfigVersion)
+		return nil
+	}
+
+	w := c.Root().Writer
+	_, _ = fmt.Fprintf(w, "--- %s\n+++ %s (migrated)\n", path, path)
+	_, _ = fmt.Fprint(w, textdiff.Unified(string(res.Original), string(res.Migrated), 3))
+
+	for _, msg := range res.Applied {
+		p.Infof("%s", msg)
+	}
+
+	if cmd.dryRun {
+		p.Infof("Dry run: no changes written")
+		return nil
+	}
+
+	if !cmd.noBackup {
+		backup := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backup, res.Original, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("write backup: %w", err)
+		}
+		p.Infof("Backup written to %s", backup)
+	}
+
+	if err := os.WriteFile(path, res.Migrated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write config file: %w", err)
+	}
+
+	p.Successf("Migrated %s to version %s", path, config.CurrentConfigVersion)
+	return nil
+}
@@ -8,7 +8,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/hay-kot/hive/internal/core/git"
 	"github.com/hay-kot/hive/internal/printer"
 	"github.com/urfave/cli/v3"
 )
@@ -100,35 +99,37 @@ func (cmd *CtxCmd) runInit(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
-	// Create the context directory if it doesn't exist
-	if err := os.MkdirAll(ctxDir, 0o755); err != nil {
-		return fmt.Errorf("create context directory: %w", err)
+	link, err := cmd.flags.Service.InitContext(".", ctxDir)
+	if err != nil {
+		return err
 	}
 
-	symlinkName := cmd.flags.Config.Context.SymlinkName
-	symlinkPath := filepath.Join(".", symlinkName)
-
-	// Check if symlink already exists
-	if info, err := os.Lstat(symlinkPath); err == nil {
-		if info.Mode()&os.ModeSymlink != 0 {
-			target, _ := os.Readlink(symlinkPath)
-			if target == ctxDir {
-				p.Infof("Symlink already exists: %s -> %s", symlinkName, ctxDir)
-				return nil
-			}
-			return fmt.Errorf("symlink %s exists but points to %s, not %s", symlinkName, target, ctxDir)
-		}
-		return fmt.Errorf("%s already exists and is not a symlink", symlinkName)
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, ctxInitOutput{Symlink: link.Symlink, Target: link.Target, Created: link.Created})
 	}
 
-	if err := os.Symlink(ctxDir, symlinkPath); err != nil {
-		return fmt.Errorf("create symlink: %w", err)
+	if !link.Created {
+		p.Infof("Symlink already exists: %s -> %s", link.Symlink, link.Target)
+		return nil
 	}
-
-	p.Successf("Created symlink: %s -> %s", symlinkName, ctxDir)
+	p.Successf("Created symlink: %s -> %s", link.Symlink, link.Target)
 	return nil
 }
 
+// ctxInitOutput is the JSON output format for hive ctx init.
+type ctxInitOutput struct {
+	Symlink string `json:"symlink"`
+	Target  string `json:"target"`
+	Created bool   `json:"created"` // false if the symlink already existed
+}
+
+// ctxPruneOutput is the JSON output format for hive ctx prune.
+type ctxPruneOutput struct {
+	Dir       string `json:"dir"`
+	OlderThan string `json:"older_than"`
+	Removed   int    `json:"removed"`
+}
+
 func (cmd *CtxCmd) runPrune(ctx context.Context, c *cli.Command) error {
 	p := printer.Ctx(ctx)
 
@@ -148,6 +149,9 @@ func (cmd *CtxCmd) runPrune(ctx context.Context, c *cli.Command) error {
 	entries, err := os.ReadDir(ctxDir)
 	if err != nil {
 		if os.IsNotExist(err) {
+			if p.IsJSON() {
+				return printer.EncodeJSON(c.Root().Writer, ctxPruneOutput{Dir: ctxDir, OlderThan: cmd.olderThan})
+			}
 			p.Infof("Context directory does not exist")
 			return nil
 		}
@@ -170,6 +174,10 @@ func (cmd *CtxCmd) runPrune(ctx context.Context, c *cli.Command) error {
 		}
 	}
 
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, ctxPruneOutput{Dir: ctxDir, OlderThan: cmd.olderThan, Removed: count})
+	}
+
 	p.Successf("Removed %d file(s) older than %s", count, cmd.olderThan)
 	return nil
 }
@@ -188,17 +196,7 @@ func (cmd *CtxCmd) resolveContextDir(ctx context.Context) (string, error) {
 	}
 
 	// Detect from current directory
-	remote, err := cmd.flags.Service.DetectRemote(ctx, ".")
-	if err != nil {
-		return "", fmt.Errorf("detect remote (are you in a git repository?): %w", err)
-	}
-
-	owner, repo := git.ExtractOwnerRepo(remote)
-	if owner == "" || repo == "" {
-		return "", fmt.Errorf("could not extract owner/repo from remote: %s", remote)
-	}
-
-	return cmd.flags.Config.RepoContextDir(owner, repo), nil
+	return cmd.flags.Service.RepoContextDir(ctx, ".")
 }
 
 func parseDuration(s string) (time.Duration, error) {
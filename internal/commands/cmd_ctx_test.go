@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestCtxPrune_JSON(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir()}
+	dir := cfg.RepoContextDir("hay-kot", "hive")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	old := filepath.Join(dir, "old.md")
+	require.NoError(t, os.WriteFile(old, []byte("x"), 0o644))
+	require.NoError(t, os.Chtimes(old, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.md"), []byte("x"), 0o644))
+
+	p := printer.New(io.Discard)
+	p.SetFormat(printer.FormatJSON)
+	ctx := printer.NewContext(context.Background(), p)
+
+	var buf bytes.Buffer
+	app := NewCtxCmd(&Flags{Config: cfg}).Register(&cli.Command{Name: "hive", Writer: &buf})
+	require.NoError(t, app.Run(ctx, []string{"hive", "ctx", "--repo", "hay-kot/hive", "prune", "--older-than", "1d"}))
+
+	assert.JSONEq(t, `{"dir":"`+dir+`","older_than":"1d","removed":1}`, buf.String())
+}
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+type DeleteCmd struct {
+	flags *Flags
+
+	// flags
+	force bool
+}
+
+// NewDeleteCmd creates a new delete command
+func NewDeleteCmd(flags *Flags) *DeleteCmd {
+	return &DeleteCmd{flags: flags}
+}
+
+// Register adds the delete command to the application
+func (cmd *DeleteCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "delete",
+		Aliases:   []string{"rm"},
+		Usage:     "Delete sessions and their directories",
+		ArgsUsage: "<session-id|name>...",
+		Description: `Deletes one or more sessions, given by ID or name: runs pre_delete hooks,
+moves the session directory to the trash, and drops the session record.
+'hive undelete <id>' restores a session until trash.retention passes.
+
+Active sessions with uncommitted changes are refused, and the sessions to
+delete are listed for confirmation. Use --force to skip both checks, which is
+required when stdin is not a terminal. Without a session, pick one
+interactively.
+
+Example:
+  hive delete abc123
+  hive delete fix-auth old-spike --force`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "force",
+				Aliases:     []string{"f"},
+				Usage:       "skip the uncommitted changes check and confirmation",
+				Destination: &cmd.force,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *DeleteCmd) run(ctx context.Context, c *cli.Command) error {
+	refs := c.Args().Slice()
+	if len(refs) == 0 {
+		ref, err := sessionArg(ctx, cmd.flags, nil, "session ID required\n\nUsage: hive delete <session-id|name>...", nil)
+		if err != nil {
+			return err
+		}
+		refs = []string{ref}
+	}
+
+	targets, err := cmd.resolve(ctx, refs)
+	if err != nil {
+		return err
+	}
+
+	p := printer.Ctx(ctx)
+	if !cmd.force {
+		if err := cmd.checkClean(ctx, targets); err != nil {
+			return err
+		}
+
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("confirmation required but stdin is not a terminal; use --force")
+		}
+		for _, s := range targets {
+			p.Printf("  %s  %s  %s", s.ID, s.Name, s.Path)
+		}
+		if !confirm(ctx, fmt.Sprintf("Delete %d session(s)?", len(targets))) {
+			return fmt.Errorf("aborted")
+		}
+	}
+
+	results := make([]deleteResult, 0, len(targets))
+	var failed int
+	for _, s := range targets {
+		result := deleteResult{ID: s.ID, Name: s.Name, Path: s.Path, Deleted: true}
+		if err := cmd.flags.Service.DeleteSession(ctx, s.ID); err != nil {
+			result.Deleted = false
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if p.IsJSON() {
+		if err := printer.EncodeJSON(c.Root().Writer, deleteOutput{Sessions: results}); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			if r.Deleted {
+				p.Success("Deleted session "+r.Name, r.Path)
+			} else {
+				p.Errorf("Failed to delete %s: %s", r.ID, r.Error)
+			}
+		}
+		if trashed(targets, results) && cmd.flags.Config.Trash.RetentionPeriod() > 0 {
+			p.Infof("Run 'hive undelete <id>' to restore a deleted session")
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to delete %d of %d session(s)", failed, len(targets))
+	}
+	return nil
+}
+
+// trashed reports whether any deleted session went to the trash; recycled
+// and review sessions are removed outright.
+func trashed(targets []session.Session, results []deleteResult) bool {
+	for i, r := range results {
+		if r.Deleted && targets[i].State != session.StateRecycled && !targets[i].IsReview() {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve looks up each reference, dropping duplicates.
+func (cmd *DeleteCmd) resolve(ctx context.Context, refs []string) ([]session.Session, error) {
+	var (
+		targets []session.Session
+		seen    = make(map[string]bool, len(refs))
+	)
+	for _, ref := range refs {
+		s, err := cmd.flags.Service.ResolveSession(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("get session: %w", err)
+		}
+		if !seen[s.ID] {
+			seen[s.ID] = true
+			targets = append(targets, s)
+		}
+	}
+	return targets, nil
+}
+
+// checkClean returns an error naming every active session with uncommitted
+// changes. Sessions whose directory is missing or not a git repository are
+// not checked.
+func (cmd *DeleteCmd) checkClean(ctx context.Context, targets []session.Session) error {
+	var dirty []string
+	for _, s := range targets {
+		if s.State != session.StateActive || s.IsReview() {
+			continue
+		}
+		if _, err := os.Stat(s.Path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		clean, err := cmd.flags.Service.Git().IsClean(ctx, s.Path)
+		if err == nil && !clean {
+			dirty = append(dirty, fmt.Sprintf("%s (%s)", s.ID, s.Name))
+		}
+	}
+
+	if len(dirty) > 0 {
+		return fmt.Errorf("uncommitted changes in %s; commit them or use --force", strings.Join(dirty, ", "))
+	}
+	return nil
+}
+
+// deleteResult is the outcome for one session in hive delete's JSON output.
+type deleteResult struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Deleted bool   `json:"deleted"`
+	Error   string `json:"error,omitempty"`
+}
+
+// deleteOutput is the JSON output format for hive delete.
+type deleteOutput struct {
+	Sessions []deleteResult `json:"sessions"`
+}
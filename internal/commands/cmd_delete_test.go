@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestDelete(t *testing.T) {
+	alpha, bravo, keep := t.TempDir(), t.TempDir(), t.TempDir()
+	flags := newServiceFlags(t, []session.Session{
+		{ID: "a1", Name: "alpha", Slug: "alpha", Path: alpha, State: session.StateActive},
+		{ID: "b2", Name: "bravo", Slug: "bravo", Path: bravo, State: session.StateRecycled},
+		{ID: "c3", Name: "keep", Slug: "keep", Path: keep, State: session.StateActive},
+	})
+
+	p := printer.New(io.Discard)
+	p.SetFormat(printer.FormatJSON)
+	ctx := printer.NewContext(context.Background(), p)
+
+	// Without --force, confirmation is required and tests have no terminal
+	app := NewDeleteCmd(flags).Register(&cli.Command{Name: "hive", Writer: io.Discard})
+	require.ErrorContains(t, app.Run(ctx, []string{"hive", "delete", "a1"}), "use --force")
+	assert.DirExists(t, alpha)
+
+	var buf bytes.Buffer
+	app = NewDeleteCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+	require.NoError(t, app.Run(ctx, []string{"hive", "delete", "--force", "alpha", "b2", "a1"}))
+
+	assert.JSONEq(t, `{"sessions":[
+		{"id":"a1","name":"alpha","path":"`+alpha+`","deleted":true},
+		{"id":"b2","name":"bravo","path":"`+bravo+`","deleted":true}
+	]}`, buf.String())
+	assert.NoDirExists(t, alpha)
+	assert.NoDirExists(t, bravo)
+
+	sessions, err := flags.Service.ListSessions(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "c3", sessions[0].ID)
+}
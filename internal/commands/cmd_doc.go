@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strconv"
 	"strings"
 
 	"github.com/hay-kot/hive/internal/core/config"
@@ -43,7 +42,9 @@ func (cmd *DocCmd) migrateCmd() *cli.Command {
 		Description: `Outputs migration information for config changes between versions.
 
 By default, only shows migrations needed for your current config version.
-Use --all to show all migrations.`,
+Use --all to show all migrations.
+
+To apply mechanical migrations to your config file, use 'hive config migrate'.`,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:        "all",
@@ -272,7 +273,7 @@ func printMigrationGuide(w io.Writer, configVersion string, showAll bool) {
 	_, _ = fmt.Fprintf(w, "**Latest version:** %s\n", config.CurrentConfigVersion)
 	_, _ = fmt.Fprintln(w)
 
-	if !showAll && configVersion != "" && compareVersions(configVersion, config.CurrentConfigVersion) >= 0 {
+	if !showAll && configVersion != "" && config.CompareVersions(configVersion, config.CurrentConfigVersion) >= 0 {
 		_, _ = fmt.Fprintln(w, "Your config is up to date. No migrations needed.")
 		_, _ = fmt.Fprintln(w)
 		_, _ = fmt.Fprintln(w, "Use --all to see all migrations.")
@@ -284,7 +285,7 @@ func printMigrationGuide(w io.Writer, configVersion string, showAll bool) {
 	if !showAll && configVersion != "" {
 		filtered = nil
 		for _, m := range migrations {
-			if compareVersions(m.Version, configVersion) > 0 {
+			if config.CompareVersions(m.Version, configVersion) > 0 {
 				filtered = append(filtered, m)
 			}
 		}
@@ -347,34 +348,6 @@ func printMigrationGuide(w io.Writer, configVersion string, showAll bool) {
 	if !showAll && configVersion != "" {
 		_, _ = fmt.Fprintln(w, "---")
 		_, _ = fmt.Fprintf(w, "After migrating, update your config version to: %s\n", config.CurrentConfigVersion)
+		_, _ = fmt.Fprintln(w, "Run 'hive config migrate' to apply mechanical migrations automatically.")
 	}
 }
-
-// compareVersions compares two semantic versions.
-// Returns -1 if a < b, 0 if a == b, 1 if a > b.
-func compareVersions(a, b string) int {
-	aParts := parseVersion(a)
-	bParts := parseVersion(b)
-
-	for i := 0; i < 3; i++ {
-		if aParts[i] < bParts[i] {
-			return -1
-		}
-		if aParts[i] > bParts[i] {
-			return 1
-		}
-	}
-	return 0
-}
-
-// parseVersion extracts major, minor, patch from a version string.
-// Returns [0,0,0] for invalid versions.
-func parseVersion(v string) [3]int {
-	var parts [3]int
-	segments := strings.Split(v, ".")
-	for i := 0; i < len(segments) && i < 3; i++ {
-		n, _ := strconv.Atoi(segments[i])
-		parts[i] = n
-	}
-	return parts
-}
@@ -1,18 +1,24 @@
 package commands
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/hay-kot/hive/internal/commands/doctor"
 	"github.com/hay-kot/hive/internal/printer"
 	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
 )
 
 type DoctorCmd struct {
 	flags   *Flags
 	format  string
 	autofix bool
+	data    bool
 }
 
 func NewDoctorCmd(flags *Flags) *DoctorCmd {
@@ -21,10 +27,22 @@ func NewDoctorCmd(flags *Flags) *DoctorCmd {
 
 func (cmd *DoctorCmd) Register(app *cli.Command) *cli.Command {
 	app.Commands = append(app.Commands, &cli.Command{
-		Name:        "doctor",
-		Usage:       "Run health checks on your hive setup",
-		UsageText:   "hive doctor [options]",
-		Description: "Runs diagnostic checks on configuration, environment, and dependencies.",
+		Name:      "doctor",
+		Usage:     "Run health checks on your hive setup",
+		UsageText: "hive doctor [options]",
+		Description: `Runs diagnostic checks on configuration, environment, and dependencies.
+
+Checks include config validation, terminal integrations and spawn programs
+on PATH (commands are rendered but not executed), data directory
+writability, clock skew, stale message lock files, and orphaned worktrees.
+
+With --data, doctor also checks that sessions.json, history.json, every
+message topic, and every batch state file parse. Fixing moves a corrupt file
+aside with a .corrupt suffix and restores the newest good copy from
+'hive backup', if there is one.
+
+When run interactively with fixable issues, doctor offers to fix them.
+Use --format json for machine-readable output.`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "format",
@@ -37,6 +55,11 @@ func (cmd *DoctorCmd) Register(app *cli.Command) *cli.Command {
 				Usage:       "automatically fix issues (e.g., delete orphaned worktrees)",
 				Destination: &cmd.autofix,
 			},
+			&cli.BoolFlag{
+				Name:        "data",
+				Usage:       "check that data files parse, quarantining corrupt ones when fixing",
+				Destination: &cmd.data,
+			},
 		},
 		Action: cmd.run,
 	})
@@ -44,18 +67,52 @@ func (cmd *DoctorCmd) Register(app *cli.Command) *cli.Command {
 }
 
 func (cmd *DoctorCmd) run(ctx context.Context, c *cli.Command) error {
-	checks := []doctor.Check{
-		doctor.NewConfigCheck(cmd.flags.Config, cmd.flags.ConfigPath),
-		doctor.NewOrphanCheck(cmd.flags.Store, cmd.flags.Config.ReposDir(), cmd.autofix),
+	results := doctor.RunAll(ctx, cmd.checks(cmd.autofix))
+
+	if cmd.format == "json" || printer.Ctx(ctx).IsJSON() {
+		return cmd.outputJSON(c, results)
 	}
 
-	results := doctor.RunAll(ctx, checks)
+	// Offer to fix issues when a user is at the terminal
+	if !cmd.autofix && doctor.CountFixable(results) > 0 && term.IsTerminal(int(os.Stdin.Fd())) {
+		cmd.outputResults(ctx, results)
+		if !confirm(ctx, "Fix these issues now?") {
+			return cmd.outputSummary(ctx, results)
+		}
+		cmd.autofix = true
+		results = doctor.RunAll(ctx, cmd.checks(true))
+	}
 
-	if cmd.format == "json" {
-		return cmd.outputJSON(c, results)
+	cmd.outputResults(ctx, results)
+	return cmd.outputSummary(ctx, results)
+}
+
+func (cmd *DoctorCmd) checks(fix bool) []doctor.Check {
+	cfg := cmd.flags.Config
+	checks := []doctor.Check{
+		doctor.NewConfigCheck(cfg, cmd.flags.ConfigPath),
+		doctor.NewEnvironmentCheck(cfg),
+		doctor.NewDataDirCheck(cfg.DataDir),
 	}
 
-	return cmd.outputText(ctx, results)
+	// Integrity runs first so a repaired sessions file is what the orphan
+	// check reads
+	if cmd.data {
+		checks = append(checks, doctor.NewIntegrityCheck(cfg.DataDir, cmd.flags.Service.Backups(), fix))
+	}
+
+	return append(checks,
+		doctor.NewLockCheck(filepath.Join(cfg.DataDir, "messages", "topics"), fix),
+		doctor.NewOrphanCheck(cmd.flags.Store, cfg.ReposDir(), fix),
+	)
+}
+
+// confirm prompts on stderr and reads a yes/no answer from stdin.
+func confirm(ctx context.Context, question string) bool {
+	printer.Ctx(ctx).Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
 
 func (cmd *DoctorCmd) outputJSON(c *cli.Command, results []doctor.Result) error {
@@ -82,7 +139,7 @@ type summaryJSON struct {
 	Failed int `json:"failed"`
 }
 
-func (cmd *DoctorCmd) outputText(ctx context.Context, results []doctor.Result) error {
+func (cmd *DoctorCmd) outputResults(ctx context.Context, results []doctor.Result) {
 	p := printer.Ctx(ctx)
 
 	for _, result := range results {
@@ -101,6 +158,10 @@ func (cmd *DoctorCmd) outputText(ctx context.Context, results []doctor.Result) e
 
 		p.Printf("")
 	}
+}
+
+func (cmd *DoctorCmd) outputSummary(ctx context.Context, results []doctor.Result) error {
+	p := printer.Ctx(ctx)
 
 	passed, warned, failed := doctor.Summary(results)
 	p.Printf("Summary: %d passed, %d warnings, %d failed", passed, warned, failed)
@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/events"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type EventsCmd struct {
+	flags *Flags
+
+	// flags
+	follow  bool
+	since   string
+	types   []string
+	session string
+}
+
+// NewEventsCmd creates a new events command
+func NewEventsCmd(flags *Flags) *EventsCmd {
+	return &EventsCmd{flags: flags}
+}
+
+// Register adds the events command to the application
+func (cmd *EventsCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "events",
+		Usage: "Show the event log",
+		Description: `Prints events recorded in the event log (events.ndjson in the data
+directory): sessions created, recycled, deleted, or marked corrupted,
+terminal status changes seen by the TUI, published messages, and prune
+and gc runs.
+
+Use --follow to keep printing events as they are appended. With --json,
+each event is written as one JSON object per line.
+
+Example:
+  hive events --since 1h
+  hive events --follow --type 'session.*'
+  hive events --session abc123 --json`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "follow",
+				Aliases:     []string{"f"},
+				Usage:       "keep printing new events until interrupted",
+				Destination: &cmd.follow,
+			},
+			&cli.StringFlag{
+				Name:        "since",
+				Usage:       "only show events newer than a duration (e.g. 30m, 2d) or RFC3339 time",
+				Destination: &cmd.since,
+			},
+			&cli.StringSliceFlag{
+				Name:        "type",
+				Aliases:     []string{"t"},
+				Usage:       "only show event types matching a glob (e.g. session.*); repeatable",
+				Destination: &cmd.types,
+			},
+			&cli.StringFlag{
+				Name:        "session",
+				Usage:       "only show events for a session ID",
+				Destination: &cmd.session,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *EventsCmd) run(ctx context.Context, c *cli.Command) error {
+	filter := events.Filter{Types: cmd.types, SessionID: cmd.session}
+	if cmd.since != "" {
+		since, err := parseSince(cmd.since, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = since
+	}
+
+	out := c.Root().Writer
+	write := func(e events.Event) error {
+		if printer.Ctx(ctx).IsJSON() {
+			return printer.EncodeJSON(out, e)
+		}
+		writeEvent(out, e)
+		return nil
+	}
+
+	log := cmd.flags.Service.Events()
+	if cmd.follow {
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return log.Follow(ctx, filter, write)
+	}
+
+	list, err := log.Read(filter)
+	if err != nil {
+		return err
+	}
+	for _, e := range list {
+		if err := write(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseSince accepts a duration before now (including the "d" suffix) or an
+// RFC3339 timestamp.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: expected a duration like 1h or an RFC3339 time", s)
+	}
+	return t, nil
+}
+
+// writeEvent prints e as one line: time, type, session, then data as sorted
+// key=value pairs.
+func writeEvent(w io.Writer, e events.Event) {
+	session := e.SessionID
+	if session == "" {
+		session = "-"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %-18s %s", e.Time.Local().Format(time.DateTime), e.Type, session)
+	for _, k := range slices.Sorted(maps.Keys(e.Data)) {
+		fmt.Fprintf(&b, " %s=%v", k, e.Data[k])
+	}
+	_, _ = fmt.Fprintln(w, b.String())
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/urfave/cli/v3"
+)
+
+type ExecCmd struct {
+	flags *Flags
+}
+
+// NewExecCmd creates a new exec command
+func NewExecCmd(flags *Flags) *ExecCmd {
+	return &ExecCmd{flags: flags}
+}
+
+// Register adds the exec command to the application
+func (cmd *ExecCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "exec",
+		Usage:     "Run a command inside a session directory",
+		ArgsUsage: "<session-id|name> -- <command> [args...]",
+		Description: `Runs a command with its working directory set to an active session's path
+and streams its output. The session can be given by ID or by name.
+
+The configured env and the session's HIVE_SESSION_ID, HIVE_PATH, HIVE_REMOTE,
+and HIVE_PROMPT variables are exported to the command. hive exits with the
+command's exit code.
+
+Example:
+  hive exec abc123 -- go test ./...
+  hive exec fix-auth -- git status --short`,
+		// Everything after the session is the command, even without "--"
+		StopOnNthArg: &execStopArg,
+		Action:       cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *ExecCmd) run(ctx context.Context, c *cli.Command) error {
+	if c.NArg() < 2 {
+		return fmt.Errorf("session and command required\n\nUsage: hive exec <session-id|name> -- <command> [args...]")
+	}
+	args := c.Args().Slice()
+	if args[1] == "--" {
+		args = append(args[:1], args[2:]...)
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("command required\n\nUsage: hive exec <session-id|name> -- <command> [args...]")
+	}
+
+	err := cmd.flags.Service.ExecSession(ctx, args[0], c.Root().Writer, c.Root().ErrWriter, args[1], args[2:]...)
+
+	// The command's own output explains the failure; just pass on its exit code
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return cli.Exit("", exitErr.ExitCode())
+	}
+	return err
+}
+
+// execStopArg stops flag parsing after the session argument.
+var execStopArg = 1
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type GCCmd struct {
+	flags *Flags
+
+	// flags
+	remove bool
+	minAge string
+}
+
+// NewGCCmd creates a new gc command
+func NewGCCmd(flags *Flags) *GCCmd {
+	return &GCCmd{flags: flags}
+}
+
+// Register adds the gc command to the application
+func (cmd *GCCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "gc",
+		Usage:     "Find directories and session records that have lost their counterpart",
+		UsageText: "hive gc [--remove] [--min-age 1h]",
+		Description: `Reconciles the session store against the repos directory and reports:
+
+  directory  a directory in the repos directory with no session record, e.g.
+             left by a crash mid-create or a record deleted outside hive
+  record     a session whose directory was removed outside hive
+
+Nothing is changed unless --remove is given, which deletes orphaned
+directories and drops orphaned records from the store. Hooks do not run.
+
+Directories modified within --min-age (default 1h) are skipped so sessions
+being created right now are not mistaken for orphans.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "remove",
+				Usage:       "delete orphaned directories and drop orphaned records",
+				Destination: &cmd.remove,
+			},
+			&cli.StringFlag{
+				Name:        "min-age",
+				Usage:       "skip directories modified more recently than this (e.g. 30m, 1d)",
+				Value:       "1h",
+				Destination: &cmd.minAge,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *GCCmd) run(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	minAge, err := parseDuration(cmd.minAge)
+	if err != nil {
+		return fmt.Errorf("invalid --min-age: %w", err)
+	}
+
+	result, err := cmd.flags.Service.GC(ctx, hive.GCOptions{Remove: cmd.remove, MinAge: minAge})
+	if err != nil {
+		return fmt.Errorf("gc: %w", err)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, result)
+	}
+
+	if result.TrashExpired > 0 {
+		p.Infof("Removed %d expired session(s) from trash", result.TrashExpired)
+	}
+
+	if len(result.Orphans) == 0 {
+		p.Infof("No orphans found")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tSESSION\tSIZE\tSTATUS\tPATH")
+	for _, o := range result.Orphans {
+		id, size := o.SessionID, formatBytes(o.Bytes)
+		if id == "" {
+			id = "-"
+		}
+		if o.Kind == hive.OrphanRecord {
+			size = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", o.Kind, id, size, gcStatus(o), o.Path)
+	}
+	_ = w.Flush()
+
+	if !cmd.remove {
+		p.Infof("Found %d orphan(s) using %s. Run 'hive gc --remove' to clean up", len(result.Orphans), formatBytes(result.Reclaimed))
+		return nil
+	}
+
+	removed := 0
+	for _, o := range result.Orphans {
+		if o.Removed {
+			removed++
+		}
+	}
+	if failed := len(result.Orphans) - removed; failed > 0 {
+		p.Warnf("Failed to remove %d orphan(s)", failed)
+	}
+	p.Successf("Removed %d orphan(s), freed %s", removed, formatBytes(result.Reclaimed))
+	return nil
+}
+
+// gcStatus describes what happened to an orphan.
+func gcStatus(o hive.Orphan) string {
+	switch {
+	case o.Error != "":
+		return "failed: " + o.Error
+	case o.Removed:
+		return "removed"
+	default:
+		return "found"
+	}
+}
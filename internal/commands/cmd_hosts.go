@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type HostsCmd struct {
+	flags *Flags
+}
+
+// NewHostsCmd creates a new hosts command
+func NewHostsCmd(flags *Flags) *HostsCmd {
+	return &HostsCmd{flags: flags}
+}
+
+// Register adds the hosts command to the application
+func (cmd *HostsCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "hosts",
+		Usage: "Check the remote hosts configured under hosts",
+		Description: `Connects to each host configured under hosts over SSH and reports how
+many sessions it has, or why it could not be reached.
+
+Hosts are used by 'hive ls --all-hosts', the TUI, and 'hive msg --host',
+and receive messages on their relay topics.
+
+Example config:
+  hosts:
+    server:
+      ssh: me@server
+      relay: [handoff.*]`,
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+// hostStatus is the JSON output of hive hosts.
+type hostStatus struct {
+	Name     string   `json:"name"`
+	SSH      string   `json:"ssh"`
+	Relay    []string `json:"relay,omitempty"`
+	Sessions int      `json:"sessions"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func (cmd *HostsCmd) run(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	results := cmd.flags.Service.RemoteSessions(ctx)
+	statuses := make([]hostStatus, 0, len(results))
+	for _, r := range results {
+		h := cmd.flags.Config.Hosts[r.Host]
+		st := hostStatus{Name: r.Host, SSH: h.SSH, Relay: h.Relay, Sessions: len(r.Sessions)}
+		if r.Err != nil {
+			st.Error = r.Err.Error()
+		}
+		statuses = append(statuses, st)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, statuses)
+	}
+
+	if len(statuses) == 0 {
+		p.Infof("No hosts configured")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSSH\tRELAY\tSTATUS")
+	for _, st := range statuses {
+		status := fmt.Sprintf("%d session(s)", st.Sessions)
+		if st.Error != "" {
+			status = "unreachable: " + st.Error
+		}
+		relay := strings.Join(st.Relay, ",")
+		if relay == "" {
+			relay = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.Name, st.SSH, relay, status)
+	}
+	return w.Flush()
+}
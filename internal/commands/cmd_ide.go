@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type IDECmd struct {
+	flags *Flags
+
+	// flags
+	remote     string
+	output     string
+	open       bool
+	jsonOutput bool
+}
+
+// NewIDECmd creates a new ide command.
+func NewIDECmd(flags *Flags) *IDECmd {
+	return &IDECmd{flags: flags}
+}
+
+// Register adds the ide command to the application.
+func (cmd *IDECmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "ide",
+		Usage: "Generate editor workspaces for a repository's sessions",
+		Commands: []*cli.Command{
+			{
+				Name:      "vscode",
+				Usage:     "Write a VS Code workspace with every active session of a repository",
+				UsageText: "hive ide vscode [-r remote] [-o path] [--open]",
+				Description: `Writes a .code-workspace file with a folder for the working directory of
+each active session of the repository, named after the session, so the
+checkouts of every agent open in one window.
+
+The file is written to workspaces/<owner>/<repo>.code-workspace in the data
+directory unless -o is given, and -o - prints it instead. Run the command
+again after creating or recycling sessions; VS Code reloads the folders of
+an open workspace when the file changes.
+
+Examples:
+  hive ide vscode --open
+  hive ide vscode -r git@github.com:hay-kot/hive.git -o hive.code-workspace
+  hive ide vscode -o -`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:        "remote",
+						Aliases:     []string{"r"},
+						Usage:       "git remote URL or local repository path (defaults to current directory's origin)",
+						Destination: &cmd.remote,
+					},
+					&cli.StringFlag{
+						Name:        "output",
+						Aliases:     []string{"o"},
+						Usage:       "workspace file to write, or - for stdout",
+						Destination: &cmd.output,
+					},
+					&cli.BoolFlag{
+						Name:        "open",
+						Usage:       "open the workspace with code afterwards",
+						Destination: &cmd.open,
+					},
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "output as JSON",
+						Destination: &cmd.jsonOutput,
+					},
+				},
+				Action: cmd.runVSCode,
+			},
+		},
+	})
+	return app
+}
+
+// ideOutput is the JSON output format for hive ide vscode.
+type ideOutput struct {
+	Path    string                 `json:"path"`
+	Folders []hive.WorkspaceFolder `json:"folders"`
+}
+
+func (cmd *IDECmd) runVSCode(ctx context.Context, c *cli.Command) error {
+	if cmd.output == "-" && cmd.open {
+		return fmt.Errorf("--open cannot be used with -o -")
+	}
+
+	remote, err := cmd.flags.Service.ResolveRemote(ctx, cmd.remote)
+	if err != nil {
+		return err
+	}
+	ws, err := cmd.flags.Service.VSCodeWorkspace(ctx, remote)
+	if err != nil {
+		return err
+	}
+
+	if cmd.output == "-" {
+		data, err := ws.Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = c.Root().Writer.Write(data)
+		return err
+	}
+
+	path := cmd.output
+	if path == "" {
+		path = cmd.flags.Service.WorkspaceFile(remote)
+	}
+	if err := hive.WriteVSCodeWorkspace(path, ws); err != nil {
+		return err
+	}
+
+	if wantJSON(ctx, cmd.jsonOutput) {
+		if err := printer.EncodeJSON(c.Root().Writer, ideOutput{Path: path, Folders: ws.Folders}); err != nil {
+			return err
+		}
+	} else {
+		printer.Ctx(ctx).Success(fmt.Sprintf("Workspace written with %d sessions", len(ws.Folders)), path)
+	}
+
+	if !cmd.open {
+		return nil
+	}
+	code := exec.CommandContext(ctx, "code", path)
+	code.Stdout, code.Stderr = os.Stderr, os.Stderr
+	if err := code.Run(); err != nil {
+		return fmt.Errorf("open workspace: %w", err)
+	}
+	return nil
+}
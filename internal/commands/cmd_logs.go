@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type LogsCmd struct {
+	flags *Flags
+
+	// flags
+	source string
+}
+
+// NewLogsCmd creates a new logs command
+func NewLogsCmd(flags *Flags) *LogsCmd {
+	return &LogsCmd{flags: flags}
+}
+
+// Register adds the logs command to the application
+func (cmd *LogsCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "logs",
+		Usage:     "Show hook, recycle, and spawn output for a session",
+		ArgsUsage: "[session-id]",
+		Description: `Prints the output of commands hive ran for a session, with a header for
+each command recording when it started and how it ended.
+
+Sources:
+  hooks    - rule commands, lifecycle hooks, and background hooks
+  recycle  - recycle commands
+  spawn    - spawn and batch_spawn commands
+
+Without a session ID, the session is detected from the current directory.
+Logs are kept after a session is deleted.
+
+Example:
+  hive logs abc123
+  hive logs abc123 --source hooks`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "source",
+				Aliases:     []string{"s"},
+				Usage:       "log to show: " + strings.Join(hive.LogSources, ", ") + " (default: all)",
+				Destination: &cmd.source,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *LogsCmd) run(ctx context.Context, c *cli.Command) error {
+	sources := hive.LogSources
+	if cmd.source != "" {
+		if !slices.Contains(hive.LogSources, cmd.source) {
+			return fmt.Errorf("unknown source %q (expected %s)", cmd.source, strings.Join(hive.LogSources, ", "))
+		}
+		sources = []string{cmd.source}
+	}
+
+	id, err := cmd.sessionID(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	out := c.Root().Writer
+	found := 0
+	for _, source := range sources {
+		path := cmd.flags.Config.SessionLogFile(id, source)
+
+		f, err := os.Open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("open %s log: %w", source, err)
+		}
+
+		if len(sources) > 1 {
+			if found > 0 {
+				_, _ = fmt.Fprintln(out)
+			}
+			_, _ = fmt.Fprintf(out, "==> %s <==\n", path)
+		}
+		found++
+
+		_, err = io.Copy(out, f)
+		_ = f.Close()
+		if err != nil {
+			return fmt.Errorf("read %s log: %w", source, err)
+		}
+	}
+
+	if found == 0 {
+		printer.Ctx(ctx).Infof("No %s logs for session %s", strings.Join(sources, "/"), id)
+	}
+	return nil
+}
+
+// sessionID returns the session ID argument, or detects the session from
+// the working directory.
+func (cmd *LogsCmd) sessionID(ctx context.Context, c *cli.Command) (string, error) {
+	switch c.NArg() {
+	case 0:
+	case 1:
+		id := c.Args().First()
+		if strings.ContainsAny(id, `/\`) || id == ".." {
+			return "", fmt.Errorf("invalid session ID %q", id)
+		}
+		return id, nil
+	default:
+		return "", fmt.Errorf("expected at most one session ID\n\nUsage: hive logs [session-id]")
+	}
+
+	id, err := cmd.flags.SessionDetector().DetectSession(ctx)
+	if err != nil {
+		return "", fmt.Errorf("detect session: %w", err)
+	}
+	if id == "" {
+		return "", fmt.Errorf("not in a hive session; pass a session ID")
+	}
+	return id, nil
+}
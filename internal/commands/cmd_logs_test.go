@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func runLogsCmd(t *testing.T, flags *Flags, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	app := NewLogsCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+	err := app.Run(context.Background(), append([]string{"hive", "logs"}, args...))
+	return buf.String(), err
+}
+
+func TestLogsCmd(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir()}
+	flags := &Flags{Config: cfg}
+
+	write := func(source, content string) {
+		path := cfg.SessionLogFile("abc123", source)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("hooks", "=== hook npm install\n")
+	write("spawn", "=== spawn tmux\n")
+
+	out, err := runLogsCmd(t, flags, "abc123", "--source", "hooks")
+	require.NoError(t, err)
+	assert.Equal(t, "=== hook npm install\n", out)
+
+	out, err = runLogsCmd(t, flags, "abc123")
+	require.NoError(t, err)
+	assert.Contains(t, out, "hooks.log <==\n=== hook npm install")
+	assert.Contains(t, out, "spawn.log <==\n=== spawn tmux")
+	assert.NotContains(t, out, "recycle.log")
+
+	out, err = runLogsCmd(t, flags, "abc123", "--source", "recycle")
+	require.NoError(t, err)
+	assert.Empty(t, out)
+
+	_, err = runLogsCmd(t, flags, "abc123", "--source", "build")
+	require.ErrorContains(t, err, "unknown source")
+
+	_, err = runLogsCmd(t, flags, "../abc123")
+	require.ErrorContains(t, err, "invalid session ID")
+}
@@ -1,19 +1,26 @@
 package commands
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"path/filepath"
 	"slices"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/hay-kot/hive/internal/core/config"
 	"github.com/hay-kot/hive/internal/core/git"
 	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hosts"
 	"github.com/hay-kot/hive/internal/printer"
 	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/internal/tui"
 	"github.com/urfave/cli/v3"
 )
 
@@ -22,6 +29,18 @@ type LsCmd struct {
 
 	// flags
 	jsonOutput bool
+	state      string
+	repo       string
+	batch      string
+	sortBy     string
+	fields     string
+	allHosts   bool
+	tree       bool
+	ascii      bool
+	limit      int
+	offset     int
+	watch      bool
+	interval   string
 }
 
 // NewLsCmd creates a new ls command
@@ -34,16 +53,99 @@ func (cmd *LsCmd) Register(app *cli.Command) *cli.Command {
 	app.Commands = append(app.Commands, &cli.Command{
 		Name:      "ls",
 		Usage:     "List all sessions",
-		UsageText: "hive ls [--json]",
+		UsageText: "hive ls [--json] [--tree] [--state state] [--repo owner/name] [--batch id] [--sort key] [--fields list] [--limit n] [--offset n] [--all-hosts] [--watch [--interval 5s]]",
 		Description: `Displays a table of all sessions with their repo, name, state, and path.
 
-Use --json for LLM-friendly output with additional fields like inbox topic and unread count.`,
+Use --json for LLM-friendly output with additional fields like inbox topic and unread count.
+
+Filter with --state (active, recycled, corrupted), --repo (owner/name, or
+just name), and --batch (the ID printed by hive batch). Sort with --sort repo
+(default), name, or updated (most recent first). Select columns, or JSON keys,
+with --fields, e.g. --fields id,name,path.
+Page through long lists with --limit and --offset, applied after sorting.
+
+Use --all-hosts to also list sessions on the remote hosts configured under
+hosts, with a host column. Unreachable hosts are reported and skipped.
+
+Use --tree for the TUI's view as plain text: sessions grouped by repository
+with their terminal status, branch, and diff stats. Recycled sessions are
+collapsed into a count, and --sort, --fields, and paging do not apply.
+
+Use --watch to redraw the table, or the tree with --tree, every --interval
+(default 5s) until interrupted, e.g. in a tmux pane. Lines that changed since
+the previous refresh, such as a new state or unread count, are highlighted.
+The table adds an unread column unless --fields is given.
+
+Fields: ` + strings.Join(lsFieldNames, ", "),
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:        "json",
 				Usage:       "output as JSON lines with inbox info",
 				Destination: &cmd.jsonOutput,
 			},
+			&cli.StringFlag{
+				Name:        "state",
+				Usage:       "only show sessions in this state (active, recycled, corrupted)",
+				Destination: &cmd.state,
+			},
+			&cli.StringFlag{
+				Name:        "repo",
+				Usage:       "only show sessions for this repository (owner/name or name)",
+				Destination: &cmd.repo,
+			},
+			&cli.StringFlag{
+				Name:        "batch",
+				Usage:       "only show sessions created by this hive batch",
+				Destination: &cmd.batch,
+			},
+			&cli.StringFlag{
+				Name:        "sort",
+				Usage:       "sort by repo, name, or updated",
+				Value:       lsSortRepo,
+				Destination: &cmd.sortBy,
+			},
+			&cli.StringFlag{
+				Name:        "fields",
+				Usage:       "comma-separated fields to show (" + strings.Join(lsFieldNames, ", ") + ")",
+				Destination: &cmd.fields,
+			},
+			&cli.IntFlag{
+				Name:        "limit",
+				Usage:       "show at most this many sessions (0 for all)",
+				Destination: &cmd.limit,
+			},
+			&cli.IntFlag{
+				Name:        "offset",
+				Usage:       "skip this many sessions before listing",
+				Destination: &cmd.offset,
+			},
+			&cli.BoolFlag{
+				Name:        "tree",
+				Usage:       "show sessions as a tree grouped by repository, as in the TUI",
+				Destination: &cmd.tree,
+			},
+			&cli.BoolFlag{
+				Name:        "ascii",
+				Usage:       "draw --tree with ASCII glyphs",
+				Sources:     cli.EnvVars("HIVE_ASCII"),
+				Destination: &cmd.ascii,
+			},
+			&cli.BoolFlag{
+				Name:        "all-hosts",
+				Usage:       "include sessions on configured remote hosts",
+				Destination: &cmd.allHosts,
+			},
+			&cli.BoolFlag{
+				Name:        "watch",
+				Usage:       "redraw the list in place every --interval",
+				Destination: &cmd.watch,
+			},
+			&cli.StringFlag{
+				Name:        "interval",
+				Usage:       "refresh interval for --watch (e.g., 5s, 1m)",
+				Value:       "5s",
+				Destination: &cmd.interval,
+			},
 		},
 		Action: cmd.run,
 	})
@@ -52,45 +154,126 @@ Use --json for LLM-friendly output with additional fields like inbox topic and u
 }
 
 func (cmd *LsCmd) run(ctx context.Context, c *cli.Command) error {
+	fields, err := parseLsFields(cmd.fields)
+	if err != nil {
+		return err
+	}
+
+	sortBy := cmd.sortBy
+	if sortBy == "" {
+		sortBy = lsSortRepo
+	}
+	if !slices.Contains([]string{lsSortRepo, lsSortName, lsSortUpdated}, sortBy) {
+		return fmt.Errorf("unknown sort key %q (expected repo, name, or updated)", sortBy)
+	}
+
+	switch session.State(cmd.state) {
+	case "", session.StateActive, session.StateRecycled, session.StateCorrupted:
+	default:
+		return fmt.Errorf("unknown state %q (expected active, recycled, or corrupted)", cmd.state)
+	}
+	if cmd.limit < 0 || cmd.offset < 0 {
+		return fmt.Errorf("--limit and --offset must not be negative")
+	}
+	if cmd.tree && wantJSON(ctx, cmd.jsonOutput) {
+		return fmt.Errorf("--tree cannot be combined with JSON output")
+	}
+
+	if cmd.watch {
+		if wantJSON(ctx, cmd.jsonOutput) {
+			return fmt.Errorf("--watch cannot be combined with JSON output")
+		}
+		interval, err := time.ParseDuration(cmd.interval)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid interval %q", cmd.interval)
+		}
+		if fields == nil && !cmd.tree {
+			fields = append(slices.Clone(lsDefaultFields), "unread")
+			if cmd.allHosts {
+				fields = append([]string{"host"}, fields...)
+			}
+		}
+		return cmd.watchList(ctx, c.Root().Writer, sortBy, fields, interval)
+	}
+
+	return cmd.list(ctx, c.Root().Writer, sortBy, fields)
+}
+
+// list writes the sessions matching the flags to out, as a table, JSON
+// lines, or a tree. fields is nil for the default columns.
+func (cmd *LsCmd) list(ctx context.Context, out io.Writer, sortBy string, fields []string) error {
 	p := printer.Ctx(ctx)
 
-	sessions, err := cmd.flags.Service.ListSessions(ctx)
+	// The state filter is applied by the store, so large stores only decode
+	// the sessions asked for.
+	var filter session.Filter
+	if cmd.state != "" {
+		filter.States = []session.State{session.State(cmd.state)}
+	}
+	sessions, err := cmd.flags.Service.FindSessions(ctx, filter)
 	if err != nil {
 		return fmt.Errorf("list sessions: %w", err)
 	}
-
-	if len(sessions) == 0 {
-		if !cmd.jsonOutput {
-			p.Infof("No sessions found")
-		}
-		return nil
+	sessions = filterSessions(sessions, "", cmd.repo)
+	if cmd.batch != "" {
+		sessions = slices.DeleteFunc(sessions, func(s session.Session) bool { return s.BatchID() != cmd.batch })
 	}
 
-	// Separate normal and corrupted sessions
+	// Separate normal and corrupted sessions, unless corrupted ones were asked for
 	var normal, corrupted []session.Session
 	for _, s := range sessions {
-		if s.State == session.StateCorrupted {
+		if s.State == session.StateCorrupted && cmd.state == "" {
 			corrupted = append(corrupted, s)
 		} else {
 			normal = append(normal, s)
 		}
 	}
 
-	// Sort by repository name
-	slices.SortFunc(normal, func(a, b session.Session) int {
-		return strings.Compare(git.ExtractRepoName(a.Remote), git.ExtractRepoName(b.Remote))
-	})
+	if cmd.tree {
+		return cmd.printTree(ctx, out, normal)
+	}
 
-	out := c.Root().Writer
+	sortSessions(normal, sortBy)
+	rows := make([]lsRow, 0, len(normal))
+	for _, s := range normal {
+		rows = append(rows, lsRow{session: s})
+	}
+
+	if cmd.allHosts && cmd.batch == "" {
+		for _, result := range cmd.flags.Service.RemoteSessions(ctx) {
+			if result.Err != nil {
+				p.Warnf("%v", result.Err)
+				continue
+			}
+			remote := filterSessions(result.Sessions, session.State(cmd.state), cmd.repo)
+			sortSessions(remote, sortBy)
+			for _, s := range remote {
+				rows = append(rows, lsRow{host: result.Host, session: s})
+			}
+		}
+	}
+
+	rows = paginate(rows, cmd.offset, cmd.limit)
+
+	if len(rows) == 0 && len(corrupted) == 0 {
+		if !wantJSON(ctx, cmd.jsonOutput) {
+			p.Infof("No sessions found")
+		}
+		return nil
+	}
 
 	// JSON output mode
-	if cmd.jsonOutput {
+	if wantJSON(ctx, cmd.jsonOutput) {
 		msgStore := cmd.getMsgStore()
 		enc := json.NewEncoder(out)
 
-		for _, s := range normal {
-			info := cmd.buildSessionInfo(ctx, s, msgStore)
-			if err := enc.Encode(info); err != nil {
+		for _, row := range rows {
+			info := cmd.buildRowInfo(ctx, row, msgStore)
+			var v any = info
+			if fields != nil {
+				v = selectRowFields(fields, row, info)
+			}
+			if err := enc.Encode(v); err != nil {
 				return fmt.Errorf("encode session: %w", err)
 			}
 		}
@@ -98,13 +281,33 @@ func (cmd *LsCmd) run(ctx context.Context, c *cli.Command) error {
 	}
 
 	// Table output mode
-	if len(normal) > 0 {
+	if len(rows) > 0 {
+		if fields == nil {
+			fields = lsDefaultFields
+			if cmd.allHosts {
+				fields = append([]string{"host"}, fields...)
+			}
+		}
+
 		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
-		_, _ = fmt.Fprintln(w, "REPO\tNAME\tSTATE\tPATH")
+		_, _ = fmt.Fprintln(w, strings.ToUpper(strings.Join(fields, "\t")))
 
-		for _, s := range normal {
-			repo := git.ExtractRepoName(s.Remote)
-			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", repo, s.Name, s.State, s.Path)
+		var msgStore *jsonfile.MsgStore
+		if slices.Contains(fields, "unread") {
+			msgStore = cmd.getMsgStore()
+		}
+
+		for _, row := range rows {
+			var info sessionInfo
+			if msgStore != nil {
+				info = cmd.buildRowInfo(ctx, row, msgStore)
+			}
+			values := selectRowFields(fields, row, info)
+			cells := make([]string, len(fields))
+			for i, f := range fields {
+				cells[i] = formatLsValue(values[f])
+			}
+			_, _ = fmt.Fprintln(w, strings.Join(cells, "\t"))
 		}
 
 		_ = w.Flush()
@@ -124,6 +327,93 @@ func (cmd *LsCmd) run(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
+// watchList redraws the list every interval until ctx is cancelled. Each
+// frame is rendered in full before the screen is cleared, and lines not in
+// the previous frame are highlighted.
+func (cmd *LsCmd) watchList(ctx context.Context, out io.Writer, sortBy string, fields []string, interval time.Duration) error {
+	p := printer.Ctx(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var prev map[string]bool
+	for {
+		var frame bytes.Buffer
+		// Messages such as "No sessions found" belong to the frame
+		frameCtx := printer.NewContext(ctx, p.WithWriter(&frame))
+		if err := cmd.list(frameCtx, &frame, sortBy, fields); err != nil {
+			return err
+		}
+
+		lines := strings.Split(strings.TrimRight(frame.String(), "\n"), "\n")
+		var b strings.Builder
+		b.WriteString(clearScreen)
+		b.WriteString(p.Gray(fmt.Sprintf("Every %s: hive ls  %s", interval, time.Now().Format(time.TimeOnly))) + "\n\n")
+		for _, line := range lines {
+			if prev != nil && line != "" && !prev[line] {
+				line = p.Highlight(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		if _, err := io.WriteString(out, b.String()); err != nil {
+			return err
+		}
+
+		prev = make(map[string]bool, len(lines))
+		for _, line := range lines {
+			prev[line] = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// printTree writes sessions, and with --all-hosts those on remote hosts, as
+// the TUI's repository tree. Git and terminal status are fetched for local
+// active sessions only.
+func (cmd *LsCmd) printTree(ctx context.Context, w io.Writer, sessions []session.Session) error {
+	cfg := cmd.flags.Config
+	localRemote, _ := cmd.flags.Service.DetectRemote(ctx, ".")
+
+	var paths []string
+	var active []*session.Session
+	for i := range sessions {
+		if sessions[i].State == session.StateActive {
+			paths = append(paths, sessions[i].Path)
+			active = append(active, &sessions[i])
+		}
+	}
+	workers := max(1, cfg.Git.StatusWorkers)
+	gitStatuses := tui.FetchGitStatuses(cmd.flags.Service.Git(), paths, workers)
+	termStatuses := tui.FetchTerminalStatuses(newTerminalManager(cfg.Integrations.Terminal.Enabled), active, workers)
+
+	groups := tui.GroupSessionsByRepo(sessions, localRemote)
+	var items []list.Item
+	if cfg.TUI.GroupBy == config.GroupByOwner {
+		items = tui.BuildOwnerTreeItems(groups, localRemote, nil)
+	} else {
+		items = tui.BuildTreeItems(groups, localRemote)
+	}
+	if cmd.allHosts && cmd.batch == "" {
+		for _, result := range cmd.flags.Service.RemoteSessions(ctx) {
+			remote := filterSessions(result.Sessions, session.State(cmd.state), cmd.repo)
+			items = append(items, tui.BuildHostTreeItems(result.Host, remote, result.Err)...)
+		}
+	}
+
+	if len(items) == 0 {
+		printer.Ctx(ctx).Infof("No sessions found")
+		return nil
+	}
+	return tui.RenderTreeText(w, items, gitStatuses, termStatuses, tui.NewIcons(cmd.ascii, cfg.TUI.Icons))
+}
+
 // sessionInfo is the JSON output format for hive ls --json.
 type sessionInfo struct {
 	ID         string     `json:"id"`
@@ -133,6 +423,16 @@ type sessionInfo struct {
 	LastActive *time.Time `json:"last_active,omitempty"`
 	State      string     `json:"state"`
 	Unread     int        `json:"unread"`
+	BatchID    string     `json:"batch_id,omitempty"` // hive batch that created the session
+	Host       string     `json:"host,omitempty"`     // set by --all-hosts
+
+	Timings []session.Timing `json:"timings,omitempty"` // steps of the session's creation
+}
+
+// lsRow is a listed session and the host it is on, empty for this machine.
+type lsRow struct {
+	host    string
+	session session.Session
 }
 
 func (cmd *LsCmd) getMsgStore() *jsonfile.MsgStore {
@@ -140,6 +440,29 @@ func (cmd *LsCmd) getMsgStore() *jsonfile.MsgStore {
 	return jsonfile.NewMsgStore(topicsDir)
 }
 
+// buildRowInfo returns the JSON output for a row. Unread counts come from the
+// local message store, so they are only computed for local sessions.
+func (cmd *LsCmd) buildRowInfo(ctx context.Context, row lsRow, msgStore *jsonfile.MsgStore) sessionInfo {
+	if row.host == "" {
+		info := cmd.buildSessionInfo(ctx, row.session, msgStore)
+		if cmd.allHosts {
+			info.Host = hosts.Local
+		}
+		return info
+	}
+
+	s := row.session
+	return sessionInfo{
+		ID:      s.ID,
+		Name:    s.Name,
+		Repo:    git.ExtractRepoName(s.Remote),
+		Inbox:   s.InboxTopic(),
+		State:   string(s.State),
+		BatchID: s.BatchID(),
+		Host:    row.host,
+	}
+}
+
 func (cmd *LsCmd) buildSessionInfo(ctx context.Context, s session.Session, msgStore *jsonfile.MsgStore) sessionInfo {
 	info := sessionInfo{
 		ID:         s.ID,
@@ -149,6 +472,8 @@ func (cmd *LsCmd) buildSessionInfo(ctx context.Context, s session.Session, msgSt
 		LastActive: s.LastInboxRead,
 		State:      string(s.State),
 		Unread:     0,
+		BatchID:    s.BatchID(),
+		Timings:    s.Timings,
 	}
 
 	// Count unread messages if we have a last read timestamp
@@ -164,3 +489,143 @@ func (cmd *LsCmd) buildSessionInfo(ctx context.Context, s session.Session, msgSt
 
 	return info
 }
+
+// Sort keys for hive ls --sort.
+const (
+	lsSortRepo    = "repo"
+	lsSortName    = "name"
+	lsSortUpdated = "updated"
+)
+
+// lsFieldNames lists the fields accepted by hive ls --fields.
+var lsFieldNames = []string{"id", "name", "repo", "remote", "state", "path", "inbox", "unread", "last_active", "created", "updated", "batch", "host"}
+
+// lsDefaultFields are the table columns shown without --fields.
+var lsDefaultFields = []string{"repo", "name", "state", "path"}
+
+// parseLsFields parses a comma-separated field list. An empty list returns nil.
+func parseLsFields(list string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+
+	var fields []string
+	for _, f := range strings.Split(list, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(lsFieldNames, f) {
+			return nil, fmt.Errorf("unknown field %q (expected %s)", f, strings.Join(lsFieldNames, ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// selectLsFields returns the requested fields of a session. info supplies the
+// inbox fields and may be zero when they were not requested.
+func selectLsFields(fields []string, s session.Session, info sessionInfo) map[string]any {
+	values := make(map[string]any, len(fields))
+	for _, f := range fields {
+		switch f {
+		case "id":
+			values[f] = s.ID
+		case "name":
+			values[f] = s.Name
+		case "repo":
+			values[f] = git.ExtractRepoName(s.Remote)
+		case "remote":
+			values[f] = s.Remote
+		case "state":
+			values[f] = string(s.State)
+		case "path":
+			values[f] = s.Path
+		case "inbox":
+			values[f] = s.InboxTopic()
+		case "unread":
+			values[f] = info.Unread
+		case "last_active":
+			values[f] = s.LastInboxRead
+		case "created":
+			values[f] = s.CreatedAt
+		case "updated":
+			values[f] = s.UpdatedAt
+		case "batch":
+			values[f] = s.BatchID()
+		}
+	}
+	return values
+}
+
+// selectRowFields is selectLsFields for a row, adding its host.
+func selectRowFields(fields []string, row lsRow, info sessionInfo) map[string]any {
+	values := selectLsFields(fields, row.session, info)
+	if slices.Contains(fields, "host") {
+		values["host"] = cmp.Or(row.host, hosts.Local)
+	}
+	return values
+}
+
+// formatLsValue formats a field value for the table.
+func formatLsValue(v any) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.Local().Format(time.DateTime)
+	case *time.Time:
+		if v == nil {
+			return "-"
+		}
+		return v.Local().Format(time.DateTime)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// filterSessions returns the sessions in state (if set) whose repository
+// matches repo, given as owner/name or just name (if set).
+func filterSessions(sessions []session.Session, state session.State, repo string) []session.Session {
+	if state == "" && repo == "" {
+		return sessions
+	}
+
+	var out []session.Session
+	for _, s := range sessions {
+		if state != "" && s.State != state {
+			continue
+		}
+		if repo != "" && !git.MatchesRepo(s.Remote, repo) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// paginate returns the rows after skipping offset, at most limit of them when
+// limit is positive.
+func paginate(rows []lsRow, offset, limit int) []lsRow {
+	if offset >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if limit > 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// sortSessions sorts sessions in place by repo (then name), name, or most
+// recently updated.
+func sortSessions(sessions []session.Session, by string) {
+	slices.SortStableFunc(sessions, func(a, b session.Session) int {
+		switch by {
+		case lsSortName:
+			return strings.Compare(a.Name, b.Name)
+		case lsSortUpdated:
+			return b.UpdatedAt.Compare(a.UpdatedAt)
+		default:
+			return strings.Compare(git.ExtractRepoName(a.Remote), git.ExtractRepoName(b.Remote))
+		}
+	})
+}
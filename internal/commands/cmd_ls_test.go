@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+// newServiceFlags returns Flags backed by a session store holding sessions.
+func newServiceFlags(t *testing.T, sessions []session.Session) *Flags {
+	t.Helper()
+
+	cfg := &config.Config{DataDir: t.TempDir()}
+	store := jsonfile.New(cfg.SessionsFile())
+	for _, s := range sessions {
+		require.NoError(t, store.Save(context.Background(), s))
+	}
+
+	exec := &executil.RecordingExecutor{}
+	svc := hive.New(store, git.NewExecutor("git", exec), cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	return &Flags{Config: cfg, Service: svc, Store: store, DataDir: cfg.DataDir}
+}
+
+func runLs(t *testing.T, sessions []session.Session, args ...string) (string, error) {
+	t.Helper()
+
+	flags := newServiceFlags(t, sessions)
+	ctx := printer.NewContext(context.Background(), printer.New(io.Discard))
+
+	var buf bytes.Buffer
+	app := NewLsCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+	err := app.Run(ctx, append([]string{"hive", "ls"}, args...))
+	return buf.String(), err
+}
+
+func TestLs_FilterSortFields(t *testing.T) {
+	now := time.Now()
+	sessions := []session.Session{
+		{ID: "a1", Name: "alpha", Remote: "git@github.com:hay-kot/hive.git", State: session.StateActive, Path: "/s/a1", UpdatedAt: now.Add(-time.Hour)},
+		{ID: "b2", Name: "bravo", Remote: "git@github.com:hay-kot/hive.git", State: session.StateRecycled, Path: "/s/b2", UpdatedAt: now},
+		{ID: "c3", Name: "charlie", Remote: "https://github.com/other/tool.git", State: session.StateActive, Path: "/s/c3", UpdatedAt: now.Add(-2 * time.Hour), Metadata: map[string]string{session.MetaBatchID: "k3x9qa"}},
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "state",
+			args: []string{"--state", "active", "--sort", "name", "--fields", "id"},
+			want: "ID\na1\nc3\n",
+		},
+		{
+			name: "repo owner/name",
+			args: []string{"--repo", "hay-kot/hive", "--sort", "name", "--fields", "id,name"},
+			want: "ID  NAME\na1  alpha\nb2  bravo\n",
+		},
+		{
+			name: "repo name only",
+			args: []string{"--repo", "tool", "--fields", "id"},
+			want: "ID\nc3\n",
+		},
+		{
+			name: "batch",
+			args: []string{"--batch", "k3x9qa", "--fields", "id,batch"},
+			want: "ID  BATCH\nc3  k3x9qa\n",
+		},
+		{
+			name: "sort updated",
+			args: []string{"--sort", "updated", "--fields", "id"},
+			want: "ID\nb2\na1\nc3\n",
+		},
+		{
+			name: "limit and offset",
+			args: []string{"--sort", "updated", "--fields", "id", "--offset", "1", "--limit", "1"},
+			want: "ID\na1\n",
+		},
+		{
+			name: "offset past end",
+			args: []string{"--offset", "5", "--fields", "id"},
+			want: "",
+		},
+		{
+			name: "json fields",
+			args: []string{"--json", "--state", "recycled", "--fields", "id,path"},
+			want: `{"id":"b2","path":"/s/b2"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := runLs(t, sessions, tt.args...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLs_InvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--state", "bogus"},
+		{"--sort", "size"},
+		{"--fields", "id,nope"},
+		{"--limit", "-1"},
+	} {
+		_, err := runLs(t, nil, args...)
+		assert.Error(t, err, args)
+	}
+}
+
+func TestLs_Watch(t *testing.T) {
+	sessions := []session.Session{
+		{ID: "a1", Name: "alpha", Remote: "git@github.com:hay-kot/hive.git", Path: "/s/a1", State: session.StateActive},
+	}
+	flags := newServiceFlags(t, sessions)
+	ctx, cancel := context.WithCancel(printer.NewContext(context.Background(), printer.New(io.Discard)))
+
+	var buf bytes.Buffer
+	cmd := NewLsCmd(flags)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		cancel()
+	}()
+	require.NoError(t, cmd.watchList(ctx, &buf, lsSortRepo, []string{"name", "state", "unread"}, 10*time.Millisecond))
+
+	out := buf.String()
+	assert.Contains(t, out, clearScreen)
+	assert.Contains(t, out, "alpha")
+	assert.Greater(t, strings.Count(out, clearScreen), 1, "the list is redrawn")
+}
+
+func TestLs_WatchInvalidFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"--watch", "--json"},
+		{"--watch", "--interval", "soon"},
+		{"--watch", "--interval", "0s"},
+	} {
+		_, err := runLs(t, nil, args...)
+		assert.Error(t, err, args)
+	}
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type MigrateStoreCmd struct {
+	flags *Flags
+
+	// flags
+	to    string
+	force bool
+}
+
+// NewMigrateStoreCmd creates a new migrate-store command
+func NewMigrateStoreCmd(flags *Flags) *MigrateStoreCmd {
+	return &MigrateStoreCmd{flags: flags}
+}
+
+// Register adds the migrate-store command to the application
+func (cmd *MigrateStoreCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "migrate-store",
+		Usage:     "Copy sessions between the json and sqlite store backends",
+		UsageText: "hive migrate-store [--to sqlite|json] [--force]",
+		Description: `Copies every session from one store backend to the other. By default
+sessions are copied from sessions.json into sessions.db (SQLite).
+
+The source is left untouched. After migrating, select the new backend in
+your config:
+
+  store:
+    backend: sqlite
+
+The destination must be empty unless --force is given, in which case
+sessions with the same ID are overwritten.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "to",
+				Usage:       "backend to copy sessions into (sqlite or json)",
+				Value:       config.StoreSQLite,
+				Destination: &cmd.to,
+			},
+			&cli.BoolFlag{
+				Name:        "force",
+				Aliases:     []string{"f"},
+				Usage:       "copy into a destination that already has sessions",
+				Destination: &cmd.force,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+type migrateStoreOutput struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Migrated int    `json:"migrated"`
+}
+
+func (cmd *MigrateStoreCmd) run(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+	cfg := cmd.flags.Config
+
+	var from string
+	switch cmd.to {
+	case config.StoreSQLite:
+		from = config.StoreJSON
+	case config.StoreJSON:
+		from = config.StoreSQLite
+	default:
+		return fmt.Errorf("invalid --to %q: must be sqlite or json", cmd.to)
+	}
+
+	src, err := openBackend(cfg, from)
+	if err != nil {
+		return err
+	}
+	defer CloseStore(src)
+
+	dst, err := openBackend(cfg, cmd.to)
+	if err != nil {
+		return err
+	}
+	defer CloseStore(dst)
+
+	sessions, err := src.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list %s sessions: %w", from, err)
+	}
+
+	existing, err := dst.List(ctx)
+	if err != nil {
+		return fmt.Errorf("list %s sessions: %w", cmd.to, err)
+	}
+	if len(existing) > 0 && !cmd.force {
+		return fmt.Errorf("%s store already has %d sessions; use --force to merge into it", cmd.to, len(existing))
+	}
+
+	cmd.flags.Service.AutoBackup(ctx, hive.BackupMigrate)
+
+	for _, sess := range sessions {
+		// Versions are tracked per store, so copy over whatever is there
+		sess.Version = 0
+		if err := dst.Save(ctx, sess); err != nil {
+			return fmt.Errorf("save session %s: %w", sess.ID, err)
+		}
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, migrateStoreOutput{From: from, To: cmd.to, Migrated: len(sessions)})
+	}
+
+	p.Successf("Migrated %d sessions from %s to %s", len(sessions), from, cmd.to)
+	if cfg.Store.Backend != cmd.to {
+		p.Infof("Set store.backend: %s in your config to use it", cmd.to)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestMigrateStore(t *testing.T) {
+	flags := newServiceFlags(t, []session.Session{
+		{ID: "a1", Name: "alpha", State: session.StateActive, Metadata: map[string]string{"tmux": "hive-a1"}},
+		{ID: "b2", Name: "bravo", State: session.StateRecycled},
+	})
+	ctx := printer.NewContext(context.Background(), printer.New(io.Discard))
+
+	run := func(args ...string) error {
+		app := NewMigrateStoreCmd(flags).Register(&cli.Command{Name: "hive", Writer: io.Discard})
+		return app.Run(ctx, append([]string{"hive", "migrate-store"}, args...))
+	}
+
+	require.NoError(t, run())
+
+	dst, err := openBackend(flags.Config, config.StoreSQLite)
+	require.NoError(t, err)
+	defer CloseStore(dst)
+
+	got, err := dst.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a1", got[0].ID)
+	assert.Equal(t, "hive-a1", got[0].Metadata["tmux"])
+
+	// A populated destination needs --force
+	err = run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--force")
+	require.NoError(t, run("--force"))
+
+	// And back again, into a json store that still holds the originals
+	require.Error(t, run("--to", "json"))
+	require.NoError(t, run("--to", "json", "--force"))
+
+	require.Error(t, run("--to", "postgres"))
+}
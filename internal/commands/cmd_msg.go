@@ -8,10 +8,14 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/printer"
 	"github.com/hay-kot/hive/internal/store/jsonfile"
 	"github.com/hay-kot/hive/pkg/randid"
 	"github.com/urfave/cli/v3"
@@ -21,9 +25,14 @@ type MsgCmd struct {
 	flags *Flags
 
 	// pub flags
-	pubTopic  string
-	pubFile   string
-	pubSender string
+	pubTopic    string
+	pubFile     string
+	pubSender   string
+	pubHost     string
+	pubPriority string
+	pubExpires  string
+	noRelay     bool
+	pubOrigin   messaging.Message // host, user, and tool forwarded by a relaying hive
 
 	// sub flags
 	subTopic   string
@@ -32,6 +41,30 @@ type MsgCmd struct {
 	subListen  bool
 	subWait    bool
 	subNew     bool
+	subHost    string
+	subOrder   string
+
+	// search flags
+	searchTopic  string
+	searchSince  string
+	searchSender string
+	searchJSON   bool
+	searchAll    bool
+
+	// pipe flags
+	pipeTopic  string
+	pipeReply  string
+	pipeJSON   bool
+	pipeSender string
+
+	// stats flags
+	statsTopSenders bool
+	statsSince      string
+	statsLimit      int
+
+	// archive flags
+	archiveTopic     string
+	archiveOlderThan string
 
 	// topic flags
 	topicNew    bool
@@ -58,6 +91,10 @@ The sender is auto-detected from the current working directory's hive session.`,
 			cmd.pubCmd(),
 			cmd.subCmd(),
 			cmd.listCmd(),
+			cmd.searchCmd(),
+			cmd.statsCmd(),
+			cmd.archiveCmd(),
+			cmd.pipeCmd(),
 			cmd.topicCmd(),
 		},
 	})
@@ -78,11 +115,25 @@ The message can be provided as:
 - From stdin if no argument is provided
 
 The sender is auto-detected from the current hive session, or can be overridden with --sender.
+The publishing hostname, OS user, and AI tool (when detected) are recorded with
+the message. Set HIVE_TOOL to name the tool explicitly.
+
+Use --priority to mark a message low, normal (the default), high, or urgent.
+Readers can list urgent messages first with hive msg sub --order priority.
+
+Use --expires-in for short-lived notices: once expired, a message is no longer
+returned to readers and is removed the next time its topic is written or pruned.
+
+Messages on topics listed under a host's relay patterns are also published on
+that host. Use --host to publish only on a remote host instead.
 
 Examples:
   hive msg pub --topic build.started "Build starting"
   echo "Hello" | hive msg pub --topic greetings
-  hive msg pub --topic logs -f build.log`,
+  hive msg pub --topic logs -f build.log
+  hive msg pub --host server --topic handoff "Ready for review"
+  hive msg pub -t agent.abc.inbox --priority urgent "Stop: main is broken"
+  hive msg pub -t repo.status --expires-in 30m "Running migrations"`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "topic",
@@ -103,6 +154,42 @@ Examples:
 				Usage:       "override sender ID (default: auto-detect from session)",
 				Destination: &cmd.pubSender,
 			},
+			&cli.StringFlag{
+				Name:        "priority",
+				Aliases:     []string{"p"},
+				Usage:       "message priority: low, normal, high, or urgent",
+				Destination: &cmd.pubPriority,
+			},
+			&cli.StringFlag{
+				Name:        "expires-in",
+				Usage:       "expire the message after a duration (e.g., 30m, 2h, 1d)",
+				Destination: &cmd.pubExpires,
+			},
+			&cli.StringFlag{
+				Name:        "host",
+				Usage:       "publish on a configured remote host instead of locally",
+				Destination: &cmd.pubHost,
+			},
+			&cli.BoolFlag{
+				Name:        "no-relay",
+				Usage:       "do not relay the message to other hosts",
+				Destination: &cmd.noRelay,
+			},
+			&cli.StringFlag{
+				Name:        "origin-host",
+				Hidden:      true,
+				Destination: &cmd.pubOrigin.Host,
+			},
+			&cli.StringFlag{
+				Name:        "origin-user",
+				Hidden:      true,
+				Destination: &cmd.pubOrigin.User,
+			},
+			&cli.StringFlag{
+				Name:        "origin-tool",
+				Hidden:      true,
+				Destination: &cmd.pubOrigin.Tool,
+			},
 		},
 		Action: cmd.runPub,
 	}
@@ -120,6 +207,9 @@ or --wait to block until a single message arrives (useful for inter-agent handof
 
 Use --new to filter messages since your last inbox read (only works for inbox topics).
 
+Use --order priority to list urgent and high priority messages first, oldest
+first within each priority. Messages from --listen and --wait print as they arrive.
+
 Topic patterns:
 - No topic or "*": all messages
 - "exact.topic": exact topic match
@@ -132,7 +222,9 @@ Examples:
   hive msg sub --last 10                # last 10 messages
   hive msg sub --listen                 # poll for new messages
   hive msg sub --wait --topic handoff   # wait for single message (24h default timeout)
-  hive msg sub -t agent.abc.inbox --new # only unread inbox messages`,
+  hive msg sub -t agent.abc.inbox --new # only unread inbox messages
+  hive msg sub -t agent.abc.inbox --order priority # most urgent first
+  hive msg sub --host server -t handoff # read a topic on a remote host`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "topic",
@@ -169,6 +261,23 @@ Examples:
 				Value:       "30s",
 				Destination: &cmd.subTimeout,
 			},
+			&cli.StringFlag{
+				Name:        "order",
+				Usage:       "message order: time or priority",
+				Value:       "time",
+				Destination: &cmd.subOrder,
+				Validator: func(s string) error {
+					if s != "time" && s != "priority" {
+						return fmt.Errorf("unknown order %q (expected time or priority)", s)
+					}
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:        "host",
+				Usage:       "read from a configured remote host instead of locally",
+				Destination: &cmd.subHost,
+			},
 		},
 		Action: cmd.runSub,
 	}
@@ -187,6 +296,131 @@ Examples:
 	}
 }
 
+func (cmd *MsgCmd) searchCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "search",
+		Usage:     "Search message payloads",
+		UsageText: "hive msg search <query> [--topic <pattern>] [--since 24h] [--sender id] [--include-archived] [--json]",
+		Description: `Searches message payloads for every word of the query, ignoring case.
+
+Matches are printed oldest first with the matching text highlighted. Use --json
+for JSON lines that keep each message's metadata along with the byte ranges
+of the matches.
+
+--sender matches either the message's sender or its session ID. Messages moved
+to cold storage by hive msg archive are searched with --include-archived.
+
+Examples:
+  hive msg search "build failed"
+  hive msg search -t "agent.*" --since 24h timeout
+  hive msg search --sender abc123 --json review`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "topic",
+				Aliases:     []string{"t"},
+				Usage:       "topic pattern to search (supports wildcards like agent.*)",
+				Destination: &cmd.searchTopic,
+			},
+			&cli.StringFlag{
+				Name:        "since",
+				Usage:       "only search messages newer than a duration (e.g., 30m, 24h, 7d)",
+				Destination: &cmd.searchSince,
+			},
+			&cli.StringFlag{
+				Name:        "sender",
+				Aliases:     []string{"s"},
+				Usage:       "only search messages from a sender or session ID",
+				Destination: &cmd.searchSender,
+			},
+			&cli.BoolFlag{
+				Name:        "include-archived",
+				Usage:       "also search archived messages",
+				Destination: &cmd.searchAll,
+			},
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output matches as JSON lines",
+				Destination: &cmd.searchJSON,
+			},
+		},
+		Action: cmd.runSearch,
+	}
+}
+
+func (cmd *MsgCmd) statsCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "stats",
+		Usage:     "Show message counts per topic or per sender",
+		UsageText: "hive msg stats [--top-senders] [--since <duration>] [--limit N]",
+		Description: `Shows how many messages each topic holds and when it was last written.
+
+With --top-senders, counts messages by sender instead, busiest first, to find
+an agent flooding topics. A message's sender is its --sender, else its session
+ID, else the user@host that published it. Use --since to count only recent
+messages.
+
+Senders can be limited with messaging.rate_limit in the config; publishes over
+the limit fail until the window has passed.
+
+Examples:
+  hive msg stats
+  hive msg stats --top-senders --since 5m
+  hive msg stats --top-senders --limit 3 --json`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "top-senders",
+				Usage:       "count messages by sender instead of by topic",
+				Destination: &cmd.statsTopSenders,
+			},
+			&cli.StringFlag{
+				Name:        "since",
+				Usage:       "only count messages newer than a duration (e.g., 1m, 1h, 7d)",
+				Destination: &cmd.statsSince,
+			},
+			&cli.IntFlag{
+				Name:        "limit",
+				Aliases:     []string{"n"},
+				Usage:       "show at most N senders (0 for all)",
+				Value:       10,
+				Destination: &cmd.statsLimit,
+			},
+		},
+		Action: cmd.runStats,
+	}
+}
+
+func (cmd *MsgCmd) archiveCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "archive",
+		Usage:     "Move old messages to compressed cold storage",
+		UsageText: "hive msg archive --older-than <duration> [--topic <pattern>]",
+		Description: `Moves messages older than a duration out of the active topic files into
+gzip-compressed archive files under $XDG_DATA_HOME/hive/messages/archive/.
+
+Archived messages no longer appear in hive msg sub or the TUI, but are kept and
+can be found with hive msg search --include-archived.
+
+Examples:
+  hive msg archive --older-than 7d
+  hive msg archive -t "agent.*" --older-than 24h`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "topic",
+				Aliases:     []string{"t"},
+				Usage:       "topic pattern to archive (supports wildcards like agent.*)",
+				Destination: &cmd.archiveTopic,
+			},
+			&cli.StringFlag{
+				Name:        "older-than",
+				Usage:       "archive messages older than this duration (e.g., 7d, 24h)",
+				Required:    true,
+				Destination: &cmd.archiveOlderThan,
+			},
+		},
+		Action: cmd.runArchive,
+	}
+}
+
 func (cmd *MsgCmd) topicCmd() *cli.Command {
 	return &cli.Command{
 		Name:      "topic",
@@ -268,16 +502,59 @@ func (cmd *MsgCmd) runPub(ctx context.Context, c *cli.Command) error {
 		Payload:   payload,
 		Sender:    cmd.pubSender,
 		SessionID: cmd.detectSessionID(ctx),
+		Host:      cmd.pubOrigin.Host,
+		User:      cmd.pubOrigin.User,
+		Tool:      cmd.pubOrigin.Tool,
+	}
+	if cmd.pubPriority != "" {
+		priority, err := messaging.ParsePriority(cmd.pubPriority)
+		if err != nil {
+			return err
+		}
+		msg.Priority = priority
+	}
+	if cmd.pubExpires != "" {
+		d, err := parseDuration(cmd.pubExpires)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --expires-in %q: must be a positive duration", cmd.pubExpires)
+		}
+		msg.ExpiresAt = time.Now().Add(d)
+	}
+	messaging.Stamp(&msg)
+
+	if cmd.pubHost != "" {
+		host, err := cmd.flags.Service.Host(cmd.pubHost)
+		if err != nil {
+			return err
+		}
+		if msg.Sender == "" {
+			msg.Sender = msg.SessionID
+		}
+		return host.Publish(ctx, msg)
 	}
 
+	return cmd.publish(ctx, store, msg, !cmd.noRelay)
+}
+
+// publish publishes msg locally, records the event, and relays it to hosts
+// relaying its topic when relay is set.
+func (cmd *MsgCmd) publish(ctx context.Context, store *jsonfile.MsgStore, msg messaging.Message, relay bool) error {
 	if err := store.Publish(ctx, msg); err != nil {
 		return fmt.Errorf("publish message: %w", err)
 	}
+	cmd.flags.Service.EmitMessage(msg)
 
+	if relay {
+		cmd.flags.Service.RelayMessage(ctx, msg)
+	}
 	return nil
 }
 
 func (cmd *MsgCmd) runSub(ctx context.Context, c *cli.Command) error {
+	if cmd.subHost != "" {
+		return cmd.runRemoteSub(ctx, c)
+	}
+
 	store := cmd.getMsgStore()
 
 	topic := cmd.subTopic
@@ -301,8 +578,15 @@ func (cmd *MsgCmd) runSub(ctx context.Context, c *cli.Command) error {
 		return cmd.listenForMessages(ctx, c, store, topic, since)
 	}
 
-	// Default: return messages immediately
-	messages, err := store.Subscribe(ctx, topic, since)
+	// Default: return messages immediately. With --last N only the newest
+	// topics are read.
+	var messages []messaging.Message
+	var err error
+	if cmd.subLast > 0 {
+		messages, err = store.Latest(ctx, topic, since, cmd.subLast)
+	} else {
+		messages, err = store.Subscribe(ctx, topic, since)
+	}
 	if err != nil {
 		if errors.Is(err, messaging.ErrTopicNotFound) {
 			return nil // No messages, no output
@@ -313,14 +597,44 @@ func (cmd *MsgCmd) runSub(ctx context.Context, c *cli.Command) error {
 	// Update inbox read timestamp if subscribing to own inbox
 	cmd.updateInboxReadIfOwn(ctx, topic)
 
-	// Apply --last N limit if specified
-	if cmd.subLast > 0 && len(messages) > cmd.subLast {
-		messages = messages[len(messages)-cmd.subLast:]
+	if cmd.subOrder == "priority" {
+		messaging.SortByPriority(messages)
 	}
-
 	return cmd.printMessages(c.Root().Writer, messages)
 }
 
+// runRemoteSub runs hive msg sub on a remote host with the same flags,
+// streaming its output.
+func (cmd *MsgCmd) runRemoteSub(ctx context.Context, c *cli.Command) error {
+	if cmd.subNew {
+		return fmt.Errorf("--new cannot be used with --host")
+	}
+
+	host, err := cmd.flags.Service.Host(cmd.subHost)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"msg", "sub", "--timeout", cmd.subTimeout}
+	if cmd.subTopic != "" {
+		args = append(args, "--topic", cmd.subTopic)
+	}
+	if cmd.subLast > 0 {
+		args = append(args, "--last", strconv.Itoa(cmd.subLast))
+	}
+	if cmd.subListen {
+		args = append(args, "--listen")
+	}
+	if cmd.subWait {
+		args = append(args, "--wait")
+	}
+	if cmd.subOrder != "" && cmd.subOrder != "time" {
+		args = append(args, "--order", cmd.subOrder)
+	}
+
+	return host.Stream(ctx, c.Root().Writer, c.Root().ErrWriter, args...)
+}
+
 func (cmd *MsgCmd) listenForMessages(ctx context.Context, c *cli.Command, store *jsonfile.MsgStore, topic string, initialSince time.Time) error {
 	timeout, err := time.ParseDuration(cmd.subTimeout)
 	if err != nil {
@@ -420,7 +734,12 @@ func (cmd *MsgCmd) runList(ctx context.Context, c *cli.Command) error {
 		return nil // No topics, no output
 	}
 
-	// Get message counts for each topic
+	// Message counts come from the store's index where it is current
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		return fmt.Errorf("topic stats: %w", err)
+	}
+
 	type topicInfo struct {
 		Name         string `json:"name"`
 		MessageCount int    `json:"message_count"`
@@ -428,12 +747,7 @@ func (cmd *MsgCmd) runList(ctx context.Context, c *cli.Command) error {
 
 	var infos []topicInfo
 	for _, t := range topics {
-		messages, err := store.Subscribe(ctx, t, time.Time{})
-		count := 0
-		if err == nil {
-			count = len(messages)
-		}
-		infos = append(infos, topicInfo{Name: t, MessageCount: count})
+		infos = append(infos, topicInfo{Name: t, MessageCount: stats[t].Count})
 	}
 
 	enc := json.NewEncoder(c.Root().Writer)
@@ -445,15 +759,212 @@ func (cmd *MsgCmd) runList(ctx context.Context, c *cli.Command) error {
 	return nil
 }
 
+func (cmd *MsgCmd) runSearch(ctx context.Context, c *cli.Command) error {
+	query := strings.Join(c.Args().Slice(), " ")
+	if strings.TrimSpace(query) == "" && cmd.searchSender == "" {
+		return fmt.Errorf("search query required")
+	}
+
+	var since time.Time
+	if cmd.searchSince != "" {
+		d, err := parseDuration(cmd.searchSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	topic := cmd.searchTopic
+	if topic == "" {
+		topic = "*"
+	}
+
+	store := cmd.getMsgStore()
+	messages, err := store.Subscribe(ctx, topic, since)
+	if err != nil && !errors.Is(err, messaging.ErrTopicNotFound) {
+		return fmt.Errorf("search: %w", err)
+	}
+	if cmd.searchAll {
+		archived, err := store.Archived(ctx, topic, since)
+		if err != nil {
+			return fmt.Errorf("search archive: %w", err)
+		}
+		messages = append(archived, messages...)
+		slices.SortStableFunc(messages, func(a, b messaging.Message) int {
+			return a.CreatedAt.Compare(b.CreatedAt)
+		})
+	}
+
+	results := messaging.Search(messages, query, cmd.searchSender)
+
+	out := c.Root().Writer
+	if wantJSON(ctx, cmd.searchJSON) {
+		for _, r := range results {
+			if err := printer.EncodeJSON(out, r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	p := printer.Ctx(ctx)
+	if len(results) == 0 {
+		p.Infof("No messages match %q", query)
+		return nil
+	}
+	for _, r := range results {
+		sender := r.Sender
+		if sender == "" {
+			sender = r.Origin()
+		}
+		header := fmt.Sprintf("%s [%s] [%s]", r.CreatedAt.Format(time.DateTime), sender, r.Topic)
+		_, _ = fmt.Fprintf(out, "%s %s\n", p.Gray(header), messaging.Highlight(r.Payload, r.Matches, p.Highlight))
+	}
+	return nil
+}
+
+func (cmd *MsgCmd) runStats(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+	out := c.Root().Writer
+	store := cmd.getMsgStore()
+
+	var since time.Time
+	if cmd.statsSince != "" {
+		d, err := parseDuration(cmd.statsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+
+	if cmd.statsTopSenders {
+		messages, err := store.Subscribe(ctx, "*", since)
+		if err != nil && !errors.Is(err, messaging.ErrTopicNotFound) {
+			return fmt.Errorf("read messages: %w", err)
+		}
+		senders := messaging.TopSenders(messages, cmd.statsLimit)
+
+		if p.IsJSON() {
+			return printer.EncodeJSON(out, senders)
+		}
+		if len(senders) == 0 {
+			p.Infof("No messages")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "SENDER\tMESSAGES\tTOPICS\tLAST")
+		for _, s := range senders {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", s.Sender, s.Messages, s.Topics, s.Last.Format(time.DateTime))
+		}
+		return w.Flush()
+	}
+
+	// Whole-topic counts come from the store's index; counts since a time
+	// need the messages themselves.
+	var stats map[string]jsonfile.TopicStats
+	if since.IsZero() {
+		var err error
+		if stats, err = store.Stats(ctx); err != nil {
+			return fmt.Errorf("topic stats: %w", err)
+		}
+	} else {
+		messages, err := store.Subscribe(ctx, "*", since)
+		if err != nil && !errors.Is(err, messaging.ErrTopicNotFound) {
+			return fmt.Errorf("read messages: %w", err)
+		}
+		stats = make(map[string]jsonfile.TopicStats)
+		for _, msg := range messages {
+			st := stats[msg.Topic]
+			st.Count++
+			if msg.CreatedAt.After(st.Last) {
+				st.Last = msg.CreatedAt
+			}
+			stats[msg.Topic] = st
+		}
+	}
+
+	topics := make([]msgTopicStats, 0, len(stats))
+	for name, st := range stats {
+		if st.Count > 0 {
+			topics = append(topics, msgTopicStats{Topic: name, Messages: st.Count, Last: st.Last})
+		}
+	}
+	slices.SortFunc(topics, func(a, b msgTopicStats) int {
+		if a.Messages != b.Messages {
+			return b.Messages - a.Messages
+		}
+		return strings.Compare(a.Topic, b.Topic)
+	})
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(out, topics)
+	}
+	if len(topics) == 0 {
+		p.Infof("No messages")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "TOPIC\tMESSAGES\tLAST")
+	for _, t := range topics {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", t.Topic, t.Messages, t.Last.Format(time.DateTime))
+	}
+	return w.Flush()
+}
+
+// msgTopicStats is the JSON output format for a topic in hive msg stats.
+type msgTopicStats struct {
+	Topic    string    `json:"topic"`
+	Messages int       `json:"messages"`
+	Last     time.Time `json:"last"`
+}
+
+func (cmd *MsgCmd) runArchive(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	olderThan, err := parseDuration(cmd.archiveOlderThan)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	topic := cmd.archiveTopic
+	if topic == "" {
+		topic = "*"
+	}
+
+	archived, err := cmd.getMsgStore().Archive(ctx, topic, olderThan)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	for _, n := range archived {
+		total += n
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, msgArchiveOutput{Topics: archived, Archived: total})
+	}
+
+	p.Successf("Archived %d message(s) from %d topic(s) older than %s", total, len(archived), cmd.archiveOlderThan)
+	return nil
+}
+
+// msgArchiveOutput is the JSON output format for hive msg archive.
+type msgArchiveOutput struct {
+	Topics   map[string]int `json:"topics"`
+	Archived int            `json:"archived"`
+}
+
 func (cmd *MsgCmd) getMsgStore() *jsonfile.MsgStore {
 	topicsDir := filepath.Join(cmd.flags.DataDir, "messages", "topics")
-	return jsonfile.NewMsgStore(topicsDir)
+	limit := cmd.flags.Config.Messaging.RateLimit
+	return jsonfile.NewMsgStore(topicsDir).WithRateLimit(limit.Messages, limit.Per)
 }
 
 func (cmd *MsgCmd) detectSessionID(ctx context.Context) string {
-	sessionsPath := filepath.Join(cmd.flags.DataDir, "sessions.json")
-	sessStore := jsonfile.New(sessionsPath)
-	detector := messaging.NewSessionDetector(sessStore)
+	detector := cmd.flags.SessionDetector()
 
 	sessionID, _ := detector.DetectSession(ctx)
 	return sessionID
@@ -494,8 +1005,7 @@ func (cmd *MsgCmd) updateInboxReadIfOwn(ctx context.Context, topic string) {
 	}
 
 	// Update the session's LastInboxRead
-	sessionsPath := filepath.Join(cmd.flags.DataDir, "sessions.json")
-	sessStore := jsonfile.New(sessionsPath)
+	sessStore := cmd.flags.Store
 
 	sess, err := sessStore.Get(ctx, currentSessionID)
 	if err != nil {
@@ -523,8 +1033,7 @@ func (cmd *MsgCmd) getLastInboxRead(ctx context.Context, topic string) time.Time
 	topicSessionID := parts[1]
 
 	// Get the session's LastInboxRead
-	sessionsPath := filepath.Join(cmd.flags.DataDir, "sessions.json")
-	sessStore := jsonfile.New(sessionsPath)
+	sessStore := cmd.flags.Store
 
 	sess, err := sessStore.Get(ctx, topicSessionID)
 	if err != nil {
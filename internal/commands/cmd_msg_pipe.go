@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/pkg/randid"
+	"github.com/urfave/cli/v3"
+)
+
+func (cmd *MsgCmd) pipeCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "pipe",
+		Usage:     "Bridge a topic to a command's stdin and stdout",
+		UsageText: "hive msg pipe --topic <pattern> [--reply <topic>] [--json] -- <command> [args...]",
+		Description: `Runs a command, writing each new message on the topic to its stdin and
+publishing each line it writes to stdout to the reply topic.
+
+Messages are written one per line: the payload with newlines replaced by
+spaces, or the whole message as JSON with --json. Only messages published after
+the command starts are fed. Blank output lines are skipped, and the command's
+replies are never fed back to it, even when the reply topic matches --topic.
+
+The reply topic defaults to "<topic>.reply" and is required when --topic is a
+wildcard pattern. The pipe runs until the command exits or hive is interrupted.
+
+Examples:
+  hive msg pipe -t build.status -- grep --line-buffered -i failed
+  hive msg pipe -t "agent.*" --reply bot.out --json -- ./triage-bot`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "topic",
+				Aliases:     []string{"t"},
+				Usage:       "topic pattern to feed to the command (supports wildcards like agent.*)",
+				Required:    true,
+				Destination: &cmd.pipeTopic,
+			},
+			&cli.StringFlag{
+				Name:        "reply",
+				Aliases:     []string{"r"},
+				Usage:       "topic to publish the command's output to (default: <topic>.reply)",
+				Destination: &cmd.pipeReply,
+			},
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "write messages to the command as JSON lines instead of payloads",
+				Destination: &cmd.pipeJSON,
+			},
+			&cli.StringFlag{
+				Name:        "sender",
+				Aliases:     []string{"s"},
+				Usage:       "sender ID for replies (default: auto-detect from session)",
+				Destination: &cmd.pipeSender,
+			},
+		},
+		Action: cmd.runPipe,
+	}
+}
+
+func (cmd *MsgCmd) runPipe(ctx context.Context, c *cli.Command) error {
+	args := c.Args().Slice()
+	if len(args) == 0 {
+		return fmt.Errorf("command required: hive msg pipe --topic <pattern> -- <command> [args...]")
+	}
+
+	reply := cmd.pipeReply
+	if reply == "" {
+		if strings.Contains(cmd.pipeTopic, "*") {
+			return fmt.Errorf("--reply is required when --topic is a pattern")
+		}
+		reply = cmd.pipeTopic + ".reply"
+	}
+
+	store := cmd.getMsgStore()
+	sessionID := cmd.detectSessionID(ctx)
+
+	pipeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	child := exec.CommandContext(pipeCtx, args[0], args[1:]...)
+	child.Stderr = c.Root().ErrWriter
+	stdin, err := child.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := child.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", args[0], err)
+	}
+
+	// IDs of published replies, so they are not fed back to the command
+	var replies sync.Map
+
+	go func() {
+		defer stdin.Close() //nolint:errcheck
+		_ = cmd.feedPipe(pipeCtx, store, stdin, &replies)
+	}()
+
+	var publishErr error
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		msg := messaging.Message{
+			ID:        randid.Generate(16),
+			Topic:     reply,
+			Payload:   line,
+			Sender:    cmd.pipeSender,
+			SessionID: sessionID,
+		}
+		messaging.Stamp(&msg)
+		replies.Store(msg.ID, true)
+		if err := cmd.publish(pipeCtx, store, msg, true); err != nil {
+			publishErr = err
+			cancel()
+			break
+		}
+	}
+	// Drain the rest of the output so the command is not blocked writing it
+	_, _ = io.Copy(io.Discard, stdout)
+
+	waitErr := child.Wait()
+	switch {
+	case publishErr != nil:
+		return publishErr
+	case ctx.Err() != nil:
+		return nil // interrupted; the command was stopped with it
+	case waitErr != nil:
+		return fmt.Errorf("%s: %w", args[0], waitErr)
+	}
+	return nil
+}
+
+// feedPipe writes messages published on the pipe topic to w until ctx is
+// done or a write fails, skipping the pipe's own replies.
+func (cmd *MsgCmd) feedPipe(ctx context.Context, store *jsonfile.MsgStore, w io.Writer, replies *sync.Map) error {
+	since := time.Now()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			messages, err := store.Subscribe(ctx, cmd.pipeTopic, since)
+			if err != nil && !errors.Is(err, messaging.ErrTopicNotFound) {
+				return fmt.Errorf("subscribe: %w", err)
+			}
+
+			for _, msg := range messages {
+				since = msg.CreatedAt
+				if _, ok := replies.Load(msg.ID); ok {
+					continue
+				}
+
+				line, err := pipeLine(msg, cmd.pipeJSON)
+				if err != nil {
+					return err
+				}
+				if _, err := io.WriteString(w, line+"\n"); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// pipeLine formats msg as a single line for a piped command.
+func pipeLine(msg messaging.Message, asJSON bool) (string, error) {
+	if asJSON {
+		data, err := json.Marshal(msg)
+		return string(data), err
+	}
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(msg.Payload), nil
+}
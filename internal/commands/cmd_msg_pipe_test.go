@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestRunPipe(t *testing.T) {
+	flags := newServiceFlags(t, nil)
+	store := NewMsgCmd(flags).getMsgStore()
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_ = store.Publish(context.Background(), messaging.Message{Topic: "requests", Payload: "hello\nworld"})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	app := NewMsgCmd(flags).Register(&cli.Command{Name: "hive", ErrWriter: &stderr})
+	err := app.Run(ctx, []string{"hive", "msg", "pipe", "-t", "requests", "--", "sh", "-c", `read line; echo "got $line"; echo; echo done`})
+	require.NoError(t, err, stderr.String())
+
+	replies, err := store.Subscribe(context.Background(), "requests.reply", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Equal(t, "got hello world", replies[0].Payload)
+	assert.Equal(t, "done", replies[1].Payload)
+}
+
+func TestRunPipe_PatternNeedsReply(t *testing.T) {
+	app := NewMsgCmd(newServiceFlags(t, nil)).Register(&cli.Command{Name: "hive"})
+	err := app.Run(context.Background(), []string{"hive", "msg", "pipe", "-t", "agent.*", "--", "cat"})
+	require.ErrorContains(t, err, "--reply is required")
+}
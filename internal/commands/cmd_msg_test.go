@@ -3,10 +3,15 @@ package commands
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
 	"github.com/urfave/cli/v3"
 )
 
@@ -201,3 +206,107 @@ func TestRunTopic_Uniqueness(t *testing.T) {
 		t.Errorf("generated only %d unique topic IDs in 10 attempts, expected near 10", len(seen))
 	}
 }
+
+func TestRunSearch(t *testing.T) {
+	dataDir := t.TempDir()
+	flags := &Flags{Config: &config.Config{}, DataDir: dataDir}
+	cmd := NewMsgCmd(flags)
+
+	store := cmd.getMsgStore()
+	for _, msg := range []messaging.Message{
+		{Topic: "build.status", Payload: "Build failed on main", Sender: "abc"},
+		{Topic: "build.status", Payload: "build passed", Sender: "def"},
+		{Topic: "deploy", Payload: "build deployed", Sender: "abc"},
+	} {
+		if err := store.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	app := &cli.Command{Name: "hive", Writer: &buf}
+	cmd.Register(app)
+
+	err := app.Run(context.Background(), []string{"hive", "msg", "search", "-t", "build.*", "--sender", "abc", "--json", "BUILD"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var result messaging.SearchResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if result.Payload != "Build failed on main" {
+		t.Errorf("Payload = %q, want the failed build message", result.Payload)
+	}
+	if want := []messaging.Match{{Start: 0, End: 5}}; !reflect.DeepEqual(result.Matches, want) {
+		t.Errorf("Matches = %v, want %v", result.Matches, want)
+	}
+}
+
+func TestRunArchive(t *testing.T) {
+	flags := &Flags{Config: &config.Config{}, DataDir: t.TempDir()}
+	cmd := NewMsgCmd(flags)
+
+	store := cmd.getMsgStore()
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	if err := store.Publish(context.Background(), messaging.Message{Topic: "build", Payload: "old failure", CreatedAt: old}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	run := func(args ...string) string {
+		var buf bytes.Buffer
+		app := &cli.Command{Name: "hive", Writer: &buf}
+		NewMsgCmd(flags).Register(app)
+		if err := app.Run(context.Background(), append([]string{"hive", "msg"}, args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		return buf.String()
+	}
+
+	run("archive", "--older-than", "7d")
+
+	if out := run("search", "--json", "failure"); out != "" {
+		t.Errorf("search without --include-archived = %q, want no matches", out)
+	}
+	if out := run("search", "--json", "--include-archived", "failure"); !strings.Contains(out, "old failure") {
+		t.Errorf("search --include-archived = %q, want the archived message", out)
+	}
+}
+
+func TestRunSub_OrderPriority(t *testing.T) {
+	flags := &Flags{Config: &config.Config{}, DataDir: t.TempDir()}
+
+	run := func(args ...string) string {
+		var buf bytes.Buffer
+		app := &cli.Command{Name: "hive", Writer: &buf}
+		NewMsgCmd(flags).Register(app)
+		if err := app.Run(context.Background(), append([]string{"hive", "msg"}, args...)); err != nil {
+			t.Fatalf("%v: unexpected error: %v", args, err)
+		}
+		return buf.String()
+	}
+
+	store := NewMsgCmd(flags).getMsgStore()
+	for _, msg := range []messaging.Message{
+		{Topic: "inbox", Payload: "build passed"},
+		{Topic: "inbox", Payload: "main is broken", Priority: messaging.PriorityUrgent},
+		{Topic: "inbox", Payload: "lint nit", Priority: messaging.PriorityLow},
+	} {
+		if err := store.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	var payloads []string
+	for line := range strings.SplitSeq(strings.TrimSpace(run("sub", "-t", "inbox", "--order", "priority")), "\n") {
+		var msg messaging.Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("decode %q: %v", line, err)
+		}
+		payloads = append(payloads, msg.Payload)
+	}
+	if want := []string{"main is broken", "build passed", "lint nit"}; !reflect.DeepEqual(payloads, want) {
+		t.Errorf("payloads = %v, want %v", payloads, want)
+	}
+}
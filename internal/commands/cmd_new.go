@@ -2,19 +2,40 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/core/templates"
 	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/pkg/executil"
 	"github.com/urfave/cli/v3"
 )
 
 type NewCmd struct {
-	flags  *Flags
-	remote string
-	source string
+	flags    *Flags
+	remote   string
+	source   string
+	template string
+	set      []string
+	preview  bool
+	spawn    string
+	subdir   string
+	reviewOf string
+	dryRun   bool
+	wait     bool
+	issue    int
+
+	sibling      bool
+	hereIsSource bool
 }
 
 // NewNewCmd creates a new new command
@@ -27,7 +48,7 @@ func (cmd *NewCmd) Register(app *cli.Command) *cli.Command {
 	app.Commands = append(app.Commands, &cli.Command{
 		Name:      "new",
 		Usage:     "Create a new agent session",
-		UsageText: "hive new <name...>",
+		UsageText: "hive new <name...> | hive new --issue <number> [name...]",
 		Description: `Creates a new isolated git environment for an AI agent session.
 
 If a recyclable session exists for the same remote, it will be reused
@@ -36,14 +57,60 @@ If a recyclable session exists for the same remote, it will be reused
 After setup, any matching hooks are executed and the configured spawn
 command launches a terminal with the AI tool.
 
+With --template, the prompt is rendered from a configured template using
+--set values and the session is spawned with the batch_spawn commands, so
+the prompt is available as {{.Prompt}}. Use --preview (or "preview: true"
+on the template) to review the rendered prompt, and optionally edit it in
+$EDITOR, before the session is created.
+
+With --issue, the issue is fetched from the repository's GitHub or GitLab
+project, with the gh or glab CLI or, when the last matching rule sets
+issues.token, the REST API. The session is named issue-<number> unless a
+name is given, its prompt is rendered from --template or the rule's
+issues.template (which get the issue_number, issue_title, issue_body, and
+issue_url fields they declare) or else lists the issue's title, body, and
+URL, and the issue URL is recorded on the session.
+
+With --spawn, the terminal is launched with a named profile from
+commands.spawn_profiles instead of spawn/batch_spawn. Without it, the
+spawn profile of the last matching rule is used, if any.
+
+With --subdir, the session works in a subdirectory of the repository, such
+as one service of a monorepo: spawn commands, 'hive exec', 'hive open', and
+'hive path' use it, while hooks and git status still run at the repository
+root.
+
+With --review-of, no clone is made: the new session is a read-only review
+of another session's directory, for launching a reviewer agent over the same
+working tree. No rules or hooks run, spawn commands get the reviewed session's
+ID as {{.ReviewOf}}, and recycling or deleting the review only drops its
+record. Reviews end when the reviewed session is recycled or deleted.
+
+Inside the checkout of an active session, hive new stops rather than treat
+the checkout as a plain repository. Pass --sibling to create another session
+of the same remote without copying files from the checkout, or
+--here-is-source to use the checkout as the source of copy rules. Neither is
+needed with --remote or --source.
+
+With --dry-run, nothing is cloned, copied, or run. Instead hive prints the
+resolved remote, the target path, the recycled session it would reuse (if
+any), the matching rules' copy entries, commands, and post_create hooks, and
+the rendered spawn commands.
+
 Example:
   hive new Fix Auth Bug
-  hive new bugfix --source /some/path`,
+  hive new bugfix --source /some/path
+  hive new Review 123 --template pr-review --set pr_number=123 --preview
+  hive new Review PR --spawn review
+  hive new --issue 123
+  hive new API Fix --subdir services/api
+  hive new Review Auth --review-of abc123 --spawn review
+  hive new Fix Auth Bug --dry-run`,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "remote",
 				Aliases:     []string{"r"},
-				Usage:       "git remote URL (defaults to current directory's origin)",
+				Usage:       "git remote URL or local repository path (defaults to current directory's origin)",
 				Destination: &cmd.remote,
 			},
 			&cli.StringFlag{
@@ -52,6 +119,62 @@ Example:
 				Usage:       "source directory for file copying (defaults to current directory)",
 				Destination: &cmd.source,
 			},
+			&cli.StringFlag{
+				Name:        "template",
+				Aliases:     []string{"t"},
+				Usage:       "render the session prompt from a configured template",
+				Destination: &cmd.template,
+			},
+			&cli.StringSliceFlag{
+				Name:        "set",
+				Usage:       "template field value as key=value (repeatable)",
+				Destination: &cmd.set,
+			},
+			&cli.BoolFlag{
+				Name:        "preview",
+				Usage:       "review and optionally edit the rendered prompt before creating the session",
+				Destination: &cmd.preview,
+			},
+			&cli.IntFlag{
+				Name:        "issue",
+				Usage:       "create the session for a GitHub or GitLab issue of the repository",
+				Destination: &cmd.issue,
+			},
+			&cli.StringFlag{
+				Name:        "spawn",
+				Usage:       "spawn profile from commands.spawn_profiles",
+				Destination: &cmd.spawn,
+			},
+			&cli.StringFlag{
+				Name:        "subdir",
+				Usage:       "working subdirectory within the repository, e.g. services/api",
+				Destination: &cmd.subdir,
+			},
+			&cli.StringFlag{
+				Name:        "review-of",
+				Usage:       "create a read-only review session over another session's directory",
+				Destination: &cmd.reviewOf,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "print what would be done without creating the session",
+				Destination: &cmd.dryRun,
+			},
+			&cli.BoolFlag{
+				Name:        "sibling",
+				Usage:       "inside a session, create another session of its remote without copying from the checkout",
+				Destination: &cmd.sibling,
+			},
+			&cli.BoolFlag{
+				Name:        "here-is-source",
+				Usage:       "inside a session, copy files from its checkout",
+				Destination: &cmd.hereIsSource,
+			},
+			&cli.BoolFlag{
+				Name:        "wait",
+				Usage:       "wait for a free slot instead of failing when max_active limits are reached",
+				Destination: &cmd.wait,
+			},
 		},
 		Action: cmd.run,
 	})
@@ -63,31 +186,335 @@ func (cmd *NewCmd) run(ctx context.Context, c *cli.Command) error {
 	p := printer.Ctx(ctx)
 
 	args := c.Args().Slice()
-	if len(args) == 0 {
+	if len(args) == 0 && cmd.issue == 0 {
 		return fmt.Errorf("session name required\n\nUsage: hive new <name...>\n\nExample: hive new Fix Auth Bug")
 	}
 	name := strings.Join(args, " ")
 
-	source := cmd.source
+	if cmd.reviewOf != "" && (cmd.dryRun || cmd.subdir != "" || cmd.remote != "" || cmd.issue != 0) {
+		return fmt.Errorf("--review-of cannot be combined with --dry-run, --subdir, --remote, or --issue")
+	}
+	if cmd.issue < 0 {
+		return fmt.Errorf("--issue must be a positive issue number")
+	}
+
+	if cmd.sibling && cmd.hereIsSource {
+		return fmt.Errorf("--sibling and --here-is-source cannot be combined")
+	}
+
+	remote, source := cmd.remote, cmd.source
 	if source == "" {
-		var err error
-		source, err = os.Getwd()
+		cwd, err := os.Getwd()
 		if err != nil {
 			return fmt.Errorf("determine source directory: %w", err)
 		}
+		source = cwd
+
+		if remote == "" && cmd.reviewOf == "" {
+			remote, source, err = cmd.fromSession(ctx, cwd)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if (cmd.sibling || cmd.hereIsSource) && remote == "" {
+		return fmt.Errorf("--sibling and --here-is-source only apply inside a session checkout")
+	}
+
+	var issue *hive.Issue
+	issueTemplate := ""
+	if cmd.issue > 0 {
+		resolved, err := cmd.flags.Service.ResolveRemote(ctx, remote)
+		if err != nil {
+			return err
+		}
+		fetched, err := cmd.flags.Service.FetchIssue(ctx, resolved, cmd.issue)
+		if err != nil {
+			return err
+		}
+		issue = &fetched
+		issueTemplate = cmd.flags.Config.IssueConfigFor(resolved).Template
+		if name == "" {
+			name = issue.SessionName()
+		}
+	}
+
+	prompt, err := cmd.prompt(ctx, name, remote, issue, issueTemplate)
+	if err != nil {
+		return err
 	}
 
 	opts := hive.CreateOptions{
-		Name:   name,
-		Remote: cmd.remote,
-		Source: source,
+		Name:            name,
+		Prompt:          prompt,
+		PromptPreambled: true,
+		Remote:          remote,
+		Source:          source,
+		UseBatchSpawn:   prompt != "",
+		SpawnProfile:    cmd.spawn,
+		Subdir:          cmd.subdir,
+		ReviewOf:        cmd.reviewOf,
+		Wait:            cmd.wait,
+	}
+	if issue != nil {
+		opts.IssueURL = issue.URL
+	}
+
+	if cmd.dryRun {
+		plans, err := cmd.flags.Service.PlanSessions(ctx, []hive.CreateOptions{opts})
+		if err != nil {
+			return fmt.Errorf("plan session: %w", err)
+		}
+		if p.IsJSON() {
+			return printer.EncodeJSON(c.Root().Writer, plans[0])
+		}
+		return printSessionPlan(c.Root().Writer, plans[0])
 	}
 
-	sess, err := cmd.flags.Service.CreateSession(ctx, opts)
+	sess, err := cmd.flags.Service.CreateSession(hive.WithStepReporter(ctx, &createProgress{p: p}), opts)
 	if err != nil {
 		return fmt.Errorf("create session: %w", err)
 	}
 
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, newSessionInfoOutput(*sess))
+	}
+
 	p.Success("Session created", sess.Path)
 	return nil
 }
+
+// prompt renders the --template prompt with the preambles of the rules for
+// remote, previewing it when requested by the flag or the template. For an
+// issue, the template defaults to the rule's issues.template and gets the
+// issue's fields, and without a template the prompt is the issue itself. It
+// returns an empty prompt when there is neither a template nor an issue.
+func (cmd *NewCmd) prompt(ctx context.Context, name, remote string, issue *hive.Issue, issueTemplate string) (string, error) {
+	tmplName := cmd.template
+	if tmplName == "" && issue != nil {
+		tmplName = issueTemplate
+	}
+
+	var prompt string
+	var t config.Template
+	switch {
+	case tmplName == "" && issue == nil:
+		if len(cmd.set) > 0 || cmd.preview {
+			return "", fmt.Errorf("--set and --preview require --template")
+		}
+		return "", nil
+	case tmplName == "":
+		if len(cmd.set) > 0 {
+			return "", fmt.Errorf("--set requires --template")
+		}
+		prompt = issue.Prompt()
+	default:
+		var err error
+		t, err = templates.Lookup(cmd.flags.Config.Templates, tmplName)
+		if err != nil {
+			return "", err
+		}
+
+		values, err := parseSetFlags(cmd.set)
+		if err != nil {
+			return "", err
+		}
+		if issue != nil {
+			// --set values override the issue's
+			issueValues := issue.TemplateValues(t.Fields)
+			maps.Copy(issueValues, values)
+			values = issueValues
+		}
+
+		t, err = templates.LoadOptions(ctx, &executil.RealExecutor{}, t)
+		if err != nil {
+			return "", err
+		}
+
+		prompt, err = templates.Render(t, values)
+		if err != nil {
+			return "", fmt.Errorf("template %q: %w", tmplName, err)
+		}
+	}
+
+	remote, err := cmd.promptRemote(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+	prompt = cmd.flags.Service.PromptFor(remote, prompt)
+
+	if !cmd.preview && !t.Preview {
+		return prompt, nil
+	}
+
+	preview, closeTTY, err := openPreview()
+	if err != nil {
+		if !cmd.preview {
+			// Template-level previews are skipped when run non-interactively.
+			return prompt, nil
+		}
+		return "", err
+	}
+	defer closeTTY()
+
+	prompt, ok, err := preview.confirm(ctx, name, prompt)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("session creation cancelled")
+	}
+	return prompt, nil
+}
+
+// promptRemote returns the remote whose rules' preambles go in front of the
+// prompt: that of the reviewed session with --review-of, else remote, which
+// is detected from the working directory when empty.
+func (cmd *NewCmd) promptRemote(ctx context.Context, remote string) (string, error) {
+	if cmd.reviewOf == "" {
+		return cmd.flags.Service.ResolveRemote(ctx, remote)
+	}
+	target, err := cmd.flags.Service.ResolveSession(ctx, cmd.reviewOf)
+	if err != nil {
+		return "", fmt.Errorf("get reviewed session: %w", err)
+	}
+	return target.Remote, nil
+}
+
+// printSessionPlan writes a human-readable session plan. A plan error is
+// returned after printing whatever was resolved.
+func printSessionPlan(out io.Writer, plan hive.SessionPlan) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Name:\t%s\n", plan.Name)
+	_, _ = fmt.Fprintf(w, "Remote:\t%s\n", plan.Remote)
+	if plan.Action == hive.PlanRecycle {
+		_, _ = fmt.Fprintf(w, "Action:\treuse recycled session %s\n", plan.RecycleFrom)
+	} else if plan.Action != "" {
+		_, _ = fmt.Fprintf(w, "Action:\t%s\n", plan.Action)
+	}
+	if plan.Path != "" {
+		_, _ = fmt.Fprintf(w, "Path:\t%s\n", plan.Path)
+	}
+	_ = w.Flush()
+
+	if plan.Prompt != "" {
+		_, _ = fmt.Fprintln(out, "\nPrompt")
+		for line := range strings.SplitSeq(plan.Prompt, "\n") {
+			_, _ = fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+
+	for _, rule := range plan.Rules {
+		pattern := rule.Pattern
+		if pattern == "" {
+			pattern = "(all repositories)"
+		}
+		if rule.Background {
+			pattern += " [background]"
+		}
+		_, _ = fmt.Fprintf(out, "\nRule %s\n", pattern)
+		printPlanList(out, "copy", rule.Copy)
+		printPlanList(out, "commands", rule.Commands)
+		printPlanList(out, "post_create", rule.PostCreate)
+	}
+
+	if len(plan.Spawn) > 0 {
+		_, _ = fmt.Fprintln(out, "\nSpawn")
+		for _, c := range plan.Spawn {
+			_, _ = fmt.Fprintf(out, "  %s\n", c)
+		}
+	}
+
+	if plan.Error != "" {
+		return errors.New(plan.Error)
+	}
+	return nil
+}
+
+// printPlanList writes a labelled, indented list, skipping empty lists.
+func printPlanList(out io.Writer, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(out, "  %s:\n", label)
+	for _, item := range items {
+		_, _ = fmt.Fprintf(out, "    %s\n", item)
+	}
+}
+
+// fromSession returns the remote and source for a session created from cwd
+// when cwd is inside the checkout of an active session: the session's remote,
+// with no source for --sibling or the checkout for --here-is-source. Without
+// either flag it is an error. Outside a session, both are empty and cwd is
+// the source.
+func (cmd *NewCmd) fromSession(ctx context.Context, cwd string) (remote, source string, err error) {
+	id, _ := cmd.flags.SessionDetector().DetectSessionFromPath(ctx, cwd)
+	if id == "" {
+		return "", cwd, nil
+	}
+	sess, err := cmd.flags.Store.Get(ctx, id)
+	if err != nil {
+		return "", cwd, nil
+	}
+	return sessionSource(sess, cwd, cmd.sibling, cmd.hereIsSource)
+}
+
+// sessionSource applies --sibling or --here-is-source to hive new run from
+// cwd inside the checkout of sess.
+func sessionSource(sess session.Session, cwd string, sibling, hereIsSource bool) (remote, source string, err error) {
+	switch {
+	case sibling:
+		return sess.Remote, "", nil
+	case hereIsSource:
+		return sess.Remote, cwd, nil
+	default:
+		return "", "", fmt.Errorf("%s is inside session %s (%s); pass --sibling to create another session of %s without copying from this checkout, or --here-is-source to copy files from it",
+			cwd, sess.Name, sess.ID, git.ExtractRepoName(sess.Remote))
+	}
+}
+
+// createProgress shows the steps of creating a session: waiting for a slot
+// and the clone or pull as a progress line with git's progress, and a header before the output of
+// copies, rule commands, and hooks.
+type createProgress struct {
+	p       *printer.Printer
+	current *printer.Progress
+}
+
+func (c *createProgress) StartStep(step, rule string) io.Writer {
+	switch step {
+	case session.StepWait:
+		c.current = c.p.Progress("Waiting for a free session slot")
+		return c.current
+	case session.StepClone:
+		c.current = c.p.Progress("Cloning repository")
+		return c.current
+	case session.StepPull:
+		c.current = c.p.Progress("Pulling recycled session")
+		return c.current
+	case session.StepCopy:
+		c.p.Infof("Copying files%s", ruleSuffix(rule))
+	case session.StepRuleCommands:
+		c.p.Infof("Running rule commands%s", ruleSuffix(rule))
+	case session.StepPostCreate:
+		c.p.Infof("Running post_create hooks%s", ruleSuffix(rule))
+	case session.StepSpawn:
+		c.p.Infof("Spawning terminal")
+	}
+	return nil
+}
+
+func (c *createProgress) EndStep(_, _ string, err error) {
+	if c.current != nil {
+		c.current.Done(err)
+		c.current = nil
+	}
+}
+
+// ruleSuffix names the rule a step runs for, unless it is the catch-all rule.
+func ruleSuffix(pattern string) string {
+	if pattern == "" {
+		return ""
+	}
+	return fmt.Sprintf(" for rule %q", pattern)
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintSessionPlan(t *testing.T) {
+	var buf bytes.Buffer
+	err := printSessionPlan(&buf, hive.SessionPlan{
+		Name:        "Fix Bug",
+		Remote:      "https://github.com/hay-kot/hive.git",
+		Action:      hive.PlanRecycle,
+		RecycleFrom: "rec1",
+		Path:        "/repos/hive-fix-bug-rec1",
+		Rules: []hive.RulePlan{
+			{Copy: []string{".envrc"}, Commands: []string{"npm install"}},
+			{Pattern: ".*/api", PostCreate: []string{"docker compose up -d"}, Background: true},
+		},
+		Spawn: []string{"tmux new-window"},
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "Action:  reuse recycled session rec1\n")
+	assert.Contains(t, out, "Rule (all repositories)\n  copy:\n    .envrc\n  commands:\n    npm install\n")
+	assert.Contains(t, out, "Rule .*/api [background]\n  post_create:\n    docker compose up -d\n")
+	assert.Contains(t, out, "Spawn\n  tmux new-window\n")
+
+	buf.Reset()
+	err = printSessionPlan(&buf, hive.SessionPlan{Name: "bad", Error: "detect remote: no origin"})
+	require.EqualError(t, err, "detect remote: no origin")
+	assert.Contains(t, buf.String(), "Name:    bad\n")
+}
+
+func TestSessionSource(t *testing.T) {
+	sess := session.Session{ID: "abc123", Name: "Fix Bug", Remote: "https://github.com/hay-kot/hive.git"}
+	const cwd = "/repos/hive-fix-bug-abc123/internal"
+
+	remote, source, err := sessionSource(sess, cwd, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, sess.Remote, remote)
+	assert.Empty(t, source, "siblings copy nothing from the checkout")
+
+	remote, source, err = sessionSource(sess, cwd, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, sess.Remote, remote)
+	assert.Equal(t, cwd, source)
+
+	_, _, err = sessionSource(sess, cwd, false, false)
+	require.ErrorContains(t, err, "inside session Fix Bug (abc123)")
+	assert.ErrorContains(t, err, "--sibling")
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/urfave/cli/v3"
+)
+
+type OpenCmd struct {
+	flags *Flags
+}
+
+// NewOpenCmd creates a new open command
+func NewOpenCmd(flags *Flags) *OpenCmd {
+	return &OpenCmd{flags: flags}
+}
+
+// Register adds the open command to the application
+func (cmd *OpenCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "open",
+		Usage:     "Open a session directory in an editor or file manager",
+		ArgsUsage: "<session-id|name>",
+		Description: `Opens the session directory with commands.open if configured, otherwise
+in $VISUAL or $EDITOR, then VS Code (code), then the system file manager.
+
+commands.open is a template with {{ .Path }}, {{ .Name }}, {{ .ID }}, and
+{{ .Remote }}. The TUI runs the same opener on the "o" key. Without a
+session, pick one interactively.
+
+Example:
+  hive open abc123
+  hive open fix-auth`,
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *OpenCmd) run(ctx context.Context, c *cli.Command) error {
+	ref, err := sessionArg(ctx, cmd.flags, c.Args().Slice(), "session ID required\n\nUsage: hive open <session-id|name>", isActive)
+	if err != nil {
+		return err
+	}
+
+	opener, err := cmd.flags.Service.OpenCommand(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	sh := exec.CommandContext(ctx, "sh", "-c", opener)
+	sh.Stdin = os.Stdin
+	sh.Stdout = os.Stdout
+	sh.Stderr = os.Stderr
+	if err := sh.Run(); err != nil {
+		return fmt.Errorf("run %q: %w", opener, err)
+	}
+	return nil
+}
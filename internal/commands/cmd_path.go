@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type PathCmd struct {
+	flags *Flags
+}
+
+// NewPathCmd creates a new path command
+func NewPathCmd(flags *Flags) *PathCmd {
+	return &PathCmd{flags: flags}
+}
+
+// Register adds the path command to the application
+func (cmd *PathCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "path",
+		Usage:     "Print a session's directory",
+		ArgsUsage: "<session-id|name>",
+		Description: `Prints only the path of a session, given by ID or name, for use in scripts
+and shell functions.
+
+For sessions created with --subdir, that subdirectory is printed. Without a
+session, pick one interactively. See 'hive shellenv' for an hcd
+function that changes into a session.
+
+Example:
+  cd "$(hive path fix-auth)"`,
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *PathCmd) run(ctx context.Context, c *cli.Command) error {
+	ref, err := sessionArg(ctx, cmd.flags, c.Args().Slice(), "session ID required\n\nUsage: hive path <session-id|name>", isActive)
+	if err != nil {
+		return err
+	}
+
+	sess, err := cmd.flags.Service.ResolveSession(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	out := c.Root().Writer
+	if printer.Ctx(ctx).IsJSON() {
+		return printer.EncodeJSON(out, pathOutput{ID: sess.ID, Path: sess.WorkDir(), Root: sess.Path})
+	}
+
+	_, _ = fmt.Fprintln(out, sess.WorkDir())
+	return nil
+}
+
+// pathOutput is the JSON output format for hive path.
+type pathOutput struct {
+	ID   string `json:"id"`
+	Path string `json:"path"`
+	Root string `json:"root"` // repository root; differs from path for --subdir sessions
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestPath(t *testing.T) {
+	flags := newServiceFlags(t, []session.Session{
+		{ID: "abc123", Name: "fix-auth", Slug: "fix-auth", Path: "/sessions/hive-fix-auth-abc123", State: session.StateActive},
+		{ID: "def456", Name: "api", Slug: "api", Path: "/sessions/mono-api-def456", State: session.StateActive, Metadata: map[string]string{session.MetaSubdir: "services/api"}},
+	})
+	ctx := printer.NewContext(context.Background(), printer.New(io.Discard))
+
+	for _, ref := range []string{"abc123", "fix-auth"} {
+		var buf bytes.Buffer
+		app := NewPathCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+		require.NoError(t, app.Run(ctx, []string{"hive", "path", ref}))
+		assert.Equal(t, "/sessions/hive-fix-auth-abc123\n", buf.String())
+	}
+
+	var buf bytes.Buffer
+	app := NewPathCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+	require.NoError(t, app.Run(ctx, []string{"hive", "path", "api"}))
+	assert.Equal(t, "/sessions/mono-api-def456/services/api\n", buf.String())
+
+	app = NewPathCmd(flags).Register(&cli.Command{Name: "hive", Writer: io.Discard})
+	require.ErrorIs(t, app.Run(ctx, []string{"hive", "path", "missing"}), session.ErrNotFound)
+}
@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+// pluginPrefix is the executable name prefix of plugins: hive-foo on PATH
+// runs as 'hive foo'.
+const pluginPrefix = "hive-"
+
+// Plugin is an external subcommand found on PATH.
+type Plugin struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Shadowed bool   `json:"shadowed,omitempty"` // a built-in command of the same name runs instead
+}
+
+type PluginsCmd struct {
+	flags *Flags
+
+	// flags
+	jsonOutput bool
+}
+
+// NewPluginsCmd creates a new plugins command.
+func NewPluginsCmd(flags *Flags) *PluginsCmd {
+	return &PluginsCmd{flags: flags}
+}
+
+// Register adds the plugins command to the application.
+func (cmd *PluginsCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "plugins",
+		Usage: "Manage external subcommands",
+		Description: `An executable named hive-<name> on PATH runs as 'hive <name>', with the
+remaining arguments passed through. Built-in commands take precedence.
+
+Plugins receive the resolved settings as HIVE_CONFIG, HIVE_DATA_DIR,
+HIVE_PROFILE, HIVE_OUTPUT, HIVE_QUIET, and HIVE_NO_COLOR, so 'hive' commands
+they run use the same config. HIVE_BIN is the path of the hive executable,
+HIVE_SESSION_ID the session the working directory belongs to, if any, and
+HIVE_CONTEXT all of it as a JSON object.`,
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List plugins found on PATH",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:        "json",
+						Usage:       "output as JSON",
+						Destination: &cmd.jsonOutput,
+					},
+				},
+				Action: cmd.runList,
+			},
+		},
+	})
+	return app
+}
+
+func (cmd *PluginsCmd) runList(ctx context.Context, c *cli.Command) error {
+	plugins := ListPlugins(os.Getenv("PATH"), builtinCommands(c.Root()))
+
+	if wantJSON(ctx, cmd.jsonOutput) {
+		if plugins == nil {
+			plugins = []Plugin{}
+		}
+		return printer.EncodeJSON(c.Root().Writer, plugins)
+	}
+
+	if len(plugins) == 0 {
+		printer.Ctx(ctx).Infof("No plugins found; add an executable named %s<name> to PATH", pluginPrefix)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tPATH")
+	for _, p := range plugins {
+		name := p.Name
+		if p.Shadowed {
+			name += " (shadowed by built-in)"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", name, p.Path)
+	}
+	return w.Flush()
+}
+
+// builtinCommands returns the names and aliases of root's subcommands.
+func builtinCommands(root *cli.Command) []string {
+	var names []string
+	for _, c := range root.Commands {
+		names = append(names, c.Names()...)
+	}
+	return names
+}
+
+// ListPlugins returns the plugins in the directories of path, sorted by
+// name. Like a shell, the first directory with a given plugin wins. Plugins
+// named like a builtin are marked shadowed.
+func ListPlugins(path string, builtins []string) []Plugin {
+	seen := make(map[string]bool)
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			full := filepath.Join(dir, e.Name())
+			if !isExecutable(full) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: full, Shadowed: slices.Contains(builtins, name)})
+		}
+	}
+	slices.SortFunc(plugins, func(a, b Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+// pluginName returns the subcommand name of an executable file name, or
+// false if it is not a plugin.
+func pluginName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	name, ok := strings.CutPrefix(file, pluginPrefix)
+	return name, ok && name != ""
+}
+
+// isExecutable reports whether path is a regular file the user may run.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// FindPlugin looks up the plugin for the subcommand name on PATH.
+func FindPlugin(name string) (Plugin, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return Plugin{}, false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+	return Plugin{Name: name, Path: path}, true
+}
+
+// pluginContext is the JSON object plugins receive in HIVE_CONTEXT.
+type pluginContext struct {
+	Bin         string `json:"bin"`
+	Config      string `json:"config"`
+	DataDir     string `json:"data_dir"`
+	Profile     string `json:"profile,omitempty"`
+	Output      string `json:"output"`
+	Quiet       bool   `json:"quiet"`
+	NoColor     bool   `json:"no_color"`
+	SessionID   string `json:"session_id,omitempty"`
+	SessionPath string `json:"session_path,omitempty"`
+}
+
+// RunPlugin runs p with args, connected to the terminal, and with the
+// resolved global settings in its environment. hive exits with the plugin's
+// exit code. The plugin is not killed when hive is interrupted: it gets the
+// terminal's signals itself and decides how to stop.
+func RunPlugin(ctx context.Context, flags *Flags, p Plugin, args []string) error {
+	env, err := pluginEnv(flags.pluginContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	c := exec.Command(p.Path, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = append(os.Environ(), env...)
+	err = c.Run()
+
+	// The plugin's own output explains the failure; just pass on its exit code
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return cli.Exit("", pluginExitCode(exitErr))
+	}
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// pluginExitCode returns the exit code of a plugin that failed, or 128 plus
+// the signal number if a signal ended it, as a shell reports it.
+func pluginExitCode(err *exec.ExitError) int {
+	if ws, ok := err.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return err.ExitCode()
+}
+
+// pluginContext returns the settings passed to plugins. The session is the
+// one the working directory belongs to, if any.
+func (f *Flags) pluginContext(ctx context.Context) pluginContext {
+	pc := pluginContext{
+		Config:  f.ConfigPath,
+		DataDir: f.DataDir,
+		Profile: f.Profile,
+		Output:  string(printer.FormatText),
+		Quiet:   f.Quiet,
+		NoColor: f.NoColor,
+	}
+	if printer.Ctx(ctx).IsJSON() {
+		pc.Output = string(printer.FormatJSON)
+	}
+	pc.Bin, _ = os.Executable()
+
+	if id, err := f.SessionDetector().DetectSession(ctx); err == nil && id != "" {
+		if sess, err := f.Store.Get(ctx, id); err == nil {
+			pc.SessionID, pc.SessionPath = sess.ID, sess.Path
+		}
+	}
+	return pc
+}
+
+// pluginEnv returns the HIVE_* variables for pc.
+func pluginEnv(pc pluginContext) ([]string, error) {
+	data, err := json.Marshal(pc)
+	if err != nil {
+		return nil, fmt.Errorf("encode plugin context: %w", err)
+	}
+	env := []string{
+		"HIVE_BIN=" + pc.Bin,
+		"HIVE_CONFIG=" + pc.Config,
+		"HIVE_DATA_DIR=" + pc.DataDir,
+		"HIVE_PROFILE=" + pc.Profile,
+		"HIVE_OUTPUT=" + pc.Output,
+		fmt.Sprintf("HIVE_QUIET=%t", pc.Quiet),
+		fmt.Sprintf("HIVE_NO_COLOR=%t", pc.NoColor),
+		"HIVE_CONTEXT=" + string(data),
+	}
+	// Leave a HIVE_SESSION_ID set by a hook alone when there is no session
+	if pc.SessionID != "" {
+		env = append(env, "HIVE_SESSION_ID="+pc.SessionID)
+	}
+	return env, nil
+}
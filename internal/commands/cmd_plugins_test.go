@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePlugin(t *testing.T, dir, file string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, file)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), mode))
+	return path
+}
+
+func TestListPlugins(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	foo := writePlugin(t, first, "hive-foo", 0o755)
+	writePlugin(t, second, "hive-foo", 0o755)
+	writePlugin(t, second, "hive-not-executable", 0o644)
+	writePlugin(t, second, "hive-", 0o755)
+	writePlugin(t, second, "other-tool", 0o755)
+	ls := writePlugin(t, second, "hive-ls", 0o755)
+
+	path := strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(os.PathListSeparator))
+	plugins := ListPlugins(path, []string{"ls", "new"})
+
+	assert.Equal(t, []Plugin{
+		{Name: "foo", Path: foo},
+		{Name: "ls", Path: ls, Shadowed: true},
+	}, plugins)
+}
+
+func TestFindPlugin(t *testing.T) {
+	dir := t.TempDir()
+	path := writePlugin(t, dir, "hive-foo", 0o755)
+	t.Setenv("PATH", dir)
+
+	p, ok := FindPlugin("foo")
+	require.True(t, ok)
+	assert.Equal(t, Plugin{Name: "foo", Path: path}, p)
+
+	_, ok = FindPlugin("bar")
+	assert.False(t, ok)
+
+	_, ok = FindPlugin("../foo")
+	assert.False(t, ok, "names with separators are not looked up")
+}
+
+func TestPluginEnv(t *testing.T) {
+	pc := pluginContext{Bin: "/usr/bin/hive", Config: "/cfg/config.yaml", DataDir: "/data", Output: "json", Quiet: true}
+
+	env, err := pluginEnv(pc)
+	require.NoError(t, err)
+	assert.Contains(t, env, "HIVE_CONFIG=/cfg/config.yaml")
+	assert.Contains(t, env, "HIVE_OUTPUT=json")
+	assert.Contains(t, env, "HIVE_QUIET=true")
+	for _, kv := range env {
+		assert.False(t, strings.HasPrefix(kv, "HIVE_SESSION_ID="), "no session leaves HIVE_SESSION_ID unset")
+	}
+
+	var got pluginContext
+	for _, kv := range env {
+		if data, ok := strings.CutPrefix(kv, "HIVE_CONTEXT="); ok {
+			require.NoError(t, json.Unmarshal([]byte(data), &got))
+		}
+	}
+	assert.Equal(t, pc, got)
+
+	pc.SessionID = "abc123"
+	env, err = pluginEnv(pc)
+	require.NoError(t, err)
+	assert.Contains(t, env, "HIVE_SESSION_ID=abc123")
+}
+
+func TestPluginExitCode(t *testing.T) {
+	tests := []struct {
+		script string
+		want   int
+	}{
+		{script: "exit 3", want: 3},
+		{script: "kill -TERM $$", want: 128 + int(syscall.SIGTERM)},
+	}
+	for _, tt := range tests {
+		err := exec.Command("sh", "-c", tt.script).Run()
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, tt.want, pluginExitCode(exitErr), tt.script)
+	}
+}
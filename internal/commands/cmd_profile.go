@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/urfave/cli/v3"
+)
+
+// defaultProfileName is displayed for the unnamed profile.
+const defaultProfileName = "default"
+
+type ProfileCmd struct {
+	flags *Flags
+
+	// flags
+	jsonOutput bool
+}
+
+// NewProfileCmd creates a new profile command.
+func NewProfileCmd(flags *Flags) *ProfileCmd {
+	return &ProfileCmd{flags: flags}
+}
+
+// Register adds the profile command to the application.
+func (cmd *ProfileCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "profile",
+		Usage: "Inspect configuration profiles",
+		Description: `Profiles keep separate config files and data directories for different setups.
+
+Select a profile with --profile <name> or HIVE_PROFILE=<name>. A profile uses:
+  config: $XDG_CONFIG_HOME/hive/profiles/<name>/config.yaml
+  data:   $XDG_DATA_HOME/hive/profiles/<name>/
+
+Explicit --config or --data-dir flags take precedence over the profile.`,
+		Commands: []*cli.Command{
+			cmd.listCmd(),
+			cmd.currentCmd(),
+		},
+	})
+	return app
+}
+
+func (cmd *ProfileCmd) listCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List available profiles",
+		Description: `Lists profiles that have a config file or data directory.
+
+The active profile is marked with '*'.`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.runList,
+	}
+}
+
+func (cmd *ProfileCmd) currentCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "current",
+		Usage: "Show the active profile and its paths",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.runCurrent,
+	}
+}
+
+// profileInfo is the JSON output format for profile commands.
+type profileInfo struct {
+	Name       string `json:"name"`
+	ConfigPath string `json:"config_path"`
+	DataDir    string `json:"data_dir"`
+	Active     bool   `json:"active"`
+}
+
+func (cmd *ProfileCmd) runList(ctx context.Context, c *cli.Command) error {
+	names, err := listProfiles()
+	if err != nil {
+		return err
+	}
+
+	// The active profile may not have been used yet, but is still listed.
+	active := cmd.activeName()
+	if active != defaultProfileName && !slices.Contains(names, active) {
+		names = append(names, active)
+		slices.Sort(names)
+	}
+
+	infos := make([]profileInfo, 0, len(names)+1)
+	for _, name := range append([]string{defaultProfileName}, names...) {
+		profile := name
+		if name == defaultProfileName {
+			profile = ""
+		}
+		infos = append(infos, profileInfo{
+			Name:       name,
+			ConfigPath: ProfileConfigPath(profile),
+			DataDir:    ProfileDataDir(profile),
+			Active:     name == active,
+		})
+	}
+
+	w := c.Root().Writer
+	if wantJSON(ctx, cmd.jsonOutput) {
+		return json.NewEncoder(w).Encode(infos)
+	}
+
+	for _, info := range infos {
+		marker := " "
+		if info.Active {
+			marker = "*"
+		}
+		_, _ = fmt.Fprintf(w, "%s %s\n", marker, info.Name)
+	}
+	return nil
+}
+
+func (cmd *ProfileCmd) runCurrent(ctx context.Context, c *cli.Command) error {
+	info := profileInfo{
+		Name:       cmd.activeName(),
+		ConfigPath: cmd.flags.ConfigPath,
+		DataDir:    cmd.flags.DataDir,
+		Active:     true,
+	}
+
+	w := c.Root().Writer
+	if wantJSON(ctx, cmd.jsonOutput) {
+		return json.NewEncoder(w).Encode(info)
+	}
+
+	_, _ = fmt.Fprintf(w, "Profile: %s\n", info.Name)
+	_, _ = fmt.Fprintf(w, "Config:  %s\n", info.ConfigPath)
+	_, _ = fmt.Fprintf(w, "Data:    %s\n", info.DataDir)
+	return nil
+}
+
+func (cmd *ProfileCmd) activeName() string {
+	if cmd.flags.Profile == "" {
+		return defaultProfileName
+	}
+	return cmd.flags.Profile
+}
+
+// listProfiles returns the sorted names of profiles found in either the
+// profiles config directory or the profiles data directory.
+func listProfiles() ([]string, error) {
+	dirs := []string{
+		profilesConfigDir(),
+		filepath.Join(DefaultDataDir(), "profiles"),
+	}
+
+	var names []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("read profiles directory: %w", err)
+		}
+
+		for _, e := range entries {
+			if !e.IsDir() || ValidateProfileName(e.Name()) != nil {
+				continue
+			}
+			if !slices.Contains(names, e.Name()) {
+				names = append(names, e.Name())
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
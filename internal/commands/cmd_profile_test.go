@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfilePaths(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	assert.Equal(t, DefaultConfigPath(), ProfileConfigPath(""))
+	assert.Equal(t, DefaultDataDir(), ProfileDataDir(""))
+	assert.Equal(t, filepath.Join(configHome, "hive", "profiles", "work", "config.yaml"), ProfileConfigPath("work"))
+	assert.Equal(t, filepath.Join(dataHome, "hive", "profiles", "work"), ProfileDataDir("work"))
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"work", "personal-2", "a_b"} {
+		assert.NoError(t, ValidateProfileName(name), name)
+	}
+	for _, name := range []string{"", "../etc", "with space", "-leading", "default", "Default"} {
+		assert.Error(t, ValidateProfileName(name), name)
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	configHome := t.TempDir()
+	dataHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	names, err := listProfiles()
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(configHome, "hive", "profiles", "work"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dataHome, "hive", "profiles", "personal"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dataHome, "hive", "profiles", "work"), 0o755))
+
+	names, err = listProfiles()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"personal", "work"}, names)
+}
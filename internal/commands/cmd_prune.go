@@ -3,13 +3,22 @@ package commands
 import (
 	"context"
 	"fmt"
+	"text/tabwriter"
 
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/internal/printer"
 	"github.com/urfave/cli/v3"
 )
 
 type PruneCmd struct {
 	flags *Flags
+
+	// flags
+	all       bool
+	dryRun    bool
+	repo      string
+	olderThan string
 }
 
 // NewPruneCmd creates a new prune command
@@ -22,21 +31,44 @@ func (cmd *PruneCmd) Register(app *cli.Command) *cli.Command {
 	app.Commands = append(app.Commands, &cli.Command{
 		Name:      "prune",
 		Usage:     "Remove recycled sessions exceeding max_recycled limit",
-		UsageText: "hive prune [--all]",
+		UsageText: "hive prune [--all] [--dry-run] [--repo owner/name] [--older-than 7d]",
 		Description: `Removes recycled sessions based on the max_recycled configuration.
 
 By default, keeps the newest N recycled sessions per repository (based on
-max_recycled config) and deletes the rest.
+max_recycled config) and deletes the rest. Corrupted sessions are always
+removed.
+
+Use --all to delete ALL recycled sessions regardless of the limit, or
+--older-than to also delete recycled sessions not used within a duration
+(e.g. 7d, 12h). --repo limits pruning to one repository.
 
-Use --all to delete ALL recycled sessions regardless of the limit.
+Use --dry-run to list what would be removed and the disk space it would free
+without deleting anything. With --json the report lists every session, its
+path, the reason it was selected, and its size in bytes.
 
 Active sessions are not affected.`,
 		Action: cmd.run,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
-				Name:    "all",
-				Aliases: []string{"a"},
-				Usage:   "Delete all recycled sessions (ignore max_recycled limit)",
+				Name:        "all",
+				Aliases:     []string{"a"},
+				Usage:       "Delete all recycled sessions (ignore max_recycled limit)",
+				Destination: &cmd.all,
+			},
+			&cli.BoolFlag{
+				Name:        "dry-run",
+				Usage:       "List sessions that would be pruned without deleting them",
+				Destination: &cmd.dryRun,
+			},
+			&cli.StringFlag{
+				Name:        "repo",
+				Usage:       "Only prune sessions for this repository (owner/name or name)",
+				Destination: &cmd.repo,
+			},
+			&cli.StringFlag{
+				Name:        "older-than",
+				Usage:       "Also prune recycled sessions unused for this long (e.g. 7d, 12h)",
+				Destination: &cmd.olderThan,
 			},
 		},
 	})
@@ -47,14 +79,26 @@ Active sessions are not affected.`,
 func (cmd *PruneCmd) run(ctx context.Context, c *cli.Command) error {
 	p := printer.Ctx(ctx)
 
-	all := c.Bool("all")
-	count, err := cmd.flags.Service.Prune(ctx, all)
+	opts := hive.PruneOptions{All: cmd.all, Repo: cmd.repo, DryRun: cmd.dryRun}
+	if cmd.olderThan != "" {
+		d, err := parseDuration(cmd.olderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+		opts.OlderThan = d
+	}
+
+	result, err := cmd.flags.Service.Prune(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("prune sessions: %w", err)
 	}
 
-	if count == 0 {
-		if all {
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, pruneOutput{Pruned: result.Pruned(), All: cmd.all, PruneResult: result})
+	}
+
+	if len(result.Sessions) == 0 {
+		if cmd.all {
 			p.Infof("No recycled sessions to prune")
 		} else {
 			p.Infof("No sessions exceed max_recycled limit")
@@ -62,7 +106,47 @@ func (cmd *PruneCmd) run(ctx context.Context, c *cli.Command) error {
 		return nil
 	}
 
-	p.Successf("Pruned %d session(s)", count)
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "REPO\tID\tREASON\tSIZE\tPATH")
+	for _, s := range result.Sessions {
+		reason := s.Reason
+		if s.Error != "" {
+			reason = "failed: " + s.Error
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", git.ExtractRepoName(s.Remote), s.ID, reason, formatBytes(s.Bytes), s.Path)
+	}
+	_ = w.Flush()
+
+	if result.DryRun {
+		p.Infof("Would prune %d session(s), freeing %s", len(result.Sessions), formatBytes(result.Reclaimed))
+		return nil
+	}
+
+	if failed := len(result.Sessions) - result.Pruned(); failed > 0 {
+		p.Warnf("Failed to prune %d session(s)", failed)
+	}
+	p.Successf("Pruned %d session(s), freed %s", result.Pruned(), formatBytes(result.Reclaimed))
 
 	return nil
 }
+
+// pruneOutput is the JSON output format for hive prune.
+type pruneOutput struct {
+	Pruned int  `json:"pruned"`
+	All    bool `json:"all"`
+	hive.PruneResult
+}
+
+// formatBytes formats a byte count with a binary unit, e.g. "1.5 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/urfave/cli/v3"
+)
+
+type ResumeCmd struct {
+	flags *Flags
+
+	// flags
+	spawn    string
+	noNotify bool
+}
+
+// NewResumeCmd creates a new resume command
+func NewResumeCmd(flags *Flags) *ResumeCmd {
+	return &ResumeCmd{flags: flags}
+}
+
+// Register adds the resume command to the application
+func (cmd *ResumeCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "resume",
+		Usage:     "Relaunch a session's agent with its prior context",
+		ArgsUsage: "<session-id|name>",
+		Description: `Relaunches the AI tool of an active session, for example after its terminal
+crashed. Runs commands.resume if configured, otherwise the commands the session
+was spawned with, with the stored {{.Prompt}} and the linked {{.ToolSession}}
+(see 'hive session link'). If no tool session is linked, the newest Claude
+Code transcript for the session directory is linked first.
+
+A "resumed" message is published to the session's inbox topic so
+collaborating agents know it is back. Use --no-notify to skip it.
+
+Example:
+  hive resume abc123
+  hive resume fix-auth --spawn shell
+
+Without a session, pick one interactively.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "spawn",
+				Usage:       "spawn profile from commands.spawn_profiles instead of commands.resume",
+				Destination: &cmd.spawn,
+			},
+			&cli.BoolFlag{
+				Name:        "no-notify",
+				Usage:       "do not publish a resumed message to the session's inbox",
+				Destination: &cmd.noNotify,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *ResumeCmd) run(ctx context.Context, c *cli.Command) error {
+	ref, err := sessionArg(ctx, cmd.flags, c.Args().Slice(), "session ID required\n\nUsage: hive resume <session-id|name>", isActive)
+	if err != nil {
+		return err
+	}
+
+	sess, err := cmd.flags.Service.ResumeSession(ctx, ref, cmd.spawn)
+	if err != nil {
+		return fmt.Errorf("resume session: %w", err)
+	}
+
+	p := printer.Ctx(ctx)
+	if !cmd.noNotify {
+		if err := cmd.notify(ctx, sess); err != nil {
+			p.Warnf("Failed to post resumed message: %v", err)
+		}
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, resumeOutput{
+			ID:          sess.ID,
+			ToolSession: sess.GetMeta(session.MetaToolSession),
+			Resumed:     true,
+		})
+	}
+
+	p.Success("Session resumed", sess.ID)
+	return nil
+}
+
+// notify publishes a resumed message to the session's inbox.
+func (cmd *ResumeCmd) notify(ctx context.Context, sess session.Session) error {
+	payload := fmt.Sprintf("Session %s (%s) resumed", sess.Name, sess.ID)
+	if tool := sess.GetMeta(session.MetaToolSession); tool != "" {
+		payload += " with tool session " + tool
+	}
+
+	msg := messaging.Message{
+		Topic:     sess.InboxTopic(),
+		Payload:   payload,
+		Sender:    "hive",
+		SessionID: sess.ID,
+	}
+	messaging.Stamp(&msg)
+	store := jsonfile.NewMsgStore(filepath.Join(cmd.flags.DataDir, "messages", "topics"))
+	if err := store.Publish(ctx, msg); err != nil {
+		return err
+	}
+	cmd.flags.Service.EmitMessage(msg)
+	cmd.flags.Service.RelayMessage(ctx, msg)
+	return nil
+}
+
+// resumeOutput is the JSON output format for hive resume.
+type resumeOutput struct {
+	ID          string `json:"id"`
+	ToolSession string `json:"tool_session,omitempty"`
+	Resumed     bool   `json:"resumed"`
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/internal/server"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+)
+
+// serveShutdownTimeout bounds how long in-flight requests get to finish.
+const serveShutdownTimeout = 5 * time.Second
+
+type ServeCmd struct {
+	flags *Flags
+
+	// flags
+	socket string
+	addr   string
+	token  string
+	cors   []string
+}
+
+// NewServeCmd creates a new serve command
+func NewServeCmd(flags *Flags) *ServeCmd {
+	return &ServeCmd{flags: flags}
+}
+
+// Register adds the serve command to the application
+func (cmd *ServeCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "serve",
+		Usage:     "Serve the hive API over a Unix socket or local HTTP",
+		UsageText: "hive serve [--socket path | --addr host:port] [--token token] [--cors-origin origin]",
+		Description: `Runs until interrupted, exposing sessions, messaging, and a shared
+key-value scratch space as a JSON HTTP API for editor extensions,
+dashboards, and agents.
+
+By default the API listens on a Unix socket in the data directory
+(hive.sock). Use --addr to listen on TCP instead, e.g. 127.0.0.1:7777.
+
+Every request must send the token as "Authorization: Bearer <token>" (or a
+?token= query parameter). Without --token, a token is generated once and
+kept in serve.token in the data directory.
+
+Endpoints:
+  GET    /v1/status
+  GET    /v1/sessions                  POST /v1/sessions
+  GET    /v1/sessions/{ref}            DELETE /v1/sessions/{ref}
+  GET    /v1/sessions/events           (server-sent events)
+  POST   /v1/sessions/{ref}/recycle
+  GET    /v1/topics
+  GET    /v1/topics/{topic}/messages   POST /v1/topics/{topic}/messages
+  GET    /v1/topics/{topic}/events     (server-sent events)
+  GET    /v1/kv                        GET|PUT|DELETE /v1/kv/{key}
+
+Use --cors-origin to let a status board served from another origin read the
+API from a browser. Cross-origin clients may only GET: writes from a page on
+any other origin are rejected with 403, even with the token.
+
+With messaging.heartbeat.interval set, each active session's terminal status
+and git summary is also published on session.<id>.heartbeat every interval,
+so orchestrators can spot dead or stuck workers by missing or unchanging
+heartbeats.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "socket",
+				Usage:       "Unix socket path (default: <data-dir>/hive.sock)",
+				Destination: &cmd.socket,
+			},
+			&cli.StringFlag{
+				Name:        "addr",
+				Usage:       "listen on TCP at host:port instead of a Unix socket",
+				Destination: &cmd.addr,
+			},
+			&cli.StringFlag{
+				Name:        "token",
+				Usage:       "token clients must present (default: generated, stored in <data-dir>/serve.token)",
+				Sources:     cli.EnvVars("HIVE_SERVE_TOKEN"),
+				Destination: &cmd.token,
+			},
+			&cli.StringSliceFlag{
+				Name:        "cors-origin",
+				Usage:       "browser origin allowed to read the API, e.g. http://localhost:3000, or * for any (repeatable)",
+				Destination: &cmd.cors,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *ServeCmd) run(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+	cfg := cmd.flags.Config
+
+	token := cmd.token
+	tokenFile := filepath.Join(cfg.DataDir, "serve.token")
+	if token == "" {
+		var err error
+		token, err = loadOrCreateToken(tokenFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	ln, where, err := cmd.listen(cfg.DataDir)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	limit := cfg.Messaging.RateLimit
+	msgStore := jsonfile.NewMsgStore(filepath.Join(cfg.DataDir, "messages", "topics")).WithRateLimit(limit.Messages, limit.Per)
+	logger := log.With().Str("component", "server").Logger()
+	api := server.New(cmd.flags.Service, msgStore, token, c.Root().Version, logger).WithCORS(cmd.cors)
+
+	srv := &http.Server{
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Request contexts end on shutdown, which closes event streams
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	p.Infof("Serving hive API on %s", where)
+	if cmd.token == "" {
+		p.Infof("Token: %s", tokenFile)
+	}
+
+	if interval := cfg.Messaging.Heartbeat.Interval; interval > 0 {
+		// Heartbeats come from hive itself, so they bypass the rate limit
+		hbStore := jsonfile.NewMsgStore(filepath.Join(cfg.DataDir, "messages", "topics"))
+		go cmd.flags.Service.RunHeartbeats(ctx, hbStore, newTerminalManager(cfg.Integrations.Terminal.Enabled), interval)
+		p.Infof("Publishing session heartbeats every %s", interval)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errCh; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
+
+// listen opens the TCP listener for --addr, or else the Unix socket, and
+// describes where it listens.
+func (cmd *ServeCmd) listen(dataDir string) (net.Listener, string, error) {
+	if cmd.addr != "" {
+		ln, err := net.Listen("tcp", cmd.addr)
+		if err != nil {
+			return nil, "", fmt.Errorf("listen: %w", err)
+		}
+		return ln, "http://" + ln.Addr().String(), nil
+	}
+
+	path := cmd.socket
+	if path == "" {
+		path = filepath.Join(dataDir, "hive.sock")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, "", fmt.Errorf("create socket directory: %w", err)
+	}
+
+	// A socket left by a crashed server is removed; a live one is an error
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return nil, "", fmt.Errorf("another hive serve is listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, "", fmt.Errorf("listen: %w", err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, "", fmt.Errorf("restrict socket permissions: %w", err)
+	}
+
+	return ln, "unix:" + path, nil
+}
+
+// loadOrCreateToken returns the token stored at path, generating and
+// storing one readable only by the user if there is none.
+func loadOrCreateToken(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("read token: %w", err)
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create data directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write token: %w", err)
+	}
+
+	return token, nil
+}
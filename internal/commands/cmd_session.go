@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/hay-kot/hive/internal/core/git"
-	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/internal/printer"
-	"github.com/hay-kot/hive/internal/store/jsonfile"
 	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
 )
 
 type SessionCmd struct {
@@ -18,6 +21,7 @@ type SessionCmd struct {
 
 	// flags
 	jsonOutput bool
+	session    string
 }
 
 // NewSessionCmd creates a new session command
@@ -35,6 +39,7 @@ func (cmd *SessionCmd) Register(app *cli.Command) *cli.Command {
 Use 'hive session info' to get details about the current session.`,
 		Commands: []*cli.Command{
 			cmd.infoCmd(),
+			cmd.linkCmd(),
 		},
 	})
 	return app
@@ -61,31 +66,94 @@ Example output (--json):
 	}
 }
 
+func (cmd *SessionCmd) linkCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "link",
+		Usage:     "Record the AI tool's session ID on the current session",
+		ArgsUsage: "[tool-session-id]",
+		Description: `Stores the AI tool's own session or conversation ID on a hive session, so
+spawn commands can resume it with {{ .ToolSession }} after the terminal is
+lost.
+
+The ID is taken from the argument, or else from a Claude Code hook payload
+({"session_id": ..., "cwd": ...}) on stdin, or else from the newest Claude
+Code transcript for the session directory. The session is detected from the
+working directory (or the payload's cwd) unless --session is given.
+
+Example, as a Claude Code SessionStart hook:
+  {"hooks": {"SessionStart": [{"hooks": [{"type": "command", "command": "hive session link"}]}]}}`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "session",
+				Usage:       "hive session ID or name (default: detected from the working directory)",
+				Destination: &cmd.session,
+			},
+		},
+		Action: cmd.runLink,
+	}
+}
+
 // sessionInfoOutput is the JSON output format for hive session info.
 type sessionInfoOutput struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	Repo   string `json:"repo"`
-	Remote string `json:"remote"`
-	Path   string `json:"path"`
-	Inbox  string `json:"inbox"`
-	State  string `json:"state"`
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Repo        string `json:"repo"`
+	Remote      string `json:"remote"`
+	Path        string `json:"path"`
+	Inbox       string `json:"inbox"`
+	State       string `json:"state"`
+	ToolSession string `json:"tool_session,omitempty"`
+	ReviewOf    string `json:"review_of,omitempty"` // reviewed session, for read-only review sessions
+	Note        string `json:"note,omitempty"`
+	IssueURL    string `json:"issue_url,omitempty"` // issue the session was created for
+
+	Timings []session.Timing `json:"timings,omitempty"` // steps of the session's creation
+}
+
+// newSessionInfoOutput builds the JSON description of a session, also used by
+// hive new.
+func newSessionInfoOutput(sess session.Session) sessionInfoOutput {
+	return sessionInfoOutput{
+		ID:          sess.ID,
+		Name:        sess.Name,
+		Repo:        git.ExtractRepoName(sess.Remote),
+		Remote:      sess.Remote,
+		Path:        sess.Path,
+		Inbox:       sess.InboxTopic(),
+		State:       string(sess.State),
+		ToolSession: sess.GetMeta(session.MetaToolSession),
+		ReviewOf:    sess.GetMeta(session.MetaReviewOf),
+		Note:        sess.Note(),
+		IssueURL:    sess.IssueURL(),
+		Timings:     sess.Timings,
+	}
+}
+
+// formatTimings lists creation steps with their durations, naming the rule
+// of rule steps, e.g. "clone 2.1s, rule_commands (.*) 8.4s".
+func formatTimings(timings []session.Timing) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		step := t.Step
+		if t.Rule != "" {
+			step += " (" + t.Rule + ")"
+		}
+		parts = append(parts, step+" "+t.Duration().String())
+	}
+	return strings.Join(parts, ", ")
 }
 
 func (cmd *SessionCmd) runInfo(ctx context.Context, c *cli.Command) error {
 	p := printer.Ctx(ctx)
 
 	// Detect session from current working directory
-	sessionsPath := filepath.Join(cmd.flags.DataDir, "sessions.json")
-	sessStore := jsonfile.New(sessionsPath)
-	detector := messaging.NewSessionDetector(sessStore)
-	sessionID, err := detector.DetectSession(ctx)
+	sessionID, err := cmd.flags.SessionDetector().DetectSession(ctx)
 	if err != nil {
 		return fmt.Errorf("detect session: %w", err)
 	}
 
 	if sessionID == "" {
-		if cmd.jsonOutput {
+		if wantJSON(ctx, cmd.jsonOutput) {
 			_, _ = fmt.Fprintln(c.Root().Writer, "{\"error\":\"not in a hive session\"}")
 			return nil
 		}
@@ -102,18 +170,9 @@ func (cmd *SessionCmd) runInfo(ctx context.Context, c *cli.Command) error {
 
 	out := c.Root().Writer
 
-	if cmd.jsonOutput {
-		info := sessionInfoOutput{
-			ID:     sess.ID,
-			Name:   sess.Name,
-			Repo:   git.ExtractRepoName(sess.Remote),
-			Remote: sess.Remote,
-			Path:   sess.Path,
-			Inbox:  sess.InboxTopic(),
-			State:  string(sess.State),
-		}
+	if wantJSON(ctx, cmd.jsonOutput) {
 		enc := json.NewEncoder(out)
-		return enc.Encode(info)
+		return enc.Encode(newSessionInfoOutput(sess))
 	}
 
 	// Human-readable output
@@ -123,6 +182,91 @@ func (cmd *SessionCmd) runInfo(ctx context.Context, c *cli.Command) error {
 	_, _ = fmt.Fprintf(out, "Inbox:       %s\n", sess.InboxTopic())
 	_, _ = fmt.Fprintf(out, "Path:        %s\n", sess.Path)
 	_, _ = fmt.Fprintf(out, "State:       %s\n", sess.State)
+	if reviewOf := sess.GetMeta(session.MetaReviewOf); reviewOf != "" {
+		_, _ = fmt.Fprintf(out, "Review of:   %s (read-only)\n", reviewOf)
+	}
+	if tool := sess.GetMeta(session.MetaToolSession); tool != "" {
+		_, _ = fmt.Fprintf(out, "Tool:        %s\n", tool)
+	}
+	if note := sess.Note(); note != "" {
+		_, _ = fmt.Fprintf(out, "Note:        %s\n", note)
+	}
+	if issue := sess.IssueURL(); issue != "" {
+		_, _ = fmt.Fprintf(out, "Issue:       %s\n", issue)
+	}
+	if len(sess.Timings) > 0 {
+		_, _ = fmt.Fprintf(out, "Created in:  %s\n", formatTimings(sess.Timings))
+	}
+
+	return nil
+}
 
+// hookPayload is the part of a Claude Code hook's stdin that hive session
+// link reads.
+type hookPayload struct {
+	SessionID string `json:"session_id"`
+	Cwd       string `json:"cwd"`
+}
+
+func (cmd *SessionCmd) runLink(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	toolSession := c.Args().First()
+	dir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get working directory: %w", err)
+	}
+
+	if toolSession == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+		data, err := io.ReadAll(io.LimitReader(os.Stdin, 1<<20))
+		if err != nil {
+			return fmt.Errorf("read stdin: %w", err)
+		}
+		var payload hookPayload
+		if len(data) > 0 {
+			if err := json.Unmarshal(data, &payload); err != nil {
+				return fmt.Errorf("decode hook payload: %w", err)
+			}
+		}
+		toolSession = payload.SessionID
+		if payload.Cwd != "" {
+			dir = payload.Cwd
+		}
+	}
+
+	ref := cmd.session
+	if ref == "" {
+		ref, err = cmd.flags.SessionDetector().DetectSessionFromPath(ctx, dir)
+		if err != nil {
+			return fmt.Errorf("detect session: %w", err)
+		}
+		if ref == "" {
+			return fmt.Errorf("not in a hive session; use --session")
+		}
+	}
+
+	if toolSession == "" {
+		sess, err := cmd.flags.Service.ResolveSession(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("get session: %w", err)
+		}
+		toolSession, err = hive.DetectToolSession(sess.WorkDir())
+		if err != nil {
+			return fmt.Errorf("detect tool session: %w", err)
+		}
+		if toolSession == "" {
+			return fmt.Errorf("no tool session ID given and no Claude Code transcript found for %s", sess.WorkDir())
+		}
+	}
+
+	sess, err := cmd.flags.Service.LinkToolSession(ctx, ref, toolSession)
+	if err != nil {
+		return err
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, newSessionInfoOutput(sess))
+	}
+	p.Successf("Linked tool session %s to %s", toolSession, sess.Name)
 	return nil
 }
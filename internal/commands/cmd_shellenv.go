@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+)
+
+// posixShellEnv defines hcd for bash and zsh.
+const posixShellEnv = `# hive shell integration
+hcd() {
+  if [ $# -gt 1 ]; then
+    echo "usage: hcd [session-id|name]" >&2
+    return 2
+  fi
+  local dir
+  dir="$(hive path "$@")" || return
+  cd "$dir"
+}
+`
+
+// fishShellEnv defines hcd for fish.
+const fishShellEnv = `# hive shell integration
+function hcd --description 'cd into a hive session'
+  if test (count $argv) -gt 1
+    echo "usage: hcd [session-id|name]" >&2
+    return 2
+  end
+  set -l dir (hive path $argv); or return
+  cd $dir
+end
+`
+
+type ShellEnvCmd struct {
+	flags *Flags
+
+	// flags
+	shell string
+}
+
+// NewShellEnvCmd creates a new shellenv command
+func NewShellEnvCmd(flags *Flags) *ShellEnvCmd {
+	return &ShellEnvCmd{flags: flags}
+}
+
+// Register adds the shellenv command to the application
+func (cmd *ShellEnvCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "shellenv",
+		Usage: "Print shell functions for navigating sessions",
+		Description: `Prints an hcd function that changes into a session's directory by ID or
+name. Add it to your shell config:
+
+  # bash / zsh
+  eval "$(hive shellenv)"
+
+  # fish
+  hive shellenv --shell fish | source
+
+Then run 'hcd fix-auth', or 'hcd' alone to pick a session.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "shell",
+				Usage:       "shell to emit functions for (bash, zsh, fish)",
+				Value:       "bash",
+				Destination: &cmd.shell,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *ShellEnvCmd) run(_ context.Context, c *cli.Command) error {
+	var script string
+	switch strings.ToLower(cmd.shell) {
+	case "bash", "zsh", "sh":
+		script = posixShellEnv
+	case "fish":
+		script = fishShellEnv
+	default:
+		return fmt.Errorf("unsupported shell %q (expected bash, zsh, or fish)", cmd.shell)
+	}
+
+	_, err := fmt.Fprint(c.Root().Writer, script)
+	return err
+}
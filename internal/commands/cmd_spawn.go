@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type SpawnCmd struct {
+	flags *Flags
+
+	// flags
+	spawn string
+}
+
+// NewSpawnCmd creates a new spawn command
+func NewSpawnCmd(flags *Flags) *SpawnCmd {
+	return &SpawnCmd{flags: flags}
+}
+
+// Register adds the spawn command to the application
+func (cmd *SpawnCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "spawn",
+		Usage:     "Spawn a terminal for an existing session",
+		ArgsUsage: "<session-id>",
+		Description: `Runs the spawn commands again for an active session, for example after
+its terminal was closed, without recreating the session.
+
+The session's prompt and spawn profile from when it was created are reused,
+so batch and templated sessions get batch_spawn with the same {{.Prompt}}.
+Use --spawn to launch a different profile from commands.spawn_profiles.
+
+Example:
+  hive spawn abc123
+  hive spawn abc123 --spawn shell
+
+Without a session ID, pick one interactively.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "spawn",
+				Usage:       "spawn profile from commands.spawn_profiles",
+				Destination: &cmd.spawn,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *SpawnCmd) run(ctx context.Context, c *cli.Command) error {
+	id, err := sessionArg(ctx, cmd.flags, c.Args().Slice(), "session ID required\n\nUsage: hive spawn <session-id>", isActive)
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.flags.Service.SpawnSession(ctx, id, cmd.spawn); err != nil {
+		return fmt.Errorf("spawn session: %w", err)
+	}
+
+	p := printer.Ctx(ctx)
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, spawnOutput{ID: id, Spawned: true})
+	}
+
+	p.Success("Session spawned", id)
+	return nil
+}
+
+// spawnOutput is the JSON output format for hive spawn.
+type spawnOutput struct {
+	ID      string `json:"id"`
+	Spawned bool   `json:"spawned"`
+}
@@ -0,0 +1,535 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/templates"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/urfave/cli/v3"
+)
+
+// maxTemplateSize limits downloaded template files.
+const maxTemplateSize = 1 << 20
+
+type TemplateCmd struct {
+	flags *Flags
+
+	// flags
+	jsonOutput bool
+	set        []string
+
+	// import flags
+	name  string
+	path  string
+	force bool
+}
+
+// NewTemplateCmd creates a new template command.
+func NewTemplateCmd(flags *Flags) *TemplateCmd {
+	return &TemplateCmd{flags: flags}
+}
+
+// Register adds the template command to the application.
+func (cmd *TemplateCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:  "template",
+		Usage: "Manage prompt templates",
+		Description: `Prompt templates are defined inline under "templates" in config.yaml or as
+one <name>.yaml file per template in templates_dir. Inline templates take
+precedence over files with the same name.`,
+		Commands: []*cli.Command{
+			cmd.listCmd(),
+			cmd.showCmd(),
+			cmd.renderCmd(),
+			cmd.importCmd(),
+		},
+	})
+	return app
+}
+
+func (cmd *TemplateCmd) listCmd() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List configured templates",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.runList,
+	}
+}
+
+func (cmd *TemplateCmd) showCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "show",
+		Usage:     "Show a template's fields and prompt",
+		ArgsUsage: "<name>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.runShow,
+	}
+}
+
+func (cmd *TemplateCmd) renderCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "render",
+		Usage:     "Render a template's prompt without creating a session",
+		ArgsUsage: "<name>",
+		Description: `Renders the prompt with field values from --set and prints it to stdout.
+
+Values are checked against the field types, required fields, and select
+options (running options_cmd when present), the same way sessions are.
+
+Example:
+  hive template render pr-review --set pr_number=123 --set focus=security`,
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:        "set",
+				Usage:       "field value as key=value (repeatable)",
+				Destination: &cmd.set,
+			},
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "output the prompt and resolved values as JSON",
+				Destination: &cmd.jsonOutput,
+			},
+		},
+		Action: cmd.runRender,
+	}
+}
+
+func (cmd *TemplateCmd) importCmd() *cli.Command {
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import templates from a file, URL, or git repository into templates_dir",
+		ArgsUsage: "<source>",
+		Description: `Copies shared templates into templates_dir so they are available by name.
+
+The source can be:
+  a local file     ./pr-review.yaml
+  an HTTP(S) URL   https://example.com/templates/pr-review.yaml
+  a git repository https://github.com/org/templates.git (or git@host:org/repo.git)
+
+For git sources every template file in the repository root (or --path) is
+imported. Each template is validated before it is written; existing files are
+only replaced with --force.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "name",
+				Usage:       "template name for a single file or URL (default: file name)",
+				Destination: &cmd.name,
+			},
+			&cli.StringFlag{
+				Name:        "path",
+				Usage:       "subdirectory of a git repository containing templates",
+				Destination: &cmd.path,
+			},
+			&cli.BoolFlag{
+				Name:        "force",
+				Aliases:     []string{"f"},
+				Usage:       "overwrite existing template files",
+				Destination: &cmd.force,
+			},
+		},
+		Action: cmd.runImport,
+	}
+}
+
+// templateInfo is the JSON output format for template list and show.
+type templateInfo struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Source      string              `json:"source,omitempty"`
+	Prompt      string              `json:"prompt,omitempty"`
+	Preview     bool                `json:"preview,omitempty"`
+	Fields      []templateFieldInfo `json:"fields"`
+}
+
+type templateFieldInfo struct {
+	Name       string   `json:"name"`
+	Label      string   `json:"label,omitempty"`
+	Type       string   `json:"type"`
+	Default    string   `json:"default,omitempty"`
+	Required   bool     `json:"required"`
+	Options    []string `json:"options,omitempty"`
+	OptionsCmd string   `json:"options_cmd,omitempty"`
+}
+
+func newTemplateInfo(name string, t config.Template, withPrompt bool) templateInfo {
+	info := templateInfo{
+		Name:        name,
+		Description: t.Description,
+		Source:      t.Source,
+		Preview:     t.Preview,
+		Fields:      make([]templateFieldInfo, len(t.Fields)),
+	}
+	if withPrompt {
+		info.Prompt = t.Prompt
+	}
+	for i, f := range t.Fields {
+		typ := f.Type
+		if typ == "" {
+			typ = config.FieldString
+		}
+		info.Fields[i] = templateFieldInfo{
+			Name:       f.Name,
+			Label:      f.Label,
+			Type:       typ,
+			Default:    f.Default,
+			Required:   f.Required,
+			Options:    f.Options,
+			OptionsCmd: f.OptionsCmd,
+		}
+	}
+	return info
+}
+
+func (cmd *TemplateCmd) runList(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+	defs := cmd.flags.Config.Templates
+
+	names := slices.Sorted(maps.Keys(defs))
+
+	out := c.Root().Writer
+	if wantJSON(ctx, cmd.jsonOutput) {
+		infos := make([]templateInfo, 0, len(names))
+		for _, name := range names {
+			infos = append(infos, newTemplateInfo(name, defs[name], false))
+		}
+		return json.NewEncoder(out).Encode(infos)
+	}
+
+	if len(names) == 0 {
+		p.Infof("No templates configured")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tFIELDS\tSOURCE\tDESCRIPTION")
+	for _, name := range names {
+		t := defs[name]
+		source := "config"
+		if t.Source != "" {
+			source = t.Source
+		}
+		fields := make([]string, len(t.Fields))
+		for i, f := range t.Fields {
+			fields[i] = f.Name
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, strings.Join(fields, ","), source, t.Description)
+	}
+	return w.Flush()
+}
+
+func (cmd *TemplateCmd) runShow(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one template name\n\nUsage: hive template show <name>")
+	}
+	name := c.Args().First()
+
+	t, err := templates.Lookup(cmd.flags.Config.Templates, name)
+	if err != nil {
+		return err
+	}
+	info := newTemplateInfo(name, t, true)
+
+	out := c.Root().Writer
+	if wantJSON(ctx, cmd.jsonOutput) {
+		return json.NewEncoder(out).Encode(info)
+	}
+
+	_, _ = fmt.Fprintf(out, "Name:        %s\n", info.Name)
+	if info.Description != "" {
+		_, _ = fmt.Fprintf(out, "Description: %s\n", info.Description)
+	}
+	source := "config"
+	if info.Source != "" {
+		source = info.Source
+	}
+	_, _ = fmt.Fprintf(out, "Source:      %s\n", source)
+
+	if len(info.Fields) > 0 {
+		_, _ = fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "FIELD\tTYPE\tREQUIRED\tDEFAULT\tOPTIONS")
+		for _, f := range info.Fields {
+			options := strings.Join(f.Options, ",")
+			if f.OptionsCmd != "" {
+				options = "$(" + f.OptionsCmd + ")"
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n", f.Name, f.Type, f.Required, f.Default, options)
+		}
+		_ = w.Flush()
+	}
+
+	_, _ = fmt.Fprintln(out)
+	_, _ = fmt.Fprintln(out, "Prompt:")
+	_, _ = fmt.Fprintln(out, info.Prompt)
+	return nil
+}
+
+// renderOutput is the JSON output format for template render.
+type renderOutput struct {
+	Template string         `json:"template"`
+	Prompt   string         `json:"prompt"`
+	Values   map[string]any `json:"values"`
+}
+
+func (cmd *TemplateCmd) runRender(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one template name\n\nUsage: hive template render <name> [--set key=value...]")
+	}
+	name := c.Args().First()
+
+	t, err := templates.Lookup(cmd.flags.Config.Templates, name)
+	if err != nil {
+		return err
+	}
+
+	values, err := parseSetFlags(cmd.set)
+	if err != nil {
+		return err
+	}
+
+	t, err = templates.LoadOptions(ctx, &executil.RealExecutor{}, t)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := templates.Resolve(t, values)
+	if err != nil {
+		return fmt.Errorf("template %q: %w", name, err)
+	}
+
+	prompt, err := templates.Render(t, values)
+	if err != nil {
+		return fmt.Errorf("template %q: %w", name, err)
+	}
+
+	out := c.Root().Writer
+	if wantJSON(ctx, cmd.jsonOutput) {
+		return json.NewEncoder(out).Encode(renderOutput{Template: name, Prompt: prompt, Values: resolved})
+	}
+
+	_, _ = fmt.Fprintln(out, prompt)
+	return nil
+}
+
+// parseSetFlags parses key=value pairs. Later values override earlier ones.
+func parseSetFlags(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func (cmd *TemplateCmd) runImport(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one source\n\nUsage: hive template import <source>")
+	}
+	source := c.Args().First()
+
+	dir := cmd.flags.Config.TemplatesPath(cmd.flags.ConfigPath)
+	if dir == "" {
+		return fmt.Errorf("templates_dir is not set in %s", cmd.flags.ConfigPath)
+	}
+
+	files, err := cmd.fetch(ctx, source)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no template files found in %s", source)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create templates dir: %w", err)
+	}
+
+	// Validate everything before writing anything
+	for _, f := range files {
+		t, err := config.ParseTemplateFile(f.data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+		if err := config.ValidateTemplate(f.name, t); err != nil {
+			return fmt.Errorf("template %q: %w", f.name, err)
+		}
+
+		dest := filepath.Join(dir, f.name+".yaml")
+		if _, err := os.Stat(dest); err == nil && !cmd.force {
+			return fmt.Errorf("template %q already exists at %s (use --force to overwrite)", f.name, dest)
+		}
+	}
+
+	for _, f := range files {
+		dest := filepath.Join(dir, f.name+".yaml")
+		if err := os.WriteFile(dest, f.data, 0o644); err != nil {
+			return fmt.Errorf("write template %q: %w", f.name, err)
+		}
+		p.Successf("Imported template %s → %s", f.name, dest)
+	}
+
+	return nil
+}
+
+// templateFile is a template fetched from an import source.
+type templateFile struct {
+	name string
+	data []byte
+}
+
+// fetch reads template files from source.
+func (cmd *TemplateCmd) fetch(ctx context.Context, source string) ([]templateFile, error) {
+	switch {
+	case isGitSource(source):
+		return cmd.fetchGit(ctx, source)
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		return cmd.fetchURL(ctx, source)
+	default:
+		data, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", source, err)
+		}
+		name, err := cmd.templateName(filepath.Base(source))
+		if err != nil {
+			return nil, err
+		}
+		return []templateFile{{name: name, data: data}}, nil
+	}
+}
+
+func (cmd *TemplateCmd) fetchURL(ctx context.Context, source string) ([]templateFile, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("parse URL: %w", err)
+	}
+	name, err := cmd.templateName(path.Base(u.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", source, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", source, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTemplateSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", source, err)
+	}
+	if len(data) > maxTemplateSize {
+		return nil, fmt.Errorf("download %s: template exceeds %d bytes", source, maxTemplateSize)
+	}
+
+	return []templateFile{{name: name, data: data}}, nil
+}
+
+func (cmd *TemplateCmd) fetchGit(ctx context.Context, source string) ([]templateFile, error) {
+	if cmd.name != "" {
+		return nil, fmt.Errorf("--name cannot be used with a git source")
+	}
+
+	tmp, err := os.MkdirTemp("", "hive-templates-*")
+	if err != nil {
+		return nil, fmt.Errorf("create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	repo := filepath.Join(tmp, "repo")
+	if err := cmd.flags.Service.Git().Clone(ctx, source, repo); err != nil {
+		return nil, fmt.Errorf("clone %s: %w", source, err)
+	}
+
+	dir := filepath.Join(repo, filepath.FromSlash(cmd.path))
+	if rel, err := filepath.Rel(repo, dir); err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("--path %q is outside the repository", cmd.path)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", cmd.path, err)
+	}
+
+	var files []templateFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name, ok := config.TemplateNameFromFile(entry.Name())
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+		}
+		files = append(files, templateFile{name: name, data: data})
+	}
+	return files, nil
+}
+
+// templateName returns --name if set, otherwise the name derived from file.
+func (cmd *TemplateCmd) templateName(file string) (string, error) {
+	if cmd.name != "" {
+		return cmd.name, nil
+	}
+	name, ok := config.TemplateNameFromFile(file)
+	if !ok {
+		return "", errors.New("cannot derive template name from source; use --name")
+	}
+	return name, nil
+}
+
+// isGitSource reports whether source refers to a git repository.
+func isGitSource(source string) bool {
+	return strings.HasSuffix(source, ".git") ||
+		strings.HasPrefix(source, "git@") ||
+		strings.HasPrefix(source, "ssh://") ||
+		strings.HasPrefix(source, "git://")
+}
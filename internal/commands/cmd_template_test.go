@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func runTemplateCmd(t *testing.T, flags *Flags, args ...string) error {
+	t.Helper()
+	app := NewTemplateCmd(flags).Register(&cli.Command{Name: "hive"})
+	return app.Run(context.Background(), append([]string{"hive", "template"}, args...))
+}
+
+func templateOutput(t *testing.T, flags *Flags, args ...string) (string, error) {
+	t.Helper()
+	var buf bytes.Buffer
+	app := NewTemplateCmd(flags).Register(&cli.Command{Name: "hive", Writer: &buf})
+	err := app.Run(context.Background(), append([]string{"hive", "template"}, args...))
+	return buf.String(), err
+}
+
+func reviewTemplateFlags() *Flags {
+	return &Flags{Config: &config.Config{
+		Templates: map[string]config.Template{
+			"review": {
+				Description: "Review a PR",
+				Prompt:      "Review PR #{{ .pr }} focusing on {{ .focus }}",
+				Fields: []config.TemplateField{
+					{Name: "pr", Type: config.FieldNumber, Required: true},
+					{Name: "focus", Type: config.FieldSelect, Options: []string{"security", "style"}, Default: "style"},
+				},
+			},
+			"audit": {Prompt: "Audit the repo", Source: "/templates/audit.yaml"},
+		},
+	}}
+}
+
+func TestTemplateList(t *testing.T) {
+	out, err := templateOutput(t, reviewTemplateFlags(), "list")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "NAME")
+	assert.Contains(t, lines[1], "/templates/audit.yaml")
+	assert.Contains(t, lines[2], "pr,focus")
+	assert.Contains(t, lines[2], "Review a PR")
+
+	out, err = templateOutput(t, reviewTemplateFlags(), "list", "--json")
+	require.NoError(t, err)
+
+	var infos []templateInfo
+	require.NoError(t, json.Unmarshal([]byte(out), &infos))
+	require.Len(t, infos, 2)
+	assert.Equal(t, "audit", infos[0].Name)
+	assert.Empty(t, infos[0].Prompt)
+	assert.Equal(t, "number", infos[1].Fields[0].Type)
+}
+
+func TestTemplateShow(t *testing.T) {
+	out, err := templateOutput(t, reviewTemplateFlags(), "show", "review")
+	require.NoError(t, err)
+	assert.Contains(t, out, "Review a PR")
+	assert.Contains(t, out, "security,style")
+	assert.Contains(t, out, "Review PR #{{ .pr }}")
+
+	_, err = templateOutput(t, reviewTemplateFlags(), "show", "missing")
+	require.Error(t, err)
+}
+
+func TestTemplateRender(t *testing.T) {
+	out, err := templateOutput(t, reviewTemplateFlags(), "render", "review", "--set", "pr=12", "--set", "focus=security")
+	require.NoError(t, err)
+	assert.Equal(t, "Review PR #12 focusing on security\n", out)
+
+	out, err = templateOutput(t, reviewTemplateFlags(), "render", "review", "--set", "pr=7", "--json")
+	require.NoError(t, err)
+
+	var got renderOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, "review", got.Template)
+	assert.Equal(t, "Review PR #7 focusing on style", got.Prompt)
+	assert.InDelta(t, 7, got.Values["pr"], 0)
+}
+
+func TestTemplateRender_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "missing required", args: nil, want: "pr"},
+		{name: "bad number", args: []string{"--set", "pr=abc"}, want: "pr"},
+		{name: "bad option", args: []string{"--set", "pr=1", "--set", "focus=speed"}, want: "focus"},
+		{name: "malformed set", args: []string{"--set", "pr"}, want: "expected key=value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"render", "review"}, tt.args...)
+			_, err := templateOutput(t, reviewTemplateFlags(), args...)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestTemplateImport_LocalFile(t *testing.T) {
+	root := t.TempDir()
+	flags := &Flags{
+		ConfigPath: filepath.Join(root, "config.yaml"),
+		Config:     &config.Config{TemplatesDir: "templates"},
+	}
+
+	src := filepath.Join(root, "review.yaml")
+	require.NoError(t, os.WriteFile(src, []byte("prompt: \"Review {{ .pr }}\"\nfields:\n  - name: pr\n"), 0o644))
+
+	require.NoError(t, runTemplateCmd(t, flags, "import", src))
+
+	data, err := os.ReadFile(filepath.Join(root, "templates", "review.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Review {{ .pr }}")
+
+	// Existing templates are not replaced without --force
+	err = runTemplateCmd(t, flags, "import", src)
+	require.ErrorContains(t, err, "already exists")
+
+	require.NoError(t, runTemplateCmd(t, flags, "import", "--force", "--name", "renamed", src))
+	assert.FileExists(t, filepath.Join(root, "templates", "renamed.yaml"))
+}
+
+func TestTemplateImport_RejectsInvalid(t *testing.T) {
+	root := t.TempDir()
+	flags := &Flags{
+		ConfigPath: filepath.Join(root, "config.yaml"),
+		Config:     &config.Config{TemplatesDir: "templates"},
+	}
+
+	src := filepath.Join(root, "bad.yaml")
+	require.NoError(t, os.WriteFile(src, []byte("prompt: \"{{ .missing }}\"\n"), 0o644))
+
+	err := runTemplateCmd(t, flags, "import", src)
+	require.ErrorContains(t, err, "unknown variable .missing")
+	assert.NoFileExists(t, filepath.Join(root, "templates", "bad.yaml"))
+}
+
+func TestTemplateImport_RequiresTemplatesDir(t *testing.T) {
+	flags := &Flags{Config: &config.Config{}}
+	err := runTemplateCmd(t, flags, "import", "x.yaml")
+	require.ErrorContains(t, err, "templates_dir is not set")
+}
+
+func TestIsGitSource(t *testing.T) {
+	assert.True(t, isGitSource("https://github.com/org/templates.git"))
+	assert.True(t, isGitSource("git@github.com:org/templates.git"))
+	assert.True(t, isGitSource("ssh://git@host/org/repo"))
+	assert.False(t, isGitSource("https://example.com/t/review.yaml"))
+	assert.False(t, isGitSource("./review.yaml"))
+}
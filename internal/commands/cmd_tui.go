@@ -18,6 +18,7 @@ import (
 
 type TuiCmd struct {
 	flags *Flags
+	ascii bool
 }
 
 // NewTuiCmd creates a new tui command
@@ -29,7 +30,14 @@ func NewTuiCmd(flags *Flags) *TuiCmd {
 
 // Flags returns the TUI-specific flags for registration on the root command
 func (cmd *TuiCmd) Flags() []cli.Flag {
-	return nil
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "ascii",
+			Usage:       "draw the session tree with ASCII glyphs, for fonts and terminals that lack the defaults",
+			Sources:     cli.EnvVars("HIVE_ASCII"),
+			Destination: &cmd.ascii,
+		},
+	}
 }
 
 // Run executes the TUI. Exported for use as default command.
@@ -43,24 +51,18 @@ func (cmd *TuiCmd) run(ctx context.Context, _ *cli.Command) error {
 
 	// Create message store for pub/sub events
 	topicsDir := filepath.Join(cmd.flags.DataDir, "messages", "topics")
-	msgStore := jsonfile.NewMsgStore(topicsDir)
-
-	// Create terminal integration manager if configured
-	var termMgr *terminal.Manager
-	if len(cmd.flags.Config.Integrations.Terminal.Enabled) > 0 {
-		termMgr = terminal.NewManager(cmd.flags.Config.Integrations.Terminal.Enabled)
-		// Register tmux integration
-		tmuxIntegration := tmux.New()
-		if tmuxIntegration.Available() {
-			termMgr.Register(tmuxIntegration)
-		}
-	}
+	limit := cmd.flags.Config.Messaging.RateLimit
+	msgStore := jsonfile.NewMsgStore(topicsDir).WithRateLimit(limit.Messages, limit.Per)
+
+	termMgr := newTerminalManager(cmd.flags.Config.Integrations.Terminal.Enabled)
 
 	for {
 		opts := tui.Options{
 			LocalRemote:     localRemote,
 			MsgStore:        msgStore,
 			TerminalManager: termMgr,
+			ConfigPath:      cmd.flags.ConfigPath,
+			ASCII:           cmd.ascii,
 		}
 
 		m := tui.New(cmd.flags.Service, cmd.flags.Config, opts)
@@ -94,3 +96,19 @@ func (cmd *TuiCmd) run(ctx context.Context, _ *cli.Command) error {
 
 	return nil
 }
+
+// newTerminalManager returns a terminal integration manager for the enabled
+// integrations, or nil if none are enabled.
+func newTerminalManager(enabled []string) *terminal.Manager {
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	mgr := terminal.NewManager(enabled)
+	// Register tmux integration
+	tmuxIntegration := tmux.New()
+	if tmuxIntegration.Available() {
+		mgr.Register(tmuxIntegration)
+	}
+	return mgr
+}
@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type UndeleteCmd struct {
+	flags *Flags
+
+	// flags
+	list bool
+}
+
+// NewUndeleteCmd creates a new undelete command
+func NewUndeleteCmd(flags *Flags) *UndeleteCmd {
+	return &UndeleteCmd{flags: flags}
+}
+
+// Register adds the undelete command to the application
+func (cmd *UndeleteCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "undelete",
+		Usage:     "Restore a deleted session from the trash",
+		ArgsUsage: "<session-id>",
+		Description: `Deleted sessions are moved to the trash under the data directory instead of
+being removed. They can be restored, directory and record, until
+trash.retention (default 7 days) passes; 'hive gc --remove' and later
+deletes clear expired entries. trash.retention: 0 turns the trash off.
+
+Recycled sessions are removed outright, since they hold no work.
+
+Without a session ID, or with --list, lists the trash.
+
+Example:
+  hive undelete --list
+  hive undelete abc123`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:        "list",
+				Aliases:     []string{"l"},
+				Usage:       "list deleted sessions that can be restored",
+				Destination: &cmd.list,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *UndeleteCmd) run(ctx context.Context, c *cli.Command) error {
+	if cmd.list || c.NArg() == 0 {
+		return cmd.runList(ctx, c)
+	}
+	if c.NArg() != 1 {
+		return fmt.Errorf("expected exactly one session ID\n\nUsage: hive undelete <session-id>")
+	}
+
+	p := printer.Ctx(ctx)
+
+	sess, err := cmd.flags.Service.RestoreSession(ctx, c.Args().First())
+	if err != nil {
+		return fmt.Errorf("undelete: %w", err)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, sess)
+	}
+
+	p.Success("Restored session "+sess.Name, sess.Path)
+	return nil
+}
+
+func (cmd *UndeleteCmd) runList(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	trash, err := cmd.flags.Service.Trash()
+	if err != nil {
+		return err
+	}
+
+	if p.IsJSON() {
+		if trash == nil {
+			trash = []hive.TrashedSession{}
+		}
+		return printer.EncodeJSON(c.Root().Writer, trash)
+	}
+
+	if len(trash) == 0 {
+		p.Infof("Trash is empty")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(c.Root().Writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ID\tNAME\tDELETED\tEXPIRES\tPATH")
+	for _, t := range trash {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			t.Session.ID, t.Session.Name,
+			t.DeletedAt.Local().Format(time.DateTime), t.ExpiresAt.Local().Format(time.DateTime),
+			t.Session.Path)
+	}
+	return w.Flush()
+}
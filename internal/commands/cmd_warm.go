@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hay-kot/hive/internal/printer"
+	"github.com/urfave/cli/v3"
+)
+
+type WarmCmd struct {
+	flags *Flags
+
+	// flags
+	repo  string
+	count int
+}
+
+// NewWarmCmd creates a new warm command
+func NewWarmCmd(flags *Flags) *WarmCmd {
+	return &WarmCmd{flags: flags}
+}
+
+// Register adds the warm command to the application
+func (cmd *WarmCmd) Register(app *cli.Command) *cli.Command {
+	app.Commands = append(app.Commands, &cli.Command{
+		Name:      "warm",
+		Usage:     "Pre-create recycled sessions so hive new does not have to clone",
+		UsageText: "hive warm [--repo owner/name] [--count 3]",
+		Description: `Keeps --count recycled sessions of a repository ready for hive new to reuse.
+
+Recycled sessions of the repository that already exist are brought up to date
+with git pull; ones that fail to pull are marked corrupted. The shortfall is
+cloned, and in each clone the commands of matching rules run, then the
+post_recycle hooks, so dependencies are installed before the session is
+needed. Running hive warm on a schedule keeps the pool fresh.
+
+The count is capped at the repository's max_recycled limit.
+
+--repo takes owner/name or name of a repository hive has sessions for, or a
+remote URL or local path. It defaults to the current directory's origin.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:        "repo",
+				Aliases:     []string{"r"},
+				Usage:       "repository to warm: owner/name, name, remote URL, or local path",
+				Destination: &cmd.repo,
+			},
+			&cli.IntFlag{
+				Name:        "count",
+				Aliases:     []string{"n"},
+				Usage:       "recycled sessions to keep ready",
+				Value:       1,
+				Destination: &cmd.count,
+			},
+		},
+		Action: cmd.run,
+	})
+
+	return app
+}
+
+func (cmd *WarmCmd) run(ctx context.Context, c *cli.Command) error {
+	p := printer.Ctx(ctx)
+
+	if cmd.count < 1 {
+		return fmt.Errorf("--count must be at least 1, got %d", cmd.count)
+	}
+
+	remote, err := cmd.flags.Service.RemoteForRepo(ctx, cmd.repo)
+	if err != nil {
+		return err
+	}
+
+	result, err := cmd.flags.Service.Warm(ctx, remote, cmd.count, cmd.flags.progressWriter())
+	if err != nil {
+		return fmt.Errorf("warm %s: %w", remote, err)
+	}
+
+	if p.IsJSON() {
+		return printer.EncodeJSON(c.Root().Writer, result)
+	}
+
+	if result.Limit > 0 && cmd.count > result.Limit {
+		p.Warnf("Capped at max_recycled (%d)", result.Limit)
+	}
+	if len(result.Corrupted) > 0 {
+		p.Warnf("Marked %d recycled session(s) corrupted after a failed pull", len(result.Corrupted))
+	}
+	p.Successf("%d recycled session(s) ready for %s (%d created, %d refreshed)",
+		len(result.Created)+len(result.Refreshed), remote, len(result.Created), len(result.Refreshed))
+	return nil
+}
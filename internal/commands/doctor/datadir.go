@@ -0,0 +1,92 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// maxClockSkew is the tolerated difference between the system clock and
+// file timestamps written to the data directory.
+const maxClockSkew = 5 * time.Second
+
+// DataDirCheck verifies the data directory is writable and that file
+// timestamps agree with the system clock.
+type DataDirCheck struct {
+	dataDir string
+	now     func() time.Time
+}
+
+// NewDataDirCheck creates a new data directory check.
+func NewDataDirCheck(dataDir string) *DataDirCheck {
+	return &DataDirCheck{
+		dataDir: dataDir,
+		now:     time.Now,
+	}
+}
+
+func (c *DataDirCheck) Name() string {
+	return "Data Directory"
+}
+
+func (c *DataDirCheck) Run(_ context.Context) Result {
+	result := Result{Name: c.Name()}
+
+	if err := os.MkdirAll(c.dataDir, 0o755); err != nil {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Writable",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("cannot create %s: %v", c.dataDir, err),
+		})
+		return result
+	}
+
+	f, err := os.CreateTemp(c.dataDir, ".doctor-*")
+	if err != nil {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Writable",
+			Status: StatusFail,
+			Detail: err.Error(),
+		})
+		return result
+	}
+	name := f.Name()
+	_ = f.Close()
+	defer func() { _ = os.Remove(name) }()
+
+	result.Items = append(result.Items, CheckItem{
+		Label:  "Writable",
+		Status: StatusPass,
+		Detail: c.dataDir,
+	})
+
+	info, err := os.Stat(name)
+	if err != nil {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Clock skew",
+			Status: StatusWarn,
+			Detail: err.Error(),
+		})
+		return result
+	}
+
+	skew := c.now().Sub(info.ModTime())
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Clock skew",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("file timestamps differ from system clock by %s", skew.Round(time.Second)),
+		})
+	} else {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Clock skew",
+			Status: StatusPass,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,33 @@
+package doctor
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataDirCheck(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+
+	result := NewDataDirCheck(dir).Run(context.Background())
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, StatusPass, result.Items[0].Status)
+	assert.Equal(t, StatusPass, result.Items[1].Status)
+
+	entries, err := filepath.Glob(filepath.Join(dir, ".doctor-*"))
+	require.NoError(t, err)
+	assert.Empty(t, entries, "probe file should be removed")
+}
+
+func TestDataDirCheck_ClockSkew(t *testing.T) {
+	check := NewDataDirCheck(t.TempDir())
+	check.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+	result := check.Run(context.Background())
+	require.Len(t, result.Items, 2)
+	assert.Equal(t, StatusWarn, result.Items[1].Status)
+}
@@ -0,0 +1,109 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// shellBuiltins are commands that may not exist as standalone executables.
+var shellBuiltins = []string{"cd", "exec", "export", "source", ".", "eval", "set", "true", "false", ":"}
+
+// EnvironmentCheck verifies that external tools used by hive are reachable.
+type EnvironmentCheck struct {
+	config   *config.Config
+	lookPath func(string) (string, error)
+}
+
+// NewEnvironmentCheck creates a new environment check.
+func NewEnvironmentCheck(cfg *config.Config) *EnvironmentCheck {
+	return &EnvironmentCheck{
+		config:   cfg,
+		lookPath: exec.LookPath,
+	}
+}
+
+func (c *EnvironmentCheck) Name() string {
+	return "Environment"
+}
+
+func (c *EnvironmentCheck) Run(_ context.Context) Result {
+	result := Result{Name: c.Name()}
+
+	for _, name := range c.config.Integrations.Terminal.Enabled {
+		result.Items = append(result.Items, c.checkExecutable("terminal: "+name, name))
+	}
+
+	result.Items = append(result.Items, c.dryRun("commands.spawn", c.config.Commands.Spawn, config.SpawnTemplateData{
+		Path: "/tmp/hive-doctor", Name: "doctor", Slug: "doctor", ContextDir: "/tmp/hive-doctor-ctx", Owner: "owner", Repo: "repo",
+	})...)
+	result.Items = append(result.Items, c.dryRun("commands.batch_spawn", c.config.Commands.BatchSpawn, config.BatchSpawnTemplateData{
+		Path: "/tmp/hive-doctor", Name: "doctor", Prompt: "doctor", Slug: "doctor", ContextDir: "/tmp/hive-doctor-ctx", Owner: "owner", Repo: "repo",
+	})...)
+
+	if len(result.Items) == 0 {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Spawn commands",
+			Status: StatusWarn,
+			Detail: "no spawn commands or terminal integrations configured",
+		})
+	}
+
+	return result
+}
+
+// checkExecutable reports whether program is on PATH.
+func (c *EnvironmentCheck) checkExecutable(label, program string) CheckItem {
+	path, err := c.lookPath(program)
+	if err != nil {
+		return CheckItem{
+			Label:  label,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s not found in PATH", program),
+		}
+	}
+	return CheckItem{Label: label, Status: StatusPass, Detail: path}
+}
+
+// dryRun renders each command with sample data and checks that the program
+// it invokes exists, without executing anything.
+func (c *EnvironmentCheck) dryRun(field string, commands []string, data any) []CheckItem {
+	items := make([]CheckItem, 0, len(commands))
+	for i, cmd := range commands {
+		label := fmt.Sprintf("%s[%d]", field, i)
+
+		rendered, err := tmpl.Render(cmd, data)
+		if err != nil {
+			items = append(items, CheckItem{Label: label, Status: StatusFail, Detail: err.Error()})
+			continue
+		}
+
+		program := commandProgram(rendered)
+		switch {
+		case program == "":
+			items = append(items, CheckItem{Label: label, Status: StatusWarn, Detail: "could not determine program"})
+		case slices.Contains(shellBuiltins, program):
+			items = append(items, CheckItem{Label: label, Status: StatusPass, Detail: "shell builtin " + program})
+		default:
+			items = append(items, c.checkExecutable(label, program))
+		}
+	}
+	return items
+}
+
+// commandProgram returns the program a shell command invokes, skipping
+// leading VAR=value assignments.
+func commandProgram(cmd string) string {
+	for _, field := range strings.Fields(cmd) {
+		if strings.Contains(field, "=") && !strings.HasPrefix(field, "=") {
+			continue
+		}
+		return strings.Trim(field, `"'`)
+	}
+	return ""
+}
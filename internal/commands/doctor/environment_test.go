@@ -0,0 +1,63 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeLookPath(available ...string) func(string) (string, error) {
+	return func(name string) (string, error) {
+		for _, a := range available {
+			if a == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestEnvironmentCheck(t *testing.T) {
+	cfg := &config.Config{
+		Commands: config.Commands{
+			Spawn:      []string{`wezterm cli spawn --cwd "{{ .Path }}"`, "cd {{ .Path }}"},
+			BatchSpawn: []string{`FOO=bar claude {{ .Prompt | shq }}`},
+		},
+		Integrations: config.IntegrationsConfig{
+			Terminal: config.TerminalConfig{Enabled: []string{"tmux"}},
+		},
+	}
+
+	check := NewEnvironmentCheck(cfg)
+	check.lookPath = fakeLookPath("wezterm", "claude")
+
+	result := check.Run(context.Background())
+	require.Len(t, result.Items, 4)
+
+	assert.Equal(t, "terminal: tmux", result.Items[0].Label)
+	assert.Equal(t, StatusFail, result.Items[0].Status)
+	assert.Equal(t, StatusPass, result.Items[1].Status, "wezterm on PATH")
+	assert.Equal(t, StatusPass, result.Items[2].Status, "cd is a builtin")
+	assert.Equal(t, StatusPass, result.Items[3].Status, "claude found after env assignment")
+}
+
+func TestEnvironmentCheck_InvalidTemplate(t *testing.T) {
+	cfg := &config.Config{Commands: config.Commands{Spawn: []string{"echo {{ .Missing }}"}}}
+
+	check := NewEnvironmentCheck(cfg)
+	check.lookPath = fakeLookPath("echo")
+
+	result := check.Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, StatusFail, result.Items[0].Status)
+}
+
+func TestEnvironmentCheck_NothingConfigured(t *testing.T) {
+	result := NewEnvironmentCheck(&config.Config{}).Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, StatusWarn, result.Items[0].Status)
+}
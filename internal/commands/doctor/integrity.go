@@ -0,0 +1,182 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hay-kot/hive/internal/store/backup"
+)
+
+// IntegrityCheck verifies that the JSON files in the data directory parse.
+// With fix, corrupt files are moved aside with a .corrupt suffix and, where
+// a backup holds a good copy, restored from the newest one.
+type IntegrityCheck struct {
+	dataDir string
+	backups *backup.Manager
+	fix     bool
+}
+
+// NewIntegrityCheck creates a new data integrity check. backups may be nil,
+// in which case corrupt files are only quarantined.
+func NewIntegrityCheck(dataDir string, backups *backup.Manager, fix bool) *IntegrityCheck {
+	return &IntegrityCheck{
+		dataDir: dataDir,
+		backups: backups,
+		fix:     fix,
+	}
+}
+
+func (c *IntegrityCheck) Name() string {
+	return "Data Integrity"
+}
+
+func (c *IntegrityCheck) Run(_ context.Context) Result {
+	result := Result{Name: c.Name()}
+
+	files, err := c.files()
+	if err != nil {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Read data directory",
+			Status: StatusFail,
+			Detail: err.Error(),
+		})
+		return result
+	}
+
+	corrupt := 0
+	for _, rel := range files {
+		parseErr := checkJSON(filepath.Join(c.dataDir, rel))
+		if parseErr == nil {
+			continue
+		}
+		corrupt++
+
+		if !c.fix {
+			result.Items = append(result.Items, CheckItem{
+				Label:   rel,
+				Status:  StatusFail,
+				Detail:  parseErr.Error(),
+				Fixable: true,
+			})
+			continue
+		}
+
+		result.Items = append(result.Items, c.repair(rel))
+	}
+
+	if corrupt == 0 {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Data files valid",
+			Status: StatusPass,
+			Detail: fmt.Sprintf("%d files checked", len(files)),
+		})
+	}
+
+	return result
+}
+
+// files lists the JSON files hive keeps in the data directory, relative to
+// it.
+func (c *IntegrityCheck) files() ([]string, error) {
+	var files []string
+	for _, name := range []string{"sessions.json", "history.json"} {
+		if _, err := os.Stat(filepath.Join(c.dataDir, name)); err == nil {
+			files = append(files, name)
+		}
+	}
+
+	for _, dir := range []string{filepath.Join("messages", "topics"), "batches"} {
+		matches, err := filepath.Glob(filepath.Join(c.dataDir, dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			files = append(files, filepath.Join(dir, filepath.Base(m)))
+		}
+	}
+
+	return files, nil
+}
+
+// repair quarantines a corrupt file, then restores it from a backup if one
+// has a valid copy.
+func (c *IntegrityCheck) repair(rel string) CheckItem {
+	path := filepath.Join(c.dataDir, rel)
+
+	quarantined, err := quarantine(path)
+	if err != nil {
+		return CheckItem{
+			Label:  rel,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("failed to quarantine: %v", err),
+		}
+	}
+	moved := fmt.Sprintf("moved to %s", filepath.Base(quarantined))
+
+	if c.backups == nil {
+		return CheckItem{Label: rel, Status: StatusPass, Detail: moved}
+	}
+
+	b, err := c.backups.RecoverJSON(rel)
+	switch {
+	case err == nil:
+		return CheckItem{
+			Label:  rel,
+			Status: StatusPass,
+			Detail: fmt.Sprintf("%s, restored from backup %s", moved, b.ID),
+		}
+	case errors.Is(err, backup.ErrNotFound):
+		return CheckItem{
+			Label:  rel,
+			Status: StatusWarn,
+			Detail: moved + ", no backup to restore from",
+		}
+	default:
+		return CheckItem{
+			Label:  rel,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s, restore failed: %v", moved, err),
+		}
+	}
+}
+
+// checkJSON returns an error if the file at path is not valid JSON. Empty
+// files are valid; the stores treat them as empty.
+func checkJSON(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
+
+// quarantine renames path to <path>.corrupt, numbering the suffix if an
+// earlier quarantined copy exists, and returns the new path.
+func quarantine(path string) (string, error) {
+	dst := path + ".corrupt"
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dst); os.IsNotExist(err) {
+			break
+		}
+		dst = fmt.Sprintf("%s.corrupt.%d", path, i)
+	}
+
+	if err := os.Rename(path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
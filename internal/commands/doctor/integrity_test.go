@@ -0,0 +1,58 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/store/backup"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrityCheck(t *testing.T) {
+	dataDir := t.TempDir()
+	topics := filepath.Join(dataDir, "messages", "topics")
+	require.NoError(t, os.MkdirAll(topics, 0o755))
+
+	write := func(rel, content string) {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dataDir, rel), []byte(content), 0o644))
+	}
+
+	write("sessions.json", `{"sessions":[{"id":"a"}]}`)
+	write("messages/topics/good.json", `{"name":"good"}`)
+	write("messages/topics/bad.json", `{"name":`)
+
+	result := NewIntegrityCheck(dataDir, nil, false).Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, filepath.Join("messages", "topics", "bad.json"), result.Items[0].Label)
+	assert.Equal(t, StatusFail, result.Items[0].Status)
+	assert.True(t, result.Items[0].Fixable)
+
+	// Back up the good sessions file, then corrupt it
+	backups := backup.New(dataDir, filepath.Join(dataDir, "backups"), 10)
+	_, err := backups.Create(context.Background(), backup.ReasonManual)
+	require.NoError(t, err)
+	write("sessions.json", "\x00\x00")
+
+	result = NewIntegrityCheck(dataDir, backups, true).Run(context.Background())
+	require.Len(t, result.Items, 2)
+
+	// sessions.json came back from the backup
+	assert.Equal(t, "sessions.json", result.Items[0].Label)
+	assert.Equal(t, StatusPass, result.Items[0].Status)
+	assert.Contains(t, result.Items[0].Detail, "restored from backup")
+	assert.FileExists(t, filepath.Join(dataDir, "sessions.json.corrupt"))
+	assert.NoError(t, checkJSON(filepath.Join(dataDir, "sessions.json")))
+
+	// bad.json was not in the backup, so it is only quarantined
+	assert.Equal(t, StatusWarn, result.Items[1].Status)
+	assert.FileExists(t, filepath.Join(topics, "bad.json.corrupt"))
+	assert.NoFileExists(t, filepath.Join(topics, "bad.json"))
+
+	result = NewIntegrityCheck(dataDir, backups, false).Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, StatusPass, result.Items[0].Status)
+}
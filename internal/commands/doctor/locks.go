@@ -0,0 +1,175 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// LockCheck detects message lock and temp files left behind without a topic.
+type LockCheck struct {
+	topicsDir string
+	fix       bool
+}
+
+// NewLockCheck creates a new stale lock file check.
+// If fix is true, stale files will be deleted.
+func NewLockCheck(topicsDir string, fix bool) *LockCheck {
+	return &LockCheck{
+		topicsDir: topicsDir,
+		fix:       fix,
+	}
+}
+
+func (c *LockCheck) Name() string {
+	return "Lock Files"
+}
+
+func (c *LockCheck) Run(_ context.Context) Result {
+	result := Result{Name: c.Name()}
+
+	entries, err := os.ReadDir(c.topicsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			result.Items = append(result.Items, CheckItem{
+				Label:  "No stale locks",
+				Status: StatusPass,
+				Detail: "no message topics yet",
+			})
+			return result
+		}
+		result.Items = append(result.Items, CheckItem{
+			Label:  "Read topics directory",
+			Status: StatusFail,
+			Detail: err.Error(),
+		})
+		return result
+	}
+
+	var stale []staleFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		path := filepath.Join(c.topicsDir, name)
+
+		switch {
+		case strings.HasSuffix(name, ".json.lock"):
+			topic := strings.TrimSuffix(path, ".lock")
+			f := staleFile{name: name, lock: path, stale: func() bool {
+				_, err := os.Stat(topic)
+				return errors.Is(err, os.ErrNotExist)
+			}}
+			if f.stale() && !isLockHeld(path) {
+				stale = append(stale, f)
+			}
+		case strings.HasSuffix(name, ".json.tmp"):
+			// The store writes <topic>.json.tmp under the topic's lock
+			lock := strings.TrimSuffix(path, ".tmp") + ".lock"
+			if !isLockHeld(lock) {
+				stale = append(stale, staleFile{name: name, lock: lock, stale: func() bool { return true }})
+			}
+		}
+	}
+
+	if len(stale) == 0 {
+		result.Items = append(result.Items, CheckItem{
+			Label:  "No stale locks",
+			Status: StatusPass,
+		})
+		return result
+	}
+
+	for _, f := range stale {
+		if !c.fix {
+			result.Items = append(result.Items, CheckItem{
+				Label:   f.name,
+				Status:  StatusWarn,
+				Detail:  "stale lock or temp file",
+				Fixable: true,
+			})
+			continue
+		}
+
+		removed, err := removeUnlocked(filepath.Join(c.topicsDir, f.name), f.lock, f.stale)
+		switch {
+		case err != nil:
+			result.Items = append(result.Items, CheckItem{
+				Label:  f.name,
+				Status: StatusFail,
+				Detail: fmt.Sprintf("failed to delete: %v", err),
+			})
+		case !removed:
+			result.Items = append(result.Items, CheckItem{
+				Label:  f.name,
+				Status: StatusPass,
+				Detail: "in use again, left in place",
+			})
+		default:
+			result.Items = append(result.Items, CheckItem{
+				Label:  f.name,
+				Status: StatusPass,
+				Detail: "deleted stale file",
+			})
+		}
+	}
+
+	return result
+}
+
+// staleFile is a lock or temp file in the topics directory that no process
+// appears to be using.
+type staleFile struct {
+	name  string      // file name in the topics directory
+	lock  string      // lock file guarding it; may be the file itself
+	stale func() bool // reports whether the file is still stale
+}
+
+// removeUnlocked removes path while holding an exclusive lock on lock, after
+// checking with stale once more, so a file a writer started using since it
+// was found is left alone. It reports false without error when the lock is
+// held or the file is no longer stale.
+func removeUnlocked(path, lock string, stale func() bool) (bool, error) {
+	f, err := os.OpenFile(lock, os.O_RDWR, 0)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		// Writers create the lock before anything else; without one nobody
+		// is using the file
+	case err != nil:
+		return false, err
+	default:
+		defer func() { _ = f.Close() }()
+		if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			return false, nil
+		}
+		defer func() { _ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN) }()
+	}
+
+	if !stale() {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
+}
+
+// isLockHeld reports whether another process currently holds a lock on path.
+func isLockHeld(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		return true
+	}
+	_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	return false
+}
@@ -0,0 +1,91 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockCheck_NoDirectory(t *testing.T) {
+	result := NewLockCheck(filepath.Join(t.TempDir(), "missing"), false).Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, StatusPass, result.Items[0].Status)
+}
+
+func TestLockCheck_StaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"live.json", "live.json.lock", "gone.json.lock", "crash.json.tmp"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	result := NewLockCheck(dir, false).Run(context.Background())
+	require.Len(t, result.Items, 2)
+	for _, item := range result.Items {
+		assert.Equal(t, StatusWarn, item.Status)
+		assert.True(t, item.Fixable)
+	}
+
+	result = NewLockCheck(dir, true).Run(context.Background())
+	require.Len(t, result.Items, 2)
+	for _, item := range result.Items {
+		assert.Equal(t, StatusPass, item.Status)
+	}
+
+	assert.FileExists(t, filepath.Join(dir, "live.json.lock"))
+	assert.NoFileExists(t, filepath.Join(dir, "gone.json.lock"))
+	assert.NoFileExists(t, filepath.Join(dir, "crash.json.tmp"))
+}
+
+func TestLockCheck_HeldLocks(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"busy.json.lock", "busy.json.tmp", "new.json.lock"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	// A writer holding a topic's lock owns its temp file, and a lock held
+	// for a topic that is not written yet is not stale
+	for _, name := range []string{"busy.json.lock", "new.json.lock"} {
+		f, err := os.Open(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.NoError(t, syscall.Flock(int(f.Fd()), syscall.LOCK_EX))
+		t.Cleanup(func() { _ = f.Close() })
+	}
+
+	result := NewLockCheck(dir, true).Run(context.Background())
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "No stale locks", result.Items[0].Label)
+	assert.FileExists(t, filepath.Join(dir, "busy.json.tmp"))
+	assert.FileExists(t, filepath.Join(dir, "new.json.lock"))
+}
+
+func TestRemoveUnlocked(t *testing.T) {
+	dir := t.TempDir()
+	tmp := filepath.Join(dir, "topic.json.tmp")
+	lock := filepath.Join(dir, "topic.json.lock")
+	require.NoError(t, os.WriteFile(tmp, nil, 0o644))
+	require.NoError(t, os.WriteFile(lock, nil, 0o644))
+
+	f, err := os.Open(lock)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Flock(int(f.Fd()), syscall.LOCK_EX))
+
+	removed, err := removeUnlocked(tmp, lock, func() bool { return true })
+	require.NoError(t, err)
+	assert.False(t, removed, "a file whose lock was taken since the scan is kept")
+	assert.FileExists(t, tmp)
+
+	require.NoError(t, f.Close())
+	removed, err = removeUnlocked(tmp, lock, func() bool { return false })
+	require.NoError(t, err)
+	assert.False(t, removed, "a file that is no longer stale is kept")
+
+	removed, err = removeUnlocked(tmp, lock, func() bool { return true })
+	require.NoError(t, err)
+	assert.True(t, removed)
+	assert.NoFileExists(t, tmp)
+}
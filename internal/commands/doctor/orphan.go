@@ -44,11 +44,6 @@ func (c *OrphanCheck) Run(ctx context.Context) Result {
 		return result
 	}
 
-	knownPaths := make(map[string]bool, len(sessions))
-	for _, sess := range sessions {
-		knownPaths[sess.Path] = true
-	}
-
 	// Check if repos directory exists
 	if _, err := os.Stat(c.reposDir); os.IsNotExist(err) {
 		result.Items = append(result.Items, CheckItem{
@@ -59,8 +54,7 @@ func (c *OrphanCheck) Run(ctx context.Context) Result {
 		return result
 	}
 
-	// List all directories in repos folder
-	entries, err := os.ReadDir(c.reposDir)
+	dirs, err := session.UnknownDirs(c.reposDir, sessions)
 	if err != nil {
 		result.Items = append(result.Items, CheckItem{
 			Label:  "Read repos directory",
@@ -71,15 +65,12 @@ func (c *OrphanCheck) Run(ctx context.Context) Result {
 	}
 
 	var orphans []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		dirPath := filepath.Join(c.reposDir, entry.Name())
-		if !knownPaths[dirPath] {
-			orphans = append(orphans, entry.Name())
+	for _, dir := range dirs {
+		name, err := filepath.Rel(c.reposDir, dir)
+		if err != nil {
+			name = dir
 		}
+		orphans = append(orphans, name)
 	}
 
 	if len(orphans) == 0 {
@@ -31,6 +31,11 @@ func (m *mockStore) Delete(_ context.Context, _ string) error {
 	return nil
 }
 
+func (m *mockStore) Find(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	sessions, err := m.List(ctx)
+	return f.Apply(sessions), err
+}
+
 func (m *mockStore) FindRecyclable(_ context.Context, _ string) (session.Session, error) {
 	return session.Session{}, nil
 }
@@ -1,12 +1,21 @@
 package commands
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
 	"github.com/hay-kot/hive/internal/core/session"
 	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/printer"
+	hiveapi "github.com/hay-kot/hive/pkg/hive"
 )
 
 type Flags struct {
@@ -14,6 +23,11 @@ type Flags struct {
 	LogFile    string
 	ConfigPath string
 	DataDir    string
+	Profile    string
+	Output     string // result format: text or json
+	JSON       bool   // shorthand for Output "json"
+	Quiet      bool   // suppress progress and informational output
+	NoColor    bool   // disable ANSI colors
 
 	// Config is loaded in the Before hook and available to all commands
 	Config *config.Config
@@ -23,24 +37,78 @@ type Flags struct {
 
 	// Store is the session store for direct access (used by doctor checks)
 	Store session.Store
+
+	detectorOnce sync.Once
+	detector     *messaging.SessionDetector
+}
+
+// SessionDetector returns the detector shared by all commands in this
+// process, so the working directory is resolved to a session only once.
+func (f *Flags) SessionDetector() *messaging.SessionDetector {
+	f.detectorOnce.Do(func() {
+		f.detector = messaging.NewSessionDetector(f.Store)
+	})
+	return f.detector
+}
+
+// progressWriter returns where streamed command output, such as recycle
+// progress, is written: stderr, or nowhere with --quiet.
+func (f *Flags) progressWriter() io.Writer {
+	if f.Quiet {
+		return io.Discard
+	}
+	return os.Stderr
+}
+
+// profileNameRe restricts profile names to characters that are safe in paths.
+var profileNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateProfileName returns an error if name cannot be used as a profile.
+// The name the unnamed profile is listed under is reserved.
+func ValidateProfileName(name string) error {
+	if !profileNameRe.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	if strings.EqualFold(name, defaultProfileName) {
+		return fmt.Errorf("invalid profile name %q: reserved for the unnamed profile; omit --profile to use it", name)
+	}
+	return nil
+}
+
+// ProfileConfigPath returns the config file path for a named profile.
+// An empty profile returns DefaultConfigPath.
+func ProfileConfigPath(profile string) string {
+	if profile == "" {
+		return DefaultConfigPath()
+	}
+	return filepath.Join(profilesConfigDir(), profile, "config.yaml")
+}
+
+// ProfileDataDir returns the data directory for a named profile.
+// An empty profile returns DefaultDataDir.
+func ProfileDataDir(profile string) string {
+	if profile == "" {
+		return DefaultDataDir()
+	}
+	return filepath.Join(DefaultDataDir(), "profiles", profile)
+}
+
+func profilesConfigDir() string {
+	return filepath.Join(filepath.Dir(DefaultConfigPath()), "profiles")
 }
 
 // DefaultConfigPath returns the default config file path using XDG_CONFIG_HOME.
 func DefaultConfigPath() string {
-	configHome := os.Getenv("XDG_CONFIG_HOME")
-	if configHome == "" {
-		home, _ := os.UserHomeDir()
-		configHome = filepath.Join(home, ".config")
-	}
-	return filepath.Join(configHome, "hive", "config.yaml")
+	return hiveapi.DefaultConfigPath()
 }
 
 // DefaultDataDir returns the default data directory using XDG_DATA_HOME.
 func DefaultDataDir() string {
-	dataHome := os.Getenv("XDG_DATA_HOME")
-	if dataHome == "" {
-		home, _ := os.UserHomeDir()
-		dataHome = filepath.Join(home, ".local", "share")
-	}
-	return filepath.Join(dataHome, "hive")
+	return hiveapi.DefaultDataDir()
+}
+
+// wantJSON reports whether a command should write JSON: either its own --json
+// flag or the global --json/--output json was given.
+func wantJSON(ctx context.Context, local bool) bool {
+	return local || printer.Ctx(ctx).IsJSON()
 }
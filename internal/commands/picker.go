@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/styles"
+	"golang.org/x/term"
+)
+
+// pickAllRepos is the picker value that widens a local list to all sessions.
+const pickAllRepos = "\x00all"
+
+// sessionArg returns the session reference given as the command's only
+// argument. Without one, the user picks a session interactively from those
+// that include accepts; usage is returned when there is no terminal to ask.
+func sessionArg(ctx context.Context, flags *Flags, args []string, usage string, include func(session.Session) bool) (string, error) {
+	switch len(args) {
+	case 1:
+		return args[0], nil
+	case 0:
+	default:
+		return "", errors.New(usage)
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return "", errors.New(usage)
+	}
+
+	sessions, err := flags.Service.ListSessions(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list sessions: %w", err)
+	}
+
+	var candidates []session.Session
+	for _, s := range sessions {
+		if include == nil || include(s) {
+			candidates = append(candidates, s)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no sessions to choose from")
+	}
+
+	var localRemote string
+	if dir, err := os.Getwd(); err == nil {
+		localRemote, _ = flags.Service.DetectRemote(ctx, dir)
+	}
+
+	id, err := runSessionPicker(pickerOptions(candidates, localRemote, false))
+	if err == nil && id == pickAllRepos {
+		id, err = runSessionPicker(pickerOptions(candidates, localRemote, true))
+	}
+	return id, err
+}
+
+// isActive reports whether s is an active session.
+func isActive(s session.Session) bool {
+	return s.State == session.StateActive
+}
+
+// sessionPicker is the title and options shown by runSessionPicker.
+type sessionPicker struct {
+	title   string
+	options []huh.Option[string]
+}
+
+// pickerOptions lists the sessions of the repository at localRemote, plus an
+// entry to show every repository, unless all is set or no session belongs
+// to that repository.
+func pickerOptions(sessions []session.Session, localRemote string, all bool) sessionPicker {
+	var local []session.Session
+	if !all && localRemote != "" {
+		for _, s := range sessions {
+			if s.Remote == localRemote {
+				local = append(local, s)
+			}
+		}
+	}
+
+	picker := sessionPicker{title: "Select a session"}
+	shown := sessions
+	if len(local) > 0 && len(local) < len(sessions) {
+		shown = local
+		picker.title = "Select a session in " + git.ExtractRepoName(localRemote)
+	}
+
+	for _, s := range shown {
+		label := fmt.Sprintf("%s  %s  %s", s.Name, s.ID, git.ExtractRepoName(s.Remote))
+		if s.State != session.StateActive {
+			label += "  (" + string(s.State) + ")"
+		}
+		picker.options = append(picker.options, huh.NewOption(label, s.ID))
+	}
+	if len(shown) < len(sessions) {
+		picker.options = append(picker.options, huh.NewOption("Show all repositories…", pickAllRepos))
+	}
+	return picker
+}
+
+// runSessionPicker shows a filterable list on stderr, keeping stdout clean
+// for the command's output, and returns the chosen session ID.
+func runSessionPicker(picker sessionPicker) (string, error) {
+	var id string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(picker.title).
+				Options(picker.options...).
+				Value(&id).
+				Filtering(true).
+				Height(min(len(picker.options)+2, 12)),
+		),
+	).WithTheme(styles.FormTheme()).WithOutput(os.Stderr)
+
+	if err := form.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", fmt.Errorf("aborted")
+		}
+		return "", fmt.Errorf("pick session: %w", err)
+	}
+	return id, nil
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickerOptions(t *testing.T) {
+	const local = "git@github.com:hay-kot/hive.git"
+	sessions := []session.Session{
+		{ID: "a1", Name: "alpha", Remote: local, State: session.StateActive},
+		{ID: "b2", Name: "bravo", Remote: "git@github.com:other/tool.git", State: session.StateActive},
+		{ID: "c3", Name: "charlie", Remote: local, State: session.StateRecycled},
+	}
+
+	values := func(p sessionPicker) []string {
+		var out []string
+		for _, o := range p.options {
+			out = append(out, o.Value)
+		}
+		return out
+	}
+
+	picker := pickerOptions(sessions, local, false)
+	assert.Equal(t, "Select a session in hive", picker.title)
+	assert.Equal(t, []string{"a1", "c3", pickAllRepos}, values(picker))
+	assert.Equal(t, "charlie  c3  hive  (recycled)", picker.options[1].Key)
+
+	picker = pickerOptions(sessions, local, true)
+	assert.Equal(t, "Select a session", picker.title)
+	assert.Equal(t, []string{"a1", "b2", "c3"}, values(picker))
+
+	// Outside a repository with sessions, everything is listed
+	assert.Equal(t, []string{"a1", "b2", "c3"}, values(pickerOptions(sessions, "git@github.com:x/y.git", false)))
+}
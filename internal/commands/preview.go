@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// errNoTerminal is returned when a prompt preview is requested but there is
+// no terminal to show it on.
+var errNoTerminal = errors.New("prompt preview requires an interactive terminal")
+
+// promptPreview shows a rendered prompt and asks whether to use it, edit it
+// in $EDITOR, or cancel.
+type promptPreview struct {
+	in   *bufio.Reader
+	out  io.Writer
+	show func(text string) // displays the prompt
+	edit func(ctx context.Context, text string) (string, error)
+}
+
+// openPreview attaches a preview to the controlling terminal. Stdin is used
+// when it is a terminal; otherwise (e.g. batch input piped on stdin) the
+// preview reads from /dev/tty. The returned close function must be called
+// when done.
+func openPreview() (*promptPreview, func(), error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		return newPromptPreview(os.Stdin), func() {}, nil
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, errNoTerminal
+	}
+	return newPromptPreview(tty), func() { _ = tty.Close() }, nil
+}
+
+func newPromptPreview(tty *os.File) *promptPreview {
+	return &promptPreview{
+		in:  bufio.NewReader(tty),
+		out: os.Stderr,
+		show: func(text string) {
+			showPrompt(tty, os.Stderr, text)
+		},
+		edit: func(ctx context.Context, text string) (string, error) {
+			return editText(ctx, tty, text)
+		},
+	}
+}
+
+// confirm shows the prompt for the named session until the user accepts or
+// cancels it. It returns the (possibly edited) prompt and false if the user
+// cancelled.
+func (p *promptPreview) confirm(ctx context.Context, name, prompt string) (string, bool, error) {
+	for {
+		_, _ = fmt.Fprintf(p.out, "\nPrompt for %q:\n\n", name)
+		p.show(prompt)
+		_, _ = fmt.Fprint(p.out, "\nCreate session? [Y]es / [e]dit / [n]o: ")
+
+		answer, err := p.in.ReadString('\n')
+		if err != nil && answer == "" {
+			return "", false, fmt.Errorf("read answer: %w", err)
+		}
+
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "", "y", "yes":
+			return prompt, true, nil
+		case "n", "no":
+			return "", false, nil
+		case "e", "edit":
+			edited, err := p.edit(ctx, prompt)
+			if err != nil {
+				return "", false, err
+			}
+			if strings.TrimSpace(edited) == "" {
+				_, _ = fmt.Fprintln(p.out, "Edited prompt is empty; keeping the previous prompt.")
+				continue
+			}
+			prompt = edited
+		default:
+			_, _ = fmt.Fprintf(p.out, "Unknown answer %q\n", strings.TrimSpace(answer))
+		}
+	}
+}
+
+// showPrompt writes text to out, paging it through $PAGER (default less)
+// when it is taller than the terminal.
+func showPrompt(tty *os.File, out io.Writer, text string) {
+	_, height, err := term.GetSize(int(tty.Fd()))
+	if err != nil || strings.Count(text, "\n")+1 < height-4 {
+		_, _ = fmt.Fprintln(out, text)
+		return
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+
+	c := exec.Command("sh", "-c", pager)
+	c.Stdin = strings.NewReader(text + "\n")
+	c.Stdout = out
+	c.Stderr = out
+	if err := c.Run(); err != nil {
+		_, _ = fmt.Fprintln(out, text)
+	}
+}
+
+// editText opens text in the user's editor ($VISUAL, then $EDITOR, then vi)
+// and returns the saved contents without the trailing newline.
+func editText(ctx context.Context, tty *os.File, text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	f, err := os.CreateTemp("", "hive-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("create prompt file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+
+	if _, err := f.WriteString(text + "\n"); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("write prompt file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write prompt file: %w", err)
+	}
+
+	// Run through the shell so editors with arguments (e.g. "code --wait") work.
+	c := exec.CommandContext(ctx, "sh", "-c", editor+` "$1"`, "sh", path)
+	c.Stdin = tty
+	c.Stdout = tty
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("run editor %q: %w", editor, err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read prompt file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\n"), nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPreview(input string, edits ...string) (*promptPreview, *bytes.Buffer) {
+	var out bytes.Buffer
+	return &promptPreview{
+		in:   bufio.NewReader(strings.NewReader(input)),
+		out:  &out,
+		show: func(text string) { out.WriteString(text + "\n") },
+		edit: func(context.Context, string) (string, error) {
+			next := edits[0]
+			edits = edits[1:]
+			return next, nil
+		},
+	}, &out
+}
+
+func TestPromptPreview_Confirm(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		edits  []string
+		want   string
+		wantOK bool
+	}{
+		{name: "accept default", input: "\n", want: "original", wantOK: true},
+		{name: "accept yes", input: "y\n", want: "original", wantOK: true},
+		{name: "decline", input: "n\n", wantOK: false},
+		{name: "edit then accept", input: "e\ny\n", edits: []string{"edited"}, want: "edited", wantOK: true},
+		{name: "empty edit keeps prompt", input: "e\ny\n", edits: []string{"  "}, want: "original", wantOK: true},
+		{name: "unknown answer asks again", input: "maybe\nyes\n", want: "original", wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, out := testPreview(tt.input, tt.edits...)
+
+			got, ok, err := p.confirm(context.Background(), "task", "original")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+			assert.Contains(t, out.String(), `Prompt for "task"`)
+		})
+	}
+}
+
+func TestPromptPreview_ConfirmEOF(t *testing.T) {
+	p, _ := testPreview("")
+
+	_, _, err := p.confirm(context.Background(), "task", "original")
+	require.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	hiveapi "github.com/hay-kot/hive/pkg/hive"
+)
+
+// OpenStore opens the session store selected by store.backend.
+func OpenStore(cfg *config.Config) (session.Store, error) {
+	return openBackend(cfg, cfg.Store.Backend)
+}
+
+// openBackend opens the named session store backend under cfg.DataDir.
+func openBackend(cfg *config.Config, backend string) (session.Store, error) {
+	return hiveapi.OpenStore(cfg, backend)
+}
+
+// CloseStore closes store if its backend holds resources, such as a
+// database handle.
+func CloseStore(store session.Store) {
+	_ = hiveapi.CloseStore(store)
+}
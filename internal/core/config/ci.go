@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/criterio"
+)
+
+// DefaultCITimeout is how long 'hive ci' waits for a status when the rule
+// sets no timeout.
+const DefaultCITimeout = 30 * time.Minute
+
+// CIConfig configures how 'hive ci' triggers CI for the repositories a rule
+// matches and where it listens for the result.
+type CIConfig struct {
+	// Trigger is the shell command template that starts CI, run in the
+	// session's working directory, e.g. "gh workflow run ci.yml --ref {{ .Branch }}".
+	Trigger string `yaml:"trigger,omitempty"`
+	// Topic is the message topic template CI reports its status on.
+	// Empty = agent.<session-id>.ci.
+	Topic string `yaml:"topic,omitempty"`
+	// Timeout bounds the wait for a final status. 0 = DefaultCITimeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+}
+
+// CIConfigFor returns the CI settings for the given remote URL. The last
+// matching rule with ci set wins; without one the trigger is empty.
+func (c *Config) CIConfigFor(remote string) CIConfig {
+	var result CIConfig
+	for _, rule := range c.Rules {
+		if rule.CI != nil && (rule.Pattern == "" || matchesPattern(rule.Pattern, remote)) {
+			result = *rule.CI
+		}
+	}
+	if result.Timeout == 0 {
+		result.Timeout = DefaultCITimeout
+	}
+	return result
+}
+
+// validateCI checks the CI settings of each rule. Template syntax is checked
+// by ValidateDeep.
+func (c *Config) validateCI() error {
+	var errs criterio.FieldErrorsBuilder
+	for i, rule := range c.Rules {
+		if rule.CI == nil {
+			continue
+		}
+		field := fmt.Sprintf("rules[%d].ci", i)
+
+		if strings.TrimSpace(rule.CI.Trigger) == "" {
+			errs = errs.Append(field+".trigger", fmt.Errorf("required"))
+		}
+		if rule.CI.Timeout < 0 {
+			errs = errs.Append(field+".timeout", fmt.Errorf("must not be negative"))
+		}
+	}
+	return errs.ToError()
+}
@@ -36,6 +36,21 @@ func ParseExitCondition(s string) bool {
 const (
 	ActionRecycle = "recycle"
 	ActionDelete  = "delete"
+	ActionOpen    = "open"
+	ActionPin     = "pin"
+	ActionEdit    = "edit"
+	// ActionRecycleBatch recycles every active session of the selected
+	// session's hive batch.
+	ActionRecycleBatch = "recycle-batch"
+	// ActionOpenURL opens the keybinding's url template in the browser.
+	ActionOpenURL = "open_url"
+	// Copy actions put the session's working directory, ID, or inbox topic
+	// on the clipboard.
+	ActionCopyPath       = "copy_path"
+	ActionCopyID         = "copy_id"
+	ActionCopyInboxTopic = "copy_inbox_topic"
+	// ActionCI runs the ci trigger of the session's matching rule.
+	ActionCI = "ci"
 )
 
 // defaultKeybindings provides built-in keybindings that users can override.
@@ -50,6 +65,24 @@ var defaultKeybindings = map[string]Keybinding{
 		Help:    "delete",
 		Confirm: "Are you sure you want to delete this session?",
 	},
+	"o": {
+		Action: ActionOpen,
+		Help:   "open",
+	},
+	"p": {
+		Action: ActionPin,
+		Help:   "pin",
+		Silent: true,
+	},
+	"e": {
+		Action: ActionEdit,
+		Help:   "edit",
+	},
+	"R": {
+		Action:  ActionRecycleBatch,
+		Help:    "recycle batch",
+		Confirm: "Are you sure you want to recycle every session in this batch?",
+	},
 }
 
 // CurrentConfigVersion is the latest config schema version.
@@ -58,20 +91,123 @@ const CurrentConfigVersion = "0.2.3"
 
 // Config holds the application configuration.
 type Config struct {
-	Version             string                `yaml:"version"`
-	Commands            Commands              `yaml:"commands"`
-	Git                 GitConfig             `yaml:"git"`
-	GitPath             string                `yaml:"git_path"`
-	Keybindings         map[string]Keybinding `yaml:"keybindings"`
-	Rules               []Rule                `yaml:"rules"`
-	AutoDeleteCorrupted bool                  `yaml:"auto_delete_corrupted"`
-	History             HistoryConfig         `yaml:"history"`
-	Context             ContextConfig         `yaml:"context"`
-	TUI                 TUIConfig             `yaml:"tui"`
-	Messaging           MessagingConfig       `yaml:"messaging"`
-	Integrations        IntegrationsConfig    `yaml:"integrations"`
-	RepoDirs            []string              `yaml:"repo_dirs"` // directories containing git repositories for new session dialog
-	DataDir             string                `yaml:"-"`         // set by caller, not from config file
+	Version             string                 `yaml:"version"`
+	Commands            Commands               `yaml:"commands"`
+	Git                 GitConfig              `yaml:"git"`
+	Batch               BatchConfig            `yaml:"batch"`
+	GitPath             string                 `yaml:"git_path"`
+	Keybindings         map[string]Keybinding  `yaml:"keybindings"`
+	Rules               []Rule                 `yaml:"rules"`
+	AutoDeleteCorrupted bool                   `yaml:"auto_delete_corrupted"`
+	MaxActiveSessions   int                    `yaml:"max_active_sessions"` // active sessions across all repos, 0 = unlimited
+	History             HistoryConfig          `yaml:"history"`
+	Context             ContextConfig          `yaml:"context"`
+	TUI                 TUIConfig              `yaml:"tui"`
+	Messaging           MessagingConfig        `yaml:"messaging"`
+	Integrations        IntegrationsConfig     `yaml:"integrations"`
+	RepoDirs            []string               `yaml:"repo_dirs"` // directories containing git repositories for new session dialog
+	Env                 map[string]SecretValue `yaml:"env"`       // environment for spawn, recycle, and rule commands
+	Secrets             SecretsConfig          `yaml:"secrets"`
+	Store               StoreConfig            `yaml:"store"`
+	Backups             BackupsConfig          `yaml:"backups"`
+	Trash               TrashConfig            `yaml:"trash"`
+	Paths               PathsConfig            `yaml:"paths"`
+	Notifications       []Notification         `yaml:"notifications"` // commands and webhooks run for recorded events
+	Hosts               map[string]HostConfig  `yaml:"hosts"`         // remote machines running hive, by name
+	Tracing             TracingConfig          `yaml:"tracing"`
+	Templates           map[string]Template    `yaml:"templates"`     // prompt templates referenced by batch sessions
+	TemplatesDir        string                 `yaml:"templates_dir"` // directory of <name>.yaml template files
+	DataDir             string                 `yaml:"-"`             // set by caller, not from config file
+}
+
+// BatchConfig holds batch session creation configuration.
+type BatchConfig struct {
+	Concurrency int `yaml:"concurrency"` // sessions created in parallel, default: 1
+	// MaxFailures is the number of failures after which remaining sessions are
+	// skipped. nil = default (3), 0 = never skip.
+	MaxFailures *int `yaml:"max_failures,omitempty"`
+}
+
+// DefaultBatchMaxFailures is the failure threshold when batch.max_failures is unset.
+const DefaultBatchMaxFailures = 3
+
+// FailureLimit returns the configured failure threshold, or
+// DefaultBatchMaxFailures if unset. Returns 0 for no limit.
+func (b BatchConfig) FailureLimit() int {
+	if b.MaxFailures != nil {
+		return *b.MaxFailures
+	}
+	return DefaultBatchMaxFailures
+}
+
+// Template is a reusable prompt with named input fields.
+type Template struct {
+	Description string          `yaml:"description"`
+	Prompt      string          `yaml:"prompt"` // Go template rendered with field values, e.g. {{ .pr_number }}
+	Fields      []TemplateField `yaml:"fields"`
+	Preview     bool            `yaml:"preview"` // confirm the rendered prompt before creating a session
+	Source      string          `yaml:"-"`       // file the template was loaded from, empty if inline
+}
+
+// TemplateField is a named value a template accepts.
+type TemplateField struct {
+	Name       string   `yaml:"name"`
+	Label      string   `yaml:"label"`
+	Type       string   `yaml:"type"` // string (default), number, bool, or select
+	Default    string   `yaml:"default"`
+	Required   bool     `yaml:"required"`
+	Options    []string `yaml:"options"`     // allowed values for select
+	OptionsCmd string   `yaml:"options_cmd"` // shell command whose output lists select options
+}
+
+// Template field types.
+const (
+	FieldString = "string"
+	FieldNumber = "number"
+	FieldBool   = "bool"
+	FieldSelect = "select"
+)
+
+// Parse converts a raw value to the field's type: int64 or float64 for
+// numbers, bool for booleans, and string otherwise. Select values must be
+// one of Options when options are known.
+func (f TemplateField) Parse(v string) (any, error) {
+	switch f.Type {
+	case FieldNumber:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n, nil
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", v)
+		}
+		return n, nil
+	case FieldBool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean (use true or false)", v)
+		}
+		return b, nil
+	case FieldSelect:
+		if len(f.Options) > 0 && !slices.Contains(f.Options, v) {
+			return nil, fmt.Errorf("%q is not one of: %s", v, strings.Join(f.Options, ", "))
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// Zero returns the value used for the field when it is left empty.
+func (f TemplateField) Zero() any {
+	switch f.Type {
+	case FieldNumber:
+		return int64(0)
+	case FieldBool:
+		return false
+	default:
+		return ""
+	}
 }
 
 // HistoryConfig holds command history configuration.
@@ -79,6 +215,65 @@ type HistoryConfig struct {
 	MaxEntries int `yaml:"max_entries"`
 }
 
+// StoreConfig selects the session store backend.
+type StoreConfig struct {
+	Backend string `yaml:"backend"` // json (default) or sqlite
+}
+
+// BackupsConfig configures automatic store backups.
+type BackupsConfig struct {
+	// Keep is the number of backups retained. nil = default (10), 0 disables
+	// automatic backups and rotation.
+	Keep *int `yaml:"keep,omitempty"`
+}
+
+// DefaultBackupsKeep is the number of backups retained when backups.keep is unset.
+const DefaultBackupsKeep = 10
+
+// KeepLimit returns the configured number of backups to keep, or
+// DefaultBackupsKeep if unset.
+func (b BackupsConfig) KeepLimit() int {
+	if b.Keep != nil {
+		return *b.Keep
+	}
+	return DefaultBackupsKeep
+}
+
+// TrashConfig configures where deleted sessions are held before removal.
+type TrashConfig struct {
+	// Retention is how long a deleted session can be restored with
+	// "hive undelete". nil = default (7 days), 0 removes sessions immediately.
+	Retention *time.Duration `yaml:"retention,omitempty"`
+}
+
+// DefaultTrashRetention is how long deleted sessions are kept when
+// trash.retention is unset.
+const DefaultTrashRetention = 7 * 24 * time.Hour
+
+// RetentionPeriod returns the configured trash retention, or
+// DefaultTrashRetention if unset. Returns 0 when the trash is disabled.
+func (t TrashConfig) RetentionPeriod() time.Duration {
+	if t.Retention != nil {
+		return *t.Retention
+	}
+	return DefaultTrashRetention
+}
+
+// TracingConfig configures OpenTelemetry tracing of session creation and
+// recycling. Spans are exported over OTLP/HTTP.
+type TracingConfig struct {
+	// Endpoint is the collector host:port, e.g. "localhost:4318". When empty,
+	// tracing is enabled only by the standard OTEL_EXPORTER_OTLP_* variables.
+	Endpoint string `yaml:"endpoint"`
+	Insecure bool   `yaml:"insecure"` // use http instead of https
+}
+
+// Session store backends.
+const (
+	StoreJSON   = "json"
+	StoreSQLite = "sqlite"
+)
+
 // ContextConfig configures context directory behavior.
 type ContextConfig struct {
 	SymlinkName string `yaml:"symlink_name"` // default: ".hive"
@@ -86,12 +281,42 @@ type ContextConfig struct {
 
 // TUIConfig holds TUI-related configuration.
 type TUIConfig struct {
-	RefreshInterval time.Duration `yaml:"refresh_interval"` // default: 15s, 0 to disable
+	RefreshInterval time.Duration     `yaml:"refresh_interval"` // default: 15s, 0 to disable
+	GroupBy         string            `yaml:"group_by"`         // default: "repo"
+	Icons           map[string]string `yaml:"icons"`            // glyph overrides by name, see TUIIconNames
+	ShowActivity    bool              `yaml:"show_activity"`    // default: false, last terminal output line beside each session
 }
 
+// TUIIconNames are the glyph names tui.icons can override.
+var TUIIconNames = []string{
+	"active", "approval", "ready", "unknown", "recycled",
+	"current_repo", "pinned", "expanded", "collapsed", "branch", "last",
+}
+
+// Session tree groupings for tui.group_by.
+const (
+	GroupByRepo  = "repo"
+	GroupByOwner = "owner"
+)
+
 // MessagingConfig holds messaging-related configuration.
 type MessagingConfig struct {
-	TopicPrefix string `yaml:"topic_prefix"` // default: "agent"
+	TopicPrefix string                    `yaml:"topic_prefix"` // default: "agent"
+	Snippets    map[string]MessageSnippet `yaml:"snippets"`     // payloads offered by the TUI message composer
+	RateLimit   RateLimitConfig           `yaml:"rate_limit"`
+	Heartbeat   HeartbeatConfig           `yaml:"heartbeat"`
+}
+
+// HeartbeatConfig controls the session heartbeats published by hive serve.
+type HeartbeatConfig struct {
+	Interval time.Duration `yaml:"interval"` // default: 0 (no heartbeats)
+}
+
+// RateLimitConfig caps how many messages one sender may publish within a
+// window, across all topics.
+type RateLimitConfig struct {
+	Messages int           `yaml:"messages"` // default: 0 (no limit)
+	Per      time.Duration `yaml:"per"`      // default: 1m
 }
 
 // IntegrationsConfig holds configuration for external integrations.
@@ -113,6 +338,15 @@ func (t TerminalConfig) IsEnabled(name string) bool {
 // GitConfig holds git-related configuration.
 type GitConfig struct {
 	StatusWorkers int `yaml:"status_workers"`
+	// StatusCacheTTL is how long the TUI reuses a session's git status while
+	// its repository is unchanged. default: 30s, 0 to always re-run git.
+	StatusCacheTTL time.Duration `yaml:"status_cache_ttl"`
+	// Retries is how many times a failed clone, fetch, pull, or push is
+	// retried. default: 2, 0 to fail on the first error.
+	Retries int `yaml:"retries"`
+	// RetryDelay is the wait before the first retry, doubled for each
+	// retry after it. default: 1s
+	RetryDelay time.Duration `yaml:"retry_delay"`
 }
 
 // Rule defines actions to take for matching repositories.
@@ -121,27 +355,109 @@ type Rule struct {
 	Pattern string `yaml:"pattern"`
 	// Commands to run in the session directory after clone/recycle.
 	Commands []string `yaml:"commands,omitempty"`
-	// Copy are glob patterns to copy from source directory.
-	Copy []string `yaml:"copy,omitempty"`
+	// Copy lists files to copy from the source directory, as globs or
+	// from/to entries.
+	Copy []CopySpec `yaml:"copy,omitempty"`
+	// MaxFileSize skips copied files larger than this. 0 = no limit.
+	MaxFileSize ByteSize `yaml:"max_file_size,omitempty"`
 	// MaxRecycled sets the max recycled sessions for matching repos.
 	// nil = inherit from previous rule or default (5), 0 = unlimited, >0 = limit
 	MaxRecycled *int `yaml:"max_recycled,omitempty"`
+	// MaxActive caps the active sessions of each matching repo.
+	// nil = inherit from previous rule or unlimited, 0 = unlimited, >0 = limit
+	MaxActive *int `yaml:"max_active,omitempty"`
+	// Spawn names the spawn profile used for matching repos.
+	// Empty = inherit from previous rule, or use spawn/batch_spawn.
+	Spawn string `yaml:"spawn,omitempty"`
+	// Hooks are commands run at points in the session lifecycle.
+	Hooks RuleHooks `yaml:"hooks,omitempty"`
+	// PromptPreamble is prepended to the prompts of sessions for matching
+	// repos, as inline text or the path of a file holding it. Load replaces
+	// a path with the file's contents.
+	PromptPreamble string `yaml:"prompt_preamble,omitempty"`
+	// Issues configures 'hive new --issue' for matching repos.
+	// nil = inherit from previous rule, or detect the provider and use its CLI.
+	Issues *IssueConfig `yaml:"issues,omitempty"`
+	// CI configures 'hive ci' for matching repos.
+	// nil = inherit from previous rule, or no CI trigger.
+	CI *CIConfig `yaml:"ci,omitempty"`
+	// HookPolicy applies to each of the rule's commands and hooks.
+	HookPolicy `yaml:",inline"`
+}
+
+// Hook failure policies.
+const (
+	OnFailureAbort    = "abort"    // stop and fail the operation (default)
+	OnFailureContinue = "continue" // log the failure and keep going
+	OnFailureWarn     = "warn"     // print a warning and keep going
+)
+
+// HookPolicy controls how rule commands and hooks are run.
+type HookPolicy struct {
+	Timeout   time.Duration `yaml:"timeout,omitempty"`    // per attempt, 0 = no limit
+	Retries   int           `yaml:"retries,omitempty"`    // extra attempts after a failure
+	OnFailure string        `yaml:"on_failure,omitempty"` // abort (default), continue, or warn
+	// Background starts the rule's commands and post_create hooks in a
+	// detached process after the session is spawned.
+	Background bool `yaml:"background,omitempty"`
+}
+
+// Lifecycle hook events.
+const (
+	HookPostCreate  = "post_create"  // after all rules' copy and commands, before spawn
+	HookPreRecycle  = "pre_recycle"  // before recycle commands run
+	HookPostRecycle = "post_recycle" // after the session is marked recycled
+	HookPreDelete   = "pre_delete"   // before the session directory is removed
+)
+
+// RuleHooks are lifecycle commands for sessions matching a rule. Each runs
+// in the session directory.
+type RuleHooks struct {
+	PostCreate  []string `yaml:"post_create,omitempty"`
+	PreRecycle  []string `yaml:"pre_recycle,omitempty"`
+	PostRecycle []string `yaml:"post_recycle,omitempty"`
+	PreDelete   []string `yaml:"pre_delete,omitempty"`
+}
+
+// For returns the commands for a lifecycle event.
+func (h RuleHooks) For(event string) []string {
+	switch event {
+	case HookPostCreate:
+		return h.PostCreate
+	case HookPreRecycle:
+		return h.PreRecycle
+	case HookPostRecycle:
+		return h.PostRecycle
+	case HookPreDelete:
+		return h.PreDelete
+	default:
+		return nil
+	}
+}
+
+// IsEmpty reports whether no lifecycle hooks are defined.
+func (h RuleHooks) IsEmpty() bool {
+	return len(h.PostCreate) == 0 && len(h.PreRecycle) == 0 && len(h.PostRecycle) == 0 && len(h.PreDelete) == 0
 }
 
 // Commands defines the shell commands used by hive.
 type Commands struct {
-	Spawn       []string `yaml:"spawn"`
-	BatchSpawn  []string `yaml:"batch_spawn"`
-	Recycle     []string `yaml:"recycle"`
-	CopyCommand string   `yaml:"copy_command"` // command to copy to clipboard (e.g., pbcopy, xclip)
+	Spawn         []string            `yaml:"spawn"`
+	BatchSpawn    []string            `yaml:"batch_spawn"`
+	SpawnProfiles map[string][]string `yaml:"spawn_profiles"` // named alternatives to spawn, selected with --spawn or rule spawn
+	Resume        []string            `yaml:"resume"`         // commands run by hive resume; default: the session's spawn commands
+	Recycle       []string            `yaml:"recycle"`
+	CopyCommand   string              `yaml:"copy_command"` // command to copy to clipboard (e.g., pbcopy, xclip); OSC 52 is used over SSH
+	Open          string              `yaml:"open"`         // command template to open a session directory (default: $EDITOR, code, or the system opener)
 }
 
 // Keybinding defines a TUI keybinding action.
 type Keybinding struct {
-	Action  string `yaml:"action"`  // built-in action name (recycle, delete)
+	Action  string `yaml:"action"`  // built-in action name (recycle, delete, open, pin, edit, recycle-batch, open_url, copy_path, copy_id, copy_inbox_topic, ci)
 	Help    string `yaml:"help"`    // help text shown in TUI
 	Sh      string `yaml:"sh"`      // shell command template
-	Confirm string `yaml:"confirm"` // confirmation prompt (empty = no confirm)
+	URL     string `yaml:"url"`     // url template opened by the open_url action
+	Confirm string `yaml:"confirm"` // confirmation policy (never, dirty-only, always) or prompt (empty = no confirm)
 	Silent  bool   `yaml:"silent"`  // skip loading popup for fast commands
 	Exit    string `yaml:"exit"`    // exit hive after command (bool or $ENV_VAR)
 }
@@ -151,6 +467,47 @@ func (k Keybinding) ShouldExit() bool {
 	return ParseExitCondition(k.Exit)
 }
 
+// Confirmation policies for the confirm field of a keybinding. Any other
+// non-empty confirm is the prompt of an action that always asks.
+const (
+	ConfirmNever     = "never"
+	ConfirmDirtyOnly = "dirty-only" // ask only when the session has uncommitted changes
+	ConfirmAlways    = "always"
+)
+
+// ConfirmPolicy returns when the keybinding asks for confirmation.
+func (k Keybinding) ConfirmPolicy() string {
+	switch k.Confirm {
+	case "", ConfirmNever:
+		return ConfirmNever
+	case ConfirmDirtyOnly:
+		return ConfirmDirtyOnly
+	default:
+		return ConfirmAlways
+	}
+}
+
+// ConfirmPrompt returns the custom confirmation prompt, empty when confirm
+// is a policy name.
+func (k Keybinding) ConfirmPrompt() string {
+	switch k.Confirm {
+	case ConfirmNever, ConfirmDirtyOnly, ConfirmAlways:
+		return ""
+	default:
+		return k.Confirm
+	}
+}
+
+// KeySequence splits a keybinding key into the keys pressed in turn. Keys
+// separated by spaces form a chord, e.g. "g p" is g followed by p; any other
+// key, including the space key itself, is a single key.
+func KeySequence(key string) []string {
+	if strings.TrimSpace(key) == "" {
+		return []string{key}
+	}
+	return strings.Split(key, " ")
+}
+
 // DefaultConfig returns a Config with sensible defaults.
 func DefaultConfig() Config {
 	return Config{
@@ -164,7 +521,13 @@ func DefaultConfig() Config {
 			},
 		},
 		Git: GitConfig{
-			StatusWorkers: 3,
+			StatusWorkers:  3,
+			StatusCacheTTL: 30 * time.Second,
+			Retries:        2,
+			RetryDelay:     time.Second,
+		},
+		Batch: BatchConfig{
+			Concurrency: 1,
 		},
 		GitPath:             "git",
 		Keybindings:         map[string]Keybinding{},
@@ -177,9 +540,14 @@ func DefaultConfig() Config {
 		},
 		TUI: TUIConfig{
 			RefreshInterval: 15 * time.Second,
+			GroupBy:         GroupByRepo,
 		},
 		Messaging: MessagingConfig{
 			TopicPrefix: "agent",
+			RateLimit:   RateLimitConfig{Per: time.Minute},
+		},
+		Store: StoreConfig{
+			Backend: StoreJSON,
 		},
 	}
 }
@@ -206,6 +574,18 @@ func Load(configPath, dataDir string) (*Config, error) {
 		}
 	}
 
+	if dir := cfg.TemplatesPath(configPath); dir != "" {
+		files, err := loadTemplatesDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("load templates: %w", err)
+		}
+		cfg.mergeTemplates(files)
+	}
+
+	if err := cfg.loadPromptPreambles(configPath); err != nil {
+		return nil, err
+	}
+
 	// Merge user keybindings into defaults (user config overrides defaults)
 	cfg.Keybindings = mergeKeybindings(defaultKeybindings, cfg.Keybindings)
 
@@ -225,12 +605,18 @@ func (c *Config) applyDefaults() {
 	if c.Git.StatusWorkers == 0 {
 		c.Git.StatusWorkers = defaults.Git.StatusWorkers
 	}
+	if c.Batch.Concurrency == 0 {
+		c.Batch.Concurrency = defaults.Batch.Concurrency
+	}
 	if c.History.MaxEntries == 0 {
 		c.History.MaxEntries = defaults.History.MaxEntries
 	}
 	if c.Context.SymlinkName == "" {
 		c.Context.SymlinkName = defaults.Context.SymlinkName
 	}
+	if c.Store.Backend == "" {
+		c.Store.Backend = defaults.Store.Backend
+	}
 	if c.Commands.CopyCommand == "" {
 		c.Commands.CopyCommand = defaultCopyCommand()
 	}
@@ -270,30 +656,241 @@ func (c *Config) Validate() error {
 		criterio.Run("git_path", c.GitPath, criterio.Required[string]),
 		criterio.Run("data_dir", c.DataDir, criterio.Required[string]),
 		criterio.Run("git.status_workers", c.Git.StatusWorkers, criterio.Min(1)),
+		criterio.Run("git.retries", c.Git.Retries, criterio.Min(0)),
+		criterio.Run("max_active_sessions", c.MaxActiveSessions, criterio.Min(0)),
+		criterio.Run("git.retry_delay", c.Git.RetryDelay, criterio.Min(time.Duration(0))),
+		criterio.Run("batch.concurrency", c.Batch.Concurrency, criterio.Min(1)),
 		c.validateKeybindingsBasic(),
-		c.validateMaxRecycled(),
+		c.validateRuleSettings(),
+		c.validateBatchMaxFailures(),
+		c.validateStore(),
+		c.validateGroupBy(),
+		c.validateIcons(),
+		c.validateBackupsKeep(),
+		c.validateTrashRetention(),
+		c.validateSessionDirTemplate(),
+		c.validateNotifications(),
+		c.validateHosts(),
+		c.validateMessageSnippets(),
+		c.validateMessageRateLimit(),
+		c.validateHeartbeat(),
+		c.validateEnv(),
+		c.validatePromptTemplates(),
+		c.validateSpawnProfiles(),
+		c.validateCopySpecs(),
+		c.validateIssues(),
+		c.validateCI(),
 	)
 }
 
-// validateMaxRecycled checks that max_recycled values are non-negative.
-func (c *Config) validateMaxRecycled() error {
+// validateSpawnProfiles checks spawn profile names and that each has
+// commands. Command syntax is checked by ValidateDeep.
+func (c *Config) validateSpawnProfiles() error {
+	var errs criterio.FieldErrorsBuilder
+	for name, commands := range c.Commands.SpawnProfiles {
+		field := fmt.Sprintf("commands.spawn_profiles[%q]", name)
+		if !templateNameRe.MatchString(name) {
+			errs = errs.Append(field, fmt.Errorf("invalid name; use letters, digits, '-' and '_'"))
+		}
+		if len(commands) == 0 {
+			errs = errs.Append(field, fmt.Errorf("must have at least one command"))
+		}
+	}
+	return errs.ToError()
+}
+
+// Valid template and template field names. Field names must be usable as
+// {{ .name }} in a Go template.
+var (
+	templateNameRe      = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+	templateFieldNameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+)
+
+// validatePromptTemplates checks template names, prompts, and field names.
+// Prompt syntax is checked by ValidateDeep.
+func (c *Config) validatePromptTemplates() error {
+	var errs criterio.FieldErrorsBuilder
+	for name, t := range c.Templates {
+		field := fmt.Sprintf("templates[%q]", name)
+
+		if !templateNameRe.MatchString(name) {
+			errs = errs.Append(field, fmt.Errorf("invalid name; use letters, digits, '-' and '_'"))
+		}
+		if strings.TrimSpace(t.Prompt) == "" {
+			errs = errs.Append(field+".prompt", fmt.Errorf("is required"))
+		}
+
+		seen := make(map[string]bool, len(t.Fields))
+		for i, f := range t.Fields {
+			fieldName := fmt.Sprintf("%s.fields[%d].name", field, i)
+			switch {
+			case !templateFieldNameRe.MatchString(f.Name):
+				errs = errs.Append(fieldName, fmt.Errorf("invalid name %q; use letters, digits, and '_'", f.Name))
+			case seen[f.Name]:
+				errs = errs.Append(fieldName, fmt.Errorf("duplicate field %q", f.Name))
+			}
+			seen[f.Name] = true
+
+			if err := validateTemplateField(f); err != nil {
+				errs = errs.Append(fmt.Sprintf("%s.fields[%d]", field, i), err)
+			}
+		}
+	}
+	return errs.ToError()
+}
+
+// validateTemplateField checks a field's type, options, and default.
+func validateTemplateField(f TemplateField) error {
+	switch f.Type {
+	case "", FieldString, FieldNumber, FieldBool:
+		if len(f.Options) > 0 || f.OptionsCmd != "" {
+			return fmt.Errorf("options and options_cmd require type select")
+		}
+	case FieldSelect:
+		if len(f.Options) == 0 && f.OptionsCmd == "" {
+			return fmt.Errorf("select requires options or options_cmd")
+		}
+		if len(f.Options) > 0 && f.OptionsCmd != "" {
+			return fmt.Errorf("cannot set both options and options_cmd")
+		}
+	default:
+		return fmt.Errorf("invalid type %q (expected string, number, bool, or select)", f.Type)
+	}
+
+	if f.Default != "" {
+		if _, err := f.Parse(f.Default); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRuleSettings checks max_recycled, max_active, and the hook policy
+// of each rule.
+func (c *Config) validateRuleSettings() error {
 	var errs criterio.FieldErrorsBuilder
 
 	for i, rule := range c.Rules {
 		if rule.MaxRecycled != nil && *rule.MaxRecycled < 0 {
 			errs = errs.Append(fmt.Sprintf("rules[%d].max_recycled", i), fmt.Errorf("must be >= 0, got %d", *rule.MaxRecycled))
 		}
+		if rule.MaxActive != nil && *rule.MaxActive < 0 {
+			errs = errs.Append(fmt.Sprintf("rules[%d].max_active", i), fmt.Errorf("must be >= 0, got %d", *rule.MaxActive))
+		}
+		if rule.Timeout < 0 {
+			errs = errs.Append(fmt.Sprintf("rules[%d].timeout", i), fmt.Errorf("must be >= 0, got %s", rule.Timeout))
+		}
+		if rule.Spawn != "" {
+			if _, ok := c.Commands.SpawnProfiles[rule.Spawn]; !ok {
+				errs = errs.Append(fmt.Sprintf("rules[%d].spawn", i), fmt.Errorf("unknown spawn profile %q", rule.Spawn))
+			}
+		}
+		if rule.Background && rule.Timeout > 0 {
+			errs = errs.Append(fmt.Sprintf("rules[%d].timeout", i), fmt.Errorf("not supported with background"))
+		}
+		if rule.Retries < 0 {
+			errs = errs.Append(fmt.Sprintf("rules[%d].retries", i), fmt.Errorf("must be >= 0, got %d", rule.Retries))
+		}
+		switch rule.OnFailure {
+		case "", OnFailureAbort, OnFailureContinue, OnFailureWarn:
+		default:
+			errs = errs.Append(fmt.Sprintf("rules[%d].on_failure", i), fmt.Errorf("must be abort, continue, or warn, got %q", rule.OnFailure))
+		}
 	}
 
 	return errs.ToError()
 }
 
+// validateBatchMaxFailures checks that batch.max_failures is non-negative.
+func (c *Config) validateBatchMaxFailures() error {
+	if c.Batch.MaxFailures != nil && *c.Batch.MaxFailures < 0 {
+		return criterio.NewFieldErrors("batch.max_failures", fmt.Errorf("must be >= 0, got %d", *c.Batch.MaxFailures))
+	}
+	return nil
+}
+
+// validateStore checks that store.backend names a known backend.
+func (c *Config) validateStore() error {
+	switch c.Store.Backend {
+	case "", StoreJSON, StoreSQLite:
+		return nil
+	default:
+		return criterio.NewFieldErrors("store.backend", fmt.Errorf("must be json or sqlite, got %q", c.Store.Backend))
+	}
+}
+
+// validateGroupBy checks that tui.group_by names a known grouping.
+func (c *Config) validateGroupBy() error {
+	switch c.TUI.GroupBy {
+	case "", GroupByRepo, GroupByOwner:
+		return nil
+	default:
+		return criterio.NewFieldErrors("tui.group_by", fmt.Errorf("must be repo or owner, got %q", c.TUI.GroupBy))
+	}
+}
+
+// validateIcons checks that tui.icons only overrides known glyphs and that
+// none is empty.
+func (c *Config) validateIcons() error {
+	var errs criterio.FieldErrorsBuilder
+	for name, glyph := range c.TUI.Icons {
+		field := fmt.Sprintf("tui.icons[%q]", name)
+		if !slices.Contains(TUIIconNames, name) {
+			errs = errs.Append(field, fmt.Errorf("unknown icon; use one of %s", strings.Join(TUIIconNames, ", ")))
+		} else if glyph == "" {
+			errs = errs.Append(field, fmt.Errorf("must not be empty"))
+		}
+	}
+	return errs.ToError()
+}
+
+// validateBackupsKeep checks that backups.keep is non-negative.
+func (c *Config) validateBackupsKeep() error {
+	if c.Backups.Keep != nil && *c.Backups.Keep < 0 {
+		return criterio.NewFieldErrors("backups.keep", fmt.Errorf("must be >= 0, got %d", *c.Backups.Keep))
+	}
+	return nil
+}
+
+// validateMessageRateLimit checks that messaging.rate_limit has a
+// non-negative message count and, when enabled, a positive window.
+func (c *Config) validateMessageRateLimit() error {
+	rl := c.Messaging.RateLimit
+	if rl.Messages < 0 {
+		return criterio.NewFieldErrors("messaging.rate_limit.messages", fmt.Errorf("must be >= 0, got %d", rl.Messages))
+	}
+	if rl.Messages > 0 && rl.Per <= 0 {
+		return criterio.NewFieldErrors("messaging.rate_limit.per", fmt.Errorf("must be positive, got %s", rl.Per))
+	}
+	return nil
+}
+
+// validateHeartbeat checks that messaging.heartbeat.interval is zero or at
+// least a second.
+func (c *Config) validateHeartbeat() error {
+	if iv := c.Messaging.Heartbeat.Interval; iv != 0 && iv < time.Second {
+		return criterio.NewFieldErrors("messaging.heartbeat.interval", fmt.Errorf("must be 0 or at least 1s, got %s", iv))
+	}
+	return nil
+}
+
+// validateTrashRetention checks that trash.retention is non-negative.
+func (c *Config) validateTrashRetention() error {
+	if c.Trash.Retention != nil && *c.Trash.Retention < 0 {
+		return criterio.NewFieldErrors("trash.retention", fmt.Errorf("must be >= 0, got %s", *c.Trash.Retention))
+	}
+	return nil
+}
+
 // validateKeybindingsBasic performs basic keybinding validation for the Validate() method.
 func (c *Config) validateKeybindingsBasic() error {
 	var errs criterio.FieldErrorsBuilder
 	for key, kb := range c.Keybindings {
 		field := fmt.Sprintf("keybindings[%q]", key)
 
+		if slices.Contains(KeySequence(key), "") {
+			errs = errs.Append(field, fmt.Errorf("chord keys must be separated by a single space"))
+		}
 		if kb.Action == "" && kb.Sh == "" {
 			errs = errs.Append(field, fmt.Errorf("must have either action or sh"))
 			continue
@@ -305,6 +902,12 @@ func (c *Config) validateKeybindingsBasic() error {
 		if kb.Action != "" && !isValidAction(kb.Action) {
 			errs = errs.Append(field, fmt.Errorf("invalid action %q", kb.Action))
 		}
+		if kb.Action == ActionOpenURL && kb.URL == "" {
+			errs = errs.Append(field, fmt.Errorf("action %q requires url", ActionOpenURL))
+		}
+		if kb.Action != ActionOpenURL && kb.URL != "" {
+			errs = errs.Append(field, fmt.Errorf("url is only used by action %q", ActionOpenURL))
+		}
 	}
 
 	return errs.ToError()
@@ -320,6 +923,39 @@ func (c *Config) SessionsFile() string {
 	return filepath.Join(c.DataDir, "sessions.json")
 }
 
+// SessionsDB returns the path to the sessions SQLite database.
+func (c *Config) SessionsDB() string {
+	return filepath.Join(c.DataDir, "sessions.db")
+}
+
+// TrashDir returns the path where deleted sessions are held until they
+// expire or are restored.
+func (c *Config) TrashDir() string {
+	return filepath.Join(c.DataDir, "trash")
+}
+
+// JournalDir returns the path where session directory renames are recorded
+// until the store reflects them.
+func (c *Config) JournalDir() string {
+	return filepath.Join(c.DataDir, "journal")
+}
+
+// BackupsDir returns the path where store backups are kept.
+func (c *Config) BackupsDir() string {
+	return filepath.Join(c.DataDir, "backups")
+}
+
+// EventsFile returns the path to the NDJSON event log.
+func (c *Config) EventsFile() string {
+	return filepath.Join(c.DataDir, "events.ndjson")
+}
+
+// ReservationsFile returns the path to the active session slots reserved by
+// in-flight creates, shared by every hive process.
+func (c *Config) ReservationsFile() string {
+	return filepath.Join(c.DataDir, "reservations.json")
+}
+
 // HistoryFile returns the path to the command history JSON file.
 func (c *Config) HistoryFile() string {
 	return filepath.Join(c.DataDir, "history.json")
@@ -330,6 +966,28 @@ func (c *Config) LogsDir() string {
 	return filepath.Join(c.DataDir, "logs")
 }
 
+// SessionLogsDir returns the directory holding a session's log files.
+func (c *Config) SessionLogsDir(id string) string {
+	return filepath.Join(c.LogsDir(), "sessions", id)
+}
+
+// SessionLogFile returns the path of a session's log for source (hooks,
+// recycle, or spawn).
+func (c *Config) SessionLogFile(id, source string) string {
+	return filepath.Join(c.SessionLogsDir(id), source+".log")
+}
+
+// BatchesDir returns the path where batch state files are stored.
+func (c *Config) BatchesDir() string {
+	return filepath.Join(c.DataDir, "batches")
+}
+
+// WorkspacesDir returns the path where generated editor workspace files are
+// written.
+func (c *Config) WorkspacesDir() string {
+	return filepath.Join(c.DataDir, "workspaces")
+}
+
 // ContextDir returns the base context directory path.
 func (c *Config) ContextDir() string {
 	return filepath.Join(c.DataDir, "context")
@@ -347,7 +1005,8 @@ func (c *Config) SharedContextDir() string {
 
 func isValidAction(action string) bool {
 	switch action {
-	case ActionRecycle, ActionDelete:
+	case ActionRecycle, ActionDelete, ActionOpen, ActionPin, ActionEdit, ActionRecycleBatch, ActionOpenURL,
+		ActionCopyPath, ActionCopyID, ActionCopyInboxTopic, ActionCI:
 		return true
 	default:
 		return false
@@ -378,6 +1037,80 @@ func (c *Config) GetMaxRecycled(remote string) int {
 	return DefaultMaxRecycled
 }
 
+// GetMaxActive returns the max active sessions limit for the given remote
+// URL. The last matching rule with max_active set wins. Returns 0 for
+// unlimited, the default.
+func (c *Config) GetMaxActive(remote string) int {
+	var result int
+	for _, rule := range c.Rules {
+		if rule.MaxActive != nil && (rule.Pattern == "" || matchesPattern(rule.Pattern, remote)) {
+			result = *rule.MaxActive
+		}
+	}
+	return result
+}
+
+// SpawnProfileFor returns the spawn profile for the given remote URL, or ""
+// to use spawn/batch_spawn. The last matching rule with spawn set wins.
+func (c *Config) SpawnProfileFor(remote string) string {
+	var result string
+	for _, rule := range c.Rules {
+		if rule.Spawn != "" && (rule.Pattern == "" || matchesPattern(rule.Pattern, remote)) {
+			result = rule.Spawn
+		}
+	}
+	return result
+}
+
+// PromptPreambleFor returns the prompt preambles of every rule matching
+// remote, in rule order and separated by blank lines.
+func (c *Config) PromptPreambleFor(remote string) string {
+	var parts []string
+	for _, rule := range c.Rules {
+		preamble := strings.TrimSpace(rule.PromptPreamble)
+		if preamble != "" && (rule.Pattern == "" || matchesPattern(rule.Pattern, remote)) {
+			parts = append(parts, preamble)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// loadPromptPreambles replaces rule prompt preambles that name a file with
+// the file's contents. A single-line preamble is a file when it names an
+// existing file, after expanding a leading ~ and resolving relative paths
+// against the config file's directory. One that starts like a path (/, ./,
+// ../, ~/) must name a file.
+func (c *Config) loadPromptPreambles(configPath string) error {
+	for i, rule := range c.Rules {
+		value := strings.TrimSpace(rule.PromptPreamble)
+		if value == "" || strings.Contains(value, "\n") {
+			continue
+		}
+
+		path := value
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		if !filepath.IsAbs(path) && configPath != "" {
+			path = filepath.Join(filepath.Dir(configPath), path)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			looksLikePath := strings.HasPrefix(value, "/") || strings.HasPrefix(value, "./") ||
+				strings.HasPrefix(value, "../") || strings.HasPrefix(value, "~/")
+			if looksLikePath {
+				return fmt.Errorf("rules[%d].prompt_preamble: %w", i, err)
+			}
+			continue
+		}
+		c.Rules[i].PromptPreamble = string(data)
+	}
+	return nil
+}
+
 // matchesPattern checks if remote matches the regex pattern.
 func matchesPattern(pattern, remote string) bool {
 	matched, _ := filepath.Match(pattern, remote)
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/hay-kot/criterio"
+	"gopkg.in/yaml.v3"
+)
+
+// CopySpec is a single copy rule entry. In YAML it is written either as a
+// glob string, copied to the same relative path, or as a mapping:
+//
+//	copy:
+//	  - .envrc
+//	  - from: .envrc.tmpl
+//	    to: .envrc
+//	    template: true
+//	  - from: configs/**
+//	    exclude: ["**/node_modules/**"]
+//
+// A literal (non-glob) From naming a directory is copied recursively.
+type CopySpec struct {
+	From     string   `yaml:"from"`               // glob or path relative to the source directory
+	To       string   `yaml:"to,omitempty"`       // destination relative to the session; requires a literal From
+	Template bool     `yaml:"template,omitempty"` // render file contents with the rule template data
+	Exclude  []string `yaml:"exclude,omitempty"`  // globs, relative to the source directory, of paths to skip
+}
+
+// copySpecFields mirrors CopySpec without its YAML methods.
+type copySpecFields CopySpec
+
+// UnmarshalYAML decodes either a glob string or a from/to mapping.
+func (s *CopySpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*s = CopySpec{From: node.Value}
+		return nil
+	}
+
+	var fields copySpecFields
+	if err := node.Decode(&fields); err != nil {
+		return err
+	}
+	*s = CopySpec(fields)
+	return nil
+}
+
+// MarshalYAML writes plain globs as strings so they round-trip.
+func (s CopySpec) MarshalYAML() (any, error) {
+	if s.To == "" && !s.Template && len(s.Exclude) == 0 {
+		return s.From, nil
+	}
+	return copySpecFields(s), nil
+}
+
+// String describes the entry as "from" or "from -> to".
+func (s CopySpec) String() string {
+	if s.To == "" {
+		return s.From
+	}
+	return s.From + " -> " + s.To
+}
+
+// IsGlob reports whether From contains glob characters.
+func (s CopySpec) IsGlob() bool {
+	return strings.ContainsAny(s.From, "*?[{")
+}
+
+// validateCopySpecs checks that each copy entry has a source, that
+// destinations stay inside the session directory, and that exclude patterns
+// and max_file_size are valid.
+func (c *Config) validateCopySpecs() error {
+	var errs criterio.FieldErrorsBuilder
+
+	for i, rule := range c.Rules {
+		if rule.MaxFileSize < 0 {
+			errs = errs.Append(fmt.Sprintf("rules[%d].max_file_size", i), fmt.Errorf("must be >= 0, got %d", rule.MaxFileSize))
+		}
+
+		for j, spec := range rule.Copy {
+			field := fmt.Sprintf("rules[%d].copy[%d]", i, j)
+			switch {
+			case spec.From == "":
+				errs = errs.Append(field+".from", fmt.Errorf("is required"))
+			case spec.To != "" && spec.IsGlob():
+				errs = errs.Append(field+".to", fmt.Errorf("requires a literal from path, got glob %q", spec.From))
+			case spec.To != "" && escapesDir(spec.To):
+				errs = errs.Append(field+".to", fmt.Errorf("must be a relative path inside the session, got %q", spec.To))
+			}
+
+			for k, pattern := range spec.Exclude {
+				if !doublestar.ValidatePattern(pattern) {
+					errs = errs.Append(fmt.Sprintf("%s.exclude[%d]", field, k), fmt.Errorf("invalid glob %q", pattern))
+				}
+			}
+		}
+	}
+
+	return errs.ToError()
+}
+
+// escapesDir reports whether a relative path is absolute or climbs out of
+// its base directory.
+func escapesDir(path string) bool {
+	clean := filepath.Clean(path)
+	return filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator))
+}
+
+// ByteSize is a size in bytes. In YAML it is written as a number of bytes or
+// with a KB, MB, or GB suffix (powers of 1024), e.g. "512KB" or "10MB".
+type ByteSize int64
+
+var byteUnits = []struct {
+	suffix string
+	size   ByteSize
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a size such as "10MB", "512 KB", or "2048".
+func ParseByteSize(s string) (ByteSize, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	unit := ByteSize(1)
+	for _, u := range byteUnits {
+		if trimmed, ok := strings.CutSuffix(value, u.suffix); ok {
+			value, unit = strings.TrimSpace(trimmed), u.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 512KB or 10MB)", s)
+	}
+	return ByteSize(n * float64(unit)), nil
+}
+
+// UnmarshalYAML decodes a size with an optional unit suffix.
+func (b *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: size must be a scalar", node.Line)
+	}
+	size, err := ParseByteSize(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*b = size
+	return nil
+}
+
+// MarshalYAML writes the size in its largest whole unit.
+func (b ByteSize) MarshalYAML() (any, error) {
+	return b.String(), nil
+}
+
+// String formats the size in its largest whole unit, e.g. "10MB".
+func (b ByteSize) String() string {
+	for _, u := range byteUnits {
+		if b != 0 && b%u.size == 0 {
+			return strconv.FormatInt(int64(b/u.size), 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64(b), 10) + "B"
+}
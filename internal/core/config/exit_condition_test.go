@@ -146,3 +146,25 @@ func TestKeybinding_ShouldExit(t *testing.T) {
 		})
 	}
 }
+
+func TestKeybinding_ConfirmPolicy(t *testing.T) {
+	tests := []struct {
+		confirm    string
+		wantPolicy string
+		wantPrompt string
+	}{
+		{confirm: "", wantPolicy: ConfirmNever},
+		{confirm: "never", wantPolicy: ConfirmNever},
+		{confirm: "dirty-only", wantPolicy: ConfirmDirtyOnly},
+		{confirm: "always", wantPolicy: ConfirmAlways},
+		{confirm: "Delete it?", wantPolicy: ConfirmAlways, wantPrompt: "Delete it?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.confirm, func(t *testing.T) {
+			kb := Keybinding{Action: ActionDelete, Confirm: tt.confirm}
+			assert.Equal(t, tt.wantPolicy, kb.ConfirmPolicy())
+			assert.Equal(t, tt.wantPrompt, kb.ConfirmPrompt())
+		})
+	}
+}
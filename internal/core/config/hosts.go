@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"github.com/hay-kot/criterio"
+)
+
+// DefaultHostHive is the hive command run on a remote host when hive is unset.
+const DefaultHostHive = "hive"
+
+// HostConfig is a remote machine running hive, reached over SSH.
+type HostConfig struct {
+	SSH  string `yaml:"ssh"`  // ssh destination, e.g. "me@server" or a Host from ~/.ssh/config
+	Hive string `yaml:"hive"` // hive command on the host, default: hive
+	// Relay are topic globs whose messages, when published locally, are also
+	// published on this host.
+	Relay []string `yaml:"relay"`
+}
+
+// HiveCommand returns the hive command to run on the host.
+func (h HostConfig) HiveCommand() string {
+	if h.Hive == "" {
+		return DefaultHostHive
+	}
+	return h.Hive
+}
+
+// validateHosts checks host names, destinations, and relay patterns.
+func (c *Config) validateHosts() error {
+	var errs criterio.FieldErrorsBuilder
+	for _, name := range slices.Sorted(maps.Keys(c.Hosts)) {
+		h := c.Hosts[name]
+		field := fmt.Sprintf("hosts[%q]", name)
+
+		if !templateNameRe.MatchString(name) || name == "local" {
+			errs = errs.Append(field, fmt.Errorf("invalid name; use letters, digits, '-' and '_' (\"local\" is reserved)"))
+		}
+		if h.SSH == "" {
+			errs = errs.Append(field+".ssh", fmt.Errorf("is required"))
+		}
+		for _, pattern := range h.Relay {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = errs.Append(field+".relay", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+			}
+		}
+	}
+	return errs.ToError()
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hay-kot/criterio"
+)
+
+// Issue providers for 'hive new --issue'.
+const (
+	IssueProviderGitHub = "github"
+	IssueProviderGitLab = "gitlab"
+)
+
+// IssueConfig configures how 'hive new --issue' fetches issues of the
+// repositories a rule matches.
+type IssueConfig struct {
+	// Provider is github or gitlab. Empty = detected from the remote's host.
+	Provider string `yaml:"provider,omitempty"`
+	// Token authenticates requests to the provider's REST API. Empty = run
+	// the gh or glab CLI with its own login instead.
+	Token SecretValue `yaml:"token,omitempty"`
+	// APIURL is the REST API base URL, for GitHub Enterprise or self-hosted
+	// GitLab. Empty = derived from the remote's host.
+	APIURL string `yaml:"api_url,omitempty"`
+	// Template renders the session prompt from the issue. Empty = a built-in
+	// prompt with the issue's title, body, and URL.
+	Template string `yaml:"template,omitempty"`
+}
+
+// IssueConfigFor returns the issue settings for the given remote URL. The
+// last matching rule with issues set wins; without one the zero value
+// detects the provider and uses its CLI.
+func (c *Config) IssueConfigFor(remote string) IssueConfig {
+	var result IssueConfig
+	for _, rule := range c.Rules {
+		if rule.Issues != nil && (rule.Pattern == "" || matchesPattern(rule.Pattern, remote)) {
+			result = *rule.Issues
+		}
+	}
+	return result
+}
+
+// validateIssues checks the issue settings of each rule.
+func (c *Config) validateIssues() error {
+	var errs criterio.FieldErrorsBuilder
+	for i, rule := range c.Rules {
+		if rule.Issues == nil {
+			continue
+		}
+		field := fmt.Sprintf("rules[%d].issues", i)
+		is := rule.Issues
+
+		switch is.Provider {
+		case "", IssueProviderGitHub, IssueProviderGitLab:
+		default:
+			errs = errs.Append(field+".provider", fmt.Errorf("must be github or gitlab, got %q", is.Provider))
+		}
+		if is.Token.Kind != SecretPlain && is.Token.Value == "" {
+			errs = errs.Append(field+".token", fmt.Errorf("!%s requires a value", is.Token.Kind))
+		}
+		if is.Token.Kind == SecretSecret && c.Secrets.Command == "" {
+			errs = errs.Append(field+".token", fmt.Errorf("!secret requires secrets.command to be set"))
+		}
+		if is.APIURL != "" {
+			u, err := url.Parse(is.APIURL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				errs = errs.Append(field+".api_url", fmt.Errorf("must be an http or https URL"))
+			}
+		}
+		if is.Template != "" {
+			if _, ok := c.Templates[is.Template]; !ok {
+				errs = errs.Append(field+".template", fmt.Errorf("unknown template %q", is.Template))
+			}
+		}
+	}
+	return errs.ToError()
+}
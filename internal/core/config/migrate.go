@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MigrateResult describes the outcome of migrating a config file.
+type MigrateResult struct {
+	Original []byte
+	Migrated []byte
+	Applied  []string // migrations that modified the document
+	Manual   []string // migrations that require user action
+}
+
+// Changed reports whether the migration produced a different document.
+func (r MigrateResult) Changed() bool {
+	return len(r.Applied) > 0
+}
+
+// promptTemplateRe matches template actions that reference .Prompt.
+var promptTemplateRe = regexp.MustCompile(`\{\{[^}]*\.Prompt\b[^}]*\}\}`)
+
+// Migrate applies the known mechanical migrations to raw config YAML and
+// returns the rewritten document. Comments are preserved where possible.
+// Migrations that cannot be applied safely are reported in Manual, and the
+// version is not updated while any remain.
+func Migrate(data []byte) (MigrateResult, error) {
+	res := MigrateResult{Original: data, Migrated: data}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return res, fmt.Errorf("parse config file: %w", err)
+	}
+
+	// An empty file decodes to a zero node; start from an empty mapping.
+	if doc.Kind == 0 {
+		doc = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}},
+		}
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return res, fmt.Errorf("parse config file: top level must be a mapping")
+	}
+	root := doc.Content[0]
+
+	migrateSpawnPrompt(root, &res)
+
+	// The version records that every migration is done, so it stays put
+	// until the manual steps are resolved and Migrate runs again.
+	if len(res.Manual) == 0 {
+		migrateVersion(root, &res)
+	}
+
+	if !res.Changed() {
+		return res, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return res, fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return res, fmt.Errorf("encode config: %w", err)
+	}
+
+	res.Migrated = buf.Bytes()
+	return res, nil
+}
+
+// migrateSpawnPrompt moves commands.spawn to commands.batch_spawn when the
+// spawn commands reference {{.Prompt}}, which spawn no longer supports (0.2.0).
+func migrateSpawnPrompt(root *yaml.Node, res *MigrateResult) {
+	commands := mappingValue(root, "commands")
+	if commands == nil || commands.Kind != yaml.MappingNode {
+		return
+	}
+
+	spawn := mappingValue(commands, "spawn")
+	if spawn == nil || spawn.Kind != yaml.SequenceNode {
+		return
+	}
+
+	usesPrompt := false
+	for _, item := range spawn.Content {
+		if item.Kind == yaml.ScalarNode && promptTemplateRe.MatchString(item.Value) {
+			usesPrompt = true
+			break
+		}
+	}
+	if !usesPrompt {
+		return
+	}
+
+	batch := mappingValue(commands, "batch_spawn")
+	if batch != nil && len(batch.Content) > 0 {
+		res.Manual = append(res.Manual,
+			"commands.spawn references {{.Prompt}} but commands.batch_spawn is already set; remove {{.Prompt}} from spawn manually")
+		return
+	}
+
+	moved := *spawn
+	if batch != nil {
+		*batch = moved
+	} else {
+		setMappingValue(commands, "batch_spawn", &moved)
+	}
+
+	// Keep a prompt-free copy for spawn so `hive new` still opens a terminal.
+	var kept []*yaml.Node
+	for _, item := range spawn.Content {
+		if item.Kind == yaml.ScalarNode && promptTemplateRe.MatchString(item.Value) {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	setMappingValue(commands, "spawn", &yaml.Node{
+		Kind:    yaml.SequenceNode,
+		Tag:     "!!seq",
+		Content: kept,
+	})
+
+	res.Applied = append(res.Applied, "moved prompt-bearing commands.spawn entries to commands.batch_spawn")
+	if len(kept) == 0 {
+		res.Manual = append(res.Manual,
+			"commands.spawn is now empty; add a spawn command without {{.Prompt}} for `hive new`")
+	}
+}
+
+// migrateVersion sets the version field to CurrentConfigVersion.
+func migrateVersion(root *yaml.Node, res *MigrateResult) {
+	current := mappingValue(root, "version")
+	if current != nil && current.Kind == yaml.ScalarNode && CompareVersions(current.Value, CurrentConfigVersion) >= 0 {
+		return
+	}
+
+	if current == nil {
+		// Insert at the top so the version is the first thing readers see.
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+		val := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: CurrentConfigVersion}
+		root.Content = append([]*yaml.Node{key, val}, root.Content...)
+		res.Applied = append(res.Applied, fmt.Sprintf("added version: %s", CurrentConfigVersion))
+		return
+	}
+
+	prev := current.Value
+	*current = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: CurrentConfigVersion, LineComment: current.LineComment}
+	res.Applied = append(res.Applied, fmt.Sprintf("updated version: %s -> %s", prev, CurrentConfigVersion))
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue replaces the value for key, appending the key if missing.
+func setMappingValue(m *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content[i+1] = value
+			return
+		}
+	}
+	m.Content = append(m.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		value,
+	)
+}
+
+// CompareVersions compares two semantic versions.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func CompareVersions(a, b string) int {
+	aParts := parseVersion(a)
+	bParts := parseVersion(b)
+
+	for i := 0; i < 3; i++ {
+		if aParts[i] < bParts[i] {
+			return -1
+		}
+		if aParts[i] > bParts[i] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseVersion extracts major, minor, patch from a version string.
+// Returns [0,0,0] for invalid versions.
+func parseVersion(v string) [3]int {
+	var parts [3]int
+	segments := strings.Split(v, ".")
+	for i := 0; i < len(segments) && i < 3; i++ {
+		n, _ := strconv.Atoi(segments[i])
+		parts[i] = n
+	}
+	return parts
+}
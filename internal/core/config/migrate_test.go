@@ -0,0 +1,106 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrate_SpawnPromptMovedToBatchSpawn(t *testing.T) {
+	input := `# my config
+commands:
+  spawn:
+    - "wezterm cli spawn --cwd {{.Path}} -- claude --prompt '{{ .Prompt }}'"
+    - "echo {{.Name}}"
+  recycle:
+    - git reset --hard
+`
+
+	res, err := Migrate([]byte(input))
+	require.NoError(t, err)
+	assert.True(t, res.Changed())
+	assert.Len(t, res.Applied, 2)
+	assert.Empty(t, res.Manual)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(res.Migrated, &cfg))
+	assert.Equal(t, CurrentConfigVersion, cfg.Version)
+	assert.Equal(t, []string{"echo {{.Name}}"}, cfg.Commands.Spawn)
+	assert.Len(t, cfg.Commands.BatchSpawn, 2)
+	assert.Equal(t, []string{"git reset --hard"}, cfg.Commands.Recycle)
+	assert.Contains(t, string(res.Migrated), "# my config")
+}
+
+func TestMigrate_SpawnPromptWithExistingBatchSpawn(t *testing.T) {
+	input := `version: 0.1.0
+commands:
+  spawn:
+    - "claude '{{.Prompt}}'"
+  batch_spawn:
+    - "claude '{{.Prompt}}'"
+`
+
+	res, err := Migrate([]byte(input))
+	require.NoError(t, err)
+	require.Len(t, res.Manual, 1)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(res.Migrated, &cfg))
+	assert.Equal(t, []string{"claude '{{.Prompt}}'"}, cfg.Commands.Spawn, "spawn should be untouched")
+	assert.Equal(t, "0.1.0", cfg.Version, "version should wait for the manual step")
+}
+
+func TestMigrate_OnlyPromptSpawnLeavesEmptySpawn(t *testing.T) {
+	input := `commands:
+  spawn:
+    - "claude '{{.Prompt}}'"
+`
+
+	res, err := Migrate([]byte(input))
+	require.NoError(t, err)
+	assert.Len(t, res.Manual, 1)
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal(res.Migrated, &cfg))
+	assert.Empty(t, cfg.Commands.Spawn)
+	assert.Equal(t, []string{"claude '{{.Prompt}}'"}, cfg.Commands.BatchSpawn)
+}
+
+func TestMigrate_UpToDate(t *testing.T) {
+	input := "version: " + CurrentConfigVersion + "\ncommands:\n  spawn:\n    - echo {{.Path}}\n"
+
+	res, err := Migrate([]byte(input))
+	require.NoError(t, err)
+	assert.False(t, res.Changed())
+	assert.Equal(t, input, string(res.Migrated))
+}
+
+func TestMigrate_EmptyFile(t *testing.T) {
+	res, err := Migrate(nil)
+	require.NoError(t, err)
+	assert.True(t, res.Changed())
+	assert.Equal(t, "version: "+CurrentConfigVersion+"\n", string(res.Migrated))
+}
+
+func TestMigrate_InvalidYAML(t *testing.T) {
+	_, err := Migrate([]byte("- just\n- a list\n"))
+	assert.Error(t, err)
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.2.3", "0.2.3", 0},
+		{"0.2.2", "0.2.3", -1},
+		{"0.10.0", "0.9.9", 1},
+		{"", "0.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
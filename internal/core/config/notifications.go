@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/hay-kot/criterio"
+)
+
+// Notification runs a shell command, calls a webhook, or posts to a Slack or
+// Discord channel when an event matching one of Events is recorded in the
+// event log.
+type Notification struct {
+	// Events are event type globs, e.g. "session.created" or "session.*".
+	// Terminal status changes also match "status.<status>", e.g. "status.approval".
+	Events []string `yaml:"events"`
+	// Topics are topic globs, e.g. "agent.*.inbox", that message.published
+	// events must match. Empty = every topic.
+	Topics  []string `yaml:"topics"`
+	Command string   `yaml:"command"` // shell template run for each event
+	Webhook string   `yaml:"webhook"` // URL that receives a POST for each event
+	// Payload is the webhook body template. Defaults to the event as JSON.
+	Payload string                 `yaml:"payload"`
+	Headers map[string]SecretValue `yaml:"headers"` // webhook request headers
+	Slack   SecretValue            `yaml:"slack"`   // Slack incoming webhook URL
+	Discord SecretValue            `yaml:"discord"` // Discord webhook URL
+	// Message is the Slack or Discord message template. Defaults to a
+	// summary of the event.
+	Message string `yaml:"message"`
+}
+
+// Chat services with built-in message formatting.
+const (
+	ChatSlack   = "slack"
+	ChatDiscord = "discord"
+)
+
+// Chat returns the chat service and webhook URL of the notification, or an
+// empty service if it does not post to one.
+func (n Notification) Chat() (string, SecretValue) {
+	switch {
+	case n.Slack != (SecretValue{}):
+		return ChatSlack, n.Slack
+	case n.Discord != (SecretValue{}):
+		return ChatDiscord, n.Discord
+	default:
+		return "", SecretValue{}
+	}
+}
+
+// NotificationTemplateData defines available fields for notification command,
+// payload, and message templates.
+type NotificationTemplateData struct {
+	Type      string         // Event type, e.g. "session.created"
+	SessionID string         // Session the event is about, empty for global events
+	Name      string         // Session name, when the event records one
+	Time      string         // Event time in RFC3339
+	Data      map[string]any // Event-specific fields, as shown by 'hive events'
+	JSON      string         // The whole event as JSON
+}
+
+// validateNotifications checks each notification has events and exactly one
+// target. Template syntax is checked by ValidateDeep.
+func (c *Config) validateNotifications() error {
+	var errs criterio.FieldErrorsBuilder
+	for i, n := range c.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+
+		if len(n.Events) == 0 {
+			errs = errs.Append(field+".events", fmt.Errorf("must have at least one event"))
+		}
+		for _, pattern := range n.Events {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = errs.Append(field+".events", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+			}
+		}
+
+		for _, pattern := range n.Topics {
+			if _, err := path.Match(pattern, ""); err != nil {
+				errs = errs.Append(field+".topics", fmt.Errorf("invalid pattern %q: %w", pattern, err))
+			}
+		}
+
+		targets := 0
+		for _, set := range []bool{n.Command != "", n.Webhook != "", n.Slack != (SecretValue{}), n.Discord != (SecretValue{})} {
+			if set {
+				targets++
+			}
+		}
+		switch {
+		case targets == 0:
+			errs = errs.Append(field, fmt.Errorf("must have one of command, webhook, slack, or discord"))
+		case targets > 1:
+			errs = errs.Append(field, fmt.Errorf("can only have one of command, webhook, slack, or discord"))
+		}
+
+		for name, v := range map[string]SecretValue{"slack": n.Slack, "discord": n.Discord} {
+			if v == (SecretValue{}) {
+				continue
+			}
+			if v.Value == "" {
+				errs = errs.Append(field+"."+name, fmt.Errorf("!%s requires a value", v.Kind))
+			}
+			if v.Kind == SecretSecret && c.Secrets.Command == "" {
+				errs = errs.Append(field+"."+name, fmt.Errorf("!secret requires secrets.command to be set"))
+			}
+			if v.Kind == SecretPlain && !isHTTPURL(v.Value) {
+				errs = errs.Append(field+"."+name, fmt.Errorf("must be an http or https URL"))
+			}
+		}
+		if chat, _ := n.Chat(); chat == "" && n.Message != "" {
+			errs = errs.Append(field, fmt.Errorf("message requires slack or discord"))
+		}
+
+		if n.Webhook != "" && !isHTTPURL(n.Webhook) {
+			errs = errs.Append(field+".webhook", fmt.Errorf("must be an http or https URL"))
+		}
+		if n.Webhook == "" && (n.Payload != "" || len(n.Headers) > 0) {
+			errs = errs.Append(field, fmt.Errorf("payload and headers require webhook"))
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(n.Headers)) {
+			v := n.Headers[name]
+			hfield := fmt.Sprintf("%s.headers[%q]", field, name)
+			if strings.TrimSpace(name) == "" || strings.ContainsAny(name, " :\t\r\n") {
+				errs = errs.Append(hfield, fmt.Errorf("invalid header name"))
+			}
+			if v.Kind == SecretSecret && c.Secrets.Command == "" {
+				errs = errs.Append(hfield, fmt.Errorf("!secret requires secrets.command to be set"))
+			}
+		}
+	}
+	return errs.ToError()
+}
+
+// isHTTPURL reports whether s is an absolute http or https URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateNotificationTemplates checks notification command and payload
+// templates only reference NotificationTemplateData fields.
+func (c *Config) validateNotificationTemplates() error {
+	var errs criterio.FieldErrorsBuilder
+	for i, n := range c.Notifications {
+		field := fmt.Sprintf("notifications[%d]", i)
+		if n.Command != "" {
+			if err := validateTemplate(n.Command, NotificationTemplateData{}); err != nil {
+				errs = errs.Append(field+".command", fmt.Errorf("template error: %w", err))
+			}
+		}
+		if n.Payload != "" {
+			if err := validateTemplate(n.Payload, NotificationTemplateData{}); err != nil {
+				errs = errs.Append(field+".payload", fmt.Errorf("template error: %w", err))
+			}
+		}
+		if n.Message != "" {
+			if err := validateTemplate(n.Message, NotificationTemplateData{}); err != nil {
+				errs = errs.Append(field+".message", fmt.Errorf("template error: %w", err))
+			}
+		}
+	}
+	return errs.ToError()
+}
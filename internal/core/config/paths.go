@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hay-kot/criterio"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// DefaultSessionDirTemplate is the session directory layout used when
+// paths.session_dir_template is unset.
+const DefaultSessionDirTemplate = "{{ .Repo }}-{{ .Slug }}-{{ .ID }}"
+
+// PathsConfig controls where session directories are placed.
+type PathsConfig struct {
+	// SessionDirTemplate is a Go template for a session's directory, relative
+	// to the repos directory. It may contain "/" to nest directories, e.g.
+	// "{{ .Owner }}/{{ .Repo }}/{{ .Slug }}-{{ .ID }}".
+	SessionDirTemplate string `yaml:"session_dir_template"`
+}
+
+// SessionDirData is the data available to paths.session_dir_template.
+type SessionDirData struct {
+	Owner string // repository owner, e.g. "hay-kot"
+	Repo  string // repository name, e.g. "hive"
+	Slug  string // slugified session name
+	ID    string // session ID
+	Date  string // creation date, YYYY-MM-DD
+}
+
+// DirTemplate returns the session directory template, or
+// DefaultSessionDirTemplate if unset.
+func (p PathsConfig) DirTemplate() string {
+	if p.SessionDirTemplate == "" {
+		return DefaultSessionDirTemplate
+	}
+	return p.SessionDirTemplate
+}
+
+// SessionDir renders paths.session_dir_template and returns the absolute
+// session directory under ReposDir.
+func (c *Config) SessionDir(data SessionDirData) (string, error) {
+	rel, err := renderSessionDir(c.Paths.DirTemplate(), data)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(c.ReposDir(), rel), nil
+}
+
+// renderSessionDir renders a session directory template and checks that the
+// result is a clean relative path that stays inside the repos directory.
+func renderSessionDir(template string, data SessionDirData) (string, error) {
+	out, err := tmpl.Render(template, data)
+	if err != nil {
+		return "", err
+	}
+
+	rel := filepath.Clean(filepath.FromSlash(strings.TrimSpace(out)))
+	switch {
+	case rel == "." || rel == "":
+		return "", fmt.Errorf("session directory template rendered an empty path")
+	case filepath.IsAbs(rel):
+		return "", fmt.Errorf("session directory %q must be relative to the repos directory", rel)
+	case rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)):
+		return "", fmt.Errorf("session directory %q escapes the repos directory", rel)
+	}
+	return rel, nil
+}
+
+// validateSessionDirTemplate renders paths.session_dir_template with sample
+// data. The template must include the session ID so every session gets its
+// own directory.
+func (c *Config) validateSessionDirTemplate() error {
+	sample := SessionDirData{Owner: "owner", Repo: "repo", Slug: "slug", ID: "zz9sample", Date: "2006-01-02"}
+	rel, err := renderSessionDir(c.Paths.DirTemplate(), sample)
+	if err != nil {
+		return criterio.NewFieldErrors("paths.session_dir_template", err)
+	}
+	if !strings.Contains(rel, sample.ID) {
+		return criterio.NewFieldErrors("paths.session_dir_template", fmt.Errorf("must include {{ .ID }}"))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hay-kot/criterio"
+	"gopkg.in/yaml.v3"
+)
+
+// SecretKind identifies how a SecretValue is resolved.
+type SecretKind string
+
+const (
+	SecretPlain  SecretKind = ""       // literal value
+	SecretEnv    SecretKind = "env"    // read from the hive process environment
+	SecretSecret SecretKind = "secret" // resolved via secrets.command
+)
+
+// SecretValue is a config value that may reference a secret instead of
+// holding it verbatim. In YAML it is written as a plain scalar, or tagged
+// with !env NAME or !secret REF.
+type SecretValue struct {
+	Kind  SecretKind
+	Value string // literal value, env var name, or secret reference
+}
+
+// UnmarshalYAML decodes a scalar, honoring the !env and !secret tags.
+func (s *SecretValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("line %d: secret value must be a scalar", node.Line)
+	}
+
+	switch node.Tag {
+	case "!env":
+		s.Kind = SecretEnv
+	case "!secret":
+		s.Kind = SecretSecret
+	default:
+		s.Kind = SecretPlain
+	}
+	s.Value = node.Value
+	return nil
+}
+
+// MarshalYAML encodes the value with its tag so references round-trip.
+func (s SecretValue) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: s.Value}
+	if s.Kind != SecretPlain {
+		node.Tag = "!" + string(s.Kind)
+	}
+	return node, nil
+}
+
+// String never includes resolved secrets; references are shown by name.
+func (s SecretValue) String() string {
+	if s.Kind == SecretPlain {
+		return s.Value
+	}
+	return fmt.Sprintf("!%s %s", s.Kind, s.Value)
+}
+
+// SecretsConfig configures how !secret references are resolved.
+type SecretsConfig struct {
+	// Command is a shell template that prints the secret for {{ .Ref }} on stdout,
+	// e.g. "op read {{ .Ref | shq }}".
+	Command string `yaml:"command"`
+}
+
+// SecretTemplateData defines available fields for the secrets.command template.
+type SecretTemplateData struct {
+	Ref string // Secret reference from the !secret tag
+}
+
+// envNameRe matches valid environment variable names.
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateEnv checks env names and that secret references can be resolved.
+func (c *Config) validateEnv() error {
+	var errs criterio.FieldErrorsBuilder
+
+	names := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := fmt.Sprintf("env[%q]", name)
+		v := c.Env[name]
+
+		if !envNameRe.MatchString(name) {
+			errs = errs.Append(field, fmt.Errorf("invalid environment variable name"))
+		}
+		if v.Kind != SecretPlain && v.Value == "" {
+			errs = errs.Append(field, fmt.Errorf("!%s requires a value", v.Kind))
+		}
+		if v.Kind == SecretSecret && c.Secrets.Command == "" {
+			errs = errs.Append(field, fmt.Errorf("!secret requires secrets.command to be set"))
+		}
+	}
+
+	return errs.ToError()
+}
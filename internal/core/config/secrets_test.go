@@ -0,0 +1,83 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestSecretValue_UnmarshalYAML(t *testing.T) {
+	input := `env:
+  PLAIN: value
+  FROM_ENV: !env WORK_TOKEN
+  FROM_SECRET: !secret op://vault/item/field
+`
+
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte(input), &cfg))
+
+	assert.Equal(t, SecretValue{Kind: SecretPlain, Value: "value"}, cfg.Env["PLAIN"])
+	assert.Equal(t, SecretValue{Kind: SecretEnv, Value: "WORK_TOKEN"}, cfg.Env["FROM_ENV"])
+	assert.Equal(t, SecretValue{Kind: SecretSecret, Value: "op://vault/item/field"}, cfg.Env["FROM_SECRET"])
+	assert.Equal(t, "!secret op://vault/item/field", cfg.Env["FROM_SECRET"].String())
+}
+
+func TestSecretValue_RejectsNonScalar(t *testing.T) {
+	var cfg Config
+	err := yaml.Unmarshal([]byte("env:\n  TOKEN:\n    - a\n"), &cfg)
+	assert.Error(t, err)
+}
+
+func TestValidate_Env(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]SecretValue
+		command string
+		wantErr bool
+	}{
+		{
+			name: "plain and env values",
+			env: map[string]SecretValue{
+				"A": {Value: "x"},
+				"B": {Kind: SecretEnv, Value: "HOME"},
+			},
+		},
+		{
+			name:    "secret with command",
+			env:     map[string]SecretValue{"TOKEN": {Kind: SecretSecret, Value: "op://x"}},
+			command: "op read {{ .Ref | shq }}",
+		},
+		{
+			name:    "secret without command",
+			env:     map[string]SecretValue{"TOKEN": {Kind: SecretSecret, Value: "op://x"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			env:     map[string]SecretValue{"BAD-NAME": {Value: "x"}},
+			wantErr: true,
+		},
+		{
+			name:    "empty reference",
+			env:     map[string]SecretValue{"A": {Kind: SecretEnv}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Env = tt.env
+			cfg.Secrets.Command = tt.command
+
+			err := cfg.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
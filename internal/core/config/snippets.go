@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hay-kot/criterio"
+)
+
+// MessageSnippet is a canned message offered by the TUI message composer.
+// Its payload is inserted into the editor for further changes before
+// publishing.
+type MessageSnippet struct {
+	Topic   string `yaml:"topic,omitempty"` // topic filled in when the snippet is picked and none was entered
+	Payload string `yaml:"payload"`
+}
+
+// SnippetNames returns the configured message snippet names in sorted order.
+func (c MessagingConfig) SnippetNames() []string {
+	return slices.Sorted(maps.Keys(c.Snippets))
+}
+
+func (c *Config) validateMessageSnippets() error {
+	var errs criterio.FieldErrorsBuilder
+	for _, name := range c.Messaging.SnippetNames() {
+		field := fmt.Sprintf("messaging.snippets[%q]", name)
+		if !templateNameRe.MatchString(name) {
+			errs = errs.Append(field, fmt.Errorf("invalid name; use letters, digits, '-' and '_'"))
+		}
+		if c.Messaging.Snippets[name].Payload == "" {
+			errs = errs.Append(field+".payload", fmt.Errorf("is required"))
+		}
+	}
+	return errs.ToError()
+}
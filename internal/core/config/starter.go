@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Terminal is a terminal emulator or multiplexer hive can spawn sessions in.
+type Terminal struct {
+	Name  string // display name, e.g. WezTerm
+	Spawn string // spawn command template opening a window in the session directory
+}
+
+// DetectTerminal returns the terminal hive is running in, judged by the
+// variables terminals set in their environment. tmux wins over the emulator
+// hosting it. getenv is os.Getenv outside tests. It reports false when the
+// terminal is not recognized.
+func DetectTerminal(getenv func(string) string) (Terminal, bool) {
+	switch {
+	case getenv("TMUX") != "":
+		return Terminal{Name: "tmux", Spawn: `tmux new-window -c "{{ .Path }}" -n "{{ .Name }}"`}, true
+	case getenv("WEZTERM_PANE") != "" || getenv("TERM_PROGRAM") == "WezTerm":
+		return Terminal{Name: "WezTerm", Spawn: `wezterm cli spawn --cwd "{{ .Path }}"`}, true
+	case getenv("KITTY_WINDOW_ID") != "":
+		return Terminal{Name: "kitty", Spawn: `kitty @ launch --type=tab --cwd "{{ .Path }}"`}, true
+	case getenv("TERM_PROGRAM") == "ghostty":
+		if runtime.GOOS == "darwin" {
+			return Terminal{Name: "Ghostty", Spawn: `open -na Ghostty --args --working-directory="{{ .Path }}"`}, true
+		}
+		return Terminal{Name: "Ghostty", Spawn: `ghostty --working-directory="{{ .Path }}"`}, true
+	case getenv("TERM_PROGRAM") == "iTerm.app":
+		return Terminal{Name: "iTerm2", Spawn: `open -na iTerm "{{ .Path }}"`}, true
+	case getenv("TERM_PROGRAM") == "Apple_Terminal":
+		return Terminal{Name: "Terminal", Spawn: `open -a Terminal "{{ .Path }}"`}, true
+	default:
+		return Terminal{}, false
+	}
+}
+
+// StarterConfig returns a commented config file for a first run that spawns
+// sessions in term. A zero term leaves the spawn commands for the user to
+// fill in.
+func StarterConfig(term Terminal) string {
+	var b strings.Builder
+	b.WriteString("# hive config, check it with 'hive doctor'\n\n")
+
+	b.WriteString("# Directories to scan for repositories (enables 'n' in the TUI)\n")
+	b.WriteString("# repo_dirs:\n#   - ~/code\n\n")
+
+	if term.Name == "tmux" {
+		b.WriteString("# Terminal integration for real-time agent status\n")
+		b.WriteString("integrations:\n  terminal:\n    enabled: [tmux]\n\n")
+	}
+
+	b.WriteString("commands:\n")
+	if term.Spawn == "" {
+		b.WriteString("  # Commands that open a terminal in a new session, e.g.\n")
+		b.WriteString("  #   - 'wezterm cli spawn --cwd \"{{ .Path }}\" -- claude'\n")
+		b.WriteString("  spawn: []\n\n")
+	} else {
+		fmt.Fprintf(&b, "  # Opens a %s window in each new session\n", term.Name)
+		b.WriteString("  spawn:\n")
+		fmt.Fprintf(&b, "    - '%s'\n\n", term.Spawn)
+	}
+
+	b.WriteString("# Run for every repository after a session is created\n")
+	b.WriteString("rules:\n  - pattern: \"\"\n    commands:\n      - hive ctx init\n")
+	return b.String()
+}
+
+// WriteStarterConfig writes StarterConfig(term) to path, creating its
+// directory. It never overwrites an existing file.
+func WriteStarterConfig(path string, term Terminal) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("config file %s already exists", path)
+		}
+		return fmt.Errorf("create config file: %w", err)
+	}
+	if _, err := f.WriteString(StarterConfig(term)); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write config file: %w", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTerminal(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "tmux inside wezterm", env: map[string]string{"TMUX": "/tmp/tmux", "TERM_PROGRAM": "WezTerm"}, want: "tmux"},
+		{name: "wezterm", env: map[string]string{"WEZTERM_PANE": "0"}, want: "WezTerm"},
+		{name: "kitty", env: map[string]string{"KITTY_WINDOW_ID": "1"}, want: "kitty"},
+		{name: "unknown", env: map[string]string{"TERM_PROGRAM": "vscode"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, ok := DetectTerminal(func(key string) string { return tt.env[key] })
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, term.Name)
+		})
+	}
+}
+
+func TestWriteStarterConfig(t *testing.T) {
+	for _, term := range []Terminal{{}, {Name: "tmux", Spawn: `tmux new-window -c "{{ .Path }}" -n "{{ .Name }}"`}} {
+		t.Run(term.Name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hive", "config.yaml")
+			require.NoError(t, WriteStarterConfig(path, term))
+
+			cfg, err := Load(path, t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, cfg.ValidateDeep(path))
+			if term.Spawn != "" {
+				assert.Equal(t, []string{term.Spawn}, cfg.Commands.Spawn)
+				assert.Equal(t, []string{"tmux"}, cfg.Integrations.Terminal.Enabled)
+			}
+		})
+	}
+}
+
+func TestWriteStarterConfig_KeepsExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("repo_dirs: []\n"), 0o644))
+
+	err := WriteStarterConfig(path, Terminal{})
+	require.ErrorContains(t, err, "already exists")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "repo_dirs: []\n", string(data))
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateFileExts are the extensions recognized as template files in templates_dir.
+var TemplateFileExts = []string{".yaml", ".yml"}
+
+// TemplatesPath returns the absolute templates directory, expanding a
+// leading ~ and resolving relative paths against the config file's
+// directory. Returns "" if templates_dir is not set.
+func (c *Config) TemplatesPath(configPath string) string {
+	dir := c.TemplatesDir
+	if dir == "" {
+		return ""
+	}
+
+	if strings.HasPrefix(dir, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, dir[1:])
+		}
+	}
+
+	if !filepath.IsAbs(dir) && configPath != "" {
+		dir = filepath.Join(filepath.Dir(configPath), dir)
+	}
+
+	return dir
+}
+
+// ParseTemplateFile decodes a single template from YAML. The template name
+// is not part of the file; it comes from the file name.
+func ParseTemplateFile(data []byte) (Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return Template{}, fmt.Errorf("parse template: %w", err)
+	}
+	return t, nil
+}
+
+// TemplateNameFromFile returns the template name for a file path, or false
+// if the file does not have a template extension.
+func TemplateNameFromFile(path string) (string, bool) {
+	base := filepath.Base(path)
+	for _, ext := range TemplateFileExts {
+		if name, ok := strings.CutSuffix(base, ext); ok && name != "" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// loadTemplatesDir reads every template file in dir. A missing directory is
+// not an error so templates_dir can point at a location that is populated later.
+func loadTemplatesDir(dir string) (map[string]Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read templates dir: %w", err)
+	}
+
+	templates := make(map[string]Template)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name, ok := TemplateNameFromFile(entry.Name())
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read template %s: %w", path, err)
+		}
+
+		t, err := ParseTemplateFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, dup := templates[name]; dup {
+			return nil, fmt.Errorf("%s: duplicate template %q", path, name)
+		}
+
+		t.Source = path
+		templates[name] = t
+	}
+
+	return templates, nil
+}
+
+// mergeTemplates adds templates from files that are not already defined
+// inline in the config. Inline templates take precedence.
+func (c *Config) mergeTemplates(files map[string]Template) {
+	if len(files) == 0 {
+		return
+	}
+	if c.Templates == nil {
+		c.Templates = make(map[string]Template, len(files))
+	}
+	for name, t := range files {
+		if _, ok := c.Templates[name]; !ok {
+			c.Templates[name] = t
+		}
+	}
+}
+
+// ValidateTemplate checks a single template's name, fields, and prompt the
+// same way config validation does.
+func ValidateTemplate(name string, t Template) error {
+	c := &Config{Templates: map[string]Template{name: t}}
+	if err := c.validatePromptTemplates(); err != nil {
+		return err
+	}
+	return c.validatePromptTemplateSyntax()
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoad_TemplatesDir(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.yaml")
+
+	writeFile(t, configPath, `
+templates_dir: templates
+templates:
+  shared:
+    prompt: inline wins
+`)
+	writeFile(t, filepath.Join(root, "templates", "pr-review.yaml"), `
+description: Review a PR
+prompt: "Review #{{ .pr_number }}"
+fields:
+  - name: pr_number
+    required: true
+`)
+	writeFile(t, filepath.Join(root, "templates", "shared.yml"), "prompt: from file\n")
+	writeFile(t, filepath.Join(root, "templates", "README.md"), "ignored")
+
+	cfg, err := Load(configPath, t.TempDir())
+	require.NoError(t, err)
+
+	require.Contains(t, cfg.Templates, "pr-review")
+	pr := cfg.Templates["pr-review"]
+	assert.Equal(t, "Review a PR", pr.Description)
+	assert.Equal(t, filepath.Join(root, "templates", "pr-review.yaml"), pr.Source)
+
+	assert.Equal(t, "inline wins", cfg.Templates["shared"].Prompt)
+	assert.Empty(t, cfg.Templates["shared"].Source)
+	assert.Len(t, cfg.Templates, 2)
+}
+
+func TestLoad_TemplatesDirMissing(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.yaml")
+	writeFile(t, configPath, "templates_dir: does-not-exist\n")
+
+	cfg, err := Load(configPath, t.TempDir())
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Templates)
+}
+
+func TestLoad_TemplatesDirInvalidFile(t *testing.T) {
+	root := t.TempDir()
+	configPath := filepath.Join(root, "config.yaml")
+	writeFile(t, configPath, "templates_dir: templates\n")
+	writeFile(t, filepath.Join(root, "templates", "bad.yaml"), "prompt: [unclosed\n")
+
+	_, err := Load(configPath, t.TempDir())
+	require.ErrorContains(t, err, "bad.yaml")
+}
+
+func TestTemplateNameFromFile(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{path: "/a/pr-review.yaml", want: "pr-review", wantOK: true},
+		{path: "fix.yml", want: "fix", wantOK: true},
+		{path: "notes.md", wantOK: false},
+		{path: ".yaml", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := TemplateNameFromFile(tt.path)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	require.NoError(t, ValidateTemplate("ok", Template{
+		Prompt: "{{ .a }}",
+		Fields: []TemplateField{{Name: "a"}},
+	}))
+
+	require.Error(t, ValidateTemplate("../escape", Template{Prompt: "x"}))
+	require.ErrorContains(t, ValidateTemplate("ok", Template{Prompt: "{{ .b }}"}), "unknown variable .b")
+}
+
+func TestValidateTemplate_FieldTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   TemplateField
+		wantErr string
+	}{
+		{name: "string", field: TemplateField{Name: "a"}},
+		{name: "number default", field: TemplateField{Name: "a", Type: FieldNumber, Default: "1.5"}},
+		{name: "bad number default", field: TemplateField{Name: "a", Type: FieldNumber, Default: "x"}, wantErr: "invalid default"},
+		{name: "bool default", field: TemplateField{Name: "a", Type: FieldBool, Default: "false"}},
+		{name: "bad bool default", field: TemplateField{Name: "a", Type: FieldBool, Default: "nah"}, wantErr: "invalid default"},
+		{name: "select options", field: TemplateField{Name: "a", Type: FieldSelect, Options: []string{"x"}, Default: "x"}},
+		{name: "select default not in options", field: TemplateField{Name: "a", Type: FieldSelect, Options: []string{"x"}, Default: "y"}, wantErr: "not one of"},
+		{name: "select options_cmd", field: TemplateField{Name: "a", Type: FieldSelect, OptionsCmd: "ls"}},
+		{name: "select without options", field: TemplateField{Name: "a", Type: FieldSelect}, wantErr: "requires options"},
+		{name: "select both", field: TemplateField{Name: "a", Type: FieldSelect, Options: []string{"x"}, OptionsCmd: "ls"}, wantErr: "cannot set both"},
+		{name: "options on string", field: TemplateField{Name: "a", Options: []string{"x"}}, wantErr: "require type select"},
+		{name: "unknown type", field: TemplateField{Name: "a", Type: "date"}, wantErr: `invalid type "date"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate("t", Template{Prompt: "{{ .a }}", Fields: []TemplateField{tt.field}})
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
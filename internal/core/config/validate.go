@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"reflect"
 	"regexp"
+	"slices"
+	"strings"
+	"text/template/parse"
 
 	"github.com/hay-kot/criterio"
 	"github.com/hay-kot/hive/pkg/tmpl"
@@ -12,38 +16,92 @@ import (
 
 // SpawnTemplateData defines available fields for spawn command templates (hive new).
 type SpawnTemplateData struct {
-	Path       string // Absolute path to the session directory
-	Name       string // Session name (directory basename)
-	Slug       string // Session slug (URL-safe version of name)
-	ContextDir string // Path to context directory
-	Owner      string // Repository owner
-	Repo       string // Repository name
+	Path        string // Absolute path to the session's working directory
+	Root        string // Absolute path to the repository root
+	Name        string // Session name (directory basename)
+	Slug        string // Session slug (URL-safe version of name)
+	ContextDir  string // Path to context directory
+	Owner       string // Repository owner
+	Repo        string // Repository name
+	ToolSession string // AI tool session ID linked with "hive session link"
+	ReviewOf    string // Reviewed session ID, for read-only review sessions
 }
 
 // BatchSpawnTemplateData defines available fields for batch_spawn command templates (hive batch).
 type BatchSpawnTemplateData struct {
-	Path       string // Absolute path to the session directory
-	Name       string // Session name (directory basename)
-	Prompt     string // User-provided prompt (batch only)
+	Path        string // Absolute path to the session's working directory
+	Root        string // Absolute path to the repository root
+	Name        string // Session name (directory basename)
+	Prompt      string // User-provided prompt (batch only)
+	Slug        string // Session slug (URL-safe version of name)
+	ContextDir  string // Path to context directory
+	Owner       string // Repository owner
+	Repo        string // Repository name
+	ToolSession string // AI tool session ID linked with "hive session link"
+	ReviewOf    string // Reviewed session ID, for read-only review sessions
+}
+
+// spawnValidationData accepts .Prompt in spawn templates. At runtime it
+// renders empty for `hive new`, so Warnings() reports it instead of failing.
+type spawnValidationData struct {
+	SpawnTemplateData
+	Prompt string
+}
+
+// RecycleTemplateData defines available fields for recycle command templates.
+type RecycleTemplateData struct {
+	DefaultBranch string // Default branch name (e.g., "main" or "master")
+	Path          string // Absolute path to the session directory, before it is renamed
+	ID            string // Unique session identifier
+	Name          string // Session name (display name)
+	Remote        string // Git remote URL
+}
+
+// HookTemplateData defines available fields for rule commands and lifecycle
+// hooks. The same values are exported as HIVE_* environment variables.
+type HookTemplateData struct {
+	ID         string // Unique session identifier
+	Name       string // Session name (display name)
 	Slug       string // Session slug (URL-safe version of name)
+	Path       string // Absolute path to the session directory
+	WorkDir    string // Working directory; Path joined with the session's subdir, if any
+	Remote     string // Git remote URL
+	Prompt     string // Prompt the session was created with (creation only)
 	ContextDir string // Path to context directory
 	Owner      string // Repository owner
 	Repo       string // Repository name
 }
 
-// RecycleTemplateData defines available fields for recycle command templates.
-type RecycleTemplateData struct {
-	DefaultBranch string // Default branch name (e.g., "main" or "master")
+// CITemplateData defines available fields for the ci trigger and topic
+// templates of rules. It mirrors hive.CIData.
+type CITemplateData struct {
+	HookTemplateData
+	Branch string // Branch checked out in the session
+	Topic  string // Topic CI reports its status on (trigger only)
 }
 
 // KeybindingTemplateData defines available fields for keybinding shell templates.
 type KeybindingTemplateData struct {
-	Path   string // Absolute path to the session directory
+	Path   string // Absolute path to the session's working directory
+	Root   string // Absolute path to the repository root
 	Remote string // Git remote URL (origin)
 	ID     string // Unique session identifier
 	Name   string // Session name (directory basename)
 }
 
+// KeybindingURLTemplateData defines available fields for open_url keybinding
+// url templates.
+type KeybindingURLTemplateData struct {
+	ID            string // Unique session identifier
+	Name          string // Session name
+	Remote        string // Git remote URL (origin)
+	Owner         string // Repository owner
+	Repo          string // Repository name
+	OwnerRepo     string // Owner and name, e.g. hay-kot/hive
+	Branch        string // Branch checked out in the session
+	DefaultBranch string // Default branch of the repository
+}
+
 // ValidationWarning represents a non-fatal configuration issue.
 type ValidationWarning struct {
 	Category string `json:"category"`
@@ -62,14 +120,44 @@ func (c *Config) ValidateDeep(configPath string) error {
 
 	return criterio.ValidateStruct(
 		c.validateFileAccess(configPath),
-		validateTemplates("commands.spawn", c.Commands.Spawn, SpawnTemplateData{}),
+		validateTemplates("commands.spawn", c.Commands.Spawn, spawnValidationData{}),
 		validateTemplates("commands.batch_spawn", c.Commands.BatchSpawn, BatchSpawnTemplateData{}),
+		validateTemplates("commands.resume", c.Commands.Resume, BatchSpawnTemplateData{}),
 		validateTemplates("commands.recycle", c.Commands.Recycle, RecycleTemplateData{}),
+		c.validateSpawnProfileTemplates(),
 		c.validateRules(),
+		c.validateRuleTemplates(),
 		c.validateKeybindingTemplates(),
+		c.validateSecretsCommand(),
+		c.validatePromptTemplateSyntax(),
+		c.validateNotificationTemplates(),
 	)
 }
 
+// validatePromptTemplateSyntax checks each template prompt only references
+// its declared fields.
+func (c *Config) validatePromptTemplateSyntax() error {
+	var errs criterio.FieldErrorsBuilder
+	for name, t := range c.Templates {
+		data := make(map[string]any, len(t.Fields))
+		for _, f := range t.Fields {
+			data[f.Name] = f.Zero()
+		}
+		if err := validateTemplate(t.Prompt, data); err != nil {
+			errs = errs.Append(fmt.Sprintf("templates[%q].prompt", name), fmt.Errorf("template error: %w", err))
+		}
+	}
+	return errs.ToError()
+}
+
+// validateSecretsCommand checks the secrets.command template if set.
+func (c *Config) validateSecretsCommand() error {
+	if c.Secrets.Command == "" {
+		return nil
+	}
+	return validateTemplates("secrets.command", []string{c.Secrets.Command}, SecretTemplateData{})
+}
+
 // Warnings returns non-fatal configuration issues.
 func (c *Config) Warnings() []ValidationWarning {
 	var warnings []ValidationWarning
@@ -82,8 +170,26 @@ func (c *Config) Warnings() []ValidationWarning {
 		})
 	}
 
+	for i, cmd := range c.Commands.Spawn {
+		if templateUsesField(cmd, "Prompt") {
+			warnings = append(warnings, ValidationWarning{
+				Category: "Spawn Commands",
+				Item:     fmt.Sprintf("commands.spawn[%d]", i),
+				Message:  "{{.Prompt}} is always empty in spawn; move this command to batch_spawn (see 'hive config migrate')",
+			})
+		}
+	}
+
+	if len(c.Commands.BatchSpawn) == 0 && len(c.Commands.Spawn) > 0 {
+		warnings = append(warnings, ValidationWarning{
+			Category: "Spawn Commands",
+			Item:     "commands.batch_spawn",
+			Message:  "batch_spawn is not set; 'hive batch' falls back to spawn and prompts are not passed",
+		})
+	}
+
 	for i, rule := range c.Rules {
-		if len(rule.Commands) == 0 && len(rule.Copy) == 0 {
+		if len(rule.Commands) == 0 && len(rule.Copy) == 0 && rule.Hooks.IsEmpty() && rule.Spawn == "" {
 			warnings = append(warnings, ValidationWarning{
 				Category: "Rules",
 				Item:     fmt.Sprintf("rule %d", i),
@@ -176,6 +282,44 @@ func (c *Config) validateRules() error {
 	return errs.ToError()
 }
 
+// validateSpawnProfileTemplates checks template syntax for spawn profiles,
+// which receive the same data as batch_spawn.
+func (c *Config) validateSpawnProfileTemplates() error {
+	errs := make([]error, 0, len(c.Commands.SpawnProfiles))
+	for name, commands := range c.Commands.SpawnProfiles {
+		errs = append(errs, validateTemplates(fmt.Sprintf("commands.spawn_profiles[%q]", name), commands, BatchSpawnTemplateData{}))
+	}
+	return criterio.ValidateStruct(errs...)
+}
+
+// validateRuleTemplates checks template syntax for rule commands and hooks.
+func (c *Config) validateRuleTemplates() error {
+	var errs []error
+	for i, rule := range c.Rules {
+		errs = append(errs, validateTemplates(fmt.Sprintf("rules[%d].commands", i), rule.Commands, HookTemplateData{}))
+		for _, event := range []string{HookPostCreate, HookPreRecycle, HookPostRecycle, HookPreDelete} {
+			errs = append(errs, validateTemplates(fmt.Sprintf("rules[%d].hooks.%s", i, event), rule.Hooks.For(event), HookTemplateData{}))
+		}
+		if rule.CI != nil {
+			errs = append(errs, validateCITemplates(i, *rule.CI))
+		}
+	}
+	return criterio.ValidateStruct(errs...)
+}
+
+// validateCITemplates checks template syntax for the ci trigger and topic
+// of rule i.
+func validateCITemplates(i int, ci CIConfig) error {
+	var errs criterio.FieldErrorsBuilder
+	if err := validateTemplate(ci.Trigger, CITemplateData{}); err != nil {
+		errs = errs.Append(fmt.Sprintf("rules[%d].ci.trigger", i), fmt.Errorf("template error: %w", err))
+	}
+	if err := validateTemplate(ci.Topic, CITemplateData{}); err != nil {
+		errs = errs.Append(fmt.Sprintf("rules[%d].ci.topic", i), fmt.Errorf("template error: %w", err))
+	}
+	return errs.ToError()
+}
+
 // validateKeybindingTemplates checks template syntax for keybinding shell commands.
 // Basic keybinding structure validation is done by Validate().
 func (c *Config) validateKeybindingTemplates() error {
@@ -186,12 +330,130 @@ func (c *Config) validateKeybindingTemplates() error {
 				errs = errs.Append(fmt.Sprintf("keybindings[%q]", key), fmt.Errorf("template error in sh: %w", err))
 			}
 		}
+		if kb.URL != "" {
+			if err := validateTemplate(kb.URL, KeybindingURLTemplateData{}); err != nil {
+				errs = errs.Append(fmt.Sprintf("keybindings[%q]", key), fmt.Errorf("template error in url: %w", err))
+			}
+		}
 	}
 	return errs.ToError()
 }
 
-// validateTemplate checks if a template string is valid.
+// validateTemplate checks if a template string is valid and only references
+// fields available in data. Fields are checked statically so references in
+// branches that are not taken with zero-value data are still caught.
 func validateTemplate(tmplStr string, data any) error {
-	_, err := tmpl.Render(tmplStr, data)
+	t, err := tmpl.Parse(tmplStr)
+	if err != nil {
+		return err
+	}
+
+	available := templateFieldNames(data)
+	for _, field := range collectTemplateFields(t.Root) {
+		if !slices.Contains(available, field) {
+			return fmt.Errorf("unknown variable .%s (available: %s)", field, formatFields(available))
+		}
+	}
+
+	_, err = tmpl.Render(tmplStr, data)
 	return err
 }
+
+// templateUsesField reports whether tmplStr references .field at the top level.
+func templateUsesField(tmplStr, field string) bool {
+	t, err := tmpl.Parse(tmplStr)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(collectTemplateFields(t.Root), field)
+}
+
+// templateFieldNames returns the exported field names of a struct value,
+// including promoted fields of embedded structs, or the sorted keys of a
+// string-keyed map.
+func templateFieldNames(data any) []string {
+	rt := reflect.TypeOf(data)
+	if rt == nil {
+		return nil
+	}
+
+	if rt.Kind() == reflect.Map && rt.Key().Kind() == reflect.String {
+		var names []string
+		for _, k := range reflect.ValueOf(data).MapKeys() {
+			names = append(names, k.String())
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if rt.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for _, f := range reflect.VisibleFields(rt) {
+		if f.IsExported() && !f.Anonymous {
+			names = append(names, f.Name)
+		}
+	}
+	return names
+}
+
+func formatFields(fields []string) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = "." + f
+	}
+	return strings.Join(parts, ", ")
+}
+
+// collectTemplateFields returns the top-level field names referenced on dot.
+// Bodies of range and with blocks are skipped because they rebind dot.
+func collectTemplateFields(node parse.Node) []string {
+	var fields []string
+
+	var walk func(n parse.Node)
+	walk = func(n parse.Node) {
+		switch n := n.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Nodes {
+				walk(c)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.ElseList)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, c := range n.Cmds {
+				walk(c)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.FieldNode:
+			if len(n.Ident) > 0 && !slices.Contains(fields, n.Ident[0]) {
+				fields = append(fields, n.Ident[0])
+			}
+		}
+	}
+
+	walk(node)
+	return fields
+}
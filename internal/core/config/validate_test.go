@@ -5,10 +5,12 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hay-kot/criterio"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // validConfig returns a Config with all required fields set for testing.
@@ -18,6 +20,7 @@ func validConfig(t *testing.T) *Config {
 		GitPath: "git",
 		DataDir: t.TempDir(),
 		Git:     GitConfig{StatusWorkers: 1},
+		Batch:   BatchConfig{Concurrency: 1},
 	}
 }
 
@@ -77,6 +80,8 @@ func TestValidateDeep_ValidRecycleTemplate(t *testing.T) {
 			"git fetch origin",
 			"git checkout {{.DefaultBranch}}",
 			"git reset --hard origin/{{.DefaultBranch}}",
+			"tar czf ~/archive/{{ .ID }}-{{ .Name | shq }}.tgz -C {{ .Path | shq }} dist",
+			"echo {{ .Remote }}",
 		},
 	}
 
@@ -99,6 +104,27 @@ func TestValidateDeep_InvalidRulePattern(t *testing.T) {
 	assert.Contains(t, fieldErrs[0].Err.Error(), "invalid regex")
 }
 
+func TestValidateDeep_RuleTemplates(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Rules = []Rule{
+		{
+			Commands: []string{"echo {{ .Name }} {{ .Owner }}/{{ .Repo }}"},
+			Hooks: RuleHooks{
+				PostCreate: []string{"notify {{ .ID }}"},
+				PreDelete:  []string{"docker rm {{ .Unknown }}"},
+			},
+		},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Len(t, fieldErrs, 1)
+	assert.Equal(t, "rules[0].hooks.pre_delete[0]", fieldErrs[0].Field)
+	assert.Contains(t, fieldErrs[0].Err.Error(), "template error")
+}
+
 func TestValidateDeep_KeybindingBothActionAndSh(t *testing.T) {
 	cfg := validConfig(t)
 	cfg.Keybindings = map[string]Keybinding{
@@ -141,6 +167,50 @@ func TestValidateDeep_KeybindingInvalidAction(t *testing.T) {
 	assert.Contains(t, fieldErrs[0].Err.Error(), "invalid action")
 }
 
+func TestValidateDeep_KeybindingChord(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Keybindings = map[string]Keybinding{
+		"g p":  {Sh: "git push"},
+		"g  f": {Sh: "git fetch"},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Len(t, fieldErrs, 1)
+	assert.Equal(t, `keybindings["g  f"]`, fieldErrs[0].Field)
+	assert.Contains(t, fieldErrs[0].Err.Error(), "single space")
+}
+
+func TestValidateDeep_KeybindingOpenURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		kb      Keybinding
+		wantErr string
+	}{
+		{name: "valid", kb: Keybinding{Action: ActionOpenURL, URL: "https://github.com/{{ .OwnerRepo }}/compare/{{ .Branch }}"}},
+		{name: "missing url", kb: Keybinding{Action: ActionOpenURL}, wantErr: "requires url"},
+		{name: "url without open_url", kb: Keybinding{Action: ActionOpen, URL: "https://example.com"}, wantErr: "only used by action"},
+		{name: "unknown field", kb: Keybinding{Action: ActionOpenURL, URL: "https://example.com/{{ .Path }}"}, wantErr: "template error in url"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Keybindings = map[string]Keybinding{"u": tt.kb}
+
+			err := cfg.ValidateDeep("")
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestValidateDeep_KeybindingInvalidShTemplate(t *testing.T) {
 	cfg := validConfig(t)
 	cfg.Keybindings = map[string]Keybinding{
@@ -268,8 +338,8 @@ func TestWarnings_EmptyRecycleCommands(t *testing.T) {
 func TestValidateDeep_ValidRulesWithCopy(t *testing.T) {
 	cfg := validConfig(t)
 	cfg.Rules = []Rule{
-		{Pattern: "", Copy: []string{".envrc"}},
-		{Pattern: "^https://github.com/.*", Copy: []string{"*.yaml"}},
+		{Pattern: "", Copy: copyGlobs(".envrc")},
+		{Pattern: "^https://github.com/.*", Copy: copyGlobs("*.yaml")},
 	}
 
 	err := cfg.ValidateDeep("")
@@ -282,7 +352,7 @@ func TestValidateDeep_ValidRulesWithCommandsAndCopy(t *testing.T) {
 		{
 			Pattern:  "^https://github.com/hay-kot/.*",
 			Commands: []string{"mise trust", "task dep:sync"},
-			Copy:     []string{".envrc", "configs/*.yaml"},
+			Copy:     copyGlobs(".envrc", "configs/*.yaml"),
 		},
 	}
 
@@ -422,3 +492,608 @@ func TestValidate_MaxRecycledNegative(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestValidateDeep_UnknownVariableListsAvailable(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Commands = Commands{
+		Recycle: []string{"git checkout {{ if false }}{{ .Branch }}{{ end }}"},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "commands.recycle[0]", fieldErrs[0].Field)
+	assert.Contains(t, fieldErrs[0].Err.Error(), "unknown variable .Branch")
+	assert.Contains(t, fieldErrs[0].Err.Error(), ".DefaultBranch")
+}
+
+func TestValidateDeep_BatchSpawnPromptAllowed(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Commands = Commands{
+		Spawn:      []string{"echo {{ .Path }}"},
+		BatchSpawn: []string{"claude {{ .Prompt | shq }}", "echo {{ .Typo }}"},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "commands.batch_spawn[1]", fieldErrs[0].Field)
+}
+
+func TestWarnings_PromptInSpawn(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Commands = Commands{
+		Spawn:      []string{"claude {{ .Prompt | shq }}"},
+		BatchSpawn: []string{"claude {{ .Prompt | shq }}"},
+		Recycle:    []string{"git reset --hard"},
+	}
+
+	require.NoError(t, cfg.ValidateDeep(""), "prompt in spawn is a warning, not an error")
+
+	warnings := cfg.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "commands.spawn[0]", warnings[0].Item)
+	assert.Contains(t, warnings[0].Message, "batch_spawn")
+}
+
+func TestWarnings_BatchSpawnFallback(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Commands = Commands{
+		Spawn:   []string{"echo {{ .Path }}"},
+		Recycle: []string{"git reset --hard"},
+	}
+
+	warnings := cfg.Warnings()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "commands.batch_spawn", warnings[0].Item)
+}
+
+func TestWarnings_TemplatedRuleCommand(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Commands = Commands{Recycle: []string{"git reset --hard"}}
+	cfg.Rules = []Rule{{Commands: []string{"echo {{ .Path }}"}}}
+
+	// Rule commands are rendered as templates, so {{ }} is not a mistake
+	assert.Empty(t, cfg.Warnings())
+}
+
+func TestValidate_PromptTemplates(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Templates = map[string]Template{
+		"bad name!": {Prompt: "x"},
+		"no-prompt": {},
+		"bad-fields": {
+			Prompt: "x",
+			Fields: []TemplateField{{Name: "pr-number"}, {Name: "a"}, {Name: "a"}},
+		},
+	}
+
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	assert.Len(t, fieldErrs, 4)
+	assert.Contains(t, err.Error(), "invalid name")
+	assert.Contains(t, err.Error(), "is required")
+	assert.Contains(t, err.Error(), `invalid name "pr-number"`)
+	assert.Contains(t, err.Error(), `duplicate field "a"`)
+}
+
+func TestValidateDeep_PromptTemplateUnknownField(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Templates = map[string]Template{
+		"pr-review": {
+			Prompt: "Review #{{ .pr_number }} {{ .missing }}",
+			Fields: []TemplateField{{Name: "pr_number"}},
+		},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, `templates["pr-review"].prompt`, fieldErrs[0].Field)
+	assert.Contains(t, fieldErrs[0].Err.Error(), "unknown variable .missing (available: .pr_number)")
+}
+
+func TestValidate_BatchMaxFailuresNegative(t *testing.T) {
+	n := -1
+	cfg := validConfig(t)
+	cfg.Batch.MaxFailures = &n
+
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "batch.max_failures", fieldErrs[0].Field)
+}
+
+func TestValidate_StoreBackend(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Store.Backend = StoreSQLite
+	require.NoError(t, cfg.Validate())
+
+	cfg.Store.Backend = "postgres"
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "store.backend", fieldErrs[0].Field)
+}
+
+func TestValidate_GroupBy(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.TUI.GroupBy = GroupByOwner
+	require.NoError(t, cfg.Validate())
+
+	cfg.TUI.GroupBy = "org"
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "tui.group_by", fieldErrs[0].Field)
+}
+
+func TestValidate_Icons(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.TUI.Icons = map[string]string{"active": "*", "current_repo": "@"}
+	require.NoError(t, cfg.Validate())
+
+	cfg.TUI.Icons = map[string]string{"spinner": "|", "ready": ""}
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	fields := make([]string, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		fields[i] = fe.Field
+	}
+	assert.ElementsMatch(t, []string{`tui.icons["spinner"]`, `tui.icons["ready"]`}, fields)
+}
+
+func TestValidate_RuleHookPolicy(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Rules = []Rule{
+		{Commands: []string{"npm install"}, HookPolicy: HookPolicy{Timeout: 5 * time.Minute, Retries: 2, OnFailure: OnFailureWarn}},
+		{Commands: []string{"make"}, HookPolicy: HookPolicy{Timeout: -time.Second, Retries: -1, OnFailure: "ignore"}},
+		{Commands: []string{"seed"}, HookPolicy: HookPolicy{Background: true, Retries: 1}},
+		{Commands: []string{"build"}, HookPolicy: HookPolicy{Background: true, Timeout: time.Minute}},
+	}
+
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	fields := make([]string, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		fields[i] = fe.Field
+	}
+	assert.ElementsMatch(t, []string{"rules[1].timeout", "rules[1].retries", "rules[1].on_failure", "rules[3].timeout"}, fields)
+}
+
+func TestRuleHookPolicy_YAML(t *testing.T) {
+	var rule Rule
+	require.NoError(t, yaml.Unmarshal([]byte("commands: [npm install]\ntimeout: 2m\nretries: 3\non_failure: continue\n"), &rule))
+	assert.Equal(t, HookPolicy{Timeout: 2 * time.Minute, Retries: 3, OnFailure: OnFailureContinue}, rule.HookPolicy)
+}
+
+func TestValidate_SpawnProfiles(t *testing.T) {
+	t.Run("rule references unknown profile", func(t *testing.T) {
+		cfg := validConfig(t)
+		cfg.Commands.SpawnProfiles = map[string][]string{"review": {"tmux new-window"}}
+		cfg.Rules = []Rule{{Pattern: ".*", Spawn: "shell"}}
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown spawn profile")
+	})
+
+	t.Run("profile without commands", func(t *testing.T) {
+		cfg := validConfig(t)
+		cfg.Commands.SpawnProfiles = map[string][]string{"review": nil}
+
+		err := cfg.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "spawn_profiles")
+	})
+
+	t.Run("invalid profile template", func(t *testing.T) {
+		cfg := validConfig(t)
+		cfg.Commands.SpawnProfiles = map[string][]string{"review": {"claude {{ .Unknown }}"}}
+
+		err := cfg.ValidateDeep("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `commands.spawn_profiles["review"]`)
+	})
+}
+
+func TestSpawnProfileFor(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{Pattern: "", Spawn: "claude"},
+			{Pattern: ".*/docs\\.git$", Spawn: "shell"},
+			{Pattern: ".*", Commands: []string{"make"}},
+		},
+	}
+
+	assert.Equal(t, "claude", cfg.SpawnProfileFor("https://github.com/org/app.git"))
+	assert.Equal(t, "shell", cfg.SpawnProfileFor("https://github.com/org/docs.git"))
+	assert.Empty(t, (&Config{}).SpawnProfileFor("https://github.com/org/app.git"))
+}
+
+func TestIssueConfigFor(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{Pattern: "", Issues: &IssueConfig{Template: "issue"}},
+			{Pattern: ".*gitlab\\.acme\\.com.*", Issues: &IssueConfig{Provider: IssueProviderGitLab}},
+			{Pattern: ".*", Commands: []string{"make"}},
+		},
+	}
+
+	assert.Equal(t, IssueConfig{Template: "issue"}, cfg.IssueConfigFor("https://github.com/org/app.git"))
+	assert.Equal(t, IssueConfig{Provider: IssueProviderGitLab}, cfg.IssueConfigFor("git@gitlab.acme.com:org/app.git"))
+	assert.Equal(t, IssueConfig{}, (&Config{}).IssueConfigFor("https://github.com/org/app.git"))
+}
+
+func TestValidateIssues(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Templates = map[string]Template{"issue": {Prompt: "Fix {{ .issue_title }}"}}
+	cfg.Rules = []Rule{{Issues: &IssueConfig{Provider: IssueProviderGitHub, Token: SecretValue{Kind: SecretEnv, Value: "GITHUB_TOKEN"}, Template: "issue"}}}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Rules = []Rule{{Issues: &IssueConfig{
+		Provider: "jira",
+		Token:    SecretValue{Kind: SecretSecret, Value: "github"},
+		APIURL:   "ftp://example.com",
+		Template: "missing",
+	}}}
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, field := range []string{"rules[0].issues.provider", "rules[0].issues.token", "rules[0].issues.api_url", "rules[0].issues.template"} {
+		assert.Contains(t, err.Error(), field)
+	}
+}
+
+func TestCIConfigFor(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{Pattern: "", CI: &CIConfig{Trigger: "gh workflow run ci.yml"}},
+			{Pattern: ".*/docs\\.git$", CI: &CIConfig{Trigger: "make ci", Topic: "ci.docs", Timeout: time.Minute}},
+			{Pattern: ".*", Commands: []string{"make"}},
+		},
+	}
+
+	assert.Equal(t, CIConfig{Trigger: "gh workflow run ci.yml", Timeout: DefaultCITimeout}, cfg.CIConfigFor("https://github.com/org/app.git"))
+	assert.Equal(t, CIConfig{Trigger: "make ci", Topic: "ci.docs", Timeout: time.Minute}, cfg.CIConfigFor("https://github.com/org/docs.git"))
+	assert.Empty(t, (&Config{}).CIConfigFor("https://github.com/org/app.git").Trigger)
+}
+
+func TestValidateCI(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Rules = []Rule{{CI: &CIConfig{Trigger: "gh workflow run ci.yml --ref {{ .Branch }}", Topic: "ci.{{ .Repo }}"}}}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Rules = []Rule{{CI: &CIConfig{Timeout: -time.Second}}}
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, field := range []string{"rules[0].ci.trigger", "rules[0].ci.timeout"} {
+		assert.Contains(t, err.Error(), field)
+	}
+
+	require.ErrorContains(t, validateCITemplates(0, CIConfig{Trigger: "make ci", Topic: "ci.{{ .Prompt2 }}"}), "rules[0].ci.topic")
+	require.NoError(t, validateCITemplates(0, CIConfig{Trigger: "make ci HIVE_TOPIC={{ .Topic }} ID={{ .ID }}"}))
+}
+
+func TestPromptPreambleFor(t *testing.T) {
+	cfg := &Config{
+		Rules: []Rule{
+			{Pattern: "", PromptPreamble: "Run make lint before committing.\n"},
+			{Pattern: ".*/docs\\.git$", PromptPreamble: "Keep lines under 80 columns."},
+			{Pattern: ".*", Commands: []string{"make"}},
+		},
+	}
+
+	assert.Equal(t, "Run make lint before committing.", cfg.PromptPreambleFor("https://github.com/org/app.git"))
+	assert.Equal(t, "Run make lint before committing.\n\nKeep lines under 80 columns.", cfg.PromptPreambleFor("https://github.com/org/docs.git"))
+	assert.Empty(t, (&Config{}).PromptPreambleFor("https://github.com/org/app.git"))
+}
+
+func TestLoad_PromptPreambleFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "preamble.md"), []byte("Use conventional commits.\n"), 0o644))
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`rules:
+  - pattern: ""
+    prompt_preamble: preamble.md
+  - pattern: ".*"
+    prompt_preamble: Run make lint before committing.
+`), 0o644))
+
+	cfg, err := Load(path, t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, "Use conventional commits.\n", cfg.Rules[0].PromptPreamble)
+	assert.Equal(t, "Run make lint before committing.", cfg.Rules[1].PromptPreamble)
+
+	require.NoError(t, os.WriteFile(path, []byte(`rules:
+  - prompt_preamble: ./missing.md
+`), 0o644))
+	_, err = Load(path, t.TempDir())
+	require.ErrorContains(t, err, "rules[0].prompt_preamble")
+}
+
+func copyGlobs(patterns ...string) []CopySpec {
+	specs := make([]CopySpec, len(patterns))
+	for i, p := range patterns {
+		specs[i] = CopySpec{From: p}
+	}
+	return specs
+}
+
+func TestCopySpec_UnmarshalYAML(t *testing.T) {
+	var rule Rule
+	err := yaml.Unmarshal([]byte(`
+copy:
+  - .tool-versions
+  - from: .envrc.tmpl
+    to: .envrc
+    template: true
+`), &rule)
+	require.NoError(t, err)
+	assert.Equal(t, []CopySpec{
+		{From: ".tool-versions"},
+		{From: ".envrc.tmpl", To: ".envrc", Template: true},
+	}, rule.Copy)
+
+	out, err := yaml.Marshal(rule.Copy)
+	require.NoError(t, err)
+	assert.Equal(t, "- .tool-versions\n- from: .envrc.tmpl\n  to: .envrc\n  template: true\n", string(out))
+}
+
+func TestValidate_CopySpecs(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  CopySpec
+		field string
+	}{
+		{name: "missing from", spec: CopySpec{To: ".envrc"}, field: "rules[0].copy[0].from"},
+		{name: "glob with to", spec: CopySpec{From: "*.env", To: "env"}, field: "rules[0].copy[0].to"},
+		{name: "absolute to", spec: CopySpec{From: ".envrc", To: "/etc/envrc"}, field: "rules[0].copy[0].to"},
+		{name: "escaping to", spec: CopySpec{From: ".envrc", To: "../.envrc"}, field: "rules[0].copy[0].to"},
+		{name: "invalid exclude", spec: CopySpec{From: "configs/**", Exclude: []string{"[abc"}}, field: "rules[0].copy[0].exclude[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Rules = []Rule{{Copy: []CopySpec{tt.spec}}}
+
+			err := cfg.Validate()
+
+			var fieldErrs criterio.FieldErrors
+			require.ErrorAs(t, err, &fieldErrs)
+			require.Len(t, fieldErrs, 1)
+			assert.Equal(t, tt.field, fieldErrs[0].Field)
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ByteSize
+		wantErr bool
+	}{
+		{in: "2048", want: 2048},
+		{in: "512KB", want: 512 << 10},
+		{in: "10 mb", want: 10 << 20},
+		{in: "1.5GB", want: 3 << 29},
+		{in: "100B", want: 100},
+		{in: "ten", wantErr: true},
+		{in: "-1MB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseByteSize(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var rule Rule
+	require.NoError(t, yaml.Unmarshal([]byte("max_file_size: 10MB"), &rule))
+	assert.Equal(t, ByteSize(10<<20), rule.MaxFileSize)
+	assert.Equal(t, "10MB", rule.MaxFileSize.String())
+}
+
+func TestValidate_Notifications(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       Notification
+		wantErr string
+	}{
+		{name: "command", n: Notification{Events: []string{"session.*"}, Command: "echo hi"}},
+		{name: "webhook", n: Notification{Events: []string{"status.approval"}, Webhook: "https://example.com/hook", Payload: "{}"}},
+		{name: "no events", n: Notification{Command: "echo hi"}, wantErr: "at least one event"},
+		{name: "bad pattern", n: Notification{Events: []string{"session.["}, Command: "echo hi"}, wantErr: "invalid pattern"},
+		{name: "no target", n: Notification{Events: []string{"session.created"}}, wantErr: "must have one of command, webhook, slack, or discord"},
+		{name: "both targets", n: Notification{Events: []string{"session.created"}, Command: "echo", Webhook: "https://example.com"}, wantErr: "can only have one of"},
+		{name: "bad url", n: Notification{Events: []string{"session.created"}, Webhook: "example.com"}, wantErr: "http or https URL"},
+		{name: "payload without webhook", n: Notification{Events: []string{"session.created"}, Command: "echo", Payload: "{}"}, wantErr: "require webhook"},
+		{name: "slack", n: Notification{Events: []string{"message.published"}, Topics: []string{"agent.*.inbox"}, Slack: SecretValue{Kind: SecretEnv, Value: "SLACK_WEBHOOK_URL"}, Message: "{{ .Name }}"}},
+		{name: "discord", n: Notification{Events: []string{"batch.completed"}, Discord: SecretValue{Value: "https://discord.com/api/webhooks/1/x"}}},
+		{name: "slack and webhook", n: Notification{Events: []string{"session.created"}, Webhook: "https://example.com", Slack: SecretValue{Value: "https://hooks.slack.com/x"}}, wantErr: "can only have one of"},
+		{name: "bad slack url", n: Notification{Events: []string{"session.created"}, Slack: SecretValue{Value: "hooks.slack.com"}}, wantErr: "notifications[0].slack"},
+		{name: "discord secret without command", n: Notification{Events: []string{"session.created"}, Discord: SecretValue{Kind: SecretSecret, Value: "discord"}}, wantErr: "requires secrets.command"},
+		{name: "message without chat", n: Notification{Events: []string{"session.created"}, Command: "echo", Message: "hi"}, wantErr: "message requires slack or discord"},
+		{name: "bad topic pattern", n: Notification{Events: []string{"message.published"}, Topics: []string{"agent.["}, Command: "echo"}, wantErr: "notifications[0].topics"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Notifications = []Notification{tt.n}
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateDeep_NotificationTemplates(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Notifications = []Notification{
+		{Events: []string{"session.created"}, Command: "echo {{ .Name }} {{ .SessionID }}"},
+		{Events: []string{"batch.completed"}, Webhook: "https://example.com", Payload: `{"id": "{{ .Invalid }}"}`},
+	}
+
+	err := cfg.ValidateDeep("")
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	require.Len(t, fieldErrs, 1)
+	assert.Equal(t, "notifications[1].payload", fieldErrs[0].Field)
+}
+
+func TestValidate_Hosts(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Hosts = map[string]HostConfig{
+		"server": {SSH: "me@server", Relay: []string{"handoff.*"}},
+		"local":  {SSH: "localhost"},
+		"nossh":  {},
+		"badre":  {SSH: "x", Relay: []string{"["}},
+	}
+
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	fields := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		fields = append(fields, fe.Field)
+	}
+	assert.ElementsMatch(t, []string{`hosts["badre"].relay`, `hosts["local"]`, `hosts["nossh"].ssh`}, fields)
+}
+
+func TestValidate_MessageSnippets(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Messaging.Snippets = map[string]MessageSnippet{
+		"handoff":  {Topic: "handoff.review", Payload: "Ready for review"},
+		"bad name": {Payload: "x"},
+		"empty":    {Topic: "build"},
+	}
+
+	err := cfg.Validate()
+
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, err, &fieldErrs)
+	fields := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		fields = append(fields, fe.Field)
+	}
+	assert.ElementsMatch(t, []string{`messaging.snippets["bad name"]`, `messaging.snippets["empty"].payload`}, fields)
+}
+
+func TestValidate_MessageRateLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     RateLimitConfig
+		wantField string
+	}{
+		{name: "disabled", limit: RateLimitConfig{}},
+		{name: "enabled", limit: RateLimitConfig{Messages: 60, Per: time.Minute}},
+		{name: "negative messages", limit: RateLimitConfig{Messages: -1, Per: time.Minute}, wantField: "messaging.rate_limit.messages"},
+		{name: "missing window", limit: RateLimitConfig{Messages: 10}, wantField: "messaging.rate_limit.per"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Messaging.RateLimit = tt.limit
+
+			err := cfg.Validate()
+			if tt.wantField == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var fieldErrs criterio.FieldErrors
+			require.ErrorAs(t, err, &fieldErrs)
+			assert.Equal(t, tt.wantField, fieldErrs[0].Field)
+		})
+	}
+}
+
+func TestValidate_Heartbeat(t *testing.T) {
+	cfg := validConfig(t)
+	cfg.Messaging.Heartbeat.Interval = 30 * time.Second
+	require.NoError(t, cfg.Validate())
+
+	cfg.Messaging.Heartbeat.Interval = 100 * time.Millisecond
+	var fieldErrs criterio.FieldErrors
+	require.ErrorAs(t, cfg.Validate(), &fieldErrs)
+	assert.Equal(t, "messaging.heartbeat.interval", fieldErrs[0].Field)
+}
+
+func TestValidate_SessionDirTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "default", template: ""},
+		{name: "nested by owner", template: "{{ .Owner }}/{{ .Repo }}/{{ .Date }}-{{ .Slug }}-{{ .ID }}"},
+		{name: "missing id", template: "{{ .Repo }}-{{ .Slug }}", wantErr: "must include"},
+		{name: "unknown field", template: "{{ .Branch }}-{{ .ID }}", wantErr: "Branch"},
+		{name: "parse error", template: "{{ .ID", wantErr: "parse template"},
+		{name: "absolute", template: "/tmp/{{ .ID }}", wantErr: "must be relative"},
+		{name: "escapes", template: "../{{ .ID }}", wantErr: "escapes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig(t)
+			cfg.Paths.SessionDirTemplate = tt.template
+
+			err := cfg.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, "paths.session_dir_template")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_SessionDir(t *testing.T) {
+	cfg := validConfig(t)
+	data := SessionDirData{Owner: "hay-kot", Repo: "hive", Slug: "fix-auth", ID: "abc123", Date: "2026-01-02"}
+
+	dir, err := cfg.SessionDir(data)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cfg.ReposDir(), "hive-fix-auth-abc123"), dir)
+
+	cfg.Paths.SessionDirTemplate = "{{ .Owner }}/{{ .Repo }}/{{ .Slug }}-{{ .ID }}"
+	dir, err = cfg.SessionDir(data)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(cfg.ReposDir(), "hay-kot", "hive", "fix-auth-abc123"), dir)
+}
@@ -3,6 +3,7 @@ package git
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -22,6 +23,12 @@ func NewExecutor(gitPath string, exec executil.Executor) *Executor {
 }
 
 func (e *Executor) Clone(ctx context.Context, url, dest string) error {
+	if w := ProgressWriter(ctx); w != nil {
+		if err := e.exec.RunStream(ctx, io.Discard, w, e.gitPath, "clone", "--progress", url, dest); err != nil {
+			return fmt.Errorf("git clone: %w", err)
+		}
+		return nil
+	}
 	if _, err := e.exec.Run(ctx, e.gitPath, "clone", url, dest); err != nil {
 		return fmt.Errorf("git clone: %w", err)
 	}
@@ -36,6 +43,12 @@ func (e *Executor) Checkout(ctx context.Context, dir, branch string) error {
 }
 
 func (e *Executor) Pull(ctx context.Context, dir string) error {
+	if w := ProgressWriter(ctx); w != nil {
+		if err := e.exec.RunDirStream(ctx, dir, io.Discard, w, e.gitPath, "pull", "--progress"); err != nil {
+			return fmt.Errorf("git pull: %w", err)
+		}
+		return nil
+	}
 	if _, err := e.exec.RunDir(ctx, dir, e.gitPath, "pull"); err != nil {
 		return fmt.Errorf("git pull: %w", err)
 	}
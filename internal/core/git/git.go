@@ -3,6 +3,10 @@ package git
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -30,6 +34,67 @@ type Git interface {
 	IsValidRepo(ctx context.Context, dir string) error
 }
 
+// progressKey is the context key of the git progress writer.
+type progressKey struct{}
+
+// WithProgress returns a context in which Clone and Pull stream git's
+// progress output, such as "Receiving objects:  45% (450/1000)", to w.
+func WithProgress(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, progressKey{}, w)
+}
+
+// ProgressWriter returns the progress writer of ctx, or nil if there is none.
+func ProgressWriter(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(progressKey{}).(io.Writer)
+	return w
+}
+
+// IsLocalRemote reports whether remote is a local repository rather than a
+// hosted one: a file:// URL or a path starting with /, ./, ../, or ~/.
+func IsLocalRemote(remote string) bool {
+	if strings.HasPrefix(remote, "file://") || remote == "." || remote == ".." {
+		return true
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/"} {
+		if strings.HasPrefix(remote, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeRemote returns remote in the form sessions store it. Local remotes
+// become the absolute, cleaned path of the repository, so the same directory
+// matches however it was written; the directory must exist. Hosted remotes
+// are returned unchanged.
+func NormalizeRemote(remote string) (string, error) {
+	if !IsLocalRemote(remote) {
+		return remote, nil
+	}
+
+	path := strings.TrimPrefix(remote, "file://")
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand %s: %w", remote, err)
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", remote, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("local remote %s: %w", remote, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("local remote %s is not a directory", remote)
+	}
+	return path, nil
+}
+
 // ExtractRepoName extracts the repository name from a git remote URL.
 // Handles both SSH (git@github.com:user/repo.git) and HTTPS (https://github.com/user/repo.git) formats.
 func ExtractRepoName(remote string) string {
@@ -72,3 +137,36 @@ func ExtractOwnerRepo(remote string) (owner, repo string) {
 
 	return "", ""
 }
+
+// ExtractHostPath splits a hosted git remote into its host and repository
+// path, e.g. ("gitlab.com", "org/subgroup/repo"). It handles SSH
+// (git@host:path), ssh:// and https:// URLs, and drops ports, users, and a
+// .git suffix. Returns empty strings for local remotes and unparseable URLs.
+func ExtractHostPath(remote string) (host, path string) {
+	if IsLocalRemote(remote) {
+		return "", ""
+	}
+	remote = strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+
+	if _, rest, ok := strings.Cut(remote, "://"); ok {
+		host, path, _ = strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host[strings.LastIndex(host, "@")+1:], ":")
+	} else if h, p, ok := strings.Cut(remote, ":"); ok {
+		host, path = h[strings.LastIndex(h, "@")+1:], p
+	}
+
+	if host == "" || path == "" {
+		return "", ""
+	}
+	return host, strings.Trim(path, "/")
+}
+
+// MatchesRepo reports whether remote is the repository repo, given as
+// owner/name or just name. Comparison is case-insensitive.
+func MatchesRepo(remote, repo string) bool {
+	owner, name := ExtractOwnerRepo(remote)
+	if wantOwner, wantName, ok := strings.Cut(repo, "/"); ok {
+		return strings.EqualFold(owner, wantOwner) && strings.EqualFold(name, wantName)
+	}
+	return strings.EqualFold(name, repo)
+}
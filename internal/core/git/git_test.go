@@ -1,6 +1,10 @@
 package git
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
 
 func TestExtractOwnerRepo(t *testing.T) {
 	tests := []struct {
@@ -14,6 +18,7 @@ func TestExtractOwnerRepo(t *testing.T) {
 		{"https://github.com/hay-kot/hive", "hay-kot", "hive"},
 		{"git@gitlab.com:org/subgroup/repo.git", "subgroup", "repo"},
 		{"https://gitlab.com/org/subgroup/repo.git", "subgroup", "repo"},
+		{"/srv/mirrors/hay-kot/hive.git", "hay-kot", "hive"},
 		{"invalid", "", ""},
 		{"", "", ""},
 	}
@@ -29,6 +34,33 @@ func TestExtractOwnerRepo(t *testing.T) {
 	}
 }
 
+func TestExtractHostPath(t *testing.T) {
+	tests := []struct {
+		remote   string
+		wantHost string
+		wantPath string
+	}{
+		{"git@github.com:hay-kot/hive.git", "github.com", "hay-kot/hive"},
+		{"https://github.com/hay-kot/hive.git", "github.com", "hay-kot/hive"},
+		{"https://user@github.example.com/hay-kot/hive/", "github.example.com", "hay-kot/hive"},
+		{"ssh://git@gitlab.com:2222/org/subgroup/repo.git", "gitlab.com", "org/subgroup/repo"},
+		{"git@gitlab.com:org/subgroup/repo.git", "gitlab.com", "org/subgroup/repo"},
+		{"/srv/mirrors/hay-kot/hive.git", "", ""},
+		{"file:///srv/mirrors/hive.git", "", ""},
+		{"invalid", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			host, path := ExtractHostPath(tt.remote)
+			if host != tt.wantHost || path != tt.wantPath {
+				t.Errorf("ExtractHostPath(%q) = (%q, %q), want (%q, %q)",
+					tt.remote, host, path, tt.wantHost, tt.wantPath)
+			}
+		})
+	}
+}
+
 func TestExtractRepoName(t *testing.T) {
 	tests := []struct {
 		remote   string
@@ -49,3 +81,63 @@ func TestExtractRepoName(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchesRepo(t *testing.T) {
+	tests := []struct {
+		remote string
+		repo   string
+		want   bool
+	}{
+		{"git@github.com:hay-kot/hive.git", "hay-kot/hive", true},
+		{"https://github.com/hay-kot/hive", "Hay-Kot/Hive", true},
+		{"https://github.com/hay-kot/hive", "hive", true},
+		{"https://github.com/hay-kot/hive", "other/hive", false},
+		{"https://github.com/hay-kot/hive", "hiv", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchesRepo(tt.remote, tt.repo); got != tt.want {
+			t.Errorf("MatchesRepo(%q, %q) = %v, want %v", tt.remote, tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeRemote(t *testing.T) {
+	dir := t.TempDir()
+	mirror := filepath.Join(dir, "mirrors", "hive.git")
+	if err := os.MkdirAll(mirror, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	tests := []struct {
+		remote  string
+		want    string
+		wantErr bool
+	}{
+		{remote: "git@github.com:hay-kot/hive.git", want: "git@github.com:hay-kot/hive.git"},
+		{remote: "https://github.com/hay-kot/hive", want: "https://github.com/hay-kot/hive"},
+		{remote: mirror + "/", want: mirror},
+		{remote: "./mirrors/hive.git", want: mirror},
+		{remote: "file://" + mirror, want: mirror},
+		{remote: "./missing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			got, err := NormalizeRemote(tt.remote)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeRemote(%q) = %q, want error", tt.remote, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeRemote(%q): %v", tt.remote, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeRemote(%q) = %q, want %q", tt.remote, got, tt.want)
+			}
+		})
+	}
+}
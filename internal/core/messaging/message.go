@@ -9,7 +9,43 @@ type Message struct {
 	Payload   string    `json:"payload"`
 	Sender    string    `json:"sender,omitempty"`
 	SessionID string    `json:"session_id,omitempty"`
+	Host      string    `json:"host,omitempty"`     // hostname of the publisher
+	User      string    `json:"user,omitempty"`     // OS user of the publisher
+	Tool      string    `json:"tool,omitempty"`     // AI tool the publisher ran under, if detected
+	Priority  Priority  `json:"priority,omitempty"` // empty means normal
 	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"` // zero means the message does not expire
+}
+
+// Expired reports whether the message has an expiry at or before now.
+func (m Message) Expired(now time.Time) bool {
+	return !m.ExpiresAt.IsZero() && !m.ExpiresAt.After(now)
+}
+
+// Origin returns "user@host" for the message's publisher, or whichever of
+// the two is set. Returns empty string when neither is known.
+func (m Message) Origin() string {
+	switch {
+	case m.User != "" && m.Host != "":
+		return m.User + "@" + m.Host
+	case m.User != "":
+		return m.User
+	default:
+		return m.Host
+	}
+}
+
+// SenderID returns the identity a message is attributed to: its Sender,
+// else its SessionID, else its Origin. Returns empty string when none is set.
+func (m Message) SenderID() string {
+	switch {
+	case m.Sender != "":
+		return m.Sender
+	case m.SessionID != "":
+		return m.SessionID
+	default:
+		return m.Origin()
+	}
 }
 
 // Topic represents a named channel for messages.
@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"os"
+	"os/user"
+)
+
+// toolEnv maps environment variables set by AI tools to the tool's name.
+// The first variable found set wins.
+var toolEnv = []struct {
+	env  string
+	tool string
+}{
+	{"CLAUDECODE", "claude"},
+	{"GEMINI_CLI", "gemini"},
+	{"CODEX_SANDBOX", "codex"},
+	{"CURSOR_AGENT", "cursor"},
+}
+
+// Stamp fills the message's Host, User, and Tool from the current process,
+// leaving fields that are already set untouched. Lookups that fail leave the
+// field empty.
+func Stamp(msg *Message) {
+	if msg.Host == "" {
+		msg.Host, _ = os.Hostname()
+	}
+	if msg.User == "" {
+		msg.User = currentUser()
+	}
+	if msg.Tool == "" {
+		msg.Tool = DetectTool()
+	}
+}
+
+// DetectTool returns the name of the AI tool the process runs under, from
+// HIVE_TOOL if set and otherwise from variables the tools export. Returns
+// empty string when no tool is detected.
+func DetectTool() string {
+	if tool := os.Getenv("HIVE_TOOL"); tool != "" {
+		return tool
+	}
+	for _, t := range toolEnv {
+		if os.Getenv(t.env) != "" {
+			return t.tool
+		}
+	}
+	return ""
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
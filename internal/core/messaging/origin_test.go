@@ -0,0 +1,42 @@
+package messaging
+
+import "testing"
+
+func TestStamp(t *testing.T) {
+	t.Setenv("HIVE_TOOL", "")
+	t.Setenv("CLAUDECODE", "1")
+
+	msg := Message{Host: "laptop"}
+	Stamp(&msg)
+
+	if msg.Host != "laptop" {
+		t.Errorf("Host = %q, want preset value kept", msg.Host)
+	}
+	if msg.Tool != "claude" {
+		t.Errorf("Tool = %q, want %q", msg.Tool, "claude")
+	}
+
+	t.Setenv("HIVE_TOOL", "aider")
+	msg = Message{}
+	Stamp(&msg)
+	if msg.Tool != "aider" {
+		t.Errorf("Tool = %q, want HIVE_TOOL override %q", msg.Tool, "aider")
+	}
+}
+
+func TestMessageOrigin(t *testing.T) {
+	tests := []struct {
+		msg  Message
+		want string
+	}{
+		{Message{User: "me", Host: "laptop"}, "me@laptop"},
+		{Message{User: "me"}, "me"},
+		{Message{Host: "laptop"}, "laptop"},
+		{Message{}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.msg.Origin(); got != tt.want {
+			t.Errorf("Origin() = %q, want %q", got, tt.want)
+		}
+	}
+}
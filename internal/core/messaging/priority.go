@@ -0,0 +1,47 @@
+package messaging
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Priority ranks how urgently a message should be read. The empty value is
+// treated as PriorityNormal.
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+// priorities lists the priorities from lowest to highest.
+var priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh, PriorityUrgent}
+
+// ParsePriority parses a priority name. An empty name is PriorityNormal.
+func ParsePriority(s string) (Priority, error) {
+	if s == "" {
+		return PriorityNormal, nil
+	}
+	if p := Priority(s); slices.Contains(priorities, p) {
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown priority %q (expected low, normal, high, or urgent)", s)
+}
+
+// Rank returns the priority's position from lowest (0) to highest.
+func (p Priority) Rank() int {
+	if i := slices.Index(priorities, p); i >= 0 {
+		return i
+	}
+	return 1 // unset or unknown reads as normal
+}
+
+// SortByPriority orders messages from highest to lowest priority, keeping
+// messages of equal priority in their existing order.
+func SortByPriority(messages []Message) {
+	slices.SortStableFunc(messages, func(a, b Message) int {
+		return b.Priority.Rank() - a.Priority.Rank()
+	})
+}
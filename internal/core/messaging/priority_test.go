@@ -0,0 +1,39 @@
+package messaging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	for _, s := range []string{"low", "normal", "high", "urgent"} {
+		if p, err := ParsePriority(s); err != nil || string(p) != s {
+			t.Errorf("ParsePriority(%q) = %q, %v", s, p, err)
+		}
+	}
+	if p, err := ParsePriority(""); err != nil || p != PriorityNormal {
+		t.Errorf("ParsePriority(\"\") = %q, %v; want normal", p, err)
+	}
+	if _, err := ParsePriority("critical"); err == nil {
+		t.Error("ParsePriority(\"critical\") succeeded, want error")
+	}
+}
+
+func TestSortByPriority(t *testing.T) {
+	messages := []Message{
+		{ID: "1", Priority: PriorityLow},
+		{ID: "2"},
+		{ID: "3", Priority: PriorityUrgent},
+		{ID: "4", Priority: PriorityNormal},
+		{ID: "5", Priority: PriorityHigh},
+	}
+	SortByPriority(messages)
+
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.ID)
+	}
+	if want := []string{"3", "5", "2", "4", "1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
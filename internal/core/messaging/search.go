@@ -0,0 +1,91 @@
+package messaging
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Match is the byte range [Start, End) of a search term in a payload.
+type Match struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// SearchResult is a message that matched a search, with the ranges of its
+// payload that matched.
+type SearchResult struct {
+	Message
+	Matches []Match `json:"matches"`
+}
+
+// Search returns the messages whose payload contains every whitespace
+// separated term of query, ignoring case, in the order given. When sender is
+// set, only messages whose Sender or SessionID equals it are considered.
+// An empty query matches every message with no highlighted ranges.
+func Search(messages []Message, query, sender string) []SearchResult {
+	terms := strings.Fields(query)
+	patterns := make([]*regexp.Regexp, len(terms))
+	for i, term := range terms {
+		patterns[i] = regexp.MustCompile("(?i)" + regexp.QuoteMeta(term))
+	}
+
+	var results []SearchResult
+	for _, msg := range messages {
+		if sender != "" && msg.Sender != sender && msg.SessionID != sender {
+			continue
+		}
+
+		var matches []Match
+		found := true
+		for _, re := range patterns {
+			locs := re.FindAllStringIndex(msg.Payload, -1)
+			if len(locs) == 0 {
+				found = false
+				break
+			}
+			for _, loc := range locs {
+				matches = append(matches, Match{Start: loc[0], End: loc[1]})
+			}
+		}
+		if !found {
+			continue
+		}
+
+		results = append(results, SearchResult{Message: msg, Matches: mergeMatches(matches)})
+	}
+	return results
+}
+
+// mergeMatches sorts matches and joins overlapping ranges, so each byte of
+// the payload is highlighted at most once.
+func mergeMatches(matches []Match) []Match {
+	if len(matches) == 0 {
+		return []Match{}
+	}
+
+	slices.SortFunc(matches, func(a, b Match) int { return a.Start - b.Start })
+	merged := []Match{matches[0]}
+	for _, m := range matches[1:] {
+		last := &merged[len(merged)-1]
+		if m.Start <= last.End {
+			last.End = max(last.End, m.End)
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+// Highlight returns payload with each match wrapped by mark.
+func Highlight(payload string, matches []Match, mark func(string) string) string {
+	var b strings.Builder
+	prev := 0
+	for _, m := range matches {
+		b.WriteString(payload[prev:m.Start])
+		b.WriteString(mark(payload[m.Start:m.End]))
+		prev = m.End
+	}
+	b.WriteString(payload[prev:])
+	return b.String()
+}
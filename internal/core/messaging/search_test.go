@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	messages := []Message{
+		{ID: "1", Payload: "Build failed on main", Sender: "abc"},
+		{ID: "2", Payload: "build passed", Sender: "def", SessionID: "s2"},
+		{ID: "3", Payload: "deploy started", Sender: "abc"},
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		sender string
+		want   []string
+	}{
+		{"case insensitive", "BUILD", "", []string{"1", "2"}},
+		{"all terms", "build failed", "", []string{"1"}},
+		{"sender", "build", "def", []string{"2"}},
+		{"session id as sender", "", "s2", []string{"2"}},
+		{"no match", "rollback", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, r := range Search(messages, tt.query, tt.sender) {
+				got = append(got, r.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q, %q) = %v, want %v", tt.query, tt.sender, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearch_Highlight(t *testing.T) {
+	results := Search([]Message{{Payload: "the build, then build again"}}, "build uild the", "")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+
+	want := []Match{{0, 3}, {4, 9}, {11, 14}, {16, 21}}
+	if !reflect.DeepEqual(results[0].Matches, want) {
+		t.Errorf("Matches = %v, want %v", results[0].Matches, want)
+	}
+
+	got := Highlight(results[0].Payload, results[0].Matches, func(s string) string { return "[" + s + "]" })
+	if want := "[the] [build], [the]n [build] again"; got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
@@ -5,18 +5,33 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hay-kot/hive/internal/core/session"
 )
 
-// SessionDetector finds the current session from the working directory.
+// SessionDetector finds the current session from the working directory. It
+// is safe for concurrent use: active sessions are read from the store once,
+// and each path's result is cached, so repeated lookups in one process cost
+// nothing. Call Reset to pick up sessions created since the first lookup.
 type SessionDetector struct {
 	store session.Store
+
+	mu       sync.Mutex
+	sessions []detectorSession // active sessions; nil until first lookup
+	cache    map[string]string // resolved path -> session ID
+}
+
+// detectorSession is an active session with the forms of its path that a
+// working directory may be under.
+type detectorSession struct {
+	sess  session.Session
+	paths []string // cleaned path and, if different, its symlink-resolved form
 }
 
 // NewSessionDetector creates a new session detector.
 func NewSessionDetector(store session.Store) *SessionDetector {
-	return &SessionDetector{store: store}
+	return &SessionDetector{store: store, cache: make(map[string]string)}
 }
 
 // DetectSession returns the session ID for the current working directory.
@@ -29,44 +44,82 @@ func (d *SessionDetector) DetectSession(ctx context.Context) (string, error) {
 	return d.DetectSessionFromPath(ctx, cwd)
 }
 
-// DetectSessionFromPath returns the session ID for the given path.
+// DetectSessionFromPath returns the session ID for the given path, which may
+// be the session directory, any directory below it, or a symlink to either.
 // Returns empty string if the path is not within a hive session.
 func (d *SessionDetector) DetectSessionFromPath(ctx context.Context, path string) (string, error) {
-	sessions, err := d.store.List(ctx)
-	if err != nil {
-		return "", nil // Not an error - just can't detect
-	}
-
 	// Clean and normalize the path
-	path, err = filepath.Abs(path)
+	path, err := filepath.Abs(path)
 	if err != nil {
 		return "", nil
 	}
-	path = filepath.Clean(path)
+	candidates := pathForms(path)
 
-	// Find the longest matching session path (most specific match)
-	var bestMatch session.Session
-	var bestMatchLen int
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if id, ok := d.cache[candidates[0]]; ok {
+		return id, nil
+	}
 
-	for _, sess := range sessions {
-		if sess.State != session.StateActive {
-			continue
+	if d.sessions == nil {
+		sessions, err := d.store.List(ctx)
+		if err != nil {
+			return "", nil // Not an error - just can't detect
 		}
+		d.sessions = make([]detectorSession, 0, len(sessions))
+		for _, sess := range sessions {
+			if sess.State == session.StateActive {
+				d.sessions = append(d.sessions, detectorSession{sess: sess, paths: pathForms(sess.Path)})
+			}
+		}
+	}
 
-		sessPath := filepath.Clean(sess.Path)
+	// Find the longest matching session path (most specific match)
+	var bestMatch session.Session
+	var bestMatchLen int
 
-		// Check if path equals or is within the session path
-		if path == sessPath || isSubpath(sessPath, path) {
-			if len(sessPath) > bestMatchLen {
-				bestMatch = sess
-				bestMatchLen = len(sessPath)
+	for _, ds := range d.sessions {
+		for _, sessPath := range ds.paths {
+			for _, p := range candidates {
+				if p != sessPath && !isSubpath(sessPath, p) {
+					continue
+				}
+				// A review session shares its directory with the reviewed
+				// session, which wins ties.
+				if len(sessPath) > bestMatchLen || (len(sessPath) == bestMatchLen && bestMatch.IsReview() && !ds.sess.IsReview()) {
+					bestMatch = ds.sess
+					bestMatchLen = len(sessPath)
+				}
 			}
 		}
 	}
 
+	for _, p := range candidates {
+		d.cache[p] = bestMatch.ID
+	}
 	return bestMatch.ID, nil
 }
 
+// Reset drops the cached sessions and lookups.
+func (d *SessionDetector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sessions = nil
+	clear(d.cache)
+}
+
+// pathForms returns the cleaned path and, when it differs, the path with
+// symlinks resolved. Paths that do not exist are only cleaned.
+func pathForms(path string) []string {
+	path = filepath.Clean(path)
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil || resolved == path {
+		return []string{path}
+	}
+	return []string{path, resolved}
+}
+
 // isSubpath returns true if child is a subdirectory of parent.
 func isSubpath(parent, child string) bool {
 	// Ensure parent ends with separator for correct prefix matching
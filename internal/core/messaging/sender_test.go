@@ -2,6 +2,10 @@ package messaging
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/hay-kot/hive/internal/core/session"
@@ -10,9 +14,11 @@ import (
 // mockSessionStore implements session.Store for testing.
 type mockSessionStore struct {
 	sessions []session.Session
+	lists    atomic.Int32
 }
 
 func (m *mockSessionStore) List(_ context.Context) ([]session.Session, error) {
+	m.lists.Add(1)
 	return m.sessions, nil
 }
 
@@ -33,6 +39,11 @@ func (m *mockSessionStore) Delete(_ context.Context, _ string) error {
 	return nil
 }
 
+func (m *mockSessionStore) Find(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	sessions, err := m.List(ctx)
+	return f.Apply(sessions), err
+}
+
 func (m *mockSessionStore) FindRecyclable(_ context.Context, _ string) (session.Session, error) {
 	return session.Session{}, session.ErrNoRecyclable
 }
@@ -141,3 +152,72 @@ func TestIsSubpath(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionDetector_Symlinks(t *testing.T) {
+	root := t.TempDir()
+	real := filepath.Join(root, "repos", "hive-work-abc")
+	if err := os.MkdirAll(filepath.Join(real, "src", "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "work")
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+	linkedSession := filepath.Join(root, "linked")
+	if err := os.Symlink(filepath.Join(root, "repos"), linkedSession); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, tt := range []struct {
+		name     string
+		sessPath string
+		path     string
+	}{
+		{name: "cwd through symlink", sessPath: real, path: filepath.Join(link, "src", "pkg")},
+		{name: "session path through symlink", sessPath: filepath.Join(linkedSession, "hive-work-abc"), path: filepath.Join(real, "src")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockSessionStore{sessions: []session.Session{{ID: "abc", Path: tt.sessPath, State: session.StateActive}}}
+			got, err := NewSessionDetector(store).DetectSessionFromPath(ctx, tt.path)
+			if err != nil {
+				t.Fatalf("DetectSessionFromPath failed: %v", err)
+			}
+			if got != "abc" {
+				t.Errorf("DetectSessionFromPath(%q) = %q, want %q", tt.path, got, "abc")
+			}
+		})
+	}
+}
+
+func TestSessionDetector_Cache(t *testing.T) {
+	store := &mockSessionStore{sessions: []session.Session{
+		{ID: "sess-1", Path: "/home/user/projects/foo", State: session.StateActive},
+	}}
+	detector := NewSessionDetector(store)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Go(func() {
+			if got, _ := detector.DetectSessionFromPath(ctx, "/home/user/projects/foo/src"); got != "sess-1" {
+				t.Errorf("got %q, want sess-1", got)
+			}
+			_, _ = detector.DetectSessionFromPath(ctx, "/elsewhere")
+		})
+	}
+	wg.Wait()
+
+	if n := store.lists.Load(); n != 1 {
+		t.Errorf("store listed %d times, want 1", n)
+	}
+
+	store.sessions = append(store.sessions, session.Session{ID: "sess-2", Path: "/elsewhere", State: session.StateActive})
+	if got, _ := detector.DetectSessionFromPath(ctx, "/elsewhere"); got != "" {
+		t.Errorf("cached lookup = %q, want empty until Reset", got)
+	}
+	detector.Reset()
+	if got, _ := detector.DetectSessionFromPath(ctx, "/elsewhere"); got != "sess-2" {
+		t.Errorf("after Reset = %q, want sess-2", got)
+	}
+}
@@ -0,0 +1,57 @@
+package messaging
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// SenderCount is the number of messages attributed to one sender.
+type SenderCount struct {
+	Sender   string    `json:"sender"`
+	Messages int       `json:"messages"`
+	Topics   int       `json:"topics"`
+	Last     time.Time `json:"last"` // CreatedAt of the sender's newest message
+}
+
+// TopSenders counts messages by SenderID and returns the n busiest senders,
+// most messages first. Messages without a sender identity are counted under
+// "unknown". A non-positive n returns every sender.
+func TopSenders(messages []Message, n int) []SenderCount {
+	counts := make(map[string]*SenderCount)
+	topics := make(map[string]map[string]struct{})
+	for _, msg := range messages {
+		sender := msg.SenderID()
+		if sender == "" {
+			sender = "unknown"
+		}
+		sc, ok := counts[sender]
+		if !ok {
+			sc = &SenderCount{Sender: sender}
+			counts[sender] = sc
+			topics[sender] = make(map[string]struct{})
+		}
+		sc.Messages++
+		if msg.CreatedAt.After(sc.Last) {
+			sc.Last = msg.CreatedAt
+		}
+		topics[sender][msg.Topic] = struct{}{}
+	}
+
+	result := make([]SenderCount, 0, len(counts))
+	for sender, sc := range counts {
+		sc.Topics = len(topics[sender])
+		result = append(result, *sc)
+	}
+	slices.SortFunc(result, func(a, b SenderCount) int {
+		if c := cmp.Compare(b.Messages, a.Messages); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Sender, b.Sender)
+	})
+
+	if n > 0 && len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopSenders(t *testing.T) {
+	now := time.Now()
+	messages := []Message{
+		{Topic: "a", Sender: "loop", CreatedAt: now.Add(-3 * time.Second)},
+		{Topic: "b", Sender: "loop", CreatedAt: now.Add(-2 * time.Second)},
+		{Topic: "a", Sender: "loop", CreatedAt: now.Add(-time.Second)},
+		{Topic: "a", SessionID: "sess1", CreatedAt: now},
+		{Topic: "a", User: "me", Host: "laptop", CreatedAt: now},
+		{Topic: "a", CreatedAt: now},
+	}
+
+	got := TopSenders(messages, 0)
+	if len(got) != 4 {
+		t.Fatalf("TopSenders() returned %d senders, want 4", len(got))
+	}
+	if got[0].Sender != "loop" || got[0].Messages != 3 || got[0].Topics != 2 {
+		t.Errorf("top sender = %+v, want loop with 3 messages on 2 topics", got[0])
+	}
+	if !got[0].Last.Equal(now.Add(-time.Second)) {
+		t.Errorf("top sender Last = %v, want newest message time", got[0].Last)
+	}
+
+	// Ties are ordered by name
+	wantRest := []string{"me@laptop", "sess1", "unknown"}
+	for i, want := range wantRest {
+		if got[i+1].Sender != want {
+			t.Errorf("sender %d = %q, want %q", i+1, got[i+1].Sender, want)
+		}
+	}
+
+	if got := TopSenders(messages, 2); len(got) != 2 {
+		t.Errorf("TopSenders(n=2) returned %d senders, want 2", len(got))
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"time"
 )
 
-var ErrTopicNotFound = errors.New("topic not found")
+var (
+	ErrTopicNotFound = errors.New("topic not found")
+	ErrRateLimited   = errors.New("rate limit exceeded")
+)
 
 // Store defines the interface for message persistence.
 type Store interface {
@@ -14,6 +17,7 @@ type Store interface {
 	Publish(ctx context.Context, msg Message) error
 
 	// Subscribe returns all messages for a topic, optionally filtered by since timestamp.
+	// Expired messages are not returned.
 	// Returns ErrTopicNotFound if the topic doesn't exist.
 	Subscribe(ctx context.Context, topic string, since time.Time) ([]Message, error)
 
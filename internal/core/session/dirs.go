@@ -0,0 +1,56 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// UnknownDirs returns the directories under root that belong to none of the
+// given sessions. Session directories may be nested (see
+// paths.session_dir_template), so directories that contain a session
+// directory are searched rather than reported. A missing root has no
+// unknown directories.
+func UnknownDirs(root string, sessions []Session) ([]string, error) {
+	root = filepath.Clean(root)
+	known := make(map[string]bool, len(sessions))
+	parents := make(map[string]bool)
+	for _, s := range sessions {
+		p := filepath.Clean(s.Path)
+		known[p] = true
+		for dir := filepath.Dir(p); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+			parents[dir] = true
+		}
+	}
+
+	var unknown []string
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case !entry.IsDir() || known[path]:
+			case parents[path]:
+				if err := walk(path); err != nil {
+					return err
+				}
+			default:
+				unknown = append(unknown, path)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read repos directory: %w", err)
+	}
+	return unknown, nil
+}
@@ -0,0 +1,33 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownDirs(t *testing.T) {
+	root := t.TempDir()
+	mkdir := func(parts ...string) string {
+		dir := filepath.Join(append([]string{root}, parts...)...)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		return dir
+	}
+
+	flat := mkdir("hive-work-abc")
+	nested := mkdir("hay-kot", "hive", "fix-def")
+	strayNested := mkdir("hay-kot", "hive", "crash-ghi")
+	strayOwner := mkdir("someone")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644))
+
+	unknown, err := UnknownDirs(root, []Session{{Path: flat}, {Path: nested}})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{strayNested, strayOwner}, unknown)
+
+	unknown, err = UnknownDirs(filepath.Join(root, "missing"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+}
@@ -2,6 +2,7 @@
 package session
 
 import (
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -33,6 +34,63 @@ const (
 	MetaTmuxPane    = "tmux_pane"    // tmux pane identifier
 )
 
+// MetaBatchID records the ID of the hive batch that created the session.
+const MetaBatchID = "batch_id"
+
+// Metadata keys recording how a session was spawned, so its terminal can be
+// spawned again with the same prompt and commands.
+const (
+	MetaPrompt       = "prompt"        // prompt passed to the spawn commands
+	MetaSpawnProfile = "spawn_profile" // spawn profile chosen at creation
+)
+
+// MetaSubdir records the working subdirectory of a session in a monorepo,
+// relative to the repository root.
+const MetaSubdir = "subdir"
+
+// MetaReviewOf records, on a read-only review session, the ID of the session
+// whose directory it shares. Review sessions never own their directory.
+const MetaReviewOf = "review_of"
+
+// MetaToolSession records the AI tool's own session or conversation ID, such
+// as a Claude Code session, so the tool can be resumed after its terminal is
+// lost.
+const MetaToolSession = "tool_session"
+
+// MetaPinned is set to "true" on sessions pinned to the top of their
+// repository in the TUI.
+const MetaPinned = "pinned"
+
+// MetaIssueURL records the URL of the issue a session was created for with
+// 'hive new --issue'.
+const MetaIssueURL = "issue_url"
+
+// MetaNote holds a free-form note about the session, shown in the TUI.
+const MetaNote = "note"
+
+// Creation steps recorded in Session.Timings.
+const (
+	StepWait         = "wait"          // waiting for a free slot under max_active
+	StepClone        = "clone"         // git clone of a new session
+	StepPull         = "pull"          // git pull of a reused recycled session
+	StepCopy         = "copy"          // a rule's file copies
+	StepRuleCommands = "rule_commands" // a rule's commands
+	StepPostCreate   = "post_create"   // a rule's post_create hooks
+	StepSpawn        = "spawn"         // the spawn commands
+)
+
+// Timing is how long one step of creating a session took.
+type Timing struct {
+	Step       string `json:"step"`
+	Rule       string `json:"rule,omitempty"` // pattern of the rule the step ran for
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Duration returns the step's duration.
+func (t Timing) Duration() time.Duration {
+	return time.Duration(t.DurationMS) * time.Millisecond
+}
+
 // Session represents an isolated git environment for an AI agent.
 type Session struct {
 	ID            string            `json:"id"`
@@ -45,6 +103,12 @@ type Session struct {
 	CreatedAt     time.Time         `json:"created_at"`
 	UpdatedAt     time.Time         `json:"updated_at"`
 	LastInboxRead *time.Time        `json:"last_inbox_read,omitempty"`
+	Timings       []Timing          `json:"timings,omitempty"` // steps of the session's creation
+
+	// Version is incremented by the store on every save. A session read from
+	// the store carries its version, so saving a stale copy fails with
+	// ErrConflict; zero skips the check.
+	Version int64 `json:"version,omitempty"`
 }
 
 // InboxTopic returns the conventional inbox topic name for this session.
@@ -53,6 +117,56 @@ func (s *Session) InboxTopic() string {
 	return "agent." + s.ID + ".inbox"
 }
 
+// HeartbeatTopic returns the topic hive serve publishes this session's
+// heartbeats on. Format: session.<session-id>.heartbeat
+func (s *Session) HeartbeatTopic() string {
+	return "session." + s.ID + ".heartbeat"
+}
+
+// CITopic returns the default topic CI reports this session's status on for
+// 'hive ci'. Format: agent.<session-id>.ci
+func (s *Session) CITopic() string {
+	return "agent." + s.ID + ".ci"
+}
+
+// WorkDir returns the directory spawned terminals and commands start in: the
+// session's subdirectory when one was set, otherwise its root.
+func (s *Session) WorkDir() string {
+	if sub := s.GetMeta(MetaSubdir); sub != "" {
+		return filepath.Join(s.Path, sub)
+	}
+	return s.Path
+}
+
+// IsReview reports whether the session is a read-only review of another
+// session's directory.
+func (s *Session) IsReview() bool {
+	return s.GetMeta(MetaReviewOf) != ""
+}
+
+// IsPinned reports whether the session is pinned to the top of its
+// repository in the TUI.
+func (s *Session) IsPinned() bool {
+	return s.GetMeta(MetaPinned) == "true"
+}
+
+// Note returns the session's note, or empty string if it has none.
+func (s *Session) Note() string {
+	return s.GetMeta(MetaNote)
+}
+
+// BatchID returns the ID of the hive batch that created the session, or
+// empty string if it was not created by a batch.
+func (s *Session) BatchID() string {
+	return s.GetMeta(MetaBatchID)
+}
+
+// IssueURL returns the URL of the issue the session was created for, or
+// empty string if it was not created from an issue.
+func (s *Session) IssueURL() string {
+	return s.GetMeta(MetaIssueURL)
+}
+
 // UpdateLastInboxRead updates the last inbox read timestamp.
 func (s *Session) UpdateLastInboxRead(t time.Time) {
 	s.LastInboxRead = &t
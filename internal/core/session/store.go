@@ -3,21 +3,27 @@ package session
 import (
 	"context"
 	"errors"
+	"slices"
 )
 
 // Sentinel errors for session operations.
 var (
 	ErrNotFound     = errors.New("session not found")
 	ErrNoRecyclable = errors.New("no recyclable session found")
+	ErrConflict     = errors.New("session was modified concurrently")
 )
 
 // Store defines persistence operations for sessions.
 type Store interface {
 	// List returns all sessions.
 	List(ctx context.Context) ([]Session, error)
+	// Find returns the sessions matching f, in the order they were first
+	// saved, without loading the others where the backend allows it.
+	Find(ctx context.Context, f Filter) ([]Session, error)
 	// Get returns a session by ID. Returns ErrNotFound if not found.
 	Get(ctx context.Context, id string) (Session, error)
-	// Save creates or updates a session.
+	// Save creates or updates a session. If s.Version is non-zero it must
+	// match the stored version, otherwise Save returns ErrConflict.
 	Save(ctx context.Context, s Session) error
 	// Delete removes a session by ID. Returns ErrNotFound if not found.
 	Delete(ctx context.Context, id string) error
@@ -25,3 +31,40 @@ type Store interface {
 	// Returns ErrNoRecyclable if none available.
 	FindRecyclable(ctx context.Context, remote string) (Session, error)
 }
+
+// Filter selects sessions for Store.Find. Zero fields match everything.
+type Filter struct {
+	Remote string  // Only sessions for this remote
+	States []State // Only sessions in one of these states
+	Limit  int     // Return at most this many sessions; 0 for no limit
+	Offset int     // Skip this many matching sessions first
+}
+
+// Match reports whether s satisfies the Remote and States conditions.
+func (f Filter) Match(s Session) bool {
+	if f.Remote != "" && s.Remote != f.Remote {
+		return false
+	}
+	return len(f.States) == 0 || slices.Contains(f.States, s.State)
+}
+
+// Apply returns the sessions matching f in their input order, paginated by
+// Offset and Limit. Stores without native filtering implement Find with it.
+func (f Filter) Apply(sessions []Session) []Session {
+	var matched []Session
+	skip := f.Offset
+	for _, s := range sessions {
+		if !f.Match(s) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		matched = append(matched, s)
+		if f.Limit > 0 && len(matched) == f.Limit {
+			break
+		}
+	}
+	return matched
+}
@@ -0,0 +1,169 @@
+// Package templates resolves field values for configured prompt templates
+// and renders their prompts.
+package templates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// Lookup returns the named template from templates.
+func Lookup(templates map[string]config.Template, name string) (config.Template, error) {
+	t, ok := templates[name]
+	if !ok {
+		return config.Template{}, fmt.Errorf("unknown template %q (available: %s)", name, available(templates))
+	}
+	return t, nil
+}
+
+// Resolve checks values against the template's fields and returns the full
+// set of typed field values with defaults applied. Values for undeclared
+// fields, missing required fields, and values that do not parse as the
+// field's type are errors. Empty optional fields resolve to the type's zero
+// value so {{ if .flag }} behaves as expected.
+func Resolve(t config.Template, values map[string]string) (map[string]any, error) {
+	names := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		names[i] = f.Name
+	}
+
+	for key := range values {
+		if !slices.Contains(names, key) {
+			return nil, fmt.Errorf("unknown field %q (fields: %s)", key, strings.Join(names, ", "))
+		}
+	}
+
+	resolved := make(map[string]any, len(t.Fields))
+	for _, f := range t.Fields {
+		v, ok := values[f.Name]
+		if !ok || v == "" {
+			v = f.Default
+		}
+		if v == "" {
+			if f.Required {
+				return nil, fmt.Errorf("missing required field %q", f.Name)
+			}
+			resolved[f.Name] = f.Zero()
+			continue
+		}
+
+		parsed, err := f.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		resolved[f.Name] = parsed
+	}
+
+	return resolved, nil
+}
+
+// Render resolves values and renders the template's prompt with them.
+func Render(t config.Template, values map[string]string) (string, error) {
+	resolved, err := Resolve(t, values)
+	if err != nil {
+		return "", err
+	}
+
+	prompt, err := tmpl.Render(t.Prompt, resolved)
+	if err != nil {
+		return "", fmt.Errorf("render prompt: %w", err)
+	}
+	return prompt, nil
+}
+
+func available(templates map[string]config.Template) string {
+	if len(templates) == 0 {
+		return "none configured"
+	}
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// LoadOptions returns a copy of t with the options of every options_cmd
+// field populated from the command's output, so select values can be
+// checked against them. The command runs with sh -c. Output is either a
+// JSON array (of scalars, or of single-key objects such as the output of
+// `gh pr list --json number`) or one option per line.
+func LoadOptions(ctx context.Context, exec executil.Executor, t config.Template) (config.Template, error) {
+	fields := slices.Clone(t.Fields)
+	for i, f := range fields {
+		if f.OptionsCmd == "" {
+			continue
+		}
+
+		var stdout, stderr bytes.Buffer
+		if err := exec.RunStream(ctx, &stdout, &stderr, "sh", "-c", f.OptionsCmd); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return config.Template{}, fmt.Errorf("field %q options: %w: %s", f.Name, err, msg)
+			}
+			return config.Template{}, fmt.Errorf("field %q options: %w", f.Name, err)
+		}
+
+		opts, err := parseOptions(stdout.Bytes())
+		if err != nil {
+			return config.Template{}, fmt.Errorf("field %q options: %w", f.Name, err)
+		}
+		fields[i].Options = opts
+	}
+
+	t.Fields = fields
+	return t, nil
+}
+
+// parseOptions parses options_cmd output into option values.
+func parseOptions(out []byte) ([]string, error) {
+	trimmed := bytes.TrimSpace(out)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+
+	if trimmed[0] != '[' {
+		var opts []string
+		for _, line := range strings.Split(string(trimmed), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				opts = append(opts, line)
+			}
+		}
+		return opts, nil
+	}
+
+	var items []any
+	if err := json.Unmarshal(trimmed, &items); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	opts := make([]string, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(map[string]any); ok {
+			if len(obj) != 1 {
+				return nil, fmt.Errorf("JSON objects must have exactly one key, got %d", len(obj))
+			}
+			for _, v := range obj {
+				item = v
+			}
+		}
+
+		switch v := item.(type) {
+		case string:
+			opts = append(opts, v)
+		case float64, bool:
+			opts = append(opts, fmt.Sprint(v))
+		default:
+			return nil, fmt.Errorf("unsupported option value %v", v)
+		}
+	}
+	return opts, nil
+}
@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var prReview = config.Template{
+	Prompt: "Review PR #{{ .pr_number }}{{ if .focus }} focusing on {{ .focus }}{{ end }}",
+	Fields: []config.TemplateField{
+		{Name: "pr_number", Required: true},
+		{Name: "focus", Default: "tests"},
+	},
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "defaults applied",
+			values: map[string]string{"pr_number": "123"},
+			want:   "Review PR #123 focusing on tests",
+		},
+		{
+			name:   "override default",
+			values: map[string]string{"pr_number": "7", "focus": "security"},
+			want:   "Review PR #7 focusing on security",
+		},
+		{
+			name:    "missing required",
+			values:  map[string]string{},
+			wantErr: `missing required field "pr_number"`,
+		},
+		{
+			name:    "unknown field",
+			values:  map[string]string{"pr_number": "1", "nope": "x"},
+			wantErr: `unknown field "nope"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(prReview, tt.values)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	templates := map[string]config.Template{"pr-review": prReview}
+
+	got, err := Lookup(templates, "pr-review")
+	require.NoError(t, err)
+	assert.Equal(t, prReview.Prompt, got.Prompt)
+
+	_, err = Lookup(templates, "missing")
+	require.ErrorContains(t, err, "available: pr-review")
+
+	_, err = Lookup(nil, "missing")
+	require.ErrorContains(t, err, "none configured")
+}
+
+func TestResolve_Types(t *testing.T) {
+	tmpl := config.Template{
+		Prompt: "x",
+		Fields: []config.TemplateField{
+			{Name: "count", Type: config.FieldNumber, Default: "3"},
+			{Name: "ratio", Type: config.FieldNumber},
+			{Name: "draft", Type: config.FieldBool},
+			{Name: "env", Type: config.FieldSelect, Options: []string{"dev", "prod"}, Default: "dev"},
+		},
+	}
+
+	got, err := Resolve(tmpl, map[string]string{"ratio": "0.5", "draft": "true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"count": int64(3), "ratio": 0.5, "draft": true, "env": "dev"}, got)
+
+	got, err = Resolve(tmpl, nil)
+	require.NoError(t, err)
+	assert.Equal(t, false, got["draft"], "empty bool resolves to false")
+	assert.Equal(t, int64(0), got["ratio"])
+
+	_, err = Resolve(tmpl, map[string]string{"count": "many"})
+	require.ErrorContains(t, err, `field "count": "many" is not a number`)
+
+	_, err = Resolve(tmpl, map[string]string{"draft": "maybe"})
+	require.ErrorContains(t, err, "not a boolean")
+
+	_, err = Resolve(tmpl, map[string]string{"env": "staging"})
+	require.ErrorContains(t, err, "not one of: dev, prod")
+}
+
+func TestRender_BoolCondition(t *testing.T) {
+	tmpl := config.Template{
+		Prompt: "{{ if .draft }}draft{{ else }}ready{{ end }}",
+		Fields: []config.TemplateField{{Name: "draft", Type: config.FieldBool}},
+	}
+
+	got, err := Render(tmpl, map[string]string{"draft": "false"})
+	require.NoError(t, err)
+	assert.Equal(t, "ready", got)
+}
+
+func TestLoadOptions(t *testing.T) {
+	tmpl := config.Template{
+		Prompt: "x",
+		Fields: []config.TemplateField{
+			{Name: "pr", Type: config.FieldSelect, OptionsCmd: "gh pr list --json number"},
+			{Name: "other"},
+		},
+	}
+	exec := &executil.RecordingExecutor{
+		Outputs: map[string][]byte{"sh": []byte(`[{"number":12},{"number":34}]`)},
+	}
+
+	loaded, err := LoadOptions(context.Background(), exec, tmpl)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12", "34"}, loaded.Fields[0].Options)
+	assert.Empty(t, tmpl.Fields[0].Options, "original template is not modified")
+
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"-c", "gh pr list --json number"}, exec.Commands[0].Args)
+
+	_, err = Resolve(loaded, map[string]string{"pr": "99"})
+	require.ErrorContains(t, err, "not one of: 12, 34")
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    []string
+		wantErr string
+	}{
+		{name: "lines", out: "main\n\n  dev  \n", want: []string{"main", "dev"}},
+		{name: "empty", out: "  \n", want: nil},
+		{name: "json scalars", out: `["a", 1, true]`, want: []string{"a", "1", "true"}},
+		{name: "json objects", out: `[{"name":"a"},{"name":"b"}]`, want: []string{"a", "b"}},
+		{name: "multi-key object", out: `[{"a":1,"b":2}]`, wantErr: "exactly one key"},
+		{name: "bad json", out: `[1,`, wantErr: "decode JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOptions([]byte(tt.out))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,14 +3,86 @@ package validate
 
 import (
 	"fmt"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
 )
 
-// SessionName validates a session name is non-empty after trimming whitespace.
+// Limits on the session directories hive creates. maxNameLength is the
+// longest file name most filesystems accept, in bytes.
+const maxNameLength = 255
+
+// maxPathLength returns the longest path goos accepts, in bytes.
+func maxPathLength(goos string) int {
+	switch goos {
+	case "windows":
+		return 260
+	case "darwin":
+		return 1024
+	default:
+		return 4096
+	}
+}
+
+// reservedNameChars returns the characters goos does not allow in file names,
+// beyond control characters.
+func reservedNameChars(goos string) string {
+	if goos == "windows" {
+		return `<>:"/\|?*`
+	}
+	return ""
+}
+
+// NormalizeSessionName returns name trimmed of surrounding whitespace and in
+// Unicode NFC form, so names typed with combining characters and precomposed
+// ones compare equal.
+func NormalizeSessionName(name string) string {
+	return norm.NFC.String(strings.TrimSpace(name))
+}
+
+// SessionName validates a session name:
+// - Non-empty after trimming whitespace
+// - No control characters
+// - No characters the OS reserves in file names (e.g., ? or * on Windows)
 func SessionName(name string) error {
+	return sessionName(name, runtime.GOOS)
+}
+
+func sessionName(name, goos string) error {
 	if strings.TrimSpace(name) == "" {
 		return fmt.Errorf("name is required")
 	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("name must not contain control characters, got %q", name)
+		}
+		if strings.ContainsRune(reservedNameChars(goos), r) {
+			return fmt.Errorf("name must not contain %q on %s, got %q", r, goos, name)
+		}
+	}
+	return nil
+}
+
+// SessionPath validates that the directory path a session is created in is
+// within the OS limits on path length and on the length of each path
+// component. A long session name is the usual cause, as the directory name
+// holds the repository name, the name, and the ID.
+func SessionPath(path string) error {
+	return sessionPath(path, runtime.GOOS)
+}
+
+func sessionPath(path, goos string) error {
+	if limit := maxPathLength(goos); len(path) > limit {
+		return fmt.Errorf("session directory %s is %d bytes, over the %d byte limit on %s; use a shorter name", path, len(path), limit, goos)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if len(part) > maxNameLength {
+			return fmt.Errorf("session directory name %s is %d bytes, over the %d byte limit; use a shorter name", part, len(part), maxNameLength)
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package validate
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -15,6 +16,9 @@ func TestSessionName(t *testing.T) {
 		{"empty string", "", true},
 		{"only spaces", "   ", true},
 		{"only tabs", "\t\t", true},
+		{"unicode", "café", false},
+		{"control character", "fix\x1b[2Jbug", true},
+		{"newline", "fix\nbug", true},
 	}
 
 	for _, tt := range tests {
@@ -27,6 +31,46 @@ func TestSessionName(t *testing.T) {
 	}
 }
 
+func TestSessionName_Windows(t *testing.T) {
+	if err := sessionName("fix: auth?", "windows"); err == nil {
+		t.Error("expected reserved characters to be rejected on windows")
+	}
+	if err := sessionName("fix: auth?", "linux"); err != nil {
+		t.Errorf("unexpected error on linux: %v", err)
+	}
+}
+
+func TestNormalizeSessionName(t *testing.T) {
+	// "e" followed by a combining acute accent
+	if got := NormalizeSessionName("  cafe\u0301 "); got != "caf\u00e9" {
+		t.Errorf("NormalizeSessionName = %q, want %q", got, "caf\u00e9")
+	}
+}
+
+func TestSessionPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		goos    string
+		wantErr bool
+	}{
+		{"short", "/home/u/.local/share/hive/repos/hive-fix-auth-abc123", "linux", false},
+		{"long component", "/repos/hive-" + strings.Repeat("a", 250) + "-abc123", "linux", true},
+		{"long nested component", "/repos/" + strings.Repeat("a", 256) + "/hive-abc123", "linux", true},
+		{"long path on windows", `C:\repos\` + strings.Repeat(`a\`, 130), "windows", true},
+		{"same path on linux", "/repos/" + strings.Repeat("a/", 130), "linux", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sessionPath(tt.path, tt.goos)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("sessionPath(%q, %q) error = %v, wantErr %v", tt.path, tt.goos, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestSessionID(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,242 @@
+// Package events records hive activity as an append-only NDJSON log in the
+// data directory, one JSON object per line, for integrations and audits.
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	SessionCreated   = "session.created"
+	SessionRecycled  = "session.recycled"
+	SessionDeleted   = "session.deleted"
+	SessionCorrupted = "session.corrupted"
+	SessionRestored  = "session.restored" // deleted session moved back out of the trash
+	SessionRenamed   = "session.renamed"  // display name changed, data.from -> data.to
+	SessionStatus    = "session.status"   // terminal status changed, e.g. active -> ready
+	MessagePublished = "message.published"
+	PruneCompleted   = "prune.completed"
+	GCCompleted      = "gc.completed"
+	BatchCompleted   = "batch.completed"
+)
+
+// defaultMaxBytes is the size at which the log is rotated to <path>.1.
+const defaultMaxBytes = 10 << 20
+
+// pollInterval is how often Follow checks the log for new lines.
+const pollInterval = 500 * time.Millisecond
+
+// Event is one line of the log.
+type Event struct {
+	Time      time.Time      `json:"time"`
+	Type      string         `json:"type"`
+	SessionID string         `json:"session_id,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+}
+
+// Filter selects events. Zero values match everything.
+type Filter struct {
+	Since     time.Time
+	Types     []string // glob patterns, e.g. "session.*"
+	SessionID string
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Event) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.SessionID != "" && e.SessionID != f.SessionID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, pattern := range f.Types {
+		if ok, _ := path.Match(pattern, e.Type); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Log appends events to, and reads them from, an NDJSON file.
+type Log struct {
+	path     string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// New returns a log at path. The file is created on the first Append.
+func New(path string) *Log {
+	return &Log{path: path, maxBytes: defaultMaxBytes}
+}
+
+// Append writes e as a single line, stamping the time if unset. Once the log
+// exceeds its size limit it is moved to <path>.1, replacing the previous one.
+func (l *Log) Append(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o755); err != nil {
+		return fmt.Errorf("create events directory: %w", err)
+	}
+
+	if info, err := os.Stat(l.path); err == nil && info.Size() >= l.maxBytes {
+		if err := os.Rename(l.path, l.path+".1"); err != nil {
+			return fmt.Errorf("rotate events log: %w", err)
+		}
+	}
+
+	// O_APPEND keeps single-write lines from interleaving across processes
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open events log: %w", err)
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write event: %w", err)
+	}
+	return f.Close()
+}
+
+// Read returns the events matching f, oldest first, including those in the
+// rotated log.
+func (l *Log) Read(f Filter) ([]Event, error) {
+	var events []Event
+	for _, p := range []string{l.path + ".1", l.path} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read events log: %w", err)
+		}
+		for _, e := range parse(data) {
+			if f.Match(e) {
+				events = append(events, e)
+			}
+		}
+	}
+	return events, nil
+}
+
+// Follow calls fn for each event matching f already in the log, then for
+// each new one as it is appended, until ctx is done or fn returns an error.
+func (l *Log) Follow(ctx context.Context, f Filter, fn func(Event) error) error {
+	existing, err := l.Read(f)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	offset, err := l.size()
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		size, err := l.size()
+		if err != nil {
+			return err
+		}
+		if size < offset {
+			offset = 0 // rotated
+		}
+		if size == offset {
+			continue
+		}
+
+		chunk, err := l.readFrom(offset, size)
+		if err != nil {
+			return err
+		}
+		// Leave a partially written last line for the next poll
+		end := bytes.LastIndexByte(chunk, '\n') + 1
+		offset += int64(end)
+
+		for _, e := range parse(chunk[:end]) {
+			if !f.Match(e) {
+				continue
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (l *Log) size() (int64, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("stat events log: %w", err)
+	}
+	return info.Size(), nil
+}
+
+func (l *Log) readFrom(offset, size int64) ([]byte, error) {
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("open events log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	buf := make([]byte, size-offset)
+	n, err := file.ReadAt(buf, offset)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("read events log: %w", err)
+	}
+	return buf[:n], nil
+}
+
+// parse decodes NDJSON, skipping lines that are not valid events.
+func parse(data []byte) []Event {
+	var events []Event
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events
+}
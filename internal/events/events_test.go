@@ -0,0 +1,113 @@
+package events
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendAndRead(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), "events.ndjson"))
+
+	require.NoError(t, log.Append(Event{Type: SessionCreated, SessionID: "a", Data: map[string]any{"name": "one"}}))
+	require.NoError(t, log.Append(Event{Type: SessionDeleted, SessionID: "a"}))
+	require.NoError(t, log.Append(Event{Type: PruneCompleted, Data: map[string]any{"pruned": 2}}))
+
+	all, err := log.Read(Filter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, SessionCreated, all[0].Type)
+	assert.Equal(t, "one", all[0].Data["name"])
+	assert.False(t, all[0].Time.IsZero())
+
+	sessions, err := log.Read(Filter{Types: []string{"session.*"}})
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	none, err := log.Read(Filter{SessionID: "b"})
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestReadMissingLog(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), "events.ndjson"))
+
+	got, err := log.Read(Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestReadSkipsInvalidLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	require.NoError(t, os.WriteFile(path, []byte("{\"type\":\"a\"}\nnot json\n{\"type\":\"b\"}\n"), 0o644))
+
+	got, err := New(path).Read(Filter{})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "b", got[1].Type)
+}
+
+func TestFilterSince(t *testing.T) {
+	now := time.Now()
+	f := Filter{Since: now.Add(-time.Hour)}
+
+	assert.True(t, f.Match(Event{Time: now}))
+	assert.False(t, f.Match(Event{Time: now.Add(-2 * time.Hour)}))
+}
+
+func TestAppendRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	log := New(path)
+	log.maxBytes = 1
+
+	require.NoError(t, log.Append(Event{Type: "first"}))
+	require.NoError(t, log.Append(Event{Type: "second"}))
+	require.NoError(t, log.Append(Event{Type: "third"}))
+
+	_, err := os.Stat(path + ".1")
+	require.NoError(t, err)
+
+	// Only one rotated file is kept
+	got, err := log.Read(Filter{})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "second", got[0].Type)
+	assert.Equal(t, "third", got[1].Type)
+}
+
+func TestFollow(t *testing.T) {
+	log := New(filepath.Join(t.TempDir(), "events.ndjson"))
+	require.NoError(t, log.Append(Event{Type: "existing"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	got := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- log.Follow(ctx, Filter{Types: []string{"existing", "new"}}, func(e Event) error {
+			got <- e.Type
+			return nil
+		})
+	}()
+
+	assert.Equal(t, "existing", <-got)
+
+	require.NoError(t, log.Append(Event{Type: "skipped"}))
+	require.NoError(t, log.Append(Event{Type: "new"}))
+
+	select {
+	case typ := <-got:
+		assert.Equal(t, "new", typ)
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for followed event")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
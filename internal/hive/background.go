@@ -0,0 +1,76 @@
+package hive
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// BackgroundJob is a group of rendered commands from a background rule.
+type BackgroundJob struct {
+	Label    string // hook label, e.g. "hook" or "post_create"
+	Commands []string
+	Policy   config.HookPolicy
+}
+
+// backgroundScript builds a shell script that runs jobs in order. Each
+// command is retried per its policy; once retries are exhausted, abort stops
+// the script and continue/warn move on to the next command.
+func backgroundScript(jobs []BackgroundJob) string {
+	var b strings.Builder
+	b.WriteString("echo \"--- background hooks started $(date) ---\"\n")
+
+	for _, job := range jobs {
+		for _, cmd := range job.Commands {
+			fmt.Fprintf(&b, "printf '%%s\\n' %s\n", tmpl.ShellQuote(fmt.Sprintf("==> [%s] %s", job.Label, cmd)))
+			b.WriteString("n=0\n")
+			fmt.Fprintf(&b, "until sh -c %s; do\n", tmpl.ShellQuote(cmd))
+			b.WriteString("  n=$((n+1))\n")
+			fmt.Fprintf(&b, "  if [ \"$n\" -gt %d ]; then\n", job.Policy.Retries)
+			fmt.Fprintf(&b, "    printf '%%s\\n' %s\n", tmpl.ShellQuote("hook failed: "+cmd))
+			if job.Policy.OnFailure == "" || job.Policy.OnFailure == config.OnFailureAbort {
+				b.WriteString("    exit 1\n")
+			} else {
+				b.WriteString("    break\n")
+			}
+			b.WriteString("  fi\n")
+			b.WriteString("  sleep 1\n")
+			b.WriteString("done\n")
+		}
+	}
+
+	b.WriteString("echo \"--- background hooks finished $(date) ---\"\n")
+	return b.String()
+}
+
+// startDetached starts script in a new session so it outlives hive,
+// appending its output to logPath. It does not wait for the script.
+func startDetached(dir string, env []string, script, logPath string) error {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o755); err != nil {
+		return fmt.Errorf("create log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	c := exec.Command("sh", "-c", script)
+	c.Dir = dir
+	c.Env = append(os.Environ(), env...)
+	c.Stdout = f
+	c.Stderr = f
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("start background hooks: %w", err)
+	}
+	return c.Process.Release()
+}
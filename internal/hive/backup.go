@@ -0,0 +1,36 @@
+package hive
+
+import (
+	"context"
+
+	"github.com/hay-kot/hive/internal/store/backup"
+)
+
+// Reasons recorded for automatic backups.
+const (
+	BackupPrune   = "prune"
+	BackupBatch   = "batch"
+	BackupMigrate = "migrate"
+)
+
+// Backups returns the manager for store backups.
+func (s *Service) Backups() *backup.Manager {
+	return s.deps().backups
+}
+
+// AutoBackup snapshots the stores before a destructive operation, unless
+// backups.keep is 0. Failures are logged rather than returned so a broken
+// backup never blocks the operation itself.
+func (s *Service) AutoBackup(ctx context.Context, reason string) {
+	if s.cfg().Backups.KeepLimit() == 0 {
+		return
+	}
+
+	b, err := s.deps().backups.Create(ctx, reason)
+	if err != nil {
+		s.log.Warn().Err(err).Str("reason", reason).Msg("failed to back up stores")
+		return
+	}
+
+	s.log.Debug().Str("backup", b.ID).Str("reason", reason).Msg("backed up stores")
+}
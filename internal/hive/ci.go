@@ -0,0 +1,182 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// ErrNoCITrigger is returned when no rule configures a ci trigger for the
+// session's repository.
+var ErrNoCITrigger = errors.New("no ci trigger configured")
+
+// CI status states. Any other status reported on the topic is treated as
+// still pending.
+const (
+	CIStateSuccess = "success"
+	CIStateFailure = "failure"
+)
+
+// CIData is the template context for the ci trigger and topic of a rule.
+// It mirrors config.CITemplateData.
+type CIData struct {
+	HookData
+	Branch string // Branch checked out in the session
+	Topic  string // Topic CI reports its status on (trigger only)
+}
+
+// CIRun is a CI run started by TriggerCI.
+type CIRun struct {
+	SessionID string        `json:"session_id"`
+	Topic     string        `json:"topic"`
+	StartedAt time.Time     `json:"started_at"`
+	Timeout   time.Duration `json:"-"`
+}
+
+// CIStatus is a status reported for a CI run.
+type CIStatus struct {
+	State   string            `json:"state"` // success, failure, or the reported pending status
+	Message messaging.Message `json:"message"`
+}
+
+// Done reports whether the status is final.
+func (s CIStatus) Done() bool {
+	return s.State == CIStateSuccess || s.State == CIStateFailure
+}
+
+// TriggerCI runs the ci trigger of the last matching rule in the working
+// directory of an active session, streaming its output to stdout and
+// stderr. The status topic is exported to the trigger as HIVE_CI_TOPIC,
+// along with the configured env and the session's HIVE_* variables.
+func (s *Service) TriggerCI(ctx context.Context, ref string, stdout, stderr io.Writer) (CIRun, error) {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return CIRun{}, fmt.Errorf("get session: %w", err)
+	}
+	if sess.State != session.StateActive {
+		return CIRun{}, fmt.Errorf("session %s is %s, not active", sess.ID, sess.State)
+	}
+	if _, err := os.Stat(sess.WorkDir()); err != nil {
+		return CIRun{}, fmt.Errorf("session directory: %w", err)
+	}
+
+	cfg := s.cfg().CIConfigFor(sess.Remote)
+	if cfg.Trigger == "" {
+		return CIRun{}, fmt.Errorf("%w for %s; set ci.trigger on a rule", ErrNoCITrigger, sess.Remote)
+	}
+
+	data := CIData{HookData: s.hookData(sess, sess.GetMeta(session.MetaPrompt))}
+	if data.Branch, err = s.git.Branch(ctx, sess.WorkDir()); err != nil {
+		return CIRun{}, fmt.Errorf("get branch: %w", err)
+	}
+
+	data.Topic = sess.CITopic()
+	if cfg.Topic != "" {
+		if data.Topic, err = tmpl.Render(cfg.Topic, data); err != nil {
+			return CIRun{}, fmt.Errorf("render ci topic: %w", err)
+		}
+	}
+
+	trigger, err := tmpl.Render(cfg.Trigger, data)
+	if err != nil {
+		return CIRun{}, fmt.Errorf("render ci trigger: %w", err)
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return CIRun{}, err
+	}
+	cmdCtx = executil.WithEnv(cmdCtx, append(data.Env(), "HIVE_CI_TOPIC="+data.Topic))
+
+	run := CIRun{SessionID: sess.ID, Topic: data.Topic, StartedAt: time.Now(), Timeout: cfg.Timeout}
+	s.log.Debug().Str("session_id", sess.ID).Str("topic", run.Topic).Str("trigger", trigger).Msg("triggering ci")
+	err = traced(cmdCtx, "hive.ci.trigger", func(ctx context.Context) error {
+		return s.executor.RunDirStream(ctx, sess.WorkDir(), stdout, stderr, "sh", "-c", trigger)
+	})
+	if err != nil {
+		return CIRun{}, fmt.Errorf("run ci trigger: %w", err)
+	}
+	return run, nil
+}
+
+// WaitCIStatus polls store for statuses published on the run's topic after
+// it started, calling onStatus for each, until one is final or the run's
+// timeout passes. onStatus may be nil.
+func (s *Service) WaitCIStatus(ctx context.Context, store messaging.Store, run CIRun, onStatus func(CIStatus)) (CIStatus, error) {
+	if run.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, run.Timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(s.ciPollInterval)
+	defer ticker.Stop()
+
+	since := run.StartedAt
+	for {
+		messages, err := store.Subscribe(ctx, run.Topic, since)
+		if err != nil && !errors.Is(err, messaging.ErrTopicNotFound) {
+			return CIStatus{}, fmt.Errorf("subscribe %s: %w", run.Topic, err)
+		}
+
+		for _, msg := range messages {
+			since = msg.CreatedAt
+			status := CIStatus{State: parseCIState(msg.Payload), Message: msg}
+			if onStatus != nil {
+				onStatus(status)
+			}
+			if status.Done() {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return CIStatus{}, fmt.Errorf("no ci status on %s after %s", run.Topic, run.Timeout)
+			}
+			return CIStatus{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// parseCIState returns the state of a status payload: a JSON object with a
+// conclusion or status field, as GitHub Actions reports them, or plain text
+// whose first word is the status. Common spellings of success and failure
+// are normalized; anything else is returned lowercased as a pending state.
+func parseCIState(payload string) string {
+	var obj struct {
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	}
+	state := ""
+	if err := json.Unmarshal([]byte(payload), &obj); err == nil {
+		state = obj.Status
+		if obj.Conclusion != "" {
+			state = obj.Conclusion
+		}
+	} else if fields := strings.Fields(payload); len(fields) > 0 {
+		state = strings.TrimRight(fields[0], ":.!,")
+	}
+
+	state = strings.ToLower(state)
+	switch state {
+	case "success", "succeeded", "passed", "pass", "ok", "green":
+		return CIStateSuccess
+	case "failure", "failed", "fail", "error", "errored", "cancelled", "canceled", "timed_out", "red":
+		return CIStateFailure
+	default:
+		return state
+	}
+}
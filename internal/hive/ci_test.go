@@ -0,0 +1,104 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerCI(t *testing.T) {
+	ctx := context.Background()
+	exec := &executil.RecordingExecutor{}
+	store := newMockStore()
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", Rules: []config.Rule{
+		{CI: &config.CIConfig{Trigger: "gh workflow run ci.yml --ref {{ .Branch }} -f topic={{ .Topic }}"}},
+	}}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	sess := session.Session{ID: "abc123", Name: "task", Path: t.TempDir(), Remote: "https://github.com/hay-kot/hive.git", State: session.StateActive}
+	require.NoError(t, store.Save(ctx, sess))
+
+	run, err := svc.TriggerCI(ctx, "task", io.Discard, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", run.SessionID)
+	assert.Equal(t, "agent.abc123.ci", run.Topic)
+	assert.Equal(t, config.DefaultCITimeout, run.Timeout)
+
+	require.Len(t, exec.Commands, 1)
+	got := exec.Commands[0]
+	assert.Equal(t, sess.Path, got.Dir)
+	assert.Equal(t, []string{"-c", "gh workflow run ci.yml --ref main -f topic=agent.abc123.ci"}, got.Args)
+	assert.Contains(t, got.Env, "HIVE_CI_TOPIC=agent.abc123.ci")
+	assert.Contains(t, got.Env, "HIVE_SESSION_ID=abc123")
+
+	t.Run("custom topic", func(t *testing.T) {
+		cfg.Rules = append(cfg.Rules, config.Rule{CI: &config.CIConfig{Trigger: "make ci", Topic: "ci.{{ .Repo }}.{{ .Branch }}", Timeout: time.Minute}})
+		run, err := svc.TriggerCI(ctx, "abc123", io.Discard, io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, "ci.hive.main", run.Topic)
+		assert.Equal(t, time.Minute, run.Timeout)
+	})
+
+	t.Run("no trigger", func(t *testing.T) {
+		cfg.Rules = nil
+		_, err := svc.TriggerCI(ctx, "abc123", io.Discard, io.Discard)
+		require.ErrorIs(t, err, ErrNoCITrigger)
+	})
+}
+
+func TestWaitCIStatus(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestService(t, newMockStore(), nil)
+	svc.ciPollInterval = time.Millisecond
+	msgs := jsonfile.NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+
+	run := CIRun{SessionID: "abc", Topic: "agent.abc.ci", StartedAt: time.Now().Add(-time.Second), Timeout: time.Second}
+	require.NoError(t, msgs.Publish(ctx, messaging.Message{Topic: run.Topic, Payload: "failure: stale run", CreatedAt: run.StartedAt.Add(-time.Minute)}))
+	require.NoError(t, msgs.Publish(ctx, messaging.Message{Topic: run.Topic, Payload: `{"status":"in_progress"}`}))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_ = msgs.Publish(ctx, messaging.Message{Topic: run.Topic, Payload: `{"status":"completed","conclusion":"success"}`})
+	}()
+
+	var seen []string
+	status, err := svc.WaitCIStatus(ctx, msgs, run, func(s CIStatus) { seen = append(seen, s.State) })
+	require.NoError(t, err)
+	assert.Equal(t, CIStateSuccess, status.State)
+	assert.Equal(t, []string{"in_progress", CIStateSuccess}, seen, "messages from before the run are ignored")
+
+	t.Run("timeout", func(t *testing.T) {
+		run := CIRun{Topic: "agent.quiet.ci", StartedAt: time.Now(), Timeout: 10 * time.Millisecond}
+		_, err := svc.WaitCIStatus(ctx, msgs, run, nil)
+		require.ErrorContains(t, err, "no ci status on agent.quiet.ci")
+	})
+}
+
+func TestParseCIState(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    string
+	}{
+		{"success", CIStateSuccess},
+		{"Passed: all 120 tests", CIStateSuccess},
+		{"FAILED. lint", CIStateFailure},
+		{`{"status":"completed","conclusion":"cancelled"}`, CIStateFailure},
+		{`{"status":"queued"}`, "queued"},
+		{"running", "running"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseCIState(tt.payload), tt.payload)
+	}
+}
@@ -0,0 +1,60 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hay-kot/hive/internal/core/git"
+)
+
+// ContextLink is a symlink from a directory to a context directory.
+type ContextLink struct {
+	Symlink string // symlink name, relative to the linked directory
+	Target  string // context directory the symlink points to
+	Created bool   // false if the symlink already existed
+}
+
+// RepoContextDir returns the context directory of the repository in dir,
+// found from its origin remote.
+func (s *Service) RepoContextDir(ctx context.Context, dir string) (string, error) {
+	remote, err := s.DetectRemote(ctx, dir)
+	if err != nil {
+		return "", fmt.Errorf("detect remote (are you in a git repository?): %w", err)
+	}
+
+	owner, repo := git.ExtractOwnerRepo(remote)
+	if owner == "" || repo == "" {
+		return "", fmt.Errorf("could not extract owner/repo from remote: %s", remote)
+	}
+	return s.cfg().RepoContextDir(owner, repo), nil
+}
+
+// InitContext creates the context directory ctxDir and links it into dir
+// under context.symlink_name. An existing symlink to ctxDir is left as is;
+// any other file of that name is an error.
+func (s *Service) InitContext(dir, ctxDir string) (ContextLink, error) {
+	link := ContextLink{Symlink: s.cfg().Context.SymlinkName, Target: ctxDir}
+
+	if err := os.MkdirAll(ctxDir, 0o755); err != nil {
+		return link, fmt.Errorf("create context directory: %w", err)
+	}
+
+	symlinkPath := filepath.Join(dir, link.Symlink)
+	if info, err := os.Lstat(symlinkPath); err == nil {
+		if info.Mode()&os.ModeSymlink == 0 {
+			return link, fmt.Errorf("%s already exists and is not a symlink", link.Symlink)
+		}
+		if target, _ := os.Readlink(symlinkPath); target != ctxDir {
+			return link, fmt.Errorf("symlink %s exists but points to %s, not %s", link.Symlink, target, ctxDir)
+		}
+		return link, nil
+	}
+
+	if err := os.Symlink(ctxDir, symlinkPath); err != nil {
+		return link, fmt.Errorf("create symlink: %w", err)
+	}
+	link.Created = true
+	return link, nil
+}
@@ -0,0 +1,35 @@
+package hive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitContext(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", Context: config.ContextConfig{SymlinkName: ".hive"}}
+	svc := newTestService(t, newMockStore(), cfg)
+
+	dir := t.TempDir()
+	ctxDir := cfg.RepoContextDir("hay-kot", "hive")
+
+	link, err := svc.InitContext(dir, ctxDir)
+	require.NoError(t, err)
+	assert.True(t, link.Created)
+	assert.DirExists(t, ctxDir)
+
+	target, err := os.Readlink(filepath.Join(dir, ".hive"))
+	require.NoError(t, err)
+	assert.Equal(t, ctxDir, target)
+
+	link, err = svc.InitContext(dir, ctxDir)
+	require.NoError(t, err)
+	assert.False(t, link.Created, "existing symlink is kept")
+
+	_, err = svc.InitContext(dir, cfg.SharedContextDir())
+	require.ErrorContains(t, err, "points to")
+}
@@ -6,12 +6,14 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
 	"github.com/hay-kot/hive/internal/core/config"
 	"github.com/hay-kot/hive/internal/styles"
+	"github.com/hay-kot/hive/pkg/tmpl"
 	"github.com/rs/zerolog"
 )
 
@@ -29,8 +31,9 @@ func NewFileCopier(log zerolog.Logger, stdout io.Writer) *FileCopier {
 	}
 }
 
-// CopyFiles copies files matching the rule's copy patterns from sourceDir to destDir.
-func (c *FileCopier) CopyFiles(ctx context.Context, rule config.Rule, sourceDir, destDir string) error {
+// CopyFiles copies the rule's copy entries from sourceDir to destDir.
+// Entries with template set have their contents rendered with data.
+func (c *FileCopier) CopyFiles(ctx context.Context, rule config.Rule, sourceDir, destDir string, data HookData) error {
 	// Validate source directory exists
 	info, err := os.Stat(sourceDir)
 	if err != nil {
@@ -44,17 +47,17 @@ func (c *FileCopier) CopyFiles(ctx context.Context, rule config.Rule, sourceDir,
 		Str("pattern", rule.Pattern).
 		Str("source", sourceDir).
 		Str("dest", destDir).
-		Strs("copy", rule.Copy).
+		Strs("copy", copyLabels(rule.Copy)).
 		Msg("processing copy patterns")
 
-	for _, filePattern := range rule.Copy {
+	for _, spec := range rule.Copy {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		if err := c.copyPattern(ctx, sourceDir, destDir, filePattern); err != nil {
+		if err := c.copySpec(ctx, sourceDir, destDir, spec, rule.MaxFileSize, data); err != nil {
 			return err
 		}
 	}
@@ -62,6 +65,15 @@ func (c *FileCopier) CopyFiles(ctx context.Context, rule config.Rule, sourceDir,
 	return nil
 }
 
+// copyLabels describes copy entries for logs and plans.
+func copyLabels(specs []config.CopySpec) []string {
+	labels := make([]string, len(specs))
+	for i, spec := range specs {
+		labels[i] = spec.String()
+	}
+	return labels
+}
+
 // globFiles finds files matching a pattern in sourceDir, including symlinks.
 // Returns paths relative to sourceDir.
 func (c *FileCopier) globFiles(sourceDir, pattern string) ([]string, error) {
@@ -118,56 +130,187 @@ func isPathTraversal(relPath string) bool {
 	return false
 }
 
-// copyPattern copies files matching a glob pattern from source to dest.
-func (c *FileCopier) copyPattern(ctx context.Context, sourceDir, destDir, pattern string) error {
-	matches, err := c.globFiles(sourceDir, pattern)
+// copyPair is a file to copy, relative to the source and destination.
+type copyPair struct {
+	src string
+	dst string
+}
+
+// copySpec copies the files matched by a copy entry from source to dest,
+// skipping excluded paths and regular files larger than maxSize (if set).
+func (c *FileCopier) copySpec(ctx context.Context, sourceDir, destDir string, spec config.CopySpec, maxSize config.ByteSize, data HookData) error {
+	matches, err := c.globFiles(sourceDir, spec.From)
 	if err != nil {
-		return fmt.Errorf("glob %q: %w", pattern, err)
+		return fmt.Errorf("glob %q: %w", spec.From, err)
 	}
 
 	if len(matches) == 0 {
 		c.log.Warn().
-			Str("pattern", pattern).
+			Str("pattern", spec.From).
 			Str("source", sourceDir).
 			Msg("glob pattern matched no files")
-		_, _ = fmt.Fprintf(c.stdout, "warning: pattern %q matched no files in %s\n", pattern, sourceDir)
+		_, _ = fmt.Fprintf(c.stdout, "warning: pattern %q matched no files in %s\n", spec.From, sourceDir)
 		return nil
 	}
 
-	c.printCopyHeader(pattern, len(matches))
-
+	var pairs []copyPair
 	for _, match := range matches {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
 		// Validate path doesn't escape source directory
 		if isPathTraversal(match) {
 			return fmt.Errorf("path traversal detected: %q", match)
 		}
 
-		srcPath := filepath.Join(sourceDir, match)
-		dstPath := filepath.Join(destDir, match)
+		dst := match
+		if spec.To != "" {
+			dst = spec.To
+		}
+		if isPathTraversal(dst) {
+			return fmt.Errorf("path traversal detected: %q", dst)
+		}
+
+		if isExcluded(match, spec.Exclude) {
+			continue
+		}
 
-		if err := c.copyFile(srcPath, dstPath); err != nil {
+		expanded, err := c.expandMatch(sourceDir, match, dst, spec.IsGlob(), spec.Exclude)
+		if err != nil {
 			return fmt.Errorf("copy %q: %w", match, err)
 		}
+		pairs = append(pairs, expanded...)
+	}
+
+	if maxSize > 0 {
+		pairs, err = c.dropOversized(sourceDir, pairs, maxSize)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.printCopyHeader(spec.String(), len(pairs))
+
+	var tmplData *HookData
+	if spec.Template {
+		tmplData = &data
+	}
+
+	for _, pair := range pairs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		srcPath := filepath.Join(sourceDir, pair.src)
+		dstPath := filepath.Join(destDir, pair.dst)
+
+		if err := c.copyFile(srcPath, dstPath, tmplData); err != nil {
+			return fmt.Errorf("copy %q: %w", pair.src, err)
+		}
 
 		c.log.Debug().
 			Str("src", srcPath).
 			Str("dst", dstPath).
 			Msg("copied file")
 
-		_, _ = fmt.Fprintf(c.stdout, "  %s\n", match)
+		if pair.src == pair.dst {
+			_, _ = fmt.Fprintf(c.stdout, "  %s\n", pair.src)
+		} else {
+			_, _ = fmt.Fprintf(c.stdout, "  %s -> %s\n", pair.src, pair.dst)
+		}
 	}
 
 	return nil
 }
 
+// expandMatch returns the files to copy for a match. A directory named by a
+// literal path is copied recursively, minus excluded paths; directories
+// matched by a glob are skipped, since patterns like "configs/**" also match
+// their contents.
+func (c *FileCopier) expandMatch(sourceDir, match, dst string, glob bool, exclude []string) ([]copyPair, error) {
+	root := filepath.Join(sourceDir, match)
+	info, err := os.Lstat(root)
+	if err != nil {
+		return nil, fmt.Errorf("lstat source: %w", err)
+	}
+
+	if !info.IsDir() {
+		return []copyPair{{src: match, dst: dst}}, nil
+	}
+
+	if glob {
+		c.log.Debug().
+			Str("path", root).
+			Msg("skipping directory matched by glob (only files are copied)")
+		return nil, nil
+	}
+
+	var pairs []copyPair
+	err = filepath.WalkDir(root, func(walked string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, walked)
+		if err != nil {
+			return err
+		}
+		if isExcluded(filepath.Join(match, rel), exclude) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		pairs = append(pairs, copyPair{src: filepath.Join(match, rel), dst: filepath.Join(dst, rel)})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory: %w", err)
+	}
+	return pairs, nil
+}
+
+// isExcluded reports whether a path relative to the source directory, or
+// any of its parent directories, matches an exclude glob.
+func isExcluded(relPath string, exclude []string) bool {
+	for _, pattern := range exclude {
+		for p := filepath.ToSlash(relPath); p != "."; p = path.Dir(p) {
+			if ok, _ := doublestar.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dropOversized removes regular files larger than maxSize from pairs,
+// reporting each one skipped. Symlinks are kept regardless of their target.
+func (c *FileCopier) dropOversized(sourceDir string, pairs []copyPair, maxSize config.ByteSize) ([]copyPair, error) {
+	kept := pairs[:0]
+	for _, pair := range pairs {
+		info, err := os.Lstat(filepath.Join(sourceDir, pair.src))
+		if err != nil {
+			return nil, fmt.Errorf("lstat %q: %w", pair.src, err)
+		}
+
+		if info.Mode().IsRegular() && info.Size() > int64(maxSize) {
+			c.log.Warn().
+				Str("path", pair.src).
+				Int64("size", info.Size()).
+				Stringer("max_file_size", maxSize).
+				Msg("skipping file over max_file_size")
+			_, _ = fmt.Fprintf(c.stdout, "warning: skipping %s (%s exceeds max_file_size %s)\n", pair.src, config.ByteSize(info.Size()), maxSize)
+			continue
+		}
+		kept = append(kept, pair)
+	}
+	return kept, nil
+}
+
 // copyFile copies a single file or symlink, preserving permissions and creating parent directories.
-func (c *FileCopier) copyFile(src, dst string) error {
+// If data is non-nil, a regular file's contents are rendered as a template with it.
+func (c *FileCopier) copyFile(src, dst string, data *HookData) error {
 	srcInfo, err := os.Lstat(src)
 	if err != nil {
 		return fmt.Errorf("lstat source: %w", err)
@@ -191,6 +334,10 @@ func (c *FileCopier) copyFile(src, dst string) error {
 		return c.copySymlink(src, dst)
 	}
 
+	if data != nil {
+		return c.renderFile(src, dst, srcInfo, *data)
+	}
+
 	return c.copyRegularFile(src, dst, srcInfo)
 }
 
@@ -260,6 +407,36 @@ func (c *FileCopier) copyRegularFile(src, dst string, srcInfo fs.FileInfo) error
 	return nil
 }
 
+// renderFile writes src rendered as a template with data to dst, preserving permissions.
+func (c *FileCopier) renderFile(src, dst string, srcInfo fs.FileInfo, data HookData) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("read source: %w", err)
+	}
+
+	rendered, err := tmpl.Render(string(content), data)
+	if err != nil {
+		return fmt.Errorf("render template: %w", err)
+	}
+
+	if _, err := os.Lstat(dst); err == nil {
+		c.log.Warn().
+			Str("path", dst).
+			Msg("overwriting existing file")
+		// Remove first so a symlink or read-only file is replaced, not written through
+		if err := os.Remove(dst); err != nil {
+			return fmt.Errorf("remove existing: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("check destination: %w", err)
+	}
+
+	if err := os.WriteFile(dst, []byte(rendered), srcInfo.Mode().Perm()); err != nil {
+		return fmt.Errorf("write destination: %w", err)
+	}
+	return nil
+}
+
 // printCopyHeader prints a styled header for a copy operation.
 func (c *FileCopier) printCopyHeader(pattern string, count int) {
 	divider := styles.DividerStyle.Render(strings.Repeat("─", 50))
@@ -14,12 +14,21 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func copyGlobs(patterns ...string) []config.CopySpec {
+	specs := make([]config.CopySpec, len(patterns))
+	for i, p := range patterns {
+		specs[i] = config.CopySpec{From: p}
+	}
+	return specs
+}
+
 func TestFileCopier_CopyFiles(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
 		name       string
 		rule       config.Rule
+		data       HookData
 		setupFiles map[string]string // relative path -> content
 		wantFiles  map[string]string // relative path -> content
 		wantErr    bool
@@ -32,13 +41,13 @@ func TestFileCopier_CopyFiles(t *testing.T) {
 		},
 		{
 			name:       "single file copy",
-			rule:       config.Rule{Copy: []string{".envrc"}},
+			rule:       config.Rule{Copy: copyGlobs(".envrc")},
 			setupFiles: map[string]string{".envrc": "export FOO=bar"},
 			wantFiles:  map[string]string{".envrc": "export FOO=bar"},
 		},
 		{
 			name: "wildcard pattern",
-			rule: config.Rule{Copy: []string{"*.txt"}},
+			rule: config.Rule{Copy: copyGlobs("*.txt")},
 			setupFiles: map[string]string{
 				"a.txt":  "a content",
 				"b.txt":  "b content",
@@ -51,7 +60,7 @@ func TestFileCopier_CopyFiles(t *testing.T) {
 		},
 		{
 			name: "doublestar pattern",
-			rule: config.Rule{Copy: []string{"configs/**/*.yaml"}},
+			rule: config.Rule{Copy: copyGlobs("configs/**/*.yaml")},
 			setupFiles: map[string]string{
 				"configs/dev/app.yaml":  "dev config",
 				"configs/prod/app.yaml": "prod config",
@@ -67,13 +76,13 @@ func TestFileCopier_CopyFiles(t *testing.T) {
 		},
 		{
 			name:       "glob matches nothing warns but continues",
-			rule:       config.Rule{Copy: []string{"nonexistent.txt", "exists.txt"}},
+			rule:       config.Rule{Copy: copyGlobs("nonexistent.txt", "exists.txt")},
 			setupFiles: map[string]string{"exists.txt": "content"},
 			wantFiles:  map[string]string{"exists.txt": "content"},
 		},
 		{
 			name: "multiple patterns",
-			rule: config.Rule{Copy: []string{".envrc", ".tool-versions"}},
+			rule: config.Rule{Copy: copyGlobs(".envrc", ".tool-versions")},
 			setupFiles: map[string]string{
 				".envrc":         "envrc content",
 				".tool-versions": "golang 1.21",
@@ -83,6 +92,75 @@ func TestFileCopier_CopyFiles(t *testing.T) {
 				".tool-versions": "golang 1.21",
 			},
 		},
+		{
+			name:       "rename on copy",
+			rule:       config.Rule{Copy: []config.CopySpec{{From: "envrc.example", To: ".envrc"}}},
+			setupFiles: map[string]string{"envrc.example": "export FOO=bar"},
+			wantFiles:  map[string]string{".envrc": "export FOO=bar"},
+		},
+		{
+			name: "directory copy",
+			rule: config.Rule{Copy: []config.CopySpec{{From: "secrets"}, {From: "fixtures", To: "testdata/fixtures"}}},
+			setupFiles: map[string]string{
+				"secrets/dev.json":     "dev",
+				"secrets/nested/a.pem": "pem",
+				"fixtures/one.txt":     "one",
+			},
+			wantFiles: map[string]string{
+				"secrets/dev.json":          "dev",
+				"secrets/nested/a.pem":      "pem",
+				"testdata/fixtures/one.txt": "one",
+			},
+		},
+		{
+			name:       "glob skips directories",
+			rule:       config.Rule{Copy: copyGlobs("configs/*")},
+			setupFiles: map[string]string{"configs/app.yaml": "app", "configs/sub/db.yaml": "db"},
+			wantFiles:  map[string]string{"configs/app.yaml": "app"},
+		},
+		{
+			name:       "template rendering",
+			rule:       config.Rule{Copy: []config.CopySpec{{From: ".envrc.tmpl", To: ".envrc", Template: true}}},
+			data:       HookData{ID: "abc123", Name: "Fix Bug"},
+			setupFiles: map[string]string{".envrc.tmpl": "export HIVE_ID={{ .ID }}\nexport NAME={{ .Name | shq }}\n"},
+			wantFiles:  map[string]string{".envrc": "export HIVE_ID=abc123\nexport NAME='Fix Bug'\n"},
+		},
+		{
+			name:       "template error",
+			rule:       config.Rule{Copy: []config.CopySpec{{From: ".envrc", Template: true}}},
+			setupFiles: map[string]string{".envrc": "{{ .Unknown }}"},
+			wantErr:    true,
+		},
+		{
+			name: "exclude globs",
+			rule: config.Rule{Copy: []config.CopySpec{
+				{From: "configs/**", Exclude: []string{"**/node_modules", "**/*.bin"}},
+				{From: "fixtures", Exclude: []string{"fixtures/large"}},
+			}},
+			setupFiles: map[string]string{
+				"configs/app.yaml":                   "app",
+				"configs/ui/node_modules/pkg/x.json": "x",
+				"configs/blob.bin":                   "bin",
+				"fixtures/small/a.txt":               "a",
+				"fixtures/large/b.txt":               "b",
+			},
+			wantFiles: map[string]string{
+				"configs/app.yaml":     "app",
+				"fixtures/small/a.txt": "a",
+			},
+		},
+		{
+			name:       "max file size",
+			rule:       config.Rule{Copy: copyGlobs("*.txt"), MaxFileSize: 4},
+			setupFiles: map[string]string{"small.txt": "tiny", "big.txt": "too large"},
+			wantFiles:  map[string]string{"small.txt": "tiny"},
+		},
+		{
+			name:       "rename escaping session",
+			rule:       config.Rule{Copy: []config.CopySpec{{From: ".envrc", To: "../.envrc"}}},
+			setupFiles: map[string]string{".envrc": "x"},
+			wantErr:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -106,7 +184,7 @@ func TestFileCopier_CopyFiles(t *testing.T) {
 			copier := NewFileCopier(log, &buf)
 
 			// Run copy
-			err := copier.CopyFiles(context.Background(), tt.rule, sourceDir, destDir)
+			err := copier.CopyFiles(context.Background(), tt.rule, sourceDir, destDir, tt.data)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
@@ -154,9 +232,9 @@ func TestFileCopier_PreservesPermissions(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"script.sh"}}
+	rule := config.Rule{Copy: copyGlobs("script.sh")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Check permissions
@@ -184,9 +262,9 @@ func TestFileCopier_OverwritesExisting(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"config.txt"}}
+	rule := config.Rule{Copy: copyGlobs("config.txt")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Verify overwritten
@@ -210,9 +288,9 @@ func TestFileCopier_CreatesParentDirectories(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"a/b/c/file.txt"}}
+	rule := config.Rule{Copy: copyGlobs("a/b/c/file.txt")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Verify file exists
@@ -235,12 +313,12 @@ func TestFileCopier_RespectsContextCancellation(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"test.txt"}}
+	rule := config.Rule{Copy: copyGlobs("test.txt")}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	err := copier.CopyFiles(ctx, rule, sourceDir, destDir)
+	err := copier.CopyFiles(ctx, rule, sourceDir, destDir, HookData{})
 	assert.ErrorIs(t, err, context.Canceled)
 }
 
@@ -261,9 +339,9 @@ func TestFileCopier_CopiesSymlink(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"link.txt"}}
+	rule := config.Rule{Copy: copyGlobs("link.txt")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Verify the destination is a symlink with the same target
@@ -292,9 +370,9 @@ func TestFileCopier_CopiesSymlinkWithAbsoluteTarget(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"abs-link"}}
+	rule := config.Rule{Copy: copyGlobs("abs-link")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Verify the symlink target is preserved
@@ -322,9 +400,9 @@ func TestFileCopier_OverwritesExistingSymlink(t *testing.T) {
 	log := zerolog.New(&buf).Level(zerolog.DebugLevel)
 	copier := NewFileCopier(log, &buf)
 
-	rule := config.Rule{Copy: []string{"link"}}
+	rule := config.Rule{Copy: copyGlobs("link")}
 
-	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir)
+	err := copier.CopyFiles(context.Background(), rule, sourceDir, destDir, HookData{})
 	require.NoError(t, err)
 
 	// Verify the symlink was overwritten
@@ -0,0 +1,80 @@
+package hive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// ErrNameTaken is returned when renaming a session to the name of another
+// active session.
+var ErrNameTaken = errors.New("session name already in use")
+
+// RenameSession changes a session's display name. The slug, and with it the
+// directory and terminal session name, stays the same. Another active
+// session must not already use the name.
+func (s *Service) RenameSession(ctx context.Context, ref, name string) (session.Session, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return session.Session{}, errors.New("session name is required")
+	}
+
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("get session: %w", err)
+	}
+	if sess.Name == name {
+		return sess, nil
+	}
+
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("list sessions: %w", err)
+	}
+	for _, other := range sessions {
+		if other.ID != sess.ID && other.State == session.StateActive && other.Name == name {
+			return session.Session{}, fmt.Errorf("%w: %q (%s)", ErrNameTaken, name, other.ID)
+		}
+	}
+
+	from := sess.Name
+	sess.Name = name
+	sess.UpdatedAt = time.Now()
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return session.Session{}, fmt.Errorf("save session: %w", err)
+	}
+
+	s.log.Info().Str("session_id", sess.ID).Str("from", from).Str("to", name).Msg("renamed session")
+	s.Emit(events.SessionRenamed, sess.ID, map[string]any{"from": from, "to": name})
+	return sess, nil
+}
+
+// SetNote records a free-form note on a session, shown next to it in the
+// TUI. An empty note removes it.
+func (s *Service) SetNote(ctx context.Context, ref, note string) (session.Session, error) {
+	note = strings.TrimSpace(note)
+
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("get session: %w", err)
+	}
+	if sess.Note() == note {
+		return sess, nil
+	}
+
+	if note == "" {
+		delete(sess.Metadata, session.MetaNote)
+	} else {
+		sess.SetMeta(session.MetaNote, note)
+	}
+	sess.UpdatedAt = time.Now()
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return session.Session{}, fmt.Errorf("save session: %w", err)
+	}
+	return sess, nil
+}
@@ -0,0 +1,52 @@
+package hive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameSession(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	svc := newTestService(t, store, nil)
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "task", Slug: "task", State: session.StateActive}
+	store.sessions["def"] = session.Session{ID: "def", Name: "other", Slug: "other", State: session.StateActive}
+	store.sessions["old"] = session.Session{ID: "old", Name: "retired", State: session.StateRecycled}
+
+	sess, err := svc.RenameSession(ctx, "task", "  fix auth  ")
+	require.NoError(t, err)
+	assert.Equal(t, "fix auth", sess.Name)
+	assert.Equal(t, "task", sess.Slug, "slug is kept")
+	assert.Equal(t, "fix auth", store.sessions["abc"].Name)
+
+	_, err = svc.RenameSession(ctx, "abc", "other")
+	require.ErrorIs(t, err, ErrNameTaken)
+
+	_, err = svc.RenameSession(ctx, "abc", "retired")
+	require.NoError(t, err, "recycled sessions don't hold their name")
+
+	_, err = svc.RenameSession(ctx, "abc", " ")
+	require.ErrorContains(t, err, "name is required")
+}
+
+func TestSetNote(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	svc := newTestService(t, store, nil)
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "task", State: session.StateActive}
+
+	sess, err := svc.SetNote(ctx, "task", "waiting on review ")
+	require.NoError(t, err)
+	assert.Equal(t, "waiting on review", sess.Note())
+	saved := store.sessions["abc"]
+	assert.Equal(t, "waiting on review", saved.Note())
+
+	_, err = svc.SetNote(ctx, "abc", "")
+	require.NoError(t, err)
+	saved = store.sessions["abc"]
+	assert.NotContains(t, saved.Metadata, session.MetaNote)
+}
@@ -0,0 +1,54 @@
+package hive
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// messagePreviewLen caps the payload recorded on message.published events,
+// in bytes.
+const messagePreviewLen = 1000
+
+// Events returns the activity log.
+func (s *Service) Events() *events.Log {
+	return s.events
+}
+
+// Emit appends an event to the activity log and runs the notifications
+// configured for it. Failures are logged, not returned; the log is a record
+// of what happened and must not stop it.
+func (s *Service) Emit(typ, sessionID string, data map[string]any) {
+	e := events.Event{Time: time.Now(), Type: typ, SessionID: sessionID, Data: data}
+	if err := s.events.Append(e); err != nil {
+		s.log.Warn().Err(err).Str("type", typ).Msg("failed to record event")
+	}
+	s.deps().notifier.Notify(context.Background(), e)
+}
+
+// EmitMessage records a message.published event for msg. The event carries
+// the topic, sender, and the start of the payload, so notifications can
+// forward it.
+func (s *Service) EmitMessage(msg messaging.Message) {
+	s.Emit(events.MessagePublished, msg.SessionID, map[string]any{
+		"topic":   msg.Topic,
+		"sender":  msg.Sender,
+		"payload": messagePreview(msg.Payload),
+	})
+}
+
+// messagePreview shortens payload to at most messagePreviewLen bytes without
+// splitting a UTF-8 sequence.
+func messagePreview(payload string) string {
+	if len(payload) <= messagePreviewLen {
+		return payload
+	}
+	cut := messagePreviewLen
+	for cut > 0 && !utf8.RuneStart(payload[cut]) {
+		cut--
+	}
+	return payload[:cut] + "…"
+}
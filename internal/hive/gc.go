@@ -0,0 +1,144 @@
+package hive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// Orphan kinds found by GC.
+const (
+	OrphanDirectory = "directory" // directory in the repos dir with no session record
+	OrphanRecord    = "record"    // session record whose directory no longer exists
+)
+
+// GCOptions controls GC.
+type GCOptions struct {
+	Remove bool // remove orphans instead of only reporting them
+	// MinAge skips directories modified more recently than this, so a
+	// session being created concurrently is not mistaken for an orphan.
+	MinAge time.Duration
+}
+
+// Orphan is a directory or session record without its counterpart.
+type Orphan struct {
+	Kind      string        `json:"kind"`
+	Path      string        `json:"path"`
+	SessionID string        `json:"session_id,omitempty"`
+	Name      string        `json:"name,omitempty"`
+	State     session.State `json:"state,omitempty"`
+	Bytes     int64         `json:"bytes"` // disk usage of an orphaned directory
+	Removed   bool          `json:"removed"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// GCResult lists the orphans GC found and the disk space freed by removing
+// them, or that removing them would free.
+type GCResult struct {
+	Orphans   []Orphan `json:"orphans"`
+	Reclaimed int64    `json:"reclaimed_bytes"`
+	// TrashExpired is the number of trash entries past trash.retention that
+	// were removed. Only set with GCOptions.Remove.
+	TrashExpired int `json:"trash_expired,omitempty"`
+}
+
+// GC reconciles the session store against the repos directory. It reports
+// directories that no session references, left by crashes mid-create or by
+// records deleted outside hive, and session records whose directory was
+// removed outside hive. With opts.Remove, orphaned directories are deleted
+// and orphaned records are dropped from the store; no hooks run for either.
+// Expired trash entries are removed as well.
+func (s *Service) GC(ctx context.Context, opts GCOptions) (GCResult, error) {
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return GCResult{}, fmt.Errorf("list sessions: %w", err)
+	}
+
+	dirs, err := s.orphanedDirs(sessions, opts.MinAge)
+	if err != nil {
+		return GCResult{}, err
+	}
+
+	result := GCResult{Orphans: dirs}
+	for _, sess := range sessions {
+		if _, err := os.Stat(sess.Path); errors.Is(err, os.ErrNotExist) {
+			result.Orphans = append(result.Orphans, Orphan{
+				Kind:      OrphanRecord,
+				Path:      sess.Path,
+				SessionID: sess.ID,
+				Name:      sess.Name,
+				State:     sess.State,
+			})
+		}
+	}
+
+	for i := range result.Orphans {
+		o := &result.Orphans[i]
+		if opts.Remove {
+			if err := s.removeOrphan(ctx, *o); err != nil {
+				s.log.Warn().Err(err).Str("kind", o.Kind).Str("path", o.Path).Msg("failed to remove orphan")
+				o.Error = err.Error()
+				continue
+			}
+			o.Removed = true
+		}
+		result.Reclaimed += o.Bytes
+	}
+
+	if opts.Remove {
+		n, err := s.ExpireTrash()
+		if err != nil {
+			s.log.Warn().Err(err).Msg("failed to expire trash")
+		}
+		result.TrashExpired = n
+	}
+
+	s.log.Info().Int("orphans", len(result.Orphans)).Bool("remove", opts.Remove).Msg("gc complete")
+	if opts.Remove {
+		s.Emit(events.GCCompleted, "", map[string]any{"orphans": len(result.Orphans), "reclaimed_bytes": result.Reclaimed})
+	}
+
+	return result, nil
+}
+
+// orphanedDirs returns the directories in the repos dir that no session
+// references and that were last modified at least minAge ago.
+func (s *Service) orphanedDirs(sessions []session.Session, minAge time.Duration) ([]Orphan, error) {
+	dirs, err := session.UnknownDirs(s.cfg().ReposDir(), sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []Orphan
+	cutoff := time.Now().Add(-minAge)
+	for _, path := range dirs {
+		info, err := os.Stat(path)
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+
+		orphans = append(orphans, Orphan{
+			Kind:  OrphanDirectory,
+			Path:  path,
+			Bytes: dirSize(path),
+		})
+	}
+	return orphans, nil
+}
+
+// removeOrphan deletes an orphaned directory or drops an orphaned record.
+func (s *Service) removeOrphan(ctx context.Context, o Orphan) error {
+	switch o.Kind {
+	case OrphanDirectory:
+		return os.RemoveAll(o.Path)
+	case OrphanRecord:
+		return s.sessions.Delete(ctx, o.SessionID)
+	default:
+		return fmt.Errorf("unknown orphan kind %q", o.Kind)
+	}
+}
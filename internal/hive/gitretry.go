@@ -0,0 +1,80 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/rs/zerolog"
+)
+
+// GitRetrier retries network-bound git operations, which fail on transient
+// network errors, with exponential backoff.
+type GitRetrier struct {
+	log     zerolog.Logger
+	stderr  io.Writer
+	retries int
+	delay   time.Duration
+}
+
+// NewGitRetrier creates a GitRetrier following the git.retries and
+// git.retry_delay settings. Retries are announced on stderr.
+func NewGitRetrier(log zerolog.Logger, stderr io.Writer, cfg config.GitConfig) *GitRetrier {
+	return &GitRetrier{log: log, stderr: stderr, retries: cfg.Retries, delay: cfg.RetryDelay}
+}
+
+// Do runs fn, the git operation op, and retries it after a failure up to
+// the configured number of times. The wait before retry n is the retry
+// delay times 2^(n-1). Retries are announced on w, or on stderr when w is
+// nil. Do stops waiting when ctx is cancelled.
+func (r *GitRetrier) Do(ctx context.Context, w io.Writer, op string, fn func() error) error {
+	if w == nil {
+		w = r.stderr
+	}
+
+	err := fn()
+	for attempt := 1; err != nil && attempt <= r.retries && ctx.Err() == nil; attempt++ {
+		wait := r.delay << (attempt - 1)
+		r.log.Warn().Err(err).Str("op", op).Int("attempt", attempt).Dur("wait", wait).Msg("git operation failed, retrying")
+		_, _ = fmt.Fprintf(w, "%s failed, retrying in %s (%d/%d): %v\n", op, wait, attempt, r.retries, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		err = fn()
+	}
+	return err
+}
+
+// networkGitCommands are the git subcommands that talk to a remote.
+var networkGitCommands = []string{"clone", "fetch", "pull", "push"}
+
+// isNetworkGitCommand reports whether a shell command is a single git clone,
+// fetch, pull, or push, safe to run again after a failure. Commands joined
+// with shell operators are not, as retrying would rerun the others too.
+func isNetworkGitCommand(cmd string) bool {
+	if strings.ContainsAny(cmd, ";&|\n") {
+		return false
+	}
+
+	fields := strings.Fields(cmd)
+	if len(fields) < 2 || fields[0] != "git" {
+		return false
+	}
+	for i := 1; i < len(fields); i++ {
+		switch f := fields[i]; {
+		case f == "-C" || f == "-c":
+			i++ // skip the option's value
+		case strings.HasPrefix(f, "-"):
+		default:
+			return slices.Contains(networkGitCommands, f)
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitRetrier_Do(t *testing.T) {
+	var out bytes.Buffer
+	r := NewGitRetrier(zerolog.New(io.Discard), &out, config.GitConfig{Retries: 2, RetryDelay: time.Millisecond})
+
+	calls := 0
+	err := r.Do(context.Background(), nil, "git clone", func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, out.String(), "git clone failed, retrying in 1ms (1/2): connection reset")
+	assert.Contains(t, out.String(), "git clone failed, retrying in 2ms (2/2): connection reset")
+
+	calls = 0
+	err = r.Do(context.Background(), io.Discard, "git pull", func() error {
+		calls++
+		return errors.New("timeout")
+	})
+	require.EqualError(t, err, "timeout")
+	assert.Equal(t, 3, calls, "first attempt plus two retries")
+}
+
+func TestGitRetrier_StopsOnCancel(t *testing.T) {
+	r := NewGitRetrier(zerolog.New(io.Discard), io.Discard, config.GitConfig{Retries: 5, RetryDelay: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := r.Do(ctx, nil, "git fetch", func() error {
+		calls++
+		cancel()
+		return errors.New("canceled")
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIsNetworkGitCommand(t *testing.T) {
+	tests := []struct {
+		cmd  string
+		want bool
+	}{
+		{cmd: "git fetch origin", want: true},
+		{cmd: "git -C sub pull --rebase", want: true},
+		{cmd: "git -c http.lowSpeedLimit=1000 push", want: true},
+		{cmd: "git checkout main"},
+		{cmd: "git fetch origin && git reset --hard origin/main"},
+		{cmd: "make fetch"},
+		{cmd: "git"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cmd, func(t *testing.T) {
+			assert.Equal(t, tt.want, isNetworkGitCommand(tt.cmd))
+		})
+	}
+}
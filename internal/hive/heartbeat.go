@@ -0,0 +1,136 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/integration/terminal"
+)
+
+// heartbeatTimeout bounds the git and terminal lookups for one session.
+const heartbeatTimeout = 5 * time.Second
+
+// HeartbeatSender is the sender recorded on heartbeat messages.
+const HeartbeatSender = "hive"
+
+// Heartbeat is the payload of a session heartbeat message.
+type Heartbeat struct {
+	SessionID string          `json:"session_id"`
+	Name      string          `json:"name"`
+	Terminal  terminal.Status `json:"terminal,omitempty"` // empty when no terminal integration is enabled
+	Tool      string          `json:"tool,omitempty"`     // AI tool detected in the terminal
+	Branch    string          `json:"branch,omitempty"`
+	Additions int             `json:"additions"`
+	Deletions int             `json:"deletions"`
+	Dirty     bool            `json:"dirty"`
+	GitError  string          `json:"git_error,omitempty"`
+}
+
+// RunHeartbeats publishes a heartbeat for every active session on its
+// HeartbeatTopic each interval until ctx is done. term may be nil, in which
+// case heartbeats carry no terminal status.
+func (s *Service) RunHeartbeats(ctx context.Context, store messaging.Store, term *terminal.Manager, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PublishHeartbeats(ctx, store, term, interval); err != nil && ctx.Err() == nil {
+			s.log.Warn().Err(err).Msg("failed to publish heartbeats")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishHeartbeats publishes one heartbeat for each active session. Each
+// heartbeat expires after three intervals, so a topic only holds the recent
+// ones. Failures for a single session are logged and skipped.
+func (s *Service) PublishHeartbeats(ctx context.Context, store messaging.Store, term *terminal.Manager, interval time.Duration) error {
+	sessions, err := s.sessions.Find(ctx, session.Filter{States: []session.State{session.StateActive}})
+	if err != nil {
+		return fmt.Errorf("list sessions: %w", err)
+	}
+
+	if term != nil {
+		term.RefreshAll()
+	}
+
+	for _, sess := range sessions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		payload, err := json.Marshal(s.heartbeat(ctx, sess, term))
+		if err != nil {
+			return fmt.Errorf("marshal heartbeat: %w", err)
+		}
+
+		now := time.Now()
+		msg := messaging.Message{
+			Topic:     sess.HeartbeatTopic(),
+			Payload:   string(payload),
+			Sender:    HeartbeatSender,
+			SessionID: sess.ID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(3 * interval),
+		}
+		messaging.Stamp(&msg)
+
+		if err := store.Publish(ctx, msg); err != nil {
+			s.log.Warn().Err(err).Str("session", sess.ID).Msg("failed to publish heartbeat")
+			continue
+		}
+		s.RelayMessage(ctx, msg)
+	}
+	return nil
+}
+
+// heartbeat gathers the terminal status and git summary of sess.
+func (s *Service) heartbeat(ctx context.Context, sess session.Session, term *terminal.Manager) Heartbeat {
+	ctx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
+	defer cancel()
+
+	hb := Heartbeat{SessionID: sess.ID, Name: sess.Name}
+
+	if term != nil && term.HasEnabledIntegrations() {
+		hb.Terminal = terminal.StatusMissing
+		if info, integration, _ := term.DiscoverSession(ctx, sess.Slug, sess.Metadata); info != nil && integration != nil {
+			if status, err := integration.GetStatus(ctx, info); err == nil {
+				hb.Terminal = status
+				hb.Tool = info.DetectedTool
+			}
+		}
+	}
+
+	if err := s.heartbeatGit(ctx, sess.Path, &hb); err != nil {
+		hb.GitError = err.Error()
+	}
+	return hb
+}
+
+func (s *Service) heartbeatGit(ctx context.Context, dir string, hb *Heartbeat) error {
+	branch, err := s.git.Branch(ctx, dir)
+	if err != nil {
+		return err
+	}
+	hb.Branch = branch
+
+	if hb.Additions, hb.Deletions, err = s.git.DiffStats(ctx, dir); err != nil {
+		return err
+	}
+
+	clean, err := s.git.IsClean(ctx, dir)
+	if err != nil {
+		return err
+	}
+	hb.Dirty = !clean
+	return nil
+}
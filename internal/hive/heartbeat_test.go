@@ -0,0 +1,43 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishHeartbeats(t *testing.T) {
+	store := newMockStore()
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "worker", State: session.StateActive, Path: t.TempDir()}
+	store.sessions["old"] = session.Session{ID: "old", Name: "done", State: session.StateRecycled, Path: t.TempDir()}
+	svc := newTestService(t, store, nil)
+
+	msgs := jsonfile.NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+	ctx := context.Background()
+	require.NoError(t, svc.PublishHeartbeats(ctx, msgs, nil, time.Minute))
+
+	messages, err := msgs.Subscribe(ctx, "session.abc.heartbeat", time.Time{})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+
+	msg := messages[0]
+	assert.Equal(t, HeartbeatSender, msg.Sender)
+	assert.Equal(t, "abc", msg.SessionID)
+	assert.WithinDuration(t, time.Now().Add(3*time.Minute), msg.ExpiresAt, 5*time.Second)
+
+	var hb Heartbeat
+	require.NoError(t, json.Unmarshal([]byte(msg.Payload), &hb))
+	assert.Equal(t, Heartbeat{SessionID: "abc", Name: "worker", Branch: "main"}, hb)
+
+	_, err = msgs.Subscribe(ctx, "session.old.heartbeat", time.Time{})
+	assert.True(t, errors.Is(err, messaging.ErrTopicNotFound), "recycled sessions get no heartbeat")
+}
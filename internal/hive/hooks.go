@@ -2,52 +2,133 @@ package hive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/hay-kot/hive/internal/core/config"
 	"github.com/hay-kot/hive/internal/styles"
 	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
 	"github.com/rs/zerolog"
 )
 
+// HookData is the template context for rule commands and lifecycle hooks.
+// It mirrors config.HookTemplateData.
+type HookData struct {
+	ID         string // Unique session identifier
+	Name       string // Session name (display name)
+	Slug       string // Session slug (URL-safe version of name)
+	Path       string // Absolute path to session directory
+	WorkDir    string // Working directory; Path joined with the session's subdir, if any
+	Remote     string // Git remote URL
+	Prompt     string // Prompt the session was created with (creation only)
+	ContextDir string // Path to context directory
+	Owner      string // Repository owner
+	Repo       string // Repository name
+}
+
+// Env returns the session metadata exported to hook commands.
+func (d HookData) Env() []string {
+	return []string{
+		"HIVE_SESSION_ID=" + d.ID,
+		"HIVE_PATH=" + d.Path,
+		"HIVE_WORKDIR=" + d.WorkDir,
+		"HIVE_REMOTE=" + d.Remote,
+		"HIVE_PROMPT=" + d.Prompt,
+	}
+}
+
 // HookRunner executes repository-specific setup hooks.
 type HookRunner struct {
-	log      zerolog.Logger
-	executor executil.Executor
-	stdout   io.Writer
-	stderr   io.Writer
+	log        zerolog.Logger
+	executor   executil.Executor
+	stdout     io.Writer
+	stderr     io.Writer
+	retryDelay time.Duration // wait between retries of a failed command
+
+	// startDetached launches background scripts; replaced in tests.
+	startDetached func(dir string, env []string, script, logPath string) error
 }
 
 // NewHookRunner creates a new HookRunner.
 func NewHookRunner(log zerolog.Logger, executor executil.Executor, stdout, stderr io.Writer) *HookRunner {
 	return &HookRunner{
-		log:      log,
-		executor: executor,
-		stdout:   stdout,
-		stderr:   stderr,
+		log:        log,
+		executor:   executor,
+		stdout:     stdout,
+		stderr:     stderr,
+		retryDelay: time.Second,
+
+		startDetached: startDetached,
+	}
+}
+
+// WithOutput returns a copy of the runner that writes command output to w.
+// If w is nil, output is discarded.
+func (h *HookRunner) WithOutput(w io.Writer) *HookRunner {
+	if w == nil {
+		w = io.Discard
 	}
+	cp := *h
+	cp.stdout = w
+	cp.stderr = w
+	return &cp
 }
 
 // RunHooks executes the commands from a matched rule.
-func (h *HookRunner) RunHooks(ctx context.Context, rule config.Rule, path string) error {
+func (h *HookRunner) RunHooks(ctx context.Context, rule config.Rule, data HookData) error {
 	h.log.Debug().
 		Str("pattern", rule.Pattern).
 		Strs("commands", rule.Commands).
 		Msg("running rule commands")
 
-	for i, cmd := range rule.Commands {
+	return h.Run(ctx, "hook", rule.Commands, data, rule.HookPolicy)
+}
+
+// Run renders and executes commands in the session directory, labelling
+// each command header with label. Session metadata from data is added to
+// the command environment. Each command is retried and timed out per
+// policy; when it still fails, policy.OnFailure decides whether Run
+// returns the error or moves on to the next command.
+func (h *HookRunner) Run(ctx context.Context, label string, commands []string, data HookData, policy config.HookPolicy) error {
+	ctx = executil.WithEnv(ctx, data.Env())
+
+	record := commandLogFrom(ctx)
+	stdout, stderr := teeOutput(ctx, record.tee(h.stdout)), teeOutput(ctx, record.tee(h.stderr))
+
+	for i, cmd := range commands {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
 		default:
 		}
 
-		h.printCommandHeader(i+1, len(rule.Commands), cmd)
+		rendered, err := tmpl.Render(cmd, data)
+		if err != nil {
+			return fmt.Errorf("render command %q: %w", cmd, err)
+		}
+
+		h.printCommandHeader(label, i+1, len(commands), rendered)
 
-		if err := h.executor.RunDirStream(ctx, path, h.stdout, h.stderr, "sh", "-c", cmd); err != nil {
-			return fmt.Errorf("run command %q: %w", cmd, err)
+		record.start(label, rendered)
+		begin := time.Now()
+		err = h.runWithRetries(ctx, data.Path, rendered, policy, stdout, stderr)
+		record.finish(begin, err)
+
+		if err != nil {
+			err = fmt.Errorf("run command %q: %w", rendered, err)
+			switch policy.OnFailure {
+			case config.OnFailureContinue:
+				h.log.Info().Err(err).Str("label", label).Msg("hook failed, continuing")
+			case config.OnFailureWarn:
+				h.log.Warn().Err(err).Str("label", label).Msg("hook failed, continuing")
+				_, _ = fmt.Fprintf(stderr, "warning: %v\n", err)
+			default:
+				return err
+			}
 		}
 
 		_, _ = fmt.Fprintln(h.stdout)
@@ -56,10 +137,74 @@ func (h *HookRunner) RunHooks(ctx context.Context, rule config.Rule, path string
 	return nil
 }
 
+// Start renders the commands of jobs and runs them in a detached process in
+// the session directory, appending output to logPath. It returns once the
+// process has started.
+func (h *HookRunner) Start(ctx context.Context, jobs []BackgroundJob, data HookData, logPath string) error {
+	rendered := make([]BackgroundJob, len(jobs))
+	for i, job := range jobs {
+		rendered[i] = BackgroundJob{Label: job.Label, Policy: job.Policy}
+		for _, cmd := range job.Commands {
+			out, err := tmpl.Render(cmd, data)
+			if err != nil {
+				return fmt.Errorf("render command %q: %w", cmd, err)
+			}
+			rendered[i].Commands = append(rendered[i].Commands, out)
+		}
+	}
+
+	env := append(executil.EnvFromContext(ctx), data.Env()...)
+
+	h.log.Debug().Str("log", logPath).Int("jobs", len(jobs)).Msg("starting background hooks")
+	return h.startDetached(data.Path, env, backgroundScript(rendered), logPath)
+}
+
+// runWithRetries runs a shell command, retrying failed attempts up to
+// policy.Retries times. Each attempt is limited to policy.Timeout.
+func (h *HookRunner) runWithRetries(ctx context.Context, dir, cmd string, policy config.HookPolicy, stdout, stderr io.Writer) error {
+	var err error
+	for attempt := 0; attempt <= policy.Retries; attempt++ {
+		if attempt > 0 {
+			h.log.Debug().Err(err).Int("attempt", attempt+1).Str("command", cmd).Msg("retrying hook")
+			_, _ = fmt.Fprintf(stderr, "retrying (%d/%d): %v\n", attempt, policy.Retries, err)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(h.retryDelay):
+			}
+		}
+
+		err = h.runOnce(ctx, dir, cmd, policy.Timeout, stdout, stderr)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// runOnce runs a shell command, stopping it and the processes it started
+// after timeout if non-zero.
+func (h *HookRunner) runOnce(ctx context.Context, dir, cmd string, timeout time.Duration, stdout, stderr io.Writer) error {
+	if timeout <= 0 {
+		return h.executor.RunDirStream(ctx, dir, stdout, stderr, "sh", "-c", cmd)
+	}
+
+	// Run in a process group so the timeout also stops what the shell started
+	ctx, cancel := context.WithTimeout(executil.WithProcessGroup(ctx), timeout)
+	defer cancel()
+
+	err := h.executor.RunDirStream(ctx, dir, stdout, stderr, "sh", "-c", cmd)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
 // printCommandHeader prints a styled header for a hook command.
-func (h *HookRunner) printCommandHeader(cmdNum, totalCmds int, cmd string) {
+func (h *HookRunner) printCommandHeader(label string, cmdNum, totalCmds int, cmd string) {
 	divider := styles.DividerStyle.Render(strings.Repeat("─", 50))
-	header := styles.CommandHeaderStyle.Render("hook")
+	header := styles.CommandHeaderStyle.Render(label)
 	cmdLabel := styles.DividerStyle.Render(fmt.Sprintf("[%d/%d]", cmdNum, totalCmds))
 	command := styles.CommandStyle.Render(cmd)
 
@@ -0,0 +1,205 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyExecutor fails the first failures runs of each command, and blocks
+// until the context is done for commands in hang.
+type flakyExecutor struct {
+	executil.RecordingExecutor
+	failures int
+	hang     map[string]bool
+	attempts map[string]int
+}
+
+func (e *flakyExecutor) RunDirStream(ctx context.Context, dir string, stdout, stderr io.Writer, cmd string, args ...string) error {
+	_ = e.RecordingExecutor.RunDirStream(ctx, dir, stdout, stderr, cmd, args...)
+
+	script := args[len(args)-1]
+	if e.hang[script] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	e.attempts[script]++
+	if e.attempts[script] <= e.failures {
+		return assert.AnError
+	}
+	return nil
+}
+
+func newTestHookRunner(exec executil.Executor, stderr io.Writer) *HookRunner {
+	h := NewHookRunner(zerolog.New(io.Discard), exec, io.Discard, stderr)
+	h.retryDelay = 0
+	return h
+}
+
+func TestHookRunner_Retries(t *testing.T) {
+	exec := &flakyExecutor{failures: 2, attempts: map[string]int{}}
+	h := newTestHookRunner(exec, io.Discard)
+
+	err := h.Run(context.Background(), "hook", []string{"npm install"}, HookData{}, config.HookPolicy{Retries: 1})
+	require.Error(t, err, "two failures exhaust a single retry")
+
+	exec = &flakyExecutor{failures: 2, attempts: map[string]int{}}
+	h = newTestHookRunner(exec, io.Discard)
+
+	err = h.Run(context.Background(), "hook", []string{"npm install"}, HookData{}, config.HookPolicy{Retries: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, exec.attempts["npm install"])
+}
+
+func TestHookRunner_WithOutput(t *testing.T) {
+	var stdout, out bytes.Buffer
+	h := NewHookRunner(zerolog.New(io.Discard), &executil.RealExecutor{}, &stdout, io.Discard)
+
+	ctx := WithOutput(context.Background(), &out)
+	require.NoError(t, h.Run(ctx, "hook", []string{"echo installed", "echo warn >&2"}, HookData{Path: t.TempDir()}, config.HookPolicy{}))
+	assert.Equal(t, "installed\nwarn\n", out.String(), "output of every command also goes to the context's writer")
+	assert.Contains(t, stdout.String(), "installed")
+}
+
+func TestHookRunner_Timeout(t *testing.T) {
+	exec := &flakyExecutor{hang: map[string]bool{"sleep 999": true}, attempts: map[string]int{}}
+	h := newTestHookRunner(exec, io.Discard)
+
+	start := time.Now()
+	err := h.Run(context.Background(), "hook", []string{"sleep 999"}, HookData{}, config.HookPolicy{Timeout: 10 * time.Millisecond})
+	require.ErrorContains(t, err, "timed out after 10ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestHookRunner_TimeoutStopsChildren(t *testing.T) {
+	h := newTestHookRunner(&executil.RealExecutor{}, io.Discard)
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	// The shell waits on a child, as it would on npm install
+	hook := "sleep 30 & echo $! > " + pidFile + "; wait"
+	err := h.Run(context.Background(), "hook", []string{hook}, HookData{}, config.HookPolicy{Timeout: 200 * time.Millisecond})
+	require.ErrorContains(t, err, "timed out")
+
+	data, err := os.ReadFile(pidFile)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return !processAlive(pid) }, 5*time.Second, 10*time.Millisecond, "child of a timed-out hook is stopped")
+}
+
+func TestHookRunner_OnFailure(t *testing.T) {
+	tests := []struct {
+		onFailure string
+		wantErr   bool
+		wantWarn  bool
+	}{
+		{onFailure: "", wantErr: true},
+		{onFailure: config.OnFailureAbort, wantErr: true},
+		{onFailure: config.OnFailureContinue},
+		{onFailure: config.OnFailureWarn, wantWarn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.onFailure, func(t *testing.T) {
+			exec := &executil.RecordingExecutor{Errors: map[string]error{"sh": assert.AnError}}
+			var stderr bytes.Buffer
+			h := newTestHookRunner(exec, &stderr)
+
+			err := h.Run(context.Background(), "hook", []string{"first", "second"}, HookData{}, config.HookPolicy{OnFailure: tt.onFailure})
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Len(t, exec.Commands, 1, "abort stops at the first failure")
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, exec.Commands, 2)
+			assert.Equal(t, tt.wantWarn, bytes.Contains(stderr.Bytes(), []byte("warning:")))
+		})
+	}
+}
+
+func TestBackgroundScript(t *testing.T) {
+	tests := []struct {
+		name      string
+		onFailure string
+		want      string
+		wantErr   bool
+	}{
+		{name: "continue", onFailure: config.OnFailureContinue, want: "one\ntwo\n"},
+		{name: "abort", onFailure: config.OnFailureAbort, want: "one\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			script := backgroundScript([]BackgroundJob{{
+				Label:    "hook",
+				Commands: []string{"echo one > out", "exit 3", "echo two >> out"},
+				Policy:   config.HookPolicy{OnFailure: tt.onFailure},
+			}})
+
+			cmd := exec.Command("sh", "-c", script)
+			cmd.Dir = dir
+			log, err := cmd.CombinedOutput()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Contains(t, string(log), "hook failed: exit 3")
+
+			out, err := os.ReadFile(filepath.Join(dir, "out"))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(out))
+		})
+	}
+}
+
+func TestHookRunner_Start(t *testing.T) {
+	h := newTestHookRunner(&executil.RecordingExecutor{}, io.Discard)
+
+	var gotDir, gotScript, gotLog string
+	var gotEnv []string
+	h.startDetached = func(dir string, env []string, script, logPath string) error {
+		gotDir, gotEnv, gotScript, gotLog = dir, env, script, logPath
+		return nil
+	}
+
+	ctx := executil.WithEnv(context.Background(), []string{"GH_TOKEN=tok"})
+	data := HookData{ID: "abc", Path: "/repos/abc", Name: "warm"}
+	jobs := []BackgroundJob{{Label: "post_create", Commands: []string{"seed {{ .Name }}"}}}
+
+	require.NoError(t, h.Start(ctx, jobs, data, "/logs/hooks.log"))
+	assert.Equal(t, "/repos/abc", gotDir)
+	assert.Equal(t, "/logs/hooks.log", gotLog)
+	assert.Contains(t, gotScript, "sh -c 'seed warm'")
+	assert.Contains(t, gotEnv, "GH_TOKEN=tok")
+	assert.Contains(t, gotEnv, "HIVE_SESSION_ID=abc")
+}
+
+func TestStartDetached(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "logs", "hooks.log")
+
+	require.NoError(t, startDetached(dir, []string{"HIVE_SESSION_ID=abc"}, `echo "id=$HIVE_SESSION_ID"`, logPath))
+
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(logPath)
+		return err == nil && string(data) == "id=abc\n"
+	}, 5*time.Second, 10*time.Millisecond)
+}
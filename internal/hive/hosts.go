@@ -0,0 +1,81 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hosts"
+)
+
+// remoteTimeout bounds a call to a remote host.
+const remoteTimeout = 30 * time.Second
+
+// HostSessions is the result of listing sessions on one remote host.
+type HostSessions struct {
+	Host     string
+	Sessions []session.Session
+	Err      error
+}
+
+// Hosts returns the configured remote host names, sorted.
+func (s *Service) Hosts() []string {
+	return slices.Sorted(maps.Keys(s.cfg().Hosts))
+}
+
+// Host returns a client for the configured remote host name.
+func (s *Service) Host(name string) (*hosts.Client, error) {
+	cfg, ok := s.cfg().Hosts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown host %q (configure it under hosts)", name)
+	}
+	return hosts.New(name, cfg, s.executor), nil
+}
+
+// RemoteSessions lists sessions on every configured host concurrently,
+// returning one result per host in name order. A host that cannot be reached
+// reports its error without failing the others.
+func (s *Service) RemoteSessions(ctx context.Context) []HostSessions {
+	names := s.Hosts()
+	results := make([]HostSessions, len(names))
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+			defer cancel()
+
+			client, _ := s.Host(name)
+			sessions, err := client.Sessions(ctx)
+			results[i] = HostSessions{Host: name, Sessions: sessions, Err: err}
+		})
+	}
+	wg.Wait()
+
+	return results
+}
+
+// RelayMessage publishes a locally published message on every host relaying
+// its topic. Failures are logged; the local publish already succeeded.
+func (s *Service) RelayMessage(ctx context.Context, msg messaging.Message) {
+	for _, name := range s.Hosts() {
+		client, _ := s.Host(name)
+		if !client.Relays(msg.Topic) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, remoteTimeout)
+		err := client.Publish(ctx, msg)
+		cancel()
+		if err != nil {
+			s.log.Warn().Err(err).Str("host", name).Str("topic", msg.Topic).Msg("failed to relay message")
+			continue
+		}
+		s.log.Debug().Str("host", name).Str("topic", msg.Topic).Msg("relayed message")
+	}
+}
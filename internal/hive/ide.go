@@ -0,0 +1,98 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// WorkspaceFolder is a folder of a VS Code workspace.
+type WorkspaceFolder struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// VSCodeWorkspace is the content of a .code-workspace file.
+type VSCodeWorkspace struct {
+	Folders  []WorkspaceFolder `json:"folders"`
+	Settings map[string]any    `json:"settings"`
+}
+
+// VSCodeWorkspace returns a workspace with a folder for the working directory
+// of each active session of remote, which is detected from the working
+// directory when empty. Folders are named after their sessions, with the
+// session ID added when names repeat, and sorted by name.
+func (s *Service) VSCodeWorkspace(ctx context.Context, remote string) (VSCodeWorkspace, error) {
+	remote, err := s.ResolveRemote(ctx, remote)
+	if err != nil {
+		return VSCodeWorkspace{}, err
+	}
+
+	sessions, err := s.sessions.Find(ctx, session.Filter{Remote: remote, States: []session.State{session.StateActive}})
+	if err != nil {
+		return VSCodeWorkspace{}, fmt.Errorf("list sessions: %w", err)
+	}
+
+	names := make(map[string]int, len(sessions))
+	for _, sess := range sessions {
+		names[sess.Name]++
+	}
+
+	ws := VSCodeWorkspace{Folders: []WorkspaceFolder{}, Settings: map[string]any{}}
+	for _, sess := range sessions {
+		name := sess.Name
+		if names[name] > 1 {
+			name += " #" + sess.ID
+		}
+		ws.Folders = append(ws.Folders, WorkspaceFolder{Name: name, Path: sess.WorkDir()})
+	}
+	slices.SortFunc(ws.Folders, func(a, b WorkspaceFolder) int { return strings.Compare(a.Name, b.Name) })
+	return ws, nil
+}
+
+// WorkspaceFile returns where the workspace of remote is written by
+// WriteVSCodeWorkspace: <owner>/<repo>.code-workspace under the workspaces
+// directory.
+func (s *Service) WorkspaceFile(remote string) string {
+	owner, repo := git.ExtractOwnerRepo(remote)
+	return filepath.Join(s.cfg().WorkspacesDir(), owner, repo+".code-workspace")
+}
+
+// Marshal returns the workspace as the indented JSON of a .code-workspace
+// file.
+func (ws VSCodeWorkspace) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode workspace: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// WriteVSCodeWorkspace writes ws to path, replacing the previous version
+// atomically so an editor watching the file never reads a partial one.
+func WriteVSCodeWorkspace(path string, ws VSCodeWorkspace) error {
+	data, err := ws.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create workspace directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write workspace: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write workspace: %w", err)
+	}
+	return nil
+}
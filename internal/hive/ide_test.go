@@ -0,0 +1,44 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVSCodeWorkspace(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	store := newMockStore()
+	store.sessions["a1"] = session.Session{ID: "a1", Name: "fix-auth", Path: "/repos/a1", Remote: remote, State: session.StateActive}
+	store.sessions["b2"] = session.Session{ID: "b2", Name: "docs", Path: "/repos/b2", Remote: remote, State: session.StateActive, Metadata: map[string]string{session.MetaSubdir: "site"}}
+	store.sessions["c3"] = session.Session{ID: "c3", Name: "docs", Path: "/repos/c3", Remote: remote, State: session.StateActive}
+	store.sessions["d4"] = session.Session{ID: "d4", Name: "old", Path: "/repos/d4", Remote: remote, State: session.StateRecycled}
+	store.sessions["e5"] = session.Session{ID: "e5", Name: "other", Path: "/repos/e5", Remote: "https://github.com/hay-kot/other.git", State: session.StateActive}
+	svc := newTestService(t, store, nil)
+
+	ws, err := svc.VSCodeWorkspace(context.Background(), remote)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkspaceFolder{
+		{Name: "docs #b2", Path: filepath.Join("/repos/b2", "site")},
+		{Name: "docs #c3", Path: "/repos/c3"},
+		{Name: "fix-auth", Path: "/repos/a1"},
+	}, ws.Folders)
+
+	path := svc.WorkspaceFile(remote)
+	assert.Equal(t, filepath.Join(svc.cfg().WorkspacesDir(), "hay-kot", "hive.code-workspace"), path)
+
+	require.NoError(t, WriteVSCodeWorkspace(path, ws))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var got VSCodeWorkspace
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, ws, got)
+	assert.NoFileExists(t, path+".tmp")
+}
@@ -0,0 +1,209 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+)
+
+// issueTimeout bounds a request to an issue provider's API.
+const issueTimeout = 30 * time.Second
+
+// Issue is an issue fetched for 'hive new --issue'.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+}
+
+// SessionName returns the default name of a session for the issue.
+func (i Issue) SessionName() string {
+	return fmt.Sprintf("issue-%d", i.Number)
+}
+
+// Prompt returns the built-in session prompt for the issue, used when no
+// template is configured.
+func (i Issue) Prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Resolve issue #%d: %s\n", i.Number, i.Title)
+	if body := strings.TrimSpace(i.Body); body != "" {
+		b.WriteString("\n" + body + "\n")
+	}
+	if i.URL != "" {
+		b.WriteString("\n" + i.URL + "\n")
+	}
+	return b.String()
+}
+
+// TemplateValues returns the issue as template field values, keeping only
+// the fields declared in fields so templates that skip some still resolve.
+func (i Issue) TemplateValues(fields []config.TemplateField) map[string]string {
+	all := map[string]string{
+		"issue_number": strconv.Itoa(i.Number),
+		"issue_title":  i.Title,
+		"issue_body":   i.Body,
+		"issue_url":    i.URL,
+	}
+	values := make(map[string]string)
+	for _, f := range fields {
+		if v, ok := all[f.Name]; ok {
+			values[f.Name] = v
+		}
+	}
+	return values
+}
+
+// FetchIssue fetches issue number of the repository at remote, which is
+// detected from the working directory when empty. The issues settings of the
+// last matching rule choose the provider and whether to call its REST API
+// with a token or run its CLI (gh or glab).
+func (s *Service) FetchIssue(ctx context.Context, remote string, number int) (Issue, error) {
+	remote, err := s.ResolveRemote(ctx, remote)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	host, path := git.ExtractHostPath(remote)
+	if host == "" {
+		return Issue{}, fmt.Errorf("issues need a hosted remote, got %s", remote)
+	}
+
+	cfg := s.cfg().IssueConfigFor(remote)
+	provider := cfg.Provider
+	if provider == "" {
+		provider = config.IssueProviderGitHub
+		if strings.Contains(host, "gitlab") {
+			provider = config.IssueProviderGitLab
+		}
+	}
+
+	var issue Issue
+	err = traced(ctx, "issue.fetch", func(ctx context.Context) error {
+		if cfg.Token == (config.SecretValue{}) {
+			issue, err = s.fetchIssueCLI(ctx, provider, host, path, number)
+			return err
+		}
+		issue, err = s.fetchIssueAPI(ctx, provider, cfg, host, path, number)
+		return err
+	})
+	if err != nil {
+		return Issue{}, fmt.Errorf("fetch %s issue %d of %s: %w", provider, number, path, err)
+	}
+	return issue, nil
+}
+
+// fetchIssueCLI fetches an issue with the gh or glab CLI, using its login.
+func (s *Service) fetchIssueCLI(ctx context.Context, provider, host, path string, number int) (Issue, error) {
+	n := strconv.Itoa(number)
+	var name string
+	var args []string
+	switch provider {
+	case config.IssueProviderGitLab:
+		name, args = "glab", []string{"issue", "view", n, "--repo", "https://" + host + "/" + path, "--output", "json"}
+	default:
+		name, args = "gh", []string{"issue", "view", n, "--repo", host + "/" + path, "--json", "number,title,body,url"}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := s.executor.RunStream(ctx, &stdout, &stderr, name, args...); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Issue{}, fmt.Errorf("%w: %s", err, msg)
+		}
+		return Issue{}, err
+	}
+	return decodeIssue(provider, stdout.Bytes())
+}
+
+// fetchIssueAPI fetches an issue from the provider's REST API with the
+// configured token.
+func (s *Service) fetchIssueAPI(ctx context.Context, provider string, cfg config.IssueConfig, host, path string, number int) (Issue, error) {
+	token, err := s.deps().secrets.Value(ctx, cfg.Token)
+	if err != nil {
+		return Issue{}, fmt.Errorf("resolve token: %w", err)
+	}
+
+	base := strings.TrimSuffix(cfg.APIURL, "/")
+	var endpoint string
+	switch provider {
+	case config.IssueProviderGitLab:
+		if base == "" {
+			base = "https://" + host + "/api/v4"
+		}
+		endpoint = fmt.Sprintf("%s/projects/%s/issues/%d", base, url.PathEscape(path), number)
+	default:
+		if base == "" {
+			base = "https://api.github.com"
+			if host != "github.com" {
+				base = "https://" + host + "/api/v3"
+			}
+		}
+		endpoint = fmt.Sprintf("%s/repos/%s/issues/%d", base, path, number)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Issue{}, err
+	}
+	if provider == config.IssueProviderGitLab {
+		req.Header.Set("PRIVATE-TOKEN", token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/vnd.github+json")
+	}
+
+	client := &http.Client{Timeout: issueTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Issue{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Issue{}, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Issue{}, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return decodeIssue(provider, body)
+}
+
+// decodeIssue parses an issue as returned by the provider's CLI or API.
+// GitHub's API names the URL html_url and GitLab's uses iid, description,
+// and web_url.
+func decodeIssue(provider string, data []byte) (Issue, error) {
+	var raw struct {
+		Number      int    `json:"number"`
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Body        string `json:"body"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		HTMLURL     string `json:"html_url"`
+		WebURL      string `json:"web_url"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Issue{}, fmt.Errorf("decode issue: %w", err)
+	}
+
+	if provider == config.IssueProviderGitLab {
+		return Issue{Number: raw.IID, Title: raw.Title, Body: raw.Description, URL: raw.WebURL}, nil
+	}
+	issue := Issue{Number: raw.Number, Title: raw.Title, Body: raw.Body, URL: raw.HTMLURL}
+	if raw.URL != "" && issue.URL == "" {
+		// gh prints the web URL as url; the API's url is the API resource
+		issue.URL = raw.URL
+	}
+	return issue, nil
+}
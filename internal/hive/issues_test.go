@@ -0,0 +1,121 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newIssueService(t *testing.T, exec *executil.RecordingExecutor, rules ...config.Rule) *Service {
+	t.Helper()
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", Rules: rules}
+	return New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+}
+
+func TestFetchIssue_CLI(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("github", func(t *testing.T) {
+		exec := &executil.RecordingExecutor{Outputs: map[string][]byte{
+			"gh": []byte(`{"number":123,"title":"Crash on start","body":"Steps...","url":"https://github.com/hay-kot/hive/issues/123"}`),
+		}}
+		svc := newIssueService(t, exec)
+
+		issue, err := svc.FetchIssue(ctx, "git@github.com:hay-kot/hive.git", 123)
+		require.NoError(t, err)
+		assert.Equal(t, Issue{Number: 123, Title: "Crash on start", Body: "Steps...", URL: "https://github.com/hay-kot/hive/issues/123"}, issue)
+
+		require.Len(t, exec.Commands, 1)
+		assert.Equal(t, "gh", exec.Commands[0].Cmd)
+		assert.Equal(t, []string{"issue", "view", "123", "--repo", "github.com/hay-kot/hive", "--json", "number,title,body,url"}, exec.Commands[0].Args)
+	})
+
+	t.Run("gitlab detected from host", func(t *testing.T) {
+		exec := &executil.RecordingExecutor{Outputs: map[string][]byte{
+			"glab": []byte(`{"iid":7,"title":"Flaky test","description":"It fails","web_url":"https://gitlab.com/org/sub/app/-/issues/7"}`),
+		}}
+		svc := newIssueService(t, exec)
+
+		issue, err := svc.FetchIssue(ctx, "https://gitlab.com/org/sub/app.git", 7)
+		require.NoError(t, err)
+		assert.Equal(t, Issue{Number: 7, Title: "Flaky test", Body: "It fails", URL: "https://gitlab.com/org/sub/app/-/issues/7"}, issue)
+		assert.Equal(t, "glab", exec.Commands[0].Cmd)
+		assert.Contains(t, exec.Commands[0].Args, "https://gitlab.com/org/sub/app")
+	})
+
+	t.Run("local remote", func(t *testing.T) {
+		svc := newIssueService(t, &executil.RecordingExecutor{})
+		_, err := svc.FetchIssue(ctx, t.TempDir(), 1)
+		require.ErrorContains(t, err, "hosted remote")
+	})
+}
+
+func TestFetchIssue_API(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("github", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/repos/hay-kot/hive/issues/123", r.URL.Path)
+			assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+			_, _ = io.WriteString(w, `{"number":123,"title":"Crash","body":"Steps","url":"https://api.github.com/repos/hay-kot/hive/issues/123","html_url":"https://github.com/hay-kot/hive/issues/123"}`)
+		}))
+		defer ts.Close()
+
+		exec := &executil.RecordingExecutor{}
+		svc := newIssueService(t, exec, config.Rule{Issues: &config.IssueConfig{Token: config.SecretValue{Value: "tok"}, APIURL: ts.URL}})
+
+		issue, err := svc.FetchIssue(ctx, "https://github.com/hay-kot/hive.git", 123)
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/hay-kot/hive/issues/123", issue.URL, "the web URL, not the API resource")
+		assert.Empty(t, exec.Commands, "the CLI is not used with a token")
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/projects/org%2Fapp/issues/7", r.URL.EscapedPath())
+			assert.Equal(t, "tok", r.Header.Get("PRIVATE-TOKEN"))
+			_, _ = io.WriteString(w, `{"iid":7,"title":"Flaky","description":"It fails","web_url":"https://git.acme.com/org/app/-/issues/7"}`)
+		}))
+		defer ts.Close()
+
+		svc := newIssueService(t, &executil.RecordingExecutor{}, config.Rule{Issues: &config.IssueConfig{
+			Provider: config.IssueProviderGitLab,
+			Token:    config.SecretValue{Value: "tok"},
+			APIURL:   ts.URL,
+		}})
+
+		issue, err := svc.FetchIssue(ctx, "git@git.acme.com:org/app.git", 7)
+		require.NoError(t, err)
+		assert.Equal(t, Issue{Number: 7, Title: "Flaky", Body: "It fails", URL: "https://git.acme.com/org/app/-/issues/7"}, issue)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}))
+		defer ts.Close()
+
+		svc := newIssueService(t, &executil.RecordingExecutor{}, config.Rule{Issues: &config.IssueConfig{Token: config.SecretValue{Value: "tok"}, APIURL: ts.URL}})
+
+		_, err := svc.FetchIssue(ctx, "https://github.com/hay-kot/hive.git", 9)
+		require.ErrorContains(t, err, "404 Not Found")
+	})
+}
+
+func TestIssue_PromptAndValues(t *testing.T) {
+	issue := Issue{Number: 5, Title: "Add dark mode", Body: "  Users want it.\n", URL: "https://github.com/o/r/issues/5"}
+
+	assert.Equal(t, "issue-5", issue.SessionName())
+	assert.Equal(t, "Resolve issue #5: Add dark mode\n\nUsers want it.\n\nhttps://github.com/o/r/issues/5\n", issue.Prompt())
+
+	values := issue.TemplateValues([]config.TemplateField{{Name: "issue_title"}, {Name: "issue_number"}, {Name: "focus"}})
+	assert.Equal(t, map[string]string{"issue_title": "Add dark mode", "issue_number": "5"}, values)
+}
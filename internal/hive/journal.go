@@ -0,0 +1,179 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// renameEntry records a session directory rename that the store does not
+// reflect yet. Entries are written before the rename and removed once the
+// session is saved with its new path, so a crash in between leaves an entry
+// that ReconcileRenames can finish.
+type renameEntry struct {
+	SessionID string    `json:"session_id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	PID       int       `json:"pid"` // process doing the rename; live entries are left alone
+	StartedAt time.Time `json:"started_at"`
+}
+
+// renameSessionDir renames a session's directory from from to to, recording
+// the rename in the journal first. Call endRename once the session is saved
+// with its new path.
+func (s *Service) renameSessionDir(id, from, to string) error {
+	entry := renameEntry{SessionID: id, From: from, To: to, PID: os.Getpid(), StartedAt: time.Now()}
+	if err := s.writeRenameEntry(entry); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		s.endRename(id)
+		return err
+	}
+	return nil
+}
+
+// endRename removes the journal entry of a session whose rename the store
+// now reflects.
+func (s *Service) endRename(id string) {
+	if err := os.Remove(s.renameEntryPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		s.log.Warn().Err(err).Str("session_id", id).Msg("failed to remove rename journal entry")
+	}
+}
+
+func (s *Service) renameEntryPath(id string) string {
+	return filepath.Join(s.cfg().JournalDir(), id+".json")
+}
+
+// writeRenameEntry writes entry to the journal through a temporary file, so
+// a crash never leaves a partial entry.
+func (s *Service) writeRenameEntry(entry renameEntry) error {
+	if err := os.MkdirAll(s.cfg().JournalDir(), 0o755); err != nil {
+		return fmt.Errorf("create journal directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encode journal entry: %w", err)
+	}
+
+	path := s.renameEntryPath(entry.SessionID)
+	tmp, err := os.CreateTemp(s.cfg().JournalDir(), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write journal entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write journal entry: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("sync journal entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write journal entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("write journal entry: %w", err)
+	}
+	return nil
+}
+
+// ReconcileRenames finishes renames left in the journal by a process that
+// exited between renaming a session's directory and saving the session. When
+// the directory was moved, the session is pointed at its new path and left
+// recycled, as both renames move a checkout that is not in use; otherwise the
+// entry is dropped. Entries of running processes are skipped. It returns the
+// number of sessions updated.
+func (s *Service) ReconcileRenames(ctx context.Context) (int, error) {
+	files, err := os.ReadDir(s.cfg().JournalDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read journal: %w", err)
+	}
+
+	updated := 0
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(s.cfg().JournalDir(), f.Name()))
+		if err != nil {
+			return updated, fmt.Errorf("read journal entry: %w", err)
+		}
+		var entry renameEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			s.log.Warn().Err(err).Str("file", f.Name()).Msg("dropping unreadable rename journal entry")
+			_ = os.Remove(filepath.Join(s.cfg().JournalDir(), f.Name()))
+			continue
+		}
+		if entry.PID != os.Getpid() && processAlive(entry.PID) {
+			continue
+		}
+
+		ok, err := s.reconcileRename(ctx, entry)
+		if err != nil {
+			return updated, err
+		}
+		if ok {
+			updated++
+		}
+		s.endRename(entry.SessionID)
+	}
+	return updated, nil
+}
+
+// reconcileRename brings the session of entry in line with where its
+// directory is, reporting whether the session was updated.
+func (s *Service) reconcileRename(ctx context.Context, entry renameEntry) (bool, error) {
+	sess, err := s.sessions.Get(ctx, entry.SessionID)
+	if errors.Is(err, session.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("get session: %w", err)
+	}
+	if sess.Path == entry.To {
+		return false, nil
+	}
+
+	_, fromErr := os.Stat(entry.From)
+	_, toErr := os.Stat(entry.To)
+	if !errors.Is(fromErr, os.ErrNotExist) || toErr != nil {
+		// The rename never happened, or the directories are not where either
+		// side expects; leave the session as it is
+		return false, nil
+	}
+
+	s.log.Info().Str("session_id", sess.ID).Str("from", sess.Path).Str("to", entry.To).Msg("reconciling interrupted rename")
+	sess.Path = entry.To
+	sess.MarkRecycled(time.Now())
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return false, fmt.Errorf("save session: %w", err)
+	}
+	return true, nil
+}
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
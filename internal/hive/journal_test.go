@@ -0,0 +1,73 @@
+package hive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileRenames(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	store := newMockStore()
+	svc := newTestService(t, store, cfg)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	from, to := filepath.Join(dir, "hive-task-abc"), filepath.Join(dir, "hive-recycle-xyz")
+	require.NoError(t, os.Mkdir(from, 0o755))
+	store.sessions["abc"] = session.Session{ID: "abc", Path: from, State: session.StateActive}
+
+	// Crash after the rename, before the session was saved
+	require.NoError(t, svc.renameSessionDir("abc", from, to))
+	assert.FileExists(t, filepath.Join(cfg.JournalDir(), "abc.json"))
+
+	n, err := svc.ReconcileRenames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, to, store.sessions["abc"].Path)
+	assert.Equal(t, session.StateRecycled, store.sessions["abc"].State)
+	assert.NoFileExists(t, filepath.Join(cfg.JournalDir(), "abc.json"))
+
+	// Crash before the rename: the entry is dropped, the session untouched
+	store.sessions["def"] = session.Session{ID: "def", Path: to, State: session.StateRecycled}
+	require.NoError(t, svc.writeRenameEntry(renameEntry{SessionID: "def", From: to, To: filepath.Join(dir, "hive-new-def"), StartedAt: time.Now()}))
+
+	n, err = svc.ReconcileRenames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, to, store.sessions["def"].Path)
+	assert.NoFileExists(t, filepath.Join(cfg.JournalDir(), "def.json"))
+}
+
+func TestReconcileRenames_SkipsLiveProcess(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	svc := newTestService(t, newMockStore(), cfg)
+
+	// The parent of the test process is alive and not this process
+	require.NoError(t, svc.writeRenameEntry(renameEntry{SessionID: "abc", From: "/a", To: "/b", PID: os.Getppid()}))
+
+	_, err := svc.ReconcileRenames(context.Background())
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(cfg.JournalDir(), "abc.json"))
+}
+
+func TestRecycleSession_ClearsJournal(t *testing.T) {
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	store := newMockStore()
+	svc := newTestService(t, store, cfg)
+
+	path := filepath.Join(cfg.ReposDir(), "hive-task-abc")
+	require.NoError(t, os.MkdirAll(path, 0o755))
+	store.sessions["abc"] = session.Session{ID: "abc", Path: path, State: session.StateActive, Remote: "https://github.com/hay-kot/hive.git"}
+
+	require.NoError(t, svc.RecycleSession(context.Background(), "abc", nil))
+	assert.NoFileExists(t, filepath.Join(cfg.JournalDir(), "abc.json"))
+	assert.Equal(t, session.StateRecycled, store.sessions["abc"].State)
+}
@@ -0,0 +1,180 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/randid"
+)
+
+// ErrMaxActive is returned when creating a session would exceed
+// max_active_sessions or the max_active of the repository's rules.
+var ErrMaxActive = errors.New("active session limit reached")
+
+// reserveActive reserves a slot for a new active session of remote under
+// max_active_sessions and the rules' max_active, counting sessions other
+// creates, in this or another hive process, have reserved but not yet saved.
+// The returned func releases the slot and may be called more than once; call
+// it as soon as the session is saved, as from then on the session counts
+// itself. When the limits are reached, it fails with ErrMaxActive, or with
+// wait set, checks again every activeWaitInterval until a slot frees up or
+// ctx is cancelled. The wait is recorded as session.StepWait.
+func (s *Service) reserveActive(ctx context.Context, remote string, wait bool) (func(), error) {
+	release, err := s.tryReserveActive(ctx, remote)
+	if !wait || !errors.Is(err, ErrMaxActive) {
+		return release, err
+	}
+
+	s.log.Info().Err(err).Str("remote", remote).Msg("waiting for a free session slot")
+	err = timeStep(ctx, session.StepWait, "", func(ctx context.Context) error {
+		if w := git.ProgressWriter(ctx); w != nil {
+			_, _ = fmt.Fprintln(w, err)
+		}
+
+		ticker := time.NewTicker(s.activeWaitInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+
+			var tryErr error
+			release, tryErr = s.tryReserveActive(ctx, remote)
+			if !errors.Is(tryErr, ErrMaxActive) {
+				return tryErr
+			}
+		}
+	})
+	return release, err
+}
+
+// reservation is an active session slot held by a CreateSession of any hive
+// process from the limit check until the session is saved.
+type reservation struct {
+	ID     string    `json:"id"`
+	PID    int       `json:"pid"`
+	Remote string    `json:"remote"`
+	At     time.Time `json:"at"`
+}
+
+// tryReserveActive reserves a slot for a new active session of remote if the
+// limits allow one. Reservations are kept in a file in the data directory,
+// locked while it is read and updated, so concurrent hive processes count
+// each other's in-flight creates. Reservations of processes that are gone
+// are dropped.
+func (s *Service) tryReserveActive(ctx context.Context, remote string) (func(), error) {
+	globalLimit, repoLimit := s.cfg().MaxActiveSessions, s.cfg().GetMaxActive(remote)
+	if globalLimit == 0 && repoLimit == 0 {
+		return func() {}, nil
+	}
+
+	path := s.cfg().ReservationsFile()
+	id := randid.Generate(8)
+	err := withReservations(path, func(held []reservation) ([]reservation, error) {
+		sessions, err := s.sessions.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list sessions: %w", err)
+		}
+
+		total, forRepo := len(held), 0
+		for _, r := range held {
+			if r.Remote == remote {
+				forRepo++
+			}
+		}
+		for _, sess := range sessions {
+			if sess.State != session.StateActive || sess.IsReview() {
+				continue
+			}
+			total++
+			if sess.Remote == remote {
+				forRepo++
+			}
+		}
+
+		if globalLimit > 0 && total >= globalLimit {
+			return nil, fmt.Errorf("%w: %d of %d active sessions (max_active_sessions)", ErrMaxActive, total, globalLimit)
+		}
+		if repoLimit > 0 && forRepo >= repoLimit {
+			return nil, fmt.Errorf("%w: %d of %d active sessions for %s (rules max_active)", ErrMaxActive, forRepo, repoLimit, remote)
+		}
+		return append(held, reservation{ID: id, PID: os.Getpid(), Remote: remote, At: time.Now()}), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sync.OnceFunc(func() {
+		err := withReservations(path, func(held []reservation) ([]reservation, error) {
+			return slices.DeleteFunc(held, func(r reservation) bool { return r.ID == id }), nil
+		})
+		if err != nil {
+			s.log.Warn().Err(err).Str("remote", remote).Msg("failed to release session slot")
+		}
+	}), nil
+}
+
+// withReservations runs fn with the live reservations in path while holding
+// an exclusive flock on path's lock file, and writes back the reservations fn
+// returns. Nothing is written when fn fails.
+func withReservations(path string, fn func([]reservation) ([]reservation, error)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create data directory: %w", err)
+	}
+
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("open lock file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("acquire file lock: %w", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	var held []reservation
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("read reservations: %w", err)
+	case len(data) > 0:
+		if err := json.Unmarshal(data, &held); err != nil {
+			return fmt.Errorf("parse reservations: %w", err)
+		}
+	}
+	held = slices.DeleteFunc(held, func(r reservation) bool { return !processAlive(r.PID) })
+
+	held, err = fn(held)
+	if err != nil {
+		return err
+	}
+
+	data, err = json.Marshal(held)
+	if err != nil {
+		return fmt.Errorf("encode reservations: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write reservations: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write reservations: %w", err)
+	}
+	return nil
+}
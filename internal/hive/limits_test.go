@@ -0,0 +1,165 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSession_MaxActive(t *testing.T) {
+	const (
+		hiveRemote  = "https://github.com/hay-kot/hive.git"
+		otherRemote = "https://github.com/hay-kot/other.git"
+	)
+	one, two := 1, 2
+
+	tests := []struct {
+		name    string
+		global  int
+		rules   []config.Rule
+		remote  string
+		wantErr bool
+	}{
+		{name: "unlimited", remote: hiveRemote},
+		{name: "global cap", global: 2, remote: otherRemote, wantErr: true},
+		{name: "under global cap", global: 3, remote: otherRemote},
+		{name: "repo cap", rules: []config.Rule{{Pattern: "hay-kot/hive", MaxActive: &one}}, remote: hiveRemote, wantErr: true},
+		{name: "repo cap of other repo", rules: []config.Rule{{Pattern: "hay-kot/hive", MaxActive: &one}}, remote: otherRemote},
+		{name: "later rule wins", rules: []config.Rule{{MaxActive: &one}, {Pattern: "hay-kot/hive", MaxActive: &two}}, remote: hiveRemote},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.sessions["a1"] = session.Session{ID: "a1", State: session.StateActive, Remote: hiveRemote}
+			store.sessions["a2"] = session.Session{ID: "a2", State: session.StateCorrupted, Remote: hiveRemote}
+			store.sessions["a3"] = session.Session{ID: "a3", State: session.StateActive, Remote: otherRemote}
+			store.sessions["a4"] = session.Session{ID: "a4", State: session.StateActive, Remote: hiveRemote,
+				Metadata: map[string]string{session.MetaReviewOf: "a1"}}
+
+			cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", MaxActiveSessions: tt.global, Rules: tt.rules}
+			svc := newTestService(t, store, cfg)
+
+			_, err := svc.CreateSession(context.Background(), CreateOptions{Name: "task", Remote: tt.remote})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrMaxActive)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateSession_MaxActiveWait(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	store := newMockStore()
+	store.sessions["a1"] = session.Session{ID: "a1", State: session.StateActive, Remote: remote}
+
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", MaxActiveSessions: 1}
+	svc := newTestService(t, store, cfg)
+	svc.activeWaitInterval = time.Millisecond
+
+	rec := &stepRecorder{}
+	ctx, cancel := context.WithTimeout(WithStepReporter(context.Background(), rec), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := svc.CreateSession(ctx, CreateOptions{Name: "task", Remote: remote, Wait: true})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"start wait", "end wait context deadline exceeded"}, rec.events)
+	assert.Empty(t, heldReservations(t, cfg), "no slot is left reserved")
+}
+
+// spawnHookExecutor calls onSpawn for each spawn command it runs.
+type spawnHookExecutor struct {
+	executil.RecordingExecutor
+	onSpawn func()
+}
+
+func (e *spawnHookExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer, cmd string, args ...string) error {
+	e.onSpawn()
+	return e.RecordingExecutor.RunStream(ctx, stdout, stderr, cmd, args...)
+}
+
+func TestCreateSession_ReleasesSlotOnceSaved(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir:           t.TempDir(),
+		GitPath:           "git",
+		MaxActiveSessions: 2,
+		Commands:          config.Commands{Spawn: []string{"open {{ .Path }}"}},
+	}
+
+	// While the terminal spawns, the saved session counts itself, so
+	// another create may take the last slot
+	var held []reservation
+	var otherErr error
+	exec := &spawnHookExecutor{}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	exec.onSpawn = func() {
+		held = heldReservations(t, cfg)
+		var release func()
+		release, otherErr = svc.tryReserveActive(context.Background(), remote)
+		if otherErr == nil {
+			release()
+		}
+	}
+
+	_, err := svc.CreateSession(context.Background(), CreateOptions{Name: "task", Remote: remote})
+	require.NoError(t, err)
+	assert.Empty(t, held, "the slot is released once the session is saved")
+	require.NoError(t, otherErr)
+}
+
+func TestTryReserveActive_AcrossServices(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	ctx := context.Background()
+	store := newMockStore()
+	store.sessions["a1"] = session.Session{ID: "a1", State: session.StateActive, Remote: remote}
+
+	// Two services on one store and data directory, as two hive processes
+	dataDir := t.TempDir()
+	first := newTestService(t, store, &config.Config{DataDir: dataDir, GitPath: "git", MaxActiveSessions: 2})
+	second := newTestService(t, store, &config.Config{DataDir: dataDir, GitPath: "git", MaxActiveSessions: 2})
+
+	release, err := first.tryReserveActive(ctx, remote)
+	require.NoError(t, err)
+
+	_, err = second.tryReserveActive(ctx, remote)
+	require.ErrorIs(t, err, ErrMaxActive, "the other service's reservation counts")
+
+	release()
+	releaseSecond, err := second.tryReserveActive(ctx, remote)
+	require.NoError(t, err)
+	releaseSecond()
+	assert.Empty(t, heldReservations(t, first.cfg()))
+
+	t.Run("reservations of exited processes are dropped", func(t *testing.T) {
+		require.NoError(t, withReservations(first.cfg().ReservationsFile(), func(held []reservation) ([]reservation, error) {
+			return append(held, reservation{ID: "gone", PID: -1, Remote: remote}), nil
+		}))
+
+		release, err := second.tryReserveActive(ctx, remote)
+		require.NoError(t, err)
+		release()
+	})
+}
+
+// heldReservations returns the live active session reservations of cfg.
+func heldReservations(t *testing.T, cfg *config.Config) []reservation {
+	t.Helper()
+	var got []reservation
+	require.NoError(t, withReservations(cfg.ReservationsFile(), func(held []reservation) ([]reservation, error) {
+		got = held
+		return held, nil
+	}))
+	return got
+}
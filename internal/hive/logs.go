@@ -0,0 +1,160 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/git"
+)
+
+// Session log sources. Each is written to <source>.log in the session's log
+// directory (see config.SessionLogFile).
+const (
+	LogSourceHooks   = "hooks"   // rule commands and lifecycle hooks
+	LogSourceRecycle = "recycle" // recycle commands
+	LogSourceSpawn   = "spawn"   // spawn and batch_spawn commands
+)
+
+// LogSources lists the session log sources in display order.
+var LogSources = []string{LogSourceHooks, LogSourceRecycle, LogSourceSpawn}
+
+// commandLog appends a record of each command run, with its output, to a
+// session log file. The file is opened on first write, so sessions without
+// commands get no log. A nil *commandLog discards everything.
+type commandLog struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	err  error // open error; once set, writes are dropped
+}
+
+// writer returns the open log file, opening it on first use. Callers must
+// hold l.mu.
+func (l *commandLog) writer() io.Writer {
+	if l.f == nil && l.err == nil {
+		if l.err = os.MkdirAll(filepath.Dir(l.path), 0o755); l.err == nil {
+			l.f, l.err = os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		}
+	}
+	if l.err != nil {
+		return io.Discard
+	}
+	return l.f
+}
+
+// Close closes the log file if it was opened.
+func (l *commandLog) Close() error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return nil
+	}
+	return l.f.Close()
+}
+
+// start records the beginning of a command.
+func (l *commandLog) start(label, cmd string) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = fmt.Fprintf(l.writer(), "=== %s [%s] %s\n", time.Now().Format(time.RFC3339), label, cmd)
+}
+
+// finish records the outcome of a command started at begin.
+func (l *commandLog) finish(begin time.Time, err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	elapsed := time.Since(begin).Round(time.Millisecond)
+	if err != nil {
+		_, _ = fmt.Fprintf(l.writer(), "--- failed after %s: %v\n", elapsed, err)
+		return
+	}
+	_, _ = fmt.Fprintf(l.writer(), "--- ok after %s\n", elapsed)
+}
+
+// Write appends command output to the log.
+func (l *commandLog) Write(p []byte) (int, error) {
+	if l == nil {
+		return len(p), nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = l.writer().Write(p)
+	return len(p), nil
+}
+
+// tee returns a writer that sends output to w and the log.
+func (l *commandLog) tee(w io.Writer) io.Writer {
+	if l == nil {
+		return w
+	}
+	if w == nil {
+		return l
+	}
+	return io.MultiWriter(w, l)
+}
+
+type commandLogKey struct{}
+
+type outputKey struct{}
+
+// WithOutput returns a context in which the commands run for an operation,
+// such as git's clone progress, rule commands and hooks, and spawn commands,
+// also write their output to w. hive batch uses it to tag each session's
+// output in the batch log.
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return git.WithProgress(context.WithValue(ctx, outputKey{}, w), w)
+}
+
+// teeOutput returns a writer that sends output to w and to the writer
+// attached by WithOutput, if any.
+func teeOutput(ctx context.Context, w io.Writer) io.Writer {
+	out, _ := ctx.Value(outputKey{}).(io.Writer)
+	if out == nil {
+		return w
+	}
+	if w == nil {
+		return out
+	}
+	return io.MultiWriter(w, out)
+}
+
+// withCommandLog attaches l to ctx so runners record the commands they run.
+func withCommandLog(ctx context.Context, l *commandLog) context.Context {
+	if l == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, commandLogKey{}, l)
+}
+
+// commandLogFrom returns the log attached by withCommandLog, or nil.
+func commandLogFrom(ctx context.Context) *commandLog {
+	l, _ := ctx.Value(commandLogKey{}).(*commandLog)
+	return l
+}
+
+// openSessionLog returns the log for a session and source. Logging never
+// fails the command being logged: if the file cannot be opened, output is
+// dropped and a warning is logged when the log is closed.
+func (s *Service) openSessionLog(id, source string) (*commandLog, func()) {
+	l := &commandLog{path: s.cfg().SessionLogFile(id, source)}
+	return l, func() {
+		if l.err != nil {
+			s.log.Warn().Err(l.err).Str("session_id", id).Str("source", source).Msg("failed to write session log")
+		}
+		_ = l.Close()
+	}
+}
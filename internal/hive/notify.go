@@ -0,0 +1,280 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/events"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
+	"github.com/rs/zerolog"
+)
+
+// notifyTimeout bounds how long an event waits on its notifications.
+const notifyTimeout = 10 * time.Second
+
+// Notifier runs the configured notification commands and webhooks for
+// recorded events.
+type Notifier struct {
+	log           zerolog.Logger
+	executor      executil.Executor
+	secrets       *SecretResolver
+	client        *http.Client
+	notifications []config.Notification
+}
+
+// NewNotifier creates a new Notifier.
+func NewNotifier(log zerolog.Logger, executor executil.Executor, secrets *SecretResolver, notifications []config.Notification) *Notifier {
+	return &Notifier{
+		log:           log,
+		executor:      executor,
+		secrets:       secrets,
+		client:        &http.Client{Timeout: notifyTimeout},
+		notifications: notifications,
+	}
+}
+
+// Notify runs every notification matching e concurrently and waits for them
+// to finish. Failures are logged; a broken webhook must not fail the
+// operation that produced the event.
+func (n *Notifier) Notify(ctx context.Context, e events.Event) {
+	var matched []config.Notification
+	for _, cfg := range n.notifications {
+		if notificationMatches(cfg.Events, e) && topicMatches(cfg.Topics, e) {
+			matched = append(matched, cfg)
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	data, err := notificationData(e)
+	if err != nil {
+		n.log.Warn().Err(err).Str("type", e.Type).Msg("failed to build notification data")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, cfg := range matched {
+		wg.Go(func() {
+			var err error
+			switch chat, _ := cfg.Chat(); {
+			case cfg.Webhook != "":
+				err = n.post(ctx, cfg, data)
+			case chat != "":
+				err = n.postChat(ctx, cfg, data)
+			default:
+				err = n.run(ctx, cfg.Command, data)
+			}
+			if err != nil {
+				n.log.Warn().Err(err).Str("type", e.Type).Msg("notification failed")
+			}
+		})
+	}
+	wg.Wait()
+}
+
+// notificationMatches reports whether any pattern matches the event type or,
+// for terminal status changes, "status.<to>".
+func notificationMatches(patterns []string, e events.Event) bool {
+	types := []string{e.Type}
+	if e.Type == events.SessionStatus {
+		if to, ok := e.Data["to"].(string); ok {
+			types = append(types, "status."+to)
+		}
+	}
+
+	for _, pattern := range patterns {
+		for _, typ := range types {
+			if ok, _ := path.Match(pattern, typ); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// topicMatches reports whether a message.published event's topic matches
+// any pattern. Other events, and notifications without topics, always match.
+func topicMatches(patterns []string, e events.Event) bool {
+	if len(patterns) == 0 || e.Type != events.MessagePublished {
+		return true
+	}
+	topic, _ := e.Data["topic"].(string)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, topic); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func notificationData(e events.Event) (config.NotificationTemplateData, error) {
+	raw, err := json.Marshal(e)
+	if err != nil {
+		return config.NotificationTemplateData{}, fmt.Errorf("marshal event: %w", err)
+	}
+	name, _ := e.Data["name"].(string)
+	return config.NotificationTemplateData{
+		Type:      e.Type,
+		SessionID: e.SessionID,
+		Name:      name,
+		Time:      e.Time.Format(time.RFC3339),
+		Data:      e.Data,
+		JSON:      string(raw),
+	}, nil
+}
+
+// run executes a notification command with the event in HIVE_EVENT*
+// environment variables.
+func (n *Notifier) run(ctx context.Context, command string, data config.NotificationTemplateData) error {
+	rendered, err := tmpl.Render(command, data)
+	if err != nil {
+		return fmt.Errorf("render command: %w", err)
+	}
+
+	ctx = executil.WithEnv(ctx, []string{
+		"HIVE_EVENT=" + data.JSON,
+		"HIVE_EVENT_TYPE=" + data.Type,
+		"HIVE_SESSION_ID=" + data.SessionID,
+	})
+
+	var out bytes.Buffer
+	if err := n.executor.RunStream(ctx, &out, &out, "sh", "-c", rendered); err != nil {
+		return fmt.Errorf("command %q: %w: %s", rendered, err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// post sends the rendered payload, or the event JSON, to a webhook.
+func (n *Notifier) post(ctx context.Context, cfg config.Notification, data config.NotificationTemplateData) error {
+	body := data.JSON
+	if cfg.Payload != "" {
+		rendered, err := tmpl.Render(cfg.Payload, data)
+		if err != nil {
+			return fmt.Errorf("render payload: %w", err)
+		}
+		body = rendered
+	}
+
+	headers, err := n.secrets.Resolve(ctx, cfg.Headers)
+	if err != nil {
+		return fmt.Errorf("resolve headers: %w", err)
+	}
+
+	return n.send(ctx, "webhook "+cfg.Webhook, cfg.Webhook, body, headers)
+}
+
+// chatMessageLimit is the longest message Discord accepts, in characters.
+// Slack allows more, but a notification longer than this is not read.
+const chatMessageLimit = 2000
+
+// postChat sends the rendered message, or a summary of the event, to a Slack
+// or Discord webhook. The URL holds the webhook's credentials, so errors
+// name the service instead.
+func (n *Notifier) postChat(ctx context.Context, cfg config.Notification, data config.NotificationTemplateData) error {
+	chat, target := cfg.Chat()
+
+	text := chatMessage(data)
+	if cfg.Message != "" {
+		rendered, err := tmpl.Render(cfg.Message, data)
+		if err != nil {
+			return fmt.Errorf("render message: %w", err)
+		}
+		text = rendered
+	}
+	if runes := []rune(text); len(runes) > chatMessageLimit {
+		text = string(runes[:chatMessageLimit-1]) + "…"
+	}
+
+	field := "text"
+	if chat == config.ChatDiscord {
+		field = "content"
+	}
+	body, err := json.Marshal(map[string]string{field: text})
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	webhook, err := n.secrets.Value(ctx, target)
+	if err != nil {
+		return fmt.Errorf("resolve %s webhook: %w", chat, err)
+	}
+	return n.send(ctx, chat+" webhook", webhook, string(body), nil)
+}
+
+// chatMessage summarizes an event for a chat channel.
+func chatMessage(data config.NotificationTemplateData) string {
+	name := data.Name
+	if name == "" {
+		name = data.SessionID
+	}
+
+	switch {
+	case data.Type == events.BatchCompleted:
+		return fmt.Sprintf("hive: batch %v completed: %v created, %v failed, %v skipped",
+			data.Data["batch_id"], data.Data["created"], data.Data["failed"], data.Data["skipped"])
+	case data.Type == events.SessionStatus && data.Data["to"] == "approval":
+		return fmt.Sprintf("hive: %s needs approval", name)
+	case data.Type == events.SessionStatus:
+		return fmt.Sprintf("hive: %s is %v", name, data.Data["to"])
+	case data.Type == events.MessagePublished:
+		from, _ := data.Data["sender"].(string)
+		if from == "" {
+			from = data.SessionID
+		}
+		header := fmt.Sprintf("hive: message on %v", data.Data["topic"])
+		if from != "" {
+			header += " from " + from
+		}
+		return fmt.Sprintf("%s\n%v", header, data.Data["payload"])
+	case name != "":
+		return fmt.Sprintf("hive: %s %s", data.Type, name)
+	default:
+		return "hive: " + data.Type
+	}
+}
+
+// send POSTs a JSON body to target with extra headers given as name=value.
+// label names the target in errors, which otherwise leave out the URL.
+func (n *Notifier) send(ctx context.Context, label, target, body string, headers []string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: invalid url", label)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, h := range headers {
+		name, value, _ := strings.Cut(h, "=")
+		req.Header.Set(name, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%s: %w", label, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s: unexpected status %s", label, resp.Status)
+	}
+	return nil
+}
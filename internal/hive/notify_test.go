@@ -0,0 +1,156 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/events"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifier_Command(t *testing.T) {
+	exec := &executil.RecordingExecutor{}
+	n := NewNotifier(zerolog.Nop(), exec, NewSecretResolver(zerolog.Nop(), exec, ""), []config.Notification{
+		{Events: []string{"session.*"}, Command: "notify-send {{ .Name | shq }}"},
+		{Events: []string{"prune.completed"}, Command: "echo never"},
+	})
+
+	n.Notify(context.Background(), events.Event{
+		Time:      time.Now(),
+		Type:      events.SessionCreated,
+		SessionID: "abc",
+		Data:      map[string]any{"name": "feature"},
+	})
+
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"-c", "notify-send 'feature'"}, exec.Commands[0].Args)
+	assert.Contains(t, exec.Commands[0].Env, "HIVE_EVENT_TYPE=session.created")
+	assert.Contains(t, exec.Commands[0].Env, "HIVE_SESSION_ID=abc")
+}
+
+func TestNotifier_StatusAlias(t *testing.T) {
+	exec := &executil.RecordingExecutor{}
+	n := NewNotifier(zerolog.Nop(), exec, NewSecretResolver(zerolog.Nop(), exec, ""), []config.Notification{
+		{Events: []string{"status.approval"}, Command: "echo {{ .SessionID }}"},
+	})
+
+	n.Notify(context.Background(), events.Event{Type: events.SessionStatus, SessionID: "a", Data: map[string]any{"from": "active", "to": "ready"}})
+	assert.Empty(t, exec.Commands)
+
+	n.Notify(context.Background(), events.Event{Type: events.SessionStatus, SessionID: "b", Data: map[string]any{"from": "active", "to": "approval"}})
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"-c", "echo b"}, exec.Commands[0].Args)
+}
+
+func TestNotifier_Webhook(t *testing.T) {
+	t.Setenv("HIVE_TEST_WEBHOOK_TOKEN", "t0ken")
+
+	type request struct {
+		auth string
+		body string
+	}
+	got := make(chan request, 2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- request{auth: r.Header.Get("Authorization"), body: string(body)}
+	}))
+	defer srv.Close()
+
+	exec := &executil.RecordingExecutor{}
+	n := NewNotifier(zerolog.Nop(), exec, NewSecretResolver(zerolog.Nop(), exec, ""), []config.Notification{
+		{Events: []string{"batch.completed"}, Webhook: srv.URL},
+		{
+			Events:  []string{"batch.completed"},
+			Webhook: srv.URL,
+			Payload: `{"text": "batch {{ index .Data "batch_id" }} done"}`,
+			Headers: map[string]config.SecretValue{"Authorization": {Kind: config.SecretEnv, Value: "HIVE_TEST_WEBHOOK_TOKEN"}},
+		},
+	})
+
+	n.Notify(context.Background(), events.Event{Type: events.BatchCompleted, Data: map[string]any{"batch_id": "b1"}})
+
+	var bodies []string
+	for range 2 {
+		req := <-got
+		if req.auth != "" {
+			assert.Equal(t, "t0ken", req.auth)
+		}
+		bodies = append(bodies, req.body)
+	}
+	assert.Contains(t, bodies, `{"text": "batch b1 done"}`)
+
+	// The default payload is the event itself
+	for _, body := range bodies {
+		if body == `{"text": "batch b1 done"}` {
+			continue
+		}
+		var e events.Event
+		require.NoError(t, json.Unmarshal([]byte(body), &e))
+		assert.Equal(t, events.BatchCompleted, e.Type)
+		assert.Equal(t, "b1", e.Data["batch_id"])
+	}
+}
+
+func TestNotifier_Chat(t *testing.T) {
+	got := make(chan map[string]string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got <- body
+	}))
+	defer srv.Close()
+	t.Setenv("HIVE_TEST_SLACK_WEBHOOK", srv.URL)
+
+	exec := &executil.RecordingExecutor{}
+	n := NewNotifier(zerolog.Nop(), exec, NewSecretResolver(zerolog.Nop(), exec, ""), []config.Notification{
+		{Events: []string{"status.approval"}, Slack: config.SecretValue{Kind: config.SecretEnv, Value: "HIVE_TEST_SLACK_WEBHOOK"}},
+		{Events: []string{"message.published"}, Topics: []string{"agent.*.inbox"}, Discord: config.SecretValue{Value: srv.URL}, Message: `{{ index .Data "topic" }}: {{ index .Data "payload" }}`},
+	})
+
+	ctx := context.Background()
+	n.Notify(ctx, events.Event{Type: events.SessionStatus, SessionID: "abc", Data: map[string]any{"from": "active", "to": "approval"}})
+	assert.Equal(t, map[string]string{"text": "hive: abc needs approval"}, <-got)
+
+	n.Notify(ctx, events.Event{Type: events.MessagePublished, Data: map[string]any{"topic": "build.done", "payload": "ignored"}})
+	n.Notify(ctx, events.Event{Type: events.MessagePublished, Data: map[string]any{"topic": "agent.abc.inbox", "payload": `Review "auth"`}})
+	assert.Equal(t, map[string]string{"content": `agent.abc.inbox: Review "auth"`}, <-got, "messages on other topics are not posted")
+	assert.Empty(t, got)
+}
+
+func TestChatMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		data config.NotificationTemplateData
+		want string
+	}{
+		{
+			name: "batch",
+			data: config.NotificationTemplateData{Type: events.BatchCompleted, Data: map[string]any{"batch_id": "b1", "created": 3, "failed": 1, "skipped": 0}},
+			want: "hive: batch b1 completed: 3 created, 1 failed, 0 skipped",
+		},
+		{
+			name: "message",
+			data: config.NotificationTemplateData{Type: events.MessagePublished, SessionID: "abc", Data: map[string]any{"topic": "agent.def.inbox", "payload": "ready for review"}},
+			want: "hive: message on agent.def.inbox from abc\nready for review",
+		},
+		{
+			name: "session",
+			data: config.NotificationTemplateData{Type: events.SessionCreated, Name: "fix-auth"},
+			want: "hive: session.created fix-auth",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, chatMessage(tt.data))
+		})
+	}
+}
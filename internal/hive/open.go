@@ -0,0 +1,130 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// OpenData is the template context for commands.open.
+type OpenData struct {
+	ID     string // Unique session identifier
+	Name   string // Session name
+	Path   string // Absolute path to the session's working directory
+	Root   string // Absolute path to the repository root
+	Remote string // Git remote URL
+}
+
+// OpenCommand returns the shell command that opens a session's directory.
+// commands.open is rendered if set; otherwise the directory is opened in
+// $VISUAL or $EDITOR, then VS Code, then the system file manager.
+func (s *Service) OpenCommand(ctx context.Context, ref string) (string, error) {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+	if _, err := os.Stat(sess.WorkDir()); err != nil {
+		return "", fmt.Errorf("session directory: %w", err)
+	}
+
+	if s.cfg().Commands.Open == "" {
+		return defaultOpener(sess.WorkDir()), nil
+	}
+
+	rendered, err := tmpl.Render(s.cfg().Commands.Open, OpenData{
+		ID:     sess.ID,
+		Name:   sess.Name,
+		Path:   sess.WorkDir(),
+		Root:   sess.Path,
+		Remote: sess.Remote,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render open command: %w", err)
+	}
+	return rendered, nil
+}
+
+// URLData is the template context for the url of open_url keybindings.
+type URLData struct {
+	ID            string // Unique session identifier
+	Name          string // Session name
+	Remote        string // Git remote URL
+	Owner         string // Repository owner, e.g. hay-kot
+	Repo          string // Repository name, e.g. hive
+	OwnerRepo     string // Owner and name, e.g. hay-kot/hive
+	Branch        string // Branch checked out in the session
+	DefaultBranch string // Default branch of the repository
+}
+
+// SessionURL renders urlTemplate for a session, e.g. a link to its branch
+// comparison or pull request. The default branch falls back to "main" when
+// it cannot be determined.
+func (s *Service) SessionURL(ctx context.Context, ref, urlTemplate string) (string, error) {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+
+	branch, err := s.git.Branch(ctx, sess.Path)
+	if err != nil {
+		return "", fmt.Errorf("get branch: %w", err)
+	}
+	defaultBranch, err := s.git.DefaultBranch(ctx, sess.Path)
+	if err != nil {
+		defaultBranch = "main"
+	}
+
+	owner, repo := git.ExtractOwnerRepo(sess.Remote)
+	rendered, err := tmpl.Render(urlTemplate, URLData{
+		ID:            sess.ID,
+		Name:          sess.Name,
+		Remote:        sess.Remote,
+		Owner:         owner,
+		Repo:          repo,
+		OwnerRepo:     owner + "/" + repo,
+		Branch:        branch,
+		DefaultBranch: defaultBranch,
+	})
+	if err != nil {
+		return "", fmt.Errorf("render url: %w", err)
+	}
+	return rendered, nil
+}
+
+// BrowserCommand returns the shell command that opens url in $BROWSER, or
+// the system opener if it is not set.
+func BrowserCommand(url string) string {
+	opener := os.Getenv("BROWSER")
+	if opener == "" {
+		opener = "xdg-open"
+		if runtime.GOOS == "darwin" {
+			opener = "open"
+		}
+	}
+	return opener + " " + tmpl.ShellQuote(url)
+}
+
+// defaultOpener picks an opener for path when commands.open is not set.
+func defaultOpener(path string) string {
+	opener := os.Getenv("VISUAL")
+	if opener == "" {
+		opener = os.Getenv("EDITOR")
+	}
+	if opener == "" {
+		if _, err := exec.LookPath("code"); err == nil {
+			opener = "code"
+		}
+	}
+	if opener == "" {
+		opener = "xdg-open"
+		if runtime.GOOS == "darwin" {
+			opener = "open"
+		}
+	}
+	return opener + " " + tmpl.ShellQuote(path)
+}
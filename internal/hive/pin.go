@@ -0,0 +1,31 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// TogglePin pins or unpins a session, which the TUI keeps at the top of its
+// repository. It returns the saved session.
+func (s *Service) TogglePin(ctx context.Context, ref string) (session.Session, error) {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("get session: %w", err)
+	}
+
+	if sess.IsPinned() {
+		delete(sess.Metadata, session.MetaPinned)
+	} else {
+		sess.SetMeta(session.MetaPinned, "true")
+	}
+	sess.UpdatedAt = time.Now()
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return session.Session{}, fmt.Errorf("save session: %w", err)
+	}
+
+	s.log.Debug().Str("session_id", sess.ID).Bool("pinned", sess.IsPinned()).Msg("toggled pin")
+	return sess, nil
+}
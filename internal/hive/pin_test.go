@@ -0,0 +1,30 @@
+package hive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTogglePin(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	svc := newTestService(t, store, nil)
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "task", State: session.StateActive}
+
+	sess, err := svc.TogglePin(ctx, "task")
+	require.NoError(t, err)
+	assert.True(t, sess.IsPinned())
+	saved := store.sessions["abc"]
+	assert.True(t, saved.IsPinned())
+
+	sess, err = svc.TogglePin(ctx, "abc")
+	require.NoError(t, err)
+	assert.False(t, sess.IsPinned())
+	saved = store.sessions["abc"]
+	assert.False(t, saved.IsPinned())
+	assert.NotContains(t, saved.Metadata, session.MetaPinned)
+}
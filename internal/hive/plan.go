@@ -0,0 +1,317 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/core/validate"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// Plan actions describe how a session would be created.
+const (
+	PlanClone   = "clone"
+	PlanRecycle = "recycle"
+)
+
+// SessionPlan describes what CreateSession would do for a single session.
+type SessionPlan struct {
+	Name        string     `json:"name"`
+	SessionID   string     `json:"session_id,omitempty"`
+	Remote      string     `json:"remote,omitempty"`
+	Action      string     `json:"action,omitempty"`
+	RecycleFrom string     `json:"recycle_from,omitempty"` // ID of the recycled session that would be reused
+	Path        string     `json:"path,omitempty"`
+	Prompt      string     `json:"prompt,omitempty"` // Prompt the agent receives, with rule preambles
+	Rules       []RulePlan `json:"rules,omitempty"`
+	Spawn       []string   `json:"spawn,omitempty"` // Rendered spawn commands
+	Error       string     `json:"error,omitempty"`
+}
+
+// RulePlan describes a matched rule and what it would do.
+type RulePlan struct {
+	Pattern  string   `json:"pattern"`
+	Copy     []string `json:"copy,omitempty"`
+	Commands []string `json:"commands,omitempty"`
+	// PostCreate hooks run after every rule's copy and commands.
+	PostCreate []string `json:"post_create,omitempty"`
+	// Background is set when Commands and PostCreate start detached after spawn.
+	Background bool `json:"background,omitempty"`
+}
+
+// PlanSessions resolves remotes, recyclable candidates, matching rules, and
+// spawn commands for each session without cloning, copying, or running
+// anything. Recyclable sessions are assigned in order, so the plan reflects
+// which sessions would share the recycle pool. Per-session problems are
+// reported in SessionPlan.Error rather than failing the whole plan.
+func (s *Service) PlanSessions(ctx context.Context, opts []CreateOptions) ([]SessionPlan, error) {
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	used := make(map[string]bool)
+	plans := make([]SessionPlan, len(opts))
+
+	for i, opt := range opts {
+		opt.Name = validate.NormalizeSessionName(opt.Name)
+		plan := SessionPlan{Name: opt.Name}
+		if err := validate.SessionName(opt.Name); err != nil {
+			plan.Error = err.Error()
+			plans[i] = plan
+			continue
+		}
+
+		remote, err := s.ResolveRemote(ctx, opt.Remote)
+		if err != nil {
+			plan.Error = err.Error()
+			plans[i] = plan
+			continue
+		}
+		plan.Remote = remote
+		if !opt.PromptPreambled {
+			opt.Prompt = s.PromptFor(remote, opt.Prompt)
+		}
+		plan.Prompt = opt.Prompt
+
+		slug := session.Slugify(opt.Name)
+
+		if recyclable := s.planRecyclable(ctx, sessions, remote, used); recyclable != nil {
+			used[recyclable.ID] = true
+			plan.Action = PlanRecycle
+			plan.RecycleFrom = recyclable.ID
+			plan.SessionID = recyclable.ID
+		} else {
+			plan.Action = PlanClone
+			plan.SessionID = opt.SessionID
+			if plan.SessionID == "" {
+				plan.SessionID = "<generated>"
+			}
+		}
+		plan.Path, err = s.sessionDir(remote, slug, plan.SessionID)
+		if err != nil {
+			plan.Error = err.Error()
+			plans[i] = plan
+			continue
+		}
+
+		subdir, err := cleanSubdir(opt.Subdir)
+		if err != nil {
+			plan.Error = err.Error()
+			plans[i] = plan
+			continue
+		}
+		planned := session.Session{ID: plan.SessionID, Name: opt.Name, Slug: slug, Path: plan.Path, Remote: remote}
+		if subdir != "" {
+			planned.SetMeta(session.MetaSubdir, subdir)
+		}
+
+		rules, err := s.planRules(remote, opt.Source, s.hookData(planned, opt.Prompt))
+		plan.Rules = rules
+		if err != nil {
+			plan.Error = err.Error()
+			plans[i] = plan
+			continue
+		}
+
+		spawn, err := s.planSpawn(opt, plan.Path, slug, remote)
+		if err != nil {
+			plan.Error = err.Error()
+		}
+		plan.Spawn = spawn
+
+		plans[i] = plan
+	}
+
+	return plans, nil
+}
+
+// planRecyclable returns the valid recycled session for remote that is not
+// already assigned and that CreateSession would pick. Unlike
+// findValidRecyclable it never marks sessions corrupted or claims them.
+func (s *Service) planRecyclable(ctx context.Context, sessions []session.Session, remote string, used map[string]bool) *session.Session {
+	s.claimMu.Lock()
+	var candidates []*session.Session
+	for i := range sessions {
+		sess := &sessions[i]
+		if sess.State != session.StateRecycled || sess.Remote != remote || used[sess.ID] {
+			continue
+		}
+		if _, claimed := s.claimed[sess.ID]; claimed {
+			continue
+		}
+		candidates = append(candidates, sess)
+	}
+	s.claimMu.Unlock()
+
+	valid := s.validateRecyclable(ctx, candidates, func(sess *session.Session, err error) {
+		s.log.Debug().Err(err).Str("session_id", sess.ID).Msg("plan: skipping corrupted session")
+	})
+	if len(valid) == 0 {
+		return nil
+	}
+	return valid[0]
+}
+
+// planRules returns the rules that match remote, with their commands and
+// post_create hooks rendered with data. Copy patterns are only reported when
+// a source directory is available, mirroring executeRules. On a render error
+// the rules planned so far are returned with it.
+func (s *Service) planRules(remote, source string, data HookData) ([]RulePlan, error) {
+	var plans []RulePlan
+	for _, rule := range s.cfg().Rules {
+		matched, err := matchRemotePattern(rule.Pattern, remote)
+		if err != nil {
+			return nil, fmt.Errorf("match pattern %q: %w", rule.Pattern, err)
+		}
+		if !matched {
+			continue
+		}
+
+		rp := RulePlan{Pattern: rule.Pattern, Background: rule.Background}
+		if rp.Commands, err = renderAll(rule.Commands, data); err != nil {
+			return plans, fmt.Errorf("render rule command: %w", err)
+		}
+		if rp.PostCreate, err = renderAll(rule.Hooks.PostCreate, data); err != nil {
+			return plans, fmt.Errorf("render post_create hook: %w", err)
+		}
+		if source != "" {
+			rp.Copy = copyLabels(rule.Copy)
+		}
+		if len(rp.Copy) == 0 && len(rp.Commands) == 0 && len(rp.PostCreate) == 0 {
+			continue
+		}
+		plans = append(plans, rp)
+	}
+	return plans, nil
+}
+
+// planSpawn renders the spawn commands CreateSession would run. opt.Prompt
+// must already include the rules' preambles.
+func (s *Service) planSpawn(opt CreateOptions, path, slug, remote string) ([]string, error) {
+	commands, err := s.spawnCommands(opt, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, repoName := git.ExtractOwnerRepo(remote)
+	data := SpawnData{
+		Path:       path,
+		Name:       opt.Name,
+		Prompt:     opt.Prompt,
+		Slug:       slug,
+		ContextDir: s.cfg().RepoContextDir(owner, repoName),
+		Owner:      owner,
+		Repo:       repoName,
+	}
+
+	rendered, err := renderAll(commands, data)
+	if err != nil {
+		return rendered, fmt.Errorf("render spawn command: %w", err)
+	}
+	return rendered, nil
+}
+
+// RecyclePlan describes what RecycleSession would do for a session.
+type RecyclePlan struct {
+	SessionID   string   `json:"session_id"`
+	Name        string   `json:"name"`
+	Path        string   `json:"path"`
+	RecyclePath string   `json:"recycle_path"`           // directory the session would be renamed to
+	PreRecycle  []string `json:"pre_recycle,omitempty"`  // Rendered pre_recycle hooks
+	Commands    []string `json:"commands,omitempty"`     // Rendered recycle commands
+	PostRecycle []string `json:"post_recycle,omitempty"` // Rendered post_recycle hooks
+}
+
+// PlanRecycle renders the hooks and recycle commands RecycleSession would
+// run for a session, without running them or touching the directory.
+func (s *Service) PlanRecycle(ctx context.Context, id string) (RecyclePlan, error) {
+	sess, err := s.sessions.Get(ctx, id)
+	if err != nil {
+		return RecyclePlan{}, fmt.Errorf("get session: %w", err)
+	}
+
+	if !sess.CanRecycle() {
+		return RecyclePlan{}, fmt.Errorf("session %s cannot be recycled (state: %s)", id, sess.State)
+	}
+
+	defaultBranch, err := s.git.DefaultBranch(ctx, sess.Path)
+	if err != nil {
+		s.log.Debug().Err(err).Msg("plan: failed to get default branch, using 'main'")
+		defaultBranch = "main"
+	}
+
+	repoName := git.ExtractRepoName(sess.Remote)
+	plan := RecyclePlan{
+		SessionID:   sess.ID,
+		Name:        sess.Name,
+		Path:        sess.Path,
+		RecyclePath: filepath.Join(s.cfg().ReposDir(), repoName+"-recycle-<generated>"),
+	}
+
+	data := s.hookData(sess, "")
+	if plan.PreRecycle, err = s.planHooks(config.HookPreRecycle, data); err != nil {
+		return plan, err
+	}
+
+	plan.Commands, err = renderAll(s.cfg().Commands.Recycle, RecycleData{
+		DefaultBranch: defaultBranch,
+		Path:          sess.Path,
+		ID:            sess.ID,
+		Name:          sess.Name,
+		Remote:        sess.Remote,
+	})
+	if err != nil {
+		return plan, fmt.Errorf("render recycle command: %w", err)
+	}
+
+	data.Path = plan.RecyclePath
+	if plan.PostRecycle, err = s.planHooks(config.HookPostRecycle, data); err != nil {
+		return plan, err
+	}
+
+	return plan, nil
+}
+
+// planHooks renders the lifecycle hooks of every rule matching data.Remote.
+func (s *Service) planHooks(event string, data HookData) ([]string, error) {
+	var out []string
+	for _, rule := range s.cfg().Rules {
+		commands := rule.Hooks.For(event)
+		if len(commands) == 0 {
+			continue
+		}
+
+		matched, err := matchRemotePattern(rule.Pattern, data.Remote)
+		if err != nil {
+			return nil, fmt.Errorf("match pattern %q: %w", rule.Pattern, err)
+		}
+		if !matched {
+			continue
+		}
+
+		rendered, err := renderAll(commands, data)
+		if err != nil {
+			return nil, fmt.Errorf("render %s hook: %w", event, err)
+		}
+		out = append(out, rendered...)
+	}
+	return out, nil
+}
+
+// renderAll renders each command template with data.
+func renderAll(commands []string, data any) ([]string, error) {
+	rendered := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		out, err := tmpl.Render(cmd, data)
+		if err != nil {
+			return rendered, fmt.Errorf("%q: %w", cmd, err)
+		}
+		rendered = append(rendered, out)
+	}
+	return rendered, nil
+}
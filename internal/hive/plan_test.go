@@ -0,0 +1,123 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSessions(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	store := newMockStore()
+	store.sessions["rec1"] = session.Session{ID: "rec1", Remote: remote, State: session.StateRecycled, Path: t.TempDir()}
+
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Commands: config.Commands{
+			Spawn:      []string{"spawn {{ .Name }}"},
+			BatchSpawn: []string{"batch {{ .Name }} {{ .Prompt }}"},
+		},
+		Rules: []config.Rule{
+			{Pattern: ".*hive.*", Commands: []string{"make setup"}, Copy: copyGlobs(".env")},
+			{Pattern: ".*hive.*", Commands: []string{"echo {{ .Name }} {{ .Repo }}"}, Hooks: config.RuleHooks{PostCreate: []string{"cd {{ .Path }}"}}},
+			{Pattern: ".*other.*", Commands: []string{"never"}},
+		},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	plans, err := svc.PlanSessions(context.Background(), []CreateOptions{
+		{Name: "first", Remote: remote, Prompt: "p1", Source: "/src", UseBatchSpawn: true},
+		{Name: "second", Remote: remote, SessionID: "abc123", UseBatchSpawn: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+
+	first := plans[0]
+	assert.Equal(t, PlanRecycle, first.Action)
+	assert.Equal(t, "rec1", first.RecycleFrom)
+	assert.Equal(t, []string{"batch first p1"}, first.Spawn)
+	require.Len(t, first.Rules, 2)
+	assert.Equal(t, []string{".env"}, first.Rules[0].Copy)
+	assert.Equal(t, []string{"make setup"}, first.Rules[0].Commands)
+	assert.Equal(t, []string{"echo first hive"}, first.Rules[1].Commands, "commands are rendered")
+	assert.Equal(t, []string{"cd " + first.Path}, first.Rules[1].PostCreate)
+
+	// The only recycled session is taken, so the second plan clones
+	second := plans[1]
+	assert.Equal(t, PlanClone, second.Action)
+	assert.Equal(t, "abc123", second.SessionID)
+	assert.Contains(t, second.Path, "hive-second-abc123")
+	require.Len(t, second.Rules, 2)
+	assert.Empty(t, second.Rules[0].Copy, "copy is skipped without a source")
+
+	assert.Empty(t, exec.Commands, "planning must not execute commands")
+	assert.Equal(t, session.StateRecycled, store.sessions["rec1"].State)
+}
+
+func TestPlanSessions_RenderError(t *testing.T) {
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"spawn {{ .Missing }}"}},
+	}
+	svc := newTestService(t, newMockStore(), cfg)
+
+	plans, err := svc.PlanSessions(context.Background(), []CreateOptions{
+		{Name: "bad", Remote: "https://github.com/hay-kot/hive.git"},
+	})
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+	assert.Contains(t, plans[0].Error, "render spawn command")
+
+	cfg.Commands.Spawn = nil
+	cfg.Rules = []config.Rule{{Hooks: config.RuleHooks{PostCreate: []string{"run {{ .Missing }}"}}}}
+	plans, err = svc.PlanSessions(context.Background(), []CreateOptions{
+		{Name: "bad", Remote: "https://github.com/hay-kot/hive.git"},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, plans[0].Error, "render post_create hook")
+}
+
+func TestPlanRecycle(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	store := newMockStore()
+	store.sessions["abc123"] = session.Session{ID: "abc123", Name: "fix", Remote: remote, State: session.StateActive, Path: "/repos/hive-fix-abc123"}
+	store.sessions["rec1"] = session.Session{ID: "rec1", Remote: remote, State: session.StateRecycled}
+
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Recycle: []string{"git checkout {{ .DefaultBranch }}", "archive {{ .ID }}"}},
+		Rules: []config.Rule{
+			{Hooks: config.RuleHooks{PreRecycle: []string{"down {{ .Name }}"}, PostRecycle: []string{"ls {{ .Path }}"}}},
+			{Pattern: "gitlab", Hooks: config.RuleHooks{PreRecycle: []string{"never"}}},
+		},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	plan, err := svc.PlanRecycle(context.Background(), "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/hive-fix-abc123", plan.Path)
+	assert.Contains(t, plan.RecyclePath, "hive-recycle-")
+	assert.Equal(t, []string{"down fix"}, plan.PreRecycle)
+	assert.Equal(t, []string{"git checkout main", "archive abc123"}, plan.Commands)
+	assert.Equal(t, []string{"ls " + plan.RecyclePath}, plan.PostRecycle)
+
+	assert.Empty(t, exec.Commands, "planning must not execute commands")
+	assert.Equal(t, session.StateActive, store.sessions["abc123"].State)
+
+	_, err = svc.PlanRecycle(context.Background(), "rec1")
+	require.ErrorContains(t, err, "cannot be recycled")
+}
@@ -0,0 +1,186 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// Reasons a session is pruned.
+const (
+	PruneCorrupted   = "corrupted"
+	PruneAll         = "all"
+	PruneMaxRecycled = "max_recycled"
+	PruneOlderThan   = "older_than"
+)
+
+// PruneOptions selects the sessions Prune removes.
+type PruneOptions struct {
+	All       bool          // prune every recycled session, ignoring max_recycled
+	Repo      string        // only sessions of this repository (owner/name or name)
+	OlderThan time.Duration // also prune recycled sessions not updated within this duration
+	DryRun    bool          // report what would be pruned without deleting anything
+}
+
+// PrunedSession is a session removed, or that would be removed, by Prune.
+type PrunedSession struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Remote string        `json:"remote"`
+	Path   string        `json:"path"`
+	State  session.State `json:"state"`
+	Reason string        `json:"reason"`
+	Bytes  int64         `json:"bytes"` // disk usage of the session directory
+	Error  string        `json:"error,omitempty"`
+}
+
+// PruneResult lists the sessions Prune removed, or would remove in a dry
+// run, and the disk space reclaimed.
+type PruneResult struct {
+	Sessions  []PrunedSession `json:"sessions"`
+	Reclaimed int64           `json:"reclaimed_bytes"`
+	DryRun    bool            `json:"dry_run"`
+}
+
+// Pruned returns the number of sessions removed without error, which is
+// zero for a dry run.
+func (r PruneResult) Pruned() int {
+	if r.DryRun {
+		return 0
+	}
+	n := 0
+	for _, p := range r.Sessions {
+		if p.Error == "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Prune removes corrupted sessions and recycled sessions beyond the
+// max_recycled limit of their repository (keeping the newest), along with
+// their directories. opts.All prunes every recycled session and
+// opts.OlderThan additionally prunes recycled sessions idle that long.
+// Failures to delete a session are recorded in its Error field.
+func (s *Service) Prune(ctx context.Context, opts PruneOptions) (PruneResult, error) {
+	s.log.Info().Bool("all", opts.All).Str("repo", opts.Repo).Dur("older_than", opts.OlderThan).Bool("dry_run", opts.DryRun).Msg("pruning sessions")
+
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return PruneResult{}, fmt.Errorf("list sessions: %w", err)
+	}
+
+	candidates := s.pruneCandidates(sessions, opts)
+	if !opts.DryRun && len(candidates) > 0 {
+		s.AutoBackup(ctx, BackupPrune)
+	}
+
+	result := PruneResult{Sessions: []PrunedSession{}, DryRun: opts.DryRun}
+	for _, candidate := range candidates {
+		candidate.Bytes = dirSize(candidate.Path)
+
+		if !opts.DryRun {
+			if err := s.DeleteSession(ctx, candidate.ID); err != nil {
+				s.log.Warn().Err(err).Str("session_id", candidate.ID).Msg("failed to prune session")
+				candidate.Error = err.Error()
+				candidate.Bytes = 0
+			}
+		}
+
+		result.Reclaimed += candidate.Bytes
+		result.Sessions = append(result.Sessions, candidate)
+	}
+
+	s.log.Info().Int("count", result.Pruned()).Int64("reclaimed", result.Reclaimed).Msg("prune complete")
+	if !opts.DryRun {
+		s.Emit(events.PruneCompleted, "", map[string]any{"pruned": result.Pruned(), "reclaimed_bytes": result.Reclaimed})
+	}
+
+	return result, nil
+}
+
+// pruneCandidates selects the sessions to prune, each with the first reason
+// that applies.
+func (s *Service) pruneCandidates(sessions []session.Session, opts PruneOptions) []PrunedSession {
+	var (
+		candidates []PrunedSession
+		byRemote   = make(map[string][]session.Session)
+		cutoff     = time.Now().Add(-opts.OlderThan)
+	)
+
+	add := func(sess session.Session, reason string) {
+		candidates = append(candidates, PrunedSession{
+			ID:     sess.ID,
+			Name:   sess.Name,
+			Remote: sess.Remote,
+			Path:   sess.Path,
+			State:  sess.State,
+			Reason: reason,
+		})
+	}
+
+	for _, sess := range sessions {
+		if opts.Repo != "" && !git.MatchesRepo(sess.Remote, opts.Repo) {
+			continue
+		}
+
+		switch {
+		case sess.State == session.StateCorrupted:
+			add(sess, PruneCorrupted)
+		case sess.State != session.StateRecycled:
+		case opts.All:
+			add(sess, PruneAll)
+		case opts.OlderThan > 0 && sess.UpdatedAt.Before(cutoff):
+			add(sess, PruneOlderThan)
+		default:
+			byRemote[sess.Remote] = append(byRemote[sess.Remote], sess)
+		}
+	}
+
+	// The remaining recycled sessions are held to max_recycled
+	for remote, recycled := range byRemote {
+		limit := s.cfg().GetMaxRecycled(remote)
+		if limit == 0 || len(recycled) <= limit {
+			continue
+		}
+
+		// Sort by UpdatedAt descending (newest first)
+		sort.Slice(recycled, func(i, j int) bool {
+			return recycled[i].UpdatedAt.After(recycled[j].UpdatedAt)
+		})
+
+		for _, sess := range recycled[limit:] {
+			add(sess, PruneMaxRecycled)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Remote < candidates[j].Remote
+	})
+	return candidates
+}
+
+// dirSize returns the total size of the regular files under path, or 0 if
+// it cannot be read.
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/hay-kot/hive/pkg/executil"
 	"github.com/hay-kot/hive/pkg/tmpl"
@@ -13,19 +14,26 @@ import (
 // RecycleData contains template data for recycle commands.
 type RecycleData struct {
 	DefaultBranch string
+	Path          string // session directory, before it is renamed
+	ID            string
+	Name          string
+	Remote        string
 }
 
 // Recycler handles resetting a session environment for reuse.
 type Recycler struct {
 	log      zerolog.Logger
 	executor executil.Executor
+	retrier  *GitRetrier
 }
 
-// NewRecycler creates a new Recycler.
-func NewRecycler(log zerolog.Logger, executor executil.Executor) *Recycler {
+// NewRecycler creates a new Recycler. Recycle commands that are a git
+// clone, fetch, pull, or push are retried through retrier.
+func NewRecycler(log zerolog.Logger, executor executil.Executor, retrier *GitRetrier) *Recycler {
 	return &Recycler{
 		log:      log,
 		executor: executor,
+		retrier:  retrier,
 	}
 }
 
@@ -38,6 +46,8 @@ func (r *Recycler) Recycle(ctx context.Context, path string, commands []string,
 	if w == nil {
 		w = io.Discard
 	}
+	record := commandLogFrom(ctx)
+	w = record.tee(w)
 
 	for _, cmd := range commands {
 		rendered, err := tmpl.Render(cmd, data)
@@ -47,7 +57,20 @@ func (r *Recycler) Recycle(ctx context.Context, path string, commands []string,
 
 		r.log.Debug().Str("command", rendered).Msg("executing recycle command")
 
-		if err := r.executor.RunDirStream(ctx, path, w, w, "sh", "-c", rendered); err != nil {
+		run := func() error {
+			record.start("recycle", rendered)
+			begin := time.Now()
+			err := r.executor.RunDirStream(ctx, path, w, w, "sh", "-c", rendered)
+			record.finish(begin, err)
+			return err
+		}
+		if isNetworkGitCommand(rendered) {
+			err = r.retrier.Do(ctx, w, rendered, run)
+		} else {
+			err = run()
+		}
+
+		if err != nil {
 			return fmt.Errorf("execute recycle command %q: %w", rendered, err)
 		}
 	}
@@ -0,0 +1,58 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// ResumeSession relaunches the AI tool of an active session with its stored
+// prompt, for example after a terminal crash. commands.resume is run if set,
+// otherwise the commands the session was spawned with; a non-empty profile
+// overrides both. If no tool session is linked yet, the newest Claude Code
+// transcript for the session is linked first, so {{ .ToolSession }} can
+// resume the conversation.
+func (s *Service) ResumeSession(ctx context.Context, ref, profile string) (session.Session, error) {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("get session: %w", err)
+	}
+	if sess.State != session.StateActive {
+		return session.Session{}, fmt.Errorf("session %s is %s, not active", sess.ID, sess.State)
+	}
+	if _, err := os.Stat(sess.WorkDir()); err != nil {
+		return session.Session{}, fmt.Errorf("session directory: %w", err)
+	}
+
+	if sess.GetMeta(session.MetaToolSession) == "" {
+		tool, err := DetectToolSession(sess.WorkDir())
+		if err != nil {
+			s.log.Debug().Err(err).Str("session_id", sess.ID).Msg("could not detect tool session")
+		}
+		if tool != "" {
+			if sess, err = s.LinkToolSession(ctx, sess.ID, tool); err != nil {
+				return session.Session{}, err
+			}
+		}
+	}
+
+	commands := s.cfg().Commands.Resume
+	if profile != "" || len(commands) == 0 {
+		if commands, err = s.respawnCommands(sess, profile); err != nil {
+			return session.Session{}, err
+		}
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	s.log.Info().Str("session_id", sess.ID).Str("tool_session", sess.GetMeta(session.MetaToolSession)).Msg("resuming session")
+	if err := s.spawn(cmdCtx, sess, commands, sess.GetMeta(session.MetaPrompt)); err != nil {
+		return session.Session{}, err
+	}
+	return sess, nil
+}
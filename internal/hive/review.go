@@ -0,0 +1,119 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// createReview creates a read-only review session over the directory of the
+// session opts.ReviewOf, for launching a reviewer agent on the same working
+// tree. Nothing is cloned and no rules or hooks run, since they would modify
+// a directory the review does not own.
+func (s *Service) createReview(ctx context.Context, opts CreateOptions) (*session.Session, error) {
+	target, err := s.ResolveSession(ctx, opts.ReviewOf)
+	if err != nil {
+		return nil, fmt.Errorf("get reviewed session: %w", err)
+	}
+	if target.State != session.StateActive {
+		return nil, fmt.Errorf("session %s is %s, not active", target.ID, target.State)
+	}
+	if target.IsReview() {
+		return nil, fmt.Errorf("session %s is itself a review; review %s instead", target.ID, target.GetMeta(session.MetaReviewOf))
+	}
+	if _, err := os.Stat(target.Path); err != nil {
+		return nil, fmt.Errorf("session directory: %w", err)
+	}
+
+	if !opts.PromptPreambled {
+		opts.Prompt = s.PromptFor(target.Remote, opts.Prompt)
+	}
+
+	spawnCommands, err := s.spawnCommands(opts, target.Remote)
+	if err != nil {
+		return nil, err
+	}
+
+	id := opts.SessionID
+	if id == "" {
+		id = generateID()
+	}
+	now := time.Now()
+	sess := session.Session{
+		ID:        id,
+		Name:      opts.Name,
+		Slug:      session.Slugify(opts.Name),
+		Path:      target.Path,
+		Remote:    target.Remote,
+		State:     session.StateActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	sess.SetMeta(session.MetaReviewOf, target.ID)
+	if sub := target.GetMeta(session.MetaSubdir); sub != "" {
+		sess.SetMeta(session.MetaSubdir, sub)
+	}
+	if opts.Prompt != "" {
+		sess.SetMeta(session.MetaPrompt, opts.Prompt)
+	}
+	if opts.SpawnProfile != "" {
+		sess.SetMeta(session.MetaSpawnProfile, opts.SpawnProfile)
+	}
+	if opts.BatchID != "" {
+		sess.SetMeta(session.MetaBatchID, opts.BatchID)
+	}
+
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return nil, fmt.Errorf("save session: %w", err)
+	}
+	s.Emit(events.SessionCreated, sess.ID, map[string]any{
+		"name":      sess.Name,
+		"remote":    sess.Remote,
+		"path":      sess.Path,
+		"review_of": target.ID,
+	})
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.spawn(cmdCtx, sess, spawnCommands, opts.Prompt); err != nil {
+		return nil, err
+	}
+
+	s.log.Info().Str("session_id", sess.ID).Str("review_of", target.ID).Msg("review session created")
+	return &sess, nil
+}
+
+// endReview drops a review session's record. Its directory belongs to the
+// reviewed session and is left untouched.
+func (s *Service) endReview(ctx context.Context, sess session.Session) error {
+	if err := s.sessions.Delete(ctx, sess.ID); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	s.Emit(events.SessionDeleted, sess.ID, map[string]any{"name": sess.Name, "remote": sess.Remote, "review_of": sess.GetMeta(session.MetaReviewOf)})
+	s.log.Info().Str("session_id", sess.ID).Msg("review session ended")
+	return nil
+}
+
+// endReviewsOf ends the review sessions of a session whose directory is going
+// away, so none is left pointing at a path it does not own.
+func (s *Service) endReviewsOf(ctx context.Context, id string) {
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		s.log.Warn().Err(err).Str("session_id", id).Msg("failed to list review sessions")
+		return
+	}
+	for _, sess := range sessions {
+		if sess.GetMeta(session.MetaReviewOf) != id {
+			continue
+		}
+		if err := s.endReview(ctx, sess); err != nil {
+			s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to end review session")
+		}
+	}
+}
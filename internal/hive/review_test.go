@@ -0,0 +1,68 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateSession_Review(t *testing.T) {
+	ctx := context.Background()
+	exec := &executil.RecordingExecutor{}
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"spawn {{ .Name }} {{ .ReviewOf }}"}},
+		Rules:    []config.Rule{{Commands: []string{"touch setup"}}},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	dir := t.TempDir()
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "work", Path: dir, Remote: "https://github.com/hay-kot/hive.git", State: session.StateActive}
+
+	review, err := svc.CreateSession(ctx, CreateOptions{Name: "reviewer", ReviewOf: "work"})
+	require.NoError(t, err)
+	assert.Equal(t, dir, review.Path)
+	assert.True(t, review.IsReview())
+	assert.Equal(t, "abc", review.GetMeta(session.MetaReviewOf))
+	assert.Equal(t, []string{"spawn reviewer abc"}, shellCommands(exec), "rules must not run in a review")
+
+	_, err = svc.CreateSession(ctx, CreateOptions{Name: "nested", ReviewOf: review.ID})
+	require.ErrorContains(t, err, "itself a review")
+
+	t.Run("delete leaves the directory", func(t *testing.T) {
+		require.NoError(t, svc.DeleteSession(ctx, review.ID))
+		assert.DirExists(t, dir)
+		assert.NotContains(t, store.sessions, review.ID)
+
+		trash, err := svc.Trash()
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("recycle leaves the directory", func(t *testing.T) {
+		again, err := svc.CreateSession(ctx, CreateOptions{Name: "reviewer", ReviewOf: "abc"})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.RecycleSession(ctx, again.ID, io.Discard))
+		assert.DirExists(t, dir)
+		assert.NotContains(t, store.sessions, again.ID)
+		assert.Equal(t, dir, store.sessions["abc"].Path)
+	})
+
+	t.Run("ends with the reviewed session", func(t *testing.T) {
+		again, err := svc.CreateSession(ctx, CreateOptions{Name: "reviewer", ReviewOf: "abc"})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.DeleteSession(ctx, "abc"))
+		assert.NotContains(t, store.sessions, again.ID)
+	})
+}
@@ -0,0 +1,95 @@
+package hive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
+	"github.com/rs/zerolog"
+)
+
+// SecretResolver turns configured env values into "KEY=value" pairs,
+// looking up !env and !secret references at execution time.
+type SecretResolver struct {
+	log      zerolog.Logger
+	executor executil.Executor
+	command  string
+}
+
+// NewSecretResolver creates a new SecretResolver. command is the
+// secrets.command template used for !secret references.
+func NewSecretResolver(log zerolog.Logger, executor executil.Executor, command string) *SecretResolver {
+	return &SecretResolver{
+		log:      log,
+		executor: executor,
+		command:  command,
+	}
+}
+
+// Resolve returns the environment entries for env, sorted by name.
+func (r *SecretResolver) Resolve(ctx context.Context, env map[string]config.SecretValue) ([]string, error) {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		v := env[name]
+
+		value, err := r.Value(ctx, v)
+		if err != nil {
+			return nil, fmt.Errorf("resolve env %s: %w", name, err)
+		}
+
+		r.log.Debug().Str("name", name).Str("source", v.String()).Msg("resolved env")
+		out = append(out, name+"="+value)
+	}
+
+	return out, nil
+}
+
+// Value returns the value of v, looking up !env and !secret references.
+func (r *SecretResolver) Value(ctx context.Context, v config.SecretValue) (string, error) {
+	switch v.Kind {
+	case config.SecretEnv:
+		val, ok := os.LookupEnv(v.Value)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", v.Value)
+		}
+		return val, nil
+	case config.SecretSecret:
+		return r.lookup(ctx, v.Value)
+	default:
+		return v.Value, nil
+	}
+}
+
+// lookup runs the secrets command for ref and returns its trimmed stdout.
+func (r *SecretResolver) lookup(ctx context.Context, ref string) (string, error) {
+	if r.command == "" {
+		return "", fmt.Errorf("secret %q: secrets.command is not configured", ref)
+	}
+
+	rendered, err := tmpl.Render(r.command, config.SecretTemplateData{Ref: ref})
+	if err != nil {
+		return "", fmt.Errorf("render secrets command: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := r.executor.RunStream(ctx, &stdout, &stderr, "sh", "-c", rendered); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("secret %q: %w: %s", ref, err, msg)
+		}
+		return "", fmt.Errorf("secret %q: %w", ref, err)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
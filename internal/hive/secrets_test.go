@@ -0,0 +1,62 @@
+package hive
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretResolver_Resolve(t *testing.T) {
+	t.Setenv("HIVE_TEST_TOKEN", "from-env")
+
+	exec := &executil.RecordingExecutor{
+		Outputs: map[string][]byte{"sh": []byte("s3cret\n")},
+	}
+	r := NewSecretResolver(zerolog.Nop(), exec, "op read {{ .Ref | shq }}")
+
+	env, err := r.Resolve(context.Background(), map[string]config.SecretValue{
+		"PLAIN":  {Value: "literal"},
+		"ENV":    {Kind: config.SecretEnv, Value: "HIVE_TEST_TOKEN"},
+		"SECRET": {Kind: config.SecretSecret, Value: "op://vault/item/field"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ENV=from-env", "PLAIN=literal", "SECRET=s3cret"}, env)
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"-c", "op read 'op://vault/item/field'"}, exec.Commands[0].Args)
+}
+
+func TestSecretResolver_Errors(t *testing.T) {
+	t.Run("missing env var", func(t *testing.T) {
+		r := NewSecretResolver(zerolog.Nop(), &executil.RecordingExecutor{}, "")
+		_, err := r.Resolve(context.Background(), map[string]config.SecretValue{
+			"TOKEN": {Kind: config.SecretEnv, Value: "HIVE_TEST_DEFINITELY_UNSET"},
+		})
+		assert.ErrorContains(t, err, "HIVE_TEST_DEFINITELY_UNSET")
+	})
+
+	t.Run("no secrets command", func(t *testing.T) {
+		r := NewSecretResolver(zerolog.Nop(), &executil.RecordingExecutor{}, "")
+		_, err := r.Resolve(context.Background(), map[string]config.SecretValue{
+			"TOKEN": {Kind: config.SecretSecret, Value: "op://x"},
+		})
+		assert.ErrorContains(t, err, "secrets.command")
+	})
+
+	t.Run("command fails", func(t *testing.T) {
+		exec := &executil.RecordingExecutor{
+			Errors: map[string]error{"sh": errors.New("exit status 1")},
+		}
+		r := NewSecretResolver(zerolog.Nop(), exec, "op read {{ .Ref }}")
+		_, err := r.Resolve(context.Background(), map[string]config.SecretValue{
+			"TOKEN": {Kind: config.SecretSecret, Value: "op://x"},
+		})
+		assert.Error(t, err)
+	})
+}
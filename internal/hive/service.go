@@ -2,19 +2,29 @@ package hive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hay-kot/hive/internal/core/config"
 	"github.com/hay-kot/hive/internal/core/git"
 	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/core/validate"
+	"github.com/hay-kot/hive/internal/events"
+	"github.com/hay-kot/hive/internal/store/backup"
+	"github.com/hay-kot/hive/internal/tracing"
 	"github.com/hay-kot/hive/pkg/executil"
 	"github.com/hay-kot/hive/pkg/randid"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // CreateOptions configures session creation.
@@ -25,19 +35,56 @@ type CreateOptions struct {
 	Remote        string // Git remote URL to clone (auto-detected if empty)
 	Source        string // Source directory for file copying
 	UseBatchSpawn bool   // Use batch_spawn commands instead of spawn
+	SpawnProfile  string // Named spawn profile; overrides rule defaults and UseBatchSpawn
+	BatchID       string // ID of the batch creating the session, recorded in metadata
+	Subdir        string // Working subdirectory within the repository, for monorepos
+	ReviewOf      string // Session whose directory a read-only review session shares; nothing is cloned
+	Wait          bool   // Wait for a free slot instead of failing when max_active limits are reached
+	IssueURL      string // Issue the session works on, recorded in metadata
+	// PromptPreambled is set when Prompt already went through PromptFor, so
+	// the rules' preambles are not added again.
+	PromptPreambled bool
 }
 
+// ErrAmbiguous is returned when a session name matches several sessions.
+var ErrAmbiguous = errors.New("ambiguous session reference")
+
+// ErrSpawnFailed is returned by CreateSession, along with the saved session,
+// when the session was created but its spawn commands failed. SpawnSession
+// retries the spawn without creating another session.
+var ErrSpawnFailed = errors.New("spawn failed")
+
+// recycleValidateWorkers bounds how many recycled sessions are validated at
+// once when looking for one to reuse.
+const recycleValidateWorkers = 8
+
 // Service orchestrates hive operations.
 type Service struct {
 	sessions   session.Store
 	git        git.Git
-	config     *config.Config
 	executor   executil.Executor
 	log        zerolog.Logger
+	stderr     io.Writer
 	spawner    *Spawner
-	recycler   *Recycler
 	hookRunner *HookRunner
 	fileCopier *FileCopier
+	events     *events.Log
+
+	// current is the config and the dependencies built from it, replaced
+	// by ReloadConfig. Read it through cfg and deps.
+	current atomic.Pointer[configDeps]
+
+	// claimMu guards claimed, the recycled session IDs currently being reused
+	// by in-flight CreateSession calls, so concurrent creates never share one.
+	claimMu sync.Mutex
+	claimed map[string]struct{}
+
+	// activeWaitInterval is how often a create waiting for a free active
+	// session slot checks again.
+	activeWaitInterval time.Duration
+
+	// ciPollInterval is how often WaitCIStatus reads the status topic.
+	ciPollInterval time.Duration
 }
 
 // New creates a new Service.
@@ -49,38 +96,151 @@ func New(
 	log zerolog.Logger,
 	stdout, stderr io.Writer,
 ) *Service {
-	return &Service{
+	s := &Service{
 		sessions:   sessions,
 		git:        gitClient,
-		config:     cfg,
 		executor:   exec,
 		log:        log,
+		stderr:     stderr,
 		spawner:    NewSpawner(log.With().Str("component", "spawner").Logger(), exec, stdout, stderr),
-		recycler:   NewRecycler(log.With().Str("component", "recycler").Logger(), exec),
 		hookRunner: NewHookRunner(log.With().Str("component", "hooks").Logger(), exec, stdout, stderr),
 		fileCopier: NewFileCopier(log.With().Str("component", "copier").Logger(), stdout),
+		events:     events.New(cfg.EventsFile()),
+		claimed:    make(map[string]struct{}),
+
+		activeWaitInterval: 5 * time.Second,
+		ciPollInterval:     2 * time.Second,
+	}
+	s.current.Store(s.newConfigDeps(cfg))
+	return s
+}
+
+// configDeps is a config with the dependencies built from it. A config is
+// never modified once installed, so callers may keep using the one they
+// read while ReloadConfig installs another.
+type configDeps struct {
+	config     *config.Config
+	secrets    *SecretResolver
+	notifier   *Notifier
+	gitRetrier *GitRetrier
+	recycler   *Recycler
+	backups    *backup.Manager
+}
+
+func (s *Service) newConfigDeps(cfg *config.Config) *configDeps {
+	secrets := NewSecretResolver(s.log.With().Str("component", "secrets").Logger(), s.executor, cfg.Secrets.Command)
+	gitRetrier := NewGitRetrier(s.log.With().Str("component", "git").Logger(), s.stderr, cfg.Git)
+	return &configDeps{
+		config:     cfg,
+		secrets:    secrets,
+		notifier:   NewNotifier(s.log.With().Str("component", "notify").Logger(), s.executor, secrets, cfg.Notifications),
+		gitRetrier: gitRetrier,
+		recycler:   NewRecycler(s.log.With().Str("component", "recycler").Logger(), s.executor, gitRetrier),
+		backups:    backup.New(cfg.DataDir, cfg.BackupsDir(), cfg.Backups.KeepLimit()),
 	}
 }
 
+// ReloadConfig replaces the service's config, rebuilding the secrets
+// resolver, notifier, git retrier, recycler, and backup manager from it.
+// Operations already running read the config again at each step, so one
+// that spans the reload may use the old config for its earlier steps and
+// the new one for the rest. cfg must not be modified afterwards; load a new
+// one to change it again.
+func (s *Service) ReloadConfig(cfg *config.Config) {
+	s.current.Store(s.newConfigDeps(cfg))
+}
+
+// cfg returns the current config.
+func (s *Service) cfg() *config.Config {
+	return s.current.Load().config
+}
+
+// deps returns the current config's dependencies.
+func (s *Service) deps() *configDeps {
+	return s.current.Load()
+}
+
+// traced runs fn in a span named name, recording the error it returns.
+func traced(ctx context.Context, name string, fn func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, name)
+	err := fn(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+// PromptFor returns prompt as the agent of a new session of remote receives
+// it: with the prompt preambles of the rules matching remote in front. An
+// empty prompt stays empty. Callers that show the prompt before creating the
+// session pass the result on with CreateOptions.PromptPreambled set.
+func (s *Service) PromptFor(remote, prompt string) string {
+	if prompt == "" {
+		return ""
+	}
+	if preamble := s.cfg().PromptPreambleFor(remote); preamble != "" {
+		return preamble + "\n\n" + prompt
+	}
+	return prompt
+}
+
 // CreateSession creates a new session or recycles an existing one.
-func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*session.Session, error) {
+func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (_ *session.Session, err error) {
+	opts.Name = validate.NormalizeSessionName(opts.Name)
+	if err := validate.SessionName(opts.Name); err != nil {
+		return nil, err
+	}
+
 	s.log.Info().Str("name", opts.Name).Str("remote", opts.Remote).Msg("creating session")
 
-	remote := opts.Remote
-	if remote == "" {
-		var err error
-		remote, err = s.DetectRemote(ctx, ".")
-		if err != nil {
-			return nil, fmt.Errorf("detect remote: %w", err)
-		}
-		s.log.Debug().Str("remote", remote).Msg("detected remote")
+	ctx, span := tracing.Start(ctx, "hive.CreateSession",
+		attribute.String("hive.session.name", opts.Name),
+		attribute.String("hive.batch_id", opts.BatchID),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if opts.ReviewOf != "" {
+		return s.createReview(ctx, opts)
+	}
+
+	var timings []session.Timing
+	ctx = withTimings(ctx, &timings)
+
+	remote, err := s.ResolveRemote(ctx, opts.Remote)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.reserveActive(ctx, remote, opts.Wait)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if !opts.PromptPreambled {
+		opts.Prompt = s.PromptFor(remote, opts.Prompt)
+	}
+
+	// Resolve spawn commands up front so an unknown profile fails before cloning
+	spawnCommands, err := s.spawnCommands(opts, remote)
+	if err != nil {
+		return nil, err
+	}
+
+	subdir, err := cleanSubdir(opts.Subdir)
+	if err != nil {
+		return nil, err
 	}
 
 	var sess session.Session
 	slug := session.Slugify(opts.Name)
 
 	// Try to find and validate a recyclable session
-	recyclable := s.findValidRecyclable(ctx, remote)
+	findCtx, findSpan := tracing.Start(ctx, "hive.find_recyclable")
+	recyclable := s.findValidRecyclable(findCtx, remote)
+	findSpan.SetAttributes(attribute.Bool("hive.recyclable_found", recyclable != nil))
+	tracing.End(findSpan, nil)
+	if recyclable != nil {
+		defer s.releaseClaim(recyclable.ID)
+	}
 
 	if recyclable != nil {
 		// Reuse existing recycled session (already cleaned up when marked for recycle)
@@ -88,7 +248,11 @@ func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*sessi
 
 		// Pull latest changes before running hooks
 		s.log.Debug().Str("path", recyclable.Path).Msg("pulling latest changes")
-		if err := s.git.Pull(ctx, recyclable.Path); err != nil {
+		if err := timeStep(ctx, session.StepPull, "", func(ctx context.Context) error {
+			return traced(ctx, "git.pull", func(ctx context.Context) error {
+				return s.deps().gitRetrier.Do(ctx, git.ProgressWriter(ctx), "git pull", func() error { return s.git.Pull(ctx, recyclable.Path) })
+			})
+		}); err != nil {
 			// Pull failed - mark as corrupted and fall through to clone
 			s.log.Warn().Err(err).Str("session_id", recyclable.ID).Msg("pull failed, marking corrupted")
 			s.markCorrupted(ctx, recyclable)
@@ -98,10 +262,14 @@ func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*sessi
 
 	if recyclable != nil {
 		// Rename directory to new session name pattern
-		repoName := git.ExtractRepoName(remote)
-		newPath := filepath.Join(s.config.ReposDir(), fmt.Sprintf("%s-%s-%s", repoName, slug, recyclable.ID))
-
-		if err := os.Rename(recyclable.Path, newPath); err != nil {
+		newPath, err := s.sessionDir(remote, slug, recyclable.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(newPath), 0o755); err != nil {
+			return nil, fmt.Errorf("create session parent directory: %w", err)
+		}
+		if err := s.renameSessionDir(recyclable.ID, recyclable.Path, newPath); err != nil {
 			return nil, fmt.Errorf("rename recycled directory: %w", err)
 		}
 
@@ -111,18 +279,53 @@ func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*sessi
 		sess.Path = newPath
 		sess.State = session.StateActive
 		sess.UpdatedAt = time.Now()
+		// Drop metadata left over from the session's previous use
+		delete(sess.Metadata, session.MetaBatchID)
+		delete(sess.Metadata, session.MetaPrompt)
+		delete(sess.Metadata, session.MetaSpawnProfile)
+		delete(sess.Metadata, session.MetaToolSession)
+		delete(sess.Metadata, session.MetaIssueURL)
+		sess.Timings = nil
 	} else {
 		// Create new session (either no recyclable found or it was corrupted)
 		id := opts.SessionID
 		if id == "" {
 			id = generateID()
 		}
-		repoName := git.ExtractRepoName(remote)
-		path := filepath.Join(s.config.ReposDir(), fmt.Sprintf("%s-%s-%s", repoName, slug, id))
+		path, err := s.sessionDir(remote, slug, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Never clone over an existing session or directory: a failed clone
+		// clears its destination, which would take their work with it
+		if opts.SessionID != "" {
+			if _, err := s.sessions.Get(ctx, id); err == nil {
+				return nil, fmt.Errorf("session %s already exists", id)
+			} else if !errors.Is(err, session.ErrNotFound) {
+				return nil, fmt.Errorf("get session: %w", err)
+			}
+		}
+		if _, err := os.Lstat(path); err == nil {
+			return nil, fmt.Errorf("session directory %s already exists", path)
+		}
 
 		s.log.Info().Str("remote", remote).Str("dest", path).Msg("cloning repository")
 
-		if err := s.git.Clone(ctx, remote, path); err != nil {
+		if err := timeStep(ctx, session.StepClone, "", func(ctx context.Context) error {
+			return traced(ctx, "git.clone", func(ctx context.Context) error {
+				return s.deps().gitRetrier.Do(ctx, git.ProgressWriter(ctx), "git clone", func() error {
+					_, statErr := os.Lstat(path)
+					existed := statErr == nil
+					err := s.git.Clone(ctx, remote, path)
+					if err != nil && !existed {
+						// Clear the partial clone so a retry starts from an empty directory
+						_ = os.RemoveAll(path)
+					}
+					return err
+				})
+			})
+		}); err != nil {
 			return nil, fmt.Errorf("clone repository: %w", err)
 		}
 
@@ -141,36 +344,86 @@ func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*sessi
 		}
 	}
 
+	// If interrupted before the session is saved, record it as corrupted so
+	// its directory is not orphaned and prune can remove it.
+	saved := false
+	defer func() {
+		if err != nil && !saved && ctx.Err() != nil {
+			err = s.abandonSession(context.WithoutCancel(ctx), &sess, err)
+		}
+	}()
+
+	if opts.BatchID != "" {
+		sess.SetMeta(session.MetaBatchID, opts.BatchID)
+	}
+	if opts.Prompt != "" {
+		sess.SetMeta(session.MetaPrompt, opts.Prompt)
+	}
+	if opts.SpawnProfile != "" {
+		sess.SetMeta(session.MetaSpawnProfile, opts.SpawnProfile)
+	}
+	if opts.IssueURL != "" {
+		sess.SetMeta(session.MetaIssueURL, opts.IssueURL)
+	}
+	delete(sess.Metadata, session.MetaSubdir)
+	if subdir != "" {
+		if info, err := os.Stat(filepath.Join(sess.Path, subdir)); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("subdirectory %q not found in repository", subdir)
+		}
+		sess.SetMeta(session.MetaSubdir, subdir)
+	}
+
+	// Resolve configured env (including secrets) for user commands only
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hookData := s.hookData(sess, opts.Prompt)
+
+	hooksLog, closeHooksLog := s.openSessionLog(sess.ID, LogSourceHooks)
+	defer closeHooksLog()
+	hooksCtx := withCommandLog(cmdCtx, hooksLog)
+
+	span.SetAttributes(attribute.String("hive.session.id", sess.ID), attribute.Bool("hive.recycled", recyclable != nil))
+
 	// Execute matching rules
-	if err := s.executeRules(ctx, remote, opts.Source, sess.Path); err != nil {
+	if err := traced(hooksCtx, "hive.rules", func(ctx context.Context) error { return s.executeRules(ctx, opts.Source, hookData) }); err != nil {
 		return nil, fmt.Errorf("execute rules: %w", err)
 	}
 
+	if err := traced(hooksCtx, "hive.hooks.post_create", func(ctx context.Context) error {
+		return s.runLifecycleHooks(ctx, s.hookRunner, config.HookPostCreate, hookData)
+	}); err != nil {
+		return nil, err
+	}
+
 	// Save session
-	if err := s.sessions.Save(ctx, sess); err != nil {
+	if err := traced(ctx, "store.save", func(ctx context.Context) error { return s.sessions.Save(ctx, sess) }); err != nil {
 		return nil, fmt.Errorf("save session: %w", err)
 	}
+	saved = true
+	release() // the saved session now counts against the limits itself
+	s.endRename(sess.ID)
+	s.Emit(events.SessionCreated, sess.ID, map[string]any{
+		"name":     sess.Name,
+		"remote":   sess.Remote,
+		"path":     sess.Path,
+		"recycled": recyclable != nil,
+	})
 
 	// Spawn terminal
-	spawnCommands := s.config.Commands.Spawn
-	if opts.UseBatchSpawn && len(s.config.Commands.BatchSpawn) > 0 {
-		spawnCommands = s.config.Commands.BatchSpawn
+	if err := timeStep(cmdCtx, session.StepSpawn, "", func(ctx context.Context) error {
+		return traced(ctx, "hive.spawn", func(ctx context.Context) error { return s.spawn(ctx, sess, spawnCommands, opts.Prompt) })
+	}); err != nil {
+		return &sess, fmt.Errorf("%w: %w", ErrSpawnFailed, err)
 	}
 
-	if len(spawnCommands) > 0 {
-		owner, repoName := git.ExtractOwnerRepo(remote)
-		data := SpawnData{
-			Path:       sess.Path,
-			Name:       sess.Name,
-			Prompt:     opts.Prompt,
-			Slug:       sess.Slug,
-			ContextDir: s.config.RepoContextDir(owner, repoName),
-			Owner:      owner,
-			Repo:       repoName,
-		}
-		if err := s.spawner.Spawn(ctx, spawnCommands, data); err != nil {
-			return nil, fmt.Errorf("spawn terminal: %w", err)
-		}
+	sess.Timings = timings
+	s.saveTimings(ctx, sess.ID, timings)
+
+	if err := traced(cmdCtx, "hive.background_hooks", func(ctx context.Context) error { return s.startBackgroundHooks(ctx, hookData) }); err != nil {
+		s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to start background hooks")
 	}
 
 	s.log.Info().Str("session_id", sess.ID).Str("path", sess.Path).Msg("session created")
@@ -178,20 +431,185 @@ func (s *Service) CreateSession(ctx context.Context, opts CreateOptions) (*sessi
 	return &sess, nil
 }
 
+// saveTimings stores the creation timings on a saved session. The session is
+// read again because spawn commands may have updated it. Failures are only
+// logged, as the session itself was created.
+func (s *Service) saveTimings(ctx context.Context, id string, timings []session.Timing) {
+	sess, err := s.sessions.Get(ctx, id)
+	if err == nil {
+		sess.Timings = timings
+		err = s.sessions.Save(ctx, sess)
+	}
+	if err != nil {
+		s.log.Warn().Err(err).Str("session_id", id).Msg("failed to save creation timings")
+	}
+}
+
+// SpawnSession runs the spawn commands again for an active session, for
+// example after its terminal was closed. The prompt and spawn profile stored
+// at creation are reused; a non-empty profile overrides the stored one.
+func (s *Service) SpawnSession(ctx context.Context, id, profile string) error {
+	sess, err := s.sessions.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	if sess.State != session.StateActive {
+		return fmt.Errorf("session %s is %s, not active", id, sess.State)
+	}
+	if _, err := os.Stat(sess.Path); err != nil {
+		return fmt.Errorf("session directory: %w", err)
+	}
+
+	commands, err := s.respawnCommands(sess, profile)
+	if err != nil {
+		return err
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info().Str("session_id", id).Str("profile", profile).Msg("re-spawning session")
+	return s.spawn(cmdCtx, sess, commands, sess.GetMeta(session.MetaPrompt))
+}
+
+// respawnCommands returns the spawn commands for an existing session: the
+// given profile, or else the ones it was created with.
+func (s *Service) respawnCommands(sess session.Session, profile string) ([]string, error) {
+	prompt := sess.GetMeta(session.MetaPrompt)
+	if profile == "" {
+		profile = sess.GetMeta(session.MetaSpawnProfile)
+	}
+
+	// Batch sessions always used batch_spawn, even without a prompt
+	commands, err := s.spawnCommands(CreateOptions{
+		Prompt:        prompt,
+		SpawnProfile:  profile,
+		UseBatchSpawn: prompt != "" || sess.GetMeta(session.MetaBatchID) != "",
+	}, sess.Remote)
+	if err != nil {
+		return nil, err
+	}
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("no spawn commands configured")
+	}
+	return commands, nil
+}
+
+// spawn renders and runs spawn commands for sess, recording them in the
+// session's spawn log.
+func (s *Service) spawn(ctx context.Context, sess session.Session, commands []string, prompt string) error {
+	if len(commands) == 0 {
+		return nil
+	}
+
+	owner, repoName := git.ExtractOwnerRepo(sess.Remote)
+	data := SpawnData{
+		Path:        sess.WorkDir(),
+		Root:        sess.Path,
+		ToolSession: sess.GetMeta(session.MetaToolSession),
+		ReviewOf:    sess.GetMeta(session.MetaReviewOf),
+		Name:        sess.Name,
+		Prompt:      prompt,
+		Slug:        sess.Slug,
+		ContextDir:  s.cfg().RepoContextDir(owner, repoName),
+		Owner:       owner,
+		Repo:        repoName,
+	}
+	spawnLog, closeSpawnLog := s.openSessionLog(sess.ID, LogSourceSpawn)
+	defer closeSpawnLog()
+
+	if err := s.spawner.Spawn(withCommandLog(ctx, spawnLog), commands, data); err != nil {
+		return fmt.Errorf("spawn terminal: %w", err)
+	}
+	return nil
+}
+
 // ListSessions returns all sessions.
 func (s *Service) ListSessions(ctx context.Context) ([]session.Session, error) {
 	return s.sessions.List(ctx)
 }
 
+// FindSessions returns the sessions matching f.
+func (s *Service) FindSessions(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	return s.sessions.Find(ctx, f)
+}
+
 // GetSession returns a session by ID.
 func (s *Service) GetSession(ctx context.Context, id string) (session.Session, error) {
 	return s.sessions.Get(ctx, id)
 }
 
+// ResolveSession returns the session with the given ID or, failing that, the
+// active session whose name or slug is ref. Returns session.ErrNotFound if
+// nothing matches and ErrAmbiguous if a name matches several active sessions.
+func (s *Service) ResolveSession(ctx context.Context, ref string) (session.Session, error) {
+	sess, err := s.sessions.Get(ctx, ref)
+	if err == nil || !errors.Is(err, session.ErrNotFound) {
+		return sess, err
+	}
+
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	var matches []session.Session
+	for _, candidate := range sessions {
+		if candidate.State == session.StateActive && (candidate.Name == ref || candidate.Slug == ref) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return session.Session{}, fmt.Errorf("%w: %s", session.ErrNotFound, ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.ID
+		}
+		return session.Session{}, fmt.Errorf("%w: name %q matches %d sessions (%s); use an ID", ErrAmbiguous, ref, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+// ExecSession runs cmd in the directory of an active session, streaming its
+// output to stdout and stderr. The configured env and the session's HIVE_*
+// variables are exported to the command.
+func (s *Service) ExecSession(ctx context.Context, ref string, stdout, stderr io.Writer, cmd string, args ...string) error {
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	if sess.State != session.StateActive {
+		return fmt.Errorf("session %s is %s, not active", sess.ID, sess.State)
+	}
+	if _, err := os.Stat(sess.WorkDir()); err != nil {
+		return fmt.Errorf("session directory: %w", err)
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return err
+	}
+	cmdCtx = executil.WithEnv(cmdCtx, s.hookData(sess, sess.GetMeta(session.MetaPrompt)).Env())
+
+	s.log.Debug().Str("session_id", sess.ID).Str("cmd", cmd).Msg("exec in session")
+	return s.executor.RunDirStream(cmdCtx, sess.WorkDir(), stdout, stderr, cmd, args...)
+}
+
 // RecycleSession marks a session for recycling and runs recycle commands.
 // The directory is renamed to a recycled name pattern immediately.
 // Output is written to w. If w is nil, output is discarded.
-func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) error {
+func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) (err error) {
+	ctx, span := tracing.Start(ctx, "hive.RecycleSession", attribute.String("hive.session.id", id))
+	defer func() { tracing.End(span, err) }()
+
 	sess, err := s.sessions.Get(ctx, id)
 	if err != nil {
 		return fmt.Errorf("get session: %w", err)
@@ -201,8 +619,13 @@ func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) er
 		return fmt.Errorf("session %s cannot be recycled (state: %s)", id, sess.State)
 	}
 
+	// A review session has no clone of its own to return to the pool
+	if sess.IsReview() {
+		return s.endReview(ctx, sess)
+	}
+
 	// Validate repository before recycling
-	if err := s.git.IsValidRepo(ctx, sess.Path); err != nil {
+	if err := traced(ctx, "git.validate", func(ctx context.Context) error { return s.git.IsValidRepo(ctx, sess.Path) }); err != nil {
 		s.log.Warn().Err(err).Str("session_id", id).Msg("session has corrupted repository")
 		s.markCorrupted(ctx, &sess)
 		return fmt.Errorf("session %s has corrupted repository: %w", id, err)
@@ -217,17 +640,44 @@ func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) er
 
 	data := RecycleData{
 		DefaultBranch: defaultBranch,
+		Path:          sess.Path,
+		ID:            sess.ID,
+		Name:          sess.Name,
+		Remote:        sess.Remote,
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		return err
 	}
 
-	if err := s.recycler.Recycle(ctx, sess.Path, s.config.Commands.Recycle, data, w); err != nil {
+	hooksLog, closeHooksLog := s.openSessionLog(sess.ID, LogSourceHooks)
+	defer closeHooksLog()
+	hooksCtx := withCommandLog(cmdCtx, hooksLog)
+
+	hooks := s.hookRunner.WithOutput(w)
+	if err := traced(hooksCtx, "hive.hooks.pre_recycle", func(ctx context.Context) error {
+		return s.runLifecycleHooks(ctx, hooks, config.HookPreRecycle, s.hookData(sess, ""))
+	}); err != nil {
+		return fmt.Errorf("recycle session %s: %w", id, err)
+	}
+
+	recycleLog, closeRecycleLog := s.openSessionLog(sess.ID, LogSourceRecycle)
+	defer closeRecycleLog()
+
+	if err := traced(withCommandLog(cmdCtx, recycleLog), "hive.recycle_commands", func(ctx context.Context) error {
+		return s.deps().recycler.Recycle(ctx, sess.Path, s.cfg().Commands.Recycle, data, w)
+	}); err != nil {
 		return fmt.Errorf("recycle session %s: %w", id, err)
 	}
 
+	s.endReviewsOf(ctx, sess.ID)
+
 	// Rename directory to recycled pattern immediately
 	repoName := git.ExtractRepoName(sess.Remote)
-	newPath := filepath.Join(s.config.ReposDir(), fmt.Sprintf("%s-recycle-%s", repoName, generateID()))
+	newPath := filepath.Join(s.cfg().ReposDir(), fmt.Sprintf("%s-recycle-%s", repoName, generateID()))
 
-	if err := os.Rename(sess.Path, newPath); err != nil {
+	if err := s.renameSessionDir(sess.ID, sess.Path, newPath); err != nil {
 		return fmt.Errorf("rename session directory: %w", err)
 	}
 
@@ -237,6 +687,16 @@ func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) er
 	if err := s.sessions.Save(ctx, sess); err != nil {
 		return fmt.Errorf("save session: %w", err)
 	}
+	s.endRename(sess.ID)
+	s.Emit(events.SessionRecycled, sess.ID, map[string]any{"name": sess.Name, "remote": sess.Remote})
+
+	// The session is already recycled, so a failing post_recycle hook is
+	// reported but does not undo it.
+	if err := traced(hooksCtx, "hive.hooks.post_recycle", func(ctx context.Context) error {
+		return s.runLifecycleHooks(ctx, hooks, config.HookPostRecycle, s.hookData(sess, ""))
+	}); err != nil {
+		s.log.Warn().Err(err).Str("session_id", id).Msg("post_recycle hooks failed")
+	}
 
 	// Enforce max recycled limit
 	if err := s.enforceMaxRecycled(ctx, sess.Remote); err != nil {
@@ -248,7 +708,8 @@ func (s *Service) RecycleSession(ctx context.Context, id string, w io.Writer) er
 	return nil
 }
 
-// DeleteSession removes a session and its directory.
+// DeleteSession removes a session. Its directory is moved to the trash,
+// where RestoreSession can recover it until trash.retention passes.
 func (s *Service) DeleteSession(ctx context.Context, id string) error {
 	sess, err := s.sessions.Get(ctx, id)
 	if err != nil {
@@ -257,8 +718,32 @@ func (s *Service) DeleteSession(ctx context.Context, id string) error {
 
 	s.log.Info().Str("session_id", id).Str("path", sess.Path).Msg("deleting session")
 
-	// Remove directory
-	if err := os.RemoveAll(sess.Path); err != nil {
+	if sess.IsReview() {
+		return s.endReview(ctx, sess)
+	}
+
+	// pre_delete hooks need the directory; skip them if it is already gone
+	if _, err := os.Stat(sess.Path); err == nil {
+		cmdCtx, err := s.withEnv(ctx)
+		if err != nil {
+			return err
+		}
+		hooksLog, closeHooksLog := s.openSessionLog(sess.ID, LogSourceHooks)
+		defer closeHooksLog()
+
+		if err := s.runLifecycleHooks(withCommandLog(cmdCtx, hooksLog), s.hookRunner, config.HookPreDelete, s.hookData(sess, "")); err != nil {
+			return fmt.Errorf("delete session %s: %w", id, err)
+		}
+	}
+
+	// Recycled sessions were already reset, so there is nothing worth
+	// restoring; everything else goes to the trash unless it is disabled.
+	trashed := s.cfg().Trash.RetentionPeriod() > 0 && sess.State != session.StateRecycled
+	if trashed {
+		if err := s.trashSession(sess); err != nil {
+			return fmt.Errorf("delete session %s: %w", id, err)
+		}
+	} else if err := os.RemoveAll(sess.Path); err != nil {
 		return fmt.Errorf("remove directory: %w", err)
 	}
 
@@ -266,102 +751,89 @@ func (s *Service) DeleteSession(ctx context.Context, id string) error {
 	if err := s.sessions.Delete(ctx, id); err != nil {
 		return fmt.Errorf("delete session: %w", err)
 	}
+	s.Emit(events.SessionDeleted, id, map[string]any{"name": sess.Name, "remote": sess.Remote, "trashed": trashed})
+	s.endReviewsOf(ctx, id)
 
-	return nil
-}
-
-// Prune removes recycled and corrupted sessions and their directories.
-// If all is true, deletes ALL recycled sessions.
-// If all is false, respects max_recycled limit per repository (keeps newest N).
-func (s *Service) Prune(ctx context.Context, all bool) (int, error) {
-	s.log.Info().Bool("all", all).Msg("pruning sessions")
-
-	sessions, err := s.sessions.List(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("list sessions: %w", err)
+	if _, err := s.ExpireTrash(); err != nil {
+		s.log.Warn().Err(err).Msg("failed to expire trash")
 	}
 
-	count := 0
+	return nil
+}
 
-	// Always delete corrupted sessions
-	for _, sess := range sessions {
-		if sess.State == session.StateCorrupted {
-			if err := s.DeleteSession(ctx, sess.ID); err != nil {
-				s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to delete corrupted session")
-				continue
-			}
-			count++
-		}
-	}
+// DetectRemote gets the git remote URL from the specified directory.
+func (s *Service) DetectRemote(ctx context.Context, dir string) (string, error) {
+	return s.git.RemoteURL(ctx, dir)
+}
 
-	if all {
-		// Delete ALL recycled sessions
-		for _, sess := range sessions {
-			if sess.State == session.StateRecycled {
-				if err := s.DeleteSession(ctx, sess.ID); err != nil {
-					s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to prune session")
-					continue
-				}
-				count++
-			}
-		}
-	} else {
-		// Respect max_recycled limit per repository
-		deleted, err := s.pruneExcessRecycled(ctx, sessions)
+// ResolveRemote returns the remote to create a session from: remote in its
+// normalized form, or the origin of the working directory when remote is
+// empty. Local paths and file:// URLs become absolute paths.
+func (s *Service) ResolveRemote(ctx context.Context, remote string) (string, error) {
+	if remote == "" {
+		detected, err := s.DetectRemote(ctx, ".")
 		if err != nil {
-			return count, fmt.Errorf("prune excess recycled: %w", err)
+			return "", fmt.Errorf("detect remote: %w", err)
 		}
-		count += deleted
+		s.log.Debug().Str("remote", detected).Msg("detected remote")
+		remote = detected
 	}
+	return git.NormalizeRemote(remote)
+}
 
-	s.log.Info().Int("count", count).Msg("prune complete")
-
-	return count, nil
+// Git returns the git client for use in background operations.
+func (s *Service) Git() git.Git {
+	return s.git
 }
 
-// pruneExcessRecycled deletes recycled sessions exceeding max_recycled per repository.
-func (s *Service) pruneExcessRecycled(ctx context.Context, sessions []session.Session) (int, error) {
-	// Group recycled sessions by remote
-	byRemote := make(map[string][]session.Session)
-	for _, sess := range sessions {
-		if sess.State == session.StateRecycled {
-			byRemote[sess.Remote] = append(byRemote[sess.Remote], sess)
-		}
+// withEnv resolves the configured env values, including secret references,
+// and attaches them to ctx for spawn, recycle, and rule commands.
+func (s *Service) withEnv(ctx context.Context) (context.Context, error) {
+	if len(s.cfg().Env) == 0 {
+		return ctx, nil
 	}
 
-	count := 0
-	for remote, recycled := range byRemote {
-		limit := s.config.GetMaxRecycled(remote)
-		if limit == 0 || len(recycled) <= limit {
-			continue
-		}
-
-		// Sort by UpdatedAt descending (newest first)
-		sort.Slice(recycled, func(i, j int) bool {
-			return recycled[i].UpdatedAt.After(recycled[j].UpdatedAt)
-		})
-
-		// Delete oldest sessions beyond the limit
-		for _, sess := range recycled[limit:] {
-			if err := s.DeleteSession(ctx, sess.ID); err != nil {
-				s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to delete excess session")
-				continue
-			}
-			count++
-		}
+	env, err := s.deps().secrets.Resolve(ctx, s.cfg().Env)
+	if err != nil {
+		return nil, fmt.Errorf("resolve env: %w", err)
 	}
-
-	return count, nil
+	return executil.WithEnv(ctx, env), nil
 }
 
-// DetectRemote gets the git remote URL from the specified directory.
-func (s *Service) DetectRemote(ctx context.Context, dir string) (string, error) {
-	return s.git.RemoteURL(ctx, dir)
+// cleanSubdir validates a session subdirectory, which must be a relative path
+// inside the repository.
+func cleanSubdir(subdir string) (string, error) {
+	if subdir == "" {
+		return "", nil
+	}
+	clean := filepath.Clean(subdir)
+	if !filepath.IsLocal(clean) {
+		return "", fmt.Errorf("subdirectory %q must be a relative path inside the repository", subdir)
+	}
+	if clean == "." {
+		return "", nil
+	}
+	return clean, nil
 }
 
-// Git returns the git client for use in background operations.
-func (s *Service) Git() git.Git {
-	return s.git
+// sessionDir returns the directory for a session, laid out by
+// paths.session_dir_template.
+func (s *Service) sessionDir(remote, slug, id string) (string, error) {
+	owner, _ := git.ExtractOwnerRepo(remote)
+	dir, err := s.cfg().SessionDir(config.SessionDirData{
+		Owner: owner,
+		Repo:  git.ExtractRepoName(remote),
+		Slug:  slug,
+		ID:    id,
+		Date:  time.Now().Format(time.DateOnly),
+	})
+	if err != nil {
+		return "", fmt.Errorf("session directory: %w", err)
+	}
+	if err := validate.SessionPath(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
 }
 
 // generateID creates a 6-character random alphanumeric session ID.
@@ -369,8 +841,12 @@ func generateID() string {
 	return randid.Generate(6)
 }
 
-// findValidRecyclable finds a recyclable session and validates it.
-// Returns nil if none found or all candidates are corrupted.
+// findValidRecyclable finds a recyclable session, validates it, and claims it
+// so concurrent creates cannot reuse it. Candidates are validated in parallel
+// without holding claimMu, so slow checks do not hold up other creates and
+// plans; the most recently updated valid one that is still unclaimed
+// afterwards is returned. Invalid ones are marked corrupted unless another
+// create has reused them in the meantime.
 func (s *Service) findValidRecyclable(ctx context.Context, remote string) *session.Session {
 	sessions, err := s.sessions.List(ctx)
 	if err != nil {
@@ -378,32 +854,133 @@ func (s *Service) findValidRecyclable(ctx context.Context, remote string) *sessi
 		return nil
 	}
 
+	s.claimMu.Lock()
+	var candidates []*session.Session
 	for i := range sessions {
 		sess := &sessions[i]
 
-		// Skip non-recyclable sessions
+		// Skip non-recyclable sessions and ones claimed by another create
 		if sess.State != session.StateRecycled || sess.Remote != remote {
 			continue
 		}
+		if _, ok := s.claimed[sess.ID]; ok {
+			continue
+		}
+		candidates = append(candidates, sess)
+	}
+	s.claimMu.Unlock()
+
+	var invalid []*session.Session
+	valid := s.validateRecyclable(ctx, candidates, func(sess *session.Session, err error) {
+		s.log.Warn().Err(err).Str("session_id", sess.ID).Str("path", sess.Path).Msg("corrupted session found")
+		invalid = append(invalid, sess)
+	})
 
-		// Validate the repository
-		if err := s.git.IsValidRepo(ctx, sess.Path); err != nil {
-			s.log.Warn().Err(err).Str("session_id", sess.ID).Str("path", sess.Path).Msg("corrupted session found")
+	// Claim the first valid candidate, and the invalid ones while they are
+	// marked, skipping any a concurrent create claimed during validation
+	s.claimMu.Lock()
+	var found *session.Session
+	for _, sess := range valid {
+		if _, ok := s.claimed[sess.ID]; !ok {
+			s.claimed[sess.ID] = struct{}{}
+			found = sess
+			break
+		}
+	}
+	var toMark []*session.Session
+	for _, sess := range invalid {
+		if _, ok := s.claimed[sess.ID]; !ok {
+			s.claimed[sess.ID] = struct{}{}
+			toMark = append(toMark, sess)
+		}
+	}
+	s.claimMu.Unlock()
+
+	for _, sess := range toMark {
+		// A create that reused the session since it was listed has moved it
+		// out of the recycled state, and its failed check proves nothing
+		if cur, err := s.sessions.Get(ctx, sess.ID); err == nil && cur.State == session.StateRecycled && cur.Path == sess.Path {
 			s.markCorrupted(ctx, sess)
+		}
+		s.releaseClaim(sess.ID)
+	}
+	return found
+}
+
+// validateRecyclable checks the repository of each candidate with a bounded
+// pool of workers and returns the valid ones, most recently updated first.
+// onInvalid is called, one at a time, for each candidate that failed.
+func (s *Service) validateRecyclable(ctx context.Context, candidates []*session.Session, onInvalid func(*session.Session, error)) []*session.Session {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(candidates))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(recycleValidateWorkers, len(candidates)) {
+		wg.Go(func() {
+			for i := range next {
+				errs[i] = s.git.IsValidRepo(ctx, candidates[i].Path)
+			}
+		})
+	}
+	for i := range candidates {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var valid []*session.Session
+	for i, sess := range candidates {
+		if errs[i] != nil {
+			onInvalid(sess, errs[i])
 			continue
 		}
+		valid = append(valid, sess)
+	}
+
+	slices.SortStableFunc(valid, func(a, b *session.Session) int {
+		return b.UpdatedAt.Compare(a.UpdatedAt)
+	})
+	return valid
+}
+
+// abandonSession records a session whose creation was interrupted after its
+// directory was set up as corrupted, and adds what happened to it to err.
+func (s *Service) abandonSession(ctx context.Context, sess *session.Session, err error) error {
+	s.log.Warn().Str("session_id", sess.ID).Str("path", sess.Path).Msg("session creation interrupted")
 
-		return sess
+	// Save first so markCorrupted can delete or update the stored session
+	if saveErr := s.sessions.Save(ctx, *sess); saveErr != nil {
+		s.log.Error().Err(saveErr).Str("session_id", sess.ID).Msg("failed to save interrupted session")
+		return fmt.Errorf("%w (partially created session left at %s)", err, sess.Path)
+	}
+	stored, getErr := s.sessions.Get(ctx, sess.ID)
+	if getErr != nil {
+		return fmt.Errorf("%w (partially created session left at %s)", err, sess.Path)
 	}
+	s.markCorrupted(ctx, &stored)
 
-	return nil
+	if _, getErr := s.sessions.Get(ctx, sess.ID); errors.Is(getErr, session.ErrNotFound) {
+		return fmt.Errorf("%w (partially created session %s was removed)", err, sess.ID)
+	}
+	return fmt.Errorf("%w (partially created session %s was marked corrupted; run 'hive prune' to remove it)", err, sess.ID)
+}
+
+// releaseClaim releases a recycled session claimed by findValidRecyclable.
+func (s *Service) releaseClaim(id string) {
+	s.claimMu.Lock()
+	defer s.claimMu.Unlock()
+	delete(s.claimed, id)
 }
 
 // markCorrupted marks a session as corrupted and optionally deletes it.
 func (s *Service) markCorrupted(ctx context.Context, sess *session.Session) {
 	sess.MarkCorrupted(time.Now())
+	s.Emit(events.SessionCorrupted, sess.ID, map[string]any{"name": sess.Name, "path": sess.Path})
 
-	if s.config.AutoDeleteCorrupted {
+	if s.cfg().AutoDeleteCorrupted {
 		s.log.Info().Str("session_id", sess.ID).Msg("auto-deleting corrupted session")
 		if err := s.DeleteSession(ctx, sess.ID); err != nil {
 			s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("failed to delete corrupted session, marking instead")
@@ -419,10 +996,10 @@ func (s *Service) markCorrupted(ctx context.Context, sess *session.Session) {
 	}
 }
 
-// executeRules executes all rules matching the remote URL.
-func (s *Service) executeRules(ctx context.Context, remote, source, dest string) error {
-	for _, rule := range s.config.Rules {
-		matched, err := matchRemotePattern(rule.Pattern, remote)
+// executeRules executes all rules matching the session's remote URL.
+func (s *Service) executeRules(ctx context.Context, source string, data HookData) error {
+	for _, rule := range s.cfg().Rules {
+		matched, err := matchRemotePattern(rule.Pattern, data.Remote)
 		if err != nil {
 			return fmt.Errorf("match pattern %q: %w", rule.Pattern, err)
 		}
@@ -433,19 +1010,23 @@ func (s *Service) executeRules(ctx context.Context, remote, source, dest string)
 		s.log.Debug().
 			Str("pattern", rule.Pattern).
 			Strs("commands", rule.Commands).
-			Strs("copy", rule.Copy).
+			Strs("copy", copyLabels(rule.Copy)).
 			Msg("rule matched")
 
 		// Copy files first (so hooks can operate on them)
 		if len(rule.Copy) > 0 && source != "" {
-			if err := s.fileCopier.CopyFiles(ctx, rule, source, dest); err != nil {
+			if err := timeStep(ctx, session.StepCopy, rule.Pattern, func(ctx context.Context) error {
+				return traced(ctx, "hive.copy", func(ctx context.Context) error { return s.fileCopier.CopyFiles(ctx, rule, source, data.Path, data) })
+			}); err != nil {
 				return fmt.Errorf("copy files: %w", err)
 			}
 		}
 
-		// Run commands
-		if len(rule.Commands) > 0 {
-			if err := s.hookRunner.RunHooks(ctx, rule, dest); err != nil {
+		// Run commands; background rules are started after spawn
+		if len(rule.Commands) > 0 && !rule.Background {
+			if err := timeStep(ctx, session.StepRuleCommands, rule.Pattern, func(ctx context.Context) error {
+				return traced(ctx, "hive.rule_commands", func(ctx context.Context) error { return s.hookRunner.RunHooks(ctx, rule, data) })
+			}); err != nil {
 				return fmt.Errorf("run hooks: %w", err)
 			}
 		}
@@ -453,9 +1034,117 @@ func (s *Service) executeRules(ctx context.Context, remote, source, dest string)
 	return nil
 }
 
+// spawnCommands returns the commands used to spawn a session's terminal:
+// the explicit profile, else the profile of the last matching rule, else
+// batch_spawn (when requested and set) or spawn.
+func (s *Service) spawnCommands(opts CreateOptions, remote string) ([]string, error) {
+	profile := opts.SpawnProfile
+	if profile == "" {
+		profile = s.cfg().SpawnProfileFor(remote)
+	}
+
+	if profile != "" {
+		commands, ok := s.cfg().Commands.SpawnProfiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown spawn profile %q", profile)
+		}
+		return commands, nil
+	}
+
+	if opts.UseBatchSpawn && len(s.cfg().Commands.BatchSpawn) > 0 {
+		return s.cfg().Commands.BatchSpawn, nil
+	}
+	return s.cfg().Commands.Spawn, nil
+}
+
+// hookData builds the hook template context for a session.
+func (s *Service) hookData(sess session.Session, prompt string) HookData {
+	owner, repoName := git.ExtractOwnerRepo(sess.Remote)
+	return HookData{
+		ID:         sess.ID,
+		Name:       sess.Name,
+		Slug:       sess.Slug,
+		Path:       sess.Path,
+		WorkDir:    sess.WorkDir(),
+		Remote:     sess.Remote,
+		Prompt:     prompt,
+		ContextDir: s.cfg().RepoContextDir(owner, repoName),
+		Owner:      owner,
+		Repo:       repoName,
+	}
+}
+
+// startBackgroundHooks starts the commands, then the post_create hooks, of
+// every matching background rule in a detached process. Output is appended
+// to hooks.log in the session's log directory.
+func (s *Service) startBackgroundHooks(ctx context.Context, data HookData) error {
+	var commands, postCreate []BackgroundJob
+	for _, rule := range s.cfg().Rules {
+		if !rule.Background {
+			continue
+		}
+
+		matched, err := matchRemotePattern(rule.Pattern, data.Remote)
+		if err != nil {
+			return fmt.Errorf("match pattern %q: %w", rule.Pattern, err)
+		}
+		if !matched {
+			continue
+		}
+
+		if len(rule.Commands) > 0 {
+			commands = append(commands, BackgroundJob{Label: "hook", Commands: rule.Commands, Policy: rule.HookPolicy})
+		}
+		if len(rule.Hooks.PostCreate) > 0 {
+			postCreate = append(postCreate, BackgroundJob{Label: config.HookPostCreate, Commands: rule.Hooks.PostCreate, Policy: rule.HookPolicy})
+		}
+	}
+
+	jobs := slices.Concat(commands, postCreate)
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	logPath := s.cfg().SessionLogFile(data.ID, LogSourceHooks)
+	return s.hookRunner.Start(ctx, jobs, data, logPath)
+}
+
+// runLifecycleHooks runs the hooks for event from every rule matching the
+// session's remote, in rule order, stopping at the first failure.
+func (s *Service) runLifecycleHooks(ctx context.Context, runner *HookRunner, event string, data HookData) error {
+	for _, rule := range s.cfg().Rules {
+		commands := rule.Hooks.For(event)
+		if len(commands) == 0 || (rule.Background && event == config.HookPostCreate) {
+			continue
+		}
+
+		matched, err := matchRemotePattern(rule.Pattern, data.Remote)
+		if err != nil {
+			return fmt.Errorf("match pattern %q: %w", rule.Pattern, err)
+		}
+		if !matched {
+			continue
+		}
+
+		s.log.Debug().
+			Str("event", event).
+			Str("pattern", rule.Pattern).
+			Strs("commands", commands).
+			Msg("running lifecycle hooks")
+
+		// Timed while a session is created, when the event is post_create
+		if err := timeStep(ctx, event, rule.Pattern, func(ctx context.Context) error {
+			return runner.Run(ctx, event, commands, data, rule.HookPolicy)
+		}); err != nil {
+			return fmt.Errorf("%s hooks: %w", event, err)
+		}
+	}
+	return nil
+}
+
 // enforceMaxRecycled deletes oldest recycled sessions for a remote when limit is exceeded.
 func (s *Service) enforceMaxRecycled(ctx context.Context, remote string) error {
-	limit := s.config.GetMaxRecycled(remote)
+	limit := s.cfg().GetMaxRecycled(remote)
 	if limit == 0 {
 		// Unlimited
 		return nil
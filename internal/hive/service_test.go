@@ -2,16 +2,28 @@ package hive
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/hay-kot/hive/internal/core/config"
 	"github.com/hay-kot/hive/internal/core/git"
 	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // mockStore implements session.Store for testing.
@@ -49,6 +61,11 @@ func (m *mockStore) Delete(_ context.Context, id string) error {
 	return nil
 }
 
+func (m *mockStore) Find(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	sessions, err := m.List(ctx)
+	return f.Apply(sessions), err
+}
+
 func (m *mockStore) FindRecyclable(_ context.Context, remote string) (session.Session, error) {
 	for _, s := range m.sessions {
 		if s.State == session.StateRecycled && s.Remote == remote {
@@ -86,6 +103,36 @@ func newTestService(t *testing.T, store session.Store, cfg *config.Config) *Serv
 	return New(store, &mockGit{}, cfg, nil, log, io.Discard, io.Discard)
 }
 
+func TestReloadConfig(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	svc := newTestService(t, newMockStore(), nil)
+	prev := svc.cfg()
+
+	next := &config.Config{
+		DataDir: prev.DataDir,
+		GitPath: "git",
+		Rules:   []config.Rule{{PromptPreamble: "Run the tests."}},
+		Notifications: []config.Notification{
+			{Events: []string{"session.deleted"}, Command: "notify-send {{ .Type }}"},
+		},
+	}
+
+	// Run with -race: reads during a reload must not race with it
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for range 100 {
+			_ = svc.PromptFor(remote, "fix it")
+			svc.Emit("test", "", nil)
+		}
+	})
+	svc.ReloadConfig(next)
+	wg.Wait()
+
+	assert.Equal(t, "Run the tests.\n\nfix it", svc.PromptFor(remote, "fix it"))
+	assert.Empty(t, prev.Rules, "the previous config is not modified")
+	assert.Equal(t, next.Notifications, svc.deps().notifier.notifications, "dependents are rebuilt from the new config")
+}
+
 func TestEnforceMaxRecycled(t *testing.T) {
 	intPtr := func(n int) *int { return &n }
 
@@ -235,9 +282,9 @@ func TestPrune(t *testing.T) {
 			Path:   t.TempDir(),
 		}
 
-		count, err := svc.Prune(context.Background(), true)
+		result, err := svc.Prune(context.Background(), PruneOptions{All: true})
 		require.NoError(t, err)
-		assert.Equal(t, 5, count)
+		assert.Equal(t, 5, result.Pruned())
 
 		sessions, _ := store.List(context.Background())
 		assert.Len(t, sessions, 1)
@@ -268,9 +315,9 @@ func TestPrune(t *testing.T) {
 			store.sessions[sess.ID] = sess
 		}
 
-		count, err := svc.Prune(context.Background(), false)
+		result, err := svc.Prune(context.Background(), PruneOptions{})
 		require.NoError(t, err)
-		assert.Equal(t, 3, count) // Should delete 3 (5-2)
+		assert.Equal(t, 3, result.Pruned()) // Should delete 3 (5-2)
 
 		sessions, _ := store.List(context.Background())
 		assert.Len(t, sessions, 2)
@@ -298,9 +345,9 @@ func TestPrune(t *testing.T) {
 			Path:  t.TempDir(),
 		}
 
-		count, err := svc.Prune(context.Background(), false)
+		result, err := svc.Prune(context.Background(), PruneOptions{})
 		require.NoError(t, err)
-		assert.Equal(t, 1, count)
+		assert.Equal(t, 1, result.Pruned())
 
 		sessions, _ := store.List(context.Background())
 		assert.Len(t, sessions, 1)
@@ -347,11 +394,11 @@ func TestPrune(t *testing.T) {
 			store.sessions[sess.ID] = sess
 		}
 
-		count, err := svc.Prune(context.Background(), false)
+		result, err := svc.Prune(context.Background(), PruneOptions{})
 		require.NoError(t, err)
 		// strict: keep 1, delete 2
 		// normal: keep 3 (under limit of 5)
-		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, result.Pruned())
 
 		sessions, _ := store.List(context.Background())
 		assert.Len(t, sessions, 4)
@@ -369,6 +416,92 @@ func TestPrune(t *testing.T) {
 		assert.Equal(t, 1, strictCount, "strict repo should have 1 session")
 		assert.Equal(t, 3, normalCount, "normal repo should have 3 sessions")
 	})
+
+	t.Run("dry run reports without deleting", func(t *testing.T) {
+		store := newMockStore()
+		cfg := &config.Config{
+			DataDir: t.TempDir(),
+			GitPath: "git",
+			Rules:   []config.Rule{{Pattern: "", MaxRecycled: intPtr(10)}},
+		}
+		svc := newTestService(t, store, cfg)
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), make([]byte, 100), 0o644))
+		store.sessions["old"] = session.Session{
+			ID:        "old",
+			Remote:    "https://github.com/test/repo",
+			State:     session.StateRecycled,
+			Path:      dir,
+			UpdatedAt: time.Now().Add(-48 * time.Hour),
+		}
+
+		result, err := svc.Prune(context.Background(), PruneOptions{All: true, DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, result.Sessions, 1)
+		assert.Equal(t, PruneAll, result.Sessions[0].Reason)
+		assert.Equal(t, int64(100), result.Reclaimed)
+		assert.True(t, result.DryRun)
+		assert.Zero(t, result.Pruned())
+
+		assert.Len(t, store.sessions, 1)
+		assert.DirExists(t, dir)
+	})
+
+	t.Run("repo and older_than", func(t *testing.T) {
+		store := newMockStore()
+		cfg := &config.Config{
+			DataDir: t.TempDir(),
+			GitPath: "git",
+			Rules:   []config.Rule{{Pattern: "", MaxRecycled: intPtr(10)}},
+		}
+		svc := newTestService(t, store, cfg)
+
+		now := time.Now()
+		for id, sess := range map[string]session.Session{
+			"stale":  {Remote: "https://github.com/test/repo", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+			"fresh":  {Remote: "https://github.com/test/repo", UpdatedAt: now.Add(-time.Hour)},
+			"others": {Remote: "https://github.com/other/repo", UpdatedAt: now.Add(-10 * 24 * time.Hour)},
+		} {
+			sess.ID = id
+			sess.State = session.StateRecycled
+			sess.Path = t.TempDir()
+			store.sessions[id] = sess
+		}
+
+		result, err := svc.Prune(context.Background(), PruneOptions{Repo: "test/repo", OlderThan: 7 * 24 * time.Hour})
+		require.NoError(t, err)
+		require.Len(t, result.Sessions, 1)
+		assert.Equal(t, "stale", result.Sessions[0].ID)
+		assert.Equal(t, PruneOlderThan, result.Sessions[0].Reason)
+
+		assert.Contains(t, store.sessions, "fresh")
+		assert.Contains(t, store.sessions, "others")
+		assert.NotContains(t, store.sessions, "stale")
+	})
+
+	t.Run("backs up stores before deleting", func(t *testing.T) {
+		store := newMockStore()
+		cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+		svc := newTestService(t, store, cfg)
+		require.NoError(t, os.WriteFile(cfg.SessionsFile(), []byte(`{"sessions":[]}`), 0o644))
+
+		store.sessions["c"] = session.Session{ID: "c", State: session.StateCorrupted, Path: t.TempDir()}
+
+		_, err := svc.Prune(context.Background(), PruneOptions{DryRun: true})
+		require.NoError(t, err)
+		backups, err := svc.Backups().List()
+		require.NoError(t, err)
+		assert.Empty(t, backups, "dry run should not back up")
+
+		_, err = svc.Prune(context.Background(), PruneOptions{})
+		require.NoError(t, err)
+		backups, err = svc.Backups().List()
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		assert.Equal(t, BackupPrune, backups[0].Reason)
+		assert.FileExists(t, filepath.Join(backups[0].Path, "sessions.json"))
+	})
 }
 
 // Ensure the mock implements the interface at compile time.
@@ -376,3 +509,1119 @@ var (
 	_ git.Git       = (*mockGit)(nil)
 	_ session.Store = (*mockStore)(nil)
 )
+
+func TestCreateSession_InjectsEnv(t *testing.T) {
+	t.Setenv("HIVE_TEST_WORK_TOKEN", "tok")
+
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"echo {{ .Name }}"}},
+		Env: map[string]config.SecretValue{
+			"GH_TOKEN": {Kind: config.SecretEnv, Value: "HIVE_TEST_WORK_TOKEN"},
+		},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	_, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:   "env-test",
+		Remote: "https://github.com/hay-kot/hive.git",
+	})
+	require.NoError(t, err)
+
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"GH_TOKEN=tok"}, exec.Commands[0].Env)
+}
+
+func TestCreateSession_RecordsBatchID(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	store := newMockStore()
+	svc := newTestService(t, store, nil)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:    "batched",
+		Remote:  remote,
+		BatchID: "abc123",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", created.GetMeta(session.MetaBatchID))
+
+	// A recycled session reused outside a batch drops its old batch ID
+	recycled := *created
+	recycled.ID = "rec1"
+	recycled.State = session.StateRecycled
+	recycled.Path = t.TempDir()
+	store.sessions = map[string]session.Session{recycled.ID: recycled}
+	require.NoError(t, os.MkdirAll(svc.cfg().ReposDir(), 0o755))
+
+	reused, err := svc.CreateSession(context.Background(), CreateOptions{Name: "solo", Remote: remote})
+	require.NoError(t, err)
+	assert.Equal(t, "rec1", reused.ID)
+	assert.Empty(t, reused.GetMeta(session.MetaBatchID))
+}
+
+// shellCommands returns the script of each recorded "sh -c" command.
+func shellCommands(exec *executil.RecordingExecutor) []string {
+	var scripts []string
+	for _, c := range exec.Commands {
+		if c.Cmd == "sh" && len(c.Args) == 2 {
+			scripts = append(scripts, c.Args[1])
+		}
+	}
+	return scripts
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Recycle: []string{"recycle"}},
+		Rules: []config.Rule{
+			{
+				Commands: []string{"setup"},
+				Hooks: config.RuleHooks{
+					PostCreate:  []string{"post-create"},
+					PreRecycle:  []string{"pre-recycle"},
+					PostRecycle: []string{"post-recycle"},
+					PreDelete:   []string{"pre-delete"},
+				},
+			},
+			{
+				Pattern: "gitlab",
+				Hooks:   config.RuleHooks{PostCreate: []string{"other-remote"}},
+			},
+		},
+	}
+	store := newMockStore()
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	ctx := context.Background()
+
+	created, err := svc.CreateSession(ctx, CreateOptions{Name: "hooks", Remote: remote})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"setup", "post-create"}, shellCommands(exec))
+
+	// mockGit does not clone, so create the directory for recycle and delete
+	require.NoError(t, os.MkdirAll(created.Path, 0o755))
+
+	exec.Reset()
+	require.NoError(t, svc.RecycleSession(ctx, created.ID, nil))
+	assert.Equal(t, []string{"pre-recycle", "recycle", "post-recycle"}, shellCommands(exec))
+
+	recycled, err := store.Get(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, recycled.Path, exec.Commands[2].Dir, "post_recycle runs in the recycled directory")
+
+	exec.Reset()
+	require.NoError(t, svc.DeleteSession(ctx, created.ID))
+	assert.Equal(t, []string{"pre-delete"}, shellCommands(exec))
+	assert.NoDirExists(t, recycled.Path)
+}
+
+func TestLifecycleHooks_PreDeleteFailureKeepsSession(t *testing.T) {
+	exec := &executil.RecordingExecutor{Errors: map[string]error{"sh": assert.AnError}}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Rules:   []config.Rule{{Hooks: config.RuleHooks{PreDelete: []string{"docker compose down"}}}},
+	}
+	store := newMockStore()
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	dir := t.TempDir()
+	store.sessions["abc"] = session.Session{ID: "abc", Path: dir, State: session.StateActive}
+
+	err := svc.DeleteSession(context.Background(), "abc")
+	require.ErrorContains(t, err, "pre_delete hooks")
+	assert.DirExists(t, dir)
+	assert.Contains(t, store.sessions, "abc")
+}
+
+func TestCreateSession_HookEnvAndTemplates(t *testing.T) {
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Rules: []config.Rule{{
+			Commands: []string{"echo {{ .Name }} {{ .Repo }}"},
+			Hooks:    config.RuleHooks{PostCreate: []string{"notify {{ .ID }}"}},
+		}},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:      "tmpl",
+		SessionID: "abc123",
+		Prompt:    "fix it",
+		Remote:    "https://github.com/hay-kot/hive.git",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"echo tmpl hive", "notify abc123"}, shellCommands(exec))
+	for _, c := range exec.Commands {
+		assert.Equal(t, created.Path, c.Dir)
+		assert.Contains(t, c.Env, "HIVE_SESSION_ID=abc123")
+		assert.Contains(t, c.Env, "HIVE_PATH="+created.Path)
+		assert.Contains(t, c.Env, "HIVE_REMOTE=https://github.com/hay-kot/hive.git")
+		assert.Contains(t, c.Env, "HIVE_PROMPT=fix it")
+	}
+}
+
+func TestCreateSession_BackgroundHooks(t *testing.T) {
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Rules: []config.Rule{
+			{Commands: []string{"setup"}},
+			{
+				Commands:   []string{"docker compose up -d"},
+				Hooks:      config.RuleHooks{PostCreate: []string{"seed-db"}},
+				HookPolicy: config.HookPolicy{Background: true},
+			},
+		},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	var script, logPath string
+	svc.hookRunner.startDetached = func(_ string, _ []string, s, l string) error {
+		script, logPath = s, l
+		return nil
+	}
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:      "bg",
+		SessionID: "bg1",
+		Remote:    "https://github.com/hay-kot/hive.git",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"setup"}, shellCommands(exec), "background commands do not run inline")
+	assert.Equal(t, filepath.Join(cfg.SessionLogsDir(created.ID), "hooks.log"), logPath)
+	assert.Less(t, strings.Index(script, "docker compose up -d"), strings.Index(script, "seed-db"))
+}
+
+func TestSessionLogs(t *testing.T) {
+	exec := &executil.RecordingExecutor{Outputs: map[string][]byte{"sh": []byte("output\n")}}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Commands: config.Commands{
+			Spawn:   []string{"tmux new-window"},
+			Recycle: []string{"git reset --hard"},
+		},
+		Rules: []config.Rule{{Commands: []string{"npm install"}}},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	ctx := context.Background()
+
+	created, err := svc.CreateSession(ctx, CreateOptions{Name: "logs", Remote: "https://github.com/hay-kot/hive.git"})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(created.Path, 0o755))
+	require.NoError(t, svc.RecycleSession(ctx, created.ID, nil))
+
+	read := func(source string) string {
+		data, err := os.ReadFile(cfg.SessionLogFile(created.ID, source))
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	hooks := read(LogSourceHooks)
+	assert.Contains(t, hooks, "[hook] npm install\noutput\n--- ok after")
+	assert.Contains(t, read(LogSourceSpawn), "[spawn] tmux new-window\noutput\n--- ok after")
+	assert.Contains(t, read(LogSourceRecycle), "[recycle] git reset --hard\noutput\n--- ok after")
+}
+
+func TestCreateSession_SpawnProfile(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	newSvc := func(exec *executil.RecordingExecutor, rules ...config.Rule) *Service {
+		cfg := &config.Config{
+			DataDir: t.TempDir(),
+			GitPath: "git",
+			Commands: config.Commands{
+				Spawn:      []string{"spawn {{ .Name }}"},
+				BatchSpawn: []string{"batch {{ .Name }}"},
+				SpawnProfiles: map[string][]string{
+					"review": {"review {{ .Name }}"},
+					"shell":  {"shell {{ .Name }}"},
+				},
+			},
+			Rules: rules,
+		}
+		return New(newMockStore(), &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	}
+
+	tests := []struct {
+		name  string
+		opts  CreateOptions
+		rules []config.Rule
+		want  string
+	}{
+		{name: "default", want: "spawn s"},
+		{name: "batch", opts: CreateOptions{UseBatchSpawn: true}, want: "batch s"},
+		{name: "rule default", rules: []config.Rule{{Spawn: "shell"}}, opts: CreateOptions{UseBatchSpawn: true}, want: "shell s"},
+		{name: "explicit wins", rules: []config.Rule{{Spawn: "shell"}}, opts: CreateOptions{SpawnProfile: "review"}, want: "review s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := &executil.RecordingExecutor{}
+			opts := tt.opts
+			opts.Name, opts.Remote = "s", remote
+
+			_, err := newSvc(exec, tt.rules...).CreateSession(context.Background(), opts)
+			require.NoError(t, err)
+			assert.Equal(t, []string{tt.want}, shellCommands(exec))
+		})
+	}
+
+	t.Run("unknown profile", func(t *testing.T) {
+		exec := &executil.RecordingExecutor{}
+		_, err := newSvc(exec).CreateSession(context.Background(), CreateOptions{Name: "s", Remote: remote, SpawnProfile: "nope"})
+		require.ErrorContains(t, err, `unknown spawn profile "nope"`)
+		assert.Empty(t, exec.Commands)
+	})
+}
+
+func TestCreateSession_PromptPreamble(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{BatchSpawn: []string{"batch {{ .Prompt }}"}},
+		Rules: []config.Rule{
+			{Pattern: ".*/hive\\.git$", PromptPreamble: "Run make lint before committing."},
+			{Pattern: ".*/other\\.git$", PromptPreamble: "Not for this repo."},
+		},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{Name: "task", Remote: remote, Prompt: "fix it", UseBatchSpawn: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Run make lint before committing.\n\nfix it", created.GetMeta(session.MetaPrompt))
+
+	created, err = svc.CreateSession(context.Background(), CreateOptions{Name: "bare", Remote: remote})
+	require.NoError(t, err)
+	assert.Empty(t, created.GetMeta(session.MetaPrompt), "no prompt, no preamble")
+
+	// A prompt previewed with PromptFor is passed on as reviewed
+	prompt := svc.PromptFor(remote, "fix it")
+	assert.Equal(t, "Run make lint before committing.\n\nfix it", prompt)
+	created, err = svc.CreateSession(context.Background(), CreateOptions{Name: "previewed", Remote: remote, Prompt: prompt, PromptPreambled: true, UseBatchSpawn: true})
+	require.NoError(t, err)
+	assert.Equal(t, prompt, created.GetMeta(session.MetaPrompt), "the preamble is not added twice")
+
+	plans, err := svc.PlanSessions(context.Background(), []CreateOptions{
+		{Name: "planned", Remote: remote, Prompt: "fix it", UseBatchSpawn: true},
+		{Name: "previewed", Remote: remote, Prompt: prompt, PromptPreambled: true, UseBatchSpawn: true},
+	})
+	require.NoError(t, err)
+	for _, plan := range plans {
+		assert.Equal(t, prompt, plan.Prompt, plan.Name)
+		assert.Equal(t, []string{"batch " + prompt}, plan.Spawn, plan.Name)
+	}
+}
+
+// stepRecorder is a StepReporter recording the steps it is told about.
+type stepRecorder struct {
+	events []string
+}
+
+func (r *stepRecorder) StartStep(step, _ string) io.Writer {
+	r.events = append(r.events, "start "+step)
+	return io.Discard
+}
+
+func (r *stepRecorder) EndStep(step, _ string, err error) {
+	r.events = append(r.events, fmt.Sprintf("end %s %v", step, err))
+}
+
+func TestCreateSession_StepReporter(t *testing.T) {
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"spawn {{ .Name }}"}},
+		Rules:    []config.Rule{{Pattern: ".*", Commands: []string{"make setup"}}},
+	}
+	svc := New(newMockStore(), &mockGit{}, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	rec := &stepRecorder{}
+	ctx := WithStepReporter(context.Background(), rec)
+	_, err := svc.CreateSession(ctx, CreateOptions{Name: "task", Remote: "https://github.com/hay-kot/hive.git"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"start clone", "end clone <nil>",
+		"start rule_commands", "end rule_commands <nil>",
+		"start spawn", "end spawn <nil>",
+	}, rec.events)
+}
+
+func TestCreateSession_ValidatesName(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	svc := newTestService(t, newMockStore(), nil)
+
+	_, err := svc.CreateSession(context.Background(), CreateOptions{Name: "fix\x00bug", Remote: remote})
+	require.ErrorContains(t, err, "control characters")
+
+	_, err = svc.CreateSession(context.Background(), CreateOptions{Name: strings.Repeat("long ", 60), Remote: remote})
+	require.ErrorContains(t, err, "use a shorter name")
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{Name: " cafe\u0301 ", Remote: remote})
+	require.NoError(t, err)
+	assert.Equal(t, "caf\u00e9", created.Name)
+}
+
+func TestCreateSession_Timings(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"spawn {{ .Name }}"}},
+		Rules: []config.Rule{
+			{Pattern: ".*", Commands: []string{"make setup"}, Hooks: config.RuleHooks{PostCreate: []string{"make seed"}}},
+		},
+	}
+	svc := New(store, &mockGit{}, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{Name: "task", Remote: remote})
+	require.NoError(t, err)
+
+	var steps []string
+	for _, timing := range created.Timings {
+		steps = append(steps, timing.Step+" "+timing.Rule)
+	}
+	assert.Equal(t, []string{"clone ", "rule_commands .*", "post_create .*", "spawn "}, steps)
+
+	saved, err := store.Get(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Timings, saved.Timings)
+}
+
+func TestSpawnSession(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	exec := &executil.RecordingExecutor{}
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Commands: config.Commands{
+			Spawn:         []string{"spawn {{ .Name }}"},
+			BatchSpawn:    []string{"batch {{ .Prompt }}"},
+			SpawnProfiles: map[string][]string{"shell": {"shell {{ .Name }}"}},
+		},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:          "task",
+		Remote:        remote,
+		Prompt:        "fix it",
+		UseBatchSpawn: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fix it", created.GetMeta(session.MetaPrompt))
+
+	// mockGit does not clone, so give the session a directory to spawn in
+	created.Path = t.TempDir()
+	require.NoError(t, store.Save(context.Background(), *created))
+
+	exec.Reset()
+	require.NoError(t, svc.SpawnSession(context.Background(), created.ID, ""))
+	assert.Equal(t, []string{"batch fix it"}, shellCommands(exec))
+
+	exec.Reset()
+	require.NoError(t, svc.SpawnSession(context.Background(), created.ID, "shell"))
+	assert.Equal(t, []string{"shell task"}, shellCommands(exec))
+
+	require.ErrorContains(t, svc.SpawnSession(context.Background(), created.ID, "nope"), "unknown spawn profile")
+
+	created.State = session.StateRecycled
+	require.NoError(t, store.Save(context.Background(), *created))
+	require.ErrorContains(t, svc.SpawnSession(context.Background(), created.ID, ""), "not active")
+}
+
+func TestCreateSession_SpawnFailed(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	exec := &executil.RecordingExecutor{Errors: map[string]error{"sh": errors.New("exit status 1")}}
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"spawn {{ .Name }}"}},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(context.Background(), CreateOptions{Name: "task", Remote: remote})
+	require.ErrorIs(t, err, ErrSpawnFailed)
+	require.NotNil(t, created, "the saved session is returned with the spawn error")
+
+	saved, err := store.Get(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, session.StateActive, saved.State)
+
+	// mockGit does not clone, so give the session a directory to spawn in
+	saved.Path = t.TempDir()
+	require.NoError(t, store.Save(context.Background(), saved))
+
+	exec.Reset()
+	exec.Errors = nil
+	require.NoError(t, svc.SpawnSession(context.Background(), created.ID, ""))
+	assert.Equal(t, []string{"spawn task"}, shellCommands(exec))
+
+	all, err := store.List(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, all, 1, "retrying the spawn does not create another session")
+}
+
+func TestResolveSession(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	svc := New(store, &mockGit{}, &config.Config{DataDir: t.TempDir(), GitPath: "git"}, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	for _, s := range []session.Session{
+		{ID: "a1", Name: "alpha", Slug: "alpha", State: session.StateActive},
+		{ID: "b2", Name: "Bug Fix", Slug: "bug-fix", State: session.StateActive},
+		{ID: "c3", Name: "dup", Slug: "dup", State: session.StateActive},
+		{ID: "d4", Name: "dup", Slug: "dup", State: session.StateActive},
+		{ID: "e5", Name: "old", Slug: "old", State: session.StateRecycled},
+	} {
+		require.NoError(t, store.Save(ctx, s))
+	}
+
+	got, err := svc.ResolveSession(ctx, "a1")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", got.ID)
+
+	got, err = svc.ResolveSession(ctx, "bug-fix")
+	require.NoError(t, err)
+	assert.Equal(t, "b2", got.ID)
+
+	_, err = svc.ResolveSession(ctx, "dup")
+	require.ErrorIs(t, err, ErrAmbiguous)
+	require.ErrorContains(t, err, "matches 2 sessions")
+
+	_, err = svc.ResolveSession(ctx, "old")
+	require.ErrorIs(t, err, session.ErrNotFound)
+}
+
+func TestExecSession(t *testing.T) {
+	ctx := context.Background()
+	exec := &executil.RecordingExecutor{}
+	store := newMockStore()
+	svc := New(store, &mockGit{}, &config.Config{DataDir: t.TempDir(), GitPath: "git"}, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	sess := session.Session{
+		ID:     "abc123",
+		Name:   "task",
+		Slug:   "task",
+		Path:   t.TempDir(),
+		Remote: "https://github.com/hay-kot/hive.git",
+		State:  session.StateActive,
+	}
+	require.NoError(t, store.Save(ctx, sess))
+
+	require.NoError(t, svc.ExecSession(ctx, "task", io.Discard, io.Discard, "go", "test", "./..."))
+	require.Len(t, exec.Commands, 1)
+	got := exec.Commands[0]
+	assert.Equal(t, sess.Path, got.Dir)
+	assert.Equal(t, "go", got.Cmd)
+	assert.Equal(t, []string{"test", "./..."}, got.Args)
+	assert.Contains(t, got.Env, "HIVE_SESSION_ID=abc123")
+	assert.Contains(t, got.Env, "HIVE_PATH="+sess.Path)
+
+	sess.State = session.StateRecycled
+	require.NoError(t, store.Save(ctx, sess))
+	require.ErrorContains(t, svc.ExecSession(ctx, "abc123", io.Discard, io.Discard, "true"), "not active")
+}
+
+func TestOpenCommand(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	svc := New(store, &mockGit{}, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	dir := filepath.Join(t.TempDir(), "my repo")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, store.Save(ctx, session.Session{ID: "abc123", Name: "task", Path: dir, State: session.StateActive}))
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nvim")
+	got, err := svc.OpenCommand(ctx, "abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "nvim '"+dir+"'", got)
+
+	cfg.Commands.Open = "zed {{ .Path | shq }} # {{ .Name }}"
+	got, err = svc.OpenCommand(ctx, "task")
+	require.NoError(t, err)
+	assert.Equal(t, "zed '"+dir+"' # task", got)
+}
+
+func TestSessionURL(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	svc := New(store, &mockGit{}, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	require.NoError(t, store.Save(ctx, session.Session{ID: "abc123", Name: "task", Remote: "git@github.com:hay-kot/hive.git", Path: t.TempDir(), State: session.StateActive}))
+
+	got, err := svc.SessionURL(ctx, "task", "https://github.com/{{ .OwnerRepo }}/compare/{{ .DefaultBranch }}...{{ .Branch }}")
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/hay-kot/hive/compare/main...main", got)
+
+	_, err = svc.SessionURL(ctx, "task", "{{ .Missing }}")
+	require.Error(t, err)
+}
+
+func TestGC(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	svc := newTestService(t, store, cfg)
+
+	reposDir := cfg.ReposDir()
+	tracked := filepath.Join(reposDir, "hive-task-abc123")
+	orphan := filepath.Join(reposDir, "hive-crashed-xyz789")
+	fresh := filepath.Join(reposDir, "hive-creating-new123")
+	for _, dir := range []string{tracked, orphan, fresh} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(orphan, "file"), make([]byte, 10), 0o644))
+	old := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(orphan, old, old))
+
+	store.sessions["abc123"] = session.Session{ID: "abc123", Path: tracked, State: session.StateActive}
+	store.sessions["gone"] = session.Session{ID: "gone", Path: filepath.Join(reposDir, "hive-gone-gone"), State: session.StateRecycled}
+
+	result, err := svc.GC(ctx, GCOptions{MinAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, result.Orphans, 2)
+	assert.Equal(t, OrphanDirectory, result.Orphans[0].Kind)
+	assert.Equal(t, orphan, result.Orphans[0].Path)
+	assert.Equal(t, OrphanRecord, result.Orphans[1].Kind)
+	assert.Equal(t, "gone", result.Orphans[1].SessionID)
+	assert.Equal(t, int64(10), result.Reclaimed)
+	assert.DirExists(t, orphan)
+	assert.Contains(t, store.sessions, "gone")
+
+	result, err = svc.GC(ctx, GCOptions{Remove: true, MinAge: time.Hour})
+	require.NoError(t, err)
+	require.Len(t, result.Orphans, 2)
+	assert.True(t, result.Orphans[0].Removed)
+	assert.True(t, result.Orphans[1].Removed)
+	assert.NoDirExists(t, orphan)
+	assert.DirExists(t, fresh)
+	assert.DirExists(t, tracked)
+	assert.NotContains(t, store.sessions, "gone")
+	assert.Contains(t, store.sessions, "abc123")
+}
+
+func TestRecycleSession_TemplateData(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Commands: config.Commands{
+			Recycle: []string{"archive {{ .ID }} {{ .Name | shq }} {{ .Path }} {{ .Remote }} {{ .DefaultBranch }}"},
+		},
+	}
+	store := newMockStore()
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	ctx := context.Background()
+
+	created, err := svc.CreateSession(ctx, CreateOptions{Name: "Fix Bug", SessionID: "abc123", Remote: remote})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(created.Path, 0o755))
+
+	require.NoError(t, svc.RecycleSession(ctx, created.ID, nil))
+	assert.Equal(t, []string{"archive abc123 'Fix Bug' " + created.Path + " " + remote + " main"}, shellCommands(exec))
+}
+
+func TestCreateSession_Traced(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	svc := newTestService(t, newMockStore(), nil)
+	created, err := svc.CreateSession(context.Background(), CreateOptions{
+		Name:   "traced",
+		Remote: "https://github.com/hay-kot/hive.git",
+	})
+	require.NoError(t, err)
+
+	var root sdktrace.ReadOnlySpan
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		if span.Name() == "hive.CreateSession" {
+			root = span
+		}
+	}
+	assert.Subset(t, names, []string{"hive.find_recyclable", "git.clone", "hive.rules", "hive.hooks.post_create", "store.save", "hive.spawn"})
+	require.NotNil(t, root)
+	assert.Contains(t, root.Attributes(), attribute.String("hive.session.id", created.ID))
+
+	// Every step is a child of the CreateSession span
+	for _, span := range recorder.Ended() {
+		if span.Name() != "hive.CreateSession" {
+			assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID(), span.Name())
+		}
+	}
+}
+
+// invalidRepoGit reports the repositories in invalid as corrupted.
+type invalidRepoGit struct {
+	mockGit
+	invalid map[string]bool
+}
+
+func (g *invalidRepoGit) IsValidRepo(_ context.Context, path string) error {
+	if g.invalid[path] {
+		return assert.AnError
+	}
+	return nil
+}
+
+func TestFindValidRecyclable(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	now := time.Now()
+
+	store := newMockStore()
+	recycled := func(id string, age time.Duration) {
+		store.sessions[id] = session.Session{
+			ID:        id,
+			Name:      id,
+			Path:      "/repos/" + id,
+			Remote:    remote,
+			State:     session.StateRecycled,
+			UpdatedAt: now.Add(-age),
+		}
+	}
+	recycled("old", 3*time.Hour)
+	recycled("newest-broken", time.Minute)
+	recycled("recent", time.Hour)
+	recycled("older", 2*time.Hour)
+
+	g := &invalidRepoGit{invalid: map[string]bool{"/repos/newest-broken": true}}
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	svc := New(store, g, cfg, nil, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	got := svc.findValidRecyclable(context.Background(), remote)
+	require.NotNil(t, got)
+	assert.Equal(t, "recent", got.ID)
+	assert.Equal(t, session.StateCorrupted, store.sessions["newest-broken"].State)
+
+	// The claimed session is skipped by the next create
+	next := svc.findValidRecyclable(context.Background(), remote)
+	require.NotNil(t, next)
+	assert.Equal(t, "older", next.ID)
+}
+
+// blockingGit holds IsValidRepo until release is closed, reporting the
+// repositories in invalid as corrupted.
+type blockingGit struct {
+	mockGit
+	invalid map[string]bool
+	started chan struct{}
+	release chan struct{}
+}
+
+func (g *blockingGit) IsValidRepo(ctx context.Context, path string) error {
+	g.started <- struct{}{}
+	<-g.release
+	if g.invalid[path] {
+		return assert.AnError
+	}
+	return nil
+}
+
+func TestFindValidRecyclable_ValidatesUnlocked(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	newService := func(t *testing.T, invalid bool) (*Service, *mockStore, *blockingGit) {
+		store := newMockStore()
+		store.sessions["r1"] = session.Session{ID: "r1", Path: "/repos/r1", Remote: remote, State: session.StateRecycled}
+		g := &blockingGit{
+			invalid: map[string]bool{"/repos/r1": invalid},
+			started: make(chan struct{}),
+			release: make(chan struct{}),
+		}
+		cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+		return New(store, g, cfg, nil, zerolog.New(io.Discard), io.Discard, io.Discard), store, g
+	}
+
+	find := func(svc *Service) <-chan *session.Session {
+		found := make(chan *session.Session, 1)
+		go func() { found <- svc.findValidRecyclable(context.Background(), remote) }()
+		return found
+	}
+
+	t.Run("claims stay available during validation", func(t *testing.T) {
+		svc, _, g := newService(t, false)
+		found := find(svc)
+		<-g.started
+
+		require.True(t, svc.claimMu.TryLock(), "claimMu is not held while validating")
+		svc.claimMu.Unlock()
+
+		close(g.release)
+		got := <-found
+		require.NotNil(t, got)
+		assert.Equal(t, "r1", got.ID)
+	})
+
+	t.Run("skips a candidate claimed during validation", func(t *testing.T) {
+		svc, _, g := newService(t, false)
+		found := find(svc)
+		<-g.started
+
+		svc.claimMu.Lock()
+		svc.claimed["r1"] = struct{}{}
+		svc.claimMu.Unlock()
+
+		close(g.release)
+		assert.Nil(t, <-found)
+	})
+
+	t.Run("does not mark a session reused during validation", func(t *testing.T) {
+		svc, store, g := newService(t, true)
+		found := find(svc)
+		<-g.started
+
+		// Another create reuses it and moves its directory
+		store.sessions["r1"] = session.Session{ID: "r1", Path: "/repos/task-r1", Remote: remote, State: session.StateActive}
+
+		close(g.release)
+		assert.Nil(t, <-found)
+		assert.Equal(t, session.StateActive, store.sessions["r1"].State)
+		assert.Empty(t, svc.claimed, "claims taken to mark sessions are released")
+	})
+}
+
+func TestCreateSession_InterruptedMarksCorrupted(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	exec := &executil.RecordingExecutor{Errors: map[string]error{"sh": context.Canceled}}
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Rules:   []config.Rule{{Commands: []string{"npm install"}}},
+	}
+	store := newMockStore()
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.CreateSession(ctx, CreateOptions{Name: "interrupted", SessionID: "abc123", Remote: remote})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "run 'hive prune'")
+
+	sess, ok := store.sessions["abc123"]
+	require.True(t, ok, "interrupted session is recorded")
+	assert.Equal(t, session.StateCorrupted, sess.State)
+}
+
+// failingCloneGit fails every clone, after creating dest when partial is set.
+type failingCloneGit struct {
+	mockGit
+	partial bool
+	clones  int
+}
+
+func (m *failingCloneGit) Clone(_ context.Context, _, dest string) error {
+	m.clones++
+	if m.partial {
+		if err := os.MkdirAll(filepath.Join(dest, ".git"), 0o755); err != nil {
+			return err
+		}
+	}
+	return errors.New("exit status 128")
+}
+
+func TestCreateSession_KeepsExistingDirectory(t *testing.T) {
+	ctx := context.Background()
+	const remote = "https://github.com/hay-kot/hive.git"
+
+	newService := func(t *testing.T, g *failingCloneGit) (*Service, *mockStore) {
+		cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+		store := newMockStore()
+		return New(store, g, cfg, &executil.RecordingExecutor{}, zerolog.New(io.Discard), io.Discard, io.Discard), store
+	}
+
+	t.Run("existing directory", func(t *testing.T) {
+		g := &failingCloneGit{}
+		svc, _ := newService(t, g)
+
+		dir, err := svc.sessionDir(remote, "work", "abc123")
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		work := filepath.Join(dir, "WORK.txt")
+		require.NoError(t, os.WriteFile(work, []byte("wip"), 0o644))
+
+		_, err = svc.CreateSession(ctx, CreateOptions{Name: "work", SessionID: "abc123", Remote: remote})
+		require.ErrorContains(t, err, "already exists")
+		assert.Zero(t, g.clones, "nothing is cloned over the directory")
+
+		data, err := os.ReadFile(work)
+		require.NoError(t, err, "the directory is left untouched")
+		assert.Equal(t, "wip", string(data))
+	})
+
+	t.Run("existing session", func(t *testing.T) {
+		g := &failingCloneGit{}
+		svc, store := newService(t, g)
+		store.sessions["abc123"] = session.Session{ID: "abc123", Name: "other", State: session.StateActive}
+
+		_, err := svc.CreateSession(ctx, CreateOptions{Name: "work", SessionID: "abc123", Remote: remote})
+		require.ErrorContains(t, err, "session abc123 already exists")
+		assert.Zero(t, g.clones)
+	})
+
+	t.Run("failed clone clears its own directory", func(t *testing.T) {
+		g := &failingCloneGit{partial: true}
+		svc, _ := newService(t, g)
+
+		_, err := svc.CreateSession(ctx, CreateOptions{Name: "work", SessionID: "abc123", Remote: remote})
+		require.ErrorContains(t, err, "clone repository")
+
+		dir, err := svc.sessionDir(remote, "work", "abc123")
+		require.NoError(t, err)
+		assert.NoDirExists(t, dir)
+	})
+}
+
+func TestMoveDir(t *testing.T) {
+	// A destination whose parent is missing cannot be renamed to, which
+	// exercises the copy used across filesystems
+	newSource := func(t *testing.T) string {
+		src := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(src, ".git", "refs"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "notes.txt"), []byte("wip"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "run.sh"), []byte("#!/bin/sh\n"), 0o755))
+		require.NoError(t, os.Symlink("notes.txt", filepath.Join(src, "link")))
+		return src
+	}
+
+	// readOnly adds a read-only directory with a file to src, which root
+	// could still remove
+	readOnly := func(t *testing.T, src string) string {
+		dir := filepath.Join(src, "vendor")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "mod.go"), []byte("package mod\n"), 0o444))
+		require.NoError(t, os.Chmod(dir, 0o555))
+		t.Cleanup(func() { _ = os.Chmod(dir, 0o755) })
+		return dir
+	}
+
+	t.Run("copies when the rename fails", func(t *testing.T) {
+		src := newSource(t)
+		dst := filepath.Join(t.TempDir(), "missing", "dir")
+
+		require.NoError(t, moveDir(src, dst))
+		assert.NoDirExists(t, src)
+		assert.DirExists(t, filepath.Join(dst, ".git", "refs"))
+
+		data, err := os.ReadFile(filepath.Join(dst, "notes.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "wip", string(data))
+
+		info, err := os.Stat(filepath.Join(dst, "run.sh"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+		link, err := os.Readlink(filepath.Join(dst, "link"))
+		require.NoError(t, err)
+		assert.Equal(t, "notes.txt", link)
+	})
+
+	t.Run("copies read-only directories", func(t *testing.T) {
+		src := newSource(t)
+		readOnly(t, src)
+		dst := filepath.Join(t.TempDir(), "missing", "dir")
+
+		err := moveDir(src, dst)
+		t.Cleanup(func() { _ = os.Chmod(filepath.Join(dst, "vendor"), 0o755) })
+		if err != nil {
+			require.ErrorIs(t, err, errSourceLeft, "only removing the read-only source may fail")
+		}
+		assert.FileExists(t, filepath.Join(dst, "vendor", "mod.go"))
+
+		info, err := os.Stat(filepath.Join(dst, "vendor"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o555), info.Mode().Perm())
+	})
+
+	t.Run("keeps the copy when the source cannot be removed", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root removes read-only directories")
+		}
+		src := newSource(t)
+		readOnly(t, src)
+		dst := filepath.Join(t.TempDir(), "missing", "dir")
+
+		err := moveDir(src, dst)
+		t.Cleanup(func() { _ = os.Chmod(filepath.Join(dst, "vendor"), 0o755) })
+		require.ErrorIs(t, err, errSourceLeft)
+		assert.FileExists(t, filepath.Join(dst, "notes.txt"))
+		assert.FileExists(t, filepath.Join(dst, "vendor", "mod.go"))
+		assert.NoFileExists(t, filepath.Join(src, "notes.txt"), "the source was partly removed")
+	})
+
+	t.Run("keeps the source when the copy fails", func(t *testing.T) {
+		src := newSource(t)
+		require.NoError(t, syscall.Mkfifo(filepath.Join(src, "pipe"), 0o644))
+		dst := filepath.Join(t.TempDir(), "missing", "dir")
+
+		require.ErrorContains(t, moveDir(src, dst), "unsupported file type")
+		assert.FileExists(t, filepath.Join(src, "notes.txt"))
+		assert.NoDirExists(t, dst)
+	})
+}
+
+func TestDeleteSession_Trash(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("moves to trash and restores", func(t *testing.T) {
+		store := newMockStore()
+		svc := newTestService(t, store, nil)
+
+		dir := filepath.Join(svc.cfg().ReposDir(), "work-abc")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip"), 0o644))
+		store.sessions["abc"] = session.Session{ID: "abc", Name: "work", Path: dir, State: session.StateActive}
+
+		require.NoError(t, svc.DeleteSession(ctx, "abc"))
+		assert.NoDirExists(t, dir)
+		assert.NotContains(t, store.sessions, "abc")
+
+		trash, err := svc.Trash()
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, "abc", trash[0].Session.ID)
+		assert.Equal(t, trash[0].DeletedAt.Add(config.DefaultTrashRetention), trash[0].ExpiresAt)
+
+		restored, err := svc.RestoreSession(ctx, "ab")
+		require.NoError(t, err)
+		assert.Equal(t, "work", restored.Name)
+		assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+		assert.Contains(t, store.sessions, "abc")
+
+		trash, err = svc.Trash()
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("refuses to overwrite a reused directory", func(t *testing.T) {
+		store := newMockStore()
+		svc := newTestService(t, store, nil)
+
+		dir := filepath.Join(svc.cfg().ReposDir(), "work-abc")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		store.sessions["abc"] = session.Session{ID: "abc", Path: dir, State: session.StateActive}
+
+		require.NoError(t, svc.DeleteSession(ctx, "abc"))
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+
+		_, err := svc.RestoreSession(ctx, "abc")
+		require.ErrorContains(t, err, "already exists")
+		assert.NotContains(t, store.sessions, "abc")
+	})
+
+	t.Run("recycled sessions and disabled trash remove outright", func(t *testing.T) {
+		store := newMockStore()
+		retention := time.Duration(0)
+		cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+		svc := newTestService(t, store, cfg)
+
+		recycled := t.TempDir()
+		store.sessions["r"] = session.Session{ID: "r", Path: recycled, State: session.StateRecycled}
+		require.NoError(t, svc.DeleteSession(ctx, "r"))
+		assert.NoDirExists(t, recycled)
+
+		cfg.Trash.Retention = &retention
+		active := t.TempDir()
+		store.sessions["a"] = session.Session{ID: "a", Path: active, State: session.StateActive}
+		require.NoError(t, svc.DeleteSession(ctx, "a"))
+		assert.NoDirExists(t, active)
+
+		trash, err := svc.Trash()
+		require.NoError(t, err)
+		assert.Empty(t, trash)
+	})
+
+	t.Run("expires old entries", func(t *testing.T) {
+		store := newMockStore()
+		retention := time.Hour
+		cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git", Trash: config.TrashConfig{Retention: &retention}}
+		svc := newTestService(t, store, cfg)
+
+		old := filepath.Join(cfg.TrashDir(), fmt.Sprintf("old-%d", time.Now().Add(-2*time.Hour).UnixNano()))
+		require.NoError(t, os.MkdirAll(old, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(old, trashRecordFile), []byte(`{"id":"old"}`), 0o644))
+
+		store.sessions["new"] = session.Session{ID: "new", Path: t.TempDir(), State: session.StateActive}
+		require.NoError(t, svc.DeleteSession(ctx, "new"))
+
+		assert.NoDirExists(t, old)
+		trash, err := svc.Trash()
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		assert.Equal(t, "new", trash[0].Session.ID)
+	})
+}
+
+// monorepoGit clones into a directory containing the given subdirectories.
+type monorepoGit struct {
+	mockGit
+	dirs []string
+}
+
+func (m *monorepoGit) Clone(_ context.Context, _, dest string) error {
+	for _, d := range m.dirs {
+		if err := os.MkdirAll(filepath.Join(dest, d), 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestCreateSession_Subdir(t *testing.T) {
+	ctx := context.Background()
+	const remote = "https://github.com/hay-kot/mono.git"
+
+	exec := &executil.RecordingExecutor{}
+	cfg := &config.Config{
+		DataDir:  t.TempDir(),
+		GitPath:  "git",
+		Commands: config.Commands{Spawn: []string{"open {{ .Path }} {{ .Root }}"}},
+	}
+	store := newMockStore()
+	svc := New(store, &monorepoGit{dirs: []string{"services/api"}}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	created, err := svc.CreateSession(ctx, CreateOptions{Name: "api", Remote: remote, Subdir: "services/api/"})
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("services", "api"), created.GetMeta(session.MetaSubdir))
+
+	workDir := filepath.Join(created.Path, "services", "api")
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, []string{"-c", "open " + workDir + " " + created.Path}, exec.Commands[0].Args)
+
+	require.NoError(t, svc.ExecSession(ctx, created.ID, io.Discard, io.Discard, "ls"))
+	got := exec.Commands[len(exec.Commands)-1]
+	assert.Equal(t, workDir, got.Dir)
+	assert.Contains(t, got.Env, "HIVE_PATH="+created.Path)
+	assert.Contains(t, got.Env, "HIVE_WORKDIR="+workDir)
+
+	_, err = svc.CreateSession(ctx, CreateOptions{Name: "web", Remote: remote, Subdir: "services/web"})
+	require.ErrorContains(t, err, `subdirectory "services/web" not found`)
+
+	_, err = svc.CreateSession(ctx, CreateOptions{Name: "up", Remote: remote, Subdir: "../other"})
+	require.ErrorContains(t, err, "inside the repository")
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/hay-kot/hive/pkg/executil"
 	"github.com/hay-kot/hive/pkg/tmpl"
@@ -13,13 +14,16 @@ import (
 
 // SpawnData is the template context for spawn commands.
 type SpawnData struct {
-	Path       string // Absolute path to session directory
-	Name       string // Session name (display name)
-	Prompt     string // User-provided prompt (batch only)
-	Slug       string // Session slug (URL-safe version of name)
-	ContextDir string // Path to context directory
-	Owner      string // Repository owner
-	Repo       string // Repository name
+	Path        string // Absolute path to the session's working directory
+	Root        string // Absolute path to the repository root; differs from Path with --subdir
+	Name        string // Session name (display name)
+	Prompt      string // User-provided prompt (batch only)
+	Slug        string // Session slug (URL-safe version of name)
+	ContextDir  string // Path to context directory
+	Owner       string // Repository owner
+	Repo        string // Repository name
+	ToolSession string // AI tool session ID linked with "hive session link"
+	ReviewOf    string // ID of the session a read-only review session shares a directory with
 }
 
 // Spawner handles terminal spawning with template rendering.
@@ -42,6 +46,9 @@ func NewSpawner(log zerolog.Logger, executor executil.Executor, stdout, stderr i
 
 // Spawn executes spawn commands sequentially with template rendering.
 func (s *Spawner) Spawn(ctx context.Context, commands []string, data SpawnData) error {
+	record := commandLogFrom(ctx)
+	stdout, stderr := teeOutput(ctx, record.tee(s.stdout)), teeOutput(ctx, record.tee(s.stderr))
+
 	for _, cmdTmpl := range commands {
 		s.log.Debug().Str("command", cmdTmpl).Msg("executing spawn command")
 
@@ -50,7 +57,12 @@ func (s *Spawner) Spawn(ctx context.Context, commands []string, data SpawnData)
 			return fmt.Errorf("render spawn command %q: %w", cmdTmpl, err)
 		}
 
-		if err := s.executor.RunStream(ctx, s.stdout, s.stderr, "sh", "-c", rendered); err != nil {
+		record.start("spawn", rendered)
+		begin := time.Now()
+		err = s.executor.RunStream(ctx, stdout, stderr, "sh", "-c", rendered)
+		record.finish(begin, err)
+
+		if err != nil {
 			return fmt.Errorf("execute spawn command %q: %w", rendered, err)
 		}
 	}
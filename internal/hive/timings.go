@@ -0,0 +1,67 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// timingsKey is the context key of the timings recorded while creating a
+// session.
+type timingsKey struct{}
+
+// withTimings returns a context in which timeStep appends to timings.
+func withTimings(ctx context.Context, timings *[]session.Timing) context.Context {
+	return context.WithValue(ctx, timingsKey{}, timings)
+}
+
+// StepReporter is told as session creation moves through its steps, so a
+// caller such as hive new can show progress.
+type StepReporter interface {
+	// StartStep is called before step runs, for the rule with the given
+	// pattern. It returns where progress output of the step goes, such as
+	// git's clone progress, or nil to drop it.
+	StartStep(step, rule string) io.Writer
+	// EndStep is called when step finishes, with its error.
+	EndStep(step, rule string, err error)
+}
+
+// stepReporterKey is the context key of the StepReporter.
+type stepReporterKey struct{}
+
+// WithStepReporter returns a context in which the steps of creating a session
+// are reported to r.
+func WithStepReporter(ctx context.Context, r StepReporter) context.Context {
+	return context.WithValue(ctx, stepReporterKey{}, r)
+}
+
+// timeStep runs fn and, when ctx records timings, records how long it took
+// as step, for the rule with the given pattern. Failed steps are recorded too.
+// The step is reported to the StepReporter of ctx, if any, and fn gets a
+// context streaming git progress to it.
+func timeStep(ctx context.Context, step, rule string, fn func(context.Context) error) error {
+	if r, ok := ctx.Value(stepReporterKey{}).(StepReporter); ok {
+		if w := r.StartStep(step, rule); w != nil {
+			ctx = git.WithProgress(ctx, w)
+		}
+		inner := fn
+		fn = func(ctx context.Context) error {
+			err := inner(ctx)
+			r.EndStep(step, rule, err)
+			return err
+		}
+	}
+
+	timings, ok := ctx.Value(timingsKey{}).(*[]session.Timing)
+	if !ok {
+		return fn(ctx)
+	}
+
+	start := time.Now()
+	err := fn(ctx)
+	*timings = append(*timings, session.Timing{Step: step, Rule: rule, DurationMS: time.Since(start).Milliseconds()})
+	return err
+}
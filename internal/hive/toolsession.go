@@ -0,0 +1,94 @@
+package hive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+// toolSessionRe limits tool session IDs to characters that are safe to
+// render unquoted into spawn commands.
+var toolSessionRe = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// claudeProjectRe matches the characters Claude Code replaces with "-" when
+// naming a project's transcript directory after its path.
+var claudeProjectRe = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// LinkToolSession records the AI tool's session ID on a hive session, so
+// spawn commands can resume the tool's conversation with {{ .ToolSession }}.
+func (s *Service) LinkToolSession(ctx context.Context, ref, toolSession string) (session.Session, error) {
+	if !toolSessionRe.MatchString(toolSession) {
+		return session.Session{}, fmt.Errorf("invalid tool session ID %q", toolSession)
+	}
+
+	sess, err := s.ResolveSession(ctx, ref)
+	if err != nil {
+		return session.Session{}, fmt.Errorf("get session: %w", err)
+	}
+	if sess.GetMeta(session.MetaToolSession) == toolSession {
+		return sess, nil
+	}
+
+	sess.SetMeta(session.MetaToolSession, toolSession)
+	sess.UpdatedAt = time.Now()
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return session.Session{}, fmt.Errorf("save session: %w", err)
+	}
+
+	s.log.Debug().Str("session_id", sess.ID).Str("tool_session", toolSession).Msg("linked tool session")
+	return sess, nil
+}
+
+// DetectToolSession returns the ID of the most recent Claude Code
+// conversation started in dir, read from the transcripts Claude Code keeps
+// under $CLAUDE_CONFIG_DIR (default ~/.claude). Returns "" if there is none.
+func DetectToolSession(dir string) (string, error) {
+	root := os.Getenv("CLAUDE_CONFIG_DIR")
+	if root == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("find home directory: %w", err)
+		}
+		root = filepath.Join(home, ".claude")
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	projectDir := filepath.Join(root, "projects", claudeProjectRe.ReplaceAllString(abs, "-"))
+
+	entries, err := os.ReadDir(projectDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("read transcripts: %w", err)
+	}
+
+	var (
+		newest   string
+		newestAt time.Time
+	)
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || e.IsDir() || !toolSessionRe.MatchString(id) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(newestAt) {
+			newest, newestAt = id, info.ModTime()
+		}
+	}
+	return newest, nil
+}
@@ -0,0 +1,101 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkToolSession(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	svc := newTestService(t, store, nil)
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "task", State: session.StateActive}
+
+	sess, err := svc.LinkToolSession(ctx, "task", "0b6f3c1e-1234-4a5b-9c8d-abcdef012345")
+	require.NoError(t, err)
+	assert.Equal(t, "0b6f3c1e-1234-4a5b-9c8d-abcdef012345", sess.GetMeta(session.MetaToolSession))
+	saved := store.sessions["abc"]
+	assert.Equal(t, "0b6f3c1e-1234-4a5b-9c8d-abcdef012345", saved.GetMeta(session.MetaToolSession))
+
+	_, err = svc.LinkToolSession(ctx, "abc", "x; rm -rf /")
+	require.ErrorContains(t, err, "invalid tool session ID")
+}
+
+func TestDetectToolSession(t *testing.T) {
+	claudeDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	dir := filepath.Join(t.TempDir(), "hive-fix.auth-abc")
+	id, err := DetectToolSession(dir)
+	require.NoError(t, err)
+	assert.Empty(t, id, "no transcripts yet")
+
+	project := filepath.Join(claudeDir, "projects", claudeProjectRe.ReplaceAllString(dir, "-"))
+	require.NoError(t, os.MkdirAll(project, 0o755))
+	for i, name := range []string{"older.jsonl", "newer.jsonl", "notes.txt"} {
+		path := filepath.Join(project, name)
+		require.NoError(t, os.WriteFile(path, nil, 0o644))
+		mtime := time.Now().Add(time.Duration(i-3) * time.Minute)
+		require.NoError(t, os.Chtimes(path, mtime, mtime))
+	}
+
+	id, err = DetectToolSession(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "newer", id)
+}
+
+func TestResumeSession(t *testing.T) {
+	ctx := context.Background()
+	claudeDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", claudeDir)
+
+	exec := &executil.RecordingExecutor{}
+	store := newMockStore()
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Commands: config.Commands{
+			Spawn:         []string{"spawn {{ .Name }}"},
+			SpawnProfiles: map[string][]string{"shell": {"shell {{ .Name }}"}},
+		},
+	}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	dir := t.TempDir()
+	store.sessions["abc"] = session.Session{ID: "abc", Name: "task", Path: dir, State: session.StateActive,
+		Metadata: map[string]string{session.MetaPrompt: "fix it"}}
+
+	// Without commands.resume or a transcript, the spawn commands run again
+	_, err := svc.ResumeSession(ctx, "task", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"spawn task"}, shellCommands(exec))
+
+	project := filepath.Join(claudeDir, "projects", claudeProjectRe.ReplaceAllString(dir, "-"))
+	require.NoError(t, os.MkdirAll(project, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(project, "conv-1.jsonl"), nil, 0o644))
+	cfg.Commands.Resume = []string{"claude --resume {{ .ToolSession }} {{ .Prompt }}"}
+
+	exec.Reset()
+	sess, err := svc.ResumeSession(ctx, "abc", "")
+	require.NoError(t, err)
+	assert.Equal(t, "conv-1", sess.GetMeta(session.MetaToolSession))
+	saved := store.sessions["abc"]
+	assert.Equal(t, "conv-1", saved.GetMeta(session.MetaToolSession))
+	assert.Equal(t, []string{"claude --resume conv-1 fix it"}, shellCommands(exec))
+
+	exec.Reset()
+	_, err = svc.ResumeSession(ctx, "abc", "shell")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shell task"}, shellCommands(exec))
+}
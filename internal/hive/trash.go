@@ -0,0 +1,318 @@
+package hive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// Files inside a trash entry.
+const (
+	trashRecordFile = "session.json"
+	trashDirName    = "dir"
+)
+
+// TrashedSession is a deleted session held in the trash.
+type TrashedSession struct {
+	Session   session.Session `json:"session"`
+	DeletedAt time.Time       `json:"deleted_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Path      string          `json:"path"` // trash entry directory
+}
+
+// trashSession moves a session's directory into the trash alongside a copy
+// of its record, so RestoreSession can put both back. If the directory cannot
+// be moved, the entry is removed and the session is left as it was; once it
+// is fully copied into the entry, the entry is kept even if what is left of
+// the directory cannot be removed. Entries are named <id>-<unix nanos> so
+// deleting a restored session again never collides.
+func (s *Service) trashSession(sess session.Session) error {
+	entry := filepath.Join(s.cfg().TrashDir(), fmt.Sprintf("%s-%d", sess.ID, time.Now().UnixNano()))
+	if err := os.MkdirAll(entry, 0o755); err != nil {
+		return fmt.Errorf("create trash entry: %w", err)
+	}
+
+	data, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode session: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(entry, trashRecordFile), data, 0o644); err != nil {
+		return fmt.Errorf("write trash record: %w", err)
+	}
+
+	if _, err := os.Lstat(sess.Path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	err = moveDir(sess.Path, filepath.Join(entry, trashDirName))
+	if errors.Is(err, errSourceLeft) {
+		// The entry holds the only complete copy now
+		s.log.Warn().Err(err).Str("session_id", sess.ID).Str("entry", entry).Msg("session moved to trash, but its directory was not fully removed")
+		return nil
+	}
+	if err != nil {
+		// Leave the session and its directory as they were
+		_ = os.RemoveAll(entry)
+		return fmt.Errorf("move directory to trash: %w", err)
+	}
+	return nil
+}
+
+// errSourceLeft is returned by moveDir when src was copied to dst but could
+// not be fully removed afterwards. dst is complete and must be kept.
+var errSourceLeft = errors.New("moved, but the source was not fully removed")
+
+// moveDir moves the directory src to dst. When src cannot be renamed, as
+// across filesystems, it is copied to dst and removed only once the copy is
+// complete; a failed copy is removed again and src is left untouched. If
+// removing src fails after the copy, the error matches errSourceLeft.
+func moveDir(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyTree(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return err
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("%w: %w", errSourceLeft, err)
+	}
+	return nil
+}
+
+// copyTree copies the directory src to dst, keeping file modes and
+// symlinks. It fails on files it cannot copy faithfully, such as sockets.
+// Directories stay writable until their contents are copied, so read-only
+// ones get their mode only once the copy is complete.
+func copyTree(src, dst string) error {
+	type dirMode struct {
+		path string
+		perm fs.FileMode
+	}
+	var dirs []dirMode
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			dirs = append(dirs, dirMode{target, info.Mode().Perm()})
+			return os.MkdirAll(target, 0o700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return copyRegular(path, target, info.Mode().Perm())
+		default:
+			return fmt.Errorf("cannot copy %s: unsupported file type %s", path, d.Type())
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	// Deepest first, so a parent is still writable while its children change
+	for _, dir := range slices.Backward(dirs) {
+		if err := os.Chmod(dir.path, dir.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyRegular copies the regular file src to a new file dst with mode perm.
+func copyRegular(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close() //nolint:errcheck
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Trash lists deleted sessions that can still be restored, newest first.
+func (s *Service) Trash() ([]TrashedSession, error) {
+	entries, err := os.ReadDir(s.cfg().TrashDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trash: %w", err)
+	}
+
+	retention := s.cfg().Trash.RetentionPeriod()
+	var out []TrashedSession
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := readTrashEntry(filepath.Join(s.cfg().TrashDir(), e.Name()))
+		if err != nil {
+			s.log.Warn().Err(err).Str("entry", e.Name()).Msg("skipping unreadable trash entry")
+			continue
+		}
+		t.ExpiresAt = t.DeletedAt.Add(retention)
+		out = append(out, t)
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].DeletedAt.After(out[j].DeletedAt) })
+	return out, nil
+}
+
+// readTrashEntry loads the session record of a trash entry and derives the
+// deletion time from the entry name.
+func readTrashEntry(path string) (TrashedSession, error) {
+	name := filepath.Base(path)
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return TrashedSession{}, fmt.Errorf("malformed trash entry name %q", name)
+	}
+	nanos, err := strconv.ParseInt(name[i+1:], 10, 64)
+	if err != nil {
+		return TrashedSession{}, fmt.Errorf("malformed trash entry name %q", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(path, trashRecordFile))
+	if err != nil {
+		return TrashedSession{}, fmt.Errorf("read trash record: %w", err)
+	}
+	var sess session.Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return TrashedSession{}, fmt.Errorf("decode trash record: %w", err)
+	}
+
+	return TrashedSession{Session: sess, DeletedAt: time.Unix(0, nanos), Path: path}, nil
+}
+
+// RestoreSession moves the most recently deleted session with the given ID,
+// or ID prefix, out of the trash and back into the store. It fails if the
+// session's original directory has since been reused.
+func (s *Service) RestoreSession(ctx context.Context, id string) (session.Session, error) {
+	trash, err := s.Trash()
+	if err != nil {
+		return session.Session{}, err
+	}
+
+	var match *TrashedSession
+	for i := range trash {
+		if trash[i].Session.ID == id {
+			match = &trash[i]
+			break
+		}
+	}
+	if match == nil {
+		for i := range trash {
+			if !strings.HasPrefix(trash[i].Session.ID, id) {
+				continue
+			}
+			if match != nil && match.Session.ID != trash[i].Session.ID {
+				return session.Session{}, fmt.Errorf("session ID prefix %q is ambiguous", id)
+			}
+			if match == nil {
+				match = &trash[i]
+			}
+		}
+	}
+	if match == nil {
+		return session.Session{}, fmt.Errorf("no deleted session %q in trash", id)
+	}
+
+	sess := match.Session
+	if _, err := s.sessions.Get(ctx, sess.ID); err == nil {
+		return session.Session{}, fmt.Errorf("session %s already exists", sess.ID)
+	}
+	if _, err := os.Stat(sess.Path); err == nil {
+		return session.Session{}, fmt.Errorf("session directory %s already exists", sess.Path)
+	}
+
+	dir := filepath.Join(match.Path, trashDirName)
+	if _, err := os.Stat(dir); err == nil {
+		if err := os.MkdirAll(filepath.Dir(sess.Path), 0o755); err != nil {
+			return session.Session{}, fmt.Errorf("create parent directory: %w", err)
+		}
+		err := moveDir(dir, sess.Path)
+		if errors.Is(err, errSourceLeft) {
+			// The session directory is complete; what is left goes with the entry
+			s.log.Warn().Err(err).Str("session_id", sess.ID).Str("entry", match.Path).Msg("session restored, but its trash entry was not fully removed")
+		} else if err != nil {
+			return session.Session{}, fmt.Errorf("restore directory: %w", err)
+		}
+	}
+
+	sess.Version = 0
+	sess.UpdatedAt = time.Now()
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		return session.Session{}, fmt.Errorf("save session: %w", err)
+	}
+	if err := os.RemoveAll(match.Path); err != nil {
+		s.log.Warn().Err(err).Str("entry", match.Path).Msg("failed to remove trash entry")
+	}
+
+	s.log.Info().Str("session_id", sess.ID).Str("path", sess.Path).Msg("restored session from trash")
+	s.Emit(events.SessionRestored, sess.ID, map[string]any{"name": sess.Name, "remote": sess.Remote})
+
+	return sess, nil
+}
+
+// ExpireTrash permanently removes trash entries older than the configured
+// retention and returns how many were removed. With retention 0 every entry
+// is removed.
+func (s *Service) ExpireTrash() (int, error) {
+	trash, err := s.Trash()
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	removed := 0
+	for _, t := range trash {
+		if t.ExpiresAt.After(now) {
+			continue
+		}
+		if err := os.RemoveAll(t.Path); err != nil {
+			s.log.Warn().Err(err).Str("entry", t.Path).Msg("failed to remove expired trash entry")
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		s.log.Debug().Int("removed", removed).Msg("expired trash entries")
+	}
+	return removed, nil
+}
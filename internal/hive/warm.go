@@ -0,0 +1,168 @@
+package hive
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
+)
+
+// warmName is the name of recycled sessions created by Warm.
+const warmName = "warm"
+
+// WarmResult reports what Warm did for a repository.
+type WarmResult struct {
+	Remote    string   `json:"remote"`
+	Limit     int      `json:"limit"`               // max_recycled applied to the count, 0 = unlimited
+	Refreshed []string `json:"refreshed"`           // IDs of recycled sessions that were pulled
+	Created   []string `json:"created"`             // IDs of recycled sessions that were cloned
+	Corrupted []string `json:"corrupted,omitempty"` // IDs of recycled sessions that failed to pull
+}
+
+// RemoteForRepo returns the remote of repo: repo itself when it is a remote
+// URL or a local path, else the remote of a session whose repository is repo,
+// given as owner/name or name.
+func (s *Service) RemoteForRepo(ctx context.Context, repo string) (string, error) {
+	if repo == "" {
+		return s.ResolveRemote(ctx, "")
+	}
+	if git.IsLocalRemote(repo) || strings.ContainsAny(repo, ":@") {
+		return git.NormalizeRemote(repo)
+	}
+
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return "", fmt.Errorf("list sessions: %w", err)
+	}
+	for _, sess := range sessions {
+		if git.MatchesRepo(sess.Remote, repo) {
+			return sess.Remote, nil
+		}
+	}
+	return "", fmt.Errorf("no session for repository %q; pass its remote URL instead", repo)
+}
+
+// Warm keeps count recycled sessions of remote ready, so creating a session
+// for it reuses a checkout instead of cloning. Existing recycled sessions are
+// pulled, and failing ones marked corrupted. The shortfall is cloned, the
+// commands of matching rules run in each clone, then post_recycle hooks. The
+// count is capped at the repository's max_recycled. Output of commands and
+// hooks is written to w.
+func (s *Service) Warm(ctx context.Context, remote string, count int, w io.Writer) (WarmResult, error) {
+	if w == nil {
+		w = io.Discard
+	}
+
+	result := WarmResult{Remote: remote, Limit: s.cfg().GetMaxRecycled(remote)}
+	if result.Limit > 0 && count > result.Limit {
+		count = result.Limit
+	}
+
+	sessions, err := s.sessions.List(ctx)
+	if err != nil {
+		return result, fmt.Errorf("list sessions: %w", err)
+	}
+
+	ready := 0
+	for _, sess := range sessions {
+		if sess.State != session.StateRecycled || sess.Remote != remote {
+			continue
+		}
+
+		s.log.Debug().Str("session_id", sess.ID).Msg("refreshing recycled session")
+		if err := s.deps().gitRetrier.Do(ctx, w, "git pull", func() error { return s.git.Pull(ctx, sess.Path) }); err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			s.log.Warn().Err(err).Str("session_id", sess.ID).Msg("pull failed, marking corrupted")
+			s.markCorrupted(ctx, &sess)
+			result.Corrupted = append(result.Corrupted, sess.ID)
+			continue
+		}
+		sess.UpdatedAt = time.Now()
+		if err := s.sessions.Save(ctx, sess); err != nil {
+			return result, fmt.Errorf("save session: %w", err)
+		}
+		result.Refreshed = append(result.Refreshed, sess.ID)
+		ready++
+	}
+
+	for ; ready < count; ready++ {
+		id, err := s.warmOne(ctx, remote, w)
+		if err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, id)
+	}
+
+	if err := s.enforceMaxRecycled(ctx, remote); err != nil {
+		s.log.Warn().Err(err).Str("remote", remote).Msg("failed to enforce max recycled limit")
+	}
+	return result, nil
+}
+
+// warmOne clones remote into a new recycled session, runs the commands of
+// matching rules and the post_recycle hooks in it, and returns its ID. A
+// clone that fails part way is removed.
+func (s *Service) warmOne(ctx context.Context, remote string, w io.Writer) (string, error) {
+	now := time.Now()
+	sess := session.Session{
+		ID:        generateID(),
+		Name:      warmName,
+		Slug:      warmName,
+		Remote:    remote,
+		State:     session.StateRecycled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	sess.Path = filepath.Join(s.cfg().ReposDir(), fmt.Sprintf("%s-recycle-%s", git.ExtractRepoName(remote), sess.ID))
+
+	s.log.Info().Str("remote", remote).Str("dest", sess.Path).Msg("warming recycled session")
+	_, _ = fmt.Fprintf(w, "cloning %s into %s\n", remote, sess.Path)
+
+	err := s.deps().gitRetrier.Do(ctx, w, "git clone", func() error {
+		err := s.git.Clone(ctx, remote, sess.Path)
+		if err != nil {
+			_ = os.RemoveAll(sess.Path)
+		}
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("clone repository: %w", err)
+	}
+
+	cmdCtx, err := s.withEnv(ctx)
+	if err != nil {
+		_ = os.RemoveAll(sess.Path)
+		return "", err
+	}
+
+	hooksLog, closeHooksLog := s.openSessionLog(sess.ID, LogSourceHooks)
+	defer closeHooksLog()
+	hooksCtx := withCommandLog(cmdCtx, hooksLog)
+
+	data := s.hookData(sess, "")
+	if err := s.executeRules(hooksCtx, "", data); err != nil {
+		_ = os.RemoveAll(sess.Path)
+		return "", fmt.Errorf("execute rules: %w", err)
+	}
+	if err := s.runLifecycleHooks(hooksCtx, s.hookRunner.WithOutput(w), config.HookPostRecycle, data); err != nil {
+		_ = os.RemoveAll(sess.Path)
+		return "", err
+	}
+
+	if err := s.sessions.Save(ctx, sess); err != nil {
+		_ = os.RemoveAll(sess.Path)
+		return "", fmt.Errorf("save session: %w", err)
+	}
+	s.Emit(events.SessionRecycled, sess.ID, map[string]any{"name": sess.Name, "remote": sess.Remote, "warm": true})
+	return sess.ID, nil
+}
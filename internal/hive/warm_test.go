@@ -0,0 +1,49 @@
+package hive
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarm(t *testing.T) {
+	const remote = "https://github.com/hay-kot/hive.git"
+	limit := 2
+
+	store := newMockStore()
+	store.sessions["r1"] = session.Session{ID: "r1", State: session.StateRecycled, Remote: remote, Path: t.TempDir()}
+	store.sessions["a1"] = session.Session{ID: "a1", State: session.StateActive, Remote: remote, Path: t.TempDir()}
+
+	cfg := &config.Config{
+		DataDir: t.TempDir(),
+		GitPath: "git",
+		Rules:   []config.Rule{{Pattern: "", MaxRecycled: &limit, Commands: []string{"npm install"}}},
+	}
+	exec := &executil.RecordingExecutor{}
+	svc := New(store, &mockGit{}, cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+
+	result, err := svc.Warm(context.Background(), remote, 3, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Limit)
+	assert.Equal(t, []string{"r1"}, result.Refreshed)
+	require.Len(t, result.Created, 1)
+
+	warm := store.sessions[result.Created[0]]
+	assert.Equal(t, session.StateRecycled, warm.State)
+	assert.Equal(t, remote, warm.Remote)
+	assert.Contains(t, warm.Path, "hive-recycle-")
+	require.Len(t, exec.Commands, 1, "rule commands run in the new clone")
+	assert.Equal(t, warm.Path, exec.Commands[0].Dir)
+
+	result, err = svc.Warm(context.Background(), remote, 2, io.Discard)
+	require.NoError(t, err)
+	assert.Len(t, result.Refreshed, 2)
+	assert.Empty(t, result.Created)
+}
@@ -0,0 +1,161 @@
+// Package hosts runs hive commands on remote machines over SSH, so sessions
+// and messages can be shared between hosts.
+package hosts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/pkg/tmpl"
+)
+
+// Local is the host name shown for sessions on this machine.
+const Local = "local"
+
+// connectTimeout bounds how long ssh waits to connect to a host.
+const connectTimeout = "10"
+
+// sessionFields are the hive ls --fields requested from remote hosts.
+const sessionFields = "id,name,remote,state,path,created,updated"
+
+// Client runs hive on one remote host.
+type Client struct {
+	name     string
+	cfg      config.HostConfig
+	executor executil.Executor
+}
+
+// New creates a client for the host configured as name.
+func New(name string, cfg config.HostConfig, executor executil.Executor) *Client {
+	return &Client{name: name, cfg: cfg, executor: executor}
+}
+
+// Name returns the configured host name.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// Relays reports whether messages published locally on topic are also
+// published on this host.
+func (c *Client) Relays(topic string) bool {
+	for _, pattern := range c.cfg.Relay {
+		if ok, _ := path.Match(pattern, topic); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Sessions lists the sessions on the host. Corrupted sessions are omitted,
+// as in hive ls --json.
+func (c *Client) Sessions(ctx context.Context) ([]session.Session, error) {
+	out, err := c.run(ctx, "ls", "--json", "--fields", sessionFields)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []session.Session
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var row struct {
+			ID      string        `json:"id"`
+			Name    string        `json:"name"`
+			Remote  string        `json:"remote"`
+			State   session.State `json:"state"`
+			Path    string        `json:"path"`
+			Created time.Time     `json:"created"`
+			Updated time.Time     `json:"updated"`
+		}
+		if err := dec.Decode(&row); err != nil {
+			return nil, fmt.Errorf("host %s: parse sessions: %w", c.name, err)
+		}
+		sessions = append(sessions, session.Session{
+			ID:        row.ID,
+			Name:      row.Name,
+			Remote:    row.Remote,
+			State:     row.State,
+			Path:      row.Path,
+			CreatedAt: row.Created,
+			UpdatedAt: row.Updated,
+		})
+	}
+	return sessions, nil
+}
+
+// Publish publishes msg on the host. The host does not relay it further, so
+// hosts that relay to each other do not loop.
+func (c *Client) Publish(ctx context.Context, msg messaging.Message) error {
+	args := []string{"msg", "pub", "--no-relay", "--topic", msg.Topic}
+	if msg.Sender != "" {
+		args = append(args, "--sender", msg.Sender)
+	}
+	var expiresIn string
+	if !msg.ExpiresAt.IsZero() {
+		expiresIn = max(time.Until(msg.ExpiresAt), time.Second).Round(time.Second).String()
+	}
+
+	// Forward the publisher's identity so the host does not stamp its own.
+	for _, f := range []struct{ flag, value string }{
+		{"--origin-host", msg.Host},
+		{"--origin-user", msg.User},
+		{"--origin-tool", msg.Tool},
+		{"--priority", string(msg.Priority)},
+		{"--expires-in", expiresIn},
+	} {
+		if f.value != "" {
+			args = append(args, f.flag, f.value)
+		}
+	}
+	args = append(args, "--", msg.Payload)
+
+	_, err := c.run(ctx, args...)
+	return err
+}
+
+// Stream runs hive with args on the host, copying its output to stdout and
+// stderr as it is produced.
+func (c *Client) Stream(ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	if err := c.executor.RunStream(ctx, stdout, stderr, "ssh", c.sshArgs(args)...); err != nil {
+		return fmt.Errorf("host %s: %w", c.name, err)
+	}
+	return nil
+}
+
+func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	if err := c.executor.RunStream(ctx, &stdout, &stderr, "ssh", c.sshArgs(args)...); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("host %s: %w: %s", c.name, err, msg)
+		}
+		return nil, fmt.Errorf("host %s: %w", c.name, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// sshArgs returns the ssh arguments running hive with args on the host. The
+// hive command is passed unquoted so paths like ~/go/bin/hive expand there.
+func (c *Client) sshArgs(args []string) []string {
+	remote := make([]string, 0, len(args)+1)
+	remote = append(remote, c.cfg.HiveCommand())
+	for _, a := range args {
+		remote = append(remote, tmpl.ShellQuote(a))
+	}
+
+	return []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=" + connectTimeout,
+		c.cfg.SSH,
+		"--",
+		strings.Join(remote, " "),
+	}
+}
@@ -0,0 +1,66 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	exec := &executil.RecordingExecutor{
+		Outputs: map[string][]byte{"ssh": []byte(
+			`{"id":"a1","name":"one","remote":"git@github.com:u/r.git","state":"active","path":"/srv/one","created":"2026-01-02T03:04:05Z","updated":"2026-01-02T03:04:05Z"}` + "\n" +
+				`{"id":"b2","name":"two","remote":"git@github.com:u/r.git","state":"recycled","path":"/srv/two","created":"2026-01-02T03:04:05Z","updated":"2026-01-02T03:04:05Z"}` + "\n",
+		)},
+	}
+	c := New("server", config.HostConfig{SSH: "me@server", Hive: "~/bin/hive"}, exec)
+
+	sessions, err := c.Sessions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "a1", sessions[0].ID)
+	assert.Equal(t, "/srv/one", sessions[0].Path)
+	assert.Equal(t, session.StateRecycled, sessions[1].State)
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), sessions[0].CreatedAt)
+
+	require.Len(t, exec.Commands, 1)
+	assert.Equal(t, "ssh", exec.Commands[0].Cmd)
+	assert.Equal(t, []string{
+		"-o", "BatchMode=yes", "-o", "ConnectTimeout=10", "me@server", "--",
+		"~/bin/hive 'ls' '--json' '--fields' 'id,name,remote,state,path,created,updated'",
+	}, exec.Commands[0].Args)
+}
+
+func TestPublish(t *testing.T) {
+	exec := &executil.RecordingExecutor{}
+	c := New("server", config.HostConfig{SSH: "server"}, exec)
+
+	err := c.Publish(context.Background(), messaging.Message{Topic: "handoff", Sender: "abc", Payload: "it's ready"})
+	require.NoError(t, err)
+
+	require.Len(t, exec.Commands, 1)
+	args := exec.Commands[0].Args
+	assert.Equal(t, `hive 'msg' 'pub' '--no-relay' '--topic' 'handoff' '--sender' 'abc' '--' 'it'\''s ready'`, args[len(args)-1])
+
+	exec.Reset()
+	err = c.Publish(context.Background(), messaging.Message{Topic: "handoff", Payload: "hi", Host: "laptop", User: "me"})
+	require.NoError(t, err)
+
+	args = exec.Commands[0].Args
+	assert.Equal(t, `hive 'msg' 'pub' '--no-relay' '--topic' 'handoff' '--origin-host' 'laptop' '--origin-user' 'me' '--' 'hi'`, args[len(args)-1])
+}
+
+func TestRelays(t *testing.T) {
+	c := New("server", config.HostConfig{SSH: "server", Relay: []string{"handoff.*", "agent.*.inbox"}}, nil)
+
+	assert.True(t, c.Relays("handoff.review"))
+	assert.True(t, c.Relays("agent.abc.inbox"))
+	assert.False(t, c.Relays("build.started"))
+}
@@ -0,0 +1,51 @@
+package terminal
+
+import (
+	"strings"
+)
+
+// activityHints are lowercase fragments of the AI tools' own UI hints, which
+// say nothing about what the agent is doing.
+var activityHints = []string{
+	"? for shortcuts",
+	"shift+tab to cycle",
+	"auto-accept edits",
+	"bypass permissions",
+}
+
+// LastActivity returns the most recent meaningful line of terminal content,
+// as a one-line summary of what the session is doing. Blank lines, box
+// drawing, input prompts, and UI hints are skipped. Returns "" if no line
+// qualifies.
+func LastActivity(content string) string {
+	lines := strings.Split(stripANSI(content), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.ReplaceAll(lines[i], "\u00A0", " ")
+		line = strings.TrimFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == '\r' || isBoxDrawingChar(r)
+		})
+		if line == "" || isActivityNoise(line) {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
+// isActivityNoise reports whether a trimmed line is part of the tool's input
+// box or UI chrome rather than output.
+func isActivityNoise(line string) bool {
+	if strings.HasPrefix(line, ">") || strings.HasPrefix(line, "❯") {
+		return true
+	}
+	if strings.Trim(line, "─━═-") == "" {
+		return true
+	}
+	lower := strings.ToLower(line)
+	for _, hint := range activityHints {
+		if strings.Contains(lower, hint) {
+			return true
+		}
+	}
+	return false
+}
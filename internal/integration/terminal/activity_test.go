@@ -0,0 +1,51 @@
+package terminal
+
+import (
+	"testing"
+)
+
+func TestLastActivity(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "last non-empty line",
+			content: "$ go test ./...\nok  \tgithub.com/x/y\t0.2s\n\n\n",
+			want:    "ok  \tgithub.com/x/y\t0.2s",
+		},
+		{
+			name: "skips claude input box and hints",
+			content: "⏺ Updated internal/tui/model.go with 3 additions\n\n" +
+				"╭──────────────────────────╮\n" +
+				"│ >                        │\n" +
+				"╰──────────────────────────╯\n" +
+				"  ? for shortcuts\n",
+			want: "⏺ Updated internal/tui/model.go with 3 additions",
+		},
+		{
+			name:    "keeps busy line",
+			content: "some output\n✳ Pondering… (12s · esc to interrupt)\n────────\n❯ \n",
+			want:    "✳ Pondering… (12s · esc to interrupt)",
+		},
+		{
+			name:    "strips ANSI",
+			content: "\x1b[32mPASS\x1b[0m all tests\n",
+			want:    "PASS all tests",
+		},
+		{
+			name:    "nothing meaningful",
+			content: "\n❯ \n  ? for shortcuts\n",
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LastActivity(tt.content); got != tt.want {
+				t.Errorf("LastActivity() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
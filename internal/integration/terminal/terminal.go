@@ -19,6 +19,7 @@ type SessionInfo struct {
 	Pane         string // pane identifier if applicable
 	Status       Status // current detected status
 	DetectedTool string // detected AI tool (claude, gemini, etc.)
+	Activity     string // last meaningful line of output, set by GetStatus
 }
 
 // Integration defines the interface for terminal multiplexer integrations.
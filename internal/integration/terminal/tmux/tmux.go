@@ -157,6 +157,8 @@ func (t *Integration) GetStatus(ctx context.Context, info *terminal.SessionInfo)
 		info.DetectedTool = tool
 	}
 
+	info.Activity = terminal.LastActivity(content)
+
 	// Get or create state tracker for this session
 	t.mu.Lock()
 	tracker, ok := t.trackers[info.Name]
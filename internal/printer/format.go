@@ -0,0 +1,56 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Format selects how commands write their results.
+type Format string
+
+const (
+	FormatText Format = "text" // human-readable tables and messages
+	FormatJSON Format = "json" // a single JSON document (or JSON lines) on stdout
+)
+
+// ParseFormat parses an output format name. An empty name is FormatText.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (expected text or json)", s)
+	}
+}
+
+// SetFormat sets the output format commands should use.
+func (p *Printer) SetFormat(f Format) {
+	p.format = f
+}
+
+// IsJSON reports whether commands should write JSON instead of text.
+func (p *Printer) IsJSON() bool {
+	return p.format == FormatJSON
+}
+
+// EncodeJSON writes v to w as a single line of JSON.
+func EncodeJSON(w io.Writer, v any) error {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+	return nil
+}
+
+// errorJSON is the shape of errors reported in JSON mode.
+type errorJSON struct {
+	Error  string       `json:"error"`
+	Fields []fieldError `json:"fields,omitempty"`
+}
+
+type fieldError struct {
+	Field string `json:"field"`
+	Error string `json:"error"`
+}
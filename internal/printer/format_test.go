@@ -0,0 +1,35 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hay-kot/criterio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatText, "text": FormatText, "json": FormatJSON} {
+		got, err := ParseFormat(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseFormat("yaml")
+	require.Error(t, err)
+}
+
+func TestFatalError_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+	p.SetFormat(FormatJSON)
+
+	p.FatalError(errors.New("boom"))
+	assert.JSONEq(t, `{"error":"boom"}`, buf.String())
+
+	buf.Reset()
+	p.FatalError(criterio.NewFieldErrors("git_path", errors.New("is required")))
+	assert.Contains(t, buf.String(), `"fields":[{"field":"git_path","error":"is required"}]`)
+}
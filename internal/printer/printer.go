@@ -34,13 +34,17 @@ type ctxKey struct{}
 
 // Printer handles formatted output with colors and styles
 type Printer struct {
-	writer io.Writer
+	writer  io.Writer
+	format  Format
+	quiet   bool // suppress success and info messages
+	noColor bool // write plain text without ANSI codes
 }
 
 // New creates a new Printer that writes to the given writer
 func New(w io.Writer) *Printer {
 	return &Printer{
 		writer: w,
+		format: FormatText,
 	}
 }
 
@@ -57,6 +61,24 @@ func Ctx(ctx context.Context) *Printer {
 	return New(os.Stderr)
 }
 
+// SetQuiet suppresses success and info messages. Warnings, errors, and
+// command results are still written.
+func (p *Printer) SetQuiet(quiet bool) {
+	p.quiet = quiet
+}
+
+// SetNoColor disables ANSI colors and styles.
+func (p *Printer) SetNoColor(noColor bool) {
+	p.noColor = noColor
+}
+
+// WithWriter returns a copy of the printer that writes to w.
+func (p *Printer) WithWriter(w io.Writer) *Printer {
+	cp := *p
+	cp.writer = w
+	return &cp
+}
+
 // FatalError prints a formatted error box and does NOT exit
 // Caller should handle exit code
 func (p *Printer) FatalError(err error) {
@@ -66,6 +88,17 @@ func (p *Printer) FatalError(err error) {
 
 	// Check if the error contains criterio.FieldErrors for better formatting
 	var fieldErrs criterio.FieldErrors
+	if p.IsJSON() {
+		out := errorJSON{Error: err.Error()}
+		if errors.As(err, &fieldErrs) {
+			for _, fe := range fieldErrs {
+				out.Fields = append(out.Fields, fieldError{Field: fe.Field, Error: fe.Err.Error()})
+			}
+		}
+		_ = EncodeJSON(p.writer, out)
+		return
+	}
+
 	if errors.As(err, &fieldErrs) {
 		p.printValidationErrors(err, fieldErrs)
 		return
@@ -120,12 +153,18 @@ func (p *Printer) Errorf(format string, args ...any) {
 
 // Successf prints a success message in green
 func (p *Printer) Successf(format string, args ...any) {
+	if p.quiet {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	_, _ = p.writer.Write([]byte(p.colorize(ColorGreen, Check+" "+msg) + "\n"))
 }
 
 // Success prints a success message with details on a separate line
 func (p *Printer) Success(message string, details string) {
+	if p.quiet {
+		return
+	}
 	_, _ = p.writer.Write([]byte(p.colorize(ColorGreen, Check+" "+message) + "\n"))
 	if details != "" {
 		_, _ = p.writer.Write([]byte("  " + p.colorize(ColorGray, details) + "\n"))
@@ -134,6 +173,9 @@ func (p *Printer) Success(message string, details string) {
 
 // Infof prints an info message in gray
 func (p *Printer) Infof(format string, args ...any) {
+	if p.quiet {
+		return
+	}
 	msg := fmt.Sprintf(format, args...)
 	_, _ = p.writer.Write([]byte(p.colorize(ColorGray, Dot+" "+msg) + "\n"))
 }
@@ -152,17 +194,30 @@ func (p *Printer) Printf(format string, args ...any) {
 
 // colorize applies ANSI color codes to text
 func (p *Printer) colorize(color, text string) string {
+	if p.noColor {
+		return text
+	}
 	return color + text + ColorReset
 }
 
 // Bold makes text bold
 func (p *Printer) Bold(text string) string {
-	return ColorBold + text + ColorReset
+	return p.colorize(ColorBold, text)
+}
+
+// Highlight marks text as a search match (bold yellow)
+func (p *Printer) Highlight(text string) string {
+	return p.colorize(ColorBold+ColorYellow, text)
+}
+
+// Gray renders secondary text such as timestamps
+func (p *Printer) Gray(text string) string {
+	return p.colorize(ColorGray, text)
 }
 
 // Section prints a section header (bold + underlined)
 func (p *Printer) Section(title string) {
-	_, _ = p.writer.Write([]byte(ColorBold + ColorUnderline + title + ColorReset + "\n"))
+	_, _ = p.writer.Write([]byte(p.colorize(ColorBold+ColorUnderline, title) + "\n"))
 }
 
 // CheckItem prints a success item with green checkmark
@@ -0,0 +1,54 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinter_Quiet(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+	p.SetQuiet(true)
+	p.SetNoColor(true)
+
+	p.Successf("created %s", "abc")
+	p.Success("done", "details")
+	p.Infof("note")
+	p.Warnf("careful")
+	p.FatalError(errors.New("boom"))
+
+	assert.Equal(t, "• careful\n╭ Error\n│ boom\n╵\n", buf.String())
+}
+
+func TestPrinter_NoColor(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+	p.SetNoColor(true)
+
+	p.Section("Config")
+	p.Successf("ok")
+	p.FailItem("label", "detail")
+
+	assert.Equal(t, "Config\n✔ ok\n  ✘ label: detail\n", buf.String())
+	assert.NotContains(t, buf.String(), "\033[")
+}
+
+func TestProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+	p.SetNoColor(true)
+
+	pr := p.Progress("Cloning hive")
+	_, _ = pr.Write([]byte("Receiving objects:  45% (450/1000)\rReceiving objects: 100% (1000/1000), done.\n"))
+	pr.Done(nil)
+
+	pr = p.Progress("Pulling hive")
+	_, _ = pr.Write([]byte("fatal: unable to access"))
+	_, _ = pr.Write([]byte(" the remote\n"))
+	pr.Done(errors.New("exit status 128"))
+
+	assert.Equal(t, "✔ Cloning hive\n✘ Pulling hive: fatal: unable to access the remote\n", buf.String())
+}
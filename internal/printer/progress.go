@@ -0,0 +1,131 @@
+package printer
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// spinnerFrames are the frames of the Progress spinner.
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// clearLine moves the cursor to the start of the line and erases it.
+const clearLine = "\r\033[K"
+
+// maxStatusWidth keeps the progress line from wrapping, which would break
+// redrawing it in place.
+const maxStatusWidth = 60
+
+// Progress shows a long-running step on a single line that is redrawn in
+// place: a spinner, the step's title, and its latest status line, such as
+// git's "Receiving objects:  45% (450/1000)". Status output is written to it
+// as an io.Writer, with lines ended by \r or \n. Outside a terminal only the
+// outcome is printed, and only failures when the printer is quiet.
+type Progress struct {
+	p     *Printer
+	title string
+	live  bool
+	stop  chan struct{}
+
+	mu      sync.Mutex
+	frame   int
+	status  string
+	partial []byte
+}
+
+// Progress starts a progress line for the step title.
+func (p *Printer) Progress(title string) *Progress {
+	pr := &Progress{p: p, title: title, live: !p.quiet && isTerminal(p.writer), stop: make(chan struct{})}
+	if pr.live {
+		pr.draw()
+		go pr.spin()
+	}
+	return pr
+}
+
+// Write records the last complete status line in b and redraws the line.
+func (pr *Progress) Write(b []byte) (int, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pr.partial = append(pr.partial, b...)
+	for {
+		i := bytes.IndexAny(pr.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		if line := strings.TrimSpace(string(pr.partial[:i])); line != "" {
+			pr.status = line
+		}
+		pr.partial = pr.partial[i+1:]
+	}
+	if pr.live {
+		pr.draw()
+	}
+	return len(b), nil
+}
+
+// Done stops the spinner and replaces the line with the outcome of the step:
+// a check, or a cross with the last status line when err is not nil.
+func (pr *Progress) Done(err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	if pr.live {
+		close(pr.stop)
+		_, _ = io.WriteString(pr.p.writer, clearLine)
+	}
+	switch {
+	case err != nil && pr.status != "":
+		pr.p.Errorf("%s: %s", pr.title, pr.status)
+	case err != nil:
+		pr.p.Errorf("%s", pr.title)
+	default:
+		pr.p.Successf("%s", pr.title)
+	}
+}
+
+// spin advances the spinner until Done, so the line moves while the step is
+// silent.
+func (pr *Progress) spin() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pr.stop:
+			return
+		case <-ticker.C:
+			pr.mu.Lock()
+			select {
+			case <-pr.stop:
+			default:
+				pr.draw()
+			}
+			pr.mu.Unlock()
+		}
+	}
+}
+
+// draw redraws the progress line. The caller holds pr.mu.
+func (pr *Progress) draw() {
+	line := pr.p.colorize(ColorYellow, spinnerFrames[pr.frame%len(spinnerFrames)]) + " " + pr.title
+	pr.frame++
+	if status := []rune(pr.status); len(status) > 0 {
+		if len(status) > maxStatusWidth {
+			status = append(status[:maxStatusWidth-1], '…')
+		}
+		line += "  " + pr.p.colorize(ColorGray, string(status))
+	}
+	_, _ = io.WriteString(pr.p.writer, clearLine+line)
+}
+
+// isTerminal reports whether w writes to a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// maxKVValueBytes caps the size of a single kv value.
+const maxKVValueBytes = 1 << 20
+
+// The kv endpoints are an in-memory scratch space shared by API clients, for
+// example an editor extension and a dashboard agreeing on which session is
+// focused. Values are arbitrary JSON and live until the server stops.
+
+func (s *Server) handleListKV(w http.ResponseWriter, _ *http.Request) {
+	keys := s.kv.Keys()
+	sort.Strings(keys)
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (s *Server) handleGetKV(w http.ResponseWriter, r *http.Request) {
+	value, ok := s.kv.Get(r.PathValue("key"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("key not found"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, value)
+}
+
+func (s *Server) handlePutKV(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxKVValueBytes+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(body) > maxKVValueBytes {
+		writeError(w, http.StatusRequestEntityTooLarge, errors.New("value too large"))
+		return
+	}
+	if !json.Valid(body) {
+		writeError(w, http.StatusBadRequest, errors.New("value must be JSON"))
+		return
+	}
+
+	s.kv.Set(r.PathValue("key"), json.RawMessage(body))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDeleteKV(w http.ResponseWriter, r *http.Request) {
+	s.kv.Delete(r.PathValue("key"))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,183 @@
+// Package server exposes the hive service over a local HTTP API so editors,
+// dashboards, and agents can manage sessions and exchange messages without
+// running the CLI for every call.
+//
+// Every request must carry the server token, either as an
+// "Authorization: Bearer <token>" header or, for clients such as
+// EventSource that cannot set headers, a "token" query parameter.
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/pkg/kv"
+	"github.com/rs/zerolog"
+)
+
+// defaultPollInterval is how often event streams check for new messages,
+// matching 'hive msg sub --listen'.
+const defaultPollInterval = 500 * time.Millisecond
+
+// keepAliveInterval is how often an idle event stream sends a comment so
+// proxies and clients do not time it out.
+const keepAliveInterval = 15 * time.Second
+
+// Server serves the hive HTTP API.
+type Server struct {
+	svc     *hive.Service
+	msgs    messaging.Store
+	kv      *kv.Store[string, json.RawMessage]
+	token   string
+	version string
+	log     zerolog.Logger
+	started time.Time
+	poll    time.Duration
+	origins []string // browser origins allowed to read the API; "*" allows any
+}
+
+// New creates a server for svc and msgs. Requests must present token.
+func New(svc *hive.Service, msgs messaging.Store, token, version string, log zerolog.Logger) *Server {
+	return &Server{
+		svc:     svc,
+		msgs:    msgs,
+		kv:      kv.New[string, json.RawMessage](),
+		token:   token,
+		version: version,
+		log:     log,
+		started: time.Now(),
+		poll:    defaultPollInterval,
+	}
+}
+
+// WithCORS lets browser pages served from origins read the API, for status
+// boards hosted apart from the server. "*" allows any origin. Only GET is
+// allowed across origins, and other methods from any cross-origin page are
+// rejected; requests still need the token.
+func (s *Server) WithCORS(origins []string) *Server {
+	s.origins = origins
+	return s
+}
+
+// Handler returns the API routes wrapped in token authentication.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /v1/status", s.handleStatus)
+
+	mux.HandleFunc("GET /v1/sessions", s.handleListSessions)
+	mux.HandleFunc("POST /v1/sessions", s.handleCreateSession)
+	mux.HandleFunc("GET /v1/sessions/events", s.handleSessionEvents)
+	mux.HandleFunc("GET /v1/sessions/{ref}", s.handleGetSession)
+	mux.HandleFunc("DELETE /v1/sessions/{ref}", s.handleDeleteSession)
+	mux.HandleFunc("POST /v1/sessions/{ref}/recycle", s.handleRecycleSession)
+
+	mux.HandleFunc("GET /v1/topics", s.handleListTopics)
+	mux.HandleFunc("GET /v1/topics/{topic}/messages", s.handleSubscribe)
+	mux.HandleFunc("POST /v1/topics/{topic}/messages", s.handlePublish)
+	mux.HandleFunc("GET /v1/topics/{topic}/events", s.handleEvents)
+
+	mux.HandleFunc("GET /v1/kv", s.handleListKV)
+	mux.HandleFunc("GET /v1/kv/{key}", s.handleGetKV)
+	mux.HandleFunc("PUT /v1/kv/{key}", s.handlePutKV)
+	mux.HandleFunc("DELETE /v1/kv/{key}", s.handleDeleteKV)
+
+	return s.cors(s.authenticate(mux))
+}
+
+// cors adds CORS headers for allowed origins and answers their preflight
+// requests, which browsers send without the token. Browsers send simple
+// POSTs across origins without a preflight, so requests other than reads
+// from a page on another origin are rejected outright.
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && !isRead(r.Method) && !sameOrigin(r, origin) {
+			writeError(w, http.StatusForbidden, errors.New("cross-origin clients may only GET"))
+			return
+		}
+		if origin == "" || !s.allowOrigin(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", "GET")
+			h.Set("Access-Control-Allow-Headers", "Authorization")
+			h.Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowOrigin reports whether origin may read the API.
+func (s *Server) allowOrigin(origin string) bool {
+	return slices.Contains(s.origins, "*") || slices.Contains(s.origins, origin)
+}
+
+// isRead reports whether method only reads, including CORS preflights.
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// sameOrigin reports whether origin is the host the request was sent to.
+func sameOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// authenticate rejects requests that do not present the server token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			token, _ = strings.CutPrefix(auth, "Bearer ")
+		}
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errorResponse is the body of every non-2xx response.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// decode reads a JSON request body into v, rejecting unknown fields.
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return errors.New("invalid request body: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T, sessions ...session.Session) *httptest.Server {
+	t.Helper()
+
+	s, _ := newTestAPI(t, sessions...)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// newTestAPI returns a server over a store holding sessions, and the store.
+func newTestAPI(t *testing.T, sessions ...session.Session) (*Server, session.Store) {
+	t.Helper()
+
+	cfg := &config.Config{DataDir: t.TempDir(), GitPath: "git"}
+	store := jsonfile.New(cfg.SessionsFile())
+	for _, sess := range sessions {
+		require.NoError(t, store.Save(context.Background(), sess))
+	}
+
+	exec := &executil.RecordingExecutor{}
+	svc := hive.New(store, git.NewExecutor("git", exec), cfg, exec, zerolog.New(io.Discard), io.Discard, io.Discard)
+	msgs := jsonfile.NewMsgStore(filepath.Join(cfg.DataDir, "messages", "topics"))
+
+	s := New(svc, msgs, testToken, "test", zerolog.New(io.Discard))
+	s.poll = 10 * time.Millisecond
+	return s, store
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func decodeBody[T any](t *testing.T, resp *http.Response) T {
+	t.Helper()
+	var v T
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&v))
+	return v
+}
+
+func TestAuth(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp, err := ts.Client().Get(ts.URL + "/v1/status")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, err = ts.Client().Get(ts.URL + "/v1/status?token=" + testToken)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestSessions(t *testing.T) {
+	ts := newTestServer(t,
+		session.Session{ID: "a1", Name: "alpha", Slug: "alpha", State: session.StateActive, Path: t.TempDir()},
+		session.Session{ID: "b2", Name: "bravo", Slug: "bravo", State: session.StateRecycled},
+	)
+
+	resp := do(t, ts, http.MethodGet, "/v1/sessions", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, decodeBody[[]session.Session](t, resp), 2)
+
+	resp = do(t, ts, http.MethodGet, "/v1/sessions/alpha", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "a1", decodeBody[session.Session](t, resp).ID)
+
+	resp = do(t, ts, http.MethodGet, "/v1/sessions/missing", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Contains(t, decodeBody[errorResponse](t, resp).Error, "not found")
+
+	resp = do(t, ts, http.MethodGet, "/v1/status", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	status := decodeBody[statusResponse](t, resp)
+	assert.Equal(t, map[string]int{"active": 1, "recycled": 1}, status.Sessions)
+
+	resp = do(t, ts, http.MethodPost, "/v1/sessions", `{"name":"x"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = do(t, ts, http.MethodDelete, "/v1/sessions/a1", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = do(t, ts, http.MethodGet, "/v1/sessions/a1", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestSessionEvents(t *testing.T) {
+	s, store := newTestAPI(t,
+		session.Session{ID: "a1", Name: "alpha", Slug: "alpha", State: session.StateActive, Path: t.TempDir()},
+		session.Session{ID: "b2", Name: "bravo", Slug: "bravo", State: session.StateRecycled},
+	)
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/sessions/events?token="+testToken, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := ts.Client().Do(req.WithContext(ctx))
+	require.NoError(t, err)
+	defer func() { _ = stream.Body.Close() }()
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	scanner := bufio.NewScanner(stream.Body)
+	next := func() (string, string) {
+		t.Helper()
+		var event string
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+			}
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				return event, data
+			}
+		}
+		t.Fatal("stream ended")
+		return "", ""
+	}
+
+	event, data := next()
+	require.Equal(t, "sessions", event)
+	var snapshot []session.Session
+	require.NoError(t, json.Unmarshal([]byte(data), &snapshot))
+	assert.Len(t, snapshot, 2)
+
+	sess, err := store.Get(context.Background(), "b2")
+	require.NoError(t, err)
+	sess.Name = "bravo-renamed"
+	require.NoError(t, store.Save(context.Background(), sess))
+
+	event, data = next()
+	require.Equal(t, "session", event)
+	var updated session.Session
+	require.NoError(t, json.Unmarshal([]byte(data), &updated))
+	assert.Equal(t, "bravo-renamed", updated.Name)
+
+	require.NoError(t, store.Delete(context.Background(), "a1"))
+
+	event, data = next()
+	require.Equal(t, "deleted", event)
+	assert.JSONEq(t, `{"id":"a1"}`, data)
+}
+
+func TestCORS(t *testing.T) {
+	s, _ := newTestAPI(t)
+	ts := httptest.NewServer(s.WithCORS([]string{"http://board.local"}).Handler())
+	t.Cleanup(ts.Close)
+
+	preflight := func(origin string) *http.Response {
+		req, err := http.NewRequest(http.MethodOptions, ts.URL+"/v1/sessions", nil)
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	resp := preflight("http://board.local")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "http://board.local", resp.Header.Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET", resp.Header.Get("Access-Control-Allow-Methods"))
+
+	resp = preflight("http://evil.local")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "other origins get no preflight answer")
+	assert.Empty(t, resp.Header.Get("Access-Control-Allow-Origin"))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/sessions", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "http://board.local")
+	resp, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, "allowed origins still need the token")
+	assert.Equal(t, "http://board.local", resp.Header.Get("Access-Control-Allow-Origin"))
+
+	// Simple POSTs are not preflighted, so writes from other origins are
+	// rejected even with the token
+	for _, origin := range []string{"http://board.local", "http://evil.local"} {
+		req, err = http.NewRequest(http.MethodPost, ts.URL+"/v1/topics/agent.x.inbox/messages?token="+testToken, strings.NewReader("hi"))
+		require.NoError(t, err)
+		req.Header.Set("Origin", origin)
+		resp, err = ts.Client().Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, origin)
+	}
+
+	req, err = http.NewRequest(http.MethodPost, ts.URL+"/v1/topics/agent.x.inbox/messages?token="+testToken, strings.NewReader(`{"payload":"hi"}`))
+	require.NoError(t, err)
+	req.Header.Set("Origin", ts.URL)
+	resp, err = ts.Client().Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.NotEqual(t, http.StatusForbidden, resp.StatusCode, "same-origin writes are allowed")
+}
+
+func TestMessages(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := do(t, ts, http.MethodGet, "/v1/topics/agent.x.inbox/messages", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, decodeBody[[]messaging.Message](t, resp))
+
+	// Open the event stream before publishing
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/topics/agent.*/events?token="+testToken, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := ts.Client().Do(req.WithContext(ctx))
+	require.NoError(t, err)
+	defer func() { _ = stream.Body.Close() }()
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+
+	resp = do(t, ts, http.MethodPost, "/v1/topics/agent.x.inbox/messages", `{"payload":"hello","sender":"ide"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	published := decodeBody[messaging.Message](t, resp)
+	assert.NotEmpty(t, published.ID)
+
+	resp = do(t, ts, http.MethodGet, "/v1/topics/agent.x.inbox/messages", "")
+	messages := decodeBody[[]messaging.Message](t, resp)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "hello", messages[0].Payload)
+
+	resp = do(t, ts, http.MethodGet, "/v1/topics", "")
+	assert.Equal(t, []string{"agent.x.inbox"}, decodeBody[[]string](t, resp))
+
+	// The stream delivers the message as an SSE event
+	var event messaging.Message
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			require.NoError(t, json.Unmarshal([]byte(data), &event))
+			break
+		}
+	}
+	assert.Equal(t, published.ID, event.ID)
+}
+
+func TestKV(t *testing.T) {
+	ts := newTestServer(t)
+
+	resp := do(t, ts, http.MethodGet, "/v1/kv/focus", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = do(t, ts, http.MethodPut, "/v1/kv/focus", "not json")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = do(t, ts, http.MethodPut, "/v1/kv/focus", `{"session":"a1"}`)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp = do(t, ts, http.MethodGet, "/v1/kv/focus", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"session":"a1"}`, string(body))
+
+	resp = do(t, ts, http.MethodGet, "/v1/kv", "")
+	assert.Equal(t, []string{"focus"}, decodeBody[[]string](t, resp))
+
+	resp = do(t, ts, http.MethodDelete, "/v1/kv/focus", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = do(t, ts, http.MethodGet, "/v1/kv/focus", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
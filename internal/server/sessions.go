@@ -0,0 +1,222 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/hive"
+)
+
+// statusResponse is returned by GET /v1/status.
+type statusResponse struct {
+	Version  string         `json:"version"`
+	Uptime   string         `json:"uptime"`
+	Sessions map[string]int `json:"sessions"` // count by state
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.svc.ListSessions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, sess := range sessions {
+		counts[string(sess.State)]++
+	}
+
+	writeJSON(w, http.StatusOK, statusResponse{
+		Version:  s.version,
+		Uptime:   time.Since(s.started).Round(time.Second).String(),
+		Sessions: counts,
+	})
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.svc.ListSessions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if sessions == nil {
+		sessions = []session.Session{}
+	}
+
+	writeJSON(w, http.StatusOK, sessions)
+}
+
+// deletedEvent is the data of a "deleted" session event.
+type deletedEvent struct {
+	ID string `json:"id"`
+}
+
+// handleSessionEvents streams session changes as server-sent events for
+// status boards. The stream opens with a "sessions" event holding every
+// session, then sends a "session" event with the full record whenever one is
+// created or saved, and a "deleted" event with the ID when one is removed.
+func (s *Server) handleSessionEvents(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.svc.ListSessions(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if sessions == nil {
+		sessions = []session.Session{}
+	}
+
+	flusher, ok := openStream(w)
+	if !ok {
+		return
+	}
+	_ = writeEvent(w, "", "sessions", sessions)
+	flusher.Flush()
+
+	// Stores bump Version on every save, so it tells which sessions changed
+	versions := make(map[string]int64, len(sessions))
+	for _, sess := range sessions {
+		versions[sess.ID] = sess.Version
+	}
+
+	poll := time.NewTicker(s.poll)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-poll.C:
+			sessions, err := s.svc.ListSessions(r.Context())
+			if err != nil {
+				s.log.Warn().Err(err).Msg("session stream list failed")
+				continue
+			}
+
+			changed := false
+			seen := make(map[string]bool, len(sessions))
+			for _, sess := range sessions {
+				seen[sess.ID] = true
+				if v, ok := versions[sess.ID]; ok && v == sess.Version {
+					continue
+				}
+				versions[sess.ID] = sess.Version
+				_ = writeEvent(w, "", "session", sess)
+				changed = true
+			}
+			for id := range versions {
+				if !seen[id] {
+					delete(versions, id)
+					_ = writeEvent(w, "", "deleted", deletedEvent{ID: id})
+					changed = true
+				}
+			}
+			if changed {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// createRequest is the body of POST /v1/sessions.
+type createRequest struct {
+	Name         string `json:"name"`
+	Remote       string `json:"remote"`
+	Prompt       string `json:"prompt"`
+	SpawnProfile string `json:"spawn_profile"`
+	Source       string `json:"source"` // directory to copy files from, per copy rules
+	Subdir       string `json:"subdir"` // working subdirectory in a monorepo
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Name == "" || req.Remote == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name and remote are required"))
+		return
+	}
+
+	sess, err := s.svc.CreateSession(r.Context(), hive.CreateOptions{
+		Name:         req.Name,
+		Remote:       req.Remote,
+		Prompt:       req.Prompt,
+		SpawnProfile: req.SpawnProfile,
+		Source:       req.Source,
+		Subdir:       req.Subdir,
+	})
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, sess)
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.svc.ResolveSession(r.Context(), r.PathValue("ref"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sess)
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.svc.ResolveSession(r.Context(), r.PathValue("ref"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	if err := s.svc.DeleteSession(r.Context(), sess.ID); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRecycleSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.svc.ResolveSession(r.Context(), r.PathValue("ref"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	if err := s.svc.RecycleSession(r.Context(), sess.ID, io.Discard); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	sess, err = s.svc.GetSession(r.Context(), sess.ID)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sess)
+}
+
+// errorStatus maps service errors to HTTP status codes.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, hive.ErrAmbiguous), errors.Is(err, session.ErrConflict):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/pkg/randid"
+)
+
+func (s *Server) handleListTopics(w http.ResponseWriter, r *http.Request) {
+	topics, err := s.msgs.List(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if topics == nil {
+		topics = []string{}
+	}
+
+	writeJSON(w, http.StatusOK, topics)
+}
+
+// handleSubscribe returns a topic's messages, optionally only those after the
+// RFC 3339 "since" query parameter. Topics support the same wildcards as
+// 'hive msg sub'.
+func (s *Server) handleSubscribe(w http.ResponseWriter, r *http.Request) {
+	since, err := parseSince(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	messages, err := s.subscribe(r, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, messages)
+}
+
+// publishRequest is the body of POST /v1/topics/{topic}/messages.
+type publishRequest struct {
+	Payload   string    `json:"payload"`
+	Sender    string    `json:"sender"`
+	SessionID string    `json:"session_id"`
+	Host      string    `json:"host"`
+	User      string    `json:"user"`
+	Tool      string    `json:"tool"`
+	Priority  string    `json:"priority"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (s *Server) handlePublish(w http.ResponseWriter, r *http.Request) {
+	var req publishRequest
+	if err := decode(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := messaging.ParsePriority(req.Priority); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	msg := messaging.Message{
+		ID:        randid.Generate(16),
+		Topic:     r.PathValue("topic"),
+		Payload:   req.Payload,
+		Sender:    req.Sender,
+		SessionID: req.SessionID,
+		Host:      req.Host,
+		User:      req.User,
+		Tool:      req.Tool,
+		Priority:  messaging.Priority(req.Priority),
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.msgs.Publish(r.Context(), msg); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, messaging.ErrRateLimited) {
+			status = http.StatusTooManyRequests
+		}
+		writeError(w, status, err)
+		return
+	}
+	s.svc.EmitMessage(msg)
+	s.svc.RelayMessage(r.Context(), msg)
+
+	writeJSON(w, http.StatusCreated, msg)
+}
+
+// handleEvents streams a topic's new messages as server-sent events, one
+// "message" event per message. Without "since" the stream starts from now.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	since, err := parseSince(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if since.IsZero() {
+		since = time.Now()
+	}
+
+	flusher, ok := openStream(w)
+	if !ok {
+		return
+	}
+
+	poll := time.NewTicker(s.poll)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			_, _ = fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-poll.C:
+			messages, err := s.subscribe(r, since)
+			if err != nil {
+				s.log.Warn().Err(err).Str("topic", r.PathValue("topic")).Msg("event stream subscribe failed")
+				continue
+			}
+
+			for _, msg := range messages {
+				if err := writeEvent(w, msg.ID, "message", msg); err != nil {
+					continue
+				}
+				since = msg.CreatedAt
+			}
+			if len(messages) > 0 {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// subscribe returns the messages of the request's topic after since,
+// treating a topic that does not exist yet as empty.
+func (s *Server) subscribe(r *http.Request, since time.Time) ([]messaging.Message, error) {
+	messages, err := s.msgs.Subscribe(r.Context(), r.PathValue("topic"), since)
+	if errors.Is(err, messaging.ErrTopicNotFound) {
+		return []messaging.Message{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if messages == nil {
+		messages = []messaging.Message{}
+	}
+	return messages, nil
+}
+
+// parseSince reads the optional RFC 3339 "since" query parameter.
+func parseSince(r *http.Request) (time.Time, error) {
+	raw := r.URL.Query().Get("since")
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	since, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: must be RFC 3339", raw)
+	}
+	return since, nil
+}
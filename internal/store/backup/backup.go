@@ -0,0 +1,401 @@
+// Package backup snapshots hive's stores so a corrupted or mistakenly pruned
+// store can be rolled back.
+//
+// A backup is a directory under the backups directory holding copies of the
+// session store (sessions.json or sessions.db) and the message topics, plus
+// a backup.json describing why it was taken. Only the newest backups are
+// kept.
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/store/sqlite"
+)
+
+// Paths backed up, relative to the data directory.
+const (
+	sessionsFile = "sessions.json"
+	sessionsDB   = "sessions.db"
+	topicsDir    = "messages/topics"
+)
+
+const (
+	metaFile = "backup.json"
+	idLayout = "20060102-150405.000"
+)
+
+// Reasons recorded for backups hive takes on its own.
+const (
+	ReasonManual     = "manual"
+	ReasonPreRestore = "pre-restore"
+)
+
+// ErrNotFound is returned when a backup ID does not exist.
+var ErrNotFound = errors.New("backup not found")
+
+// Backup describes a snapshot on disk.
+type Backup struct {
+	ID        string    `json:"id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Path      string    `json:"path"`
+	Bytes     int64     `json:"bytes"`
+}
+
+// Manager creates, lists, rotates, and restores backups of a data directory.
+type Manager struct {
+	dataDir string
+	dir     string
+	keep    int
+}
+
+// New returns a Manager backing up dataDir into dir, keeping the newest keep
+// backups. keep <= 0 disables rotation.
+func New(dataDir, dir string, keep int) *Manager {
+	return &Manager{dataDir: dataDir, dir: dir, keep: keep}
+}
+
+// Create snapshots the stores, labelled with reason, then rotates old
+// backups. Stores that do not exist yet are skipped.
+func (m *Manager) Create(ctx context.Context, reason string) (Backup, error) {
+	b, err := m.create(ctx, reason)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	if err := m.Rotate(); err != nil {
+		return b, err
+	}
+
+	return b, nil
+}
+
+// create writes a backup without rotating.
+func (m *Manager) create(ctx context.Context, reason string) (Backup, error) {
+	now := time.Now().UTC()
+	b := Backup{
+		ID:        now.Format(idLayout),
+		Reason:    reason,
+		CreatedAt: now,
+	}
+	b.Path = filepath.Join(m.dir, b.ID)
+	// Backups taken within the same millisecond get a sequence suffix
+	for i := 1; exists(b.Path); i++ {
+		b.ID = fmt.Sprintf("%s-%d", now.Format(idLayout), i)
+		b.Path = filepath.Join(m.dir, b.ID)
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return Backup{}, fmt.Errorf("create backups directory: %w", err)
+	}
+
+	// Build in a temp directory so a failed backup never shows up in List
+	tmp, err := os.MkdirTemp(m.dir, ".tmp-")
+	if err != nil {
+		return Backup{}, fmt.Errorf("create backup directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := m.snapshot(ctx, tmp); err != nil {
+		return Backup{}, err
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return Backup{}, fmt.Errorf("marshal backup metadata: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmp, metaFile), data, 0o644); err != nil {
+		return Backup{}, fmt.Errorf("write backup metadata: %w", err)
+	}
+
+	if err := os.Rename(tmp, b.Path); err != nil {
+		return Backup{}, fmt.Errorf("finalize backup: %w", err)
+	}
+	b.Bytes = dirSize(b.Path)
+
+	return b, nil
+}
+
+// snapshot copies every existing store into dst.
+func (m *Manager) snapshot(ctx context.Context, dst string) error {
+	if src := filepath.Join(m.dataDir, sessionsFile); exists(src) {
+		if err := copyFile(src, filepath.Join(dst, sessionsFile)); err != nil {
+			return fmt.Errorf("back up %s: %w", sessionsFile, err)
+		}
+	}
+
+	if src := filepath.Join(m.dataDir, sessionsDB); exists(src) {
+		if err := sqlite.Snapshot(ctx, src, filepath.Join(dst, sessionsDB)); err != nil {
+			return fmt.Errorf("back up %s: %w", sessionsDB, err)
+		}
+	}
+
+	if src := filepath.Join(m.dataDir, topicsDir); exists(src) {
+		if err := copyDir(src, filepath.Join(dst, topicsDir)); err != nil {
+			return fmt.Errorf("back up topics: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// List returns all backups, newest first.
+func (m *Manager) List() ([]Backup, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read backups directory: %w", err)
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		b, err := m.read(e.Name())
+		if err != nil {
+			continue // not a backup, or its metadata is unreadable
+		}
+		backups = append(backups, b)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].ID > backups[j].ID
+	})
+
+	return backups, nil
+}
+
+// Get returns the backup with the given ID.
+func (m *Manager) Get(id string) (Backup, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return Backup{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+
+	b, err := m.read(id)
+	if os.IsNotExist(err) {
+		return Backup{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return b, err
+}
+
+func (m *Manager) read(id string) (Backup, error) {
+	path := filepath.Join(m.dir, id)
+	data, err := os.ReadFile(filepath.Join(path, metaFile))
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var b Backup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return Backup{}, fmt.Errorf("parse backup metadata: %w", err)
+	}
+	b.ID = id
+	b.Path = path
+	b.Bytes = dirSize(path)
+
+	return b, nil
+}
+
+// Rotate removes all but the newest backups.
+func (m *Manager) Rotate() error {
+	if m.keep <= 0 {
+		return nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	for _, b := range backups[min(m.keep, len(backups)):] {
+		if err := os.RemoveAll(b.Path); err != nil {
+			return fmt.Errorf("remove backup %s: %w", b.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Restore replaces the stores with the contents of backup id. The current
+// state is backed up first with reason "pre-restore", so a restore can be
+// undone. Stores missing from the backup are left as they are.
+//
+// Nothing else should have the stores open while restoring.
+func (m *Manager) Restore(ctx context.Context, id string) (Backup, error) {
+	b, err := m.Get(id)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	// Rotate only once restored, so the backup being restored survives
+	if _, err := m.create(ctx, ReasonPreRestore); err != nil {
+		return Backup{}, fmt.Errorf("back up current state: %w", err)
+	}
+
+	if src := filepath.Join(b.Path, sessionsFile); exists(src) {
+		if err := replaceFile(src, filepath.Join(m.dataDir, sessionsFile)); err != nil {
+			return Backup{}, fmt.Errorf("restore %s: %w", sessionsFile, err)
+		}
+	}
+
+	if src := filepath.Join(b.Path, sessionsDB); exists(src) {
+		dst := filepath.Join(m.dataDir, sessionsDB)
+		// The snapshot is self-contained; a stale WAL would be replayed over it
+		for _, suffix := range []string{"-wal", "-shm"} {
+			if err := os.Remove(dst + suffix); err != nil && !os.IsNotExist(err) {
+				return Backup{}, fmt.Errorf("restore %s: %w", sessionsDB, err)
+			}
+		}
+		if err := replaceFile(src, dst); err != nil {
+			return Backup{}, fmt.Errorf("restore %s: %w", sessionsDB, err)
+		}
+	}
+
+	if src := filepath.Join(b.Path, topicsDir); exists(src) {
+		if err := replaceDir(src, filepath.Join(m.dataDir, topicsDir)); err != nil {
+			return Backup{}, fmt.Errorf("restore topics: %w", err)
+		}
+	}
+
+	return b, m.Rotate()
+}
+
+// RecoverJSON restores the file at rel, relative to the data directory, from
+// the newest backup holding a copy that parses as JSON. Returns ErrNotFound
+// if no backup has one.
+func (m *Manager) RecoverJSON(rel string) (Backup, error) {
+	backups, err := m.List()
+	if err != nil {
+		return Backup{}, err
+	}
+
+	for _, b := range backups {
+		src := filepath.Join(b.Path, rel)
+		data, err := os.ReadFile(src)
+		if err != nil || !json.Valid(data) {
+			continue
+		}
+		if err := replaceFile(src, filepath.Join(m.dataDir, rel)); err != nil {
+			return Backup{}, fmt.Errorf("recover %s: %w", rel, err)
+		}
+		return b, nil
+	}
+
+	return Backup{}, fmt.Errorf("%w: no valid copy of %s", ErrNotFound, rel)
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// copyFile copies src to dst, creating dst's parent directories.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyDir copies the regular files under src to dst, skipping lock and
+// temp files.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(path, ".lock") || strings.HasSuffix(path, ".tmp") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dst, rel))
+	})
+}
+
+// replaceFile atomically replaces dst with a copy of src.
+func replaceFile(src, dst string) error {
+	tmp := dst + ".restore"
+	if err := copyFile(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// replaceDir swaps dst for a copy of src.
+func replaceDir(src, dst string) error {
+	tmp := dst + ".restore"
+	old := dst + ".old"
+	_ = os.RemoveAll(tmp)
+	_ = os.RemoveAll(old)
+
+	if err := copyDir(src, tmp); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	if err := os.Rename(dst, old); err != nil && !os.IsNotExist(err) {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Rename(old, dst)
+		return err
+	}
+
+	return os.RemoveAll(old)
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,147 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/store/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	m := New(dataDir, filepath.Join(dataDir, "backups"), 10)
+
+	writeFile(t, filepath.Join(dataDir, sessionsFile), `{"sessions":[{"id":"a"}]}`)
+	writeFile(t, filepath.Join(dataDir, topicsDir, "agent.a.inbox.json"), `{"name":"agent.a.inbox"}`)
+	writeFile(t, filepath.Join(dataDir, topicsDir, "agent.a.inbox.json.lock"), "")
+
+	b, err := m.Create(ctx, "prune")
+	require.NoError(t, err)
+	assert.Equal(t, "prune", b.Reason)
+	assert.Positive(t, b.Bytes)
+	assert.NoFileExists(t, filepath.Join(b.Path, topicsDir, "agent.a.inbox.json.lock"))
+
+	// Clobber the stores, then roll back
+	writeFile(t, filepath.Join(dataDir, sessionsFile), `{"sessions":[]}`)
+	require.NoError(t, os.RemoveAll(filepath.Join(dataDir, topicsDir)))
+	writeFile(t, filepath.Join(dataDir, topicsDir, "other.json"), `{}`)
+
+	restored, err := m.Restore(ctx, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, restored.ID)
+
+	assert.JSONEq(t, `{"sessions":[{"id":"a"}]}`, readFile(t, filepath.Join(dataDir, sessionsFile)))
+	assert.FileExists(t, filepath.Join(dataDir, topicsDir, "agent.a.inbox.json"))
+	assert.NoFileExists(t, filepath.Join(dataDir, topicsDir, "other.json"))
+
+	// The clobbered state was kept as a pre-restore backup
+	backups, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, ReasonPreRestore, backups[0].Reason)
+	assert.JSONEq(t, `{"sessions":[]}`, readFile(t, filepath.Join(backups[0].Path, sessionsFile)))
+}
+
+func TestCreateSQLite(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	m := New(dataDir, filepath.Join(dataDir, "backups"), 10)
+
+	store, err := sqlite.New(filepath.Join(dataDir, sessionsDB))
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, session.Session{ID: "a"}))
+
+	// Snapshot while the store is still open
+	b, err := m.Create(ctx, ReasonManual)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, session.Session{ID: "b"}))
+	require.NoError(t, store.Close())
+
+	_, err = m.Restore(ctx, b.ID)
+	require.NoError(t, err)
+
+	store, err = sqlite.New(filepath.Join(dataDir, sessionsDB))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	sessions, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "a", sessions[0].ID)
+}
+
+func TestRotate(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	m := New(dataDir, filepath.Join(dataDir, "backups"), 2)
+	writeFile(t, filepath.Join(dataDir, sessionsFile), `{"sessions":[]}`)
+
+	var ids []string
+	for range 3 {
+		b, err := m.Create(ctx, ReasonManual)
+		require.NoError(t, err)
+		ids = append(ids, b.ID)
+	}
+
+	backups, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, ids[2], backups[0].ID)
+	assert.Equal(t, ids[1], backups[1].ID)
+
+	// Restoring the oldest kept backup must not rotate it away first
+	_, err = m.Restore(ctx, ids[1])
+	require.NoError(t, err)
+}
+
+func TestGetNotFound(t *testing.T) {
+	m := New(t.TempDir(), t.TempDir(), 10)
+
+	for _, id := range []string{"missing", "../etc", ""} {
+		_, err := m.Get(id)
+		assert.ErrorIs(t, err, ErrNotFound, id)
+	}
+}
+
+func TestRecoverJSON(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+	m := New(dataDir, filepath.Join(dataDir, "backups"), 10)
+	path := filepath.Join(dataDir, sessionsFile)
+
+	_, err := m.RecoverJSON(sessionsFile)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	writeFile(t, path, `{"sessions":[{"id":"good"}]}`)
+	good, err := m.Create(ctx, ReasonManual)
+	require.NoError(t, err)
+
+	// A backup taken after the file broke is skipped
+	writeFile(t, path, `{"sessions":[`)
+	_, err = m.Create(ctx, ReasonManual)
+	require.NoError(t, err)
+
+	b, err := m.RecoverJSON(sessionsFile)
+	require.NoError(t, err)
+	assert.Equal(t, good.ID, b.ID)
+	assert.JSONEq(t, `{"sessions":[{"id":"good"}]}`, readFile(t, path))
+}
@@ -0,0 +1,190 @@
+package jsonfile
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+)
+
+// archiveDir returns the directory holding archived messages. It sits next
+// to the topics directory so archived messages never show up as topics.
+func (s *MsgStore) archiveDir() string {
+	return filepath.Join(filepath.Dir(s.topicsDir), "archive")
+}
+
+// archiveTopicDir returns the directory of a topic's archive files.
+func (s *MsgStore) archiveTopicDir(topic string) string {
+	return filepath.Join(s.archiveDir(), strings.ReplaceAll(topic, "/", "_"))
+}
+
+// Archive moves messages older than olderThan from the topics matching
+// pattern into gzip-compressed archive files, one per topic per run. A
+// topic is only trimmed once its archive file is written. Returns the number
+// of messages archived per topic; topics with nothing to archive are omitted.
+// Expired messages are dropped rather than archived.
+func (s *MsgStore) Archive(ctx context.Context, pattern string, olderThan time.Duration) (map[string]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	topics, err := s.matchingTopics(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	archived := make(map[string]int)
+
+	for _, t := range topics {
+		err := s.withExclusiveLock(t, func() error {
+			topic, err := s.loadTopic(t)
+			if err != nil {
+				return err
+			}
+
+			topic.Messages = dropExpired(topic.Messages, time.Now())
+
+			var old, kept []messaging.Message
+			for _, msg := range topic.Messages {
+				if msg.CreatedAt.After(cutoff) {
+					kept = append(kept, msg)
+				} else {
+					old = append(old, msg)
+				}
+			}
+			if len(old) == 0 {
+				return nil
+			}
+
+			if err := s.writeArchive(t, old); err != nil {
+				return err
+			}
+
+			topic.Messages = kept
+			topic.UpdatedAt = time.Now()
+			if err := s.saveTopic(topic); err != nil {
+				return err
+			}
+			s.recordTopic(topic)
+			archived[t] = len(old)
+			return nil
+		})
+		if err != nil {
+			return archived, fmt.Errorf("archive topic %s: %w", t, err)
+		}
+	}
+
+	return archived, nil
+}
+
+// writeArchive writes messages to a new gzip-compressed archive file for
+// topic, atomically.
+func (s *MsgStore) writeArchive(topic string, messages []messaging.Message) error {
+	dir := s.archiveTopicDir(topic)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create archive directory: %w", err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%d.json.gz", time.Now().UnixNano()))
+	tmp := path + ".tmp"
+
+	if err := writeGzipJSON(tmp, messages); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("write archive file: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename archive file: %w", err)
+	}
+	return nil
+}
+
+// writeGzipJSON writes v to path as gzip-compressed JSON.
+func writeGzipJSON(path string, v any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	zw := gzip.NewWriter(f)
+	if err := json.NewEncoder(zw).Encode(v); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Archived returns the archived messages of the topics matching pattern
+// created after since, oldest first.
+func (s *MsgStore) Archived(ctx context.Context, pattern string, since time.Time) ([]messaging.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entries, err := os.ReadDir(s.archiveDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read archive directory: %w", err)
+	}
+
+	var topics []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			topics = append(topics, strings.ReplaceAll(entry.Name(), "_", "/"))
+		}
+	}
+
+	var messages []messaging.Message
+	for _, t := range filterTopics(topics, pattern) {
+		files, err := filepath.Glob(filepath.Join(s.archiveTopicDir(t), "*.json.gz"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			msgs, err := readArchive(path)
+			if err != nil {
+				return nil, err
+			}
+			for _, msg := range msgs {
+				if since.IsZero() || msg.CreatedAt.After(since) {
+					messages = append(messages, msg)
+				}
+			}
+		}
+	}
+
+	sortMessages(messages)
+	return messages, nil
+}
+
+// readArchive reads the messages of one archive file.
+func readArchive(path string) ([]messaging.Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("read archive file %s: %w: %w", path, ErrCorrupt, err)
+	}
+	defer zr.Close() //nolint:errcheck
+
+	var messages []messaging.Message
+	if err := json.NewDecoder(zr).Decode(&messages); err != nil {
+		return nil, fmt.Errorf("parse archive file %s: %w: %w", path, ErrCorrupt, err)
+	}
+	return messages, nil
+}
@@ -0,0 +1,133 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+)
+
+// indexFile is the message index, kept beside the topic files. Its name does
+// not end in .json so it is never listed as a topic.
+const indexFile = ".index"
+
+// TopicStats summarises the messages in a topic.
+type TopicStats struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"` // CreatedAt of the newest message
+}
+
+// indexEntry is a topic's stats and the size and modification time of its
+// file when they were recorded. An entry whose file has changed since, for
+// example when written by a hive without the index, is ignored.
+type indexEntry struct {
+	TopicStats
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+func (e indexEntry) equal(o indexEntry) bool {
+	return e.Count == o.Count && e.Last.Equal(o.Last) && e.Size == o.Size && e.ModTime.Equal(o.ModTime)
+}
+
+// msgIndex lets readers skip topics without reading them: Subscribe passes
+// over topics with nothing newer than since, and Latest reads topics newest
+// first and stops once older topics cannot contribute.
+type msgIndex struct {
+	Topics map[string]indexEntry `json:"topics"`
+}
+
+// stats returns the recorded stats for topic if its file is unchanged.
+func (idx msgIndex) stats(s *MsgStore, topic string) (TopicStats, bool) {
+	entry, ok := idx.Topics[topic]
+	if !ok {
+		return TopicStats{}, false
+	}
+
+	info, err := os.Stat(s.topicPath(topic))
+	if err != nil || info.Size() != entry.Size || !info.ModTime().Equal(entry.ModTime) {
+		return TopicStats{}, false
+	}
+	return entry.TopicStats, true
+}
+
+func (s *MsgStore) indexPath() string {
+	return filepath.Join(s.topicsDir, indexFile)
+}
+
+// readIndex returns the index, or an empty one if it is missing or cannot be
+// read. The index is only an optimisation, so problems with it are not errors.
+func (s *MsgStore) readIndex() msgIndex {
+	idx := msgIndex{}
+	_ = s.withLockFile(s.indexPath()+".lock", syscall.LOCK_SH, func() error {
+		idx = s.loadIndex()
+		return nil
+	})
+	return idx
+}
+
+func (s *MsgStore) loadIndex() msgIndex {
+	idx := msgIndex{}
+	if data, err := os.ReadFile(s.indexPath()); err == nil {
+		_ = json.Unmarshal(data, &idx)
+	}
+	if idx.Topics == nil {
+		idx.Topics = make(map[string]indexEntry)
+	}
+	return idx
+}
+
+// recordTopic updates the index entry for topic after its file was written
+// or read. Callers must hold the topic's file lock so the file matches topic.
+func (s *MsgStore) recordTopic(topic messaging.Topic) {
+	info, err := os.Stat(s.topicPath(topic.Name))
+	if err != nil {
+		return
+	}
+
+	entry := indexEntry{
+		TopicStats: topicStats(topic),
+		Size:       info.Size(),
+		ModTime:    info.ModTime(),
+	}
+
+	_ = s.withLockFile(s.indexPath()+".lock", syscall.LOCK_EX, func() error {
+		idx := s.loadIndex()
+		if current, ok := idx.Topics[topic.Name]; ok && current.equal(entry) {
+			return nil
+		}
+		idx.Topics[topic.Name] = entry
+		return s.saveIndex(idx)
+	})
+}
+
+func (s *MsgStore) saveIndex(idx msgIndex) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshal index: %w", err)
+	}
+
+	tmp := s.indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write index: %w", err)
+	}
+	if err := os.Rename(tmp, s.indexPath()); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename index: %w", err)
+	}
+	return nil
+}
+
+func topicStats(topic messaging.Topic) TopicStats {
+	stats := TopicStats{Count: len(topic.Messages)}
+	for _, msg := range topic.Messages {
+		if msg.CreatedAt.After(stats.Last) {
+			stats.Last = msg.CreatedAt
+		}
+	}
+	return stats
+}
@@ -0,0 +1,97 @@
+package jsonfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/messaging"
+)
+
+// rateFile records recent publish times per sender, kept beside the topic
+// files. Like the index, its name does not end in .json.
+const rateFile = ".senders"
+
+// rateLedger maps a sender to the times of its publishes within the current
+// rate limit window.
+type rateLedger struct {
+	Senders map[string][]time.Time `json:"senders"`
+}
+
+// WithRateLimit limits each sender to n messages per window across all
+// topics. A non-positive n disables the limit.
+func (s *MsgStore) WithRateLimit(n int, per time.Duration) *MsgStore {
+	s.rateLimit = n
+	s.ratePer = per
+	return s
+}
+
+func (s *MsgStore) ratePath() string {
+	return filepath.Join(s.topicsDir, rateFile)
+}
+
+// allowPublish records a publish by msg's sender at now, or returns an error
+// wrapping messaging.ErrRateLimited if the sender has used its allowance.
+// Messages without a sender identity are not limited.
+func (s *MsgStore) allowPublish(msg messaging.Message, now time.Time) error {
+	sender := msg.SenderID()
+	if s.rateLimit <= 0 || s.ratePer <= 0 || sender == "" {
+		return nil
+	}
+
+	return s.withLockFile(s.ratePath()+".lock", syscall.LOCK_EX, func() error {
+		ledger := s.loadRateLedger()
+		cutoff := now.Add(-s.ratePer)
+		for name, times := range ledger.Senders {
+			times = slices.DeleteFunc(times, func(t time.Time) bool { return !t.After(cutoff) })
+			if len(times) == 0 {
+				delete(ledger.Senders, name)
+				continue
+			}
+			ledger.Senders[name] = times
+		}
+
+		if times := ledger.Senders[sender]; len(times) >= s.rateLimit {
+			retry := times[0].Add(s.ratePer).Sub(now).Round(time.Second)
+			return fmt.Errorf("%w: sender %q published %d messages in the last %s (retry in %s)",
+				messaging.ErrRateLimited, sender, len(times), s.ratePer, max(retry, time.Second))
+		}
+
+		ledger.Senders[sender] = append(ledger.Senders[sender], now)
+		return s.saveRateLedger(ledger)
+	})
+}
+
+// loadRateLedger reads the ledger, starting fresh if it is missing or
+// unreadable.
+func (s *MsgStore) loadRateLedger() rateLedger {
+	ledger := rateLedger{}
+	if data, err := os.ReadFile(s.ratePath()); err == nil {
+		_ = json.Unmarshal(data, &ledger)
+	}
+	if ledger.Senders == nil {
+		ledger.Senders = make(map[string][]time.Time)
+	}
+	return ledger
+}
+
+func (s *MsgStore) saveRateLedger(ledger rateLedger) error {
+	data, err := json.Marshal(ledger)
+	if err != nil {
+		return fmt.Errorf("marshal rate ledger: %w", err)
+	}
+
+	tmp := s.ratePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write rate ledger: %w", err)
+	}
+	if err := os.Rename(tmp, s.ratePath()); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("rename rate ledger: %w", err)
+	}
+	return nil
+}
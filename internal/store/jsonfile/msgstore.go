@@ -24,6 +24,8 @@ const defaultMaxMessages = 100
 type MsgStore struct {
 	topicsDir   string
 	maxMessages int
+	rateLimit   int // messages per sender per ratePer; 0 means no limit
+	ratePer     time.Duration
 	mu          sync.RWMutex
 }
 
@@ -67,11 +69,16 @@ func (s *MsgStore) withExclusiveLock(topic string, fn func() error) error {
 
 // withFileLock acquires a file lock, executes fn, then releases the lock.
 func (s *MsgStore) withFileLock(topic string, lockType int, fn func() error) error {
+	return s.withLockFile(s.lockPath(topic), lockType, fn)
+}
+
+// withLockFile executes fn while holding a lock of lockType on path.
+func (s *MsgStore) withLockFile(path string, lockType int, fn func() error) error {
 	if err := os.MkdirAll(s.topicsDir, 0o755); err != nil {
 		return fmt.Errorf("create topics directory: %w", err)
 	}
 
-	f, err := os.OpenFile(s.lockPath(topic), os.O_CREATE|os.O_RDWR, 0o644)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
 	if err != nil {
 		return fmt.Errorf("open lock file: %w", err)
 	}
@@ -93,10 +100,16 @@ func generateID() string {
 }
 
 // Publish adds a message to a topic, creating the topic if it doesn't exist.
+// Returns an error wrapping messaging.ErrRateLimited if the message's sender
+// is over the store's rate limit.
 func (s *MsgStore) Publish(ctx context.Context, msg messaging.Message) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.allowPublish(msg, time.Now()); err != nil {
+		return err
+	}
+
 	return s.withExclusiveLock(msg.Topic, func() error {
 		topic, err := s.loadTopic(msg.Topic)
 		if err != nil {
@@ -111,7 +124,7 @@ func (s *MsgStore) Publish(ctx context.Context, msg messaging.Message) error {
 			msg.CreatedAt = time.Now()
 		}
 
-		topic.Messages = append(topic.Messages, msg)
+		topic.Messages = append(dropExpired(topic.Messages, time.Now()), msg)
 		topic.UpdatedAt = time.Now()
 
 		// Enforce retention limit
@@ -119,15 +132,27 @@ func (s *MsgStore) Publish(ctx context.Context, msg messaging.Message) error {
 			topic.Messages = topic.Messages[len(topic.Messages)-s.maxMessages:]
 		}
 
-		return s.saveTopic(topic)
+		if err := s.saveTopic(topic); err != nil {
+			return err
+		}
+		s.recordTopic(topic)
+		return nil
 	})
 }
 
+// dropExpired returns messages without those expired at now, reusing the
+// slice's storage.
+func dropExpired(messages []messaging.Message, now time.Time) []messaging.Message {
+	return slices.DeleteFunc(messages, func(m messaging.Message) bool { return m.Expired(now) })
+}
+
 // Subscribe returns all messages for a topic pattern, optionally filtered by since timestamp.
+// Expired messages are never returned.
 // The topic parameter supports wildcards:
 //   - "*" or "" returns messages from all topics
 //   - "prefix.*" matches topics starting with "prefix."
 //
+// Topics the index shows have nothing newer than since are not read.
 // Returns ErrTopicNotFound if no matching topics exist.
 func (s *MsgStore) Subscribe(ctx context.Context, topic string, since time.Time) ([]messaging.Message, error) {
 	s.mu.RLock()
@@ -142,34 +167,154 @@ func (s *MsgStore) Subscribe(ctx context.Context, topic string, since time.Time)
 		return nil, messaging.ErrTopicNotFound
 	}
 
+	idx := s.readIndex()
 	var messages []messaging.Message
 	for _, t := range topics {
-		err := s.withSharedLock(t, func() error {
-			topicData, err := s.loadTopic(t)
-			if err != nil {
-				return err
-			}
+		if stats, ok := idx.stats(s, t); ok && !hasNewer(stats, since) {
+			continue
+		}
 
-			for _, msg := range topicData.Messages {
-				if since.IsZero() || msg.CreatedAt.After(since) {
-					messages = append(messages, msg)
-				}
-			}
-			return nil
-		})
+		msgs, err := s.readTopic(t, since)
 		if err != nil {
 			return nil, err
 		}
+		messages = append(messages, msgs...)
 	}
 
-	// Sort by creation time
-	sort.Slice(messages, func(i, j int) bool {
-		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	sortMessages(messages)
+	return messages, nil
+}
+
+// Latest returns the newest n messages after since for a topic pattern,
+// oldest first. Topics are read newest first using the index, stopping once
+// the remaining topics only hold older messages than those collected.
+// Returns ErrTopicNotFound if no matching topics exist.
+func (s *MsgStore) Latest(ctx context.Context, topic string, since time.Time, n int) ([]messaging.Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	topics, err := s.matchingTopics(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(topics) == 0 {
+		return nil, messaging.ErrTopicNotFound
+	}
+
+	type candidate struct {
+		name  string
+		stats TopicStats
+		known bool
+	}
+
+	idx := s.readIndex()
+	candidates := make([]candidate, 0, len(topics))
+	for _, t := range topics {
+		stats, ok := idx.stats(s, t)
+		if ok && !hasNewer(stats, since) {
+			continue
+		}
+		candidates = append(candidates, candidate{name: t, stats: stats, known: ok})
+	}
+
+	// Topics missing from the index have to be read regardless, so go first.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.known != b.known {
+			return !a.known
+		}
+		return a.stats.Last.After(b.stats.Last)
 	})
 
+	var messages []messaging.Message
+	for _, c := range candidates {
+		if c.known && n > 0 && len(messages) >= n {
+			sortMessages(messages)
+			if !c.stats.Last.After(messages[len(messages)-n].CreatedAt) {
+				break
+			}
+		}
+
+		msgs, err := s.readTopic(c.name, since)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msgs...)
+	}
+
+	sortMessages(messages)
+	if n > 0 && len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
 	return messages, nil
 }
 
+// Stats returns the message count and newest message time of each topic,
+// from the index where it is current. Topics that cannot be read are omitted.
+func (s *MsgStore) Stats(ctx context.Context) (map[string]TopicStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	topics, err := s.listTopicsUnsafe()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := s.readIndex()
+	result := make(map[string]TopicStats, len(topics))
+	for _, t := range topics {
+		if stats, ok := idx.stats(s, t); ok {
+			result[t] = stats
+			continue
+		}
+
+		msgs, err := s.readTopic(t, time.Time{})
+		if err != nil {
+			continue
+		}
+		result[t] = topicStats(messaging.Topic{Messages: msgs})
+	}
+	return result, nil
+}
+
+// readTopic returns the messages of a topic after since, and brings the
+// topic's index entry up to date.
+func (s *MsgStore) readTopic(name string, since time.Time) ([]messaging.Message, error) {
+	var messages []messaging.Message
+	err := s.withSharedLock(name, func() error {
+		topic, err := s.loadTopic(name)
+		if err != nil {
+			return err
+		}
+		s.recordTopic(topic)
+
+		now := time.Now()
+		for _, msg := range topic.Messages {
+			if msg.Expired(now) {
+				continue
+			}
+			if since.IsZero() || msg.CreatedAt.After(since) {
+				messages = append(messages, msg)
+			}
+		}
+		return nil
+	})
+	return messages, err
+}
+
+// hasNewer reports whether a topic with stats may hold messages after since.
+func hasNewer(stats TopicStats, since time.Time) bool {
+	return stats.Count > 0 && (since.IsZero() || stats.Last.After(since))
+}
+
+// sortMessages sorts messages by creation time.
+func sortMessages(messages []messaging.Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+}
+
 // List returns all topic names (sorted).
 func (s *MsgStore) List(ctx context.Context) ([]string, error) {
 	s.mu.RLock()
@@ -183,8 +328,8 @@ func (s *MsgStore) List(ctx context.Context) ([]string, error) {
 	return topics, nil
 }
 
-// Prune removes messages older than the given duration across all topics.
-// Returns the number of messages removed.
+// Prune removes messages older than the given duration, and expired
+// messages, across all topics. Returns the number of messages removed.
 func (s *MsgStore) Prune(ctx context.Context, olderThan time.Duration) (int, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -204,9 +349,10 @@ func (s *MsgStore) Prune(ctx context.Context, olderThan time.Duration) (int, err
 				return err
 			}
 
+			now := time.Now()
 			var kept []messaging.Message
 			for _, msg := range topic.Messages {
-				if msg.CreatedAt.After(cutoff) {
+				if msg.CreatedAt.After(cutoff) && !msg.Expired(now) {
 					kept = append(kept, msg)
 				} else {
 					removed++
@@ -216,7 +362,10 @@ func (s *MsgStore) Prune(ctx context.Context, olderThan time.Duration) (int, err
 			if len(kept) != len(topic.Messages) {
 				topic.Messages = kept
 				topic.UpdatedAt = time.Now()
-				return s.saveTopic(topic)
+				if err := s.saveTopic(topic); err != nil {
+					return err
+				}
+				s.recordTopic(topic)
 			}
 			return nil
 		})
@@ -234,10 +383,14 @@ func (s *MsgStore) matchingTopics(pattern string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	return filterTopics(topics, pattern), nil
+}
 
+// filterTopics returns the topics matching the given pattern.
+func filterTopics(topics []string, pattern string) []string {
 	// Empty pattern or "*" matches all topics
 	if pattern == "" || pattern == "*" {
-		return topics, nil
+		return topics
 	}
 
 	// Wildcard pattern like "prefix.*"
@@ -249,15 +402,15 @@ func (s *MsgStore) matchingTopics(pattern string) ([]string, error) {
 				matched = append(matched, t)
 			}
 		}
-		return matched, nil
+		return matched
 	}
 
 	// Exact match
 	if slices.Contains(topics, pattern) {
-		return []string{pattern}, nil
+		return []string{pattern}
 	}
 
-	return nil, nil
+	return nil
 }
 
 // listTopicsUnsafe returns all topic names without locking.
@@ -318,7 +471,7 @@ func (s *MsgStore) loadTopic(name string) (messaging.Topic, error) {
 
 	var topic messaging.Topic
 	if err := json.Unmarshal(data, &topic); err != nil {
-		return messaging.Topic{}, fmt.Errorf("parse topic file: %w", err)
+		return messaging.Topic{}, fmt.Errorf("parse topic file %s: %w: %w", path, ErrCorrupt, err)
 	}
 
 	return topic, nil
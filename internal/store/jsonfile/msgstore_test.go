@@ -244,6 +244,109 @@ func TestMsgStore_Prune(t *testing.T) {
 	}
 }
 
+func TestMsgStore_Expiry(t *testing.T) {
+	store := NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+	ctx := context.Background()
+
+	_ = store.Publish(ctx, messaging.Message{Topic: "status", Payload: "migrating", ExpiresAt: time.Now().Add(50 * time.Millisecond)})
+	_ = store.Publish(ctx, messaging.Message{Topic: "status", Payload: "deployed"})
+
+	messages, _ := store.Subscribe(ctx, "status", time.Time{})
+	if len(messages) != 2 {
+		t.Fatalf("Subscribe returned %d messages before expiry, want 2", len(messages))
+	}
+
+	time.Sleep(60 * time.Millisecond)
+
+	messages, _ = store.Subscribe(ctx, "status", time.Time{})
+	if len(messages) != 1 || messages[0].Payload != "deployed" {
+		t.Fatalf("Subscribe after expiry = %v, want only the unexpiring message", messages)
+	}
+
+	// The next publish drops the expired message from the topic file
+	_ = store.Publish(ctx, messaging.Message{Topic: "status", Payload: "done"})
+	topic, err := store.loadTopic("status")
+	if err != nil {
+		t.Fatalf("loadTopic failed: %v", err)
+	}
+	if len(topic.Messages) != 2 {
+		t.Errorf("topic holds %d messages, want 2 after expired message is dropped", len(topic.Messages))
+	}
+}
+
+func TestMsgStore_RateLimit(t *testing.T) {
+	store := NewMsgStore(filepath.Join(t.TempDir(), "topics")).WithRateLimit(2, 100*time.Millisecond)
+	ctx := context.Background()
+
+	for i, topic := range []string{"a", "b"} {
+		if err := store.Publish(ctx, messaging.Message{Topic: topic, Sender: "loop", Payload: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Publish %d failed: %v", i, err)
+		}
+	}
+
+	// The limit spans topics
+	err := store.Publish(ctx, messaging.Message{Topic: "c", Sender: "loop", Payload: "flood"})
+	if !errors.Is(err, messaging.ErrRateLimited) {
+		t.Fatalf("Publish over limit error = %v, want ErrRateLimited", err)
+	}
+	if _, err := store.Subscribe(ctx, "c", time.Time{}); !errors.Is(err, messaging.ErrTopicNotFound) {
+		t.Errorf("rejected message created topic c (err = %v)", err)
+	}
+
+	// Other senders are unaffected
+	if err := store.Publish(ctx, messaging.Message{Topic: "a", SessionID: "other", Payload: "hi"}); err != nil {
+		t.Errorf("Publish from another sender failed: %v", err)
+	}
+
+	time.Sleep(110 * time.Millisecond)
+
+	if err := store.Publish(ctx, messaging.Message{Topic: "a", Sender: "loop", Payload: "later"}); err != nil {
+		t.Errorf("Publish after window failed: %v", err)
+	}
+}
+
+func TestMsgStore_Archive(t *testing.T) {
+	store := NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+	ctx := context.Background()
+
+	old := time.Now().Add(-48 * time.Hour)
+	_ = store.Publish(ctx, messaging.Message{Topic: "build.main", Payload: "old build", CreatedAt: old})
+	_ = store.Publish(ctx, messaging.Message{Topic: "build.main", Payload: "new build"})
+	_ = store.Publish(ctx, messaging.Message{Topic: "deploy", Payload: "old deploy", CreatedAt: old})
+
+	archived, err := store.Archive(ctx, "build.*", 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+	if len(archived) != 1 || archived["build.main"] != 1 {
+		t.Errorf("Archive = %v, want map[build.main:1]", archived)
+	}
+
+	messages, _ := store.Subscribe(ctx, "*", time.Time{})
+	if len(messages) != 2 {
+		t.Fatalf("Subscribe returned %d messages after archive, want 2", len(messages))
+	}
+
+	// Archiving again finds nothing new
+	archived, err = store.Archive(ctx, "build.*", 24*time.Hour)
+	if err != nil || len(archived) != 0 {
+		t.Errorf("second Archive = %v, %v; want nothing archived", archived, err)
+	}
+
+	got, err := store.Archived(ctx, "*", time.Time{})
+	if err != nil {
+		t.Fatalf("Archived failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Payload != "old build" {
+		t.Errorf("Archived = %v, want the old build message", got)
+	}
+
+	got, _ = store.Archived(ctx, "*", time.Now().Add(-time.Hour))
+	if len(got) != 0 {
+		t.Errorf("Archived since an hour ago = %v, want none", got)
+	}
+}
+
 func TestMsgStore_ConcurrentAccess(t *testing.T) {
 	store := NewMsgStore(filepath.Join(t.TempDir(), "topics"))
 	ctx := context.Background()
@@ -355,3 +458,114 @@ func TestMsgStore_WildcardOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestMsgStore_Latest(t *testing.T) {
+	store := NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+
+	publish := func(topic string, minute int) {
+		t.Helper()
+		msg := messaging.Message{Topic: topic, Payload: fmt.Sprintf("%s-%d", topic, minute), CreatedAt: base.Add(time.Duration(minute) * time.Minute)}
+		if err := store.Publish(ctx, msg); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	publish("old", 1)
+	publish("old", 2)
+	publish("new", 3)
+	publish("mid", 4)
+	publish("new", 5)
+
+	messages, err := store.Latest(ctx, "*", time.Time{}, 3)
+	if err != nil {
+		t.Fatalf("Latest failed: %v", err)
+	}
+	var got []string
+	for _, m := range messages {
+		got = append(got, m.Payload)
+	}
+	want := []string{"new-3", "mid-4", "new-5"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Latest = %v, want %v", got, want)
+	}
+
+	messages, err = store.Latest(ctx, "old", base.Add(90*time.Second), 5)
+	if err != nil {
+		t.Fatalf("Latest with since failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Payload != "old-2" {
+		t.Errorf("Latest with since = %v, want [old-2]", messages)
+	}
+}
+
+func TestMsgStore_Stats(t *testing.T) {
+	store := NewMsgStore(filepath.Join(t.TempDir(), "topics"))
+	ctx := context.Background()
+
+	for i := range 3 {
+		if err := store.Publish(ctx, messaging.Message{Topic: "a", Payload: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+	if err := store.Publish(ctx, messaging.Message{Topic: "b", Payload: "x"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	stats, err := store.Stats(ctx)
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats["a"].Count != 3 || stats["b"].Count != 1 {
+		t.Errorf("Stats = %+v, want a=3 b=1", stats)
+	}
+	if stats["a"].Last.IsZero() {
+		t.Error("Last should be set")
+	}
+}
+
+func TestMsgStore_IndexSkipsUnchangedTopics(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "topics")
+	store := NewMsgStore(dir)
+	ctx := context.Background()
+
+	if err := store.Publish(ctx, messaging.Message{Topic: "quiet", Payload: "old"}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	after := time.Now().Add(time.Second)
+
+	// A topic with nothing newer than since is not read
+	idx := store.readIndex()
+	if _, ok := idx.stats(store, "quiet"); !ok {
+		t.Fatal("index should have a current entry after Publish")
+	}
+	messages, err := store.Subscribe(ctx, "*", after)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if len(messages) != 0 {
+		t.Errorf("Subscribe returned %d messages, want 0", len(messages))
+	}
+
+	// A topic file changed behind the index's back is read again
+	topic := messaging.Topic{Name: "quiet", Messages: []messaging.Message{{ID: "ext", Topic: "quiet", Payload: "external", CreatedAt: after.Add(time.Minute)}}}
+	if err := store.saveTopic(topic); err != nil {
+		t.Fatalf("saveTopic failed: %v", err)
+	}
+	messages, err = store.Subscribe(ctx, "*", after)
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+	if len(messages) != 1 || messages[0].Payload != "external" {
+		t.Errorf("Subscribe = %v, want the externally written message", messages)
+	}
+
+	// The index file is not listed as a topic
+	topics, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(topics) != 1 {
+		t.Errorf("List = %v, want [quiet]", topics)
+	}
+}
@@ -4,20 +4,29 @@ package jsonfile
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"syscall"
 
 	"github.com/hay-kot/hive/internal/core/session"
 )
 
+// ErrCorrupt is wrapped by errors for data files that cannot be parsed.
+var ErrCorrupt = errors.New("corrupt data file")
+
 // SessionFile is the root JSON structure stored on disk.
 type SessionFile struct {
 	Sessions []session.Session `json:"sessions"`
 }
 
 // Store implements session.Store using a JSON file for persistence.
+//
+// Reads hold a shared flock on a sibling .lock file and writes hold an
+// exclusive one, so separate hive processes serialize their
+// read-modify-write cycles instead of overwriting each other's changes.
 type Store struct {
 	path string
 	mu   sync.RWMutex
@@ -33,7 +42,11 @@ func (s *Store) List(ctx context.Context) ([]session.Session, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	file, err := s.load()
+	var file SessionFile
+	err := s.withFileLock(syscall.LOCK_SH, func() (err error) {
+		file, err = s.load()
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -41,12 +54,26 @@ func (s *Store) List(ctx context.Context) ([]session.Session, error) {
 	return file.Sessions, nil
 }
 
+// Find returns the sessions matching f. The file is read whole, but only the
+// matching sessions are returned.
+func (s *Store) Find(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	sessions, err := s.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return f.Apply(sessions), nil
+}
+
 // Get returns a session by ID. Returns ErrNotFound if not found.
 func (s *Store) Get(ctx context.Context, id string) (session.Session, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	file, err := s.load()
+	var file SessionFile
+	err := s.withFileLock(syscall.LOCK_SH, func() (err error) {
+		file, err = s.load()
+		return err
+	})
 	if err != nil {
 		return session.Session{}, err
 	}
@@ -60,30 +87,39 @@ func (s *Store) Get(ctx context.Context, id string) (session.Session, error) {
 	return session.Session{}, session.ErrNotFound
 }
 
-// Save creates or updates a session.
+// Save creates or updates a session. Returns ErrConflict if sess carries a
+// version other than the stored one, or a version at all once the session
+// was deleted, in which case the error also matches ErrNotFound.
 func (s *Store) Save(ctx context.Context, sess session.Session) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	file, err := s.load()
-	if err != nil {
-		return err
-	}
+	return s.withFileLock(syscall.LOCK_EX, func() error {
+		file, err := s.load()
+		if err != nil {
+			return err
+		}
 
-	// Update existing or append new
-	found := false
-	for i, existing := range file.Sessions {
-		if existing.ID == sess.ID {
+		// Update existing or append new
+		for i, existing := range file.Sessions {
+			if existing.ID != sess.ID {
+				continue
+			}
+			if sess.Version != 0 && sess.Version != existing.Version {
+				return fmt.Errorf("save session %s: %w", sess.ID, session.ErrConflict)
+			}
+			sess.Version = existing.Version + 1
 			file.Sessions[i] = sess
-			found = true
-			break
+			return s.save(file)
 		}
-	}
-	if !found {
-		file.Sessions = append(file.Sessions, sess)
-	}
 
-	return s.save(file)
+		if sess.Version != 0 {
+			return fmt.Errorf("save session %s: %w: %w", sess.ID, session.ErrConflict, session.ErrNotFound)
+		}
+		sess.Version = 1
+		file.Sessions = append(file.Sessions, sess)
+		return s.save(file)
+	})
 }
 
 // Delete removes a session by ID. Returns ErrNotFound if not found.
@@ -91,19 +127,21 @@ func (s *Store) Delete(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	file, err := s.load()
-	if err != nil {
-		return err
-	}
+	return s.withFileLock(syscall.LOCK_EX, func() error {
+		file, err := s.load()
+		if err != nil {
+			return err
+		}
 
-	for i, sess := range file.Sessions {
-		if sess.ID == id {
-			file.Sessions = append(file.Sessions[:i], file.Sessions[i+1:]...)
-			return s.save(file)
+		for i, sess := range file.Sessions {
+			if sess.ID == id {
+				file.Sessions = append(file.Sessions[:i], file.Sessions[i+1:]...)
+				return s.save(file)
+			}
 		}
-	}
 
-	return session.ErrNotFound
+		return session.ErrNotFound
+	})
 }
 
 // FindRecyclable returns a recyclable session for the given remote.
@@ -112,7 +150,11 @@ func (s *Store) FindRecyclable(ctx context.Context, remote string) (session.Sess
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	file, err := s.load()
+	var file SessionFile
+	err := s.withFileLock(syscall.LOCK_SH, func() (err error) {
+		file, err = s.load()
+		return err
+	})
 	if err != nil {
 		return session.Session{}, err
 	}
@@ -126,6 +168,27 @@ func (s *Store) FindRecyclable(ctx context.Context, remote string) (session.Sess
 	return session.Session{}, session.ErrNoRecyclable
 }
 
+// withFileLock runs fn while holding a flock of lockType on the lock file
+// next to the sessions file.
+func (s *Store) withFileLock(lockType int, fn func() error) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create sessions directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return fmt.Errorf("open lock file: %w", err)
+	}
+	defer f.Close() //nolint:errcheck
+
+	if err := syscall.Flock(int(f.Fd()), lockType); err != nil {
+		return fmt.Errorf("acquire file lock: %w", err)
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	return fn()
+}
+
 // load reads the session file from disk.
 // Returns empty SessionFile if file doesn't exist.
 func (s *Store) load() (SessionFile, error) {
@@ -143,7 +206,7 @@ func (s *Store) load() (SessionFile, error) {
 
 	var file SessionFile
 	if err := json.Unmarshal(data, &file); err != nil {
-		return SessionFile{}, fmt.Errorf("parse sessions file: %w", err)
+		return SessionFile{}, fmt.Errorf("parse sessions file %s: %w: %w", s.path, ErrCorrupt, err)
 	}
 
 	return file, nil
@@ -151,11 +214,8 @@ func (s *Store) load() (SessionFile, error) {
 
 // save writes the session file to disk atomically.
 // Uses write-to-temp-then-rename to prevent corruption from interrupted writes.
+// Caller must hold the exclusive file lock.
 func (s *Store) save(file SessionFile) error {
-	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
-		return fmt.Errorf("create sessions directory: %w", err)
-	}
-
 	data, err := json.MarshalIndent(file, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshal sessions: %w", err)
@@ -167,6 +227,7 @@ func (s *Store) save(file SessionFile) error {
 	}
 
 	if err := os.Rename(tmp, s.path); err != nil {
+		_ = os.Remove(tmp)
 		return fmt.Errorf("rename temp file: %w", err)
 	}
 	return nil
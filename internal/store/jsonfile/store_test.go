@@ -3,7 +3,10 @@ package jsonfile
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -102,6 +105,62 @@ func TestStore(t *testing.T) {
 		}
 	})
 
+	t.Run("save stale version conflicts", func(t *testing.T) {
+		store := New(filepath.Join(t.TempDir(), "sessions.json"))
+
+		if err := store.Save(ctx, session.Session{ID: "versioned", Name: "original"}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		first, err := store.Get(ctx, "versioned")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		stale := first
+
+		first.Name = "first"
+		if err := store.Save(ctx, first); err != nil {
+			t.Fatalf("Save first: %v", err)
+		}
+
+		stale.Name = "stale"
+		if err := store.Save(ctx, stale); !errors.Is(err, session.ErrConflict) {
+			t.Fatalf("Save stale: got %v, want ErrConflict", err)
+		}
+
+		got, err := store.Get(ctx, "versioned")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Name != "first" || got.Version != 2 {
+			t.Errorf("got name %q version %d, want %q version 2", got.Name, got.Version, "first")
+		}
+	})
+
+	t.Run("save deleted session conflicts", func(t *testing.T) {
+		store := New(filepath.Join(t.TempDir(), "sessions.json"))
+
+		if err := store.Save(ctx, session.Session{ID: "gone", Name: "original"}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		stale, err := store.Get(ctx, "gone")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if err := store.Delete(ctx, "gone"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		err = store.Save(ctx, stale)
+		if !errors.Is(err, session.ErrConflict) || !errors.Is(err, session.ErrNotFound) {
+			t.Fatalf("Save deleted: got %v, want ErrConflict and ErrNotFound", err)
+		}
+		if _, err := store.Get(ctx, "gone"); !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("Get: got %v, want ErrNotFound", err)
+		}
+	})
+
 	t.Run("delete", func(t *testing.T) {
 		store := New(filepath.Join(t.TempDir(), "sessions.json"))
 
@@ -183,4 +242,78 @@ func TestStore(t *testing.T) {
 			t.Errorf("got ID %q, want %q", got.ID, "recycled")
 		}
 	})
+	t.Run("find", func(t *testing.T) {
+		store := New(filepath.Join(t.TempDir(), "sessions.json"))
+		remote := "https://github.com/test/repo"
+
+		for _, sess := range []session.Session{
+			{ID: "a1", Remote: remote, State: session.StateActive},
+			{ID: "r1", Remote: remote, State: session.StateRecycled},
+			{ID: "o1", Remote: "https://github.com/other/repo", State: session.StateActive},
+			{ID: "a2", Remote: remote, State: session.StateActive},
+			{ID: "c1", Remote: remote, State: session.StateCorrupted},
+		} {
+			if err := store.Save(ctx, sess); err != nil {
+				t.Fatalf("Save %s: %v", sess.ID, err)
+			}
+		}
+
+		tests := []struct {
+			name   string
+			filter session.Filter
+			want   []string
+		}{
+			{"all", session.Filter{}, []string{"a1", "r1", "o1", "a2", "c1"}},
+			{"remote", session.Filter{Remote: remote}, []string{"a1", "r1", "a2", "c1"}},
+			{"states", session.Filter{States: []session.State{session.StateActive, session.StateCorrupted}}, []string{"a1", "o1", "a2", "c1"}},
+			{"remote and state", session.Filter{Remote: remote, States: []session.State{session.StateActive}}, []string{"a1", "a2"}},
+			{"limit", session.Filter{Limit: 2}, []string{"a1", "r1"}},
+			{"offset", session.Filter{Offset: 3}, []string{"a2", "c1"}},
+			{"page", session.Filter{States: []session.State{session.StateActive}, Limit: 1, Offset: 1}, []string{"o1"}},
+		}
+		for _, tt := range tests {
+			got, err := store.Find(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("%s: Find: %v", tt.name, err)
+			}
+			ids := make([]string, len(got))
+			for i, s := range got {
+				ids[i] = s.ID
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+			}
+		}
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sessions.json")
+
+		// Separate stores share no mutex, like separate hive processes.
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs <- New(path).Save(ctx, session.Session{ID: fmt.Sprintf("s%d", i)})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+
+		sessions, err := New(path).List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(sessions) != 20 {
+			t.Errorf("got %d sessions, want 20", len(sessions))
+		}
+	})
 }
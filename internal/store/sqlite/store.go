@@ -0,0 +1,277 @@
+// Package sqlite provides a SQLite-backed session store.
+//
+// The database runs in WAL mode with a busy timeout, so many hive processes
+// can read and write sessions concurrently without clobbering each other's
+// changes. Each session is stored as a JSON document alongside the columns
+// used for lookups.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hay-kot/hive/internal/core/session"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// busyTimeoutMS is how long a connection waits on a locked database before
+// returning SQLITE_BUSY.
+const busyTimeoutMS = 5000
+
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id     TEXT PRIMARY KEY,
+	remote TEXT NOT NULL DEFAULT '',
+	state  TEXT NOT NULL DEFAULT '',
+	data   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS sessions_remote_state ON sessions (remote, state);
+`
+
+// Store implements session.Store using a SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+// New opens (creating if needed) the SQLite database at path.
+func New(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	q := url.Values{}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeoutMS))
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Add("_pragma", "synchronous(NORMAL)")
+	q.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the database handle.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// List returns all sessions in the order they were first saved.
+func (s *Store) List(ctx context.Context) ([]session.Session, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM sessions ORDER BY rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []session.Session
+	for rows.Next() {
+		sess, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Find returns the sessions matching f, filtering and paginating in the
+// database so only the selected rows are decoded.
+func (s *Store) Find(ctx context.Context, f session.Filter) ([]session.Session, error) {
+	query := `SELECT data FROM sessions`
+	var (
+		where []string
+		args  []any
+	)
+	if f.Remote != "" {
+		where = append(where, "remote = ?")
+		args = append(args, f.Remote)
+	}
+	if len(f.States) > 0 {
+		where = append(where, "state IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(f.States)), ", ")+")")
+		for _, state := range f.States {
+			args = append(args, string(state))
+		}
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY rowid"
+
+	if f.Limit > 0 || f.Offset > 0 {
+		limit := f.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite: no limit
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, max(f.Offset, 0))
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []session.Session
+	for rows.Next() {
+		sess, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// Get returns a session by ID. Returns ErrNotFound if not found.
+func (s *Store) Get(ctx context.Context, id string) (session.Session, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT data FROM sessions WHERE id = ?`, id)
+	sess, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return session.Session{}, session.ErrNotFound
+	}
+	return sess, err
+}
+
+// Save creates or updates a session. Returns ErrConflict if sess carries a
+// version other than the stored one, or a version at all once the session
+// was deleted, in which case the error also matches ErrNotFound.
+func (s *Store) Save(ctx context.Context, sess session.Session) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var stored int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT COALESCE(json_extract(data, '$.version'), 0) FROM sessions WHERE id = ?`, sess.ID,
+	).Scan(&stored)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("read session version: %w", err)
+	}
+	if errors.Is(err, sql.ErrNoRows) && sess.Version != 0 {
+		return fmt.Errorf("save session %s: %w: %w", sess.ID, session.ErrConflict, session.ErrNotFound)
+	}
+	if err == nil && sess.Version != 0 && sess.Version != stored {
+		return fmt.Errorf("save session %s: %w", sess.ID, session.ErrConflict)
+	}
+	sess.Version = stored + 1
+
+	data, err := json.Marshal(sess)
+	if err != nil {
+		return fmt.Errorf("marshal session: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO sessions (id, remote, state, data) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			remote = excluded.remote,
+			state  = excluded.state,
+			data   = excluded.data`,
+		sess.ID, sess.Remote, string(sess.State), string(data),
+	)
+	if err != nil {
+		return fmt.Errorf("save session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save session: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a session by ID. Returns ErrNotFound if not found.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	if n == 0 {
+		return session.ErrNotFound
+	}
+
+	return nil
+}
+
+// FindRecyclable returns a recyclable session for the given remote.
+// Returns ErrNoRecyclable if none available.
+func (s *Store) FindRecyclable(ctx context.Context, remote string) (session.Session, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT data FROM sessions WHERE remote = ? AND state = ? ORDER BY rowid LIMIT 1`,
+		remote, string(session.StateRecycled),
+	)
+	sess, err := scan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return session.Session{}, session.ErrNoRecyclable
+	}
+	return sess, err
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scan(row scanner) (session.Session, error) {
+	var data string
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session.Session{}, err
+		}
+		return session.Session{}, fmt.Errorf("read session: %w", err)
+	}
+
+	var sess session.Session
+	if err := json.Unmarshal([]byte(data), &sess); err != nil {
+		return session.Session{}, fmt.Errorf("parse session: %w", err)
+	}
+
+	return sess, nil
+}
+
+// Snapshot writes a consistent copy of the database at path to dst, which
+// must not exist. It is safe to call while other processes use the database.
+func Snapshot(ctx context.Context, path, dst string) error {
+	store, err := New(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = store.Close() }()
+
+	if _, err := store.db.ExecContext(ctx, `VACUUM INTO ?`, dst); err != nil {
+		return fmt.Errorf("snapshot database: %w", err)
+	}
+
+	return nil
+}
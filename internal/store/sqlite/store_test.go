@@ -0,0 +1,371 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("save and get", func(t *testing.T) {
+		store := newTestStore(t)
+
+		sess := session.Session{
+			ID:        "test-id",
+			Name:      "test-session",
+			Path:      "/tmp/test",
+			Remote:    "https://github.com/test/repo",
+			State:     session.StateActive,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		if err := store.Save(ctx, sess); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Get(ctx, "test-id")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+
+		if got.ID != sess.ID || got.Name != sess.Name {
+			t.Errorf("got %+v, want %+v", got, sess)
+		}
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		store := newTestStore(t)
+
+		_, err := store.Get(ctx, "nonexistent")
+		if !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		store := newTestStore(t)
+
+		sessions, err := store.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(sessions) != 0 {
+			t.Errorf("got %d sessions, want 0", len(sessions))
+		}
+
+		for _, name := range []string{"first", "second"} {
+			if err := store.Save(ctx, session.Session{ID: name, Name: name}); err != nil {
+				t.Fatalf("Save %s: %v", name, err)
+			}
+		}
+
+		sessions, err = store.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(sessions) != 2 {
+			t.Fatalf("got %d sessions, want 2", len(sessions))
+		}
+		if sessions[0].ID != "first" || sessions[1].ID != "second" {
+			t.Errorf("got order %q, %q, want first, second", sessions[0].ID, sessions[1].ID)
+		}
+	})
+
+	t.Run("save updates existing", func(t *testing.T) {
+		store := newTestStore(t)
+
+		sess := session.Session{ID: "update-test", Name: "original"}
+		if err := store.Save(ctx, sess); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		sess.Name = "updated"
+		if err := store.Save(ctx, sess); err != nil {
+			t.Fatalf("Save update: %v", err)
+		}
+
+		got, err := store.Get(ctx, "update-test")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Name != "updated" {
+			t.Errorf("got name %q, want %q", got.Name, "updated")
+		}
+
+		sessions, _ := store.List(ctx)
+		if len(sessions) != 1 {
+			t.Errorf("got %d sessions, want 1", len(sessions))
+		}
+	})
+
+	t.Run("save stale version conflicts", func(t *testing.T) {
+		store := newTestStore(t)
+
+		if err := store.Save(ctx, session.Session{ID: "versioned", Name: "original"}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		first, err := store.Get(ctx, "versioned")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		stale := first
+
+		first.Name = "first"
+		if err := store.Save(ctx, first); err != nil {
+			t.Fatalf("Save first: %v", err)
+		}
+
+		stale.Name = "stale"
+		if err := store.Save(ctx, stale); !errors.Is(err, session.ErrConflict) {
+			t.Fatalf("Save stale: got %v, want ErrConflict", err)
+		}
+
+		got, err := store.Get(ctx, "versioned")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Name != "first" || got.Version != 2 {
+			t.Errorf("got name %q version %d, want %q version 2", got.Name, got.Version, "first")
+		}
+	})
+
+	t.Run("save deleted session conflicts", func(t *testing.T) {
+		store := newTestStore(t)
+
+		if err := store.Save(ctx, session.Session{ID: "gone", Name: "original"}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		stale, err := store.Get(ctx, "gone")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if err := store.Delete(ctx, "gone"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		err = store.Save(ctx, stale)
+		if !errors.Is(err, session.ErrConflict) || !errors.Is(err, session.ErrNotFound) {
+			t.Fatalf("Save deleted: got %v, want ErrConflict and ErrNotFound", err)
+		}
+		if _, err := store.Get(ctx, "gone"); !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("Get: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newTestStore(t)
+
+		if err := store.Save(ctx, session.Session{ID: "delete-me"}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		if err := store.Delete(ctx, "delete-me"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+
+		_, err := store.Get(ctx, "delete-me")
+		if !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("delete not found", func(t *testing.T) {
+		store := newTestStore(t)
+
+		err := store.Delete(ctx, "nonexistent")
+		if !errors.Is(err, session.ErrNotFound) {
+			t.Errorf("got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("find recyclable", func(t *testing.T) {
+		store := newTestStore(t)
+		remote := "https://github.com/test/repo"
+
+		// No recyclable sessions
+		_, err := store.FindRecyclable(ctx, remote)
+		if !errors.Is(err, session.ErrNoRecyclable) {
+			t.Errorf("empty store: got %v, want ErrNoRecyclable", err)
+		}
+
+		// Active session with matching remote - not recyclable
+		if err := store.Save(ctx, session.Session{
+			ID:     "active",
+			Remote: remote,
+			State:  session.StateActive,
+		}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		_, err = store.FindRecyclable(ctx, remote)
+		if !errors.Is(err, session.ErrNoRecyclable) {
+			t.Errorf("active session: got %v, want ErrNoRecyclable", err)
+		}
+
+		// Recycled session with different remote - not found
+		if err := store.Save(ctx, session.Session{
+			ID:     "different",
+			Remote: "https://github.com/other/repo",
+			State:  session.StateRecycled,
+		}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		_, err = store.FindRecyclable(ctx, remote)
+		if !errors.Is(err, session.ErrNoRecyclable) {
+			t.Errorf("different remote: got %v, want ErrNoRecyclable", err)
+		}
+
+		// Recycled session with matching remote - found
+		if err := store.Save(ctx, session.Session{
+			ID:     "recycled",
+			Remote: remote,
+			State:  session.StateRecycled,
+		}); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.FindRecyclable(ctx, remote)
+		if err != nil {
+			t.Fatalf("FindRecyclable: %v", err)
+		}
+		if got.ID != "recycled" {
+			t.Errorf("got ID %q, want %q", got.ID, "recycled")
+		}
+	})
+	t.Run("find", func(t *testing.T) {
+		store := newTestStore(t)
+		remote := "https://github.com/test/repo"
+
+		for _, sess := range []session.Session{
+			{ID: "a1", Remote: remote, State: session.StateActive},
+			{ID: "r1", Remote: remote, State: session.StateRecycled},
+			{ID: "o1", Remote: "https://github.com/other/repo", State: session.StateActive},
+			{ID: "a2", Remote: remote, State: session.StateActive},
+			{ID: "c1", Remote: remote, State: session.StateCorrupted},
+		} {
+			if err := store.Save(ctx, sess); err != nil {
+				t.Fatalf("Save %s: %v", sess.ID, err)
+			}
+		}
+
+		tests := []struct {
+			name   string
+			filter session.Filter
+			want   []string
+		}{
+			{"all", session.Filter{}, []string{"a1", "r1", "o1", "a2", "c1"}},
+			{"remote", session.Filter{Remote: remote}, []string{"a1", "r1", "a2", "c1"}},
+			{"states", session.Filter{States: []session.State{session.StateActive, session.StateCorrupted}}, []string{"a1", "o1", "a2", "c1"}},
+			{"remote and state", session.Filter{Remote: remote, States: []session.State{session.StateActive}}, []string{"a1", "a2"}},
+			{"limit", session.Filter{Limit: 2}, []string{"a1", "r1"}},
+			{"offset", session.Filter{Offset: 3}, []string{"a2", "c1"}},
+			{"page", session.Filter{States: []session.State{session.StateActive}, Limit: 1, Offset: 1}, []string{"o1"}},
+		}
+		for _, tt := range tests {
+			got, err := store.Find(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("%s: Find: %v", tt.name, err)
+			}
+			ids := make([]string, len(got))
+			for i, s := range got {
+				ids[i] = s.ID
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+			}
+		}
+	})
+
+	t.Run("save keeps metadata", func(t *testing.T) {
+		store := newTestStore(t)
+
+		read := time.Now().UTC().Truncate(time.Second)
+		sess := session.Session{
+			ID:            "meta",
+			Metadata:      map[string]string{"tmux": "hive-meta"},
+			LastInboxRead: &read,
+		}
+		if err := store.Save(ctx, sess); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+
+		got, err := store.Get(ctx, "meta")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if got.Metadata["tmux"] != "hive-meta" {
+			t.Errorf("got metadata %v, want tmux=hive-meta", got.Metadata)
+		}
+		if got.LastInboxRead == nil || !got.LastInboxRead.Equal(read) {
+			t.Errorf("got last inbox read %v, want %v", got.LastInboxRead, read)
+		}
+	})
+
+	t.Run("concurrent writers", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "sessions.db")
+
+		// Separate handles stand in for separate hive processes.
+		var wg sync.WaitGroup
+		errs := make(chan error, 20)
+		for i := range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				store, err := New(path)
+				if err != nil {
+					errs <- err
+					return
+				}
+				defer func() { _ = store.Close() }()
+				errs <- store.Save(ctx, session.Session{ID: fmt.Sprintf("s%d", i)})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+
+		store, err := New(path)
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		defer func() { _ = store.Close() }()
+
+		sessions, err := store.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(sessions) != 20 {
+			t.Errorf("got %d sessions, want 20", len(sessions))
+		}
+	})
+}
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := New(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
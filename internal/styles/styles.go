@@ -4,6 +4,7 @@ package styles
 import (
 	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // Tokyo Night color palette.
@@ -15,6 +16,11 @@ var (
 	ColorWhite  = lipgloss.Color("#c0caf5")
 )
 
+// DisableColor renders all lipgloss styles as plain text.
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
 // Banner ASCII art for the header.
 const Banner = `
  ╦ ╦╦╦  ╦╔═╗
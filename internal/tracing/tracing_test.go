@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEnabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	assert.False(t, Enabled(config.TracingConfig{}))
+	assert.True(t, Enabled(config.TracingConfig{Endpoint: "localhost:4318"}))
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+	assert.True(t, Enabled(config.TracingConfig{}))
+}
+
+func TestSetupDisabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	prev := otel.GetTracerProvider()
+	shutdown, err := Setup(context.Background(), config.TracingConfig{}, "dev")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, prev, otel.GetTracerProvider())
+}
+
+func TestEndRecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	_, span := Start(context.Background(), "failing")
+	End(span, errors.New("boom"))
+
+	require.Len(t, recorder.Ended(), 1)
+	got := recorder.Ended()[0]
+	assert.Equal(t, codes.Error, got.Status().Code)
+	assert.Equal(t, "boom", got.Status().Description)
+}
@@ -0,0 +1,223 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Action queue limits.
+const (
+	actionQueueWorkers   = 3                // actions run at once
+	actionQueueMaxLines  = 5                // jobs listed before "+N more"
+	actionDoneLinger     = 3 * time.Second  // how long finished jobs stay listed
+	actionFailedLinger   = 10 * time.Second // how long failed jobs stay listed
+	actionQueueTextWidth = 60               // columns of a job's output or error shown
+)
+
+// jobStatus is the progress of a queued action.
+type jobStatus int
+
+const (
+	jobQueued jobStatus = iota
+	jobRunning
+	jobDone
+	jobFailed
+)
+
+// queuedAction is an action waiting in or run by the action queue.
+type queuedAction struct {
+	id          int
+	action      Action
+	sessionName string
+	status      jobStatus
+	output      string // last line of output while running
+	err         error
+}
+
+// pending reports whether the job is queued or running.
+func (j queuedAction) pending() bool {
+	return j.status == jobQueued || j.status == jobRunning
+}
+
+// actionQueue runs session actions in the background so the list stays
+// usable. A session has at most one pending action, so actions on the same
+// session never run concurrently. Silent actions are queued like any other
+// but left out of the progress list.
+type actionQueue struct {
+	jobs    []queuedAction
+	nextID  int
+	workers int
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+// newActionQueue creates a queue running at most workers actions at once.
+func newActionQueue(workers int) *actionQueue {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &actionQueue{workers: max(workers, 1), ctx: ctx, cancel: cancel}
+}
+
+// Context returns the context actions run with. It is cancelled by Cancel.
+func (q *actionQueue) Context() context.Context {
+	return q.ctx
+}
+
+// Cancel cancels the running actions, e.g. when the TUI quits.
+func (q *actionQueue) Cancel() {
+	q.cancel()
+}
+
+// Add queues an action on the named session. It reports false, queuing
+// nothing, when the session already has an action queued or running.
+func (q *actionQueue) Add(action Action, sessionName string) bool {
+	if q.Busy(action.SessionID) {
+		return false
+	}
+	q.nextID++
+	q.jobs = append(q.jobs, queuedAction{id: q.nextID, action: action, sessionName: sessionName})
+	return true
+}
+
+// Busy reports whether the session has an action queued or running.
+func (q *actionQueue) Busy(sessionID string) bool {
+	for _, j := range q.jobs {
+		if j.action.SessionID == sessionID && j.pending() {
+			return true
+		}
+	}
+	return false
+}
+
+// Start marks queued jobs as running, oldest first, until the worker limit is
+// reached, and returns them.
+func (q *actionQueue) Start() []queuedAction {
+	running := 0
+	for _, j := range q.jobs {
+		if j.status == jobRunning {
+			running++
+		}
+	}
+
+	var started []queuedAction
+	for i := range q.jobs {
+		if running >= q.workers {
+			break
+		}
+		if q.jobs[i].status != jobQueued {
+			continue
+		}
+		q.jobs[i].status = jobRunning
+		started = append(started, q.jobs[i])
+		running++
+	}
+	return started
+}
+
+// SetOutput records the latest output line of a running job. Blank lines
+// are ignored.
+func (q *actionQueue) SetOutput(id int, output string) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	line := strings.TrimSpace(lines[len(lines)-1])
+	if line == "" {
+		return
+	}
+	for i := range q.jobs {
+		if q.jobs[i].id == id {
+			q.jobs[i].output = line
+			return
+		}
+	}
+}
+
+// Finish records the outcome of a running job and returns it.
+func (q *actionQueue) Finish(id int, err error) (queuedAction, bool) {
+	for i := range q.jobs {
+		if q.jobs[i].id != id {
+			continue
+		}
+		q.jobs[i].status = jobDone
+		if err != nil {
+			q.jobs[i].status = jobFailed
+			q.jobs[i].err = err
+		}
+		return q.jobs[i], true
+	}
+	return queuedAction{}, false
+}
+
+// Remove drops a finished job from the progress list.
+func (q *actionQueue) Remove(id int) {
+	for i, j := range q.jobs {
+		if j.id == id && !j.pending() {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+			return
+		}
+	}
+}
+
+// listed returns the jobs shown in the progress list.
+func (q *actionQueue) listed() []queuedAction {
+	var jobs []queuedAction
+	for _, j := range q.jobs {
+		if !j.action.Silent {
+			jobs = append(jobs, j)
+		}
+	}
+	return jobs
+}
+
+// Height returns the number of lines View renders.
+func (q *actionQueue) Height() int {
+	n := len(q.listed())
+	if n > actionQueueMaxLines {
+		return actionQueueMaxLines + 1
+	}
+	return n
+}
+
+// View renders the progress list, one job per line, with spin in front of
+// running jobs.
+func (q *actionQueue) View(spin string) string {
+	jobs := q.listed()
+	if len(jobs) == 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, actionQueueMaxLines+1)
+	for _, j := range jobs[:min(len(jobs), actionQueueMaxLines)] {
+		lines = append(lines, j.line(spin))
+	}
+	if extra := len(jobs) - actionQueueMaxLines; extra > 0 {
+		lines = append(lines, actionQueueStyle.Render(fmt.Sprintf("   +%d more", extra)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// line renders one job of the progress list.
+func (j queuedAction) line(spin string) string {
+	label := j.action.Help + " " + j.sessionName
+	switch j.status {
+	case jobRunning:
+		if j.output != "" {
+			label += actionQueueStyle.Render(activitySeparator + truncateWidth(j.output, actionQueueTextWidth))
+		}
+		return " " + spin + " " + label
+	case jobDone:
+		return actionQueueStyle.Render("   " + label + " - done")
+	case jobFailed:
+		msg := truncateWidth(j.err.Error(), actionQueueTextWidth)
+		return actionFailedStyle.Render("   " + label + " - failed: " + msg)
+	default:
+		return actionQueueStyle.Render("   " + label + " - queued")
+	}
+}
+
+// Action queue styles.
+var (
+	actionQueueStyle  = lipgloss.NewStyle().Foreground(colorGray)
+	actionFailedStyle = lipgloss.NewStyle().Foreground(colorRed)
+)
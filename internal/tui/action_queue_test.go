@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionQueue_OnePendingActionPerSession(t *testing.T) {
+	q := newActionQueue(2)
+	recycle := Action{Type: ActionTypeRecycle, Help: "recycle", SessionID: "a"}
+
+	require.True(t, q.Add(recycle, "alpha"))
+	assert.False(t, q.Add(Action{Type: ActionTypeDelete, Help: "delete", SessionID: "a"}, "alpha"))
+	assert.True(t, q.Busy("a"))
+
+	started := q.Start()
+	require.Len(t, started, 1)
+	assert.False(t, q.Add(recycle, "alpha"), "running session should stay busy")
+
+	_, ok := q.Finish(started[0].id, nil)
+	require.True(t, ok)
+	assert.False(t, q.Busy("a"))
+	assert.True(t, q.Add(recycle, "alpha"))
+}
+
+func TestActionQueue_BoundedConcurrency(t *testing.T) {
+	q := newActionQueue(2)
+	for _, id := range []string{"a", "b", "c"} {
+		require.True(t, q.Add(Action{Help: "recycle", SessionID: id}, id))
+	}
+
+	started := q.Start()
+	require.Len(t, started, 2)
+	assert.Equal(t, "a", started[0].action.SessionID)
+	assert.Equal(t, "b", started[1].action.SessionID)
+	assert.Empty(t, q.Start(), "no worker is free")
+
+	job, _ := q.Finish(started[0].id, errors.New("boom"))
+	assert.Equal(t, jobFailed, job.status)
+
+	started = q.Start()
+	require.Len(t, started, 1)
+	assert.Equal(t, "c", started[0].action.SessionID)
+}
+
+func TestActionQueue_View(t *testing.T) {
+	q := newActionQueue(1)
+	q.Add(Action{Help: "recycle", SessionID: "a"}, "alpha")
+	q.Add(Action{Help: "delete", SessionID: "b"}, "beta")
+	q.Add(Action{Help: "pin", SessionID: "c", Silent: true}, "gamma")
+
+	started := q.Start()
+	q.SetOutput(started[0].id, "fetching\ngit reset --hard\n\n")
+
+	assert.Equal(t, 2, q.Height(), "silent actions are not listed")
+	assert.Equal(t,
+		" * recycle alpha · git reset --hard\n   delete beta - queued",
+		q.View("*"))
+
+	q.Finish(started[0].id, nil)
+	q.Remove(started[0].id)
+	q.Remove(2) // still queued, kept
+	assert.Equal(t, "   delete beta - queued", q.View("*"))
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/styles"
+)
+
+// SessionBatch summarizes the sessions created by one hive batch.
+type SessionBatch struct {
+	ID       string
+	Sessions int
+	Created  time.Time // creation time of the batch's newest session
+}
+
+// SessionBatches returns the batches the sessions were created by, newest
+// first. Sessions not created by a batch are skipped.
+func SessionBatches(sessions []session.Session) []SessionBatch {
+	var batches []SessionBatch
+	for _, sess := range sessions {
+		id := sess.BatchID()
+		if id == "" {
+			continue
+		}
+		idx := slices.IndexFunc(batches, func(b SessionBatch) bool { return b.ID == id })
+		if idx < 0 {
+			batches = append(batches, SessionBatch{ID: id})
+			idx = len(batches) - 1
+		}
+		batches[idx].Sessions++
+		if sess.CreatedAt.After(batches[idx].Created) {
+			batches[idx].Created = sess.CreatedAt
+		}
+	}
+
+	slices.SortFunc(batches, func(a, b SessionBatch) int {
+		return cmp.Or(b.Created.Compare(a.Created), cmp.Compare(a.ID, b.ID))
+	})
+	return batches
+}
+
+// BatchFilterForm wraps a huh.Form for choosing the batch the session list
+// is filtered to.
+type BatchFilterForm struct {
+	form    *huh.Form
+	batchID string
+}
+
+// NewBatchFilterForm creates a form listing the batches, with current
+// preselected. Choosing "All sessions" clears the filter.
+func NewBatchFilterForm(batches []SessionBatch, current string) *BatchFilterForm {
+	f := &BatchFilterForm{batchID: current}
+
+	options := make([]huh.Option[string], 0, len(batches)+1)
+	options = append(options, huh.NewOption("All sessions", ""))
+	for _, b := range batches {
+		options = append(options, huh.NewOption(fmt.Sprintf("%s (%s)", b.ID, countLabel(b.Sessions, "session")), b.ID))
+	}
+
+	f.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Batch").
+				Options(options...).
+				Value(&f.batchID),
+		),
+	).WithTheme(styles.FormTheme())
+
+	return f
+}
+
+// Form returns the underlying huh.Form for tea.Model integration.
+func (f *BatchFilterForm) Form() *huh.Form {
+	return f.form
+}
+
+// BatchID returns the chosen batch, or empty string for all sessions.
+func (f *BatchFilterForm) BatchID() string {
+	return f.batchID
+}
+
+// View renders the form.
+func (f *BatchFilterForm) View() string {
+	return f.form.View()
+}
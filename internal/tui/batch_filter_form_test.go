@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionBatches(t *testing.T) {
+	now := time.Now()
+	batch := func(id string) map[string]string { return map[string]string{session.MetaBatchID: id} }
+	sessions := []session.Session{
+		{ID: "a", CreatedAt: now.Add(-2 * time.Hour), Metadata: batch("old")},
+		{ID: "b", CreatedAt: now.Add(-time.Hour), Metadata: batch("new")},
+		{ID: "c", CreatedAt: now},
+		{ID: "d", CreatedAt: now.Add(-time.Hour), Metadata: batch("new")},
+	}
+
+	batches := SessionBatches(sessions)
+	require.Len(t, batches, 2)
+	assert.Equal(t, "new", batches[0].ID)
+	assert.Equal(t, 2, batches[0].Sessions)
+	assert.Equal(t, "old", batches[1].ID)
+	assert.Equal(t, 1, batches[1].Sessions)
+}
+
+func TestNewBatchFilterForm(t *testing.T) {
+	form := NewBatchFilterForm([]SessionBatch{{ID: "k3x9qa", Sessions: 3}}, "k3x9qa")
+	require.NotNil(t, form.Form())
+	assert.Equal(t, "k3x9qa", form.BatchID())
+}
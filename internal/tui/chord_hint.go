@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// chordHintStyle frames the keys that may follow a leader key.
+var (
+	chordHintStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(colorBlue).
+			Padding(0, 1)
+	chordHintTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(colorBlue)
+	chordHintHelpStyle  = lipgloss.NewStyle().Foreground(colorGray)
+)
+
+// renderChordHint renders the which-key popup shown after the leader keys:
+// each key that may follow with its help, wrapped to width.
+func renderChordHint(keys string, entries []string, width int) string {
+	title := chordHintTitleStyle.Render(keys+" …") + "  " + chordHintHelpStyle.Render("esc to cancel")
+	// Border and padding take four columns
+	body := lipgloss.NewStyle().Width(max(width-4, 20)).Render(strings.Join(entries, "   "))
+	return chordHintStyle.Render(title + "\n" + body)
+}
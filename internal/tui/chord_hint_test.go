@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeybindingHandler_Chords(t *testing.T) {
+	handler := NewKeybindingHandler(map[string]config.Keybinding{
+		"r":     {Action: config.ActionRecycle},
+		"g p":   {Sh: "git push", Help: "git push"},
+		"g f":   {Sh: "git fetch"},
+		"g b c": {Sh: "git checkout -b"},
+	}, nil)
+
+	assert.True(t, handler.IsLeader("g"))
+	assert.True(t, handler.IsLeader("g b"))
+	assert.False(t, handler.IsLeader("g p"))
+	assert.False(t, handler.IsLeader("r"))
+
+	assert.Equal(t, []string{"[b] +more", "[f] shell", "[p] git push"}, handler.ChordEntries("g"))
+	assert.Equal(t, []string{"[c] shell"}, handler.ChordEntries("g b"))
+}
+
+func TestRenderChordHint(t *testing.T) {
+	view := renderChordHint("g", []string{"[p] git push", "[f] shell"}, 80)
+	assert.Contains(t, view, "g …")
+	assert.Contains(t, view, "[p] git push")
+	assert.Contains(t, view, "esc to cancel")
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"os"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/rs/zerolog/log"
+)
+
+const configWatchInterval = 2 * time.Second
+
+// configWatchTickMsg is sent to trigger the next config file check.
+type configWatchTickMsg struct{}
+
+// configReloadedMsg is sent when the config file changed on disk.
+// cfg is nil if the new config failed to load or validate.
+type configReloadedMsg struct {
+	cfg     *config.Config
+	modTime time.Time
+	err     error
+}
+
+// scheduleConfigWatch returns a command that schedules the next config check.
+func scheduleConfigWatch() tea.Cmd {
+	return tea.Tick(configWatchInterval, func(time.Time) tea.Msg {
+		return configWatchTickMsg{}
+	})
+}
+
+// configModTime returns the modification time of path, or the zero time
+// if it cannot be read.
+func configModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// checkConfig returns a command that reloads the config if its modification
+// time differs from lastMod. Returns nil when the file is unchanged or missing,
+// so a file that is briefly removed by an editor does not reset to defaults.
+func checkConfig(path, dataDir string, lastMod time.Time) tea.Cmd {
+	return func() tea.Msg {
+		modTime := configModTime(path)
+		if modTime.IsZero() || modTime.Equal(lastMod) {
+			return nil
+		}
+
+		cfg, err := config.Load(path, dataDir)
+		if err == nil {
+			err = cfg.ValidateDeep(path)
+		}
+		if err != nil {
+			return configReloadedMsg{modTime: modTime, err: err}
+		}
+		return configReloadedMsg{cfg: cfg, modTime: modTime}
+	}
+}
+
+// handleConfigReloaded applies a reloaded config, keeping the current one on error.
+func (m Model) handleConfigReloaded(msg configReloadedMsg) (tea.Model, tea.Cmd) {
+	m.configModTime = msg.modTime
+
+	if msg.err != nil {
+		log.Warn().Err(msg.err).Str("path", m.configPath).Msg("config reload failed, keeping previous config")
+		return m, nil
+	}
+
+	// An active refresh timer picks up the new interval on its next tick,
+	// but a disabled one has to be restarted.
+	restartRefresh := m.cfg.TUI.RefreshInterval == 0 && msg.cfg.TUI.RefreshInterval > 0
+	rescan := !slices.Equal(m.repoDirs, msg.cfg.RepoDirs)
+	if m.cfg.Git.StatusCacheTTL != msg.cfg.Git.StatusCacheTTL {
+		m.gitCache = newGitStatusCache(msg.cfg.Git.StatusCacheTTL)
+	}
+
+	// Swap rather than update in place: the old config is still read by
+	// the service and by running actions, and is never modified.
+	m.cfg = msg.cfg
+	if m.service != nil {
+		m.service.ReloadConfig(msg.cfg)
+	}
+
+	m.handler.SetKeybindings(m.cfg.Keybindings)
+	m.gitWorkers = m.cfg.Git.StatusWorkers
+	m.copyCommand = m.cfg.Commands.CopyCommand
+	m.repoDirs = m.cfg.RepoDirs
+	m.treeDelegate.Icons = NewIcons(m.ascii, m.cfg.TUI.Icons)
+	m.treeDelegate.ShowActivity = m.cfg.TUI.ShowActivity
+	m.list.SetDelegate(m.treeDelegate)
+
+	var cmds []tea.Cmd
+	if restartRefresh {
+		cmds = append(cmds, m.scheduleSessionRefresh())
+	}
+	if rescan && len(m.repoDirs) > 0 {
+		cmds = append(cmds, m.scanRepoDirs())
+	}
+
+	log.Info().Str("path", m.configPath).Msg("config reloaded")
+	return m, tea.Batch(cmds...)
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	first := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	writeConfig(t, path, "tui:\n  refresh_interval: 5s\n", first)
+
+	t.Run("unchanged returns nil", func(t *testing.T) {
+		assert.Nil(t, checkConfig(path, dir, first)())
+	})
+
+	t.Run("missing file returns nil", func(t *testing.T) {
+		assert.Nil(t, checkConfig(filepath.Join(dir, "missing.yaml"), dir, first)())
+	})
+
+	t.Run("changed file is loaded", func(t *testing.T) {
+		msg, ok := checkConfig(path, dir, time.Time{})().(configReloadedMsg)
+		require.True(t, ok)
+		require.NoError(t, msg.err)
+		assert.Equal(t, 5*time.Second, msg.cfg.TUI.RefreshInterval)
+		assert.Equal(t, first, msg.modTime.Truncate(time.Second))
+	})
+
+	t.Run("invalid config reports error", func(t *testing.T) {
+		second := first.Add(time.Minute)
+		writeConfig(t, path, "keybindings:\n  x:\n    help: nothing\n", second)
+
+		msg, ok := checkConfig(path, dir, first)().(configReloadedMsg)
+		require.True(t, ok)
+		require.Error(t, msg.err)
+		assert.Nil(t, msg.cfg)
+	})
+}
+
+func TestHandleConfigReloaded(t *testing.T) {
+	cfg := &config.Config{
+		Keybindings: map[string]config.Keybinding{"r": {Action: config.ActionRecycle}},
+		Git:         config.GitConfig{StatusWorkers: 1},
+	}
+	m := New(nil, cfg, Options{})
+
+	t.Run("error keeps previous config", func(t *testing.T) {
+		updated, _ := m.handleConfigReloaded(configReloadedMsg{err: assert.AnError, modTime: time.Unix(10, 0)})
+		got := updated.(Model)
+		assert.Equal(t, time.Unix(10, 0), got.configModTime)
+		assert.Equal(t, 1, got.gitWorkers)
+		_, ok := got.handler.keybindings["r"]
+		assert.True(t, ok)
+	})
+
+	t.Run("success applies new config", func(t *testing.T) {
+		next := &config.Config{
+			Keybindings: map[string]config.Keybinding{"o": {Sh: "open {{ .Path }}"}},
+			Git:         config.GitConfig{StatusWorkers: 4},
+			Commands:    config.Commands{CopyCommand: "pbcopy"},
+		}
+		updated, _ := m.handleConfigReloaded(configReloadedMsg{cfg: next, modTime: time.Unix(20, 0)})
+		got := updated.(Model)
+
+		assert.Equal(t, 4, got.gitWorkers)
+		assert.Equal(t, "pbcopy", got.copyCommand)
+		assert.Same(t, next, got.cfg)
+		assert.Equal(t, 1, cfg.Git.StatusWorkers, "the previous config is never modified")
+		_, ok := got.handler.keybindings["o"]
+		assert.True(t, ok)
+	})
+}
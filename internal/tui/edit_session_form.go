@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/styles"
+)
+
+// EditSessionForm wraps a huh.Form for editing a session's name and note.
+type EditSessionForm struct {
+	form      *huh.Form
+	sessionID string
+	oldName   string
+	oldNote   string
+	name      string
+	note      string
+}
+
+// EditSessionFormResult contains the form submission result.
+type EditSessionFormResult struct {
+	SessionID   string
+	Name        string
+	Note        string
+	NameChanged bool
+	NoteChanged bool
+}
+
+// NewEditSessionForm creates a form prefilled with the session's name and
+// note. existingNames is used to validate that a new name is unique.
+func NewEditSessionForm(sess session.Session, existingNames map[string]bool) *EditSessionForm {
+	f := &EditSessionForm{
+		sessionID: sess.ID,
+		oldName:   sess.Name,
+		oldNote:   sess.Note(),
+		name:      sess.Name,
+		note:      sess.Note(),
+	}
+
+	f.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Name").
+				Value(&f.name).
+				Validate(func(s string) error {
+					s = strings.TrimSpace(s)
+					if s == "" {
+						return errors.New("session name is required")
+					}
+					if s != f.oldName && existingNames[s] {
+						return errors.New("session name already exists")
+					}
+					return nil
+				}),
+			huh.NewInput().
+				Title("Note").
+				Placeholder("optional").
+				Value(&f.note),
+		),
+	).WithTheme(styles.FormTheme())
+
+	return f
+}
+
+// Form returns the underlying huh.Form for tea.Model integration.
+func (f *EditSessionForm) Form() *huh.Form {
+	return f.form
+}
+
+// Result returns the edited values and which of them changed.
+func (f *EditSessionForm) Result() EditSessionFormResult {
+	name := strings.TrimSpace(f.name)
+	note := strings.TrimSpace(f.note)
+	return EditSessionFormResult{
+		SessionID:   f.sessionID,
+		Name:        name,
+		Note:        note,
+		NameChanged: name != f.oldName,
+		NoteChanged: note != f.oldNote,
+	}
+}
+
+// View renders the form.
+func (f *EditSessionForm) View() string {
+	return f.form.View()
+}
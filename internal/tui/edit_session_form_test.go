@@ -0,0 +1,37 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEditSessionForm(t *testing.T) {
+	sess := session.Session{ID: "abc", Name: "task", Metadata: map[string]string{session.MetaNote: "waiting"}}
+
+	t.Run("prefills name and note", func(t *testing.T) {
+		form := NewEditSessionForm(sess, nil)
+		require.NotNil(t, form.Form())
+		assert.Equal(t, "task", form.name)
+		assert.Equal(t, "waiting", form.note)
+
+		result := form.Result()
+		assert.Equal(t, "abc", result.SessionID)
+		assert.False(t, result.NameChanged)
+		assert.False(t, result.NoteChanged)
+	})
+
+	t.Run("result trims and reports changes", func(t *testing.T) {
+		form := NewEditSessionForm(sess, nil)
+		form.name = " fix auth "
+		form.note = ""
+
+		result := form.Result()
+		assert.Equal(t, "fix auth", result.Name)
+		assert.True(t, result.NameChanged)
+		assert.Empty(t, result.Note)
+		assert.True(t, result.NoteChanged)
+	})
+}
@@ -2,6 +2,9 @@ package tui
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -58,42 +61,183 @@ func fetchGitStatusForPath(ctx context.Context, g git.Git, path string) GitStatu
 	return status
 }
 
-// fetchGitStatusBatch returns a command that fetches git status for multiple paths
-// using a bounded worker pool.
-func fetchGitStatusBatch(g git.Git, paths []string, workers int) tea.Cmd {
+// fetchGitStatusBatch returns a command that fetches git status for multiple
+// paths with a fixed pool of workers. Paths with a fresh entry in cache are
+// answered from it unless force is set; cache may be nil.
+func fetchGitStatusBatch(g git.Git, paths []string, workers int, cache *gitStatusCache, force bool) tea.Cmd {
 	if len(paths) == 0 {
 		return nil
 	}
 
 	return func() tea.Msg {
-		results := make(map[string]GitStatus)
+		results := make(map[string]GitStatus, len(paths))
 		var mu sync.Mutex
 
-		// Create a semaphore to limit concurrency
-		sem := make(chan struct{}, workers)
+		pending := make(chan string)
 		var wg sync.WaitGroup
+		for range max(1, min(workers, len(paths))) {
+			wg.Go(func() {
+				for p := range pending {
+					stamp := statRepo(p)
+
+					ctx, cancel := context.WithTimeout(context.Background(), gitStatusTimeout)
+					status := fetchGitStatusForPath(ctx, g, p)
+					cancel()
+
+					cache.store(p, stamp, status)
+
+					mu.Lock()
+					results[p] = status
+					mu.Unlock()
+				}
+			})
+		}
 
-		for _, path := range paths {
-			wg.Add(1)
-			go func(p string) {
-				defer wg.Done()
+		for _, p := range paths {
+			if !force {
+				if status, ok := cache.lookup(p); ok {
+					mu.Lock()
+					results[p] = status
+					mu.Unlock()
+					continue
+				}
+			}
+			pending <- p
+		}
+		close(pending)
 
-				// Acquire semaphore
-				sem <- struct{}{}
-				defer func() { <-sem }()
+		wg.Wait()
+		return gitStatusBatchCompleteMsg{Results: results}
+	}
+}
 
-				ctx, cancel := context.WithTimeout(context.Background(), gitStatusTimeout)
-				defer cancel()
+// FetchGitStatuses fetches the git status of each path with a fixed pool of
+// workers, outside of a running TUI.
+func FetchGitStatuses(g git.Git, paths []string, workers int) map[string]GitStatus {
+	cmd := fetchGitStatusBatch(g, paths, workers, nil, true)
+	if cmd == nil {
+		return nil
+	}
+	return cmd().(gitStatusBatchCompleteMsg).Results
+}
 
-				status := fetchGitStatusForPath(ctx, g, p)
+// repoStamp records modification times that change when a repository's
+// status may have changed: HEAD moves on commit and checkout, the index on
+// staging, and the working directory when files are added or removed.
+type repoStamp struct {
+	head  time.Time
+	index time.Time
+	dir   time.Time
+}
 
-				mu.Lock()
-				results[p] = status
-				mu.Unlock()
-			}(path)
+// statRepo returns the stamp for the repository at path. Missing files leave
+// zero times, so a repository that appears later gets a different stamp.
+func statRepo(path string) repoStamp {
+	var stamp repoStamp
+	modTime := func(p string) time.Time {
+		if info, err := os.Stat(p); err == nil {
+			return info.ModTime()
 		}
+		return time.Time{}
+	}
 
-		wg.Wait()
-		return gitStatusBatchCompleteMsg{Results: results}
+	stamp.dir = modTime(path)
+	gitDir := resolveGitDir(path)
+	stamp.head = modTime(filepath.Join(gitDir, "HEAD"))
+	stamp.index = modTime(filepath.Join(gitDir, "index"))
+	return stamp
+}
+
+// resolveGitDir returns the git directory of the worktree at path, following
+// a .git file ("gitdir: ...") as used by linked worktrees.
+func resolveGitDir(path string) string {
+	dotGit := filepath.Join(path, ".git")
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return dotGit // a directory, or missing
+	}
+
+	dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return dotGit
+	}
+	dir = strings.TrimSpace(dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(path, dir)
+	}
+	return dir
+}
+
+// gitStatusCache remembers git statuses so refreshes only re-run git for
+// repositories whose stamp changed or whose entry is older than the TTL.
+// A nil cache never hits. It is safe for concurrent use.
+type gitStatusCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]gitStatusEntry
+	now     func() time.Time
+}
+
+type gitStatusEntry struct {
+	status  GitStatus
+	stamp   repoStamp
+	fetched time.Time
+}
+
+// newGitStatusCache returns a cache keeping entries for ttl, or nil if ttl
+// is not positive.
+func newGitStatusCache(ttl time.Duration) *gitStatusCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &gitStatusCache{ttl: ttl, entries: make(map[string]gitStatusEntry), now: time.Now}
+}
+
+// lookup returns the cached status for path if it has not expired and the
+// repository is unchanged.
+func (c *gitStatusCache) lookup(path string) (GitStatus, bool) {
+	if c == nil {
+		return GitStatus{}, false
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+
+	if !ok || c.now().Sub(entry.fetched) >= c.ttl || statRepo(path) != entry.stamp {
+		return GitStatus{}, false
+	}
+	return entry.status, true
+}
+
+// store records status for path, fetched when the repository had stamp.
+// Failed fetches are not cached so they are retried on the next refresh.
+func (c *gitStatusCache) store(path string, stamp repoStamp, status GitStatus) {
+	if c == nil || status.Error != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[path] = gitStatusEntry{status: status, stamp: stamp, fetched: c.now()}
+}
+
+// prune drops entries for paths not in keep.
+func (c *gitStatusCache) prune(keep []string) {
+	if c == nil {
+		return
+	}
+
+	wanted := make(map[string]struct{}, len(keep))
+	for _, p := range keep {
+		wanted[p] = struct{}{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for p := range c.entries {
+		if _, ok := wanted[p]; !ok {
+			delete(c.entries, p)
+		}
 	}
 }
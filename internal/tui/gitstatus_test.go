@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingGit counts status fetches.
+type countingGit struct {
+	mockGit
+	calls atomic.Int32
+}
+
+func (g *countingGit) Branch(context.Context, string) (string, error) {
+	g.calls.Add(1)
+	return "main", nil
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0o644))
+	return dir
+}
+
+func touch(t *testing.T, path string, at time.Time) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	require.NoError(t, os.Chtimes(path, at, at))
+}
+
+func TestFetchGitStatusBatch_Cache(t *testing.T) {
+	repos := []string{newTestRepo(t), newTestRepo(t), newTestRepo(t)}
+	g := &countingGit{}
+	cache := newGitStatusCache(time.Minute)
+
+	fetch := func(force bool) map[string]GitStatus {
+		msg := fetchGitStatusBatch(g, repos, 2, cache, force)().(gitStatusBatchCompleteMsg)
+		return msg.Results
+	}
+
+	results := fetch(false)
+	require.Len(t, results, 3)
+	assert.Equal(t, "main", results[repos[0]].Branch)
+	assert.Equal(t, int32(3), g.calls.Load())
+
+	t.Run("unchanged repos are served from cache", func(t *testing.T) {
+		assert.Len(t, fetch(false), 3)
+		assert.Equal(t, int32(3), g.calls.Load())
+	})
+
+	t.Run("changed index is re-checked", func(t *testing.T) {
+		touch(t, filepath.Join(repos[1], ".git", "index"), time.Now().Add(time.Second))
+		assert.Len(t, fetch(false), 3)
+		assert.Equal(t, int32(4), g.calls.Load())
+	})
+
+	t.Run("force bypasses cache", func(t *testing.T) {
+		fetch(true)
+		assert.Equal(t, int32(7), g.calls.Load())
+	})
+
+	t.Run("expired entries are re-checked", func(t *testing.T) {
+		cache.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		fetch(false)
+		assert.Equal(t, int32(10), g.calls.Load())
+	})
+}
+
+func TestGitStatusCache_Disabled(t *testing.T) {
+	cache := newGitStatusCache(0)
+	assert.Nil(t, cache)
+
+	repo := newTestRepo(t)
+	cache.store(repo, statRepo(repo), GitStatus{Branch: "main"})
+	_, ok := cache.lookup(repo)
+	assert.False(t, ok)
+}
+
+func TestGitStatusCache_SkipsErrors(t *testing.T) {
+	cache := newGitStatusCache(time.Minute)
+	repo := newTestRepo(t)
+
+	cache.store(repo, statRepo(repo), GitStatus{Error: assert.AnError})
+	_, ok := cache.lookup(repo)
+	assert.False(t, ok)
+}
+
+func TestResolveGitDir_Worktree(t *testing.T) {
+	dir := writeGitFile(t, "gitdir: ../main/.git/worktrees/feat\n")
+	assert.Equal(t, filepath.Join(dir, "..", "main", ".git", "worktrees", "feat"), resolveGitDir(dir))
+	assert.Equal(t, "/abs/.git", resolveGitDir(writeGitFile(t, "gitdir: /abs/.git")))
+}
+
+func writeGitFile(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte(content), 0o644))
+	return dir
+}
+
+func TestPageSessionPaths(t *testing.T) {
+	m := New(nil, &config.Config{}, Options{})
+
+	var sessions []session.Session
+	for i := range 30 {
+		sessions = append(sessions, session.Session{
+			ID:     fmt.Sprintf("s%02d", i),
+			Name:   fmt.Sprintf("s%02d", i),
+			Path:   fmt.Sprintf("/s/%02d", i),
+			Remote: "git@github.com:hay-kot/hive.git",
+			State:  session.StateActive,
+		})
+	}
+	sessions = append(sessions, session.Session{ID: "old", Path: "/s/old", Remote: "git@github.com:hay-kot/hive.git", State: session.StateRecycled})
+
+	items := BuildTreeItems(GroupSessionsByRepo(sessions, ""), "")
+	m.list.SetItems(items)
+	m.list.SetSize(80, 12)
+
+	paths := m.pageSessionPaths()
+	require.NotEmpty(t, paths)
+	assert.Less(t, len(paths), 30, "only the visible page is returned")
+	assert.Equal(t, "/s/00", paths[0])
+
+	m.list.Paginator.NextPage()
+	next := m.pageSessionPaths()
+	require.NotEmpty(t, next)
+	assert.NotContains(t, next, paths[0])
+
+	m.gitStatuses.Set("/s/00", GitStatus{Branch: "main"})
+	m.gitStatuses.Set(next[0], GitStatus{Branch: "main"})
+	m.dropGitStatusesExcept(next)
+	_, ok := m.gitStatuses.Get("/s/00")
+	assert.False(t, ok)
+	_, ok = m.gitStatuses.Get(next[0])
+	assert.True(t, ok)
+}
+
+func TestHasChanges(t *testing.T) {
+	m := New(nil, &config.Config{}, Options{})
+	m.allSessions = []session.Session{
+		{ID: "a", Path: "/s/a", State: session.StateActive, Metadata: map[string]string{session.MetaBatchID: "b1"}},
+		{ID: "b", Path: "/s/b", State: session.StateActive, Metadata: map[string]string{session.MetaBatchID: "b1"}},
+	}
+	m.gitStatuses.Set("/s/a", GitStatus{Branch: "main"})
+
+	assert.False(t, m.hasChanges(Action{SessionPath: "/s/a"}))
+	assert.True(t, m.hasChanges(Action{SessionPath: "/s/b"}), "unknown status counts as changed")
+	assert.True(t, m.hasChanges(Action{Type: ActionTypeRecycleBatch, BatchID: "b1"}))
+
+	m.gitStatuses.Set("/s/b", GitStatus{Branch: "main", HasChanges: true})
+	assert.True(t, m.hasChanges(Action{SessionPath: "/s/b"}))
+	m.gitStatuses.Set("/s/b", GitStatus{Branch: "main"})
+	assert.False(t, m.hasChanges(Action{Type: ActionTypeRecycleBatch, BatchID: "b1"}))
+}
@@ -0,0 +1,120 @@
+package tui
+
+// Icons are the glyphs the session tree is drawn with.
+type Icons struct {
+	Active      string // agent actively working
+	Approval    string // needs approval/permission
+	Ready       string // ready for next input
+	Unknown     string // no terminal found
+	Recycled    string // session recycled
+	CurrentRepo string // marks the repository of the working directory
+	Pinned      string // marks a pinned session
+	Expanded    string // expanded owner header
+	Collapsed   string // collapsed owner header
+	Branch      string // tree line to a session with more below it
+	Last        string // tree line to the last session of a repository
+}
+
+// DefaultIcons returns the glyphs used unless configured otherwise.
+func DefaultIcons() Icons {
+	return Icons{
+		Active:      "[●]",
+		Approval:    "[!]",
+		Ready:       "[>]",
+		Unknown:     "[?]",
+		Recycled:    "[○]",
+		CurrentRepo: "◆",
+		Pinned:      "★",
+		Expanded:    "▾",
+		Collapsed:   "▸",
+		Branch:      "├─",
+		Last:        "└─",
+	}
+}
+
+// ASCIIIcons returns glyphs limited to ASCII, for fonts and terminals that
+// render the defaults poorly.
+func ASCIIIcons() Icons {
+	return Icons{
+		Active:      "[A]",
+		Approval:    "[!]",
+		Ready:       "[>]",
+		Unknown:     "[?]",
+		Recycled:    "[R]",
+		CurrentRepo: "*",
+		Pinned:      "^",
+		Expanded:    "v",
+		Collapsed:   ">",
+		Branch:      "|-",
+		Last:        "`-",
+	}
+}
+
+// NewIcons returns the default glyphs, or the ASCII ones when ascii is set,
+// with the named overrides from tui.icons applied. Unknown names are ignored.
+func NewIcons(ascii bool, overrides map[string]string) Icons {
+	icons := DefaultIcons()
+	if ascii {
+		icons = ASCIIIcons()
+	}
+	for name, glyph := range overrides {
+		if field := icons.field(name); field != nil {
+			*field = glyph
+		}
+	}
+	return icons
+}
+
+// field returns the glyph with the given tui.icons name.
+func (i *Icons) field(name string) *string {
+	switch name {
+	case "active":
+		return &i.Active
+	case "approval":
+		return &i.Approval
+	case "ready":
+		return &i.Ready
+	case "unknown":
+		return &i.Unknown
+	case "recycled":
+		return &i.Recycled
+	case "current_repo":
+		return &i.CurrentRepo
+	case "pinned":
+		return &i.Pinned
+	case "expanded":
+		return &i.Expanded
+	case "collapsed":
+		return &i.Collapsed
+	case "branch":
+		return &i.Branch
+	case "last":
+		return &i.Last
+	default:
+		return nil
+	}
+}
+
+// Status returns the glyph for a session status.
+func (i Icons) Status(status sessionStatus) string {
+	switch status {
+	case statusActive:
+		return i.Active
+	case statusApproval:
+		return i.Approval
+	case statusReady:
+		return i.Ready
+	case statusUnknown:
+		return i.Unknown
+	default:
+		return i.Recycled
+	}
+}
+
+// TreePrefix returns the tree line leading an item's line.
+func (i Icons) TreePrefix(item TreeItem) string {
+	if item.IsLastInRepo {
+		return i.Last
+	}
+	return i.Branch
+}
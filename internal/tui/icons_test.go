@@ -0,0 +1,32 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIcons(t *testing.T) {
+	icons := NewIcons(false, nil)
+	assert.Equal(t, DefaultIcons(), icons)
+
+	icons = NewIcons(true, map[string]string{"active": "[*]", "bogus": "x"})
+	assert.Equal(t, "[*]", icons.Active)
+	assert.Equal(t, "[R]", icons.Recycled)
+	assert.Equal(t, "[?]", icons.Status(statusUnknown))
+	assert.Equal(t, "`-", icons.TreePrefix(TreeItem{IsLastInRepo: true}))
+}
+
+func TestNewIcons_AllNamesOverridable(t *testing.T) {
+	overrides := make(map[string]string, len(config.TUIIconNames))
+	for _, name := range config.TUIIconNames {
+		overrides[name] = "#"
+	}
+
+	icons := NewIcons(false, overrides)
+	assert.Equal(t, Icons{
+		Active: "#", Approval: "#", Ready: "#", Unknown: "#", Recycled: "#",
+		CurrentRepo: "#", Pinned: "#", Expanded: "#", Collapsed: "#", Branch: "#", Last: "#",
+	}, icons)
+}
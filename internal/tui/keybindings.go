@@ -3,6 +3,7 @@ package tui
 import (
 	"context"
 	"fmt"
+	"io"
 	"maps"
 	"os/exec"
 	"slices"
@@ -22,7 +23,14 @@ const (
 	ActionTypeNone ActionType = iota
 	ActionTypeRecycle
 	ActionTypeDelete
+	ActionTypeOpen
 	ActionTypeShell
+	ActionTypePin
+	ActionTypeEdit
+	ActionTypeRecycleBatch
+	ActionTypeOpenURL
+	ActionTypeCopy
+	ActionTypeCI
 )
 
 // Action represents a resolved keybinding action ready for execution.
@@ -31,11 +39,15 @@ type Action struct {
 	Key         string
 	Help        string
 	Confirm     string // Non-empty if confirmation required
+	DirtyOnly   bool   // Confirm only when the session has uncommitted changes
 	ShellCmd    string // For shell actions, the rendered command
+	URL         string // For open_url actions, the url template
+	CopyText    string // For copy actions, the text to put on the clipboard
 	SessionID   string
 	SessionPath string
-	Silent      bool // Skip loading popup for fast commands
-	Exit        bool // Exit hive after command completes
+	BatchID     string // For batch actions, the session's batch
+	Silent      bool   // Skip loading popup for fast commands
+	Exit        bool   // Exit hive after command completes
 }
 
 // NeedsConfirm returns true if the action requires user confirmation.
@@ -57,6 +69,11 @@ func NewKeybindingHandler(keybindings map[string]config.Keybinding, service *hiv
 	}
 }
 
+// SetKeybindings replaces the keybindings, e.g. after a config reload.
+func (h *KeybindingHandler) SetKeybindings(keybindings map[string]config.Keybinding) {
+	h.keybindings = keybindings
+}
+
 // Resolve attempts to resolve a key press to an action for the given session.
 // Recycled sessions only allow delete actions to prevent accidental operations.
 func (h *KeybindingHandler) Resolve(key string, sess session.Session) (Action, bool) {
@@ -73,7 +90,8 @@ func (h *KeybindingHandler) Resolve(key string, sess session.Session) (Action, b
 	action := Action{
 		Key:         key,
 		Help:        kb.Help,
-		Confirm:     kb.Confirm,
+		Confirm:     confirmPrompt(kb),
+		DirtyOnly:   kb.ConfirmPolicy() == config.ConfirmDirtyOnly,
 		SessionID:   sess.ID,
 		SessionPath: sess.Path,
 		Silent:      kb.Silent,
@@ -93,6 +111,60 @@ func (h *KeybindingHandler) Resolve(key string, sess session.Session) (Action, b
 			if action.Help == "" {
 				action.Help = "delete"
 			}
+		case config.ActionOpen:
+			action.Type = ActionTypeOpen
+			if action.Help == "" {
+				action.Help = "open"
+			}
+		case config.ActionPin:
+			action.Type = ActionTypePin
+			if action.Help == "" {
+				action.Help = "pin"
+			}
+		case config.ActionEdit:
+			action.Type = ActionTypeEdit
+			if action.Help == "" {
+				action.Help = "edit"
+			}
+		case config.ActionRecycleBatch:
+			// Only sessions created by hive batch have a batch to recycle
+			if sess.BatchID() == "" {
+				return Action{}, false
+			}
+			action.Type = ActionTypeRecycleBatch
+			action.BatchID = sess.BatchID()
+			if action.Help == "" {
+				action.Help = "recycle batch"
+			}
+		case config.ActionOpenURL:
+			action.Type = ActionTypeOpenURL
+			action.URL = kb.URL
+			if action.Help == "" {
+				action.Help = "open url"
+			}
+		case config.ActionCopyPath:
+			action.Type = ActionTypeCopy
+			action.CopyText = sess.WorkDir()
+			if action.Help == "" {
+				action.Help = "copy path"
+			}
+		case config.ActionCopyID:
+			action.Type = ActionTypeCopy
+			action.CopyText = sess.ID
+			if action.Help == "" {
+				action.Help = "copy id"
+			}
+		case config.ActionCopyInboxTopic:
+			action.Type = ActionTypeCopy
+			action.CopyText = sess.InboxTopic()
+			if action.Help == "" {
+				action.Help = "copy inbox topic"
+			}
+		case config.ActionCI:
+			action.Type = ActionTypeCI
+			if action.Help == "" {
+				action.Help = "ci"
+			}
 		}
 		return action, true
 	}
@@ -101,11 +173,13 @@ func (h *KeybindingHandler) Resolve(key string, sess session.Session) (Action, b
 	if kb.Sh != "" {
 		data := struct {
 			Path   string
+			Root   string
 			Remote string
 			ID     string
 			Name   string
 		}{
-			Path:   sess.Path,
+			Path:   sess.WorkDir(),
+			Root:   sess.Path,
 			Remote: sess.Remote,
 			ID:     sess.ID,
 			Name:   sess.Name,
@@ -127,15 +201,43 @@ func (h *KeybindingHandler) Resolve(key string, sess session.Session) (Action, b
 	return Action{}, false
 }
 
+// confirmPrompt returns the confirmation prompt of a keybinding, empty when
+// it never asks.
+func confirmPrompt(kb config.Keybinding) string {
+	if kb.ConfirmPolicy() == config.ConfirmNever {
+		return ""
+	}
+	if prompt := kb.ConfirmPrompt(); prompt != "" {
+		return prompt
+	}
+	return fmt.Sprintf("Are you sure you want to run %q on this session?", keybindingHelp(kb))
+}
+
 // Execute runs the given action.
-// Note: ActionTypeRecycle is not handled here - it uses streaming output
-// and is executed directly by the TUI model via Service.RecycleSession.
+// Note: ActionTypeRecycle, ActionTypeRecycleBatch, ActionTypeOpen,
+// ActionTypeEdit, and ActionTypeCopy are not handled here - recycles stream
+// output to the action queue, open takes over the terminal, edit opens a
+// form, and copies use the TUI's clipboard, so the TUI model runs them
+// directly.
 func (h *KeybindingHandler) Execute(ctx context.Context, action Action) error {
 	switch action.Type {
 	case ActionTypeDelete:
 		return h.service.DeleteSession(ctx, action.SessionID)
 	case ActionTypeShell:
 		return h.executeShell(ctx, action.ShellCmd)
+	case ActionTypePin:
+		_, err := h.service.TogglePin(ctx, action.SessionID)
+		return err
+	case ActionTypeOpenURL:
+		url, err := h.service.SessionURL(ctx, action.SessionID, action.URL)
+		if err != nil {
+			return err
+		}
+		return h.executeShell(ctx, hive.BrowserCommand(url))
+	case ActionTypeCI:
+		// The status arrives on the run's topic; hive ci waits for it
+		_, err := h.service.TriggerCI(ctx, action.SessionID, io.Discard, io.Discard)
+		return err
 	default:
 		return fmt.Errorf("action type %d not supported by Execute", action.Type)
 	}
@@ -148,6 +250,55 @@ func (h *KeybindingHandler) executeShell(_ context.Context, cmd string) error {
 	return c.Run()
 }
 
+// IsLeader reports whether keys, the keys pressed so far separated by
+// spaces, start a longer chord. A leader waits for the next key instead of
+// running its own binding.
+func (h *KeybindingHandler) IsLeader(keys string) bool {
+	for k := range h.keybindings {
+		if strings.HasPrefix(k, keys+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// ChordEntries returns the keys that may follow the leader keys with their
+// help, sorted by key. A key that leads to a longer chord is shown as "+more".
+func (h *KeybindingHandler) ChordEntries(keys string) []string {
+	prefix := keys + " "
+	next := make(map[string]string)
+	for k, kb := range h.keybindings {
+		rest, ok := strings.CutPrefix(k, prefix)
+		if !ok {
+			continue
+		}
+		if first, _, chord := strings.Cut(rest, " "); chord {
+			next[first] = "+more"
+			continue
+		}
+		if _, seen := next[rest]; !seen {
+			next[rest] = keybindingHelp(kb)
+		}
+	}
+
+	entries := make([]string, 0, len(next))
+	for _, k := range slices.Sorted(maps.Keys(next)) {
+		entries = append(entries, fmt.Sprintf("[%s] %s", k, next[k]))
+	}
+	return entries
+}
+
+// keybindingHelp returns the help text of a keybinding.
+func keybindingHelp(kb config.Keybinding) string {
+	if kb.Help != "" {
+		return kb.Help
+	}
+	if kb.Action != "" {
+		return kb.Action
+	}
+	return "shell"
+}
+
 // HelpEntries returns all configured keybindings for display, sorted by key.
 func (h *KeybindingHandler) HelpEntries() []string {
 	// Get sorted keys for consistent ordering
@@ -155,15 +306,7 @@ func (h *KeybindingHandler) HelpEntries() []string {
 
 	entries := make([]string, 0, len(h.keybindings))
 	for _, key := range keys {
-		kb := h.keybindings[key]
-		help := kb.Help
-		if help == "" && kb.Action != "" {
-			help = kb.Action
-		}
-		if help == "" {
-			help = "shell"
-		}
-		entries = append(entries, fmt.Sprintf("[%s] %s", key, help))
+		entries = append(entries, fmt.Sprintf("[%s] %s", key, keybindingHelp(h.keybindings[key])))
 	}
 	return entries
 }
@@ -180,18 +323,9 @@ func (h *KeybindingHandler) KeyBindings() []key.Binding {
 	bindings := make([]key.Binding, 0, len(keys))
 
 	for _, k := range keys {
-		kb := h.keybindings[k]
-		help := kb.Help
-		if help == "" && kb.Action != "" {
-			help = kb.Action
-		}
-		if help == "" {
-			help = "shell"
-		}
-
 		bindings = append(bindings, key.NewBinding(
 			key.WithKeys(k),
-			key.WithHelp(k, help),
+			key.WithHelp(k, keybindingHelp(h.keybindings[k])),
 		))
 	}
 
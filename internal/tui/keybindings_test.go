@@ -12,6 +12,10 @@ func TestKeybindingHandler_Resolve_RecycledSession(t *testing.T) {
 		"d": {Action: config.ActionDelete, Help: "delete"},
 		"r": {Action: config.ActionRecycle, Help: "recycle"},
 		"o": {Sh: "code {{ .Path }}", Help: "open in vscode"},
+		"p": {Action: config.ActionPin},
+		"R": {Action: config.ActionRecycleBatch},
+		"u": {Action: config.ActionOpenURL, URL: "https://github.com/{{ .OwnerRepo }}/pulls"},
+		"C": {Action: config.ActionCI},
 	}
 
 	handler := NewKeybindingHandler(keybindings, nil)
@@ -22,6 +26,13 @@ func TestKeybindingHandler_Resolve_RecycledSession(t *testing.T) {
 		State: session.StateActive,
 	}
 
+	batchSession := session.Session{
+		ID:       "test-id",
+		Path:     "/test/path",
+		State:    session.StateActive,
+		Metadata: map[string]string{session.MetaBatchID: "b1"},
+	}
+
 	recycledSession := session.Session{
 		ID:    "test-id",
 		Path:  "/test/path",
@@ -56,6 +67,40 @@ func TestKeybindingHandler_Resolve_RecycledSession(t *testing.T) {
 			wantOK:  true,
 			wantTyp: ActionTypeShell,
 		},
+		{
+			name:    "active session allows pin",
+			key:     "p",
+			sess:    activeSession,
+			wantOK:  true,
+			wantTyp: ActionTypePin,
+		},
+		{
+			name:    "active session allows open url",
+			key:     "u",
+			sess:    activeSession,
+			wantOK:  true,
+			wantTyp: ActionTypeOpenURL,
+		},
+		{
+			name:    "active session allows ci",
+			key:     "C",
+			sess:    activeSession,
+			wantOK:  true,
+			wantTyp: ActionTypeCI,
+		},
+		{
+			name:    "batch session allows recycle batch",
+			key:     "R",
+			sess:    batchSession,
+			wantOK:  true,
+			wantTyp: ActionTypeRecycleBatch,
+		},
+		{
+			name:   "session outside a batch blocks recycle batch",
+			key:    "R",
+			sess:   activeSession,
+			wantOK: false,
+		},
 		{
 			name:    "recycled session allows delete",
 			key:     "d",
@@ -95,3 +140,67 @@ func TestKeybindingHandler_Resolve_RecycledSession(t *testing.T) {
 		})
 	}
 }
+
+func TestKeybindingHandler_Resolve_Copy(t *testing.T) {
+	handler := NewKeybindingHandler(map[string]config.Keybinding{
+		"yp": {Action: config.ActionCopyPath},
+		"yi": {Action: config.ActionCopyID},
+		"yt": {Action: config.ActionCopyInboxTopic},
+	}, nil)
+
+	sess := session.Session{
+		ID:       "abc123",
+		Name:     "fix-auth",
+		Path:     "/repos/hive-abc123",
+		State:    session.StateActive,
+		Metadata: map[string]string{session.MetaSubdir: "web"},
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "yp", want: "/repos/hive-abc123/web"},
+		{key: "yi", want: "abc123"},
+		{key: "yt", want: "agent.abc123.inbox"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			action, ok := handler.Resolve(tt.key, sess)
+			if !ok || action.Type != ActionTypeCopy {
+				t.Fatalf("Resolve() = %v, %v, want a copy action", action.Type, ok)
+			}
+			if action.CopyText != tt.want {
+				t.Errorf("Resolve() CopyText = %q, want %q", action.CopyText, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeybindingHandler_Resolve_ConfirmPolicy(t *testing.T) {
+	handler := NewKeybindingHandler(map[string]config.Keybinding{
+		"r": {Action: config.ActionRecycle, Confirm: config.ConfirmDirtyOnly},
+		"d": {Action: config.ActionDelete, Confirm: "Really delete?"},
+		"D": {Action: config.ActionDelete, Confirm: config.ConfirmAlways},
+		"p": {Action: config.ActionPin, Confirm: config.ConfirmNever},
+	}, nil)
+	sess := session.Session{ID: "s1", Path: "/s/1", State: session.StateActive}
+
+	recycle, _ := handler.Resolve("r", sess)
+	if !recycle.DirtyOnly || recycle.Confirm == "" {
+		t.Errorf("dirty-only recycle = %+v, want a dirty-only confirm", recycle)
+	}
+	del, _ := handler.Resolve("d", sess)
+	if del.DirtyOnly || del.Confirm != "Really delete?" {
+		t.Errorf("delete with prompt = %+v, want an always confirm with the prompt", del)
+	}
+	always, _ := handler.Resolve("D", sess)
+	if always.DirtyOnly || always.Confirm == "" || always.Confirm == config.ConfirmAlways {
+		t.Errorf("always delete = %+v, want an always confirm with a default prompt", always)
+	}
+	pin, _ := handler.Resolve("p", sess)
+	if pin.NeedsConfirm() {
+		t.Errorf("never pin = %+v, want no confirm", pin)
+	}
+}
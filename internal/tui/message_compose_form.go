@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/styles"
+)
+
+// MessageComposeForm wraps the huh.Forms for composing a message. It runs
+// in two steps: the topic, priority, and an optional snippet, then a
+// multi-line editor seeded with the snippet's payload.
+type MessageComposeForm struct {
+	form     *huh.Form
+	snippets map[string]config.MessageSnippet
+	topic    string
+	snippet  string // selected snippet name; empty for none
+	priority messaging.Priority
+	payload  string
+	editing  bool // true once on the payload step
+}
+
+// NewMessageComposeForm creates a compose form. Topics are offered as
+// completions for the topic input; snippets, if any, can be picked to seed
+// the payload.
+func NewMessageComposeForm(topics []string, snippets map[string]config.MessageSnippet) *MessageComposeForm {
+	f := &MessageComposeForm{snippets: snippets}
+
+	var fields []huh.Field
+	if len(snippets) > 0 {
+		options := []huh.Option[string]{huh.NewOption("(none)", "")}
+		for _, name := range (config.MessagingConfig{Snippets: snippets}).SnippetNames() {
+			options = append(options, huh.NewOption(name, name))
+		}
+		fields = append(fields, huh.NewSelect[string]().
+			Title("Snippet").
+			Options(options...).
+			Value(&f.snippet).
+			Height(6))
+	}
+
+	fields = append(fields, huh.NewInput().
+		Title("Topic").
+		Placeholder("ctrl+e to complete").
+		Suggestions(topics).
+		Value(&f.topic).
+		Validate(func(s string) error {
+			if strings.TrimSpace(s) == "" && f.snippets[f.snippet].Topic == "" {
+				return errors.New("topic is required")
+			}
+			if strings.ContainsAny(strings.TrimSpace(s), " \t\n") {
+				return errors.New("topic cannot contain spaces")
+			}
+			return nil
+		}))
+
+	fields = append(fields, huh.NewSelect[messaging.Priority]().
+		Title("Priority").
+		Options(
+			huh.NewOption("normal", messaging.Priority("")),
+			huh.NewOption("low", messaging.PriorityLow),
+			huh.NewOption("high", messaging.PriorityHigh),
+			huh.NewOption("urgent", messaging.PriorityUrgent),
+		).
+		Value(&f.priority).
+		Inline(true))
+
+	f.form = huh.NewForm(huh.NewGroup(fields...)).WithTheme(styles.FormTheme())
+	return f
+}
+
+// Form returns the underlying huh.Form for tea.Model integration.
+func (f *MessageComposeForm) Form() *huh.Form {
+	return f.form
+}
+
+// Editing reports whether the form is on the payload step.
+func (f *MessageComposeForm) Editing() bool {
+	return f.editing
+}
+
+// Edit advances to the payload step, seeding the editor with the selected
+// snippet. Returns the new form's init command.
+func (f *MessageComposeForm) Edit() tea.Cmd {
+	f.topic = strings.TrimSpace(f.topic)
+	if snippet, ok := f.snippets[f.snippet]; ok {
+		if f.topic == "" {
+			f.topic = snippet.Topic
+		}
+		f.payload = snippet.Payload
+	}
+	f.editing = true
+
+	f.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Message to " + f.topic).
+				Description("enter to publish, alt+enter for a new line").
+				Lines(8).
+				Value(&f.payload).
+				Validate(func(s string) error {
+					if strings.TrimSpace(s) == "" {
+						return errors.New("message is required")
+					}
+					return nil
+				}),
+		),
+	).WithTheme(styles.FormTheme())
+	return f.form.Init()
+}
+
+// Result returns the message to publish. Only valid once the payload step
+// has completed.
+func (f *MessageComposeForm) Result() messaging.Message {
+	return messaging.Message{
+		Topic:    f.topic,
+		Payload:  f.payload,
+		Priority: f.priority,
+	}
+}
+
+// View renders the form.
+func (f *MessageComposeForm) View() string {
+	return f.form.View()
+}
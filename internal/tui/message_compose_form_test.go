@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageComposeForm(t *testing.T) {
+	snippets := map[string]config.MessageSnippet{
+		"handoff": {Topic: "handoff.review", Payload: "Ready for review"},
+		"status":  {Payload: "Status: "},
+	}
+
+	t.Run("snippet seeds topic and payload", func(t *testing.T) {
+		form := NewMessageComposeForm([]string{"build"}, snippets)
+		require.NotNil(t, form.Form())
+		assert.False(t, form.Editing())
+
+		form.snippet = "handoff"
+		form.Edit()
+		assert.True(t, form.Editing())
+
+		msg := form.Result()
+		assert.Equal(t, "handoff.review", msg.Topic)
+		assert.Equal(t, "Ready for review", msg.Payload)
+	})
+
+	t.Run("entered topic wins over snippet topic", func(t *testing.T) {
+		form := NewMessageComposeForm(nil, snippets)
+		form.snippet = "handoff"
+		form.topic = " agent.abc.inbox "
+		form.Edit()
+
+		assert.Equal(t, "agent.abc.inbox", form.Result().Topic)
+	})
+
+	t.Run("no snippet leaves payload empty", func(t *testing.T) {
+		form := NewMessageComposeForm(nil, nil)
+		form.topic = "build"
+		form.Edit()
+
+		msg := form.Result()
+		assert.Equal(t, "build", msg.Topic)
+		assert.Empty(t, msg.Payload)
+	})
+}
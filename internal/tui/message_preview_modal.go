@@ -130,12 +130,28 @@ func (m MessagePreviewModal) Overlay(background string, width, height int) strin
 	timeStr := previewTimeStyle.Render(m.message.CreatedAt.Format("2006-01-02 15:04:05"))
 	metadata := fmt.Sprintf("%s %s %s %s", topicStr, senderStr, iconDot, timeStr)
 
+	// Flag urgent and high priority messages
+	switch m.message.Priority {
+	case messaging.PriorityUrgent:
+		metadata += " " + lipgloss.NewStyle().Foreground(colorRed).Bold(true).Render("URGENT")
+	case messaging.PriorityHigh:
+		metadata += " " + lipgloss.NewStyle().Foreground(colorYellow).Bold(true).Render("HIGH")
+	}
+
 	// Add session ID if present
 	if m.message.SessionID != "" {
 		sessionStr := previewSessionStyle.Render(fmt.Sprintf("session: %s", m.message.SessionID))
 		metadata = fmt.Sprintf("%s\n%s", metadata, sessionStr)
 	}
 
+	// Add publisher host, user, and tool if recorded
+	if origin := m.message.Origin(); origin != "" {
+		if m.message.Tool != "" {
+			origin = fmt.Sprintf("%s (%s)", origin, m.message.Tool)
+		}
+		metadata = fmt.Sprintf("%s\n%s", metadata, previewSessionStyle.Render("from: "+origin))
+	}
+
 	// Build scroll indicator
 	scrollInfo := ""
 	if m.viewport.TotalLineCount() > m.viewport.VisibleLineCount() {
@@ -190,6 +190,8 @@ func (v *MessagesView) applyFilter() {
 func (v *MessagesView) matchesFilter(msg *messaging.Message, filter string) bool {
 	return strings.Contains(strings.ToLower(msg.Topic), filter) ||
 		strings.Contains(strings.ToLower(msg.Sender), filter) ||
+		strings.Contains(strings.ToLower(msg.Origin()), filter) ||
+		strings.Contains(strings.ToLower(msg.Tool), filter) ||
 		strings.Contains(strings.ToLower(msg.Payload), filter)
 }
 
@@ -278,7 +280,7 @@ func (v *MessagesView) View() string {
 	}
 
 	// Help line (pinned to bottom, styled to match sessions view)
-	help := lipgloss.NewStyle().Foreground(colorGray).PaddingLeft(1).Render("↑/↓ navigate • enter preview • / filter • tab switch view")
+	help := lipgloss.NewStyle().Foreground(colorGray).PaddingLeft(1).Render("↑/↓ navigate • enter preview • n new • / filter • tab switch view")
 	b.WriteString(help)
 
 	return b.String()
@@ -305,6 +307,9 @@ func (v *MessagesView) renderMessageLine(msg *messaging.Message, selected bool,
 
 	// Sender (with color hashing, fixed width, in brackets)
 	sender := msg.Sender
+	if sender == "" {
+		sender = msg.Origin()
+	}
 	if sender == "" {
 		sender = "unknown"
 	}
@@ -328,6 +333,20 @@ func (v *MessagesView) renderMessageLine(msg *messaging.Message, selected bool,
 	b.WriteString(topicStyle.Render(topicPadded))
 	b.WriteString(" ")
 
+	// Priority badge (urgent and high only), taken from the content width
+	payloadStyle := lipgloss.NewStyle().Foreground(colorWhite)
+	switch msg.Priority {
+	case messaging.PriorityUrgent:
+		b.WriteString(lipgloss.NewStyle().Foreground(colorRed).Bold(true).Render("‼ "))
+		contentW -= 2
+		payloadStyle = payloadStyle.Bold(true)
+	case messaging.PriorityHigh:
+		b.WriteString(lipgloss.NewStyle().Foreground(colorYellow).Bold(true).Render("! "))
+		contentW -= 2
+	case messaging.PriorityLow:
+		payloadStyle = payloadStyle.Foreground(colorGray)
+	}
+
 	// Message preview (truncated, fills remaining space)
 	payload := strings.ReplaceAll(msg.Payload, "\n", " ")
 	payload = strings.ReplaceAll(payload, "\t", " ")
@@ -335,7 +354,6 @@ func (v *MessagesView) renderMessageLine(msg *messaging.Message, selected bool,
 	if len(payloadRunes) > contentW-1 {
 		payload = string(payloadRunes[:contentW-1]) + "…"
 	}
-	payloadStyle := lipgloss.NewStyle().Foreground(colorWhite)
 	if selected {
 		payloadStyle = payloadStyle.Bold(true)
 	}
@@ -2,7 +2,11 @@ package tui
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +22,8 @@ import (
 	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/internal/integration/terminal"
 	"github.com/hay-kot/hive/pkg/kv"
+	"github.com/muesli/termenv"
+	"github.com/rs/zerolog/log"
 )
 
 // UIState represents the current state of the TUI.
@@ -26,10 +32,11 @@ type UIState int
 const (
 	stateNormal UIState = iota
 	stateConfirming
-	stateLoading
-	stateRunningRecycle
 	statePreviewingMessage
 	stateCreatingSession
+	stateComposingMessage
+	stateEditingSession
+	stateFilteringBatch
 )
 
 // Key constants for event handling.
@@ -43,6 +50,8 @@ type Options struct {
 	LocalRemote     string            // Remote URL of current directory (empty if not in git repo)
 	MsgStore        messaging.Store   // Message store for pub/sub events (optional)
 	TerminalManager *terminal.Manager // Terminal integration manager (optional)
+	ConfigPath      string            // Config file to watch for changes (optional)
+	ASCII           bool              // Draw the tree with ASCII glyphs
 }
 
 // PendingCreate holds data for a session to create after TUI exits.
@@ -53,22 +62,24 @@ type PendingCreate struct {
 
 // Model is the main Bubble Tea model for the TUI.
 type Model struct {
-	cfg            *config.Config
-	service        *hive.Service
-	list           list.Model
-	handler        *KeybindingHandler
-	state          UIState
-	modal          Modal
-	pending        Action
-	width          int
-	height         int
-	err            error
-	spinner        spinner.Model
-	loadingMessage string
-	quitting       bool
-	gitStatuses    *kv.Store[string, GitStatus]
-	gitWorkers     int
-	columnWidths   *ColumnWidths
+	cfg          *config.Config
+	service      *hive.Service
+	list         list.Model
+	handler      *KeybindingHandler
+	state        UIState
+	modal        Modal
+	pending      Action
+	chordKeys    string // leader keys of a chord pressed so far, e.g. "g"
+	width        int
+	height       int
+	err          error
+	spinner      spinner.Model
+	quitting     bool
+	gitStatuses  *kv.Store[string, GitStatus]
+	gitWorkers   int
+	gitCache     *gitStatusCache
+	columnWidths *RepoColumnWidths
+	actions      *actionQueue // session actions running in the background
 
 	// Terminal integration
 	terminalManager  *terminal.Manager
@@ -77,16 +88,20 @@ type Model struct {
 	// Status animation
 	animationFrame int
 	treeDelegate   TreeDelegate // Keep reference to update animation frame
+	ascii          bool         // Draw the tree with ASCII glyphs (--ascii)
 
 	// Filtering
 	localRemote string            // Remote URL of current directory (for highlighting)
 	allSessions []session.Session // All sessions (unfiltered)
-
-	// Recycle streaming state
-	outputModal   OutputModal
-	recycleOutput <-chan string
-	recycleDone   <-chan error
-	recycleCancel context.CancelFunc
+	// remoteSessions are the sessions on configured remote hosts, shown
+	// read-only below the local ones.
+	remoteSessions []hive.HostSessions
+	// collapsedOwners are the owners whose repositories are hidden when the
+	// tree is grouped by owner.
+	collapsedOwners map[string]bool
+	// batchFilter limits the tree to the sessions of one hive batch.
+	batchFilter string
+	batchForm   *BatchFilterForm
 
 	// Layout
 	activeView ViewType // which view is shown
@@ -102,6 +117,12 @@ type Model struct {
 	// Message preview
 	previewModal MessagePreviewModal
 
+	// Message composer
+	composeForm *MessageComposeForm
+
+	// Session name and note editor
+	editForm *EditSessionForm
+
 	// Clipboard
 	copyCommand string
 
@@ -112,6 +133,13 @@ type Model struct {
 
 	// Pending action for after TUI exits
 	pendingCreate *PendingCreate
+
+	// First-run guide, shown while there are no sessions
+	onboarding *Onboarding
+
+	// Config hot reload
+	configPath    string
+	configModTime time.Time
 }
 
 // PendingCreate returns any pending session creation data.
@@ -125,25 +153,38 @@ type sessionsLoadedMsg struct {
 	err      error
 }
 
+// remoteSessionsLoadedMsg is sent when sessions on remote hosts are loaded.
+type remoteSessionsLoadedMsg struct {
+	results []hive.HostSessions
+}
+
 // actionCompleteMsg is sent when an action completes.
 type actionCompleteMsg struct {
 	err error
 }
 
-// recycleStartedMsg is sent when recycle begins with streaming output.
-type recycleStartedMsg struct {
+// queuedActionOutputMsg is sent when a queued action writes output.
+type queuedActionOutputMsg struct {
+	id     int
+	line   string
 	output <-chan string
 	done   <-chan error
-	cancel context.CancelFunc
 }
 
-// recycleOutputMsg is sent when new output is available.
-type recycleOutputMsg struct {
-	line string
+// queuedActionDoneMsg is sent when a queued action finishes.
+type queuedActionDoneMsg struct {
+	id  int
+	err error
+}
+
+// queuedActionExpiredMsg is sent when a finished action should leave the
+// progress list.
+type queuedActionExpiredMsg struct {
+	id int
 }
 
-// recycleCompleteMsg is sent when recycle finishes.
-type recycleCompleteMsg struct {
+// messagePublishedMsg is sent when a composed message has been published.
+type messagePublishedMsg struct {
 	err error
 }
 
@@ -156,12 +197,14 @@ type reposDiscoveredMsg struct {
 func New(service *hive.Service, cfg *config.Config, opts Options) Model {
 	gitStatuses := kv.New[string, GitStatus]()
 	terminalStatuses := kv.New[string, TerminalStatus]()
-	columnWidths := &ColumnWidths{}
+	columnWidths := &RepoColumnWidths{}
 
 	delegate := NewTreeDelegate()
 	delegate.GitStatuses = gitStatuses
 	delegate.TerminalStatuses = terminalStatuses
 	delegate.ColumnWidths = columnWidths
+	delegate.Icons = NewIcons(opts.ASCII, cfg.TUI.Icons)
+	delegate.ShowActivity = cfg.TUI.ShowActivity
 
 	l := list.New([]list.Item{}, delegate, 0, 0)
 	l.SetShowStatusBar(false)
@@ -193,6 +236,10 @@ func New(service *hive.Service, cfg *config.Config, opts Options) Model {
 			key.WithKeys("g"),
 			key.WithHelp("g", "refresh git"),
 		))
+		bindings = append(bindings, key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "filter batch"),
+		))
 		// Add tab keybinding for view switching
 		bindings = append(bindings, key.NewBinding(
 			key.WithKeys("tab"),
@@ -217,20 +264,39 @@ func New(service *hive.Service, cfg *config.Config, opts Options) Model {
 		spinner:          s,
 		gitStatuses:      gitStatuses,
 		gitWorkers:       cfg.Git.StatusWorkers,
+		gitCache:         newGitStatusCache(cfg.Git.StatusCacheTTL),
 		columnWidths:     columnWidths,
+		actions:          newActionQueue(actionQueueWorkers),
 		terminalManager:  opts.TerminalManager,
 		terminalStatuses: terminalStatuses,
 		treeDelegate:     delegate,
+		ascii:            opts.ASCII,
 		localRemote:      opts.LocalRemote,
+		collapsedOwners:  make(map[string]bool),
 		msgStore:         opts.MsgStore,
 		msgView:          msgView,
 		topicFilter:      "*",
 		activeView:       ViewSessions,
 		copyCommand:      cfg.Commands.CopyCommand,
 		repoDirs:         cfg.RepoDirs,
+		configPath:       opts.ConfigPath,
+		configModTime:    configModTime(opts.ConfigPath),
+		onboarding:       newFirstRunOnboarding(opts),
 	}
 }
 
+// newFirstRunOnboarding returns the onboarding screen when the config file
+// does not exist yet, or nil.
+func newFirstRunOnboarding(opts Options) *Onboarding {
+	if opts.ConfigPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(opts.ConfigPath); !errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return NewOnboarding(opts.ConfigPath, opts.LocalRemote)
+}
+
 // Init initializes the model.
 func (m Model) Init() tea.Cmd {
 	cmds := []tea.Cmd{m.loadSessions(), m.spinner.Tick}
@@ -252,6 +318,10 @@ func (m Model) Init() tea.Cmd {
 		cmds = append(cmds, startTerminalPollTicker(m.cfg.Integrations.Terminal.PollInterval))
 		cmds = append(cmds, scheduleAnimationTick())
 	}
+	// Watch config file for changes
+	if m.configPath != "" {
+		cmds = append(cmds, scheduleConfigWatch())
+	}
 	return tea.Batch(cmds...)
 }
 
@@ -263,39 +333,133 @@ func (m Model) scanRepoDirs() tea.Cmd {
 	}
 }
 
-// loadSessions returns a command that loads sessions from the service.
+// loadSessions returns a command that loads sessions from the service, and
+// from remote hosts if any are configured. Remote sessions arrive separately
+// so a slow host does not delay the local list.
 func (m Model) loadSessions() tea.Cmd {
-	return func() tea.Msg {
+	local := func() tea.Msg {
 		sessions, err := m.service.ListSessions(context.Background())
 		return sessionsLoadedMsg{sessions: sessions, err: err}
 	}
+	if len(m.service.Hosts()) == 0 {
+		return local
+	}
+
+	remote := func() tea.Msg {
+		return remoteSessionsLoadedMsg{results: m.service.RemoteSessions(context.Background())}
+	}
+	return tea.Batch(local, remote)
 }
 
-// executeAction returns a command that executes the given action.
-func (m Model) executeAction(action Action) tea.Cmd {
+// queueAction adds an action to the action queue and starts it if a worker
+// is free. Actions on a session that already has one pending are dropped.
+func (m Model) queueAction(action Action) (tea.Model, tea.Cmd) {
+	m.pending = Action{}
+
+	name := action.SessionID
+	if idx := slices.IndexFunc(m.allSessions, func(s session.Session) bool { return s.ID == action.SessionID }); idx >= 0 {
+		name = m.allSessions[idx].Name
+	}
+	if !m.actions.Add(action, name) {
+		return m, nil
+	}
+	m.resizeContent()
+	return m, m.startQueuedActions()
+}
+
+// startQueuedActions returns a command running the queued actions that fit
+// within the worker limit.
+func (m Model) startQueuedActions() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, job := range m.actions.Start() {
+		cmds = append(cmds, m.runQueuedAction(job))
+	}
+	return tea.Batch(cmds...)
+}
+
+// runQueuedAction returns a command that runs a queued action, streaming its
+// output to the progress list.
+func (m Model) runQueuedAction(job queuedAction) tea.Cmd {
+	ctx := m.actions.Context()
 	return func() tea.Msg {
-		err := m.handler.Execute(context.Background(), action)
-		return actionCompleteMsg{err: err}
+		output := make(chan string, 100)
+		done := make(chan error, 1)
+
+		go func() {
+			defer close(output)
+			defer close(done)
+
+			switch job.action.Type {
+			case ActionTypeRecycle:
+				done <- m.service.RecycleSession(ctx, job.action.SessionID, &channelWriter{ch: output, ctx: ctx})
+				return
+			case ActionTypeCopy:
+				done <- m.copyToClipboard(job.action.CopyText)
+				return
+			}
+			done <- m.handler.Execute(ctx, job.action)
+		}()
+
+		return listenForActionOutput(job.id, output, done)()
+	}
+}
+
+// listenForActionOutput returns a command that waits for the next output or
+// completion of a queued action.
+func listenForActionOutput(id int, output <-chan string, done <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case line, ok := <-output:
+			if !ok {
+				// Output channel closed, wait for done
+				return queuedActionDoneMsg{id: id, err: <-done}
+			}
+			return queuedActionOutputMsg{id: id, line: line, output: output, done: done}
+		case err := <-done:
+			return queuedActionDoneMsg{id: id, err: err}
+		}
 	}
 }
 
-// Update handles messages.
+// openSession returns a command that opens the session directory with the
+// configured opener, suspending the TUI so terminal editors can run.
+func (m Model) openSession(sessionID string) tea.Cmd {
+	cmd, err := m.service.OpenCommand(context.Background(), sessionID)
+	if err != nil {
+		return func() tea.Msg { return actionCompleteMsg{err: err} }
+	}
+
+	return tea.ExecProcess(exec.Command("sh", "-c", cmd), func(err error) tea.Msg {
+		if err != nil {
+			err = fmt.Errorf("open session: %w", err)
+		}
+		return actionCompleteMsg{err: err}
+	})
+}
+
+// Update handles messages. Git status is only fetched for sessions on the
+// visible page of the list, so it is requested as the user pages or filters
+// instead of for every session up front.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	nm, ok := next.(Model)
+	if !ok || nm.quitting {
+		m.actions.Cancel()
+		return next, cmd
+	}
+	if fetch := nm.fetchPageGitStatus(); fetch != nil {
+		cmd = tea.Batch(cmd, fetch)
+	}
+	return nm, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
-		// Account for: banner (5 lines) + tab bar (1) = 6 lines
-		// (spacing line is included in list's titleView and msgView's prefix)
-		contentHeight := msg.Height - 6
-		if contentHeight < 1 {
-			contentHeight = 1
-		}
-
-		m.list.SetSize(msg.Width, contentHeight)
-		// msgView gets -1 because we prepend a blank line for consistent spacing
-		m.msgView.SetSize(msg.Width, contentHeight-1)
+		m.resizeContent()
 		return m, nil
 
 	case messagesLoadedMsg:
@@ -303,9 +467,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Silently ignore message loading errors
 			return m, nil
 		}
-		// Append new messages if any
-		if len(msg.messages) > 0 {
+		// Append new messages, dropping any that have expired since loading
+		expired := slices.ContainsFunc(m.allMessages, func(message messaging.Message) bool {
+			return message.Expired(time.Now())
+		})
+		if len(msg.messages) > 0 || expired {
 			m.allMessages = append(m.allMessages, msg.messages...)
+			m.allMessages = slices.DeleteFunc(m.allMessages, func(message messaging.Message) bool {
+				return message.Expired(time.Now())
+			})
 			// Update message view with reversed order (newest first)
 			reversed := make([]messaging.Message, len(m.allMessages))
 			for i, message := range m.allMessages {
@@ -340,6 +510,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Keep scheduling refresh ticks even if not actively refreshing
 		return m, m.scheduleSessionRefresh()
 
+	case configWatchTickMsg:
+		// Defer reloads while an operation or modal is in progress
+		if m.isModalActive() {
+			return m, scheduleConfigWatch()
+		}
+		return m, tea.Batch(
+			checkConfig(m.configPath, m.cfg.DataDir, m.configModTime),
+			scheduleConfigWatch(),
+		)
+
+	case configReloadedMsg:
+		return m.handleConfigReloaded(msg)
+
 	case sessionsLoadedMsg:
 		if msg.err != nil {
 			m.err = msg.err
@@ -348,12 +531,28 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		// Store all sessions for filtering
 		m.allSessions = msg.sessions
+		// Onboarding only greets a first run
+		if len(msg.sessions) > 0 {
+			m.onboarding = nil
+		}
 		// Apply filter and update list
 		return m.applyFilter()
 
+	case remoteSessionsLoadedMsg:
+		for _, r := range msg.results {
+			if r.Err != nil {
+				log.Warn().Err(r.Err).Str("host", r.Host).Msg("failed to load remote sessions")
+			}
+		}
+		m.remoteSessions = msg.results
+		// Keep existing git statuses while the tree is rebuilt
+		m.refreshing = true
+		return m.applyFilter()
+
 	case gitStatusBatchCompleteMsg:
 		m.gitStatuses.SetBatch(msg.Results)
 		m.refreshing = false
+		m.updateColumnWidths()
 		return m, nil
 
 	case terminalPollTickMsg:
@@ -370,10 +569,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(cmds...)
 
 	case terminalStatusBatchCompleteMsg:
+		var record tea.Cmd
 		if m.terminalStatuses != nil {
+			record = m.recordStatusChanges(msg.Results)
 			m.terminalStatuses.SetBatch(msg.Results)
 		}
-		return m, nil
+		return m, record
 
 	case animationTickMsg:
 		// Advance animation frame
@@ -396,28 +597,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Reload sessions after action
 		return m, m.loadSessions()
 
-	case recycleStartedMsg:
-		m.state = stateRunningRecycle
-		m.outputModal = NewOutputModal("Recycling session...")
-		m.recycleOutput = msg.output
-		m.recycleDone = msg.done
-		m.recycleCancel = msg.cancel
-		return m, tea.Batch(
-			listenForRecycleOutput(msg.output, msg.done),
-			m.outputModal.Spinner().Tick,
-		)
+	case onboardingStepMsg:
+		if m.onboarding != nil {
+			m.onboarding.Finish(msg)
+		}
+		return m, nil
+
+	case messagePublishedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		return m, loadMessages(m.msgStore, m.topicFilter, m.lastPollTime)
 
-	case recycleOutputMsg:
-		m.outputModal.AddLine(msg.line)
+	case queuedActionOutputMsg:
+		m.actions.SetOutput(msg.id, msg.line)
 		// Keep listening for more output
-		return m, listenForRecycleOutput(m.recycleOutput, m.recycleDone)
-
-	case recycleCompleteMsg:
-		m.outputModal.SetComplete(msg.err)
-		m.recycleOutput = nil
-		m.recycleDone = nil
-		m.recycleCancel = nil
-		// Stay in stateRunningRecycle until user dismisses
+		return m, listenForActionOutput(msg.id, msg.output, msg.done)
+
+	case queuedActionDoneMsg:
+		job, ok := m.actions.Finish(msg.id, msg.err)
+		if !ok {
+			return m, nil
+		}
+		linger := actionDoneLinger
+		if msg.err != nil {
+			m.err = msg.err
+			linger = actionFailedLinger
+		}
+		expire := tea.Tick(linger, func(time.Time) tea.Msg { return queuedActionExpiredMsg{id: job.id} })
+		return m, tea.Batch(m.startQueuedActions(), m.loadSessions(), expire)
+
+	case queuedActionExpiredMsg:
+		m.actions.Remove(msg.id)
+		m.resizeContent()
 		return m, nil
 
 	case reposDiscoveredMsg:
@@ -434,6 +647,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					key.WithKeys("g"),
 					key.WithHelp("g", "refresh git"),
 				))
+				bindings = append(bindings, key.NewBinding(
+					key.WithKeys("b"),
+					key.WithHelp("b", "filter batch"),
+				))
 				bindings = append(bindings, key.NewBinding(
 					key.WithKeys("tab"),
 					key.WithHelp("tab", "switch view"),
@@ -456,6 +673,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.state == stateCreatingSession && m.newSessionForm != nil {
 		return m.updateNewSessionForm(msg)
 	}
+	if m.state == stateComposingMessage && m.composeForm != nil {
+		return m.updateComposeForm(msg)
+	}
+	if m.state == stateEditingSession && m.editForm != nil {
+		return m.updateEditForm(msg)
+	}
+	if m.state == stateFilteringBatch && m.batchForm != nil {
+		return m.updateBatchForm(msg)
+	}
 
 	// Update the focused list for any other messages (only session list needs this)
 	var cmd tea.Cmd
@@ -473,12 +699,18 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.state == stateCreatingSession {
 		return m.handleNewSessionFormKey(msg, keyStr)
 	}
+	if m.state == stateComposingMessage {
+		return m.handleComposeFormKey(msg, keyStr)
+	}
+	if m.state == stateEditingSession {
+		return m.handleEditFormKey(msg, keyStr)
+	}
+	if m.state == stateFilteringBatch {
+		return m.handleBatchFormKey(msg, keyStr)
+	}
 	if m.state == statePreviewingMessage {
 		return m.handlePreviewModalKey(msg, keyStr)
 	}
-	if m.state == stateRunningRecycle {
-		return m.handleRecycleModalKey(keyStr)
-	}
 	if m.state == stateConfirming {
 		return m.handleConfirmModalKey(keyStr)
 	}
@@ -532,30 +764,189 @@ func (m Model) updateNewSessionForm(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
-// handleRecycleModalKey handles keys when recycle modal is shown.
-func (m Model) handleRecycleModalKey(keyStr string) (tea.Model, tea.Cmd) {
+// handleComposeFormKey handles keys when the message composer is shown.
+func (m Model) handleComposeFormKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cmd) {
+	switch keyStr {
+	case keyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		m.state = stateNormal
+		m.composeForm = nil
+		return m, nil
+	}
+	return m.updateComposeForm(msg)
+}
+
+// updateComposeForm routes any message to the composer, advancing to the
+// payload editor after the topic step and publishing once it completes.
+func (m Model) updateComposeForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.composeForm.Form().Update(msg)
+	f, ok := form.(*huh.Form)
+	if !ok || f.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	if !m.composeForm.Editing() {
+		return m, m.composeForm.Edit()
+	}
+
+	message := m.composeForm.Result()
+	m.state = stateNormal
+	m.composeForm = nil
+	return m, m.publishMessage(message)
+}
+
+// handleEditFormKey handles keys when the session editor is shown.
+func (m Model) handleEditFormKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cmd) {
 	switch keyStr {
 	case keyCtrlC:
-		if m.recycleCancel != nil {
-			m.recycleCancel()
-		}
 		m.quitting = true
 		return m, tea.Quit
 	case "esc":
-		if m.outputModal.IsRunning() && m.recycleCancel != nil {
-			m.recycleCancel()
+		m.state = stateNormal
+		m.editForm = nil
+		return m, nil
+	}
+	return m.updateEditForm(msg)
+}
+
+// updateEditForm routes any message to the session editor and saves the
+// changes once it completes.
+func (m Model) updateEditForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.editForm.Form().Update(msg)
+	f, ok := form.(*huh.Form)
+	if !ok || f.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	result := m.editForm.Result()
+	m.state = stateNormal
+	m.editForm = nil
+	return m, m.saveSessionEdit(result)
+}
+
+// startEdit opens the name and note editor for a session.
+func (m Model) startEdit(sessionID string) (tea.Model, tea.Cmd) {
+	idx := slices.IndexFunc(m.allSessions, func(s session.Session) bool { return s.ID == sessionID })
+	if idx < 0 {
+		return m, nil
+	}
+
+	existingNames := make(map[string]bool, len(m.allSessions))
+	for _, s := range m.allSessions {
+		if s.State == session.StateActive {
+			existingNames[s.Name] = true
 		}
+	}
+	m.editForm = NewEditSessionForm(m.allSessions[idx], existingNames)
+	m.state = stateEditingSession
+	return m, m.editForm.Form().Init()
+}
+
+// saveSessionEdit saves the changed name and note of a session.
+func (m Model) saveSessionEdit(result EditSessionFormResult) tea.Cmd {
+	service := m.service
+	return func() tea.Msg {
+		ctx := context.Background()
+		if result.NameChanged {
+			if _, err := service.RenameSession(ctx, result.SessionID, result.Name); err != nil {
+				return actionCompleteMsg{err: err}
+			}
+		}
+		if result.NoteChanged {
+			if _, err := service.SetNote(ctx, result.SessionID, result.Note); err != nil {
+				return actionCompleteMsg{err: err}
+			}
+		}
+		return actionCompleteMsg{}
+	}
+}
+
+// handleBatchFormKey handles keys when the batch picker is shown.
+func (m Model) handleBatchFormKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cmd) {
+	switch keyStr {
+	case keyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
 		m.state = stateNormal
-		m.pending = Action{}
-		return m, m.loadSessions()
-	case keyEnter:
-		if !m.outputModal.IsRunning() {
-			m.state = stateNormal
-			m.pending = Action{}
-			return m, m.loadSessions()
+		m.batchForm = nil
+		return m, nil
+	}
+	return m.updateBatchForm(msg)
+}
+
+// updateBatchForm routes any message to the batch picker and filters the
+// tree to the chosen batch once it completes.
+func (m Model) updateBatchForm(msg tea.Msg) (tea.Model, tea.Cmd) {
+	form, cmd := m.batchForm.Form().Update(msg)
+	f, ok := form.(*huh.Form)
+	if !ok || f.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	m.batchFilter = m.batchForm.BatchID()
+	m.batchForm = nil
+	m.list.ResetSelected()
+	return m.applyFilter()
+}
+
+// queueBatchRecycle queues a recycle of every active session in the
+// action's batch. Sessions that already have an action pending are skipped.
+func (m Model) queueBatchRecycle(action Action) (tea.Model, tea.Cmd) {
+	m.pending = Action{}
+	for _, sess := range m.allSessions {
+		if sess.State != session.StateActive || sess.BatchID() != action.BatchID {
+			continue
 		}
+		m.actions.Add(Action{Type: ActionTypeRecycle, Help: "recycle", SessionID: sess.ID, SessionPath: sess.Path}, sess.Name)
 	}
-	return m, nil
+	m.resizeContent()
+	return m, m.startQueuedActions()
+}
+
+// publishMessage publishes a message composed in the TUI, relaying it to
+// remote hosts like hive msg pub does.
+func (m Model) publishMessage(msg messaging.Message) tea.Cmd {
+	store, service := m.msgStore, m.service
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		messaging.Stamp(&msg)
+		if err := store.Publish(ctx, msg); err != nil {
+			return messagePublishedMsg{err: fmt.Errorf("publish message: %w", err)}
+		}
+		service.EmitMessage(msg)
+		service.RelayMessage(ctx, msg)
+		return messagePublishedMsg{}
+	}
+}
+
+// messageTopics returns the known topic names for completion in the composer.
+func (m Model) messageTopics() []string {
+	seen := make(map[string]bool)
+	var topics []string
+	add := func(t string) {
+		if !seen[t] {
+			seen[t] = true
+			topics = append(topics, t)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if names, err := m.msgStore.List(ctx); err == nil {
+		for _, t := range names {
+			add(t)
+		}
+	}
+	for _, msg := range m.allMessages {
+		add(msg.Topic)
+	}
+	slices.Sort(topics)
+	return topics
 }
 
 // handleConfirmModalKey handles keys when confirmation modal is shown.
@@ -565,10 +956,17 @@ func (m Model) handleConfirmModalKey(keyStr string) (tea.Model, tea.Cmd) {
 		m.state = stateNormal
 		if m.modal.ConfirmSelected() {
 			action := m.pending
-			if action.Type == ActionTypeRecycle {
-				return m, m.startRecycle(action.SessionID)
+			if action.Type == ActionTypeOpen {
+				return m, m.openSession(action.SessionID)
 			}
-			return m, m.executeAction(action)
+			if action.Type == ActionTypeEdit {
+				m.pending = Action{}
+				return m.startEdit(action.SessionID)
+			}
+			if action.Type == ActionTypeRecycleBatch {
+				return m.queueBatchRecycle(action)
+			}
+			return m.queueAction(action)
 		}
 		m.pending = Action{}
 		return m, nil
@@ -616,15 +1014,15 @@ func (m Model) handlePreviewModalKey(msg tea.KeyMsg, keyStr string) (tea.Model,
 	}
 }
 
-// copyToClipboard copies the given text to the system clipboard.
+// copyToClipboard copies the given text to the system clipboard with the
+// copy command. Over SSH, where the copy command would reach the remote
+// machine's clipboard, or without a copy command, the text is sent to the
+// terminal as an OSC 52 escape sequence instead.
 func (m Model) copyToClipboard(text string) error {
-	if m.copyCommand == "" {
-		return nil
-	}
-
 	// Split the command into program and args
 	parts := strings.Fields(m.copyCommand)
-	if len(parts) == 0 {
+	if len(parts) == 0 || os.Getenv("SSH_TTY") != "" {
+		termenv.Copy(text)
 		return nil
 	}
 
@@ -668,6 +1066,11 @@ func (m Model) handleFilteringKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea
 
 // handleNormalKey handles keys in normal state.
 func (m Model) handleNormalKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cmd) {
+	// A leader key waits for the rest of its chord
+	if m.chordKeys != "" {
+		return m.handleChordKey(keyStr)
+	}
+
 	// Global keys that work regardless of focus
 	switch keyStr {
 	case "q", keyCtrlC:
@@ -679,6 +1082,12 @@ func (m Model) handleNormalKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cm
 
 	// Session-specific keys only when sessions focused
 	if m.isSessionsFocused() {
+		// Leader keys take over any single-key binding of the same key
+		if m.handler.IsLeader(keyStr) && m.selectedSession() != nil {
+			m.chordKeys = keyStr
+			m.resizeContent()
+			return m, nil
+		}
 		if keyStr == "g" {
 			return m, m.refreshGitStatuses()
 		}
@@ -700,6 +1109,12 @@ func (m Model) handleNormalKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cm
 		m.msgView.MoveDown()
 	case "/":
 		m.msgView.StartFilter()
+	case "n":
+		if m.msgStore != nil {
+			m.composeForm = NewMessageComposeForm(m.messageTopics(), m.cfg.Messaging.Snippets)
+			m.state = stateComposingMessage
+			return m, m.composeForm.Form().Init()
+		}
 	}
 	return m, nil
 }
@@ -716,6 +1131,10 @@ func (m Model) handleTabKey() (tea.Model, tea.Cmd) {
 
 // handleSessionsKey handles keys when sessions pane is focused.
 func (m Model) handleSessionsKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.Cmd) {
+	if m.showOnboarding() {
+		return m.handleOnboardingKey(keyStr)
+	}
+
 	// Handle 'n' for new session (only if repos are discovered)
 	if keyStr == "n" && len(m.discoveredRepos) > 0 {
 		// Determine preselected remote
@@ -733,6 +1152,23 @@ func (m Model) handleSessionsKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.
 		return m, m.newSessionForm.Form().Init()
 	}
 
+	// 'b' picks a batch to filter the tree to
+	if keyStr == "b" {
+		if batches := SessionBatches(m.allSessions); len(batches) > 0 || m.batchFilter != "" {
+			m.batchForm = NewBatchFilterForm(batches, m.batchFilter)
+			m.state = stateFilteringBatch
+			return m, m.batchForm.Form().Init()
+		}
+	}
+
+	// Enter or space on an owner header expands or collapses it
+	if keyStr == "enter" || keyStr == " " {
+		if item, ok := m.list.SelectedItem().(TreeItem); ok && item.IsOwnerHeader {
+			m.collapsedOwners[item.Owner] = !m.collapsedOwners[item.Owner]
+			return m.applyFilter()
+		}
+	}
+
 	selected := m.selectedSession()
 	if selected == nil {
 		var cmd tea.Cmd
@@ -740,36 +1176,149 @@ func (m Model) handleSessionsKey(msg tea.KeyMsg, keyStr string) (tea.Model, tea.
 		return m, cmd
 	}
 
-	action, ok := m.handler.Resolve(keyStr, *selected)
-	if ok {
-		if action.NeedsConfirm() {
-			m.state = stateConfirming
-			m.pending = action
-			m.modal = NewModal("Confirm", action.Confirm)
+	if action, ok := m.handler.Resolve(keyStr, *selected); ok {
+		return m.runAction(action)
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// hasChanges reports whether the action's session, or for batch actions any
+// active session of its batch, has uncommitted changes as of its last git
+// status. Sessions without a loaded status count as changed.
+func (m Model) hasChanges(action Action) bool {
+	paths := []string{action.SessionPath}
+	if action.Type == ActionTypeRecycleBatch {
+		paths = nil
+		for _, sess := range m.allSessions {
+			if sess.State == session.StateActive && sess.BatchID() == action.BatchID {
+				paths = append(paths, sess.Path)
+			}
+		}
+	}
+
+	for _, path := range paths {
+		status, ok := m.gitStatuses.Get(path)
+		if !ok || status.IsLoading || status.Error != nil || status.HasChanges {
+			return true
+		}
+	}
+	return false
+}
+
+// handleChordKey handles the key after the leader keys of a chord. Keys that
+// complete no chord are dropped, and esc cancels the chord.
+func (m Model) handleChordKey(keyStr string) (tea.Model, tea.Cmd) {
+	keys := m.chordKeys
+	m.chordKeys = ""
+	m.resizeContent()
+
+	switch keyStr {
+	case keyCtrlC:
+		m.quitting = true
+		return m, tea.Quit
+	case "esc":
+		return m, nil
+	}
+
+	keys += " " + keyStr
+	if m.handler.IsLeader(keys) {
+		m.chordKeys = keys
+		m.resizeContent()
+		return m, nil
+	}
+
+	selected := m.selectedSession()
+	if selected == nil {
+		return m, nil
+	}
+	if action, ok := m.handler.Resolve(keys, *selected); ok {
+		return m.runAction(action)
+	}
+	return m, nil
+}
+
+// runAction runs a resolved keybinding action, asking first when it needs
+// confirmation.
+func (m Model) runAction(action Action) (tea.Model, tea.Cmd) {
+	if action.NeedsConfirm() && (!action.DirtyOnly || m.hasChanges(action)) {
+		m.state = stateConfirming
+		m.pending = action
+		m.modal = NewModal("Confirm", action.Confirm)
+		return m, nil
+	}
+	if action.Type == ActionTypeOpen {
+		return m, m.openSession(action.SessionID)
+	}
+	if action.Type == ActionTypeEdit {
+		return m.startEdit(action.SessionID)
+	}
+	if action.Type == ActionTypeRecycleBatch {
+		return m.queueBatchRecycle(action)
+	}
+	// If exit is requested, execute synchronously and quit immediately
+	// This avoids async message flow issues in some terminal contexts (e.g., tmux popups)
+	if action.Exit {
+		_ = m.handler.Execute(context.Background(), action)
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m.queueAction(action)
+}
+
+// showOnboarding reports whether the first-run guide replaces the empty
+// session list.
+func (m Model) showOnboarding() bool {
+	return m.onboarding != nil && len(m.allSessions) == 0 && len(m.remoteSessions) == 0
+}
+
+// handleOnboardingKey handles keys on the first-run guide.
+func (m Model) handleOnboardingKey(keyStr string) (tea.Model, tea.Cmd) {
+	switch keyStr {
+	case "esc":
+		m.onboarding = nil
+		return m, nil
+	case "c":
+		path, term := m.onboarding.configPath, m.onboarding.terminal
+		return m, func() tea.Msg {
+			if err := config.WriteStarterConfig(path, term); err != nil {
+				return onboardingStepMsg{step: stepConfig, err: err}
+			}
+			return onboardingStepMsg{step: stepConfig, status: "Wrote " + path + ", hive reloads it automatically"}
+		}
+	case "i":
+		if m.onboarding.repo == "" {
 			return m, nil
 		}
-		if action.Type == ActionTypeRecycle {
-			return m, m.startRecycle(action.SessionID)
+		service := m.service
+		return m, func() tea.Msg {
+			ctxDir, err := service.RepoContextDir(context.Background(), ".")
+			if err != nil {
+				return onboardingStepMsg{step: stepContext, err: err}
+			}
+			link, err := service.InitContext(".", ctxDir)
+			if err != nil {
+				return onboardingStepMsg{step: stepContext, err: err}
+			}
+			return onboardingStepMsg{step: stepContext, status: "Linked " + link.Symlink + " -> " + link.Target}
 		}
-		// If exit is requested, execute synchronously and quit immediately
-		// This avoids async message flow issues in some terminal contexts (e.g., tmux popups)
-		if action.Exit {
-			_ = m.handler.Execute(context.Background(), action)
-			m.quitting = true
-			return m, tea.Quit
+	case "n":
+		if m.onboarding.repo == "" {
+			return m, nil
 		}
-		// Store pending action for exit check after completion
-		m.pending = action
-		if !action.Silent {
-			m.state = stateLoading
-			m.loadingMessage = "Processing..."
+		dir, err := os.Getwd()
+		if err != nil {
+			m.onboarding.Finish(onboardingStepMsg{err: err})
+			return m, nil
 		}
-		return m, m.executeAction(action)
+		repos := []DiscoveredRepo{{Path: dir, Name: m.onboarding.repo, Remote: m.localRemote}}
+		m.newSessionForm = NewNewSessionForm(repos, m.localRemote, nil)
+		m.state = stateCreatingSession
+		return m, m.newSessionForm.Form().Init()
 	}
-
-	var cmd tea.Cmd
-	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	return m, nil
 }
 
 // selectedSession returns the currently selected session, or nil if none.
@@ -780,8 +1329,8 @@ func (m Model) selectedSession() *session.Session {
 	}
 	// Handle TreeItem (tree view mode)
 	if treeItem, ok := item.(TreeItem); ok {
-		if treeItem.IsHeader {
-			return nil // Headers aren't sessions
+		if treeItem.IsHeader || treeItem.Host != "" {
+			return nil // Headers aren't sessions; remote sessions are read-only
 		}
 		return &treeItem.Session
 	}
@@ -813,57 +1362,136 @@ func (m Model) isModalActive() bool {
 	return m.state != stateNormal
 }
 
+// updateColumnWidths recalculates the per-repository column widths from the
+// tree items and the loaded git statuses.
+func (m Model) updateColumnWidths() {
+	statuses := make(map[string]GitStatus, m.gitStatuses.Len())
+	for _, path := range m.gitStatuses.Keys() {
+		if status, ok := m.gitStatuses.Get(path); ok {
+			statuses[path] = status
+		}
+	}
+	*m.columnWidths = CalculateRepoColumnWidths(m.list.Items(), statuses)
+}
+
 // applyFilter rebuilds the tree view from all sessions.
 func (m Model) applyFilter() (tea.Model, tea.Cmd) {
+	// Limit the tree to the chosen batch, dropping the filter once none of
+	// its sessions are left
+	sessions := m.allSessions
+	if m.batchFilter != "" {
+		sessions = slices.DeleteFunc(slices.Clone(sessions), func(s session.Session) bool {
+			return s.BatchID() != m.batchFilter
+		})
+		if len(sessions) == 0 {
+			m.batchFilter = ""
+			sessions = m.allSessions
+		}
+	}
+
 	// Group sessions by repository and build tree items
-	groups := GroupSessionsByRepo(m.allSessions, m.localRemote)
-	items := BuildTreeItems(groups, m.localRemote)
+	groups := GroupSessionsByRepo(sessions, m.localRemote)
+	var items []list.Item
+	if m.cfg.TUI.GroupBy == config.GroupByOwner {
+		items = BuildOwnerTreeItems(groups, m.localRemote, m.collapsedOwners)
+	} else {
+		items = BuildTreeItems(groups, m.localRemote)
+	}
+	// Remote sessions are not part of local batches
+	if m.batchFilter == "" {
+		for _, r := range m.remoteSessions {
+			items = append(items, BuildHostTreeItems(r.Host, r.Sessions, r.Err)...)
+		}
+	}
 
-	// Calculate column widths across all sessions
-	*m.columnWidths = CalculateColumnWidths(m.allSessions, nil)
+	m.list.SetItems(items)
+	m.updateColumnWidths()
+	m.state = stateNormal
 
-	// Collect paths for git status fetching
-	// During background refresh, keep existing statuses to avoid flashing
 	paths := make([]string, 0, len(m.allSessions))
-	for _, s := range m.allSessions {
-		paths = append(paths, s.Path)
+	for _, sess := range m.allSessions {
+		paths = append(paths, sess.Path)
+	}
+	m.gitCache.prune(paths)
+
+	// Statuses off the visible page are dropped and fetched when scrolled to.
+	// During background refresh, keep visible statuses to avoid flashing.
+	visible := m.pageSessionPaths()
+	m.dropGitStatusesExcept(visible)
+	for _, path := range visible {
 		if !m.refreshing {
-			m.gitStatuses.Set(s.Path, GitStatus{IsLoading: true})
+			m.gitStatuses.Set(path, GitStatus{IsLoading: true})
 		}
 	}
 
-	m.list.SetItems(items)
-	m.state = stateNormal
-
-	if len(paths) == 0 {
+	if len(visible) == 0 {
 		m.refreshing = false
 		return m, nil
 	}
+
 	// refreshing is cleared when gitStatusBatchCompleteMsg is received
-	return m, fetchGitStatusBatch(m.service.Git(), paths, m.gitWorkers)
+	return m, fetchGitStatusBatch(m.service.Git(), visible, m.gitWorkers, m.gitCache, false)
 }
 
-// refreshGitStatuses returns a command that refreshes git status for all sessions.
+// refreshGitStatuses returns a command that refreshes git status for the
+// sessions on the visible page, bypassing the cache. Other sessions are
+// fetched again when scrolled to.
 func (m Model) refreshGitStatuses() tea.Cmd {
-	items := m.list.Items()
-	paths := make([]string, 0, len(items))
+	paths := m.pageSessionPaths()
+	m.dropGitStatusesExcept(paths)
+	for _, path := range paths {
+		m.gitStatuses.Set(path, GitStatus{IsLoading: true})
+	}
 
-	for _, item := range items {
-		treeItem, ok := item.(TreeItem)
-		if !ok || treeItem.IsHeader {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	return fetchGitStatusBatch(m.service.Git(), paths, m.gitWorkers, m.gitCache, true)
+}
+
+// fetchPageGitStatus returns a command that fetches git status for sessions
+// on the visible page that have none yet, or nil if there are none.
+func (m Model) fetchPageGitStatus() tea.Cmd {
+	var missing []string
+	for _, path := range m.pageSessionPaths() {
+		if _, ok := m.gitStatuses.Get(path); ok {
 			continue
 		}
-		path := treeItem.Session.Path
-		paths = append(paths, path)
-		// Mark as loading
 		m.gitStatuses.Set(path, GitStatus{IsLoading: true})
+		missing = append(missing, path)
 	}
 
-	if len(paths) == 0 {
+	if len(missing) == 0 {
 		return nil
 	}
+	return fetchGitStatusBatch(m.service.Git(), missing, m.gitWorkers, m.gitCache, false)
+}
+
+// pageSessionPaths returns the paths of the local sessions on the visible
+// page of the list.
+func (m Model) pageSessionPaths() []string {
+	items := m.list.VisibleItems()
+	start, end := m.list.Paginator.GetSliceBounds(len(items))
+
+	var paths []string
+	for _, item := range items[start:end] {
+		treeItem, ok := item.(TreeItem)
+		if !ok || treeItem.IsHeader || treeItem.IsRecycledPlaceholder || treeItem.Host != "" {
+			continue
+		}
+		paths = append(paths, treeItem.Session.Path)
+	}
+	return paths
+}
 
-	return fetchGitStatusBatch(m.service.Git(), paths, m.gitWorkers)
+// dropGitStatusesExcept removes the git statuses of all paths not in keep.
+func (m Model) dropGitStatusesExcept(keep []string) {
+	for _, path := range m.gitStatuses.Keys() {
+		if !slices.Contains(keep, path) {
+			m.gitStatuses.Delete(path)
+		}
+	}
 }
 
 // View renders the TUI.
@@ -886,11 +1514,6 @@ func (m Model) View() string {
 		h = 24
 	}
 
-	// Overlay output modal if running recycle
-	if m.state == stateRunningRecycle {
-		return m.outputModal.Overlay(mainView, w, h)
-	}
-
 	// Overlay new session form (render directly without Modal's Confirm/Cancel buttons)
 	if m.state == stateCreatingSession && m.newSessionForm != nil {
 		formContent := lipgloss.JoinVertical(
@@ -903,18 +1526,47 @@ func (m Model) View() string {
 		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, formOverlay)
 	}
 
+	// Overlay message composer
+	if m.state == stateComposingMessage && m.composeForm != nil {
+		formContent := lipgloss.JoinVertical(
+			lipgloss.Left,
+			modalTitleStyle.Render("New Message"),
+			"",
+			m.composeForm.View(),
+		)
+		formOverlay := modalStyle.Render(formContent)
+		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, formOverlay)
+	}
+
+	// Overlay session editor
+	if m.state == stateEditingSession && m.editForm != nil {
+		formContent := lipgloss.JoinVertical(
+			lipgloss.Left,
+			modalTitleStyle.Render("Edit Session"),
+			"",
+			m.editForm.View(),
+		)
+		formOverlay := modalStyle.Render(formContent)
+		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, formOverlay)
+	}
+
+	// Overlay batch picker
+	if m.state == stateFilteringBatch && m.batchForm != nil {
+		formContent := lipgloss.JoinVertical(
+			lipgloss.Left,
+			modalTitleStyle.Render("Filter by Batch"),
+			"",
+			m.batchForm.View(),
+		)
+		formOverlay := modalStyle.Render(formContent)
+		return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, formOverlay)
+	}
+
 	// Overlay message preview modal
 	if m.state == statePreviewingMessage {
 		return m.previewModal.Overlay(mainView, w, h)
 	}
 
-	// Overlay loading spinner if loading
-	if m.state == stateLoading {
-		loadingView := lipgloss.JoinHorizontal(lipgloss.Left, m.spinner.View(), " "+m.loadingMessage)
-		modal := NewModal("", loadingView)
-		return modal.Overlay(mainView, w, h)
-	}
-
 	// Overlay modal if confirming
 	if m.state == stateConfirming {
 		return m.modal.Overlay(mainView, w, h)
@@ -926,26 +1578,26 @@ func (m Model) View() string {
 // renderTabView renders the tab-based view layout.
 func (m Model) renderTabView() string {
 	// Build tab bar
+	sessionsTitle := "Sessions"
+	if m.batchFilter != "" {
+		sessionsTitle += " (batch " + m.batchFilter + ")"
+	}
 	var sessionsTab, messagesTab string
 	if m.activeView == ViewSessions {
-		sessionsTab = viewSelectedStyle.Render("Sessions")
+		sessionsTab = viewSelectedStyle.Render(sessionsTitle)
 		messagesTab = viewNormalStyle.Render("Messages")
 	} else {
-		sessionsTab = viewNormalStyle.Render("Sessions")
+		sessionsTab = viewNormalStyle.Render(sessionsTitle)
 		messagesTab = viewSelectedStyle.Render("Messages")
 	}
 	tabBarContent := lipgloss.JoinHorizontal(lipgloss.Left, sessionsTab, " | ", messagesTab)
 	tabBar := lipgloss.NewStyle().PaddingLeft(1).Render(tabBarContent)
 
-	// Calculate content height: total - banner (5) - tab bar (1)
-	contentHeight := m.height - 6
-	if contentHeight < 1 {
-		contentHeight = 1
-	}
-
 	// Build content with fixed height to prevent layout shift
 	var content string
-	if m.activeView == ViewSessions {
+	if m.activeView == ViewSessions && m.showOnboarding() {
+		content = m.onboarding.View()
+	} else if m.activeView == ViewSessions {
 		content = m.list.View()
 	} else {
 		// Add blank line to match list's internal titleView padding
@@ -953,51 +1605,48 @@ func (m Model) renderTabView() string {
 	}
 
 	// Ensure consistent height
-	content = lipgloss.NewStyle().Height(contentHeight).Render(content)
+	content = lipgloss.NewStyle().Height(m.contentHeight()).Render(content)
+
+	// The chord hint pops up below the list while a leader key waits
+	if hint := m.chordHintView(); hint != "" {
+		content = lipgloss.JoinVertical(lipgloss.Left, content, hint)
+	}
 
+	// The action progress list sits below the content in both views
+	if queue := m.actions.View(m.spinner.View()); queue != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, tabBar, content, queue)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, tabBar, content)
 }
 
-// startRecycle returns a command that starts the recycle operation with streaming output.
-func (m Model) startRecycle(sessionID string) tea.Cmd {
-	return func() tea.Msg {
-		output := make(chan string, 100)
-		done := make(chan error, 1)
-
-		ctx, cancel := context.WithCancel(context.Background())
-
-		go func() {
-			defer close(output)
-			defer close(done)
-
-			writer := &channelWriter{ch: output, ctx: ctx}
-			err := m.service.RecycleSession(ctx, sessionID, writer)
-			done <- err
-		}()
-
-		return recycleStartedMsg{
-			output: output,
-			done:   done,
-			cancel: cancel,
-		}
+// contentHeight returns the height left for the active view: the total less
+// the banner (5), the tab bar (1), the chord hint, and the action progress
+// list.
+func (m Model) contentHeight() int {
+	hint := 0
+	if view := m.chordHintView(); view != "" {
+		hint = lipgloss.Height(view)
 	}
+	return max(m.height-6-hint-m.actions.Height(), 1)
 }
 
-// listenForRecycleOutput returns a command that waits for the next output or completion.
-func listenForRecycleOutput(output <-chan string, done <-chan error) tea.Cmd {
-	return func() tea.Msg {
-		select {
-		case line, ok := <-output:
-			if !ok {
-				// Output channel closed, wait for done
-				err := <-done
-				return recycleCompleteMsg{err: err}
-			}
-			return recycleOutputMsg{line: line}
-		case err := <-done:
-			return recycleCompleteMsg{err: err}
-		}
+// chordHintView renders the keys that may follow the pending leader keys, or
+// nothing when no chord is pending.
+func (m Model) chordHintView() string {
+	if m.chordKeys == "" {
+		return ""
 	}
+	return renderChordHint(m.chordKeys, m.handler.ChordEntries(m.chordKeys), m.width)
+}
+
+// resizeContent sizes the session list and the message view to the space
+// left for them.
+func (m *Model) resizeContent() {
+	// Spacing line is included in list's titleView and msgView's prefix
+	contentHeight := m.contentHeight()
+	m.list.SetSize(m.width, contentHeight)
+	// msgView gets -1 because we prepend a blank line for consistent spacing
+	m.msgView.SetSize(m.width, contentHeight-1)
 }
 
 // channelWriter is an io.Writer that sends writes to a channel.
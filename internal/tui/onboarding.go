@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+)
+
+// Onboarding is the guided empty state shown on a first run, when there are
+// no sessions and no config file. It offers to write a starter config, link
+// the working directory's context directory, and create a first session.
+type Onboarding struct {
+	configPath  string
+	terminal    config.Terminal // zero when the terminal is not recognized
+	repo        string          // owner/repo of the working directory, empty outside a repository
+	configDone  bool
+	contextDone bool
+	status      string // outcome of the last step
+	err         error  // error of the last step
+}
+
+// onboardingStepMsg is sent when an onboarding step finishes.
+type onboardingStepMsg struct {
+	step   onboardingStep
+	status string
+	err    error
+}
+
+// onboardingStep identifies a step of the onboarding screen.
+type onboardingStep int
+
+const (
+	stepConfig onboardingStep = iota
+	stepContext
+)
+
+// NewOnboarding creates the onboarding screen for a config file that does
+// not exist yet at configPath. localRemote is the origin of the working
+// directory, empty outside a repository.
+func NewOnboarding(configPath, localRemote string) *Onboarding {
+	term, _ := config.DetectTerminal(os.Getenv)
+	o := &Onboarding{configPath: configPath, terminal: term}
+	if owner, repo := git.ExtractOwnerRepo(localRemote); owner != "" && repo != "" {
+		o.repo = owner + "/" + repo
+	}
+	return o
+}
+
+// Finish records the outcome of a step.
+func (o *Onboarding) Finish(msg onboardingStepMsg) {
+	o.status, o.err = msg.status, msg.err
+	if msg.err != nil {
+		return
+	}
+	switch msg.step {
+	case stepConfig:
+		o.configDone = true
+	case stepContext:
+		o.contextDone = true
+	}
+}
+
+// View renders the onboarding screen.
+func (o *Onboarding) View() string {
+	var b strings.Builder
+	b.WriteString(onboardingTitleStyle.Render("Welcome to hive") + "\n\n")
+	b.WriteString("There are no sessions and no config file yet. A few steps to get started:\n\n")
+
+	spawn := "edit commands.spawn to open a terminal in new sessions"
+	if o.terminal.Name != "" {
+		spawn = "spawning sessions in " + o.terminal.Name
+	}
+	b.WriteString(onboardingStepLine("c", o.configDone, "Write a starter config to "+o.configPath, spawn))
+
+	if o.repo != "" {
+		b.WriteString(onboardingStepLine("i", o.contextDone, "Link the context directory of "+o.repo, "same as hive ctx init"))
+		b.WriteString(onboardingStepLine("n", false, "Create the first session in "+o.repo, ""))
+	} else {
+		b.WriteString(onboardingHintStyle.Render("    Start hive inside a git repository to link its context and create a session there.") + "\n")
+	}
+
+	switch {
+	case o.err != nil:
+		b.WriteString("\n" + onboardingErrorStyle.Render(o.err.Error()) + "\n")
+	case o.status != "":
+		b.WriteString("\n" + onboardingDoneStyle.Render(o.status) + "\n")
+	}
+
+	b.WriteString("\n" + onboardingHintStyle.Render("esc to skip"))
+	return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
+
+// onboardingStepLine renders one step with its key, or as done.
+func onboardingStepLine(key string, done bool, title, detail string) string {
+	mark := onboardingKeyStyle.Render(fmt.Sprintf("[%s]", key))
+	if done {
+		mark = onboardingDoneStyle.Render("[x]")
+		title = onboardingHintStyle.Render(title)
+	}
+	line := "  " + mark + " " + title + "\n"
+	if detail != "" {
+		line += onboardingHintStyle.Render("      "+detail) + "\n"
+	}
+	return line
+}
+
+// Onboarding styles.
+var (
+	onboardingTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(colorBlue)
+	onboardingKeyStyle   = lipgloss.NewStyle().Bold(true).Foreground(colorYellow)
+	onboardingDoneStyle  = lipgloss.NewStyle().Foreground(colorGreen)
+	onboardingHintStyle  = lipgloss.NewStyle().Foreground(colorGray)
+	onboardingErrorStyle = lipgloss.NewStyle().Foreground(colorRed)
+)
@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnboarding_Finish(t *testing.T) {
+	o := NewOnboarding("/tmp/hive/config.yaml", "git@github.com:hay-kot/hive.git")
+	assert.Equal(t, "hay-kot/hive", o.repo)
+
+	o.Finish(onboardingStepMsg{step: stepContext, err: errors.New("boom")})
+	assert.False(t, o.contextDone)
+	assert.Contains(t, o.View(), "boom")
+
+	o.Finish(onboardingStepMsg{step: stepConfig, status: "Wrote config"})
+	assert.True(t, o.configDone)
+	assert.Contains(t, o.View(), "[x]")
+	assert.NotContains(t, o.View(), "boom")
+}
+
+func TestOnboarding_ViewOutsideRepo(t *testing.T) {
+	o := NewOnboarding("/tmp/hive/config.yaml", "")
+	view := o.View()
+	assert.Contains(t, view, "[c]")
+	assert.NotContains(t, view, "[n]")
+}
@@ -79,10 +79,14 @@ func extractGroupName(remote string) string {
 	return git.ExtractRepoName(remote)
 }
 
-// sortSessions sorts sessions alphabetically by name.
+// sortSessions sorts pinned sessions first, then alphabetically by name.
 // Note: Recycled sessions are now separated and counted, not included in this slice.
 func sortSessions(sessions []session.Session) {
 	sort.Slice(sessions, func(i, j int) bool {
+		iPinned, jPinned := sessions[i].IsPinned(), sessions[j].IsPinned()
+		if iPinned != jPinned {
+			return iPinned
+		}
 		return sessions[i].Name < sessions[j].Name
 	})
 }
@@ -102,3 +106,68 @@ func sortRepoGroups(groups []RepoGroup, localRemote string) {
 		return groups[i].Name < groups[j].Name
 	})
 }
+
+// OwnerGroup is a repository owner (a user or organization) with its
+// repository groups.
+type OwnerGroup struct {
+	Name  string      // Owner from the remote, or "(no owner)"
+	Repos []RepoGroup // In the order given to GroupReposByOwner
+}
+
+// SessionCount returns the number of active and recycled sessions across the
+// owner's repositories.
+func (g OwnerGroup) SessionCount() int {
+	n := 0
+	for _, r := range g.Repos {
+		n += len(r.Sessions) + r.RecycledCount
+	}
+	return n
+}
+
+// GroupReposByOwner nests repository groups under their owner. Owners are
+// sorted with the owner of the current repository (matching localRemote)
+// first, then alphabetically; repositories keep their relative order.
+func GroupReposByOwner(groups []RepoGroup, localRemote string) []OwnerGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var owners []OwnerGroup
+	index := make(map[string]int)
+	for _, g := range groups {
+		name := extractOwnerName(g.Remote)
+		i, ok := index[name]
+		if !ok {
+			i = len(owners)
+			index[name] = i
+			owners = append(owners, OwnerGroup{Name: name})
+		}
+		owners[i].Repos = append(owners[i].Repos, g)
+	}
+
+	localOwner := ""
+	if localRemote != "" {
+		localOwner = extractOwnerName(localRemote)
+	}
+	sort.SliceStable(owners, func(i, j int) bool {
+		iLocal := owners[i].Name == localOwner
+		jLocal := owners[j].Name == localOwner
+		if iLocal != jLocal {
+			return iLocal
+		}
+		return owners[i].Name < owners[j].Name
+	})
+
+	return owners
+}
+
+// extractOwnerName returns the display name for a repository's owner.
+func extractOwnerName(remote string) string {
+	if remote == "" || remote == "(no remote)" {
+		return "(no owner)"
+	}
+	if owner, _ := git.ExtractOwnerRepo(remote); owner != "" {
+		return owner
+	}
+	return "(no owner)"
+}
@@ -172,6 +172,25 @@ func TestGroupSessionsByRepo_RecycledCount(t *testing.T) {
 	}
 }
 
+func TestGroupSessionsByRepo_PinnedFirst(t *testing.T) {
+	pinned := map[string]string{session.MetaPinned: "true"}
+	sessions := []session.Session{
+		{Name: "alpha", Remote: "git@github.com:user/repo.git", State: session.StateActive},
+		{Name: "zulu", Remote: "git@github.com:user/repo.git", State: session.StateActive, Metadata: pinned},
+		{Name: "bravo", Remote: "git@github.com:user/repo.git", State: session.StateActive},
+		{Name: "mike", Remote: "git@github.com:user/repo.git", State: session.StateActive, Metadata: pinned},
+	}
+
+	groups := GroupSessionsByRepo(sessions, "")
+	require.Len(t, groups, 1)
+
+	names := make([]string, len(groups[0].Sessions))
+	for i, s := range groups[0].Sessions {
+		names[i] = s.Name
+	}
+	assert.Equal(t, []string{"mike", "zulu", "alpha", "bravo"}, names)
+}
+
 func TestExtractGroupName(t *testing.T) {
 	tests := []struct {
 		remote string
@@ -190,3 +209,29 @@ func TestExtractGroupName(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupReposByOwner(t *testing.T) {
+	sessions := []session.Session{
+		{Name: "a", Remote: "git@github.com:zeta/api.git", State: session.StateActive},
+		{Name: "b", Remote: "git@github.com:acme/web.git", State: session.StateActive},
+		{Name: "c", Remote: "git@github.com:acme/cli.git", State: session.StateRecycled},
+		{Name: "d", Remote: "git@github.com:hay-kot/hive.git", State: session.StateActive},
+		{Name: "e", Remote: "", State: session.StateActive},
+	}
+	local := "git@github.com:hay-kot/hive.git"
+
+	owners := GroupReposByOwner(GroupSessionsByRepo(sessions, local), local)
+	require.Len(t, owners, 4)
+
+	names := make([]string, len(owners))
+	for i, o := range owners {
+		names[i] = o.Name
+	}
+	assert.Equal(t, []string{"hay-kot", "(no owner)", "acme", "zeta"}, names)
+
+	acme := owners[2]
+	require.Len(t, acme.Repos, 2)
+	assert.Equal(t, "cli", acme.Repos[0].Name)
+	assert.Equal(t, "web", acme.Repos[1].Name)
+	assert.Equal(t, 2, acme.SessionCount())
+}
@@ -7,6 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/events"
 	"github.com/hay-kot/hive/internal/integration/terminal"
 )
 
@@ -16,6 +17,7 @@ const terminalStatusTimeout = 2 * time.Second
 type TerminalStatus struct {
 	Status    terminal.Status
 	Tool      string
+	Activity  string // last meaningful line of terminal output
 	IsLoading bool
 	Error     error
 }
@@ -25,6 +27,33 @@ type terminalStatusBatchCompleteMsg struct {
 	Results map[string]TerminalStatus // sessionID -> status
 }
 
+// recordStatusChanges returns a command appending a session.status event for
+// each session whose terminal status differs from the last poll, or nil if
+// none changed. The first status seen for a session is not a change.
+func (m Model) recordStatusChanges(results map[string]TerminalStatus) tea.Cmd {
+	type change struct{ id, from, to string }
+
+	var changes []change
+	for id, next := range results {
+		prev, ok := m.terminalStatuses.Get(id)
+		if !ok || prev.IsLoading || prev.Error != nil || next.Error != nil || prev.Status == next.Status {
+			continue
+		}
+		changes = append(changes, change{id, string(prev.Status), string(next.Status)})
+	}
+	if len(changes) == 0 || m.service == nil {
+		return nil
+	}
+
+	svc := m.service
+	return func() tea.Msg {
+		for _, c := range changes {
+			svc.Emit(events.SessionStatus, c.id, map[string]any{"from": c.from, "to": c.to})
+		}
+		return nil
+	}
+}
+
 // terminalPollTickMsg triggers a terminal status poll cycle.
 type terminalPollTickMsg struct{}
 
@@ -73,6 +102,17 @@ func fetchTerminalStatusBatch(mgr *terminal.Manager, sessions []*session.Session
 	}
 }
 
+// FetchTerminalStatuses fetches the terminal status of each active session,
+// keyed by session ID, outside of a running TUI. Returns nil when mgr has no
+// enabled integrations.
+func FetchTerminalStatuses(mgr *terminal.Manager, sessions []*session.Session, workers int) map[string]TerminalStatus {
+	cmd := fetchTerminalStatusBatch(mgr, sessions, workers)
+	if cmd == nil {
+		return nil
+	}
+	return cmd().(terminalStatusBatchCompleteMsg).Results
+}
+
 // fetchTerminalStatusForSession fetches terminal status for a single session.
 func fetchTerminalStatusForSession(ctx context.Context, mgr *terminal.Manager, sess *session.Session) TerminalStatus {
 	status := TerminalStatus{
@@ -99,6 +139,7 @@ func fetchTerminalStatusForSession(ctx context.Context, mgr *terminal.Manager, s
 
 	status.Status = termStatus
 	status.Tool = info.DetectedTool
+	status.Activity = info.Activity
 	return status
 }
 
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// RenderTreeText writes the session tree as plain text, laid out as the TUI
+// shows it: a header per owner when grouped by owner, a header per repository, then each session's tree line with its
+// status indicator, name, short ID, and git status. gitStatuses is keyed by
+// session path and termStatuses by session ID; either may be nil, in which
+// case that part is left out.
+func RenderTreeText(w io.Writer, items []list.Item, gitStatuses map[string]GitStatus, termStatuses map[string]TerminalStatus, icons Icons) error {
+	widths := CalculateRepoColumnWidths(items, gitStatuses)
+
+	for i, item := range items {
+		ti, ok := item.(TreeItem)
+		if !ok {
+			continue
+		}
+
+		var line string
+		switch {
+		case ti.IsOwnerHeader:
+			if i > 0 {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			line = ownerHeaderText(ti, icons)
+		case ti.IsHeader:
+			// Blank line between repositories
+			if i > 0 && !isOwnerHeader(items[i-1]) {
+				if _, err := fmt.Fprintln(w); err != nil {
+					return err
+				}
+			}
+			line = ti.RepoName
+			if ti.IsCurrentRepo {
+				line += " " + icons.CurrentRepo
+			}
+		case ti.IsRecycledPlaceholder:
+			line = fmt.Sprintf("%s %s Recycled (%d)", icons.TreePrefix(ti), icons.Recycled, ti.RecycledCount)
+		default:
+			line = sessionLineText(ti, widths.For(ti), gitStatuses, termStatuses, icons)
+		}
+
+		if _, err := fmt.Fprintln(w, strings.Repeat("  ", ti.Indent)+line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isOwnerHeader reports whether item is an owner header.
+func isOwnerHeader(item list.Item) bool {
+	ti, ok := item.(TreeItem)
+	return ok && ti.IsOwnerHeader
+}
+
+// sessionLineText renders a session's tree line without styling.
+func sessionLineText(item TreeItem, widths ColumnWidths, gitStatuses map[string]GitStatus, termStatuses map[string]TerminalStatus, icons Icons) string {
+	var termStatus *TerminalStatus
+	if ts, ok := termStatuses[item.Session.ID]; ok && item.Host == "" {
+		termStatus = &ts
+	}
+
+	shortID := item.Session.ID
+	if len(shortID) > 4 {
+		shortID = shortID[len(shortID)-4:]
+	}
+
+	line := fmt.Sprintf("%s %s %s #%s",
+		icons.TreePrefix(item), icons.Status(sessionStatusOf(item.Session.State, termStatus)), PadRight(item.Session.Name, widths.Name), PadRight(shortID, widths.ID))
+
+	// Format: (branch) +N -N • clean/dirty
+	if status, ok := gitStatuses[item.Session.Path]; ok && item.Host == "" && status.Error == nil {
+		indicator := "clean"
+		if status.HasChanges {
+			indicator = "uncommitted"
+		}
+		line += fmt.Sprintf(" %s %s %s • %s",
+			PadRight("("+status.Branch+")", widths.Branch+2),
+			PadRight(fmt.Sprintf("+%d", status.Additions), widths.Additions),
+			PadRight(fmt.Sprintf("-%d", status.Deletions), widths.Deletions),
+			indicator)
+	}
+	line = strings.TrimRight(line, " ")
+	if item.Session.IsPinned() {
+		line += " " + icons.Pinned
+	}
+	if batch := item.Session.BatchID(); batch != "" {
+		line += " batch:" + batch
+	}
+	if note := item.Session.Note(); note != "" {
+		line += " " + note
+	}
+	return line
+}
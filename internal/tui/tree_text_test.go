@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hay-kot/hive/internal/core/session"
+	"github.com/hay-kot/hive/internal/integration/terminal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTreeText(t *testing.T) {
+	remote := "git@github.com:user/hive.git"
+	sessions := []session.Session{
+		{ID: "sess-ab12", Name: "fix-auth", Remote: remote, Path: "/s/fix-auth", State: session.StateActive},
+		{ID: "sess-cd34", Name: "docs", Remote: remote, Path: "/s/docs", State: session.StateActive},
+		{ID: "sess-ij90", Name: "zz-pinned", Remote: remote, Path: "/s/zz", State: session.StateActive, Metadata: map[string]string{session.MetaPinned: "true", session.MetaNote: "ship first"}},
+		{ID: "sess-ef56", Name: "old", Remote: remote, Path: "/s/old", State: session.StateRecycled},
+		{ID: "sess-gh78", Name: "api", Remote: "git@github.com:user/other.git", Path: "/s/api", State: session.StateActive, Metadata: map[string]string{session.MetaBatchID: "k3x9qa"}},
+	}
+	gitStatuses := map[string]GitStatus{
+		"/s/fix-auth": {Branch: "fix-auth", Additions: 3, Deletions: 1, HasChanges: true},
+		"/s/docs":     {Branch: "main"},
+	}
+	termStatuses := map[string]TerminalStatus{
+		"sess-ab12": {Status: terminal.StatusApproval},
+		"sess-cd34": {Status: terminal.StatusReady},
+	}
+
+	items := BuildTreeItems(GroupSessionsByRepo(sessions, remote), remote)
+
+	var b strings.Builder
+	require.NoError(t, RenderTreeText(&b, items, gitStatuses, termStatuses, DefaultIcons()))
+
+	want := strings.Join([]string{
+		"hive ◆",
+		"├─ [?] zz-pinned #ij90 ★ ship first",
+		"├─ [>] docs      #cd34 (main)     +0 -0 • clean",
+		"├─ [!] fix-auth  #ab12 (fix-auth) +3 -1 • uncommitted",
+		"└─ [○] Recycled (1)",
+		"",
+		"other",
+		"└─ [?] api #gh78 batch:k3x9qa",
+		"",
+	}, "\n")
+	assert.Equal(t, want, b.String())
+}
@@ -13,19 +13,17 @@ import (
 	"github.com/hay-kot/hive/pkg/kv"
 )
 
-// Tree characters for rendering the session tree.
-const (
-	treeBranch = "├─"
-	treeLast   = "└─"
-)
+// sessionStatus is the status shown for a session in the tree. Its glyph
+// comes from Icons.
+type sessionStatus int
 
-// Status indicators for sessions.
+// Session statuses.
 const (
-	statusActive   = "[●]" // green - agent actively working
-	statusApproval = "[!]" // yellow - needs approval/permission
-	statusReady    = "[>]" // cyan - ready for next input
-	statusUnknown  = "[?]" // dim - no terminal found
-	statusRecycled = "[○]" // gray - session recycled
+	statusActive   sessionStatus = iota // green - agent actively working
+	statusApproval                      // yellow - needs approval/permission
+	statusReady                         // cyan - ready for next input
+	statusUnknown                       // dim - no terminal found
+	statusRecycled                      // gray - session recycled
 )
 
 // Animation constants.
@@ -51,50 +49,65 @@ var activeAnimationColors = []lipgloss.Color{
 	lipgloss.Color("#9ece6a"), // bright green (frame 11)
 }
 
-// Star indicator for current repository.
-const currentRepoIndicator = "◆"
-
 // renderStatusIndicator returns the styled status indicator for a session.
 // For active sessions with terminal integration, it uses terminal status.
 // For recycled sessions or when no terminal status is available, it falls back to session state.
 // The animFrame parameter controls the fade animation for active status (0 to AnimationFrameCount-1).
-func renderStatusIndicator(state session.State, termStatus *TerminalStatus, styles TreeDelegateStyles, animFrame int) string {
+func renderStatusIndicator(state session.State, termStatus *TerminalStatus, styles TreeDelegateStyles, icons Icons, animFrame int) string {
+	status := sessionStatusOf(state, termStatus)
+	indicator := icons.Status(status)
+	switch status {
+	case statusActive:
+		return renderActiveIndicator(indicator, animFrame)
+	case statusApproval:
+		return styles.StatusApproval.Render(indicator)
+	case statusReady:
+		return styles.StatusReady.Render(indicator)
+	case statusUnknown:
+		return styles.StatusUnknown.Render(indicator)
+	default:
+		return styles.StatusRecycled.Render(indicator)
+	}
+}
+
+// sessionStatusOf returns the status to show for a session.
+func sessionStatusOf(state session.State, termStatus *TerminalStatus) sessionStatus {
 	// Recycled sessions always show recycled indicator
 	if state == session.StateRecycled {
-		return styles.StatusRecycled.Render(statusRecycled)
+		return statusRecycled
 	}
 
 	// If we have terminal status for active sessions, use it
 	if state == session.StateActive && termStatus != nil {
 		switch termStatus.Status {
 		case terminal.StatusActive:
-			return renderActiveIndicator(animFrame)
+			return statusActive
 		case terminal.StatusApproval:
-			return styles.StatusApproval.Render(statusApproval)
+			return statusApproval
 		case terminal.StatusReady:
-			return styles.StatusReady.Render(statusReady)
+			return statusReady
 		case terminal.StatusMissing:
-			return styles.StatusUnknown.Render(statusUnknown)
+			return statusUnknown
 		}
 	}
 
 	// Default: active session without terminal status shows as unknown
 	// We only show active (green) when we have positive confirmation of activity
 	if state == session.StateActive {
-		return styles.StatusUnknown.Render(statusUnknown)
+		return statusUnknown
 	}
 
-	return styles.StatusRecycled.Render(statusRecycled)
+	return statusRecycled
 }
 
-// renderActiveIndicator renders the active status with fade animation.
-func renderActiveIndicator(frame int) string {
+// renderActiveIndicator renders the active status glyph with fade animation.
+func renderActiveIndicator(glyph string, frame int) string {
 	// Ensure frame is in bounds
 	if frame < 0 || frame >= len(activeAnimationColors) {
 		frame = 0
 	}
 	style := lipgloss.NewStyle().Foreground(activeAnimationColors[frame])
-	return style.Render(statusActive)
+	return style.Render(glyph)
 }
 
 // TreeItem represents an item in the tree view.
@@ -115,6 +128,21 @@ type TreeItem struct {
 	// Recycled placeholder fields (only used when IsRecycledPlaceholder is true)
 	IsRecycledPlaceholder bool
 	RecycledCount         int
+
+	// Host is the remote host the item belongs to, empty for local sessions.
+	// Remote sessions are shown but cannot be acted on.
+	Host string
+
+	// Owner header fields (only used when IsOwnerHeader is true). Owner
+	// headers are also headers, so IsHeader is set with them.
+	IsOwnerHeader bool
+	Owner         string
+	RepoCount     int
+	SessionCount  int
+	Collapsed     bool
+
+	// Indent is the nesting depth of the item, 1 under an owner header.
+	Indent int
 }
 
 // FilterValue returns the value used for filtering.
@@ -178,6 +206,80 @@ func BuildTreeItems(groups []RepoGroup, localRemote string) []list.Item {
 	return items
 }
 
+// BuildOwnerTreeItems converts repo groups into tree items nested under an
+// owner header per repository owner. Owners named in collapsed show only
+// their header.
+func BuildOwnerTreeItems(groups []RepoGroup, localRemote string, collapsed map[string]bool) []list.Item {
+	owners := GroupReposByOwner(groups, localRemote)
+	if len(owners) == 0 {
+		return nil
+	}
+
+	items := make([]list.Item, 0)
+
+	for _, owner := range owners {
+		isCollapsed := collapsed[owner.Name]
+		items = append(items, TreeItem{
+			IsHeader:      true,
+			IsOwnerHeader: true,
+			Owner:         owner.Name,
+			RepoCount:     len(owner.Repos),
+			SessionCount:  owner.SessionCount(),
+			Collapsed:     isCollapsed,
+		})
+		if isCollapsed {
+			continue
+		}
+
+		for _, item := range BuildTreeItems(owner.Repos, localRemote) {
+			treeItem := item.(TreeItem)
+			treeItem.Indent = 1
+			items = append(items, treeItem)
+		}
+	}
+
+	return items
+}
+
+// ownerHeaderText returns the unstyled label of an owner header, e.g.
+// "▾ hay-kot (2 repos, 5 sessions)".
+func ownerHeaderText(item TreeItem, icons Icons) string {
+	indicator := icons.Expanded
+	if item.Collapsed {
+		indicator = icons.Collapsed
+	}
+	return fmt.Sprintf("%s %s (%s, %s)", indicator, item.Owner,
+		countLabel(item.RepoCount, "repo"), countLabel(item.SessionCount, "session"))
+}
+
+// countLabel formats n with noun, adding an "s" unless n is 1.
+func countLabel(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// BuildHostTreeItems converts the sessions on a remote host into tree items,
+// with each repository header suffixed by "@host". If the host could not be
+// reached, a single header says so.
+func BuildHostTreeItems(host string, sessions []session.Session, err error) []list.Item {
+	if err != nil {
+		return []list.Item{TreeItem{IsHeader: true, RepoName: "@" + host + " (unreachable)", Host: host}}
+	}
+
+	items := BuildTreeItems(GroupSessionsByRepo(sessions, ""), "")
+	for i, item := range items {
+		treeItem := item.(TreeItem)
+		treeItem.Host = host
+		if treeItem.IsHeader {
+			treeItem.RepoName += " @" + host
+		}
+		items[i] = treeItem
+	}
+	return items
+}
+
 // TreeDelegateStyles defines the styles for the tree delegate.
 type TreeDelegateStyles struct {
 	// Header styles
@@ -190,6 +292,9 @@ type TreeDelegateStyles struct {
 	SessionName    lipgloss.Style
 	SessionBranch  lipgloss.Style
 	SessionID      lipgloss.Style
+	SessionNote    lipgloss.Style
+	SessionBatch   lipgloss.Style
+	Activity       lipgloss.Style
 	StatusActive   lipgloss.Style
 	StatusApproval lipgloss.Style
 	StatusReady    lipgloss.Style
@@ -214,6 +319,9 @@ func DefaultTreeDelegateStyles() TreeDelegateStyles {
 		SessionName:    lipgloss.NewStyle().Foreground(colorWhite),
 		SessionBranch:  lipgloss.NewStyle().Foreground(colorGray),
 		SessionID:      lipgloss.NewStyle().Foreground(lipgloss.Color("#bb9af7")), // purple
+		SessionNote:    lipgloss.NewStyle().Foreground(colorGray).Italic(true),
+		SessionBatch:   lipgloss.NewStyle().Foreground(colorGray),
+		Activity:       lipgloss.NewStyle().Foreground(colorGray).Faint(true),
 		StatusActive:   lipgloss.NewStyle().Foreground(colorGreen),
 		StatusApproval: lipgloss.NewStyle().Foreground(colorYellow),
 		StatusReady:    lipgloss.NewStyle().Foreground(colorCyan),
@@ -228,7 +336,7 @@ func DefaultTreeDelegateStyles() TreeDelegateStyles {
 }
 
 // RenderRepoHeader renders a repository header line.
-func RenderRepoHeader(item TreeItem, isSelected bool, styles TreeDelegateStyles) string {
+func RenderRepoHeader(item TreeItem, isSelected bool, styles TreeDelegateStyles, icons Icons) string {
 	// Repo name
 	nameStyle := styles.HeaderNormal
 	if isSelected {
@@ -238,25 +346,19 @@ func RenderRepoHeader(item TreeItem, isSelected bool, styles TreeDelegateStyles)
 
 	// Append indicator for current repo
 	if item.IsCurrentRepo {
-		result += " " + styles.HeaderStar.Render(currentRepoIndicator)
+		result += " " + styles.HeaderStar.Render(icons.CurrentRepo)
 	}
 
 	return result
 }
 
 // RenderSessionLine renders a session entry with tree prefix.
-func RenderSessionLine(item TreeItem, isSelected bool, gitBranch string, termStatus *TerminalStatus, styles TreeDelegateStyles, animFrame int) string {
+func RenderSessionLine(item TreeItem, isSelected bool, gitBranch string, termStatus *TerminalStatus, styles TreeDelegateStyles, icons Icons, animFrame int) string {
 	// Tree prefix
-	var prefix string
-	if item.IsLastInRepo {
-		prefix = treeLast
-	} else {
-		prefix = treeBranch
-	}
-	prefixStyled := styles.TreeLine.Render(prefix)
+	prefixStyled := styles.TreeLine.Render(icons.TreePrefix(item))
 
 	// Status indicator - use terminal status for active sessions
-	statusStr := renderStatusIndicator(item.Session.State, termStatus, styles, animFrame)
+	statusStr := renderStatusIndicator(item.Session.State, termStatus, styles, icons, animFrame)
 
 	// Session name
 	nameStyle := styles.SessionName
@@ -283,58 +385,107 @@ func RenderSessionLine(item TreeItem, isSelected bool, gitBranch string, termSta
 
 // ColumnWidths holds the calculated widths for aligned columns.
 type ColumnWidths struct {
-	Name   int
-	Branch int
-	ID     int
+	Name      int
+	Branch    int
+	ID        int
+	Additions int // Width of "+N"
+	Deletions int // Width of "-N"
 }
 
 // CalculateColumnWidths calculates the maximum widths for each column within a repo group.
-func CalculateColumnWidths(sessions []session.Session, gitBranches map[string]string) ColumnWidths {
+// gitStatuses is keyed by session path; sessions whose status is missing, loading, or
+// failed do not widen the git columns.
+func CalculateColumnWidths(sessions []session.Session, gitStatuses map[string]GitStatus) ColumnWidths {
 	var widths ColumnWidths
 
 	for _, s := range sessions {
-		if len(s.Name) > widths.Name {
-			widths.Name = len(s.Name)
-		}
-
-		branch := gitBranches[s.Path]
-		if len(branch) > widths.Branch {
-			widths.Branch = len(branch)
-		}
+		widths.Name = max(widths.Name, lipgloss.Width(s.Name))
 
 		shortID := s.ID
 		if len(shortID) > 4 {
 			shortID = shortID[len(shortID)-4:]
 		}
-		if len(shortID) > widths.ID {
-			widths.ID = len(shortID)
+		widths.ID = max(widths.ID, len(shortID))
+
+		status, ok := gitStatuses[s.Path]
+		if !ok || status.IsLoading || status.Error != nil {
+			continue
 		}
+		widths.Branch = max(widths.Branch, lipgloss.Width(status.Branch))
+		widths.Additions = max(widths.Additions, len(fmt.Sprintf("+%d", status.Additions)))
+		widths.Deletions = max(widths.Deletions, len(fmt.Sprintf("-%d", status.Deletions)))
 	}
 
 	return widths
 }
 
+// RepoColumnWidths holds the column widths of each repository group in the
+// tree, so a session lines up with the other sessions of its repository.
+type RepoColumnWidths map[string]ColumnWidths
+
+// CalculateRepoColumnWidths calculates the column widths of every repository
+// group among items. Remote host sessions ignore gitStatuses, which only
+// describe local paths.
+func CalculateRepoColumnWidths(items []list.Item, gitStatuses map[string]GitStatus) RepoColumnWidths {
+	grouped := make(map[string][]session.Session)
+	hosts := make(map[string]bool)
+	for _, item := range items {
+		ti, ok := item.(TreeItem)
+		if !ok || ti.IsHeader || ti.IsRecycledPlaceholder {
+			continue
+		}
+		key := columnKey(ti)
+		grouped[key] = append(grouped[key], ti.Session)
+		hosts[key] = ti.Host != ""
+	}
+
+	widths := make(RepoColumnWidths, len(grouped))
+	for key, sessions := range grouped {
+		statuses := gitStatuses
+		if hosts[key] {
+			statuses = nil
+		}
+		widths[key] = CalculateColumnWidths(sessions, statuses)
+	}
+	return widths
+}
+
+// For returns the column widths of the repository group item belongs to.
+func (w RepoColumnWidths) For(item TreeItem) ColumnWidths {
+	return w[columnKey(item)]
+}
+
+// columnKey identifies the repository group of a session item. Sessions are
+// grouped by remote, separately for each host.
+func columnKey(item TreeItem) string {
+	return item.Host + "\x00" + item.Session.Remote
+}
+
 // PadRight pads a string to the right with spaces to reach the desired width.
+// The width is measured in terminal cells, ignoring any ANSI styling.
 func PadRight(s string, width int) string {
-	if len(s) >= width {
-		return s
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return s
 }
 
 // TreeDelegate handles rendering of tree items in the list.
 type TreeDelegate struct {
 	Styles           TreeDelegateStyles
+	Icons            Icons
 	GitStatuses      *kv.Store[string, GitStatus]
 	TerminalStatuses *kv.Store[string, TerminalStatus]
-	ColumnWidths     *ColumnWidths
-	AnimationFrame   int // Current frame for status animations
+	ColumnWidths     *RepoColumnWidths
+	AnimationFrame   int  // Current frame for status animations
+	ShowActivity     bool // Show the last terminal output line beside sessions
 }
 
-// NewTreeDelegate creates a new tree delegate with default styles.
+// NewTreeDelegate creates a new tree delegate with default styles and icons.
 func NewTreeDelegate() TreeDelegate {
 	return TreeDelegate{
 		Styles: DefaultTreeDelegateStyles(),
+		Icons:  DefaultIcons(),
 	}
 }
 
@@ -381,22 +532,26 @@ func (d TreeDelegate) Render(w io.Writer, m list.Model, index int, item list.Ite
 	} else {
 		prefix = "  "
 	}
+	prefix += strings.Repeat("  ", treeItem.Indent)
 
 	_, _ = fmt.Fprintf(w, "%s%s", prefix, line)
 }
 
-// renderHeader renders a repository header.
+// renderHeader renders a repository or owner header.
 func (d TreeDelegate) renderHeader(item TreeItem, isSelected bool, _ list.Model, _ int) string {
 	// Repo name
 	nameStyle := d.Styles.HeaderNormal
 	if isSelected {
 		nameStyle = d.Styles.HeaderSelected
 	}
+	if item.IsOwnerHeader {
+		return nameStyle.Render(ownerHeaderText(item, d.Icons))
+	}
 	result := nameStyle.Render(item.RepoName)
 
 	// Append indicator for current repo
 	if item.IsCurrentRepo {
-		result += " " + d.Styles.HeaderStar.Render(currentRepoIndicator)
+		result += " " + d.Styles.HeaderStar.Render(d.Icons.CurrentRepo)
 	}
 
 	return result
@@ -405,16 +560,10 @@ func (d TreeDelegate) renderHeader(item TreeItem, isSelected bool, _ list.Model,
 // renderRecycledPlaceholder renders the collapsed recycled sessions placeholder.
 func (d TreeDelegate) renderRecycledPlaceholder(item TreeItem, isSelected bool) string {
 	// Tree prefix
-	var prefix string
-	if item.IsLastInRepo {
-		prefix = treeLast
-	} else {
-		prefix = treeBranch
-	}
-	prefixStyled := d.Styles.TreeLine.Render(prefix)
+	prefixStyled := d.Styles.TreeLine.Render(d.Icons.TreePrefix(item))
 
 	// Status indicator (recycled)
-	statusStr := d.Styles.StatusRecycled.Render(statusRecycled)
+	statusStr := d.Styles.StatusRecycled.Render(d.Icons.Recycled)
 
 	// Label with count
 	labelStyle := d.Styles.StatusRecycled
@@ -429,24 +578,18 @@ func (d TreeDelegate) renderRecycledPlaceholder(item TreeItem, isSelected bool)
 // renderSession renders a session entry.
 func (d TreeDelegate) renderSession(item TreeItem, isSelected bool, m list.Model, index int) string {
 	// Tree prefix
-	var prefix string
-	if item.IsLastInRepo {
-		prefix = treeLast
-	} else {
-		prefix = treeBranch
-	}
-	prefixStyled := d.Styles.TreeLine.Render(prefix)
+	prefixStyled := d.Styles.TreeLine.Render(d.Icons.TreePrefix(item))
 
-	// Get terminal status if available
+	// Get terminal status if available; remote sessions have none
 	var termStatus *TerminalStatus
-	if d.TerminalStatuses != nil {
+	if d.TerminalStatuses != nil && item.Host == "" {
 		if ts, ok := d.TerminalStatuses.Get(item.Session.ID); ok {
 			termStatus = &ts
 		}
 	}
 
 	// Status indicator - use terminal status for active sessions
-	statusStr := renderStatusIndicator(item.Session.State, termStatus, d.Styles, d.AnimationFrame)
+	statusStr := renderStatusIndicator(item.Session.State, termStatus, d.Styles, d.Icons, d.AnimationFrame)
 
 	// Session name with filter matching
 	nameStyle := d.Styles.SessionName
@@ -467,30 +610,83 @@ func (d TreeDelegate) renderSession(item TreeItem, isSelected bool, m list.Model
 	nameOffset := len([]rune(item.RepoPrefix)) + 1
 	name := d.renderWithMatches(item.Session.Name, nameOffset, matchSet, nameStyle, matchStyle)
 
-	// Pad name to align columns (add spaces after styled name)
-	namePadding := ""
-	if d.ColumnWidths != nil && d.ColumnWidths.Name > 0 {
-		padLen := d.ColumnWidths.Name - len(item.Session.Name)
-		if padLen > 0 {
-			namePadding = strings.Repeat(" ", padLen)
-		}
+	// Pad columns to align with the rest of the repository
+	var widths ColumnWidths
+	if d.ColumnWidths != nil {
+		widths = d.ColumnWidths.For(item)
 	}
+	name = PadRight(name, widths.Name)
 
 	// Short ID
 	shortID := item.Session.ID
 	if len(shortID) > 4 {
 		shortID = shortID[len(shortID)-4:]
 	}
-	id := d.Styles.SessionID.Render(" #" + shortID)
+	id := d.Styles.SessionID.Render(" #" + PadRight(shortID, widths.ID))
 
 	// Git status: branch, diff stats, clean/dirty indicator
-	gitInfo := d.renderGitStatus(item.Session.Path)
+	var gitInfo string
+	if item.Host == "" {
+		gitInfo = d.renderGitStatus(item.Session.Path, widths)
+	}
 
-	return fmt.Sprintf("%s %s %s%s%s%s", prefixStyled, statusStr, name, namePadding, id, gitInfo)
+	// Pinned marker, batch, and note trail the line so they don't shift the
+	// aligned columns
+	var pin string
+	if item.Session.IsPinned() {
+		pin = d.Styles.HeaderStar.Render(" " + d.Icons.Pinned)
+	}
+	var batch string
+	if b := item.Session.BatchID(); b != "" {
+		batch = d.Styles.SessionBatch.Render(" batch:" + b)
+	}
+	var note string
+	if n := item.Session.Note(); n != "" {
+		note = d.Styles.SessionNote.Render(" " + n)
+	}
+
+	line := fmt.Sprintf("%s %s %s%s%s%s%s%s", prefixStyled, statusStr, name, id, gitInfo, pin, batch, note)
+
+	// Last terminal output, cut to the space left on the line
+	if d.ShowActivity && termStatus != nil && termStatus.Activity != "" {
+		avail := m.Width() - 2 - 2*item.Indent - lipgloss.Width(line) - lipgloss.Width(activitySeparator)
+		if avail >= minActivityWidth {
+			activity := strings.Join(strings.Fields(termStatus.Activity), " ")
+			line += d.Styles.Activity.Render(activitySeparator + truncateWidth(activity, avail))
+		}
+	}
+
+	return line
+}
+
+// Activity snippet layout.
+const (
+	activitySeparator = " · "
+	minActivityWidth  = 10 // narrower snippets are left out
+)
+
+// truncateWidth shortens s to at most width terminal cells, ending it with
+// "…" when cut.
+func truncateWidth(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	w := 0
+	for _, r := range s {
+		rw := lipgloss.Width(string(r))
+		if w+rw > width-1 {
+			break
+		}
+		b.WriteRune(r)
+		w += rw
+	}
+	return b.String() + "…"
 }
 
-// renderGitStatus returns the formatted git status for a session path.
-func (d TreeDelegate) renderGitStatus(path string) string {
+// renderGitStatus returns the formatted git status for a session path,
+// padded to widths.
+func (d TreeDelegate) renderGitStatus(path string, widths ColumnWidths) string {
 	if d.GitStatuses == nil {
 		return gitLoadingStyle.Render(" ...")
 	}
@@ -505,9 +701,9 @@ func (d TreeDelegate) renderGitStatus(path string) string {
 	}
 
 	// Format: (branch) +N -N • clean/dirty
-	branch := d.Styles.SessionBranch.Render(" (" + status.Branch + ")")
-	additions := gitAdditionsStyle.Render(fmt.Sprintf(" +%d", status.Additions))
-	deletions := gitDeletionsStyle.Render(fmt.Sprintf(" -%d", status.Deletions))
+	branch := d.Styles.SessionBranch.Render(" " + PadRight("("+status.Branch+")", widths.Branch+2))
+	additions := gitAdditionsStyle.Render(" " + PadRight(fmt.Sprintf("+%d", status.Additions), widths.Additions))
+	deletions := gitDeletionsStyle.Render(" " + PadRight(fmt.Sprintf("-%d", status.Deletions), widths.Deletions))
 
 	var indicator string
 	if status.HasChanges {
@@ -189,6 +189,7 @@ func TestPadRight(t *testing.T) {
 		{"abcde", 5, "abcde"},
 		{"abcdef", 5, "abcdef"},
 		{"", 3, "   "},
+		{"日本", 5, "日本 "},
 	}
 
 	for _, tt := range tests {
@@ -199,6 +200,25 @@ func TestPadRight(t *testing.T) {
 	}
 }
 
+func TestTruncateWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly-10", 10, "exactly-10"},
+		{"running go test ./...", 10, "running g…"},
+		{"日本語テキスト", 7, "日本語…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.want, truncateWidth(tt.input, tt.width))
+		})
+	}
+}
+
 func TestCalculateColumnWidths(t *testing.T) {
 	sessions := []session.Session{
 		{ID: "abcd1234", Name: "short", Path: "/path1"},
@@ -206,15 +226,97 @@ func TestCalculateColumnWidths(t *testing.T) {
 		{ID: "ijkl9012", Name: "medium", Path: "/path3"},
 	}
 
-	gitBranches := map[string]string{
-		"/path1": "main",
-		"/path2": "feature/very-long-branch-name",
-		"/path3": "develop",
+	gitStatuses := map[string]GitStatus{
+		"/path1": {Branch: "main", Additions: 120, Deletions: 3},
+		"/path2": {Branch: "feature/very-long-branch-name", Deletions: 45},
+		"/path3": {Branch: "develop-branch-still-loading-xxxxxxxxxxxx", IsLoading: true},
 	}
 
-	widths := CalculateColumnWidths(sessions, gitBranches)
+	widths := CalculateColumnWidths(sessions, gitStatuses)
 
 	assert.Equal(t, len("much-longer-name"), widths.Name)
 	assert.Equal(t, len("feature/very-long-branch-name"), widths.Branch)
 	assert.Equal(t, 4, widths.ID) // All IDs are truncated to 4 chars
+	assert.Equal(t, len("+120"), widths.Additions)
+	assert.Equal(t, len("-45"), widths.Deletions)
+}
+
+func TestCalculateRepoColumnWidths(t *testing.T) {
+	sessions := []session.Session{
+		{ID: "s1", Name: "a-very-long-session", Remote: "git@github.com:user/one.git", Path: "/one/a", State: session.StateActive},
+		{ID: "s2", Name: "short", Remote: "git@github.com:user/two.git", Path: "/two/b", State: session.StateActive},
+	}
+	items := BuildTreeItems(GroupSessionsByRepo(sessions, ""), "")
+	items = append(items, BuildHostTreeItems("server", []session.Session{
+		{ID: "s3", Name: "remote-session", Remote: "git@github.com:user/two.git", Path: "/two/b", State: session.StateActive},
+	}, nil)...)
+
+	widths := CalculateRepoColumnWidths(items, map[string]GitStatus{"/two/b": {Branch: "main"}})
+
+	require.Len(t, widths, 3)
+	assert.Equal(t, len("a-very-long-session"), widths.For(items[1].(TreeItem)).Name)
+
+	local := widths.For(items[3].(TreeItem))
+	assert.Equal(t, len("short"), local.Name)
+	assert.Equal(t, len("main"), local.Branch)
+
+	remote := widths.For(items[5].(TreeItem))
+	assert.Equal(t, len("remote-session"), remote.Name)
+	assert.Equal(t, 0, remote.Branch, "remote sessions ignore local git statuses")
+}
+
+func TestBuildHostTreeItems(t *testing.T) {
+	sessions := []session.Session{
+		{ID: "r1", Name: "remote-a", Remote: "git@github.com:user/repo.git", State: session.StateActive},
+	}
+
+	items := BuildHostTreeItems("server", sessions, nil)
+	require.Len(t, items, 2)
+
+	header := items[0].(TreeItem)
+	assert.True(t, header.IsHeader)
+	assert.Equal(t, "repo @server", header.RepoName)
+	assert.Equal(t, "server", items[1].(TreeItem).Host)
+
+	unreachable := BuildHostTreeItems("server", nil, assert.AnError)
+	require.Len(t, unreachable, 1)
+	assert.Equal(t, "@server (unreachable)", unreachable[0].(TreeItem).RepoName)
+}
+
+func TestBuildOwnerTreeItems(t *testing.T) {
+	sessions := []session.Session{
+		{ID: "s1", Name: "one", Remote: "git@github.com:acme/web.git", State: session.StateActive},
+		{ID: "s2", Name: "two", Remote: "git@github.com:acme/cli.git", State: session.StateActive},
+		{ID: "s3", Name: "three", Remote: "git@github.com:zeta/api.git", State: session.StateActive},
+	}
+	groups := GroupSessionsByRepo(sessions, "")
+
+	items := BuildOwnerTreeItems(groups, "", nil)
+	require.Len(t, items, 8) // 2 owners, 3 repos, 3 sessions
+
+	owner := items[0].(TreeItem)
+	assert.True(t, owner.IsHeader)
+	assert.True(t, owner.IsOwnerHeader)
+	assert.Equal(t, "acme", owner.Owner)
+	assert.Equal(t, 2, owner.RepoCount)
+	assert.Equal(t, 2, owner.SessionCount)
+	assert.Equal(t, 0, owner.Indent)
+	assert.Equal(t, "▾ acme (2 repos, 2 sessions)", ownerHeaderText(owner, DefaultIcons()))
+
+	repo := items[1].(TreeItem)
+	assert.True(t, repo.IsHeader)
+	assert.False(t, repo.IsOwnerHeader)
+	assert.Equal(t, 1, repo.Indent)
+	assert.Equal(t, 1, items[2].(TreeItem).Indent)
+
+	t.Run("collapsed owner hides its repos", func(t *testing.T) {
+		items := BuildOwnerTreeItems(groups, "", map[string]bool{"acme": true})
+		require.Len(t, items, 4)
+
+		owner := items[0].(TreeItem)
+		assert.True(t, owner.Collapsed)
+		assert.Equal(t, "▸ acme (2 repos, 2 sessions)", ownerHeaderText(owner, DefaultIcons()))
+		assert.Equal(t, "zeta", items[1].(TreeItem).Owner)
+		assert.Equal(t, "▾ zeta (1 repo, 1 session)", ownerHeaderText(items[1].(TreeItem), DefaultIcons()))
+	})
 }
@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -13,11 +17,11 @@ import (
 
 	"github.com/hay-kot/hive/internal/commands"
 	"github.com/hay-kot/hive/internal/core/config"
-	"github.com/hay-kot/hive/internal/core/git"
-	"github.com/hay-kot/hive/internal/hive"
 	"github.com/hay-kot/hive/internal/printer"
 	"github.com/hay-kot/hive/internal/store/jsonfile"
-	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/hay-kot/hive/internal/styles"
+	"github.com/hay-kot/hive/internal/tracing"
+	hiveapi "github.com/hay-kot/hive/pkg/hive"
 	"github.com/hay-kot/hive/pkg/utils"
 )
 
@@ -48,8 +52,18 @@ func main() {
 		flags = &commands.Flags{}
 	)
 
+	// Cancel in-flight clones, hooks, and batches on Ctrl-C or SIGTERM. Once
+	// cancelled, a second signal gets the default behaviour and exits at once.
+	ctx, stopSignals := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stopSignals()
+	}()
+
 	var deferredLogs *utils.DeferredWriter
+	var shutdownTracing func(context.Context) error
 
+	pluginArgsStart := 1
 	app := &cli.Command{
 		Name:      "hive",
 		Usage:     "Manage multiple AI agent sessions",
@@ -62,6 +76,8 @@ spawning terminal sessions with your preferred AI tool.
 Run 'hive' with no arguments to open the interactive session manager.
 Run 'hive new' to create a new session from the current repository.`,
 		Version: build(),
+		// Flags after an unknown command belong to the plugin that runs it
+		StopOnNthArg: &pluginArgsStart,
 		Flags: []cli.Flag{
 			&cli.StringFlag{
 				Name:        "log-level",
@@ -91,6 +107,38 @@ Run 'hive new' to create a new session from the current repository.`,
 				Value:       commands.DefaultDataDir(),
 				Destination: &flags.DataDir,
 			},
+			&cli.StringFlag{
+				Name:        "profile",
+				Usage:       "named profile selecting separate config and data directories",
+				Sources:     cli.EnvVars("HIVE_PROFILE"),
+				Destination: &flags.Profile,
+			},
+			&cli.StringFlag{
+				Name:        "output",
+				Aliases:     []string{"o"},
+				Usage:       "output format for command results (text, json)",
+				Sources:     cli.EnvVars("HIVE_OUTPUT"),
+				Value:       "text",
+				Destination: &flags.Output,
+			},
+			&cli.BoolFlag{
+				Name:        "json",
+				Usage:       "shorthand for --output json",
+				Destination: &flags.JSON,
+			},
+			&cli.BoolFlag{
+				Name:        "quiet",
+				Aliases:     []string{"q"},
+				Usage:       "suppress progress, hook output, and informational messages",
+				Sources:     cli.EnvVars("HIVE_QUIET"),
+				Destination: &flags.Quiet,
+			},
+			&cli.BoolFlag{
+				Name:        "no-color",
+				Usage:       "disable colored output (also set by NO_COLOR)",
+				Sources:     cli.EnvVars("HIVE_NO_COLOR"),
+				Destination: &flags.NoColor,
+			},
 		},
 		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
 			// Detect TUI mode: no subcommand means TUI (default action)
@@ -107,22 +155,72 @@ Run 'hive new' to create a new session from the current repository.`,
 				return ctx, err
 			}
 
+			format, err := printer.ParseFormat(flags.Output)
+			if err != nil {
+				return ctx, err
+			}
+			if flags.JSON {
+				format = printer.FormatJSON
+			}
+			printer.Ctx(ctx).SetFormat(format)
+			printer.Ctx(ctx).SetQuiet(flags.Quiet)
+
+			if flags.NoColor || os.Getenv("NO_COLOR") != "" {
+				printer.Ctx(ctx).SetNoColor(true)
+				styles.DisableColor()
+			}
+
+			// Profiles swap in conventional paths unless they were set explicitly
+			if flags.Profile != "" {
+				if err := commands.ValidateProfileName(flags.Profile); err != nil {
+					return ctx, err
+				}
+				if !c.IsSet("config") {
+					flags.ConfigPath = commands.ProfileConfigPath(flags.Profile)
+				}
+				if !c.IsSet("data-dir") {
+					flags.DataDir = commands.ProfileDataDir(flags.Profile)
+				}
+			}
+
 			cfg, err := config.Load(flags.ConfigPath, flags.DataDir)
 			if err != nil {
 				return ctx, fmt.Errorf("load config: %w", err)
 			}
 			flags.Config = cfg
 
-			// Create service
-			var (
-				store   = jsonfile.New(cfg.SessionsFile())
-				exec    = &executil.RealExecutor{}
-				gitExec = git.NewExecutor(cfg.GitPath, exec)
-				logger  = log.With().Str("component", "hive").Logger()
-			)
+			shutdownTracing, err = tracing.Setup(ctx, cfg.Tracing, version)
+			if err != nil {
+				return ctx, fmt.Errorf("setup tracing: %w", err)
+			}
 
-			flags.Service = hive.New(store, gitExec, cfg, exec, logger, os.Stdout, os.Stderr)
-			flags.Store = store
+			// Keep stdout clean for JSON results; command output goes to stderr,
+			// or nowhere when quiet
+			var cmdOut io.Writer = os.Stdout
+			switch {
+			case flags.Quiet:
+				cmdOut = io.Discard
+			case format == printer.FormatJSON:
+				cmdOut = os.Stderr
+			}
+
+			h, err := hiveapi.Open(cfg, hiveapi.Options{
+				Logger: log.With().Str("component", "hive").Logger(),
+				Stdout: cmdOut,
+				Stderr: os.Stderr,
+			})
+			if err != nil {
+				return ctx, err
+			}
+			flags.Service = h.Service
+			flags.Store = h.Sessions
+
+			// Finish directory renames a crashed hive left half done
+			if n, err := flags.Service.ReconcileRenames(ctx); err != nil {
+				log.Warn().Err(err).Msg("failed to reconcile interrupted renames")
+			} else if n > 0 {
+				log.Info().Int("sessions", n).Msg("reconciled interrupted renames")
+			}
 			return ctx, nil
 		},
 	}
@@ -130,14 +228,36 @@ Run 'hive new' to create a new session from the current repository.`,
 	tuiCmd := commands.NewTuiCmd(flags)
 
 	app = commands.NewNewCmd(flags).Register(app)
+	app = commands.NewSpawnCmd(flags).Register(app)
+	app = commands.NewResumeCmd(flags).Register(app)
+	app = commands.NewExecCmd(flags).Register(app)
+	app = commands.NewCICmd(flags).Register(app)
+	app = commands.NewOpenCmd(flags).Register(app)
+	app = commands.NewIDECmd(flags).Register(app)
+	app = commands.NewPathCmd(flags).Register(app)
+	app = commands.NewShellEnvCmd(flags).Register(app)
 	app = commands.NewLsCmd(flags).Register(app)
 	app = commands.NewPruneCmd(flags).Register(app)
+	app = commands.NewGCCmd(flags).Register(app)
+	app = commands.NewWarmCmd(flags).Register(app)
+	app = commands.NewMigrateStoreCmd(flags).Register(app)
+	app = commands.NewBackupCmd(flags).Register(app)
+	app = commands.NewDeleteCmd(flags).Register(app)
+	app = commands.NewUndeleteCmd(flags).Register(app)
 	app = commands.NewDoctorCmd(flags).Register(app)
 	app = commands.NewBatchCmd(flags).Register(app)
 	app = commands.NewCtxCmd(flags).Register(app)
 	app = commands.NewMsgCmd(flags).Register(app)
 	app = commands.NewDocCmd(flags).Register(app)
+	app = commands.NewConfigCmd(flags).Register(app)
+	app = commands.NewProfileCmd(flags).Register(app)
+	app = commands.NewTemplateCmd(flags).Register(app)
 	app = commands.NewSessionCmd(flags).Register(app)
+	app = commands.NewServeCmd(flags).Register(app)
+	app = commands.NewHostsCmd(flags).Register(app)
+	app = commands.NewLogsCmd(flags).Register(app)
+	app = commands.NewEventsCmd(flags).Register(app)
+	app = commands.NewPluginsCmd(flags).Register(app)
 
 	// Register TUI flags on root command
 	app.Flags = append(app.Flags, tuiCmd.Flags()...)
@@ -145,7 +265,11 @@ Run 'hive new' to create a new session from the current repository.`,
 	// Set TUI as default action when no subcommand is provided
 	app.Action = func(ctx context.Context, c *cli.Command) error {
 		if c.Args().Len() > 0 {
-			return fmt.Errorf("unknown command %q. Run 'hive --help' for usage", c.Args().First())
+			plugin, ok := commands.FindPlugin(c.Args().First())
+			if !ok {
+				return fmt.Errorf("unknown command %q. Run 'hive --help' for usage", c.Args().First())
+			}
+			return commands.RunPlugin(ctx, flags, plugin, c.Args().Tail())
 		}
 		return tuiCmd.Run(ctx, c)
 	}
@@ -153,9 +277,29 @@ Run 'hive new' to create a new session from the current repository.`,
 	exitCode := 0
 	runErr := app.Run(ctx, os.Args)
 	if runErr != nil {
-		fmt.Println()
-		printer.Ctx(ctx).FatalError(runErr)
+		if !p.IsJSON() {
+			fmt.Println()
+		}
+		p.FatalError(runErr)
+		if errors.Is(runErr, jsonfile.ErrCorrupt) && !p.IsJSON() {
+			p.Infof("Run 'hive doctor --data --autofix' to quarantine the file and restore it from a backup")
+		}
 		exitCode = 1
+		if ctx.Err() != nil {
+			exitCode = 130 // interrupted, as a shell reports it
+		}
+	}
+	stopSignals()
+
+	commands.CloseStore(flags.Store)
+
+	// Export spans buffered during the run
+	if shutdownTracing != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Warn().Err(err).Msg("failed to export traces")
+		}
+		cancel()
 	}
 
 	// Flush deferred logs to console after TUI exits
@@ -5,7 +5,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"slices"
+	"syscall"
+	"time"
 )
 
 // Executor runs shell commands.
@@ -25,7 +29,7 @@ type RealExecutor struct{}
 
 // Run executes a command and returns its combined output.
 func (e *RealExecutor) Run(ctx context.Context, cmd string, args ...string) ([]byte, error) {
-	out, err := exec.CommandContext(ctx, cmd, args...).CombinedOutput()
+	out, err := command(ctx, cmd, args...).CombinedOutput()
 	if err != nil {
 		return out, fmt.Errorf("exec %s: %w", cmd, err)
 	}
@@ -34,7 +38,7 @@ func (e *RealExecutor) Run(ctx context.Context, cmd string, args ...string) ([]b
 
 // RunDir executes a command in a specific directory.
 func (e *RealExecutor) RunDir(ctx context.Context, dir, cmd string, args ...string) ([]byte, error) {
-	c := exec.CommandContext(ctx, cmd, args...)
+	c := command(ctx, cmd, args...)
 	c.Dir = dir
 	out, err := c.CombinedOutput()
 	if err != nil {
@@ -45,7 +49,7 @@ func (e *RealExecutor) RunDir(ctx context.Context, dir, cmd string, args ...stri
 
 // RunStream executes a command and streams stdout/stderr to the provided writers.
 func (e *RealExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer, cmd string, args ...string) error {
-	c := exec.CommandContext(ctx, cmd, args...)
+	c := command(ctx, cmd, args...)
 	c.Stdout = stdout
 	c.Stderr = stderr
 	if err := c.Run(); err != nil {
@@ -56,7 +60,7 @@ func (e *RealExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer,
 
 // RunDirStream executes a command in a specific directory and streams output.
 func (e *RealExecutor) RunDirStream(ctx context.Context, dir string, stdout, stderr io.Writer, cmd string, args ...string) error {
-	c := exec.CommandContext(ctx, cmd, args...)
+	c := command(ctx, cmd, args...)
 	c.Dir = dir
 	c.Stdout = stdout
 	c.Stderr = stderr
@@ -65,3 +69,53 @@ func (e *RealExecutor) RunDirStream(ctx context.Context, dir string, stdout, std
 	}
 	return nil
 }
+
+type envKey struct{}
+
+// WithEnv returns a context that adds env ("KEY=value" pairs) to the
+// environment of commands run by RealExecutor. Entries are appended to the
+// current process environment, so they take precedence over inherited values.
+func WithEnv(ctx context.Context, env []string) context.Context {
+	if len(env) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, envKey{}, append(EnvFromContext(ctx), env...))
+}
+
+// EnvFromContext returns the extra environment attached by WithEnv.
+func EnvFromContext(ctx context.Context) []string {
+	env, _ := ctx.Value(envKey{}).([]string)
+	return slices.Clone(env)
+}
+
+type processGroupKey struct{}
+
+// WithProcessGroup returns a context under which RealExecutor starts each
+// command in its own process group, so cancelling it signals every process
+// it started, such as the npm install a hook's shell runs, rather than only
+// the shell. A process group in the background is stopped when it reads the
+// terminal, so this is not meant for commands that may prompt, such as git
+// asking for credentials.
+func WithProcessGroup(ctx context.Context) context.Context {
+	return context.WithValue(ctx, processGroupKey{}, true)
+}
+
+// waitDelay bounds how long a cancelled command has to exit after SIGTERM
+// before it is killed, and how long its output pipes may stay open, e.g.
+// held by a child process that outlived the shell.
+const waitDelay = 5 * time.Second
+
+func command(ctx context.Context, cmd string, args ...string) *exec.Cmd {
+	c := exec.CommandContext(ctx, cmd, args...)
+	// Ask cancelled commands to stop so git and hooks can clean up
+	c.Cancel = func() error { return c.Process.Signal(syscall.SIGTERM) }
+	if group, _ := ctx.Value(processGroupKey{}).(bool); group {
+		c.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		c.Cancel = func() error { return syscall.Kill(-c.Process.Pid, syscall.SIGTERM) }
+	}
+	c.WaitDelay = waitDelay
+	if env := EnvFromContext(ctx); len(env) > 0 {
+		c.Env = append(os.Environ(), env...)
+	}
+	return c
+}
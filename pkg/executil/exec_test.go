@@ -3,6 +3,9 @@ package executil
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,6 +50,26 @@ func TestRealExecutor_RunDir(t *testing.T) {
 	})
 }
 
+func TestRealExecutor_WithProcessGroup(t *testing.T) {
+	exec := &RealExecutor{}
+	ctx := context.Background()
+
+	// Prints the shell's PID and process group
+	pids := func(ctx context.Context) (pid, pgid string) {
+		out, err := exec.Run(ctx, "sh", "-c", "echo $$; ps -o pgid= -p $$")
+		require.NoError(t, err)
+		fields := strings.Fields(string(out))
+		require.Len(t, fields, 2)
+		return fields[0], fields[1]
+	}
+
+	_, pgid := pids(ctx)
+	assert.Equal(t, strconv.Itoa(syscall.Getpgrp()), pgid, "commands stay in hive's process group")
+
+	pid, pgid := pids(WithProcessGroup(ctx))
+	assert.Equal(t, pid, pgid, "the command leads its own process group")
+}
+
 func TestRecordingExecutor_Run(t *testing.T) {
 	t.Run("records commands", func(t *testing.T) {
 		exec := &RecordingExecutor{}
@@ -108,3 +131,14 @@ func TestRecordingExecutor_Run(t *testing.T) {
 		assert.Empty(t, exec.Commands)
 	})
 }
+
+func TestRealExecutor_WithEnv(t *testing.T) {
+	exec := &RealExecutor{}
+	ctx := WithEnv(context.Background(), []string{"HIVE_EXEC_TEST=hello"})
+
+	out, err := exec.Run(ctx, "sh", "-c", "printf %s \"$HIVE_EXEC_TEST\"")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(out))
+
+	assert.Empty(t, EnvFromContext(context.Background()))
+}
@@ -11,6 +11,7 @@ type RecordedCommand struct {
 	Dir  string
 	Cmd  string
 	Args []string
+	Env  []string // extra environment from WithEnv
 }
 
 // RecordingExecutor captures commands for testing.
@@ -29,15 +30,15 @@ type RecordingExecutor struct {
 
 // Run records the command and returns configured output/error.
 func (e *RecordingExecutor) Run(ctx context.Context, cmd string, args ...string) ([]byte, error) {
-	return e.record("", cmd, args...)
+	return e.record(ctx, "", cmd, args...)
 }
 
 // RunDir records the command with directory and returns configured output/error.
 func (e *RecordingExecutor) RunDir(ctx context.Context, dir, cmd string, args ...string) ([]byte, error) {
-	return e.record(dir, cmd, args...)
+	return e.record(ctx, dir, cmd, args...)
 }
 
-func (e *RecordingExecutor) record(dir, cmd string, args ...string) ([]byte, error) {
+func (e *RecordingExecutor) record(ctx context.Context, dir, cmd string, args ...string) ([]byte, error) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
@@ -45,6 +46,7 @@ func (e *RecordingExecutor) record(dir, cmd string, args ...string) ([]byte, err
 		Dir:  dir,
 		Cmd:  cmd,
 		Args: args,
+		Env:  EnvFromContext(ctx),
 	})
 
 	var out []byte
@@ -69,7 +71,7 @@ func (e *RecordingExecutor) Reset() {
 
 // RunStream records the command and writes configured output to writers.
 func (e *RecordingExecutor) RunStream(ctx context.Context, stdout, stderr io.Writer, cmd string, args ...string) error {
-	out, err := e.record("", cmd, args...)
+	out, err := e.record(ctx, "", cmd, args...)
 	if stdout != nil && len(out) > 0 {
 		_, _ = stdout.Write(out)
 	}
@@ -78,7 +80,7 @@ func (e *RecordingExecutor) RunStream(ctx context.Context, stdout, stderr io.Wri
 
 // RunDirStream records the command with directory and writes configured output to writers.
 func (e *RecordingExecutor) RunDirStream(ctx context.Context, dir string, stdout, stderr io.Writer, cmd string, args ...string) error {
-	out, err := e.record(dir, cmd, args...)
+	out, err := e.record(ctx, dir, cmd, args...)
 	if stdout != nil && len(out) > 0 {
 		_, _ = stdout.Write(out)
 	}
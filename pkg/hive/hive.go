@@ -0,0 +1,188 @@
+// Package hive is the Go API for embedding hive in other tools, such as
+// orchestrators and bots, without running the hive CLI. The CLI is built on
+// it, so both share one service, session store, and message store.
+//
+//	cfg, err := hive.LoadConfig("", "")
+//	if err != nil {
+//		return err
+//	}
+//	h, err := hive.Open(cfg, hive.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	defer h.Close()
+//
+//	sess, err := h.Service.CreateSession(ctx, hive.CreateOptions{Name: "fix-bug", Remote: remote})
+package hive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hay-kot/hive/internal/core/config"
+	"github.com/hay-kot/hive/internal/core/git"
+	"github.com/hay-kot/hive/internal/core/messaging"
+	"github.com/hay-kot/hive/internal/core/session"
+	svc "github.com/hay-kot/hive/internal/hive"
+	"github.com/hay-kot/hive/internal/store/jsonfile"
+	"github.com/hay-kot/hive/internal/store/sqlite"
+	"github.com/hay-kot/hive/pkg/executil"
+	"github.com/rs/zerolog"
+)
+
+// Core types, shared with the CLI.
+type (
+	// Config is the hive configuration, as read from config.yaml.
+	Config = config.Config
+	// Service creates, recycles, and deletes sessions.
+	Service = svc.Service
+	// CreateOptions configures Service.CreateSession.
+	CreateOptions = svc.CreateOptions
+	// Session is a session record.
+	Session = session.Session
+	// State is the lifecycle state of a session.
+	State = session.State
+	// SessionStore persists sessions.
+	SessionStore = session.Store
+	// Filter selects sessions for SessionStore.Find.
+	Filter = session.Filter
+	// Message is a message published to a topic.
+	Message = messaging.Message
+	// MessageStore persists messages between sessions.
+	MessageStore = messaging.Store
+)
+
+// Session states.
+const (
+	StateActive    = session.StateActive
+	StateRecycled  = session.StateRecycled
+	StateCorrupted = session.StateCorrupted
+)
+
+// Sentinel errors, for use with errors.Is.
+var (
+	ErrNotFound     = session.ErrNotFound
+	ErrNoRecyclable = session.ErrNoRecyclable
+	ErrConflict     = session.ErrConflict
+	ErrAmbiguous    = svc.ErrAmbiguous
+	ErrMaxActive    = svc.ErrMaxActive
+	ErrTopicMissing = messaging.ErrTopicNotFound
+	ErrRateLimited  = messaging.ErrRateLimited
+)
+
+// LoadConfig reads the config file at path, or the default location when
+// path is empty. dataDir overrides data_dir when it is not empty.
+func LoadConfig(path, dataDir string) (*Config, error) {
+	if path == "" {
+		path = DefaultConfigPath()
+	}
+	if dataDir == "" {
+		dataDir = DefaultDataDir()
+	}
+	return config.Load(path, dataDir)
+}
+
+// DefaultConfigPath returns the default config file path using XDG_CONFIG_HOME.
+func DefaultConfigPath() string {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, _ := os.UserHomeDir()
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "hive", "config.yaml")
+}
+
+// DefaultDataDir returns the default data directory using XDG_DATA_HOME.
+func DefaultDataDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, _ := os.UserHomeDir()
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "hive")
+}
+
+// Options configures Open. The zero value logs nothing and discards the
+// output of commands.
+type Options struct {
+	Logger   zerolog.Logger    // Logger for the service
+	Stdout   io.Writer         // Output of spawn commands, hooks, and file copies
+	Stderr   io.Writer         // Errors of those commands and git retry notices
+	Executor executil.Executor // Runs git and configured commands; real processes when nil
+}
+
+// Hive is an open hive: its service and the stores behind it.
+type Hive struct {
+	Config   *Config
+	Service  *Service
+	Sessions SessionStore
+	Messages MessageStore
+}
+
+// Open opens the stores of cfg and builds a service on them. Close the
+// returned Hive to release the session store.
+func Open(cfg *Config, opts Options) (*Hive, error) {
+	sessions, err := OpenStore(cfg, cfg.Store.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, stderr := opts.Stdout, opts.Stderr
+	if stdout == nil {
+		stdout = io.Discard
+	}
+	if stderr == nil {
+		stderr = io.Discard
+	}
+	exec := opts.Executor
+	if exec == nil {
+		exec = &executil.RealExecutor{}
+	}
+
+	return &Hive{
+		Config:   cfg,
+		Service:  svc.New(sessions, git.NewExecutor(cfg.GitPath, exec), cfg, exec, opts.Logger, stdout, stderr),
+		Sessions: sessions,
+		Messages: OpenMessageStore(cfg),
+	}, nil
+}
+
+// Close releases the session store.
+func (h *Hive) Close() error {
+	return CloseStore(h.Sessions)
+}
+
+// OpenStore opens the named session store backend under cfg.DataDir; an
+// empty backend is the JSON file store.
+func OpenStore(cfg *Config, backend string) (SessionStore, error) {
+	switch backend {
+	case "", config.StoreJSON:
+		return jsonfile.New(cfg.SessionsFile()), nil
+	case config.StoreSQLite:
+		store, err := sqlite.New(cfg.SessionsDB())
+		if err != nil {
+			return nil, fmt.Errorf("open sqlite store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// CloseStore closes store if its backend holds resources, such as a
+// database handle.
+func CloseStore(store SessionStore) error {
+	if closer, ok := store.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// OpenMessageStore opens the message store under cfg.DataDir, rate limited
+// by messaging.rate_limit.
+func OpenMessageStore(cfg *Config) MessageStore {
+	limit := cfg.Messaging.RateLimit
+	return jsonfile.NewMsgStore(filepath.Join(cfg.DataDir, "messages", "topics")).WithRateLimit(limit.Messages, limit.Per)
+}
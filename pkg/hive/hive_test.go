@@ -0,0 +1,51 @@
+package hive
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	for _, backend := range []string{"json", "sqlite"} {
+		t.Run(backend, func(t *testing.T) {
+			ctx := context.Background()
+			cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config.yaml"), t.TempDir())
+			require.NoError(t, err)
+			cfg.Store.Backend = backend
+
+			h, err := Open(cfg, Options{})
+			require.NoError(t, err)
+			defer func() { require.NoError(t, h.Close()) }()
+
+			sess := Session{ID: "abc123", Name: "embed", Path: t.TempDir(), Remote: "https://github.com/hay-kot/hive.git", State: StateActive}
+			require.NoError(t, h.Sessions.Save(ctx, sess))
+
+			got, err := h.Service.GetSession(ctx, "abc123")
+			require.NoError(t, err)
+			assert.Equal(t, "embed", got.Name)
+
+			_, err = h.Service.GetSession(ctx, "missing")
+			require.ErrorIs(t, err, ErrNotFound)
+
+			require.NoError(t, h.Messages.Publish(ctx, Message{Topic: "embed", Payload: "hello", CreatedAt: time.Now()}))
+			msgs, err := h.Messages.Subscribe(ctx, "embed", time.Time{})
+			require.NoError(t, err)
+			require.Len(t, msgs, 1)
+			assert.Equal(t, "hello", msgs[0].Payload)
+		})
+	}
+}
+
+func TestOpen_UnknownBackend(t *testing.T) {
+	cfg, err := LoadConfig(filepath.Join(t.TempDir(), "config.yaml"), t.TempDir())
+	require.NoError(t, err)
+	cfg.Store.Backend = "etcd"
+
+	_, err = Open(cfg, Options{})
+	require.ErrorContains(t, err, `unknown store backend "etcd"`)
+}
@@ -0,0 +1,146 @@
+// Package textdiff provides a minimal line-based diff for previewing file changes.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Op identifies the kind of change for a diff line.
+type Op int
+
+const (
+	OpEqual Op = iota
+	OpDelete
+	OpInsert
+)
+
+// Line is a single line in a diff.
+type Line struct {
+	Op   Op
+	Text string
+}
+
+// Prefix returns the unified-diff style prefix for the line.
+func (l Line) Prefix() string {
+	switch l.Op {
+	case OpDelete:
+		return "-"
+	case OpInsert:
+		return "+"
+	default:
+		return " "
+	}
+}
+
+// Lines computes a line diff between a and b using longest common subsequence.
+func Lines(a, b string) []Line {
+	al := splitLines(a)
+	bl := splitLines(b)
+
+	// lcs[i][j] holds the LCS length of al[i:] and bl[j:]
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := make([]Line, 0, max(len(al), len(bl)))
+	i, j := 0, 0
+	for i < len(al) && j < len(bl) {
+		switch {
+		case al[i] == bl[j]:
+			lines = append(lines, Line{Op: OpEqual, Text: al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: OpDelete, Text: al[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: OpInsert, Text: bl[j]})
+			j++
+		}
+	}
+	for ; i < len(al); i++ {
+		lines = append(lines, Line{Op: OpDelete, Text: al[i]})
+	}
+	for ; j < len(bl); j++ {
+		lines = append(lines, Line{Op: OpInsert, Text: bl[j]})
+	}
+
+	return lines
+}
+
+// HasChanges returns true if any line is an insert or delete.
+func HasChanges(lines []Line) bool {
+	for _, l := range lines {
+		if l.Op != OpEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// Hunks trims unchanged lines that are further than context lines away from
+// a change. Skipped regions are represented by a nil entry in the result.
+func Hunks(lines []Line, context int) []*Line {
+	keep := make([]bool, len(lines))
+	for i, l := range lines {
+		if l.Op == OpEqual {
+			continue
+		}
+		for k := max(0, i-context); k <= min(len(lines)-1, i+context); k++ {
+			keep[k] = true
+		}
+	}
+
+	var out []*Line
+	skipping := false
+	for i := range lines {
+		if !keep[i] {
+			if !skipping {
+				out = append(out, nil)
+				skipping = true
+			}
+			continue
+		}
+		skipping = false
+		out = append(out, &lines[i])
+	}
+	return out
+}
+
+// Unified renders a plain-text diff of a and b with the given context size.
+// Returns an empty string if there are no changes.
+func Unified(a, b string, context int) string {
+	lines := Lines(a, b)
+	if !HasChanges(lines) {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, l := range Hunks(lines, context) {
+		if l == nil {
+			sb.WriteString("@@\n")
+			continue
+		}
+		_, _ = fmt.Fprintf(&sb, "%s %s\n", l.Prefix(), l.Text)
+	}
+	return sb.String()
+}
+
+// splitLines splits s into lines, ignoring a single trailing newline.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
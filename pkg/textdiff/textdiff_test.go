@@ -0,0 +1,62 @@
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Line
+	}{
+		{
+			name: "identical",
+			a:    "one\ntwo\n",
+			b:    "one\ntwo\n",
+			want: []Line{{OpEqual, "one"}, {OpEqual, "two"}},
+		},
+		{
+			name: "insert",
+			a:    "one\nthree\n",
+			b:    "one\ntwo\nthree\n",
+			want: []Line{{OpEqual, "one"}, {OpInsert, "two"}, {OpEqual, "three"}},
+		},
+		{
+			name: "delete",
+			a:    "one\ntwo\nthree\n",
+			b:    "one\nthree\n",
+			want: []Line{{OpEqual, "one"}, {OpDelete, "two"}, {OpEqual, "three"}},
+		},
+		{
+			name: "replace",
+			a:    "one\ntwo\n",
+			b:    "one\n2\n",
+			want: []Line{{OpEqual, "one"}, {OpDelete, "two"}, {OpInsert, "2"}},
+		},
+		{
+			name: "empty to content",
+			a:    "",
+			b:    "one\n",
+			want: []Line{{OpInsert, "one"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lines(tt.a, tt.b))
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n"
+	b := "1\n2\n3\n4\nfive\n6\n7\n8\n"
+
+	got := Unified(a, b, 1)
+	assert.Equal(t, "@@\n  4\n- 5\n+ five\n  6\n@@\n", got)
+
+	assert.Empty(t, Unified(a, a, 3))
+}
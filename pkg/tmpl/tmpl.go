@@ -8,9 +8,9 @@ import (
 	"text/template"
 )
 
-// shellQuote returns a shell-safe quoted string. It wraps the string in single
+// ShellQuote returns a shell-safe quoted string. It wraps the string in single
 // quotes and escapes any existing single quotes using the '\” technique.
-func shellQuote(s string) string {
+func ShellQuote(s string) string {
 	if s == "" {
 		return "''"
 	}
@@ -20,7 +20,7 @@ func shellQuote(s string) string {
 }
 
 var funcs = template.FuncMap{
-	"shq": shellQuote,
+	"shq": ShellQuote,
 }
 
 // Render executes a Go template string with the given data.
@@ -29,9 +29,9 @@ var funcs = template.FuncMap{
 // Available template functions:
 //   - shq: Shell-quote a string for safe use in shell commands
 func Render(tmpl string, data any) (string, error) {
-	t, err := template.New("").Funcs(funcs).Option("missingkey=error").Parse(tmpl)
+	t, err := Parse(tmpl)
 	if err != nil {
-		return "", fmt.Errorf("parse template: %w", err)
+		return "", err
 	}
 
 	var buf bytes.Buffer
@@ -41,3 +41,13 @@ func Render(tmpl string, data any) (string, error) {
 
 	return buf.String(), nil
 }
+
+// Parse parses a template string with the same functions and options as
+// Render, without executing it. Useful for static inspection of templates.
+func Parse(tmpl string) (*template.Template, error) {
+	t, err := template.New("").Funcs(funcs).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("parse template: %w", err)
+	}
+	return t, nil
+}